@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/models/migrations"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
+	terraform_model "code.gitea.io/gitea/models/terraform"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
@@ -23,6 +24,7 @@ import (
 	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/urfave/cli/v2"
+	"xorm.io/builder"
 )
 
 // CmdMigrateStorage represents the available migrate storage sub-command.
@@ -36,7 +38,7 @@ var CmdMigrateStorage = &cli.Command{
 			Name:    "type",
 			Aliases: []string{"t"},
 			Value:   "",
-			Usage:   "Type of stored files to copy.  Allowed types: 'attachments', 'lfs', 'avatars', 'repo-avatars', 'repo-archivers', 'packages', 'actions-log', 'actions-artifacts",
+			Usage:   "Type of stored files to copy.  Allowed types: 'attachments', 'lfs', 'avatars', 'repo-avatars', 'repo-archivers', 'packages', 'actions-log', 'actions-artifacts', 'actions-cache', 'terraform-state'",
 		},
 		&cli.StringFlag{
 			Name:    "storage",
@@ -194,6 +196,27 @@ func migrateActionsLog(ctx context.Context, dstStorage storage.ObjectStorage) er
 	})
 }
 
+func migrateActionsCache(ctx context.Context, dstStorage storage.ObjectStorage) error {
+	return db.Iterate(ctx, builder.Eq{"complete": true}, func(ctx context.Context, c *actions_model.ActionCache) error {
+		_, err := storage.Copy(dstStorage, c.StoragePath(), storage.ActionsCache, c.StoragePath())
+		if err != nil {
+			// ignore files that do not exist
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func migrateTerraformState(ctx context.Context, dstStorage storage.ObjectStorage) error {
+	return db.Iterate(ctx, nil, func(ctx context.Context, v *terraform_model.TerraformStateVersion) error {
+		_, err := storage.Copy(dstStorage, v.StoragePath(), storage.TerraformState, v.StoragePath())
+		return err
+	})
+}
+
 func migrateActionsArtifacts(ctx context.Context, dstStorage storage.ObjectStorage) error {
 	return db.Iterate(ctx, nil, func(ctx context.Context, artifact *actions_model.ActionArtifact) error {
 		if artifact.Status == int64(actions_model.ArtifactStatusExpired) {
@@ -297,6 +320,8 @@ func runMigrateStorage(ctx *cli.Context) error {
 		"packages":          migratePackages,
 		"actions-log":       migrateActionsLog,
 		"actions-artifacts": migrateActionsArtifacts,
+		"actions-cache":     migrateActionsCache,
+		"terraform-state":   migrateTerraformState,
 	}
 
 	tp := strings.ToLower(ctx.String("type"))
@@ -99,6 +99,18 @@ func getArtifactByNameAndPath(ctx context.Context, runID int64, name, fpath stri
 	return &art, nil
 }
 
+// GetArtifactByID returns an artifact by id
+func GetArtifactByID(ctx context.Context, id int64) (*ActionArtifact, error) {
+	var art ActionArtifact
+	has, err := db.GetEngine(ctx).ID(id).Get(&art)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("artifact with id %d", id)
+	}
+	return &art, nil
+}
+
 // UpdateArtifactByID updates an artifact by id
 func UpdateArtifactByID(ctx context.Context, id int64, art *ActionArtifact) error {
 	art.ID = id
@@ -149,6 +161,33 @@ func ListUploadedArtifactsMeta(ctx context.Context, runID int64) ([]*ActionArtif
 		Find(&arts)
 }
 
+// FindUploadedArtifacts returns the uploaded or expired artifacts matching the options.
+// Artifacts uploaded with the v1-v3 backend consist of multiple files, so they are grouped by run and name,
+// the returned ID is the smallest ID of the group and the FileSize is the total size of the group.
+func FindUploadedArtifacts(ctx context.Context, opts FindArtifactsOptions) ([]*ActionArtifact, int64, error) {
+	cond := opts.ToConds().And(builder.In("status", ArtifactStatusUploadConfirmed, ArtifactStatusExpired))
+
+	groups := builder.Select("run_id, artifact_name").From("action_artifact").Where(cond).GroupBy("run_id, artifact_name")
+	var total int64
+	if _, err := db.GetEngine(ctx).SQL(builder.Select("count(*)").From(groups, "artifact_group")).Get(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sess := db.GetEngine(ctx).Table("action_artifact").
+		Where(cond).
+		GroupBy("run_id, artifact_name").
+		Select("min(id) as id, run_id, artifact_name, max(repo_id) as repo_id, max(owner_id) as owner_id, max(commit_sha) as commit_sha, " +
+			"sum(file_size) as file_size, max(status) as status, min(created_unix) as created_unix, " +
+			"max(updated_unix) as updated_unix, max(expired_unix) as expired_unix").
+		OrderBy("id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+
+	arts := make([]*ActionArtifact, 0, 10)
+	return arts, total, sess.Find(&arts)
+}
+
 // ListNeedExpiredArtifacts returns all need expired artifacts but not deleted
 func ListNeedExpiredArtifacts(ctx context.Context) ([]*ActionArtifact, error) {
 	arts := make([]*ActionArtifact, 0, 10)
@@ -0,0 +1,158 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(ActionCache))
+}
+
+// ActionCache is an entry saved by `actions/cache`, it can only be restored by the runs of the same ref (scope)
+// and the runs of other refs which are allowed to read the scope, like the default branch.
+type ActionCache struct {
+	ID           int64  `xorm:"pk autoincr"`
+	RepoID       int64  `xorm:"INDEX(repo_scope)"`
+	Scope        string `xorm:"VARCHAR(255) INDEX(repo_scope)"` // The git ref of the run which saved the cache
+	CacheKey     string `xorm:"VARCHAR(512)"`
+	CacheVersion string `xorm:"VARCHAR(255)"` // The hash of the paths and compression method of the cache
+	Size         int64
+	UploadedSize int64              `xorm:"NOT NULL DEFAULT 0"` // The size of the content uploaded so far, limited while uploading
+	Complete     bool               `xorm:"INDEX"`              // Whether the upload is finished
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UsedUnix     timeutil.TimeStamp `xorm:"INDEX"` // The last time the cache was saved or restored
+}
+
+// StoragePath returns the path of the cache in the storage
+func (c *ActionCache) StoragePath() string {
+	return fmt.Sprintf("%d/%d", c.RepoID, c.ID)
+}
+
+// ChunksPath returns the directory of the uploading chunks of the cache in the storage
+func (c *ActionCache) ChunksPath() string {
+	return fmt.Sprintf("tmp/%d", c.ID)
+}
+
+// GetCacheByID returns a cache by id
+func GetCacheByID(ctx context.Context, id int64) (*ActionCache, error) {
+	var c ActionCache
+	has, err := db.GetEngine(ctx).ID(id).Get(&c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("cache with id %d", id)
+	}
+	return &c, nil
+}
+
+// GetCache returns the cache with the exact key and version in the scope, the complete cache is preferred
+func GetCache(ctx context.Context, repoID int64, scope, key, version string) (*ActionCache, error) {
+	var c ActionCache
+	has, err := db.GetEngine(ctx).
+		Where("repo_id = ? AND scope = ? AND cache_key = ? AND cache_version = ?", repoID, scope, key, version).
+		OrderBy("complete DESC, id DESC").
+		Get(&c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("cache %s in scope %s", key, scope)
+	}
+	return &c, nil
+}
+
+// FindCacheByPrefix returns the most recently created complete cache whose key starts with the prefix in the scope
+func FindCacheByPrefix(ctx context.Context, repoID int64, scope, prefix, version string) (*ActionCache, error) {
+	// "%", "_" and "\" are wildcards or escape characters of LIKE in some databases,
+	// so only the part before them is matched in the database and the result is filtered again.
+	likePrefix := prefix
+	if i := strings.IndexAny(likePrefix, `%_\`); i >= 0 {
+		likePrefix = likePrefix[:i]
+	}
+
+	caches := make([]*ActionCache, 0, 10)
+	if err := db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": repoID, "scope": scope, "cache_version": version, "complete": true}).
+		And(builder.Like{"cache_key", likePrefix + "%"}).
+		OrderBy("created_unix DESC, id DESC").
+		Find(&caches); err != nil {
+		return nil, err
+	}
+	for _, c := range caches {
+		if strings.HasPrefix(c.CacheKey, prefix) {
+			return c, nil
+		}
+	}
+	return nil, util.NewNotExistErrorf("cache with prefix %s in scope %s", prefix, scope)
+}
+
+// UpdateCache updates the given columns of a cache
+func UpdateCache(ctx context.Context, c *ActionCache, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols(cols...).Update(c)
+	return err
+}
+
+// IncreaseCacheUploadedSize adds the size to the uploaded size of an uploading cache, a negative size gives it back.
+// It returns false without changes if the uploaded size would exceed the max size, a negative max size means no limit.
+func IncreaseCacheUploadedSize(ctx context.Context, id, size, maxSize int64) (bool, error) {
+	cond := builder.Eq{"id": id, "complete": false}
+	sess := db.GetEngine(ctx).Where(cond)
+	if maxSize >= 0 {
+		sess = sess.And("uploaded_size + ? <= ?", size, maxSize)
+	}
+	n, err := sess.Incr("uploaded_size", size).NoAutoTime().Update(new(ActionCache))
+	return n > 0, err
+}
+
+// DeleteCacheByID deletes the record of a cache, the file in the storage should be deleted by the caller
+func DeleteCacheByID(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&ActionCache{})
+	return err
+}
+
+// GetCachesTotalSize returns the total size of the complete caches of a repository
+func GetCachesTotalSize(ctx context.Context, repoID int64) (int64, error) {
+	return db.GetEngine(ctx).Where("repo_id = ? AND complete = ?", repoID, true).SumInt(&ActionCache{}, "size")
+}
+
+// FindCachesOptions is the options to find caches
+type FindCachesOptions struct {
+	db.ListOptions
+	RepoID       int64
+	Complete     optional.Option[bool]
+	UsedBefore   timeutil.TimeStamp
+	CreateBefore timeutil.TimeStamp
+}
+
+func (opts FindCachesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Complete.Has() {
+		cond = cond.And(builder.Eq{"complete": opts.Complete.Value()})
+	}
+	if opts.UsedBefore > 0 {
+		cond = cond.And(builder.Lt{"used_unix": opts.UsedBefore})
+	}
+	if opts.CreateBefore > 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.CreateBefore})
+	}
+	return cond
+}
+
+// ToOrders returns the least recently used caches first, which are the first to be evicted
+func (opts FindCachesOptions) ToOrders() string {
+	return "used_unix ASC, id ASC"
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCache(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	insert := func(scope, key string, size int64, complete bool) *ActionCache {
+		c := &ActionCache{RepoID: 1, Scope: scope, CacheKey: key, CacheVersion: "v1", Size: size, Complete: complete}
+		require.NoError(t, db.Insert(db.DefaultContext, c))
+		return c
+	}
+	insert("refs/heads/main", "linux-go-abc", 10, true)
+	newer := insert("refs/heads/main", "linux-go-def", 20, true)
+	insert("refs/heads/main", "linux-go-ghi", 30, false)
+	wildcard := insert("refs/heads/main", "linux_100%-abc", 40, true)
+	insert("refs/heads/feature", "linux-go-xyz", 50, true)
+
+	c, err := GetCache(db.DefaultContext, 1, "refs/heads/main", "linux-go-ghi", "v1")
+	require.NoError(t, err)
+	assert.False(t, c.Complete)
+
+	c, err = FindCacheByPrefix(db.DefaultContext, 1, "refs/heads/main", "linux-go-", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, newer.ID, c.ID)
+
+	c, err = FindCacheByPrefix(db.DefaultContext, 1, "refs/heads/main", "linux_100%", "v1")
+	require.NoError(t, err)
+	assert.Equal(t, wildcard.ID, c.ID)
+
+	_, err = FindCacheByPrefix(db.DefaultContext, 1, "refs/heads/main", "linux-go-", "v2")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+	_, err = FindCacheByPrefix(db.DefaultContext, 1, "refs/heads/main", "linux-go-x", "v1")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	total, err := GetCachesTotalSize(db.DefaultContext, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 120, total)
+}
+
+func TestIncreaseCacheUploadedSize(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	c := &ActionCache{RepoID: 1, Scope: "refs/heads/main", CacheKey: "linux-go-abc", CacheVersion: "v1"}
+	require.NoError(t, db.Insert(db.DefaultContext, c))
+
+	ok, err := IncreaseCacheUploadedSize(db.DefaultContext, c.ID, 6, 10)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = IncreaseCacheUploadedSize(db.DefaultContext, c.ID, 6, 10)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = IncreaseCacheUploadedSize(db.DefaultContext, c.ID, 4, 10)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = IncreaseCacheUploadedSize(db.DefaultContext, c.ID, -4, -1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	c, err = GetCacheByID(db.DefaultContext, c.ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, c.UploadedSize)
+}
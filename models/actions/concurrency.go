@@ -0,0 +1,140 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"slices"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+)
+
+// Concurrency is the evaluated `concurrency` setting of a workflow or a job
+type Concurrency struct {
+	Group            string
+	CancelInProgress bool
+}
+
+// HasActiveConcurrentRun returns whether there is a waiting or running run of the repository in the concurrency group.
+// Runs which are blocked by the concurrency group themselves have the blocked status, so they are not counted.
+func HasActiveConcurrentRun(ctx context.Context, repoID int64, group string) (bool, error) {
+	return db.Exist[ActionRun](ctx, FindRunOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: group,
+		Status:           []Status{StatusWaiting, StatusRunning},
+	}.ToConds())
+}
+
+// CancelConcurrentRuns cancels the runs of the repository in the concurrency group which have one of the statuses,
+// and returns the jobs which have been cancelled
+func CancelConcurrentRuns(ctx context.Context, repoID int64, group string, statuses []Status) ([]*ActionRunJob, error) {
+	runs, err := db.Find[ActionRun](ctx, FindRunOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: group,
+		Status:           statuses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var cancelledJobs []*ActionRunJob
+	for _, run := range runs {
+		jobs, err := GetRunJobsByRunID(ctx, run.ID)
+		if err != nil {
+			return nil, err
+		}
+		cancelled, err := CancelJobs(ctx, jobs)
+		if err != nil {
+			return nil, err
+		}
+		cancelledJobs = append(cancelledJobs, cancelled...)
+	}
+	return cancelledJobs, nil
+}
+
+// HasActiveConcurrentJob returns whether there is a waiting or running job of the repository in the concurrency group
+func HasActiveConcurrentJob(ctx context.Context, repoID int64, group string) (bool, error) {
+	return db.Exist[ActionRunJob](ctx, FindRunJobOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: group,
+		Statuses:         []Status{StatusWaiting, StatusRunning},
+	}.ToConds())
+}
+
+// CancelConcurrentJobs cancels the jobs of the repository in the concurrency group which are not done yet,
+// except the jobs of the run with excludeRunID, and returns the jobs which have been cancelled
+func CancelConcurrentJobs(ctx context.Context, repoID int64, group string, excludeRunID int64) ([]*ActionRunJob, error) {
+	jobs, err := db.Find[ActionRunJob](ctx, FindRunJobOptions{
+		RepoID:           repoID,
+		ConcurrencyGroup: group,
+		Statuses:         []Status{StatusWaiting, StatusRunning, StatusBlocked},
+	})
+	if err != nil {
+		return nil, err
+	}
+	jobs = slices.DeleteFunc(jobs, func(job *ActionRunJob) bool {
+		return job.RunID == excludeRunID
+	})
+	return CancelJobs(ctx, jobs)
+}
+
+// BlockedByJobConcurrency checks whether a job of the run which is ready to run should be blocked by its concurrency group.
+// usedGroups records the groups taken by the jobs of the same run which have been checked.
+// If the concurrency cancels jobs in progress, the jobs of other runs in the group are cancelled and returned,
+// and the job won't be blocked.
+func BlockedByJobConcurrency(ctx context.Context, repoID, runID int64, concurrency *Concurrency, usedGroups container.Set[string]) (bool, []*ActionRunJob, error) {
+	if usedGroups.Contains(concurrency.Group) {
+		return true, nil, nil
+	}
+
+	var cancelledJobs []*ActionRunJob
+	if concurrency.CancelInProgress {
+		var err error
+		if cancelledJobs, err = CancelConcurrentJobs(ctx, repoID, concurrency.Group, runID); err != nil {
+			return false, nil, err
+		}
+	} else if active, err := HasActiveConcurrentJob(ctx, repoID, concurrency.Group); err != nil {
+		return false, nil, err
+	} else if active {
+		return true, nil, nil
+	}
+
+	usedGroups.Add(concurrency.Group)
+	return false, cancelledJobs, nil
+}
+
+// GetBlockedConcurrentRunIDs returns the ids of the runs which are blocked by the concurrency group or have jobs blocked by it
+func GetBlockedConcurrentRunIDs(ctx context.Context, repoID int64, runGroup string, jobGroups []string) ([]int64, error) {
+	var runIDs []int64
+	if runGroup != "" {
+		runs, err := db.Find[ActionRun](ctx, FindRunOptions{
+			RepoID:           repoID,
+			ConcurrencyGroup: runGroup,
+			Status:           []Status{StatusBlocked},
+		})
+		if err != nil {
+			return nil, err
+		}
+		// the oldest run goes first
+		for i := len(runs) - 1; i >= 0; i-- {
+			runIDs = append(runIDs, runs[i].ID)
+		}
+	}
+
+	for _, group := range jobGroups {
+		jobs, err := db.Find[ActionRunJob](ctx, FindRunJobOptions{
+			RepoID:           repoID,
+			ConcurrencyGroup: group,
+			Statuses:         []Status{StatusBlocked},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			runIDs = append(runIDs, job.RunID)
+		}
+	}
+	return runIDs, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+// ActionEnvironment represents a deployment environment of a repository,
+// the jobs referencing it with the `environment` key are protected by its rules,
+// see https://docs.github.com/en/actions/managing-workflow-runs-and-deployments/managing-deployments/managing-environments-for-deployment
+type ActionEnvironment struct {
+	ID                 int64              `xorm:"pk autoincr"`
+	RepoID             int64              `xorm:"UNIQUE(repo_name) NOT NULL"`
+	Name               string             `xorm:"UNIQUE(repo_name) NOT NULL"`
+	DeploymentBranches []string           `xorm:"JSON TEXT"` // the glob patterns of the branches which can deploy to the environment, or of the full names of other refs, any ref can deploy if it's empty
+	ReviewerIDs        []int64            `xorm:"JSON TEXT"` // the users who must approve the deployments to the environment, one of them is enough
+	CreatedUnix        timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix        timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionEnvironment))
+}
+
+// MatchRef returns whether the ref can deploy to the environment.
+// The patterns match the names of branches, other refs like tags can only deploy
+// if a pattern starting with "refs/" matches their full names, such as "refs/tags/v*".
+func (env *ActionEnvironment) MatchRef(ref git.RefName) bool {
+	if len(env.DeploymentBranches) == 0 {
+		return true
+	}
+	for _, pattern := range env.DeploymentBranches {
+		var name string
+		if strings.HasPrefix(pattern, "refs/") {
+			name = ref.String()
+		} else if ref.IsBranch() {
+			name = ref.BranchName()
+		} else {
+			continue
+		}
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			log.Warn("Invalid deployment branch pattern of environment %d: %s %v", env.ID, pattern, err)
+			g = glob.MustCompile(glob.QuoteMeta(pattern), '/')
+		}
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReviewer returns whether the user is one of the required reviewers of the environment
+func (env *ActionEnvironment) IsReviewer(userID int64) bool {
+	return slices.Contains(env.ReviewerIDs, userID)
+}
+
+// NeedReview returns whether the deployments to the environment must be approved
+func (env *ActionEnvironment) NeedReview() bool {
+	return len(env.ReviewerIDs) > 0
+}
+
+type FindEnvironmentsOptions struct {
+	db.ListOptions
+	RepoID int64
+	IDs    []int64
+	Name   string
+}
+
+func (opts FindEnvironmentsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	if len(opts.IDs) > 0 {
+		cond = cond.And(builder.In("id", opts.IDs))
+	}
+	if opts.Name != "" {
+		cond = cond.And(builder.Eq{"name": opts.Name})
+	}
+	return cond
+}
+
+func (opts FindEnvironmentsOptions) ToOrders() string {
+	return "name ASC"
+}
+
+// GetEnvironmentByName returns the environment of the repository by its name
+func GetEnvironmentByName(ctx context.Context, repoID int64, name string) (*ActionEnvironment, error) {
+	env := &ActionEnvironment{}
+	has, err := db.GetEngine(ctx).Where("repo_id=? AND name=?", repoID, name).Get(env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("environment %q: %w", name, util.ErrNotExist)
+	}
+	return env, nil
+}
+
+// GetEnvironmentByID returns the environment by its id
+func GetEnvironmentByID(ctx context.Context, id int64) (*ActionEnvironment, error) {
+	env := &ActionEnvironment{}
+	has, err := db.GetEngine(ctx).ID(id).Get(env)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, fmt.Errorf("environment with id %d: %w", id, util.ErrNotExist)
+	}
+	return env, nil
+}
+
+// InsertEnvironment inserts an environment
+func InsertEnvironment(ctx context.Context, env *ActionEnvironment) error {
+	env.Name = strings.TrimSpace(env.Name)
+	return db.Insert(ctx, env)
+}
+
+// UpdateEnvironment updates the protection rules of an environment
+func UpdateEnvironment(ctx context.Context, env *ActionEnvironment) error {
+	_, err := db.GetEngine(ctx).ID(env.ID).Cols("deployment_branches", "reviewer_ids").Update(env)
+	return err
+}
+
+// DeleteEnvironment deletes an environment with its variables and deployments, its secrets should be deleted by the caller
+func DeleteEnvironment(ctx context.Context, env *ActionEnvironment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := db.DeleteBeans(ctx, &ActionVariable{EnvironmentID: env.ID}, &ActionDeployment{EnvironmentID: env.ID}); err != nil {
+			return err
+		}
+		_, err := db.DeleteByID[ActionEnvironment](ctx, env.ID)
+		return err
+	})
+}
+
+// DeploymentReviewStatus represents the review status of a deployment to an environment
+type DeploymentReviewStatus int
+
+const (
+	DeploymentReviewNotRequired DeploymentReviewStatus = iota // 0, the environment has no required reviewers
+	DeploymentReviewPending                                   // 1
+	DeploymentReviewApproved                                  // 2
+	DeploymentReviewRejected                                  // 3
+)
+
+var deploymentReviewStatusNames = map[DeploymentReviewStatus]string{
+	DeploymentReviewNotRequired: "not_required",
+	DeploymentReviewPending:     "pending",
+	DeploymentReviewApproved:    "approved",
+	DeploymentReviewRejected:    "rejected",
+}
+
+// String returns the string name of the DeploymentReviewStatus
+func (s DeploymentReviewStatus) String() string {
+	return deploymentReviewStatusNames[s]
+}
+
+// ActionDeployment represents a deployment of a job to an environment,
+// each attempt of the job has its own deployment, so they make up the deployment history of the environment
+type ActionDeployment struct {
+	ID            int64  `xorm:"pk autoincr"`
+	RepoID        int64  `xorm:"index"`
+	EnvironmentID int64  `xorm:"index"`
+	RunID         int64  `xorm:"index"`
+	RunJobID      int64  `xorm:"UNIQUE(job_attempt)"`
+	Attempt       int64  `xorm:"UNIQUE(job_attempt)"`
+	Ref           string `xorm:"index"`
+	CommitSHA     string
+	CreatorID     int64                  // the user who triggered the run
+	ReviewStatus  DeploymentReviewStatus `xorm:"index"`
+	ReviewerID    int64
+	ReviewComment string             `xorm:"TEXT"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+
+	Environment *ActionEnvironment `xorm:"-"`
+	Job         *ActionRunJob      `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionDeployment))
+}
+
+// LoadAttributes loads the environment and the job of the deployment
+func (d *ActionDeployment) LoadAttributes(ctx context.Context) error {
+	if d.Environment == nil {
+		env, err := GetEnvironmentByID(ctx, d.EnvironmentID)
+		if err != nil {
+			return err
+		}
+		d.Environment = env
+	}
+	if d.Job == nil {
+		job, err := GetRunJobByID(ctx, d.RunJobID)
+		if err != nil {
+			return err
+		}
+		d.Job = job
+	}
+	return nil
+}
+
+type FindDeploymentsOptions struct {
+	db.ListOptions
+	RepoID         int64
+	EnvironmentIDs []int64
+	RunID          int64
+	ReviewStatus   DeploymentReviewStatus
+}
+
+func (opts FindDeploymentsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if len(opts.EnvironmentIDs) > 0 {
+		cond = cond.And(builder.In("environment_id", opts.EnvironmentIDs))
+	}
+	if opts.RunID > 0 {
+		cond = cond.And(builder.Eq{"run_id": opts.RunID})
+	}
+	if opts.ReviewStatus > 0 {
+		cond = cond.And(builder.Eq{"review_status": opts.ReviewStatus})
+	}
+	return cond
+}
+
+func (opts FindDeploymentsOptions) ToOrders() string {
+	return "id DESC"
+}
+
+// GetDeploymentOfJob returns the deployment of the upcoming attempt of the job, which has been created when the job was ready to run
+func GetDeploymentOfJob(ctx context.Context, job *ActionRunJob) (*ActionDeployment, bool, error) {
+	d := &ActionDeployment{}
+	has, err := db.GetEngine(ctx).Where("run_job_id=? AND attempt=?", job.ID, job.Attempt+1).Get(d)
+	if err != nil || !has {
+		return nil, false, err
+	}
+	return d, true, nil
+}
+
+// UpdateDeploymentReview updates the review of a pending deployment, it returns false if the deployment isn't pending anymore
+func UpdateDeploymentReview(ctx context.Context, d *ActionDeployment) (bool, error) {
+	n, err := db.GetEngine(ctx).ID(d.ID).Where("review_status=?", DeploymentReviewPending).
+		Cols("review_status", "reviewer_id", "review_comment").Update(d)
+	return n == 1, err
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionEnvironmentMatchRef(t *testing.T) {
+	env := &ActionEnvironment{}
+	assert.True(t, env.MatchRef("refs/heads/feature/foo"))
+	assert.True(t, env.MatchRef("refs/tags/v1.0.0"))
+
+	env.DeploymentBranches = []string{"main", "release/*"}
+	assert.True(t, env.MatchRef("refs/heads/main"))
+	assert.True(t, env.MatchRef("refs/heads/release/1.0"))
+	assert.False(t, env.MatchRef("refs/heads/release/1.0/hotfix"))
+	assert.False(t, env.MatchRef("refs/heads/feature/foo"))
+	// the branch patterns don't match other refs with the same short names
+	assert.False(t, env.MatchRef("refs/tags/main"))
+	assert.False(t, env.MatchRef("refs/tags/release/1.0"))
+	assert.False(t, env.MatchRef("refs/pull/1/head"))
+
+	env.DeploymentBranches = []string{"main", "refs/tags/v*"}
+	assert.True(t, env.MatchRef("refs/tags/v1.0.0"))
+	assert.False(t, env.MatchRef("refs/tags/main"))
+	assert.False(t, env.MatchRef("refs/heads/v1.0.0"))
+}
+
+func TestDeploymentOfJob(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	env := &ActionEnvironment{RepoID: 1, Name: " production ", ReviewerIDs: []int64{2}}
+	require.NoError(t, InsertEnvironment(db.DefaultContext, env))
+	env, err := GetEnvironmentByName(db.DefaultContext, 1, "production")
+	require.NoError(t, err)
+	assert.True(t, env.NeedReview())
+	assert.True(t, env.IsReviewer(2))
+	assert.False(t, env.IsReviewer(1))
+
+	job := &ActionRunJob{ID: 10, RunID: 1, RepoID: 1, Attempt: 1}
+	_, has, err := GetDeploymentOfJob(db.DefaultContext, job)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	d := &ActionDeployment{RepoID: 1, EnvironmentID: env.ID, RunID: 1, RunJobID: job.ID, Attempt: job.Attempt + 1, ReviewStatus: DeploymentReviewPending}
+	require.NoError(t, db.Insert(db.DefaultContext, d))
+	d, has, err = GetDeploymentOfJob(db.DefaultContext, job)
+	require.NoError(t, err)
+	require.True(t, has)
+	assert.Equal(t, DeploymentReviewPending, d.ReviewStatus)
+
+	// only the pending deployments can be reviewed
+	d.ReviewStatus = DeploymentReviewApproved
+	d.ReviewerID = 2
+	updated, err := UpdateDeploymentReview(db.DefaultContext, d)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	d.ReviewStatus = DeploymentReviewRejected
+	updated, err = UpdateDeploymentReview(db.DefaultContext, d)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	require.NoError(t, DeleteEnvironment(db.DefaultContext, env))
+	unittest.AssertNotExistsBean(t, &ActionDeployment{ID: d.ID})
+	unittest.AssertNotExistsBean(t, &ActionEnvironment{ID: env.ID})
+}
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	api "code.gitea.io/gitea/modules/structs"
@@ -46,6 +47,8 @@ type ActionRun struct {
 	TriggerEvent      string                       // the trigger event defined in the `on` configuration of the triggered workflow
 	Status            Status                       `xorm:"index"`
 	Version           int                          `xorm:"version default 0"` // Status could be updated concomitantly, so an optimistic lock is needed
+	ConcurrencyGroup  string                       `xorm:"index"`             // the evaluated workflow-level concurrency group, runs in the same group of a repository don't run at the same time
+	ConcurrencyCancel bool                         // whether to cancel the other runs in the concurrency group when this run is triggered
 	// Started and Stopped is used for recording last run time, if rerun happened, they will be reset to 0
 	Started timeutil.TimeStamp
 	Stopped timeutil.TimeStamp
@@ -193,7 +196,8 @@ func updateRepoRunsNumbers(ctx context.Context, repo *repo_model.Repository) err
 
 // CancelPreviousJobs cancels all previous jobs of the same repository, reference, workflow, and event.
 // It's useful when a new run is triggered, and all previous runs needn't be continued anymore.
-func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID string, event webhook_module.HookEventType) error {
+// It returns the jobs which have been cancelled.
+func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID string, event webhook_module.HookEventType) ([]*ActionRunJob, error) {
 	// Find all runs in the specified repository, reference, and workflow with non-final status
 	runs, total, err := db.FindAndCount[ActionRun](ctx, FindRunOptions{
 		RepoID:       repoID,
@@ -203,14 +207,15 @@ func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID strin
 		Status:       []Status{StatusRunning, StatusWaiting, StatusBlocked},
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// If there are no runs found, there's no need to proceed with cancellation, so return nil.
 	if total == 0 {
-		return nil
+		return nil, nil
 	}
 
+	var cancelledJobs []*ActionRunJob
 	// Iterate over each found run and cancel its associated jobs.
 	for _, run := range runs {
 		// Find all jobs associated with the current run.
@@ -218,95 +223,154 @@ func CancelPreviousJobs(ctx context.Context, repoID int64, ref, workflowID strin
 			RunID: run.ID,
 		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		// Iterate over each job and attempt to cancel it.
-		for _, job := range jobs {
-			// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
-			status := job.Status
-			if status.IsDone() {
-				continue
-			}
+		cancelled, err := CancelJobs(ctx, jobs)
+		if err != nil {
+			return nil, err
+		}
+		cancelledJobs = append(cancelledJobs, cancelled...)
+	}
 
-			// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
-			if job.TaskID == 0 {
-				job.Status = StatusCancelled
-				job.Stopped = timeutil.TimeStampNow()
+	// Return the cancelled jobs to indicate successful cancellation of all running and waiting jobs.
+	return cancelledJobs, nil
+}
 
-				// Update the job's status and stopped time in the database.
-				n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
-				if err != nil {
-					return err
-				}
+// CancelJobs cancels the given jobs which are not done yet, and returns the jobs which have been cancelled.
+// Jobs without a task are marked as cancelled directly, the tasks of other jobs are stopped.
+func CancelJobs(ctx context.Context, jobs []*ActionRunJob) ([]*ActionRunJob, error) {
+	cancelledJobs := make([]*ActionRunJob, 0, len(jobs))
+	for _, job := range jobs {
+		// Skip jobs that are already in a terminal state (completed, cancelled, etc.).
+		status := job.Status
+		if status.IsDone() {
+			continue
+		}
 
-				// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
-				if n == 0 {
-					return fmt.Errorf("job has changed, try again")
-				}
+		// If the job has no associated task (probably an error), set its status to 'Cancelled' and stop it.
+		if job.TaskID == 0 {
+			job.Status = StatusCancelled
+			job.Stopped = timeutil.TimeStampNow()
 
-				// Continue with the next job.
-				continue
+			// Update the job's status and stopped time in the database.
+			n, err := UpdateRunJob(ctx, job, builder.Eq{"task_id": 0}, "status", "stopped")
+			if err != nil {
+				return nil, err
 			}
 
-			// If the job has an associated task, try to stop the task, effectively cancelling the job.
-			if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
-				return err
+			// If the update affected 0 rows, it means the job has changed in the meantime, so we need to try again.
+			if n == 0 {
+				return nil, fmt.Errorf("job has changed, try again")
 			}
+
+			cancelledJobs = append(cancelledJobs, job)
+			// Continue with the next job.
+			continue
 		}
+
+		// If the job has an associated task, try to stop the task, effectively cancelling the job.
+		if err := StopTask(ctx, job.TaskID, StatusCancelled); err != nil {
+			return nil, err
+		}
+		// StopTask has updated the job in the database, reload it to get the latest status.
+		updatedJob, err := GetRunJobByID(ctx, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		cancelledJobs = append(cancelledJobs, updatedJob)
 	}
+	return cancelledJobs, nil
+}
 
-	// Return nil to indicate successful cancellation of all running and waiting jobs.
-	return nil
+// RunJobOptions holds the settings of a job which are evaluated from the workflow file before inserting
+type RunJobOptions struct {
+	Concurrency *Concurrency
+	// ParentIndex is the index of the job calling the reusable workflow which the job is expanded from, or -1 for the top-level jobs,
+	// the calling jobs go before the jobs expanded from them.
+	ParentIndex int
+	Environment string // the name of the deployment environment which the job references
 }
 
-// InsertRun inserts a run
-func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow) error {
+// InsertRun inserts a run and its jobs.
+// jobOpts holds the evaluated settings of each job, it's either nil or has the same length as jobs.
+// The jobs are blocked if the run is blocked by its concurrency group, or if their concurrency group is in use.
+// The jobs calling reusable workflows, the jobs expanded from them and the jobs referencing environments are always blocked,
+// they are started by the job emitter.
+// It returns the jobs of other runs which have been cancelled by the concurrency of the jobs.
+func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWorkflow, jobOpts []*RunJobOptions) ([]*ActionRunJob, error) {
 	ctx, committer, err := db.TxContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer committer.Close()
 
 	index, err := db.GetNextResourceIndex(ctx, "action_run_index", run.RepoID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	run.Index = index
 
 	if err := db.Insert(ctx, run); err != nil {
-		return err
+		return nil, err
 	}
 
 	if run.Repo == nil {
 		repo, err := repo_model.GetRepositoryByID(ctx, run.RepoID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		run.Repo = repo
 	}
 
 	if err := updateRepoRunsNumbers(ctx, run.Repo); err != nil {
-		return err
+		return nil, err
+	}
+
+	callers := make(container.Set[int], len(jobOpts))
+	for _, opts := range jobOpts {
+		if opts != nil && opts.ParentIndex >= 0 {
+			callers.Add(opts.ParentIndex)
+		}
 	}
 
 	runJobs := make([]*ActionRunJob, 0, len(jobs))
 	var hasWaiting bool
-	for _, v := range jobs {
+	usedGroups := make(container.Set[string])
+	var cancelledJobs []*ActionRunJob
+	for i, v := range jobs {
+		opts := &RunJobOptions{ParentIndex: -1}
+		if jobOpts != nil && jobOpts[i] != nil {
+			opts = jobOpts[i]
+		}
 		id, job := v.Job()
 		needs := job.Needs()
 		if err := v.SetJob(id, job.EraseNeeds()); err != nil {
-			return err
+			return nil, err
 		}
 		payload, _ := v.Marshal()
+		concurrency := &Concurrency{}
+		if opts.Concurrency != nil {
+			concurrency = opts.Concurrency
+		}
 		status := StatusWaiting
-		if len(needs) > 0 || run.NeedApproval {
+		if len(needs) > 0 || run.NeedApproval || run.Status == StatusBlocked || opts.ParentIndex >= 0 || callers.Contains(i) || opts.Environment != "" {
 			status = StatusBlocked
-		} else {
+		} else if concurrency.Group != "" {
+			blocked, cancelled, err := BlockedByJobConcurrency(ctx, run.RepoID, run.ID, concurrency, usedGroups)
+			if err != nil {
+				return nil, err
+			}
+			cancelledJobs = append(cancelledJobs, cancelled...)
+			if blocked {
+				status = StatusBlocked
+			}
+		}
+		if status == StatusWaiting {
 			hasWaiting = true
 		}
 		job.Name, _ = util.SplitStringAtByteN(job.Name, 255)
-		runJobs = append(runJobs, &ActionRunJob{
+		runJob := &ActionRunJob{
 			RunID:             run.ID,
 			RepoID:            run.RepoID,
 			OwnerID:           run.OwnerID,
@@ -318,20 +382,31 @@ func InsertRun(ctx context.Context, run *ActionRun, jobs []*jobparser.SingleWork
 			Needs:             needs,
 			RunsOn:            job.RunsOn(),
 			Status:            status,
-		})
-	}
-	if err := db.Insert(ctx, runJobs); err != nil {
-		return err
+			ConcurrencyGroup:  concurrency.Group,
+			ConcurrencyCancel: concurrency.CancelInProgress,
+			Environment:       opts.Environment,
+		}
+		if opts.ParentIndex >= 0 {
+			runJob.ParentJobID = runJobs[opts.ParentIndex].ID
+		}
+		// insert the jobs one by one since the ids of the calling jobs are required by the jobs expanded from them
+		if err := db.Insert(ctx, runJob); err != nil {
+			return nil, err
+		}
+		runJobs = append(runJobs, runJob)
 	}
 
 	// if there is a job in the waiting status, increase tasks version.
 	if hasWaiting {
 		if err := IncreaseTaskVersion(ctx, run.OwnerID, run.RepoID); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return committer.Commit()
+	if err := committer.Commit(); err != nil {
+		return nil, err
+	}
+	return cancelledJobs, nil
 }
 
 func GetRunByID(ctx context.Context, id int64) (*ActionRun, error) {
@@ -391,6 +466,11 @@ func GetWorkflowLatestRun(ctx context.Context, repoID int64, workflowFile, branc
 	return &run, nil
 }
 
+// CountRunsByStatus returns the number of runs of every status
+func CountRunsByStatus(ctx context.Context) (map[Status]int64, error) {
+	return countByStatus(ctx, "action_run")
+}
+
 // UpdateRun updates a run.
 // It requires the inputted run has Version set.
 // It will return error if the version is not matched (it means the run has been changed after loaded).
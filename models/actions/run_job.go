@@ -33,6 +33,10 @@ type ActionRunJob struct {
 	RunsOn            []string `xorm:"JSON TEXT"`
 	TaskID            int64    // the latest task of the job
 	Status            Status   `xorm:"index"`
+	ConcurrencyGroup  string   `xorm:"index"` // the evaluated job-level concurrency group, jobs in the same group of a repository don't run at the same time
+	ConcurrencyCancel bool     // whether to cancel the other jobs in the concurrency group when this job is ready to run
+	ParentJobID       int64    `xorm:"index"`        // the id of the job calling the reusable workflow which this job is expanded from
+	Environment       string   `xorm:"VARCHAR(255)"` // the evaluated name of the deployment environment which the job references
 	Started           timeutil.TimeStamp
 	Stopped           timeutil.TimeStamp
 	Created           timeutil.TimeStamp `xorm:"created"`
@@ -152,6 +156,11 @@ func UpdateRunJob(ctx context.Context, job *ActionRunJob, cond builder.Cond, col
 	return affected, nil
 }
 
+// CountRunJobsByStatus returns the number of jobs of every status
+func CountRunJobsByStatus(ctx context.Context) (map[Status]int64, error) {
+	return countByStatus(ctx, "action_run_job")
+}
+
 func aggregateJobStatus(jobs []*ActionRunJob) Status {
 	allDone := true
 	allWaiting := true
@@ -21,6 +21,27 @@ func (jobs ActionJobList) GetRunIDs() []int64 {
 	})
 }
 
+// GetCallerIDs returns the ids of the jobs calling reusable workflows, which are the parents of other jobs
+func (jobs ActionJobList) GetCallerIDs() container.Set[int64] {
+	ids := make(container.Set[int64])
+	for _, job := range jobs {
+		if job.ParentJobID != 0 {
+			ids.Add(job.ParentJobID)
+		}
+	}
+	return ids
+}
+
+// HasEnvironments returns whether some of the jobs reference deployment environments
+func (jobs ActionJobList) HasEnvironments() bool {
+	for _, job := range jobs {
+		if job.Environment != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (jobs ActionJobList) LoadRuns(ctx context.Context, withRepo bool) error {
 	runIDs := jobs.GetRunIDs()
 	runs := make(map[int64]*ActionRun, len(runIDs))
@@ -48,12 +69,13 @@ func (jobs ActionJobList) LoadAttributes(ctx context.Context, withRepo bool) err
 
 type FindRunJobOptions struct {
 	db.ListOptions
-	RunID         int64
-	RepoID        int64
-	OwnerID       int64
-	CommitSHA     string
-	Statuses      []Status
-	UpdatedBefore timeutil.TimeStamp
+	RunID            int64
+	RepoID           int64
+	OwnerID          int64
+	CommitSHA        string
+	Statuses         []Status
+	UpdatedBefore    timeutil.TimeStamp
+	ConcurrencyGroup string
 }
 
 func (opts FindRunJobOptions) ToConds() builder.Cond {
@@ -76,5 +98,8 @@ func (opts FindRunJobOptions) ToConds() builder.Cond {
 	if opts.UpdatedBefore > 0 {
 		cond = cond.And(builder.Lt{"updated": opts.UpdatedBefore})
 	}
+	if opts.ConcurrencyGroup != "" {
+		cond = cond.And(builder.Eq{"concurrency_group": opts.ConcurrencyGroup})
+	}
 	return cond
 }
@@ -125,7 +125,7 @@ func CleanRepoScheduleTasks(ctx context.Context, repo *repo_model.Repository) er
 		return fmt.Errorf("DeleteCronTaskByRepo: %v", err)
 	}
 	// cancel running cron jobs of this repository and delete old schedules
-	if err := CancelPreviousJobs(
+	if _, err := CancelPreviousJobs(
 		ctx,
 		repo.ID,
 		repo.DefaultBranch,
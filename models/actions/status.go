@@ -4,6 +4,9 @@
 package actions
 
 import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/translation"
 
 	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
@@ -102,3 +105,19 @@ func (s Status) AsResult() runnerv1.Result {
 	}
 	return runnerv1.Result_RESULT_UNSPECIFIED
 }
+
+// countByStatus returns the number of the records of every status in the table
+func countByStatus(ctx context.Context, table string) (map[Status]int64, error) {
+	var rows []struct {
+		Status Status
+		Count  int64
+	}
+	if err := db.GetEngine(ctx).Table(table).Select("status, COUNT(*) AS count").GroupBy("status").Find(&rows); err != nil {
+		return nil, err
+	}
+	counts := make(map[Status]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
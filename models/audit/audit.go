@@ -0,0 +1,188 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// Action represents the kind of a security relevant action recorded by the audit log
+type Action string
+
+// The actions recorded by the audit log
+const (
+	ActionUserImpersonate Action = "user.impersonate"
+
+	ActionAccessTokenCreate Action = "access_token.create"
+	ActionAccessTokenDelete Action = "access_token.delete"
+
+	ActionOrgMemberRemove Action = "org.member_remove"
+
+	ActionTeamCreate       Action = "team.create"
+	ActionTeamUpdate       Action = "team.update"
+	ActionTeamDelete       Action = "team.delete"
+	ActionTeamMemberAdd    Action = "team.member_add"
+	ActionTeamMemberRemove Action = "team.member_remove"
+
+	ActionRepoCollaboratorAdd    Action = "repo.collaborator_add"
+	ActionRepoCollaboratorUpdate Action = "repo.collaborator_update"
+	ActionRepoCollaboratorRemove Action = "repo.collaborator_remove"
+	ActionRepoTransferStart      Action = "repo.transfer_start"
+	ActionRepoTransfer           Action = "repo.transfer"
+	ActionRepoDelete             Action = "repo.delete"
+
+	ActionProtectedBranchCreate Action = "protected_branch.create"
+	ActionProtectedBranchUpdate Action = "protected_branch.update"
+	ActionProtectedBranchDelete Action = "protected_branch.delete"
+
+	ActionRulesetCreate Action = "ruleset.create"
+	ActionRulesetUpdate Action = "ruleset.update"
+	ActionRulesetDelete Action = "ruleset.delete"
+
+	ActionPushRuleUpdate Action = "push_rule.update"
+	ActionPushRuleDelete Action = "push_rule.delete"
+
+	ActionSecretScanningBypass       Action = "secret_scanning.bypass"
+	ActionSecretScanningAlertResolve Action = "secret_scanning.alert_resolve"
+	ActionSecretScanningAlertReopen  Action = "secret_scanning.alert_reopen"
+
+	ActionSecretCreate Action = "secret.create"
+	ActionSecretUpdate Action = "secret.update"
+	ActionSecretDelete Action = "secret.delete"
+)
+
+// AllActions returns all the actions recorded by the audit log
+func AllActions() []Action {
+	return []Action{
+		ActionUserImpersonate,
+		ActionAccessTokenCreate,
+		ActionAccessTokenDelete,
+		ActionOrgMemberRemove,
+		ActionTeamCreate,
+		ActionTeamUpdate,
+		ActionTeamDelete,
+		ActionTeamMemberAdd,
+		ActionTeamMemberRemove,
+		ActionRepoCollaboratorAdd,
+		ActionRepoCollaboratorUpdate,
+		ActionRepoCollaboratorRemove,
+		ActionRepoTransferStart,
+		ActionRepoTransfer,
+		ActionRepoDelete,
+		ActionProtectedBranchCreate,
+		ActionProtectedBranchUpdate,
+		ActionProtectedBranchDelete,
+		ActionRulesetCreate,
+		ActionRulesetUpdate,
+		ActionRulesetDelete,
+		ActionPushRuleUpdate,
+		ActionPushRuleDelete,
+		ActionSecretScanningBypass,
+		ActionSecretScanningAlertResolve,
+		ActionSecretScanningAlertReopen,
+		ActionSecretCreate,
+		ActionSecretUpdate,
+		ActionSecretDelete,
+	}
+}
+
+// TargetType represents the type of the object an audit event is about
+type TargetType string
+
+// The types of the objects audit events are about
+const (
+	TargetTypeUser            TargetType = "user"
+	TargetTypeAccessToken     TargetType = "access_token"
+	TargetTypeTeam            TargetType = "team"
+	TargetTypeRepository      TargetType = "repository"
+	TargetTypeProtectedBranch TargetType = "protected_branch"
+	TargetTypeRuleset         TargetType = "ruleset"
+	TargetTypePushRule        TargetType = "push_rule"
+	TargetTypeSecretScanning  TargetType = "secret_scanning_alert"
+	TargetTypeSecret          TargetType = "secret"
+)
+
+// Event represents a security relevant action recorded by the audit log
+type Event struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Action      Action             `xorm:"VARCHAR(64) INDEX NOT NULL"`
+	DoerID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	DoerName    string             `xorm:"VARCHAR(255)"`             // kept because the doer could be deleted later
+	OwnerID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"` // the user or organization the event belongs to, 0 for instance level events
+	RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	TargetType  TargetType         `xorm:"VARCHAR(32)"`
+	TargetID    int64              `xorm:"NOT NULL DEFAULT 0"`
+	TargetName  string             `xorm:"VARCHAR(255)"`
+	Description string             `xorm:"TEXT"`
+	IPAddress   string             `xorm:"VARCHAR(64)"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// TableName represents the real table name of Event
+func (Event) TableName() string {
+	return "audit_event"
+}
+
+func init() {
+	db.RegisterModel(new(Event))
+}
+
+// InsertEvent inserts an audit event
+func InsertEvent(ctx context.Context, e *Event) error {
+	return db.Insert(ctx, e)
+}
+
+// FindEventsOptions represents the options to search audit events
+type FindEventsOptions struct {
+	db.ListOptions
+	OwnerID  int64
+	RepoID   int64
+	DoerID   int64
+	DoerName string // matched case-insensitively, the events of deleted users can still be found by the name
+	Action   Action
+	Since    timeutil.TimeStamp
+	Before   timeutil.TimeStamp
+}
+
+func (opts FindEventsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.DoerID > 0 {
+		cond = cond.And(builder.Eq{"doer_id": opts.DoerID})
+	}
+	if opts.DoerName != "" {
+		cond = cond.And(builder.Expr("LOWER(doer_name) = ?", strings.ToLower(opts.DoerName)))
+	}
+	if opts.Action != "" {
+		cond = cond.And(builder.Eq{"action": opts.Action})
+	}
+	if opts.Since > 0 {
+		cond = cond.And(builder.Gte{"created_unix": opts.Since})
+	}
+	if opts.Before > 0 {
+		cond = cond.And(builder.Lt{"created_unix": opts.Before})
+	}
+	return cond
+}
+
+func (opts FindEventsOptions) ToOrders() string {
+	return "`id` DESC"
+}
+
+// DeleteEventsOlderThan deletes the audit events which were recorded before the given time
+func DeleteEventsOlderThan(ctx context.Context, olderThan time.Time) (int64, error) {
+	return db.GetEngine(ctx).Where("created_unix < ?", olderThan.Unix()).Delete(new(Event))
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindEvents(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	events := []*audit_model.Event{
+		{Action: audit_model.ActionTeamCreate, DoerID: 2, DoerName: "user2", OwnerID: 3, TargetType: audit_model.TargetTypeTeam, TargetID: 1, TargetName: "Owners"},
+		{Action: audit_model.ActionTeamMemberAdd, DoerID: 2, DoerName: "user2", OwnerID: 3, TargetType: audit_model.TargetTypeUser, TargetID: 4, TargetName: "user4"},
+		{Action: audit_model.ActionRepoDelete, DoerID: 1, DoerName: "user1", OwnerID: 3, RepoID: 3, TargetType: audit_model.TargetTypeRepository, TargetID: 3, TargetName: "org3/repo3"},
+		{Action: audit_model.ActionUserImpersonate, DoerID: 1, DoerName: "user1", TargetType: audit_model.TargetTypeUser, TargetID: 2, TargetName: "user2"},
+	}
+	for _, e := range events {
+		assert.NoError(t, audit_model.InsertEvent(db.DefaultContext, e))
+	}
+
+	found, count, err := db.FindAndCount[audit_model.Event](db.DefaultContext, &audit_model.FindEventsOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+	if assert.Len(t, found, 4) {
+		// newest first
+		assert.Equal(t, events[3].ID, found[0].ID)
+	}
+
+	found, err = db.Find[audit_model.Event](db.DefaultContext, &audit_model.FindEventsOptions{OwnerID: 3})
+	assert.NoError(t, err)
+	assert.Len(t, found, 3)
+
+	found, err = db.Find[audit_model.Event](db.DefaultContext, &audit_model.FindEventsOptions{OwnerID: 3, DoerName: "USER2"})
+	assert.NoError(t, err)
+	assert.Len(t, found, 2)
+
+	found, err = db.Find[audit_model.Event](db.DefaultContext, &audit_model.FindEventsOptions{Action: audit_model.ActionRepoDelete})
+	assert.NoError(t, err)
+	if assert.Len(t, found, 1) {
+		assert.EqualValues(t, 3, found[0].RepoID)
+	}
+
+	found, err = db.Find[audit_model.Event](db.DefaultContext, &audit_model.FindEventsOptions{RepoID: 3, DoerID: 2})
+	assert.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestDeleteEventsOlderThan(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	old := &audit_model.Event{Action: audit_model.ActionSecretCreate, DoerID: 2, DoerName: "user2", OwnerID: 2}
+	recent := &audit_model.Event{Action: audit_model.ActionSecretDelete, DoerID: 2, DoerName: "user2", OwnerID: 2}
+	assert.NoError(t, audit_model.InsertEvent(db.DefaultContext, old))
+	assert.NoError(t, audit_model.InsertEvent(db.DefaultContext, recent))
+
+	_, err := db.GetEngine(db.DefaultContext).Exec("UPDATE audit_event SET created_unix = ? WHERE id = ?", time.Now().AddDate(0, 0, -10).Unix(), old.ID)
+	assert.NoError(t, err)
+
+	deleted, err := audit_model.DeleteEventsOlderThan(db.DefaultContext, time.Now().AddDate(0, 0, -5))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+
+	unittest.AssertNotExistsBean(t, &audit_model.Event{ID: old.ID})
+	unittest.AssertExistsAndLoadBean(t, &audit_model.Event{ID: recent.ID})
+}
@@ -57,7 +57,10 @@ type ProtectedBranch struct {
 	RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
 	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireCodeOwnerApproval      bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireLinearHistory          bool     `xorm:"NOT NULL DEFAULT false"`
+	EnableMergeQueue              bool     `xorm:"NOT NULL DEFAULT false"`
 	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
 	IgnoreStaleApprovals          bool     `xorm:"NOT NULL DEFAULT false"`
 	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
@@ -261,6 +264,14 @@ func (protectBranch *ProtectedBranch) MergeBlockedByProtectedFiles(changedProtec
 	return len(changedProtectedFiles) > 0
 }
 
+// IsMergeStyleAllowed returns false if the merge style would create a merge commit on a branch requiring a linear history
+func (protectBranch *ProtectedBranch) IsMergeStyleAllowed(mergeStyle repo_model.MergeStyle) bool {
+	if !protectBranch.RequireLinearHistory {
+		return true
+	}
+	return mergeStyle != repo_model.MergeStyleMerge && mergeStyle != repo_model.MergeStyleRebaseMerge
+}
+
 // IsProtectedFile return if path is protected
 func (protectBranch *ProtectedBranch) IsProtectedFile(patterns []glob.Glob, path string) bool {
 	if len(patterns) == 0 {
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"testing"
 
+	repo_model "code.gitea.io/gitea/models/repo"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -76,3 +78,16 @@ func TestBranchRuleMatch(t *testing.T) {
 		)
 	}
 }
+
+func TestProtectedBranchIsMergeStyleAllowed(t *testing.T) {
+	pb := &ProtectedBranch{}
+	assert.True(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleMerge))
+
+	pb.RequireLinearHistory = true
+	assert.False(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleMerge))
+	assert.False(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleRebaseMerge))
+	assert.True(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleRebase))
+	assert.True(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleSquash))
+	assert.True(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleFastForwardOnly))
+	assert.True(t, pb.IsMergeStyleAllowed(repo_model.MergeStyleManuallyMerged))
+}
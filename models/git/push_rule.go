@@ -0,0 +1,117 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"regexp"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+var ErrPushRuleNotExist = util.NewNotExistErrorf("push rule does not exist")
+
+// PushRule restricts the content of the commits pushed to a repository.
+// A push rule belongs either to an organization and applies to all its repositories, or to a single repository.
+// The push rules of a repository and of its owner are both checked.
+type PushRule struct {
+	ID      int64 `xorm:"pk autoincr"`
+	OwnerID int64 `xorm:"UNIQUE(owner_repo) NOT NULL DEFAULT 0"` // set for the push rule of an organization
+	RepoID  int64 `xorm:"UNIQUE(owner_repo) NOT NULL DEFAULT 0"` // set for the push rule of a repository
+
+	CommitMessagePattern          string `xorm:"TEXT"` // regular expression the commit messages have to match
+	RequireVerifiedAuthorEmail    bool   `xorm:"NOT NULL DEFAULT false"`
+	RequireVerifiedCommitterEmail bool   `xorm:"NOT NULL DEFAULT false"`
+	MaxFileSize                   int64  `xorm:"NOT NULL DEFAULT 0"` // in bytes, 0 for no limit
+	ForbiddenFilePatterns         string `xorm:"TEXT"`               // semicolon separated glob patterns of paths
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(PushRule))
+}
+
+// IsOrgRule returns true if the push rule applies to all repositories of an organization
+func (r *PushRule) IsOrgRule() bool {
+	return r.RepoID == 0
+}
+
+// Validate returns an error if the commit message pattern is not a valid regular expression
+func (r *PushRule) Validate() error {
+	if r.CommitMessagePattern != "" {
+		if _, err := regexp.Compile(r.CommitMessagePattern); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid commit message pattern: %v", err)
+		}
+	}
+	if r.MaxFileSize < 0 {
+		return util.NewInvalidArgumentErrorf("max file size must not be negative")
+	}
+	return nil
+}
+
+// CommitMessageRegexp returns the compiled commit message pattern or nil if the commit messages are not restricted
+func (r *PushRule) CommitMessageRegexp() *regexp.Regexp {
+	if r.CommitMessagePattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(r.CommitMessagePattern)
+	if err != nil {
+		// the pattern is validated when the rule is saved
+		return nil
+	}
+	return re
+}
+
+// GetForbiddenFilePatterns parses a semicolon separated list of forbidden file patterns and returns a glob.Glob slice
+func (r *PushRule) GetForbiddenFilePatterns() []glob.Glob {
+	return getFilePatterns(r.ForbiddenFilePatterns)
+}
+
+// GetPushRule returns the push rule of the organization (repoID 0) or of the repository (ownerID 0)
+func GetPushRule(ctx context.Context, ownerID, repoID int64) (*PushRule, error) {
+	rule := &PushRule{}
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND repo_id = ?", ownerID, repoID).Get(rule)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPushRuleNotExist
+	}
+	return rule, nil
+}
+
+// GetRepoPushRules returns the push rules which apply to the repository, the rule of the owner comes first
+func GetRepoPushRules(ctx context.Context, repo *repo_model.Repository) ([]*PushRule, error) {
+	rules := make([]*PushRule, 0, 2)
+	return rules, db.GetEngine(ctx).
+		Where(builder.Or(
+			builder.Eq{"owner_id": repo.OwnerID, "repo_id": 0},
+			builder.Eq{"owner_id": 0, "repo_id": repo.ID},
+		)).
+		OrderBy("repo_id ASC").
+		Find(&rules)
+}
+
+// SavePushRule inserts the push rule or updates all its columns if it exists
+func SavePushRule(ctx context.Context, rule *PushRule) error {
+	if rule.ID == 0 {
+		return db.Insert(ctx, rule)
+	}
+	_, err := db.GetEngine(ctx).ID(rule.ID).AllCols().Update(rule)
+	return err
+}
+
+// DeletePushRule deletes the push rule of the organization (repoID 0) or of the repository (ownerID 0)
+func DeletePushRule(ctx context.Context, ownerID, repoID int64) error {
+	_, err := db.GetEngine(ctx).Where("owner_id = ? AND repo_id = ?", ownerID, repoID).Delete(&PushRule{})
+	return err
+}
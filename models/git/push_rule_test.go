@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushRuleValidate(t *testing.T) {
+	assert.NoError(t, (&git_model.PushRule{}).Validate())
+	assert.NoError(t, (&git_model.PushRule{CommitMessagePattern: `^[A-Z]+-\d+`, MaxFileSize: 1024}).Validate())
+	assert.Error(t, (&git_model.PushRule{CommitMessagePattern: `(`}).Validate())
+	assert.Error(t, (&git_model.PushRule{MaxFileSize: -1}).Validate())
+
+	rule := &git_model.PushRule{CommitMessagePattern: `^[A-Z]+-\d+`}
+	re := rule.CommitMessageRegexp()
+	if assert.NotNil(t, re) {
+		assert.True(t, re.MatchString("GITEA-123 fix the build\n"))
+		assert.False(t, re.MatchString("fix the build\n"))
+	}
+	assert.Nil(t, (&git_model.PushRule{}).CommitMessageRegexp())
+}
+
+func TestPushRuleForbiddenFilePatterns(t *testing.T) {
+	rule := &git_model.PushRule{ForbiddenFilePatterns: "**.exe; secrets/**"}
+	globs := rule.GetForbiddenFilePatterns()
+	assert.Len(t, globs, 2)
+
+	match := func(path string) bool {
+		for _, g := range globs {
+			if g.Match(path) {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, match("setup.exe"))
+	assert.True(t, match("bin/tool.exe"))
+	assert.True(t, match("secrets/prod/key.pem"))
+	assert.False(t, match("docs/secrets.md"))
+	assert.False(t, match("main.go"))
+}
+
+func TestGetRepoPushRules(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+
+	rules, err := git_model.GetRepoPushRules(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	repoRule := &git_model.PushRule{RepoID: repo.ID, MaxFileSize: 1024}
+	assert.NoError(t, git_model.SavePushRule(db.DefaultContext, repoRule))
+	orgRule := &git_model.PushRule{OwnerID: repo.OwnerID, CommitMessagePattern: `^[A-Z]+-\d+`}
+	assert.NoError(t, git_model.SavePushRule(db.DefaultContext, orgRule))
+	otherOrgRule := &git_model.PushRule{OwnerID: repo.OwnerID + 1, RequireVerifiedAuthorEmail: true}
+	assert.NoError(t, git_model.SavePushRule(db.DefaultContext, otherOrgRule))
+
+	rules, err = git_model.GetRepoPushRules(db.DefaultContext, repo)
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, orgRule.ID, rules[0].ID)
+		assert.True(t, rules[0].IsOrgRule())
+		assert.Equal(t, repoRule.ID, rules[1].ID)
+		assert.False(t, rules[1].IsOrgRule())
+	}
+
+	repoRule.MaxFileSize = 2048
+	assert.NoError(t, git_model.SavePushRule(db.DefaultContext, repoRule))
+	rule, err := git_model.GetPushRule(db.DefaultContext, 0, repo.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2048, rule.MaxFileSize)
+
+	assert.NoError(t, git_model.DeletePushRule(db.DefaultContext, 0, repo.ID))
+	_, err = git_model.GetPushRule(db.DefaultContext, 0, repo.ID)
+	assert.ErrorIs(t, err, git_model.ErrPushRuleNotExist)
+	unittest.AssertExistsAndLoadBean(t, &git_model.PushRule{ID: orgRule.ID})
+}
@@ -0,0 +1,405 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"xorm.io/builder"
+)
+
+var ErrRulesetNotExist = util.NewNotExistErrorf("ruleset does not exist")
+
+// RulesetTarget is the kind of refs a ruleset applies to
+type RulesetTarget string
+
+// The kinds of refs a ruleset can apply to
+const (
+	RulesetTargetBranch RulesetTarget = "branch"
+	RulesetTargetTag    RulesetTarget = "tag"
+)
+
+// IsValid returns true if the target is known
+func (t RulesetTarget) IsValid() bool {
+	return t == RulesetTargetBranch || t == RulesetTargetTag
+}
+
+// RulesetEnforcement defines what happens if a rule of a ruleset is violated
+type RulesetEnforcement int
+
+// The enforcement modes of a ruleset
+const (
+	RulesetEnforcementDisabled RulesetEnforcement = iota // the ruleset is not evaluated
+	RulesetEnforcementActive                             // violations are rejected
+	RulesetEnforcementEvaluate                           // violations are recorded but allowed
+)
+
+var rulesetEnforcementNames = map[RulesetEnforcement]string{
+	RulesetEnforcementDisabled: "disabled",
+	RulesetEnforcementActive:   "active",
+	RulesetEnforcementEvaluate: "evaluate",
+}
+
+// String returns the name of the enforcement mode
+func (e RulesetEnforcement) String() string {
+	return rulesetEnforcementNames[e]
+}
+
+// ParseRulesetEnforcement parses the name of an enforcement mode
+func ParseRulesetEnforcement(name string) (RulesetEnforcement, error) {
+	for e, n := range rulesetEnforcementNames {
+		if n == name {
+			return e, nil
+		}
+	}
+	return RulesetEnforcementDisabled, util.NewInvalidArgumentErrorf("invalid ruleset enforcement %q", name)
+}
+
+// Ruleset protects the branches or tags of all matching repositories of an organization or of the whole instance.
+// The rules are evaluated in addition to the protected branch and tag rules of the repositories.
+type Ruleset struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"` // 0 for instance level rulesets
+	Name        string             `xorm:"NOT NULL"`
+	Target      RulesetTarget      `xorm:"VARCHAR(16) NOT NULL"`
+	Enforcement RulesetEnforcement `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+	RepoNamePatterns []string `xorm:"JSON TEXT"` // the ruleset applies to all repositories if empty
+	RepoTopics       []string `xorm:"JSON TEXT"` // a repository has to have one of the topics if not empty
+	RefPatterns      []string `xorm:"JSON TEXT"` // the ruleset applies to all branches or tags if empty
+
+	BypassUserIDs []int64 `xorm:"JSON TEXT"`
+	BypassTeamIDs []int64 `xorm:"JSON TEXT"`
+
+	RestrictCreation      bool   `xorm:"NOT NULL DEFAULT false"`
+	RestrictUpdate        bool   `xorm:"NOT NULL DEFAULT false"` // branches can only be updated by merging pull requests
+	RestrictDeletion      bool   `xorm:"NOT NULL DEFAULT false"`
+	BlockForcePush        bool   `xorm:"NOT NULL DEFAULT false"`
+	RequireSignedCommits  bool   `xorm:"NOT NULL DEFAULT false"`
+	ProtectedFilePatterns string `xorm:"TEXT"`
+
+	RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
+	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
+	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireCodeOwnerApproval      bool     `xorm:"NOT NULL DEFAULT false"`
+	EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
+	StatusCheckContexts           []string `xorm:"JSON TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(Ruleset))
+	db.RegisterModel(new(RulesetViolation))
+}
+
+// IsActive returns true if violations of the ruleset are rejected
+func (rs *Ruleset) IsActive() bool {
+	return rs.Enforcement == RulesetEnforcementActive
+}
+
+// ValidateRulesetPatterns returns an error if one of the glob patterns is invalid
+func ValidateRulesetPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func matchRulesetPatterns(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			continue
+		}
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRepo returns true if the ruleset applies to the repository.
+// Repository name patterns containing a slash are matched against the full name of the repository.
+func (rs *Ruleset) MatchRepo(repo *repo_model.Repository) bool {
+	if rs.OwnerID != 0 && rs.OwnerID != repo.OwnerID {
+		return false
+	}
+	if len(rs.RepoNamePatterns) > 0 {
+		matched := false
+		for _, pattern := range rs.RepoNamePatterns {
+			name := repo.LowerName
+			if strings.Contains(pattern, "/") {
+				name = strings.ToLower(repo.FullName())
+			}
+			if matchRulesetPatterns([]string{strings.ToLower(pattern)}, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(rs.RepoTopics) > 0 && !slices.ContainsFunc(rs.RepoTopics, func(topic string) bool {
+		return slices.Contains(repo.Topics, strings.ToLower(topic))
+	}) {
+		return false
+	}
+	return true
+}
+
+// MatchRef returns true if the ruleset applies to the branch or tag
+func (rs *Ruleset) MatchRef(refName git.RefName) bool {
+	var name string
+	switch {
+	case rs.Target == RulesetTargetBranch && refName.IsBranch():
+		name = refName.BranchName()
+	case rs.Target == RulesetTargetTag && refName.IsTag():
+		name = refName.TagName()
+	default:
+		return false
+	}
+	return len(rs.RefPatterns) == 0 || matchRulesetPatterns(rs.RefPatterns, name)
+}
+
+// CanBypass returns true if the user is allowed to bypass the ruleset
+func (rs *Ruleset) CanBypass(ctx context.Context, userID int64) (bool, error) {
+	if userID <= 0 {
+		return false, nil
+	}
+	if slices.Contains(rs.BypassUserIDs, userID) {
+		return true, nil
+	}
+	if len(rs.BypassTeamIDs) == 0 {
+		return false, nil
+	}
+	return organization.IsUserInTeams(ctx, userID, rs.BypassTeamIDs)
+}
+
+// GetProtectedFilePatterns parses a semicolon separated list of protected file patterns and returns a glob.Glob slice
+func (rs *Ruleset) GetProtectedFilePatterns() []glob.Glob {
+	return getFilePatterns(rs.ProtectedFilePatterns)
+}
+
+// ToProtectedBranch returns a protected branch rule with the pull request rules of the ruleset,
+// it is used to evaluate the ruleset like a protected branch rule of the repository.
+func (rs *Ruleset) ToProtectedBranch(repo *repo_model.Repository) *ProtectedBranch {
+	return &ProtectedBranch{
+		RepoID:                        repo.ID,
+		Repo:                          repo,
+		RuleName:                      rs.Name,
+		RequiredApprovals:             rs.RequiredApprovals,
+		BlockOnRejectedReviews:        rs.BlockOnRejectedReviews,
+		BlockOnOfficialReviewRequests: rs.BlockOnOfficialReviewRequests,
+		BlockOnOutdatedBranch:         rs.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      rs.RequireCodeOwnerApproval,
+		EnableStatusCheck:             rs.EnableStatusCheck,
+		StatusCheckContexts:           rs.StatusCheckContexts,
+		RequireSignedCommits:          rs.RequireSignedCommits,
+		ProtectedFilePatterns:         rs.ProtectedFilePatterns,
+	}
+}
+
+// GetRulesetByID gets the ruleset of the owner (0 for instance level rulesets)
+func GetRulesetByID(ctx context.Context, ownerID, id int64) (*Ruleset, error) {
+	rs := &Ruleset{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Get(rs)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrRulesetNotExist
+	}
+	return rs, nil
+}
+
+// FindRulesetsOptions represents the options to find the rulesets of an owner
+type FindRulesetsOptions struct {
+	db.ListOptions
+	OwnerID int64 // 0 for instance level rulesets
+}
+
+func (opts FindRulesetsOptions) ToConds() builder.Cond {
+	return builder.Eq{"owner_id": opts.OwnerID}
+}
+
+func (opts FindRulesetsOptions) ToOrders() string {
+	return "`id` ASC"
+}
+
+// GetRepoRulesets gets the enabled instance level and owner rulesets which apply to the repository and the target
+func GetRepoRulesets(ctx context.Context, repo *repo_model.Repository, target RulesetTarget) ([]*Ruleset, error) {
+	rulesets := make([]*Ruleset, 0, 5)
+	if err := db.GetEngine(ctx).
+		Where(builder.In("owner_id", 0, repo.OwnerID)).
+		And("target = ?", target).
+		And("enforcement <> ?", RulesetEnforcementDisabled).
+		OrderBy("owner_id ASC, id ASC").
+		Find(&rulesets); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(rulesets, func(rs *Ruleset) bool {
+		return !rs.MatchRepo(repo)
+	}), nil
+}
+
+// InsertRuleset inserts a ruleset
+func InsertRuleset(ctx context.Context, rs *Ruleset) error {
+	return db.Insert(ctx, rs)
+}
+
+// UpdateRuleset updates all columns of a ruleset
+func UpdateRuleset(ctx context.Context, rs *Ruleset) error {
+	_, err := db.GetEngine(ctx).ID(rs.ID).AllCols().Update(rs)
+	return err
+}
+
+// DeleteRuleset deletes a ruleset and its recorded violations
+func DeleteRuleset(ctx context.Context, id int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("ruleset_id = ?", id).Delete(&RulesetViolation{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(id).Delete(&Ruleset{})
+		return err
+	})
+}
+
+// DeleteRulesetsByOwnerID deletes all rulesets of an owner and their recorded violations
+func DeleteRulesetsByOwnerID(ctx context.Context, ownerID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).
+			Where(builder.In("ruleset_id", builder.Select("id").From("ruleset").Where(builder.Eq{"owner_id": ownerID}))).
+			Delete(&RulesetViolation{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Delete(&Ruleset{})
+		return err
+	})
+}
+
+// RulesetRefAction is a change of a branch or tag which is not done by a push
+type RulesetRefAction int
+
+// The changes of branches and tags which are not done by a push
+const (
+	RulesetRefActionCreate RulesetRefAction = iota
+	RulesetRefActionDelete
+)
+
+// CheckRulesetsRefAction checks whether the rulesets applying to the branch or tag restrict the creation or deletion by the doer.
+// It is used for the changes which are not done by a push and therefore not checked by the pre-receive hook.
+// The violation of the first actively enforced ruleset is returned, the violations of rulesets in evaluate mode are recorded.
+func CheckRulesetsRefAction(ctx context.Context, repo *repo_model.Repository, refName git.RefName, doerID int64, action RulesetRefAction) (*RulesetViolation, error) {
+	target := RulesetTargetBranch
+	if refName.IsTag() {
+		target = RulesetTargetTag
+	}
+
+	rulesets, err := GetRepoRulesets(ctx, repo, target)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := make([]*RulesetViolation, 0, len(rulesets))
+	for _, rs := range rulesets {
+		if !rs.MatchRef(refName) {
+			continue
+		}
+		var reason string
+		if action == RulesetRefActionCreate && rs.RestrictCreation {
+			reason = "creation is restricted"
+		} else if action == RulesetRefActionDelete && rs.RestrictDeletion {
+			reason = "deletion is restricted"
+		} else {
+			continue
+		}
+		if canBypass, err := rs.CanBypass(ctx, doerID); err != nil {
+			return nil, err
+		} else if canBypass {
+			continue
+		}
+
+		v := &RulesetViolation{
+			RulesetID: rs.ID,
+			Ruleset:   rs,
+			RepoID:    repo.ID,
+			RefName:   refName,
+			DoerID:    doerID,
+			Reason:    reason,
+		}
+		if rs.IsActive() {
+			return v, nil
+		}
+		violations = append(violations, v)
+	}
+
+	for _, v := range violations {
+		if err := InsertRulesetViolation(ctx, v); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// RulesetViolation is a violated rule of a ruleset.
+// The violations of rulesets in evaluate mode are recorded to check the effect of the ruleset before enforcing it.
+type RulesetViolation struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RulesetID   int64              `xorm:"INDEX NOT NULL"`
+	Ruleset     *Ruleset           `xorm:"-"`
+	RepoID      int64              `xorm:"INDEX NOT NULL"`
+	RefName     git.RefName        `xorm:"VARCHAR(255)"`
+	DoerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+	Reason      string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// Error returns the message shown to the user if the violation is rejected
+func (v *RulesetViolation) Error() string {
+	return fmt.Sprintf("%s is protected by ruleset %q: %s", v.RefName.ShortName(), v.Ruleset.Name, v.Reason)
+}
+
+// InsertRulesetViolation records a violation of a ruleset
+func InsertRulesetViolation(ctx context.Context, v *RulesetViolation) error {
+	return db.Insert(ctx, v)
+}
+
+// FindRulesetViolationsOptions represents the options to find the recorded violations of a ruleset
+type FindRulesetViolationsOptions struct {
+	db.ListOptions
+	RulesetID int64
+	RepoID    int64
+}
+
+func (opts FindRulesetViolationsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RulesetID > 0 {
+		cond = cond.And(builder.Eq{"ruleset_id": opts.RulesetID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	return cond
+}
+
+func (opts FindRulesetViolationsOptions) ToOrders() string {
+	return "`id` DESC"
+}
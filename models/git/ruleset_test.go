@@ -0,0 +1,125 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulesetMatchRepo(t *testing.T) {
+	repo := &repo_model.Repository{
+		OwnerID:   3,
+		OwnerName: "org3",
+		Name:      "Repo3",
+		LowerName: "repo3",
+		Topics:    []string{"go", "backend"},
+	}
+
+	cases := []struct {
+		ruleset *git_model.Ruleset
+		match   bool
+	}{
+		{&git_model.Ruleset{}, true},
+		{&git_model.Ruleset{OwnerID: 3}, true},
+		{&git_model.Ruleset{OwnerID: 4}, false},
+		{&git_model.Ruleset{RepoNamePatterns: []string{"repo*"}}, true},
+		{&git_model.Ruleset{RepoNamePatterns: []string{"Repo3"}}, true},
+		{&git_model.Ruleset{RepoNamePatterns: []string{"other"}}, false},
+		{&git_model.Ruleset{RepoNamePatterns: []string{"org3/*"}}, true},
+		{&git_model.Ruleset{RepoNamePatterns: []string{"user2/*"}}, false},
+		{&git_model.Ruleset{RepoTopics: []string{"Go"}}, true},
+		{&git_model.Ruleset{RepoTopics: []string{"frontend"}}, false},
+		{&git_model.Ruleset{RepoNamePatterns: []string{"repo*"}, RepoTopics: []string{"frontend"}}, false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, c.ruleset.MatchRepo(repo), "ruleset %+v", c.ruleset)
+	}
+}
+
+func TestRulesetMatchRef(t *testing.T) {
+	cases := []struct {
+		ruleset *git_model.Ruleset
+		refName git.RefName
+		match   bool
+	}{
+		{&git_model.Ruleset{Target: git_model.RulesetTargetBranch}, git.RefNameFromBranch("main"), true},
+		{&git_model.Ruleset{Target: git_model.RulesetTargetBranch}, git.RefNameFromTag("v1.0"), false},
+		{&git_model.Ruleset{Target: git_model.RulesetTargetTag}, git.RefNameFromTag("v1.0"), true},
+		{&git_model.Ruleset{Target: git_model.RulesetTargetBranch, RefPatterns: []string{"release/*"}}, git.RefNameFromBranch("release/1.0"), true},
+		{&git_model.Ruleset{Target: git_model.RulesetTargetBranch, RefPatterns: []string{"release/*"}}, git.RefNameFromBranch("release/1.0/fix"), false},
+		{&git_model.Ruleset{Target: git_model.RulesetTargetBranch, RefPatterns: []string{"release/**"}}, git.RefNameFromBranch("release/1.0/fix"), true},
+		{&git_model.Ruleset{Target: git_model.RulesetTargetTag, RefPatterns: []string{"v*"}}, git.RefNameFromTag("latest"), false},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.match, c.ruleset.MatchRef(c.refName), "ruleset %+v, ref %s", c.ruleset, c.refName)
+	}
+}
+
+func TestParseRulesetEnforcement(t *testing.T) {
+	for _, e := range []git_model.RulesetEnforcement{git_model.RulesetEnforcementDisabled, git_model.RulesetEnforcementActive, git_model.RulesetEnforcementEvaluate} {
+		parsed, err := git_model.ParseRulesetEnforcement(e.String())
+		assert.NoError(t, err)
+		assert.Equal(t, e, parsed)
+	}
+
+	_, err := git_model.ParseRulesetEnforcement("enabled")
+	assert.Error(t, err)
+}
+
+func TestCheckRulesetsRefAction(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	assert.NoError(t, repo.LoadOwner(db.DefaultContext))
+
+	active := &git_model.Ruleset{
+		OwnerID:          repo.OwnerID,
+		Name:             "release branches",
+		Target:           git_model.RulesetTargetBranch,
+		Enforcement:      git_model.RulesetEnforcementActive,
+		RefPatterns:      []string{"release/*"},
+		RestrictDeletion: true,
+		BypassUserIDs:    []int64{2},
+	}
+	assert.NoError(t, git_model.InsertRuleset(db.DefaultContext, active))
+
+	evaluate := &git_model.Ruleset{
+		Name:             "instance tags",
+		Target:           git_model.RulesetTargetTag,
+		Enforcement:      git_model.RulesetEnforcementEvaluate,
+		RestrictDeletion: true,
+	}
+	assert.NoError(t, git_model.InsertRuleset(db.DefaultContext, evaluate))
+
+	violation, err := git_model.CheckRulesetsRefAction(db.DefaultContext, repo, git.RefNameFromBranch("release/1.0"), 4, git_model.RulesetRefActionDelete)
+	assert.NoError(t, err)
+	if assert.NotNil(t, violation) {
+		assert.Equal(t, active.ID, violation.RulesetID)
+	}
+
+	violation, err = git_model.CheckRulesetsRefAction(db.DefaultContext, repo, git.RefNameFromBranch("release/1.0"), 2, git_model.RulesetRefActionDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, violation)
+
+	violation, err = git_model.CheckRulesetsRefAction(db.DefaultContext, repo, git.RefNameFromBranch("release/1.0"), 4, git_model.RulesetRefActionCreate)
+	assert.NoError(t, err)
+	assert.Nil(t, violation)
+
+	violation, err = git_model.CheckRulesetsRefAction(db.DefaultContext, repo, git.RefNameFromTag("v1.0"), 4, git_model.RulesetRefActionDelete)
+	assert.NoError(t, err)
+	assert.Nil(t, violation)
+	unittest.AssertExistsAndLoadBean(t, &git_model.RulesetViolation{RulesetID: evaluate.ID, RepoID: repo.ID, DoerID: 4})
+
+	assert.NoError(t, git_model.DeleteRulesetsByOwnerID(db.DefaultContext, repo.OwnerID))
+	unittest.AssertNotExistsBean(t, &git_model.Ruleset{ID: active.ID})
+	unittest.AssertExistsAndLoadBean(t, &git_model.Ruleset{ID: evaluate.ID})
+}
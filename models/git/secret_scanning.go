@@ -0,0 +1,195 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+var ErrSecretScanningAlertNotExist = util.NewNotExistErrorf("secret scanning alert does not exist")
+
+// SecretScanningAlertState is the state of a secret scanning alert
+type SecretScanningAlertState string
+
+// The states of a secret scanning alert
+const (
+	SecretScanningAlertStateOpen     SecretScanningAlertState = "open"
+	SecretScanningAlertStateResolved SecretScanningAlertState = "resolved"
+)
+
+// IsValid returns true if the state is known
+func (s SecretScanningAlertState) IsValid() bool {
+	return s == SecretScanningAlertStateOpen || s == SecretScanningAlertStateResolved
+}
+
+// SecretScanningResolution is the reason why a secret scanning alert has been resolved
+type SecretScanningResolution string
+
+// The reasons why a secret scanning alert can be resolved
+const (
+	SecretScanningResolutionRevoked       SecretScanningResolution = "revoked"
+	SecretScanningResolutionFalsePositive SecretScanningResolution = "false_positive"
+	SecretScanningResolutionUsedInTests   SecretScanningResolution = "used_in_tests"
+	SecretScanningResolutionWontFix       SecretScanningResolution = "wont_fix"
+)
+
+// SecretScanningResolutions returns all the reasons why a secret scanning alert can be resolved
+func SecretScanningResolutions() []SecretScanningResolution {
+	return []SecretScanningResolution{
+		SecretScanningResolutionRevoked,
+		SecretScanningResolutionFalsePositive,
+		SecretScanningResolutionUsedInTests,
+		SecretScanningResolutionWontFix,
+	}
+}
+
+// IsValid returns true if the resolution is known
+func (r SecretScanningResolution) IsValid() bool {
+	switch r {
+	case SecretScanningResolutionRevoked, SecretScanningResolutionFalsePositive, SecretScanningResolutionUsedInTests, SecretScanningResolutionWontFix:
+		return true
+	}
+	return false
+}
+
+// SecretScanningAlert is a secret found in the files of a repository.
+// There is one alert per secret and pattern, the location is the first file the secret has been found in.
+// Only the hash and a redacted form of the secret are stored.
+type SecretScanningAlert struct {
+	ID             int64                    `xorm:"pk autoincr"`
+	RepoID         int64                    `xorm:"UNIQUE(repo_secret) NOT NULL"`
+	PatternID      string                   `xorm:"VARCHAR(64) UNIQUE(repo_secret) NOT NULL"`
+	PatternName    string                   `xorm:"VARCHAR(255)"`
+	SecretHash     string                   `xorm:"VARCHAR(64) UNIQUE(repo_secret) NOT NULL"` // hex encoded SHA256 of the secret
+	RedactedSecret string                   `xorm:"VARCHAR(255)"`
+	CommitID       string                   `xorm:"VARCHAR(64)"`
+	Path           string                   `xorm:"TEXT"`
+	Line           int                      `xorm:"NOT NULL DEFAULT 0"`
+	State          SecretScanningAlertState `xorm:"VARCHAR(16) INDEX NOT NULL"`
+
+	Resolution        SecretScanningResolution `xorm:"VARCHAR(32)"`
+	ResolutionComment string                   `xorm:"TEXT"`
+	ResolverID        int64                    `xorm:"NOT NULL DEFAULT 0"`
+	Resolver          *user_model.User         `xorm:"-"`
+	ResolvedUnix      timeutil.TimeStamp
+
+	// set if the secret has been pushed by bypassing the push protection
+	PushProtectionBypassed bool             `xorm:"NOT NULL DEFAULT false"`
+	BypassReason           string           `xorm:"TEXT"`
+	BypasserID             int64            `xorm:"NOT NULL DEFAULT 0"`
+	Bypasser               *user_model.User `xorm:"-"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// SecretScanningBackfill records that the history of a repository has been scanned for secrets
+type SecretScanningBackfill struct {
+	RepoID      int64              `xorm:"pk"`
+	ScannedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(SecretScanningAlert))
+	db.RegisterModel(new(SecretScanningBackfill))
+}
+
+// IsOpen returns true if the alert has not been resolved
+func (a *SecretScanningAlert) IsOpen() bool {
+	return a.State == SecretScanningAlertStateOpen
+}
+
+// LoadAttributes loads the users who resolved the alert and bypassed the push protection
+func (a *SecretScanningAlert) LoadAttributes(ctx context.Context) (err error) {
+	if a.ResolverID > 0 && a.Resolver == nil {
+		if a.Resolver, err = user_model.GetPossibleUserByID(ctx, a.ResolverID); err != nil {
+			return err
+		}
+	}
+	if a.BypasserID > 0 && a.Bypasser == nil {
+		if a.Bypasser, err = user_model.GetPossibleUserByID(ctx, a.BypasserID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateSecretScanningAlertIfNotExist inserts the alert if the repository has no alert for the same secret and pattern,
+// it returns false if the alert already exists.
+func CreateSecretScanningAlertIfNotExist(ctx context.Context, alert *SecretScanningAlert) (created bool, err error) {
+	err = db.WithTx(ctx, func(ctx context.Context) error {
+		exist, err := db.GetEngine(ctx).Exist(&SecretScanningAlert{
+			RepoID:     alert.RepoID,
+			PatternID:  alert.PatternID,
+			SecretHash: alert.SecretHash,
+		})
+		if err != nil || exist {
+			return err
+		}
+		if alert.State == "" {
+			alert.State = SecretScanningAlertStateOpen
+		}
+		created = true
+		return db.Insert(ctx, alert)
+	})
+	return created, err
+}
+
+// GetSecretScanningAlertByID returns the alert of the repository
+func GetSecretScanningAlertByID(ctx context.Context, repoID, id int64) (*SecretScanningAlert, error) {
+	alert := &SecretScanningAlert{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(alert)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrSecretScanningAlertNotExist
+	}
+	return alert, nil
+}
+
+// UpdateSecretScanningAlertState updates the state and the resolution of the alert
+func UpdateSecretScanningAlertState(ctx context.Context, alert *SecretScanningAlert) error {
+	_, err := db.GetEngine(ctx).ID(alert.ID).Cols("state", "resolution", "resolution_comment", "resolver_id", "resolved_unix").Update(alert)
+	return err
+}
+
+// FindSecretScanningAlertsOptions represents the options to find the secret scanning alerts of a repository
+type FindSecretScanningAlertsOptions struct {
+	db.ListOptions
+	RepoID int64
+	State  SecretScanningAlertState
+}
+
+func (opts FindSecretScanningAlertsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.State != "" {
+		cond = cond.And(builder.Eq{"state": opts.State})
+	}
+	return cond
+}
+
+func (opts FindSecretScanningAlertsOptions) ToOrders() string {
+	return "`id` DESC"
+}
+
+// IsSecretScanningBackfilled returns true if the history of the repository has been scanned
+func IsSecretScanningBackfilled(ctx context.Context, repoID int64) (bool, error) {
+	return db.GetEngine(ctx).Exist(&SecretScanningBackfill{RepoID: repoID})
+}
+
+// MarkSecretScanningBackfilled records that the history of the repository has been scanned
+func MarkSecretScanningBackfilled(ctx context.Context, repoID int64) error {
+	return db.Insert(ctx, &SecretScanningBackfill{RepoID: repoID})
+}
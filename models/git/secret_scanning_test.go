@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretScanningAlerts(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	alert := &git_model.SecretScanningAlert{
+		RepoID:     1,
+		PatternID:  "aws_access_key_id",
+		SecretHash: "1234",
+		Path:       "config.ini",
+		Line:       2,
+	}
+	created, err := git_model.CreateSecretScanningAlertIfNotExist(db.DefaultContext, alert)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, git_model.SecretScanningAlertStateOpen, alert.State)
+
+	// the same secret found in another file doesn't create another alert
+	created, err = git_model.CreateSecretScanningAlertIfNotExist(db.DefaultContext, &git_model.SecretScanningAlert{
+		RepoID:     1,
+		PatternID:  "aws_access_key_id",
+		SecretHash: "1234",
+		Path:       "other.ini",
+	})
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	created, err = git_model.CreateSecretScanningAlertIfNotExist(db.DefaultContext, &git_model.SecretScanningAlert{
+		RepoID:     2,
+		PatternID:  "aws_access_key_id",
+		SecretHash: "1234",
+	})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	alerts, err := db.Find[git_model.SecretScanningAlert](db.DefaultContext, git_model.FindSecretScanningAlertsOptions{RepoID: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "config.ini", alerts[0].Path)
+	}
+
+	_, err = git_model.GetSecretScanningAlertByID(db.DefaultContext, 2, alert.ID)
+	assert.ErrorIs(t, err, git_model.ErrSecretScanningAlertNotExist)
+
+	alert.State = git_model.SecretScanningAlertStateResolved
+	alert.Resolution = git_model.SecretScanningResolutionRevoked
+	alert.ResolverID = 2
+	assert.NoError(t, git_model.UpdateSecretScanningAlertState(db.DefaultContext, alert))
+
+	alert, err = git_model.GetSecretScanningAlertByID(db.DefaultContext, 1, alert.ID)
+	assert.NoError(t, err)
+	assert.False(t, alert.IsOpen())
+	assert.Equal(t, git_model.SecretScanningResolutionRevoked, alert.Resolution)
+	assert.NoError(t, alert.LoadAttributes(db.DefaultContext))
+	assert.Equal(t, "user2", alert.Resolver.Name)
+
+	alerts, err = db.Find[git_model.SecretScanningAlert](db.DefaultContext, git_model.FindSecretScanningAlertsOptions{RepoID: 1, State: git_model.SecretScanningAlertStateOpen})
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestSecretScanningBackfill(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	backfilled, err := git_model.IsSecretScanningBackfilled(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.False(t, backfilled)
+
+	assert.NoError(t, git_model.MarkSecretScanningBackfilled(db.DefaultContext, 1))
+
+	backfilled, err = git_model.IsSecretScanningBackfilled(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.True(t, backfilled)
+}
@@ -114,6 +114,9 @@ const (
 
 	CommentTypePin   // 36 pin Issue
 	CommentTypeUnpin // 37 unpin Issue
+
+	CommentTypePRAddedToMergeQueue     // 38 pr was added to the merge queue
+	CommentTypePRRemovedFromMergeQueue // 39 pr was removed from the merge queue
 )
 
 var commentStrings = []string{
@@ -155,6 +158,8 @@ var commentStrings = []string{
 	"pull_cancel_scheduled_merge",
 	"pin",
 	"unpin",
+	"pull_add_merge_queue",
+	"pull_remove_merge_queue",
 }
 
 func (t CommentType) String() string {
@@ -1236,6 +1241,30 @@ func CreateAutoMergeComment(ctx context.Context, typ CommentType, pr *PullReques
 	return comment, err
 }
 
+// CreateMergeQueueComment is a internal function, only use it for CommentTypePRAddedToMergeQueue and CommentTypePRRemovedFromMergeQueue CommentTypes,
+// the reason is shown when a pull request is removed from the merge queue
+func CreateMergeQueueComment(ctx context.Context, typ CommentType, pr *PullRequest, doer *user_model.User, reason string) (comment *Comment, err error) {
+	if typ != CommentTypePRAddedToMergeQueue && typ != CommentTypePRRemovedFromMergeQueue {
+		return nil, fmt.Errorf("comment type %d cannot be used to create a merge queue comment", typ)
+	}
+	if err = pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	comment, err = CreateComment(ctx, &CreateCommentOptions{
+		Type:    typ,
+		Doer:    doer,
+		Repo:    pr.BaseRepo,
+		Issue:   pr.Issue,
+		Content: reason,
+	})
+	return comment, err
+}
+
 // RemapExternalUser ExternalUserRemappable interface
 func (c *Comment) RemapExternalUser(externalName string, externalID, userID int64) error {
 	c.OriginalAuthor = externalName
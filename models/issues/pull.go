@@ -203,6 +203,12 @@ func DeletePullsByBaseRepoID(ctx context.Context, repoID int64) error {
 		return err
 	}
 
+	// Delete merge queue entries
+	if _, err := db.GetEngine(ctx).In("pull_id", deleteCond).
+		Delete(&pull_model.MergeQueueEntry{}); err != nil {
+		return err
+	}
+
 	// Delete review states
 	if _, err := db.GetEngine(ctx).In("pull_id", deleteCond).
 		Delete(&pull_model.ReviewState{}); err != nil {
@@ -933,6 +939,26 @@ func MergeBlockedByOutdatedBranch(protectBranch *git_model.ProtectedBranch, pr *
 	return protectBranch.BlockOnOutdatedBranch && pr.CommitsBehind > 0
 }
 
+// CodeOwnersFiles are the paths the CODEOWNERS file is looked up at, the first existing one is used
+var CodeOwnersFiles = []string{"CODEOWNERS", "docs/CODEOWNERS", ".gitea/CODEOWNERS"}
+
+// GetCodeOwnersFromCommit returns the code owners configuration of the CODEOWNERS file in the commit,
+// the base branch of a pull request is the commit the code owners of the pull request are read from
+func GetCodeOwnersFromCommit(ctx context.Context, commit *git.Commit) []*CodeOwnerRule {
+	var data string
+	for _, file := range CodeOwnersFiles {
+		if blob, err := commit.GetBlobByPath(file); err == nil {
+			data, err = blob.GetBlobContent(setting.UI.MaxDisplayFileSize)
+			if err == nil {
+				break
+			}
+		}
+	}
+
+	rules, _ := GetCodeOwnersFromContent(ctx, data)
+	return rules
+}
+
 // GetCodeOwnersFromContent returns the code owners configuration
 // Return empty slice if files missing
 // Return warning messages on parsing errors
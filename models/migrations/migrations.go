@@ -603,6 +603,40 @@ var migrations = []Migration{
 	NewMigration("Add index for release sha1", v1_23.AddIndexForReleaseSha1),
 	// v305 -> v306
 	NewMigration("Add Repository Licenses", v1_23.AddRepositoryLicenses),
+	// v306 -> v307
+	NewMigration("Add concurrency columns to action_run and action_run_job", v1_23.AddConcurrencyColumnsToActionRunAndJob),
+	// v307 -> v308
+	NewMigration("Add parent_job_id column to action_run_job", v1_23.AddParentJobIDToActionRunJob),
+	// v308 -> v309
+	NewMigration("Add action_environment and action_deployment tables", v1_23.AddActionEnvironmentTables),
+	// v309 -> v310
+	NewMigration("Add action_cache table", v1_23.AddActionCacheTable),
+	// v310 -> v311
+	NewMigration("Add merge queue to protected branches", v1_23.AddMergeQueue),
+	// v311 -> v312
+	NewMigration("Add audit_event table", v1_23.AddAuditEventTable),
+	// v312 -> v313
+	NewMigration("Add package upstream tables", v1_23.AddPackageUpstreamTables),
+	// v313 -> v314
+	NewMigration("Add package virtual registry tables", v1_23.AddPackageVirtualRegistryTables),
+	// v314 -> v315
+	NewMigration("Add terraform_state and terraform_state_version tables", v1_23.AddTerraformStateTables),
+	// v315 -> v316
+	NewMigration("Add quota tables", v1_23.AddQuotaTables),
+	// v316 -> v317
+	NewMigration("Add package_container_component table", v1_23.AddPackageContainerComponentTable),
+	// v317 -> v318
+	NewMigration("Add require code owner approval to protected branches", v1_23.AddRequireCodeOwnerApprovalToProtectedBranch),
+	// v318 -> v319
+	NewMigration("Add ruleset and ruleset_violation tables", v1_23.AddRulesetTables),
+	// v319 -> v320
+	NewMigration("Add push_rule table", v1_23.AddPushRuleTable),
+	// v320 -> v321
+	NewMigration("Add secret_scanning_alert and secret_scanning_backfill tables", v1_23.AddSecretScanningTables),
+	// v321 -> v322
+	NewMigration("Add require linear history to protected branches", v1_23.AddRequireLinearHistoryToProtectedBranch),
+	// v322 -> v323
+	NewMigration("Add uploaded_size column to action_cache", v1_23.AddUploadedSizeToActionCache),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,18 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import "xorm.io/xorm"
+
+func AddConcurrencyColumnsToActionRunAndJob(x *xorm.Engine) error {
+	type ActionRun struct {
+		ConcurrencyGroup  string `xorm:"index"`
+		ConcurrencyCancel bool
+	}
+	type ActionRunJob struct {
+		ConcurrencyGroup  string `xorm:"index"`
+		ConcurrencyCancel bool
+	}
+	return x.Sync(new(ActionRun), new(ActionRunJob))
+}
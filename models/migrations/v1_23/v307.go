@@ -0,0 +1,13 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import "xorm.io/xorm"
+
+func AddParentJobIDToActionRunJob(x *xorm.Engine) error {
+	type ActionRunJob struct {
+		ParentJobID int64 `xorm:"index"`
+	}
+	return x.Sync(new(ActionRunJob))
+}
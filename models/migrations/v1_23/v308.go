@@ -0,0 +1,67 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddActionEnvironmentTables(x *xorm.Engine) error {
+	type ActionEnvironment struct {
+		ID                 int64              `xorm:"pk autoincr"`
+		RepoID             int64              `xorm:"UNIQUE(repo_name) NOT NULL"`
+		Name               string             `xorm:"UNIQUE(repo_name) NOT NULL"`
+		DeploymentBranches []string           `xorm:"JSON TEXT"`
+		ReviewerIDs        []int64            `xorm:"JSON TEXT"`
+		CreatedUnix        timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix        timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ActionDeployment struct {
+		ID            int64  `xorm:"pk autoincr"`
+		RepoID        int64  `xorm:"index"`
+		EnvironmentID int64  `xorm:"index"`
+		RunID         int64  `xorm:"index"`
+		RunJobID      int64  `xorm:"UNIQUE(job_attempt)"`
+		Attempt       int64  `xorm:"UNIQUE(job_attempt)"`
+		Ref           string `xorm:"index"`
+		CommitSHA     string
+		CreatorID     int64
+		ReviewStatus  int `xorm:"index"`
+		ReviewerID    int64
+		ReviewComment string             `xorm:"TEXT"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ActionRunJob struct {
+		Environment string `xorm:"VARCHAR(255)"`
+	}
+
+	// the full structs are required to add environment_id to the unique indexes
+	type Secret struct {
+		ID            int64
+		OwnerID       int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL"`
+		RepoID        int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		EnvironmentID int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		Name          string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+		Data          string             `xorm:"LONGTEXT"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
+	}
+
+	type ActionVariable struct {
+		ID            int64              `xorm:"pk autoincr"`
+		OwnerID       int64              `xorm:"UNIQUE(owner_repo_name)"`
+		RepoID        int64              `xorm:"INDEX UNIQUE(owner_repo_name)"`
+		EnvironmentID int64              `xorm:"INDEX UNIQUE(owner_repo_name) NOT NULL DEFAULT 0"`
+		Name          string             `xorm:"UNIQUE(owner_repo_name) NOT NULL"`
+		Data          string             `xorm:"LONGTEXT NOT NULL"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ActionEnvironment), new(ActionDeployment), new(ActionRunJob), new(Secret), new(ActionVariable))
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddMergeQueue(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		EnableMergeQueue bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type PullMergeQueue struct {
+		ID            int64              `xorm:"pk autoincr"`
+		RepoID        int64              `xorm:"INDEX(repo_branch)"`
+		BaseBranch    string             `xorm:"VARCHAR(255) INDEX(repo_branch)"`
+		PullID        int64              `xorm:"UNIQUE"`
+		DoerID        int64              `xorm:"NOT NULL"`
+		MergeStyle    string             `xorm:"varchar(30)"`
+		Message       string             `xorm:"LONGTEXT"`
+		HeadCommitID  string             `xorm:"VARCHAR(64)"`
+		BaseCommitID  string             `xorm:"VARCHAR(64)"`
+		GroupCommitID string             `xorm:"VARCHAR(64) INDEX"`
+		CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(ProtectedBranch), new(PullMergeQueue))
+}
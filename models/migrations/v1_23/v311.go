@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddAuditEventTable(x *xorm.Engine) error {
+	type AuditEvent struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Action      string             `xorm:"VARCHAR(64) INDEX NOT NULL"`
+		DoerID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		DoerName    string             `xorm:"VARCHAR(255)"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		TargetType  string             `xorm:"VARCHAR(32)"`
+		TargetID    int64              `xorm:"NOT NULL DEFAULT 0"`
+		TargetName  string             `xorm:"VARCHAR(255)"`
+		Description string             `xorm:"TEXT"`
+		IPAddress   string             `xorm:"VARCHAR(64)"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(AuditEvent))
+}
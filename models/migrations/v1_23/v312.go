@@ -0,0 +1,34 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPackageUpstreamTables(x *xorm.Engine) error {
+	type PackageUpstream struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Enabled     bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+		Type        string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		URL         string             `xorm:"TEXT NOT NULL"`
+		MetadataTTL int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+	}
+
+	type PackageUpstreamCache struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UpstreamID  int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Path        string             `xorm:"VARCHAR(512) UNIQUE(s) NOT NULL"`
+		ContentType string             `xorm:"NOT NULL DEFAULT ''"`
+		Content     []byte             `xorm:"LONGBLOB"`
+		FetchedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PackageUpstream), new(PackageUpstreamCache))
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPackageVirtualRegistryTables(x *xorm.Engine) error {
+	type PackageVirtualRegistry struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Enabled     bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+		OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+		Type        string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+	}
+
+	type PackageVirtualRegistryMember struct {
+		ID         int64 `xorm:"pk autoincr"`
+		RegistryID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		MemberID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Priority   int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PackageVirtualRegistry), new(PackageVirtualRegistryMember))
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddTerraformStateTables(x *xorm.Engine) error {
+	type TerraformState struct {
+		ID              int64  `xorm:"pk autoincr"`
+		RepoID          int64  `xorm:"UNIQUE(repo_name)"`
+		Name            string `xorm:"VARCHAR(255) UNIQUE(repo_name)"`
+		LatestVersionID int64
+		Serial          int64
+		Lineage         string `xorm:"VARCHAR(255)"`
+		Size            int64
+		LockID          string `xorm:"VARCHAR(255)"`
+		LockInfo        string `xorm:"TEXT"`
+		LockerID        int64
+		LockedUnix      timeutil.TimeStamp
+		CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type TerraformStateVersion struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX"`
+		StateID     int64  `xorm:"UNIQUE(state_serial)"`
+		Serial      int64  `xorm:"UNIQUE(state_serial)"`
+		Lineage     string `xorm:"VARCHAR(255)"`
+		Size        int64
+		CreatorID   int64
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(TerraformState), new(TerraformStateVersion))
+}
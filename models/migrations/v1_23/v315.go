@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+type quotaRule struct {
+	ID       int64    `xorm:"pk autoincr"`
+	Name     string   `xorm:"UNIQUE NOT NULL"`
+	Limit    int64    `xorm:"NOT NULL DEFAULT -1"`
+	Subjects []string `xorm:"JSON TEXT"`
+}
+
+func (quotaRule) TableName() string {
+	return "quota_rule"
+}
+
+type quotaGroup struct {
+	ID   int64  `xorm:"pk autoincr"`
+	Name string `xorm:"UNIQUE NOT NULL"`
+}
+
+func (quotaGroup) TableName() string {
+	return "quota_group"
+}
+
+type quotaGroupRuleMapping struct {
+	ID      int64 `xorm:"pk autoincr"`
+	GroupID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RuleID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+}
+
+func (quotaGroupRuleMapping) TableName() string {
+	return "quota_group_rule_mapping"
+}
+
+type quotaGroupMapping struct {
+	ID      int64 `xorm:"pk autoincr"`
+	GroupID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	UserID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+}
+
+func (quotaGroupMapping) TableName() string {
+	return "quota_group_mapping"
+}
+
+func AddQuotaTables(x *xorm.Engine) error {
+	return x.Sync(new(quotaRule), new(quotaGroup), new(quotaGroupRuleMapping), new(quotaGroupMapping))
+}
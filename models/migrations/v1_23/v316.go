@@ -0,0 +1,26 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddPackageContainerComponentTable(x *xorm.Engine) error {
+	type PackageContainerComponent struct {
+		ID        int64  `xorm:"pk autoincr"`
+		OwnerID   int64  `xorm:"INDEX NOT NULL"`
+		PackageID int64  `xorm:"INDEX NOT NULL"`
+		VersionID int64  `xorm:"INDEX NOT NULL"`
+		Subject   string `xorm:"INDEX NOT NULL"`
+		Name      string `xorm:"NOT NULL"`
+		LowerName string `xorm:"INDEX NOT NULL"`
+		Version   string `xorm:"INDEX NOT NULL DEFAULT ''"`
+		Type      string `xorm:"NOT NULL DEFAULT ''"`
+		PURL      string `xorm:"'purl' TEXT"`
+		License   string `xorm:"TEXT"`
+	}
+
+	return x.Sync(new(PackageContainerComponent))
+}
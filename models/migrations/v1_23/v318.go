@@ -0,0 +1,57 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRulesetTables(x *xorm.Engine) error {
+	type Ruleset struct {
+		ID          int64  `xorm:"pk autoincr"`
+		OwnerID     int64  `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Name        string `xorm:"NOT NULL"`
+		Target      string `xorm:"VARCHAR(16) NOT NULL"`
+		Enforcement int    `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+		RepoNamePatterns []string `xorm:"JSON TEXT"`
+		RepoTopics       []string `xorm:"JSON TEXT"`
+		RefPatterns      []string `xorm:"JSON TEXT"`
+
+		BypassUserIDs []int64 `xorm:"JSON TEXT"`
+		BypassTeamIDs []int64 `xorm:"JSON TEXT"`
+
+		RestrictCreation      bool   `xorm:"NOT NULL DEFAULT false"`
+		RestrictUpdate        bool   `xorm:"NOT NULL DEFAULT false"`
+		RestrictDeletion      bool   `xorm:"NOT NULL DEFAULT false"`
+		BlockForcePush        bool   `xorm:"NOT NULL DEFAULT false"`
+		RequireSignedCommits  bool   `xorm:"NOT NULL DEFAULT false"`
+		ProtectedFilePatterns string `xorm:"TEXT"`
+
+		RequiredApprovals             int64    `xorm:"NOT NULL DEFAULT 0"`
+		BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
+		BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
+		BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+		RequireCodeOwnerApproval      bool     `xorm:"NOT NULL DEFAULT false"`
+		EnableStatusCheck             bool     `xorm:"NOT NULL DEFAULT false"`
+		StatusCheckContexts           []string `xorm:"JSON TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type RulesetViolation struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RulesetID   int64              `xorm:"INDEX NOT NULL"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		RefName     string             `xorm:"VARCHAR(255)"`
+		DoerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		Reason      string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(Ruleset), new(RulesetViolation))
+}
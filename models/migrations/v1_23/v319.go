@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPushRuleTable(x *xorm.Engine) error {
+	type PushRule struct {
+		ID      int64 `xorm:"pk autoincr"`
+		OwnerID int64 `xorm:"UNIQUE(owner_repo) NOT NULL DEFAULT 0"`
+		RepoID  int64 `xorm:"UNIQUE(owner_repo) NOT NULL DEFAULT 0"`
+
+		CommitMessagePattern          string `xorm:"TEXT"`
+		RequireVerifiedAuthorEmail    bool   `xorm:"NOT NULL DEFAULT false"`
+		RequireVerifiedCommitterEmail bool   `xorm:"NOT NULL DEFAULT false"`
+		MaxFileSize                   int64  `xorm:"NOT NULL DEFAULT 0"`
+		ForbiddenFilePatterns         string `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(PushRule))
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_23 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddSecretScanningTables(x *xorm.Engine) error {
+	type SecretScanningAlert struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"UNIQUE(repo_secret) NOT NULL"`
+		PatternID      string `xorm:"VARCHAR(64) UNIQUE(repo_secret) NOT NULL"`
+		PatternName    string `xorm:"VARCHAR(255)"`
+		SecretHash     string `xorm:"VARCHAR(64) UNIQUE(repo_secret) NOT NULL"`
+		RedactedSecret string `xorm:"VARCHAR(255)"`
+		CommitID       string `xorm:"VARCHAR(64)"`
+		Path           string `xorm:"TEXT"`
+		Line           int    `xorm:"NOT NULL DEFAULT 0"`
+		State          string `xorm:"VARCHAR(16) INDEX NOT NULL"`
+
+		Resolution        string `xorm:"VARCHAR(32)"`
+		ResolutionComment string `xorm:"TEXT"`
+		ResolverID        int64  `xorm:"NOT NULL DEFAULT 0"`
+		ResolvedUnix      timeutil.TimeStamp
+
+		PushProtectionBypassed bool   `xorm:"NOT NULL DEFAULT false"`
+		BypassReason           string `xorm:"TEXT"`
+		BypasserID             int64  `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type SecretScanningBackfill struct {
+		RepoID      int64              `xorm:"pk"`
+		ScannedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(SecretScanningAlert), new(SecretScanningBackfill))
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(Component))
+}
+
+// Component is a software component listed in a SBOM attached to a container image
+type Component struct {
+	ID        int64  `xorm:"pk autoincr"`
+	OwnerID   int64  `xorm:"INDEX NOT NULL"`
+	PackageID int64  `xorm:"INDEX NOT NULL"`
+	VersionID int64  `xorm:"INDEX NOT NULL"` // the version which provided the SBOM
+	Subject   string `xorm:"INDEX NOT NULL"` // digest of the manifest described by the SBOM
+	Name      string `xorm:"NOT NULL"`
+	LowerName string `xorm:"INDEX NOT NULL"`
+	Version   string `xorm:"INDEX NOT NULL DEFAULT ''"`
+	Type      string `xorm:"NOT NULL DEFAULT ''"`
+	PURL      string `xorm:"'purl' TEXT"`
+	License   string `xorm:"TEXT"`
+}
+
+// TableName returns the table name of the component
+func (*Component) TableName() string {
+	return "package_container_component"
+}
+
+// InsertComponents inserts the components
+func InsertComponents(ctx context.Context, components []*Component) error {
+	if len(components) == 0 {
+		return nil
+	}
+	for _, c := range components {
+		c.LowerName = strings.ToLower(c.Name)
+	}
+	return db.Insert(ctx, components)
+}
+
+// DeleteComponentsByVersionID deletes all components provided by the version
+func DeleteComponentsByVersionID(ctx context.Context, versionID int64) error {
+	_, err := db.GetEngine(ctx).Where("version_id = ?", versionID).Delete(&Component{})
+	return err
+}
+
+// UpdateComponentsSubject changes the subject of the components provided by the manifest with the old subject digest
+func UpdateComponentsSubject(ctx context.Context, packageID int64, oldSubject, newSubject string) error {
+	_, err := db.GetEngine(ctx).
+		Where("package_id = ? AND subject = ?", packageID, oldSubject).
+		Cols("subject").
+		Update(&Component{Subject: newSubject})
+	return err
+}
+
+// providerCond limits the components to a single providing version per subject.
+// The same manifest may be stored as multiple versions (tag and digest) which provide the same components.
+func providerCond(cond builder.Cond) builder.Cond {
+	return builder.In(
+		"version_id",
+		builder.Select("min(version_id)").From("package_container_component").Where(cond).GroupBy("package_id, subject"),
+	)
+}
+
+// GetComponentsBySubjects gets the components describing the manifests with the subject digests
+func GetComponentsBySubjects(ctx context.Context, packageID int64, subjects []string) ([]*Component, error) {
+	components := make([]*Component, 0, 10)
+	if len(subjects) == 0 {
+		return components, nil
+	}
+
+	cond := builder.Eq{"package_id": packageID}.And(builder.In("subject", subjects))
+	return components, db.GetEngine(ctx).
+		Where(cond.And(providerCond(cond))).
+		OrderBy("lower_name ASC, version ASC").
+		Find(&components)
+}
+
+// ComponentSearchOptions are options for SearchComponents
+type ComponentSearchOptions struct {
+	db.Paginator
+	OwnerID int64
+	Name    string // exact name of the component, case insensitive
+	Version string // exact version of the component
+}
+
+func (opts *ComponentSearchOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OwnerID != 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	if opts.Name != "" {
+		cond = cond.And(builder.Eq{"lower_name": strings.ToLower(opts.Name)})
+	}
+	if opts.Version != "" {
+		cond = cond.And(builder.Eq{"version": opts.Version})
+	}
+	return cond
+}
+
+// SearchComponents gets the components matching the search options
+func SearchComponents(ctx context.Context, opts *ComponentSearchOptions) ([]*Component, int64, error) {
+	cond := opts.toConds()
+
+	sess := db.GetEngine(ctx).
+		Where(cond.And(providerCond(cond))).
+		OrderBy("lower_name ASC, version ASC, package_id ASC, id ASC")
+
+	if opts.Paginator != nil {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+
+	components := make([]*Component, 0, 10)
+	count, err := sess.FindAndCount(&components)
+	return components, count, err
+}
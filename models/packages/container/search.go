@@ -26,6 +26,7 @@ type BlobSearchOptions struct {
 	Tag        string
 	IsManifest bool
 	Repository string
+	Subject    string // digest of the manifest the searched manifests refer to
 }
 
 func (opts *BlobSearchOptions) toConds() builder.Cond {
@@ -63,6 +64,15 @@ func (opts *BlobSearchOptions) toConds() builder.Cond {
 
 		cond = cond.And(builder.In("package.id", builder.Select("package_property.ref_id").Where(propsCond).From("package_property")))
 	}
+	if opts.Subject != "" {
+		var propsCond builder.Cond = builder.Eq{
+			"package_property.ref_type": packages.PropertyTypeVersion,
+			"package_property.name":     container_module.PropertyManifestSubject,
+			"package_property.value":    opts.Subject,
+		}
+
+		cond = cond.And(builder.In("package_version.id", builder.Select("package_property.ref_id").Where(propsCond).From("package_property")))
+	}
 
 	return cond
 }
@@ -246,6 +256,41 @@ func SearchExpiredUploadedBlobs(ctx context.Context, olderThan time.Duration) ([
 		Find(&pfs)
 }
 
+// SearchDanglingReferrers gets all manifests older than specified which refer to a subject manifest not existing in the image
+func SearchDanglingReferrers(ctx context.Context, olderThan time.Duration) ([]*packages.PackageVersion, error) {
+	var cond builder.Cond = builder.Eq{
+		"package.type":                packages.TypeContainer,
+		"package_version.is_internal": false,
+		"package_property.ref_type":   packages.PropertyTypeVersion,
+		"package_property.name":       container_module.PropertyManifestSubject,
+	}
+	cond = cond.And(builder.Lt{"package_version.created_unix": time.Now().Add(-olderThan).Unix()})
+
+	subjectCond := builder.Expr("pv.package_id = package.id").
+		And(builder.Expr("pp.value = package_property.value")).
+		And(builder.Eq{
+			"pf.lower_name": ManifestFilename,
+			"pp.ref_type":   packages.PropertyTypeFile,
+			"pp.name":       container_module.PropertyDigest,
+		})
+
+	cond = cond.And(builder.NotExists(
+		builder.
+			Select("pf.id").
+			From("package_file", "pf").
+			Join("INNER", "package_version pv", "pv.id = pf.version_id").
+			Join("INNER", "package_property pp", "pp.ref_id = pf.id").
+			Where(subjectCond),
+	))
+
+	pvs := make([]*packages.PackageVersion, 0, 10)
+	return pvs, db.GetEngine(ctx).
+		Join("INNER", "package", "package.id = package_version.package_id").
+		Join("INNER", "package_property", "package_property.ref_id = package_version.id").
+		Where(cond).
+		Find(&pvs)
+}
+
 // GetRepositories gets a sorted list of all repositories
 func GetRepositories(ctx context.Context, actor *user_model.User, n int, last string) ([]string, error) {
 	var cond builder.Cond = builder.Eq{
@@ -26,6 +26,9 @@ const (
 	PropertyTypePackage // 2
 )
 
+// PropertyAttestation is the name of the file properties which contain the attestations of the file, a file can have several of them
+const PropertyAttestation = "package.attestation"
+
 // PackageProperty represents a property of a package, version or file
 type PackageProperty struct {
 	ID      int64        `xorm:"pk autoincr"`
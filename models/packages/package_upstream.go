@@ -0,0 +1,163 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+var ErrPackageUpstreamNotExist = util.NewNotExistErrorf("package upstream does not exist")
+
+func init() {
+	db.RegisterModel(new(PackageUpstream))
+	db.RegisterModel(new(PackageUpstreamCache))
+}
+
+// UpstreamTypeList contains the package types which can be proxied from a remote upstream
+var UpstreamTypeList = []Type{
+	TypeMaven,
+	TypeNpm,
+	TypePyPI,
+}
+
+// IsUpstreamType checks if packages of the type can be proxied from a remote upstream
+func IsUpstreamType(t Type) bool {
+	for _, ut := range UpstreamTypeList {
+		if ut == t {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageUpstream represents a remote registry which is used to fetch packages not available locally
+type PackageUpstream struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Enabled     bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+	Type        Type               `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	URL         string             `xorm:"TEXT NOT NULL"`
+	MetadataTTL int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+}
+
+// MetadataTTLDuration returns the time cached upstream metadata is considered fresh
+func (pu *PackageUpstream) MetadataTTLDuration() time.Duration {
+	return time.Duration(pu.MetadataTTL) * time.Second
+}
+
+func InsertUpstream(ctx context.Context, pu *PackageUpstream) (*PackageUpstream, error) {
+	return pu, db.Insert(ctx, pu)
+}
+
+func GetUpstreamByID(ctx context.Context, id int64) (*PackageUpstream, error) {
+	pu := &PackageUpstream{}
+
+	has, err := db.GetEngine(ctx).ID(id).Get(pu)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageUpstreamNotExist
+	}
+	return pu, nil
+}
+
+func GetUpstreamByOwnerAndType(ctx context.Context, ownerID int64, packageType Type) (*PackageUpstream, error) {
+	pu := &PackageUpstream{}
+
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND type = ?", ownerID, packageType).Get(pu)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageUpstreamNotExist
+	}
+	return pu, nil
+}
+
+func UpdateUpstream(ctx context.Context, pu *PackageUpstream) error {
+	_, err := db.GetEngine(ctx).ID(pu.ID).AllCols().Update(pu)
+	return err
+}
+
+func GetUpstreamsByOwner(ctx context.Context, ownerID int64) ([]*PackageUpstream, error) {
+	pus := make([]*PackageUpstream, 0, 10)
+	return pus, db.GetEngine(ctx).Where("owner_id = ?", ownerID).Find(&pus)
+}
+
+// DeleteUpstreamByID deletes the upstream and its cached metadata
+func DeleteUpstreamByID(ctx context.Context, upstreamID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := DeleteUpstreamCacheByUpstreamID(ctx, upstreamID); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(upstreamID).Delete(&PackageUpstream{})
+		return err
+	})
+}
+
+func HasOwnerUpstreamForPackageType(ctx context.Context, ownerID int64, packageType Type) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("owner_id = ? AND type = ?", ownerID, packageType).
+		Exist(&PackageUpstream{})
+}
+
+// PackageUpstreamCache represents a metadata document fetched from an upstream
+type PackageUpstreamCache struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UpstreamID  int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Path        string             `xorm:"VARCHAR(512) UNIQUE(s) NOT NULL"`
+	ContentType string             `xorm:"NOT NULL DEFAULT ''"`
+	Content     []byte             `xorm:"LONGBLOB"`
+	FetchedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+// IsFresh checks if the cached metadata is younger than the ttl
+func (puc *PackageUpstreamCache) IsFresh(ttl time.Duration) bool {
+	return puc.FetchedUnix.AsTime().Add(ttl).After(time.Now())
+}
+
+func GetUpstreamCache(ctx context.Context, upstreamID int64, path string) (*PackageUpstreamCache, error) {
+	puc := &PackageUpstreamCache{}
+
+	has, err := db.GetEngine(ctx).Where("upstream_id = ? AND path = ?", upstreamID, path).Get(puc)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, util.ErrNotExist
+	}
+	return puc, nil
+}
+
+// UpsertUpstreamCache inserts or replaces the cached metadata of the path
+func UpsertUpstreamCache(ctx context.Context, puc *PackageUpstreamCache) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetUpstreamCache(ctx, puc.UpstreamID, puc.Path)
+		if err != nil && err != util.ErrNotExist {
+			return err
+		}
+		if existing == nil {
+			_, err := db.GetEngine(ctx).Insert(puc)
+			return err
+		}
+		puc.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(puc.ID).Cols("content_type", "content", "fetched_unix").Update(puc)
+		return err
+	})
+}
+
+func DeleteUpstreamCacheByUpstreamID(ctx context.Context, upstreamID int64) error {
+	_, err := db.GetEngine(ctx).Where(builder.Eq{"upstream_id": upstreamID}).Delete(&PackageUpstreamCache{})
+	return err
+}
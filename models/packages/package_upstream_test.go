@@ -0,0 +1,71 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageUpstream(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	pu, err := packages_model.InsertUpstream(db.DefaultContext, &packages_model.PackageUpstream{
+		Enabled:     true,
+		OwnerID:     2,
+		Type:        packages_model.TypeNpm,
+		URL:         "https://registry.example.com",
+		MetadataTTL: 60,
+	})
+	assert.NoError(t, err)
+
+	has, err := packages_model.HasOwnerUpstreamForPackageType(db.DefaultContext, 2, packages_model.TypeNpm)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = packages_model.HasOwnerUpstreamForPackageType(db.DefaultContext, 2, packages_model.TypeMaven)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	_, err = packages_model.GetUpstreamCache(db.DefaultContext, pu.ID, "test")
+	assert.ErrorIs(t, err, util.ErrNotExist)
+
+	assert.NoError(t, packages_model.UpsertUpstreamCache(db.DefaultContext, &packages_model.PackageUpstreamCache{
+		UpstreamID:  pu.ID,
+		Path:        "test",
+		Content:     []byte("old"),
+		FetchedUnix: timeutil.TimeStampNow().AddDuration(-time.Hour),
+	}))
+
+	puc, err := packages_model.GetUpstreamCache(db.DefaultContext, pu.ID, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "old", string(puc.Content))
+	assert.False(t, puc.IsFresh(pu.MetadataTTLDuration()))
+
+	assert.NoError(t, packages_model.UpsertUpstreamCache(db.DefaultContext, &packages_model.PackageUpstreamCache{
+		UpstreamID:  pu.ID,
+		Path:        "test",
+		Content:     []byte("new"),
+		FetchedUnix: timeutil.TimeStampNow(),
+	}))
+
+	puc, err = packages_model.GetUpstreamCache(db.DefaultContext, pu.ID, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, "new", string(puc.Content))
+	assert.True(t, puc.IsFresh(pu.MetadataTTLDuration()))
+	unittest.AssertCount(t, &packages_model.PackageUpstreamCache{UpstreamID: pu.ID}, 1)
+
+	assert.NoError(t, packages_model.DeleteUpstreamByID(db.DefaultContext, pu.ID))
+
+	_, err = packages_model.GetUpstreamByOwnerAndType(db.DefaultContext, 2, packages_model.TypeNpm)
+	assert.ErrorIs(t, err, packages_model.ErrPackageUpstreamNotExist)
+	unittest.AssertCount(t, &packages_model.PackageUpstreamCache{UpstreamID: pu.ID}, 0)
+}
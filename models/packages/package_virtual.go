@@ -0,0 +1,167 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+var ErrPackageVirtualRegistryNotExist = util.NewNotExistErrorf("package virtual registry does not exist")
+
+func init() {
+	db.RegisterModel(new(PackageVirtualRegistry))
+	db.RegisterModel(new(PackageVirtualRegistryMember))
+}
+
+// VirtualTypeList contains the package types whose indexes can be merged by a virtual registry
+// Debian, Alpine and RPM are missing because their repository indexes are built and signed with the key of a single owner
+var VirtualTypeList = []Type{
+	TypeMaven,
+	TypeNpm,
+	TypePyPI,
+}
+
+// IsVirtualType checks if packages of the type can be served by a virtual registry
+func IsVirtualType(t Type) bool {
+	for _, vt := range VirtualTypeList {
+		if vt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// PackageVirtualRegistry merges the packages of several owners into the registry of its owner
+type PackageVirtualRegistry struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Enabled     bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+	OwnerID     int64              `xorm:"UNIQUE(s) INDEX NOT NULL DEFAULT 0"`
+	Type        Type               `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+}
+
+// PackageVirtualRegistryMember is an owner whose packages are served by a virtual registry.
+// Members with a lower priority value are preferred.
+type PackageVirtualRegistryMember struct {
+	ID         int64 `xorm:"pk autoincr"`
+	RegistryID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	MemberID   int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Priority   int   `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// InsertVirtualRegistry inserts a virtual registry, the indexes of the package type must be mergeable
+func InsertVirtualRegistry(ctx context.Context, pvr *PackageVirtualRegistry) (*PackageVirtualRegistry, error) {
+	if !IsVirtualType(pvr.Type) {
+		return nil, util.NewInvalidArgumentErrorf("virtual registries are not supported for %s packages", pvr.Type)
+	}
+	return pvr, db.Insert(ctx, pvr)
+}
+
+func GetVirtualRegistryByID(ctx context.Context, id int64) (*PackageVirtualRegistry, error) {
+	pvr := &PackageVirtualRegistry{}
+
+	has, err := db.GetEngine(ctx).ID(id).Get(pvr)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageVirtualRegistryNotExist
+	}
+	return pvr, nil
+}
+
+func GetVirtualRegistryByOwnerAndType(ctx context.Context, ownerID int64, packageType Type) (*PackageVirtualRegistry, error) {
+	pvr := &PackageVirtualRegistry{}
+
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND type = ?", ownerID, packageType).Get(pvr)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageVirtualRegistryNotExist
+	}
+	return pvr, nil
+}
+
+func UpdateVirtualRegistry(ctx context.Context, pvr *PackageVirtualRegistry) error {
+	_, err := db.GetEngine(ctx).ID(pvr.ID).AllCols().Update(pvr)
+	return err
+}
+
+func GetVirtualRegistriesByOwner(ctx context.Context, ownerID int64) ([]*PackageVirtualRegistry, error) {
+	pvrs := make([]*PackageVirtualRegistry, 0, 10)
+	return pvrs, db.GetEngine(ctx).Where("owner_id = ?", ownerID).Find(&pvrs)
+}
+
+// DeleteVirtualRegistryByID deletes the virtual registry and its members
+func DeleteVirtualRegistryByID(ctx context.Context, registryID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where(builder.Eq{"registry_id": registryID}).Delete(&PackageVirtualRegistryMember{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(registryID).Delete(&PackageVirtualRegistry{})
+		return err
+	})
+}
+
+func HasOwnerVirtualRegistryForPackageType(ctx context.Context, ownerID int64, packageType Type) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("owner_id = ? AND type = ?", ownerID, packageType).
+		Exist(&PackageVirtualRegistry{})
+}
+
+// GetVirtualRegistryMemberIDs returns the owner ids of the members ordered by priority
+func GetVirtualRegistryMemberIDs(ctx context.Context, registryID int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, db.GetEngine(ctx).
+		Table("package_virtual_registry_member").
+		Where("registry_id = ?", registryID).
+		OrderBy("priority ASC, id ASC").
+		Cols("member_id").
+		Find(&ids)
+}
+
+// SetVirtualRegistryMembers replaces the members of the virtual registry. The order of the ids defines their priority.
+func SetVirtualRegistryMembers(ctx context.Context, registryID int64, memberIDs []int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where(builder.Eq{"registry_id": registryID}).Delete(&PackageVirtualRegistryMember{}); err != nil {
+			return err
+		}
+		for i, memberID := range memberIDs {
+			if err := db.Insert(ctx, &PackageVirtualRegistryMember{
+				RegistryID: registryID,
+				MemberID:   memberID,
+				Priority:   i,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// HasVirtualRegistryMemberPackage checks if a member of an enabled virtual registry of the owner contains the package
+func HasVirtualRegistryMemberPackage(ctx context.Context, ownerID int64, packageType Type, name string) (bool, error) {
+	return db.GetEngine(ctx).
+		Table("package").
+		Join("INNER", "package_virtual_registry_member", "package_virtual_registry_member.member_id = package.owner_id").
+		Join("INNER", "package_virtual_registry", "package_virtual_registry.id = package_virtual_registry_member.registry_id").
+		Where(builder.Eq{
+			"package_virtual_registry.owner_id": ownerID,
+			"package_virtual_registry.type":     packageType,
+			"package_virtual_registry.enabled":  true,
+			"package.type":                      packageType,
+			"package.lower_name":                strings.ToLower(name),
+			"package.is_internal":               false,
+		}).
+		Exist()
+}
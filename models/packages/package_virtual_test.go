@@ -0,0 +1,83 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageVirtualRegistry(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	pvr, err := packages_model.InsertVirtualRegistry(db.DefaultContext, &packages_model.PackageVirtualRegistry{
+		Enabled: true,
+		OwnerID: 2,
+		Type:    packages_model.TypeNpm,
+	})
+	assert.NoError(t, err)
+
+	has, err := packages_model.HasOwnerVirtualRegistryForPackageType(db.DefaultContext, 2, packages_model.TypeNpm)
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = packages_model.HasOwnerVirtualRegistryForPackageType(db.DefaultContext, 2, packages_model.TypeMaven)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	assert.NoError(t, packages_model.SetVirtualRegistryMembers(db.DefaultContext, pvr.ID, []int64{5, 3, 4}))
+	ids, err := packages_model.GetVirtualRegistryMemberIDs(db.DefaultContext, pvr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5, 3, 4}, ids)
+
+	assert.NoError(t, packages_model.SetVirtualRegistryMembers(db.DefaultContext, pvr.ID, []int64{4, 5}))
+	ids, err = packages_model.GetVirtualRegistryMemberIDs(db.DefaultContext, pvr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{4, 5}, ids)
+
+	p, err := packages_model.TryInsertPackage(db.DefaultContext, &packages_model.Package{
+		OwnerID:   5,
+		LowerName: "virtual-package",
+		Name:      "virtual-package",
+		Type:      packages_model.TypeNpm,
+	})
+	assert.NoError(t, err)
+
+	has, err = packages_model.HasVirtualRegistryMemberPackage(db.DefaultContext, 2, packages_model.TypeNpm, "Virtual-Package")
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = packages_model.HasVirtualRegistryMemberPackage(db.DefaultContext, 2, packages_model.TypeNpm, "other-package")
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	pvr.Enabled = false
+	assert.NoError(t, packages_model.UpdateVirtualRegistry(db.DefaultContext, pvr))
+	has, err = packages_model.HasVirtualRegistryMemberPackage(db.DefaultContext, 2, packages_model.TypeNpm, p.Name)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	assert.NoError(t, packages_model.DeleteVirtualRegistryByID(db.DefaultContext, pvr.ID))
+	_, err = packages_model.GetVirtualRegistryByID(db.DefaultContext, pvr.ID)
+	assert.ErrorIs(t, err, packages_model.ErrPackageVirtualRegistryNotExist)
+	ids, err = packages_model.GetVirtualRegistryMemberIDs(db.DefaultContext, pvr.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	for _, pt := range []packages_model.Type{packages_model.TypeDebian, packages_model.TypeAlpine, packages_model.TypeRpm} {
+		_, err = packages_model.InsertVirtualRegistry(db.DefaultContext, &packages_model.PackageVirtualRegistry{
+			Enabled: true,
+			OwnerID: 2,
+			Type:    pt,
+		})
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+		has, err = packages_model.HasOwnerVirtualRegistryForPackageType(db.DefaultContext, 2, pt)
+		assert.NoError(t, err)
+		assert.False(t, has)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MergeQueueEntry represents a pull request waiting in the merge queue of its base branch.
+// The pull requests of a branch are merged in the order of their IDs, every entry records
+// the temporary merge group commit which combines the base branch with the entry and all the entries before it.
+type MergeQueueEntry struct {
+	ID            int64                 `xorm:"pk autoincr"`
+	RepoID        int64                 `xorm:"INDEX(repo_branch)"`
+	BaseBranch    string                `xorm:"VARCHAR(255) INDEX(repo_branch)"`
+	PullID        int64                 `xorm:"UNIQUE"`
+	DoerID        int64                 `xorm:"NOT NULL"`
+	Doer          *user_model.User      `xorm:"-"`
+	MergeStyle    repo_model.MergeStyle `xorm:"varchar(30)"`
+	Message       string                `xorm:"LONGTEXT"`
+	HeadCommitID  string                `xorm:"VARCHAR(64)"`       // the head commit of the pull request when it was queued
+	BaseCommitID  string                `xorm:"VARCHAR(64)"`       // the commit the merge group was built on, the base branch or the previous merge group
+	GroupCommitID string                `xorm:"VARCHAR(64) INDEX"` // the merge group commit, empty if it has not been built yet
+	CreatedUnix   timeutil.TimeStamp    `xorm:"created"`
+}
+
+// TableName return database table name for xorm
+func (MergeQueueEntry) TableName() string {
+	return "pull_merge_queue"
+}
+
+func init() {
+	db.RegisterModel(new(MergeQueueEntry))
+}
+
+// LoadDoer loads the user who added the pull request to the merge queue
+func (e *MergeQueueEntry) LoadDoer(ctx context.Context) (err error) {
+	if e.Doer != nil {
+		return nil
+	}
+	e.Doer, err = user_model.GetUserByID(ctx, e.DoerID)
+	return err
+}
+
+// ErrAlreadyInMergeQueue represents a "PullRequestAlreadyInMergeQueue"-error
+type ErrAlreadyInMergeQueue struct {
+	PullID int64
+}
+
+func (err ErrAlreadyInMergeQueue) Error() string {
+	return fmt.Sprintf("pull request is already in the merge queue [pull_id: %d]", err.PullID)
+}
+
+// IsErrAlreadyInMergeQueue checks if an error is a ErrAlreadyInMergeQueue.
+func IsErrAlreadyInMergeQueue(err error) bool {
+	_, ok := err.(ErrAlreadyInMergeQueue)
+	return ok
+}
+
+// AddToMergeQueue adds a pull request to the end of the merge queue of its base branch
+func AddToMergeQueue(ctx context.Context, doer *user_model.User, repoID int64, baseBranch string, pullID int64, style repo_model.MergeStyle, message, headCommitID string) (*MergeQueueEntry, error) {
+	if exists, _, err := GetMergeQueueEntryByPullID(ctx, pullID); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, ErrAlreadyInMergeQueue{PullID: pullID}
+	}
+
+	entry := &MergeQueueEntry{
+		RepoID:       repoID,
+		BaseBranch:   baseBranch,
+		PullID:       pullID,
+		DoerID:       doer.ID,
+		Doer:         doer,
+		MergeStyle:   style,
+		Message:      message,
+		HeadCommitID: headCommitID,
+	}
+	if _, err := db.GetEngine(ctx).Insert(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetMergeQueueEntryByPullID gets the merge queue entry of a pull request
+func GetMergeQueueEntryByPullID(ctx context.Context, pullID int64) (bool, *MergeQueueEntry, error) {
+	entry := &MergeQueueEntry{}
+	exists, err := db.GetEngine(ctx).Where("pull_id = ?", pullID).Get(entry)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+	return true, entry, nil
+}
+
+// GetMergeQueueEntries returns the entries of the merge queue of a branch in the order they will be merged
+func GetMergeQueueEntries(ctx context.Context, repoID int64, baseBranch string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 5)
+	return entries, db.GetEngine(ctx).
+		Where("repo_id = ? AND base_branch = ?", repoID, baseBranch).
+		OrderBy("id ASC").
+		Find(&entries)
+}
+
+// GetMergeQueueEntriesByGroupCommitID returns the merge queue entries whose merge group is the given commit
+func GetMergeQueueEntriesByGroupCommitID(ctx context.Context, repoID int64, groupCommitID string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 1)
+	return entries, db.GetEngine(ctx).
+		Where("repo_id = ? AND group_commit_id = ?", repoID, groupCommitID).
+		Find(&entries)
+}
+
+// UpdateMergeQueueEntryGroupCommit updates the merge group commit of a merge queue entry
+func UpdateMergeQueueEntryGroupCommit(ctx context.Context, entry *MergeQueueEntry) error {
+	_, err := db.GetEngine(ctx).ID(entry.ID).Cols("base_commit_id", "group_commit_id").Update(entry)
+	return err
+}
+
+// RemoveFromMergeQueue removes a pull request from the merge queue
+func RemoveFromMergeQueue(ctx context.Context, pullID int64) error {
+	exist, entry, err := GetMergeQueueEntryByPullID(ctx, pullID)
+	if err != nil {
+		return err
+	} else if !exist {
+		return db.ErrNotExist{Resource: "merge_queue", ID: pullID}
+	}
+
+	_, err = db.GetEngine(ctx).ID(entry.ID).Delete(&MergeQueueEntry{})
+	return err
+}
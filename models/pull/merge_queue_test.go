@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeQueue(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	first, err := pull_model.AddToMergeQueue(db.DefaultContext, doer, 1, "master", 1, repo_model.MergeStyleMerge, "first", "sha1")
+	assert.NoError(t, err)
+	_, err = pull_model.AddToMergeQueue(db.DefaultContext, doer, 1, "master", 2, repo_model.MergeStyleSquash, "second", "sha2")
+	assert.NoError(t, err)
+
+	_, err = pull_model.AddToMergeQueue(db.DefaultContext, doer, 1, "master", 1, repo_model.MergeStyleMerge, "again", "sha1")
+	assert.True(t, pull_model.IsErrAlreadyInMergeQueue(err))
+
+	entries, err := pull_model.GetMergeQueueEntries(db.DefaultContext, 1, "master")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.EqualValues(t, 1, entries[0].PullID)
+		assert.EqualValues(t, 2, entries[1].PullID)
+	}
+
+	first.BaseCommitID = "base"
+	first.GroupCommitID = "group1"
+	assert.NoError(t, pull_model.UpdateMergeQueueEntryGroupCommit(db.DefaultContext, first))
+
+	entries, err = pull_model.GetMergeQueueEntriesByGroupCommitID(db.DefaultContext, 1, "group1")
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 1) {
+		assert.EqualValues(t, 1, entries[0].PullID)
+		assert.Equal(t, "base", entries[0].BaseCommitID)
+	}
+
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	assert.Equal(t, repo_model.MergeStyleSquash, entry.MergeStyle)
+	assert.Equal(t, "sha2", entry.HeadCommitID)
+
+	assert.NoError(t, pull_model.RemoveFromMergeQueue(db.DefaultContext, 1))
+	assert.True(t, db.IsErrNotExist(pull_model.RemoveFromMergeQueue(db.DefaultContext, 1)))
+
+	exist, _, err = pull_model.GetMergeQueueEntryByPullID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+}
@@ -0,0 +1,306 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	ErrGroupNotExist            = util.NewNotExistErrorf("quota group does not exist")
+	ErrGroupAlreadyExist        = util.NewAlreadyExistErrorf("quota group already exists")
+	ErrRuleAlreadyInGroup       = util.NewAlreadyExistErrorf("quota rule is already part of the group")
+	ErrRuleNotInGroup           = util.NewNotExistErrorf("quota rule is not part of the group")
+	ErrUserAlreadyInGroup       = util.NewAlreadyExistErrorf("user is already part of the quota group")
+	ErrUserNotInGroup           = util.NewNotExistErrorf("user is not part of the quota group")
+	errGroupMappingQueryInvalid = util.NewInvalidArgumentErrorf("invalid quota group mapping")
+)
+
+func init() {
+	db.RegisterModel(new(Group))
+	db.RegisterModel(new(GroupRuleMapping))
+	db.RegisterModel(new(GroupMapping))
+}
+
+// Group is a named set of quota rules which can be assigned to users and organizations
+type Group struct {
+	ID    int64   `xorm:"pk autoincr"`
+	Name  string  `xorm:"UNIQUE NOT NULL"`
+	Rules []*Rule `xorm:"-"`
+}
+
+// TableName sets the table name
+func (*Group) TableName() string {
+	return "quota_group"
+}
+
+// GroupRuleMapping assigns a rule to a group
+type GroupRuleMapping struct {
+	ID      int64 `xorm:"pk autoincr"`
+	GroupID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RuleID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+}
+
+// TableName sets the table name
+func (*GroupRuleMapping) TableName() string {
+	return "quota_group_rule_mapping"
+}
+
+// GroupMapping assigns a group to a user or an organization
+type GroupMapping struct {
+	ID      int64 `xorm:"pk autoincr"`
+	GroupID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	UserID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+}
+
+// TableName sets the table name
+func (*GroupMapping) TableName() string {
+	return "quota_group_mapping"
+}
+
+// Evaluate checks if the additional size for the subject fits into the rules of the group.
+// The second result is false if the group has no rule for the subject.
+func (g *Group) Evaluate(used *Used, subject LimitSubject, size int64) (bool, bool) {
+	found := false
+	for _, r := range g.Rules {
+		if !r.Covers(subject) {
+			continue
+		}
+		found = true
+		if !r.Acceptable(used, size) {
+			return false, true
+		}
+	}
+	return true, found
+}
+
+// GroupList is a list of quota groups
+type GroupList []*Group
+
+// Covers checks if any rule of the groups limits the subject
+func (gl GroupList) Covers(subject LimitSubject) bool {
+	for _, g := range gl {
+		for _, r := range g.Rules {
+			if r.Covers(subject) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Evaluate checks if the additional size for the subject is allowed by the groups.
+// It is allowed if any group with a rule for the subject accepts it. Without any group
+// the default total limit applies.
+func (gl GroupList) Evaluate(used *Used, subject LimitSubject, size int64) bool {
+	if len(gl) == 0 {
+		return setting.Quota.DefaultTotal < 0 || used.SizeFor(LimitSubjectSizeAll)+size <= setting.Quota.DefaultTotal
+	}
+
+	found := false
+	for _, g := range gl {
+		ok, has := g.Evaluate(used, subject, size)
+		if !has {
+			continue
+		}
+		if ok {
+			return true
+		}
+		found = true
+	}
+	return !found
+}
+
+// LoadRules loads the rules of the groups
+func (gl GroupList) LoadRules(ctx context.Context) error {
+	if len(gl) == 0 {
+		return nil
+	}
+
+	groupIDs := make([]int64, 0, len(gl))
+	for _, g := range gl {
+		groupIDs = append(groupIDs, g.ID)
+	}
+
+	mappings := make([]*GroupRuleMapping, 0, len(gl))
+	if err := db.GetEngine(ctx).In("group_id", groupIDs).Find(&mappings); err != nil {
+		return err
+	}
+
+	ruleIDs := make(container.Set[int64])
+	for _, m := range mappings {
+		ruleIDs.Add(m.RuleID)
+	}
+	rules, err := GetRulesByIDs(ctx, ruleIDs.Values())
+	if err != nil {
+		return err
+	}
+	ruleMap := make(map[int64]*Rule, len(rules))
+	for _, r := range rules {
+		ruleMap[r.ID] = r
+	}
+
+	groupRules := make(map[int64][]*Rule, len(gl))
+	for _, m := range mappings {
+		if r, ok := ruleMap[m.RuleID]; ok {
+			groupRules[m.GroupID] = append(groupRules[m.GroupID], r)
+		}
+	}
+	for _, g := range gl {
+		g.Rules = groupRules[g.ID]
+		if g.Rules == nil {
+			g.Rules = []*Rule{}
+		}
+	}
+	return nil
+}
+
+// CreateGroup creates a new quota group
+func CreateGroup(ctx context.Context, g *Group) error {
+	g.Name = strings.TrimSpace(g.Name)
+
+	has, err := db.GetEngine(ctx).Where("name = ?", g.Name).Exist(new(Group))
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrGroupAlreadyExist
+	}
+
+	if err := db.Insert(ctx, g); err != nil {
+		return err
+	}
+	g.Rules = []*Rule{}
+	return nil
+}
+
+// GetGroupByName gets a quota group with its rules by its name
+func GetGroupByName(ctx context.Context, name string) (*Group, error) {
+	g := &Group{}
+	has, err := db.GetEngine(ctx).Where("name = ?", name).Get(g)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrGroupNotExist
+	}
+	if err := (GroupList{g}).LoadRules(ctx); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// ListGroups lists all quota groups with their rules
+func ListGroups(ctx context.Context) (GroupList, error) {
+	groups := make(GroupList, 0, 10)
+	if err := db.GetEngine(ctx).Asc("name").Find(&groups); err != nil {
+		return nil, err
+	}
+	return groups, groups.LoadRules(ctx)
+}
+
+// DeleteGroup deletes a quota group and all its mappings
+func DeleteGroup(ctx context.Context, groupID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+		if _, err := e.Where("group_id = ?", groupID).Delete(new(GroupRuleMapping)); err != nil {
+			return err
+		}
+		if _, err := e.Where("group_id = ?", groupID).Delete(new(GroupMapping)); err != nil {
+			return err
+		}
+		_, err := e.ID(groupID).Delete(new(Group))
+		return err
+	})
+}
+
+// AddRuleToGroup adds the rule to the group
+func AddRuleToGroup(ctx context.Context, groupID, ruleID int64) error {
+	has, err := db.GetEngine(ctx).Where("group_id = ? AND rule_id = ?", groupID, ruleID).Exist(new(GroupRuleMapping))
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrRuleAlreadyInGroup
+	}
+	return db.Insert(ctx, &GroupRuleMapping{GroupID: groupID, RuleID: ruleID})
+}
+
+// RemoveRuleFromGroup removes the rule from the group
+func RemoveRuleFromGroup(ctx context.Context, groupID, ruleID int64) error {
+	n, err := db.GetEngine(ctx).Where("group_id = ? AND rule_id = ?", groupID, ruleID).Delete(new(GroupRuleMapping))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrRuleNotInGroup
+	}
+	return nil
+}
+
+// AddUserToGroup assigns the group to the user or organization
+func AddUserToGroup(ctx context.Context, groupID, userID int64) error {
+	if groupID <= 0 || userID <= 0 {
+		return errGroupMappingQueryInvalid
+	}
+
+	has, err := db.GetEngine(ctx).Where("group_id = ? AND user_id = ?", groupID, userID).Exist(new(GroupMapping))
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrUserAlreadyInGroup
+	}
+	return db.Insert(ctx, &GroupMapping{GroupID: groupID, UserID: userID})
+}
+
+// RemoveUserFromGroup removes the group from the user or organization
+func RemoveUserFromGroup(ctx context.Context, groupID, userID int64) error {
+	n, err := db.GetEngine(ctx).Where("group_id = ? AND user_id = ?", groupID, userID).Delete(new(GroupMapping))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotInGroup
+	}
+	return nil
+}
+
+// GetUserIDsInGroup gets the ids of all users and organizations assigned to the group
+func GetUserIDsInGroup(ctx context.Context, groupID int64) ([]int64, error) {
+	userIDs := make([]int64, 0, 10)
+	return userIDs, db.GetEngine(ctx).Table("quota_group_mapping").Where("group_id = ?", groupID).Cols("user_id").Find(&userIDs)
+}
+
+// DeleteGroupMappingsForUser removes all group assignments of the user or organization
+func DeleteGroupMappingsForUser(ctx context.Context, userID int64) error {
+	_, err := db.GetEngine(ctx).Where("user_id = ?", userID).Delete(new(GroupMapping))
+	return err
+}
+
+// GetGroupsForUser gets the quota groups of the user or organization with their rules.
+// If the user has no assigned groups, the configured default groups are used.
+func GetGroupsForUser(ctx context.Context, userID int64) (GroupList, error) {
+	groups := make(GroupList, 0, 2)
+	if err := db.GetEngine(ctx).
+		Where("id IN (SELECT group_id FROM quota_group_mapping WHERE user_id = ?)", userID).
+		Asc("name").
+		Find(&groups); err != nil {
+		return nil, err
+	}
+
+	if len(groups) == 0 && len(setting.Quota.DefaultGroups) > 0 {
+		if err := db.GetEngine(ctx).In("name", setting.Quota.DefaultGroups).Asc("name").Find(&groups); err != nil {
+			return nil, err
+		}
+	}
+
+	return groups, groups.LoadRules(ctx)
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"code.gitea.io/gitea/modules/util"
+)
+
+// LimitSubject is the kind of storage a quota rule limits
+type LimitSubject string
+
+const (
+	LimitSubjectSizeAll                       LimitSubject = "size:all"
+	LimitSubjectSizeReposAll                  LimitSubject = "size:repos:all"
+	LimitSubjectSizeGitAll                    LimitSubject = "size:git:all"
+	LimitSubjectSizeGitLFS                    LimitSubject = "size:git:lfs"
+	LimitSubjectSizeAssetsAll                 LimitSubject = "size:assets:all"
+	LimitSubjectSizeAssetsAttachmentsAll      LimitSubject = "size:assets:attachments:all"
+	LimitSubjectSizeAssetsAttachmentsIssues   LimitSubject = "size:assets:attachments:issues"
+	LimitSubjectSizeAssetsAttachmentsReleases LimitSubject = "size:assets:attachments:releases"
+	LimitSubjectSizeAssetsArtifacts           LimitSubject = "size:assets:artifacts"
+	LimitSubjectSizeAssetsPackagesAll         LimitSubject = "size:assets:packages:all"
+)
+
+// LimitSubjects contains all known limit subjects
+var LimitSubjects = []LimitSubject{
+	LimitSubjectSizeAll,
+	LimitSubjectSizeReposAll,
+	LimitSubjectSizeGitAll,
+	LimitSubjectSizeGitLFS,
+	LimitSubjectSizeAssetsAll,
+	LimitSubjectSizeAssetsAttachmentsAll,
+	LimitSubjectSizeAssetsAttachmentsIssues,
+	LimitSubjectSizeAssetsAttachmentsReleases,
+	LimitSubjectSizeAssetsArtifacts,
+	LimitSubjectSizeAssetsPackagesAll,
+}
+
+var limitSubjectParents = map[LimitSubject]LimitSubject{
+	LimitSubjectSizeReposAll:                  LimitSubjectSizeAll,
+	LimitSubjectSizeGitAll:                    LimitSubjectSizeReposAll,
+	LimitSubjectSizeGitLFS:                    LimitSubjectSizeReposAll,
+	LimitSubjectSizeAssetsAll:                 LimitSubjectSizeAll,
+	LimitSubjectSizeAssetsAttachmentsAll:      LimitSubjectSizeAssetsAll,
+	LimitSubjectSizeAssetsAttachmentsIssues:   LimitSubjectSizeAssetsAttachmentsAll,
+	LimitSubjectSizeAssetsAttachmentsReleases: LimitSubjectSizeAssetsAttachmentsAll,
+	LimitSubjectSizeAssetsArtifacts:           LimitSubjectSizeAssetsAll,
+	LimitSubjectSizeAssetsPackagesAll:         LimitSubjectSizeAssetsAll,
+}
+
+// ParseLimitSubject parses a limit subject
+func ParseLimitSubject(s string) (LimitSubject, error) {
+	for _, subject := range LimitSubjects {
+		if string(subject) == s {
+			return subject, nil
+		}
+	}
+	return "", util.NewInvalidArgumentErrorf("invalid quota limit subject: %s", s)
+}
+
+// Covers checks if the subject includes the other subject
+func (s LimitSubject) Covers(other LimitSubject) bool {
+	for o := other; o != ""; o = limitSubjectParents[o] {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// EvaluateForUser checks if the user or organization may store the additional size for the subject
+func EvaluateForUser(ctx context.Context, userID int64, subject LimitSubject, size int64) (bool, error) {
+	if !setting.Quota.Enabled {
+		return true, nil
+	}
+
+	groups, err := GetGroupsForUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if len(groups) > 0 && !groups.Covers(subject) {
+		return true, nil
+	}
+	if len(groups) == 0 && setting.Quota.DefaultTotal < 0 {
+		return true, nil
+	}
+
+	used, err := GetUsedForUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return groups.Evaluate(used, subject, size), nil
+}
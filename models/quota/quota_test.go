@@ -0,0 +1,143 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package quota_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	quota_model "code.gitea.io/gitea/models/quota"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitSubjectCovers(t *testing.T) {
+	assert.True(t, quota_model.LimitSubjectSizeAll.Covers(quota_model.LimitSubjectSizeGitLFS))
+	assert.True(t, quota_model.LimitSubjectSizeReposAll.Covers(quota_model.LimitSubjectSizeGitAll))
+	assert.True(t, quota_model.LimitSubjectSizeAssetsAll.Covers(quota_model.LimitSubjectSizeAssetsAttachmentsReleases))
+	assert.True(t, quota_model.LimitSubjectSizeGitLFS.Covers(quota_model.LimitSubjectSizeGitLFS))
+	assert.False(t, quota_model.LimitSubjectSizeGitLFS.Covers(quota_model.LimitSubjectSizeReposAll))
+	assert.False(t, quota_model.LimitSubjectSizeAssetsAll.Covers(quota_model.LimitSubjectSizeGitAll))
+
+	_, err := quota_model.ParseLimitSubject("size:unknown")
+	assert.Error(t, err)
+	s, err := quota_model.ParseLimitSubject("size:assets:artifacts")
+	assert.NoError(t, err)
+	assert.Equal(t, quota_model.LimitSubjectSizeAssetsArtifacts, s)
+}
+
+func TestRuleSum(t *testing.T) {
+	used := &quota_model.Used{
+		Git:                1,
+		LFS:                2,
+		IssueAttachments:   4,
+		ReleaseAttachments: 8,
+		Artifacts:          16,
+		Packages:           32,
+	}
+
+	assert.EqualValues(t, 63, used.SizeFor(quota_model.LimitSubjectSizeAll))
+	assert.EqualValues(t, 3, used.SizeFor(quota_model.LimitSubjectSizeReposAll))
+	assert.EqualValues(t, 12, used.SizeFor(quota_model.LimitSubjectSizeAssetsAttachmentsAll))
+
+	r := &quota_model.Rule{
+		Limit:    20,
+		Subjects: []quota_model.LimitSubject{quota_model.LimitSubjectSizeReposAll, quota_model.LimitSubjectSizeGitLFS, quota_model.LimitSubjectSizeAssetsAttachmentsAll},
+	}
+	assert.EqualValues(t, 15, r.Sum(used))
+	assert.True(t, r.Covers(quota_model.LimitSubjectSizeGitAll))
+	assert.False(t, r.Covers(quota_model.LimitSubjectSizeAssetsPackagesAll))
+	assert.True(t, r.Acceptable(used, 5))
+	assert.False(t, r.Acceptable(used, 6))
+
+	r.Limit = -1
+	assert.True(t, r.Acceptable(used, 1<<40))
+}
+
+func TestGroupListEvaluate(t *testing.T) {
+	used := &quota_model.Used{Git: 10, Packages: 10}
+
+	small := &quota_model.Group{Rules: []*quota_model.Rule{
+		{Limit: 15, Subjects: []quota_model.LimitSubject{quota_model.LimitSubjectSizeAssetsAll}},
+	}}
+	large := &quota_model.Group{Rules: []*quota_model.Rule{
+		{Limit: 100, Subjects: []quota_model.LimitSubject{quota_model.LimitSubjectSizeAll}},
+	}}
+
+	assert.False(t, quota_model.GroupList{small}.Evaluate(used, quota_model.LimitSubjectSizeAssetsPackagesAll, 10))
+	// groups without a rule for the subject don't limit it
+	assert.True(t, quota_model.GroupList{small}.Evaluate(used, quota_model.LimitSubjectSizeGitAll, 1000))
+	// any group accepting the size is enough
+	assert.True(t, quota_model.GroupList{small, large}.Evaluate(used, quota_model.LimitSubjectSizeAssetsPackagesAll, 10))
+
+	defer test.MockVariableValue(&setting.Quota.DefaultTotal, 25)()
+	assert.True(t, quota_model.GroupList{}.Evaluate(used, quota_model.LimitSubjectSizeGitAll, 5))
+	assert.False(t, quota_model.GroupList{}.Evaluate(used, quota_model.LimitSubjectSizeGitAll, 6))
+}
+
+func TestQuotaGroups(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	ctx := db.DefaultContext
+
+	r := &quota_model.Rule{Name: "rule", Limit: 0, Subjects: []quota_model.LimitSubject{quota_model.LimitSubjectSizeAssetsPackagesAll}}
+	assert.NoError(t, quota_model.CreateRule(ctx, r))
+	assert.ErrorIs(t, quota_model.CreateRule(ctx, &quota_model.Rule{Name: "rule"}), quota_model.ErrRuleAlreadyExist)
+
+	g := &quota_model.Group{Name: "group"}
+	assert.NoError(t, quota_model.CreateGroup(ctx, g))
+	assert.NoError(t, quota_model.AddRuleToGroup(ctx, g.ID, r.ID))
+	assert.ErrorIs(t, quota_model.AddRuleToGroup(ctx, g.ID, r.ID), quota_model.ErrRuleAlreadyInGroup)
+
+	g, err := quota_model.GetGroupByName(ctx, "group")
+	assert.NoError(t, err)
+	assert.Len(t, g.Rules, 1)
+
+	groups, err := quota_model.GetGroupsForUser(ctx, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, groups)
+
+	defer test.MockVariableValue(&setting.Quota.DefaultGroups, []string{"group"})()
+	groups, err = quota_model.GetGroupsForUser(ctx, 2)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+
+	assert.NoError(t, quota_model.AddUserToGroup(ctx, g.ID, 2))
+	assert.ErrorIs(t, quota_model.AddUserToGroup(ctx, g.ID, 2), quota_model.ErrUserAlreadyInGroup)
+	userIDs, err := quota_model.GetUserIDsInGroup(ctx, g.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, userIDs)
+
+	ok, err := quota_model.EvaluateForUser(ctx, 2, quota_model.LimitSubjectSizeAssetsPackagesAll, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok, "quotas are disabled")
+
+	defer test.MockVariableValue(&setting.Quota.Enabled, true)()
+	ok, err = quota_model.EvaluateForUser(ctx, 2, quota_model.LimitSubjectSizeAssetsPackagesAll, 1)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = quota_model.EvaluateForUser(ctx, 2, quota_model.LimitSubjectSizeGitAll, 1)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, quota_model.DeleteRule(ctx, r.ID))
+	g, err = quota_model.GetGroupByName(ctx, "group")
+	assert.NoError(t, err)
+	assert.Empty(t, g.Rules)
+
+	assert.NoError(t, quota_model.DeleteGroup(ctx, g.ID))
+	_, err = quota_model.GetGroupByName(ctx, "group")
+	assert.ErrorIs(t, err, quota_model.ErrGroupNotExist)
+	unittest.AssertNotExistsBean(t, &quota_model.GroupMapping{UserID: 2})
+}
+
+func TestGetUsedForUser(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	used, err := quota_model.GetUsedForUser(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, used.Git+used.LFS+used.IssueAttachments+used.ReleaseAttachments+used.Artifacts+used.Packages, used.SizeFor(quota_model.LimitSubjectSizeAll))
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	ErrRuleNotExist     = util.NewNotExistErrorf("quota rule does not exist")
+	ErrRuleAlreadyExist = util.NewAlreadyExistErrorf("quota rule already exists")
+)
+
+func init() {
+	db.RegisterModel(new(Rule))
+}
+
+// Rule limits the total size of one or more subjects
+type Rule struct {
+	ID   int64  `xorm:"pk autoincr"`
+	Name string `xorm:"UNIQUE NOT NULL"`
+	// Limit is the maximum size in bytes, -1 means no limit
+	Limit    int64          `xorm:"NOT NULL DEFAULT -1"`
+	Subjects []LimitSubject `xorm:"JSON TEXT"`
+}
+
+// TableName sets the table name
+func (*Rule) TableName() string {
+	return "quota_rule"
+}
+
+// Covers checks if the rule limits the subject
+func (r *Rule) Covers(subject LimitSubject) bool {
+	for _, s := range r.Subjects {
+		if s.Covers(subject) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sum returns the used size counted against the rule
+func (r *Rule) Sum(used *Used) int64 {
+	return used.sizeForAny(r.Subjects...)
+}
+
+// Acceptable checks if the additional size fits into the rule
+func (r *Rule) Acceptable(used *Used, size int64) bool {
+	return r.Limit < 0 || r.Sum(used)+size <= r.Limit
+}
+
+// CreateRule creates a new quota rule
+func CreateRule(ctx context.Context, r *Rule) error {
+	r.Name = strings.TrimSpace(r.Name)
+
+	has, err := db.GetEngine(ctx).Where("name = ?", r.Name).Exist(new(Rule))
+	if err != nil {
+		return err
+	}
+	if has {
+		return ErrRuleAlreadyExist
+	}
+
+	return db.Insert(ctx, r)
+}
+
+// GetRuleByName gets a quota rule by its name
+func GetRuleByName(ctx context.Context, name string) (*Rule, error) {
+	r := &Rule{}
+	has, err := db.GetEngine(ctx).Where("name = ?", name).Get(r)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrRuleNotExist
+	}
+	return r, nil
+}
+
+// GetRulesByIDs gets the quota rules with the ids
+func GetRulesByIDs(ctx context.Context, ids []int64) ([]*Rule, error) {
+	rules := make([]*Rule, 0, len(ids))
+	return rules, db.GetEngine(ctx).In("id", ids).Asc("name").Find(&rules)
+}
+
+// ListRules lists all quota rules
+func ListRules(ctx context.Context) ([]*Rule, error) {
+	rules := make([]*Rule, 0, 10)
+	return rules, db.GetEngine(ctx).Asc("name").Find(&rules)
+}
+
+// UpdateRule updates the limit and subjects of a quota rule
+func UpdateRule(ctx context.Context, r *Rule) error {
+	_, err := db.GetEngine(ctx).ID(r.ID).Cols("limit", "subjects").Update(r)
+	return err
+}
+
+// DeleteRule deletes a quota rule and removes it from all groups
+func DeleteRule(ctx context.Context, ruleID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("rule_id = ?", ruleID).Delete(new(GroupRuleMapping)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(ruleID).Delete(new(Rule))
+		return err
+	})
+}
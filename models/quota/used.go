@@ -0,0 +1,92 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package quota
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"xorm.io/builder"
+)
+
+// Used contains the storage used by an owner in bytes
+type Used struct {
+	Git                int64
+	LFS                int64
+	IssueAttachments   int64
+	ReleaseAttachments int64
+	Artifacts          int64
+	Packages           int64
+}
+
+func (u *Used) sizes() map[LimitSubject]int64 {
+	return map[LimitSubject]int64{
+		LimitSubjectSizeGitAll:                    u.Git,
+		LimitSubjectSizeGitLFS:                    u.LFS,
+		LimitSubjectSizeAssetsAttachmentsIssues:   u.IssueAttachments,
+		LimitSubjectSizeAssetsAttachmentsReleases: u.ReleaseAttachments,
+		LimitSubjectSizeAssetsArtifacts:           u.Artifacts,
+		LimitSubjectSizeAssetsPackagesAll:         u.Packages,
+	}
+}
+
+// SizeFor returns the used size covered by the subject
+func (u *Used) SizeFor(subject LimitSubject) int64 {
+	return u.sizeForAny(subject)
+}
+
+// sizeForAny returns the used size covered by any of the subjects without counting storage twice
+func (u *Used) sizeForAny(subjects ...LimitSubject) int64 {
+	var size int64
+	for leaf, leafSize := range u.sizes() {
+		for _, subject := range subjects {
+			if subject.Covers(leaf) {
+				size += leafSize
+				break
+			}
+		}
+	}
+	return size
+}
+
+// GetUsedForUser calculates the storage used by the user or organization
+func GetUsedForUser(ctx context.Context, userID int64) (*Used, error) {
+	u := &Used{}
+	e := db.GetEngine(ctx)
+
+	sizes, err := e.Where("owner_id = ?", userID).SumsInt(new(repo_model.Repository), "git_size", "lfs_size")
+	if err != nil {
+		return nil, err
+	}
+	u.Git, u.LFS = sizes[0], sizes[1]
+
+	attachmentCond := builder.In("repo_id", builder.Select("id").From("repository").Where(builder.Eq{"owner_id": userID}))
+	u.ReleaseAttachments, err = e.Where(attachmentCond).And("release_id <> 0").SumInt(new(repo_model.Attachment), "size")
+	if err != nil {
+		return nil, err
+	}
+	u.IssueAttachments, err = e.Where(attachmentCond).And("release_id = 0").SumInt(new(repo_model.Attachment), "size")
+	if err != nil {
+		return nil, err
+	}
+
+	u.Artifacts, err = e.
+		Where("owner_id = ?", userID).
+		In("status", actions_model.ArtifactStatusUploadPending, actions_model.ArtifactStatusUploadConfirmed).
+		SumInt(new(actions_model.ActionArtifact), "file_compressed_size")
+	if err != nil {
+		return nil, err
+	}
+
+	u.Packages, err = packages_model.CalculateFileSize(ctx, &packages_model.PackageFileSearchOptions{OwnerID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"context"
+	"errors"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(TerraformState))
+}
+
+// ErrStateLocked is returned if a state is locked by another lock
+var ErrStateLocked = errors.New("state is locked")
+
+// TerraformState is a named state of the Terraform http backend of a repository
+type TerraformState struct {
+	ID              int64  `xorm:"pk autoincr"`
+	RepoID          int64  `xorm:"UNIQUE(repo_name)"`
+	Name            string `xorm:"VARCHAR(255) UNIQUE(repo_name)"`
+	LatestVersionID int64  // 0 if the state has been locked but never been written
+	Serial          int64
+	Lineage         string `xorm:"VARCHAR(255)"`
+	Size            int64
+
+	LockID     string `xorm:"VARCHAR(255)"` // The ID of the lock chosen by Terraform, empty if the state is not locked
+	LockInfo   string `xorm:"TEXT"`         // The JSON lock info sent by Terraform
+	LockerID   int64  // The user who locked the state
+	LockedUnix timeutil.TimeStamp
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// IsLocked returns whether the state is locked
+func (s *TerraformState) IsLocked() bool {
+	return s.LockID != ""
+}
+
+// GetStateByName returns the state with the name in a repository
+func GetStateByName(ctx context.Context, repoID int64, name string) (*TerraformState, error) {
+	var s TerraformState
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND name = ?", repoID, name).Get(&s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("terraform state %s", name)
+	}
+	return &s, nil
+}
+
+// GetOrCreateState returns the state with the name in a repository, an empty state is created if it doesn't exist
+func GetOrCreateState(ctx context.Context, repoID int64, name string) (*TerraformState, error) {
+	s, err := GetStateByName(ctx, repoID, name)
+	if err == nil || !errors.Is(err, util.ErrNotExist) {
+		return s, err
+	}
+
+	s = &TerraformState{RepoID: repoID, Name: name}
+	if err := db.Insert(ctx, s); err != nil {
+		// the state may have been created by a concurrent request
+		if s, getErr := GetStateByName(ctx, repoID, name); getErr == nil {
+			return s, nil
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// UpdateState updates the given columns of a state
+func UpdateState(ctx context.Context, s *TerraformState, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(s.ID).Cols(cols...).Update(s)
+	return err
+}
+
+// LockState acquires the lock of a state, ErrStateLocked is returned if the state is already locked by another lock.
+// Locking a state again with the same lock id succeeds.
+func LockState(ctx context.Context, s *TerraformState, lockID, lockInfo string, lockerID int64) error {
+	if lockID == "" {
+		return util.NewInvalidArgumentErrorf("lock id is required")
+	}
+
+	n, err := db.GetEngine(ctx).
+		Where(builder.Eq{"id": s.ID}.And(builder.In("lock_id", "", lockID))).
+		Cols("lock_id", "lock_info", "locker_id", "locked_unix").
+		NoAutoTime().
+		Update(&TerraformState{
+			LockID:     lockID,
+			LockInfo:   lockInfo,
+			LockerID:   lockerID,
+			LockedUnix: timeutil.TimeStampNow(),
+		})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return checkLockUnchanged(ctx, s.ID, lockID)
+	}
+	return nil
+}
+
+// UnlockState releases the lock of a state, ErrStateLocked is returned if the state is locked by another lock.
+// The lock is released regardless of its id if lockID is empty.
+func UnlockState(ctx context.Context, s *TerraformState, lockID string) error {
+	var cond builder.Cond = builder.Eq{"id": s.ID}
+	if lockID != "" {
+		cond = cond.And(builder.In("lock_id", "", lockID))
+	}
+
+	n, err := db.GetEngine(ctx).
+		Where(cond).
+		Cols("lock_id", "lock_info", "locker_id", "locked_unix").
+		NoAutoTime().
+		Update(&TerraformState{})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return checkLockUnchanged(ctx, s.ID, "")
+	}
+	return nil
+}
+
+// checkLockUnchanged checks whether no row was updated because the lock already had the expected id,
+// some databases (MySQL) don't count the rows whose values have not changed as affected.
+func checkLockUnchanged(ctx context.Context, id int64, lockID string) error {
+	var s TerraformState
+	has, err := db.GetEngine(ctx).ID(id).Cols("lock_id").Get(&s)
+	if err != nil {
+		return err
+	} else if !has {
+		return util.NewNotExistErrorf("terraform state with id %d", id)
+	} else if s.LockID != lockID {
+		return ErrStateLocked
+	}
+	return nil
+}
+
+// DeleteStateByID deletes a state and the records of its versions, the files in the storage should be deleted by the caller
+func DeleteStateByID(ctx context.Context, id int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("state_id = ?", id).Delete(&TerraformStateVersion{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(id).Delete(&TerraformState{})
+		return err
+	})
+}
+
+// FindStatesOptions is the options to find states
+type FindStatesOptions struct {
+	db.ListOptions
+	RepoID int64
+}
+
+func (opts FindStatesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	return cond
+}
+
+func (opts FindStatesOptions) ToOrders() string {
+	return "name ASC"
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockState(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s, err := GetOrCreateState(db.DefaultContext, 1, "default")
+	assert.NoError(t, err)
+	assert.False(t, s.IsLocked())
+
+	// getting the state again must not create another one
+	s2, err := GetOrCreateState(db.DefaultContext, 1, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, s.ID, s2.ID)
+
+	assert.NoError(t, LockState(db.DefaultContext, s, "lock-1", `{"ID":"lock-1"}`, 2))
+	// locking again with the same lock succeeds
+	assert.NoError(t, LockState(db.DefaultContext, s, "lock-1", `{"ID":"lock-1"}`, 2))
+	assert.ErrorIs(t, LockState(db.DefaultContext, s, "lock-2", `{"ID":"lock-2"}`, 2), ErrStateLocked)
+
+	s = unittest.AssertExistsAndLoadBean(t, &TerraformState{ID: s.ID})
+	assert.True(t, s.IsLocked())
+	assert.Equal(t, "lock-1", s.LockID)
+	assert.Equal(t, `{"ID":"lock-1"}`, s.LockInfo)
+	assert.EqualValues(t, 2, s.LockerID)
+
+	assert.ErrorIs(t, UnlockState(db.DefaultContext, s, "lock-2"), ErrStateLocked)
+	assert.NoError(t, UnlockState(db.DefaultContext, s, "lock-1"))
+	s = unittest.AssertExistsAndLoadBean(t, &TerraformState{ID: s.ID})
+	assert.False(t, s.IsLocked())
+	assert.Empty(t, s.LockInfo)
+
+	// an empty lock id unlocks the state regardless of the current lock
+	assert.NoError(t, LockState(db.DefaultContext, s, "lock-2", `{"ID":"lock-2"}`, 2))
+	assert.NoError(t, UnlockState(db.DefaultContext, s, ""))
+	s = unittest.AssertExistsAndLoadBean(t, &TerraformState{ID: s.ID})
+	assert.False(t, s.IsLocked())
+
+	assert.Error(t, LockState(db.DefaultContext, s, "", "", 2))
+}
+
+func TestDeleteStateByID(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s, err := GetOrCreateState(db.DefaultContext, 1, "default")
+	assert.NoError(t, err)
+	assert.NoError(t, db.Insert(db.DefaultContext, &TerraformStateVersion{RepoID: 1, StateID: s.ID, Serial: 1}))
+	assert.NoError(t, db.Insert(db.DefaultContext, &TerraformStateVersion{RepoID: 1, StateID: s.ID, Serial: 2}))
+
+	versions, err := db.Find[TerraformStateVersion](db.DefaultContext, FindStateVersionsOptions{StateID: s.ID})
+	assert.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.EqualValues(t, 2, versions[0].Serial)
+
+	assert.NoError(t, DeleteStateByID(db.DefaultContext, s.ID))
+	unittest.AssertNotExistsBean(t, &TerraformState{ID: s.ID})
+	unittest.AssertNotExistsBean(t, &TerraformStateVersion{StateID: s.ID})
+}
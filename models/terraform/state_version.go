@@ -0,0 +1,90 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(TerraformStateVersion))
+}
+
+// TerraformStateVersion is a version of a state, Terraform increases the serial with every change of the state.
+// The encrypted content is stored in the storage.
+type TerraformStateVersion struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"INDEX"`
+	StateID     int64  `xorm:"UNIQUE(state_serial)"`
+	Serial      int64  `xorm:"UNIQUE(state_serial)"`
+	Lineage     string `xorm:"VARCHAR(255)"`
+	Size        int64  // The size of the unencrypted content
+	CreatorID   int64
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// StoragePath returns the path of the version in the storage
+func (v *TerraformStateVersion) StoragePath() string {
+	return fmt.Sprintf("%d/%d/%d", v.RepoID, v.StateID, v.ID)
+}
+
+// GetStateVersionByID returns a version by id
+func GetStateVersionByID(ctx context.Context, id int64) (*TerraformStateVersion, error) {
+	var v TerraformStateVersion
+	has, err := db.GetEngine(ctx).ID(id).Get(&v)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("terraform state version with id %d", id)
+	}
+	return &v, nil
+}
+
+// GetStateVersionBySerial returns the version of a state with the serial
+func GetStateVersionBySerial(ctx context.Context, stateID, serial int64) (*TerraformStateVersion, error) {
+	var v TerraformStateVersion
+	has, err := db.GetEngine(ctx).Where("state_id = ? AND serial = ?", stateID, serial).Get(&v)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, util.NewNotExistErrorf("terraform state version with serial %d", serial)
+	}
+	return &v, nil
+}
+
+// DeleteStateVersionByID deletes the record of a version, the file in the storage should be deleted by the caller
+func DeleteStateVersionByID(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&TerraformStateVersion{})
+	return err
+}
+
+// FindStateVersionsOptions is the options to find versions of states
+type FindStateVersionsOptions struct {
+	db.ListOptions
+	RepoID  int64
+	StateID int64
+}
+
+func (opts FindStateVersionsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.StateID > 0 {
+		cond = cond.And(builder.Eq{"state_id": opts.StateID})
+	}
+	return cond
+}
+
+// ToOrders returns the newest versions first
+func (opts FindStateVersionsOptions) ToOrders() string {
+	return "serial DESC, id DESC"
+}
@@ -125,6 +125,81 @@ func TestDetectMatched(t *testing.T) {
 			yamlOn:       "on: schedule",
 			expected:     true,
 		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) `completed` action matches GithubEventWorkflowRun(workflow_run) with matched workflow and branch",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload: &api.WorkflowRunPayload{
+				Action:      api.HookWorkflowRunCompleted,
+				Workflow:    &api.ActionWorkflow{Name: "CI"},
+				WorkflowRun: &api.ActionWorkflowRun{HeadBranch: "main"},
+			},
+			yamlOn:   "on:\n  workflow_run:\n    workflows: [CI]\n    types: [completed]\n    branches: [main]",
+			expected: true,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) `requested` action doesn't match GithubEventWorkflowRun(workflow_run) with `completed` activity type",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload: &api.WorkflowRunPayload{
+				Action:      api.HookWorkflowRunRequested,
+				Workflow:    &api.ActionWorkflow{Name: "CI"},
+				WorkflowRun: &api.ActionWorkflowRun{HeadBranch: "main"},
+			},
+			yamlOn:   "on:\n  workflow_run:\n    workflows: [CI]\n    types: [completed]",
+			expected: false,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) doesn't match GithubEventWorkflowRun(workflow_run) of other workflows",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload: &api.WorkflowRunPayload{
+				Action:      api.HookWorkflowRunCompleted,
+				Workflow:    &api.ActionWorkflow{Name: "Lint"},
+				WorkflowRun: &api.ActionWorkflowRun{HeadBranch: "main"},
+			},
+			yamlOn:   "on:\n  workflow_run:\n    workflows: [CI]",
+			expected: false,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) doesn't match GithubEventWorkflowRun(workflow_run) with ignored branch",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload: &api.WorkflowRunPayload{
+				Action:      api.HookWorkflowRunCompleted,
+				Workflow:    &api.ActionWorkflow{Name: "CI"},
+				WorkflowRun: &api.ActionWorkflowRun{HeadBranch: "release/v1"},
+			},
+			yamlOn:   "on:\n  workflow_run:\n    workflows: [CI]\n    branches-ignore: ['release/**']",
+			expected: false,
+		},
+		{
+			desc:         "HookEventWorkflowRun(workflow_run) matches GithubEventWorkflowRun(workflow_run) with workflow pattern",
+			triggedEvent: webhook_module.HookEventWorkflowRun,
+			payload: &api.WorkflowRunPayload{
+				Action:      api.HookWorkflowRunCompleted,
+				Workflow:    &api.ActionWorkflow{Name: "Build Linux"},
+				WorkflowRun: &api.ActionWorkflowRun{HeadBranch: "main"},
+			},
+			yamlOn:   "on:\n  workflow_run:\n    workflows: ['Build *']",
+			expected: true,
+		},
+		{
+			desc:         "HookEventMergeGroup(merge_group) matches GithubEventMergeGroup(merge_group)",
+			triggedEvent: webhook_module.HookEventMergeGroup,
+			payload: &api.MergeGroupPayload{
+				Action:     api.HookMergeGroupChecksRequested,
+				MergeGroup: &api.MergeGroup{BaseRef: "refs/heads/main"},
+			},
+			yamlOn:   "on:\n  merge_group:\n    types: [checks_requested]",
+			expected: true,
+		},
+		{
+			desc:         "HookEventMergeGroup(merge_group) doesn't match GithubEventMergeGroup(merge_group) of other branches",
+			triggedEvent: webhook_module.HookEventMergeGroup,
+			payload: &api.MergeGroupPayload{
+				Action:     api.HookMergeGroupChecksRequested,
+				MergeGroup: &api.MergeGroup{BaseRef: "refs/heads/develop"},
+			},
+			yamlOn:   "on:\n  merge_group:\n    branches: [main]",
+			expected: false,
+		},
 	}
 
 	for _, tc := range testCases {
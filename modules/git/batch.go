@@ -15,24 +15,35 @@ type Batch struct {
 }
 
 func (repo *Repository) NewBatch(ctx context.Context) (*Batch, error) {
+	return repo.NewBatchWithEnv(ctx, nil)
+}
+
+// NewBatchWithEnv opens a cat-file --batch with the environment, the environment can contain
+// the quarantine directories of a push to read the received objects in the pre-receive hook
+func (repo *Repository) NewBatchWithEnv(ctx context.Context, env []string) (*Batch, error) {
 	// Now because of some insanity with git cat-file not immediately failing if not run in a valid git directory we need to run git rev-parse first!
 	if err := ensureValidGitRepository(ctx, repo.Path); err != nil {
 		return nil, err
 	}
 
 	var batch Batch
-	batch.Writer, batch.Reader, batch.cancel = catFileBatch(ctx, repo.Path)
+	batch.Writer, batch.Reader, batch.cancel = catFileBatch(ctx, repo.Path, env)
 	return &batch, nil
 }
 
 func (repo *Repository) NewBatchCheck(ctx context.Context) (*Batch, error) {
+	return repo.NewBatchCheckWithEnv(ctx, nil)
+}
+
+// NewBatchCheckWithEnv opens a cat-file --batch-check with the environment, see NewBatchWithEnv
+func (repo *Repository) NewBatchCheckWithEnv(ctx context.Context, env []string) (*Batch, error) {
 	// Now because of some insanity with git cat-file not immediately failing if not run in a valid git directory we need to run git rev-parse first!
 	if err := ensureValidGitRepository(ctx, repo.Path); err != nil {
 		return nil, err
 	}
 
 	var check Batch
-	check.Writer, check.Reader, check.cancel = catFileBatchCheck(ctx, repo.Path)
+	check.Writer, check.Reader, check.cancel = catFileBatchCheck(ctx, repo.Path, env)
 	return &check, nil
 }
 
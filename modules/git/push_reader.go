@@ -0,0 +1,161 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PushedFile is a file added or modified by a commit
+type PushedFile struct {
+	Path   string
+	BlobID string
+	Size   int64
+}
+
+// PushReader reads the commits and files received by a push.
+// In the pre-receive hook the received objects are in the quarantine directories of the push,
+// so all git commands are run with the environment of the hook.
+type PushReader struct {
+	repo  *Repository
+	env   []string
+	batch *Batch
+	check *Batch
+}
+
+// NewPushReader creates a reader for the objects received by a push, the env must contain the quarantine directories of the push.
+// Without an env the reader reads the objects of the repository.
+func NewPushReader(ctx context.Context, repo *Repository, env []string) (*PushReader, error) {
+	batch, err := repo.NewBatchWithEnv(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+	check, err := repo.NewBatchCheckWithEnv(ctx, env)
+	if err != nil {
+		batch.Close()
+		return nil, err
+	}
+	return &PushReader{repo: repo, env: env, batch: batch, check: check}, nil
+}
+
+// Close closes the cat-file processes of the reader
+func (r *PushReader) Close() {
+	r.batch.Close()
+	r.check.Close()
+}
+
+// CommitIDs returns the IDs of the commits reachable from newCommitID which are not reachable from any existing ref,
+// these are the commits which are added to the repository by the push.
+func (r *PushReader) CommitIDs(ctx context.Context, newCommitID string) ([]string, error) {
+	stdout, _, err := NewCommand(ctx, "rev-list").AddDynamicArguments(newCommitID).AddArguments("--not", "--all").
+		RunStdString(&RunOpts{Dir: r.repo.Path, Env: r.env})
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(stdout), nil
+}
+
+// Commit reads a received commit
+func (r *PushReader) Commit(commitID string) (*Commit, error) {
+	id, err := NewIDFromString(commitID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.batch.Writer.Write([]byte(commitID + "\n")); err != nil {
+		return nil, err
+	}
+	_, typ, size, err := ReadBatchLine(r.batch.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if typ != "commit" {
+		if err := DiscardFull(r.batch.Reader, size+1); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("object %s is a %s, not a commit", commitID, typ)
+	}
+	commit, err := CommitFromReader(r.repo, id, io.LimitReader(r.batch.Reader, size))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.batch.Reader.Discard(1); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+// ChangedFiles returns the files added or modified by the commit compared to its parent, submodules are skipped.
+// Merge commits don't have changed files because the changes are made by their parents.
+func (r *PushReader) ChangedFiles(ctx context.Context, commitID string) ([]*PushedFile, error) {
+	stdout, _, err := NewCommand(ctx, "diff-tree", "-r", "-z", "--root", "--no-commit-id", "--no-renames", "--diff-filter=d").
+		AddDynamicArguments(commitID).
+		RunStdBytes(&RunOpts{Dir: r.repo.Path, Env: r.env})
+	if err != nil {
+		return nil, err
+	}
+
+	// the raw output is ":<old mode> <new mode> <old id> <new id> <status>\0<path>\0" for every file
+	fields := bytes.Split(bytes.TrimSuffix(stdout, []byte{0}), []byte{0})
+	files := make([]*PushedFile, 0, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		info := strings.Fields(string(fields[i]))
+		if len(info) != 5 {
+			return nil, fmt.Errorf("unexpected diff-tree output: %q", fields[i])
+		}
+		if info[1] == "160000" {
+			continue
+		}
+		files = append(files, &PushedFile{
+			Path:   string(fields[i+1]),
+			BlobID: info[3],
+		})
+	}
+
+	for _, file := range files {
+		size, err := r.BlobSize(file.BlobID)
+		if err != nil {
+			return nil, err
+		}
+		file.Size = size
+	}
+	return files, nil
+}
+
+// BlobSize returns the size of a blob
+func (r *PushReader) BlobSize(blobID string) (int64, error) {
+	if _, err := r.check.Writer.Write([]byte(blobID + "\n")); err != nil {
+		return 0, err
+	}
+	_, _, size, err := ReadBatchLine(r.check.Reader)
+	return size, err
+}
+
+// BlobContent returns the content of a blob
+func (r *PushReader) BlobContent(blobID string) ([]byte, error) {
+	if _, err := r.batch.Writer.Write([]byte(blobID + "\n")); err != nil {
+		return nil, err
+	}
+	_, typ, size, err := ReadBatchLine(r.batch.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if typ != "blob" {
+		if err := DiscardFull(r.batch.Reader, size+1); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("object %s is a %s, not a blob", blobID, typ)
+	}
+	content := make([]byte, size)
+	if _, err := io.ReadFull(r.batch.Reader, content); err != nil {
+		return nil, err
+	}
+	if _, err := r.batch.Reader.Discard(1); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
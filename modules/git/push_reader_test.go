@@ -0,0 +1,60 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushReader(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := openRepositoryWithDefaultContext(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	reader, err := NewPushReader(DefaultContext, bareRepo1, nil)
+	assert.NoError(t, err)
+	defer reader.Close()
+
+	// all commits are reachable from the existing refs
+	commitIDs, err := reader.CommitIDs(DefaultContext, "37991dec2c8e592043f47155ce4808d4580f9123")
+	assert.NoError(t, err)
+	assert.Empty(t, commitIDs)
+
+	commit, err := reader.Commit("37991dec2c8e592043f47155ce4808d4580f9123")
+	assert.NoError(t, err)
+	assert.Equal(t, "Added short link\n", commit.CommitMessage)
+	assert.Equal(t, "tris.git@shoddynet.org", commit.Committer.Email)
+
+	files, err := reader.ChangedFiles(DefaultContext, "37991dec2c8e592043f47155ce4808d4580f9123")
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		assert.Equal(t, "foo/link_short", files[0].Path)
+		assert.Equal(t, "2e65efe2a145dda7ee51d1741299f848e5bf752e", files[0].BlobID)
+		assert.EqualValues(t, 1, files[0].Size)
+	}
+
+	// the empty commit doesn't change files
+	files, err = reader.ChangedFiles(DefaultContext, "feaf4ba6bc635fec442f46ddd4512416ec43c2c2")
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, err = reader.Commit("2e65efe2a145dda7ee51d1741299f848e5bf752e")
+	assert.Error(t, err)
+
+	// the reader must still be usable after reading an object which is not a commit
+	commit, err = reader.Commit("feaf4ba6bc635fec442f46ddd4512416ec43c2c2")
+	assert.NoError(t, err)
+	assert.Equal(t, "empty commit", commit.Summary())
+
+	content, err := reader.BlobContent("2e65efe2a145dda7ee51d1741299f848e5bf752e")
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(content))
+
+	_, err = reader.BlobContent("feaf4ba6bc635fec442f46ddd4512416ec43c2c2")
+	assert.Error(t, err)
+}
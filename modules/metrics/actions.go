@@ -0,0 +1,101 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ActionsCollector implements the prometheus.Collector interface and
+// exposes the state of Gitea Actions for prometheus
+type ActionsCollector struct {
+	Runs          *prometheus.Desc
+	Jobs          *prometheus.Desc
+	RunnersOnline *prometheus.Desc
+}
+
+// NewActionsCollector returns a new ActionsCollector with all prometheus.Desc initialized
+func NewActionsCollector() ActionsCollector {
+	return ActionsCollector{
+		Runs: prometheus.NewDesc(
+			namespace+"actions_runs",
+			"Number of Actions runs by status",
+			[]string{"status"}, nil,
+		),
+		Jobs: prometheus.NewDesc(
+			namespace+"actions_jobs",
+			"Number of Actions jobs by status",
+			[]string{"status"}, nil,
+		),
+		RunnersOnline: prometheus.NewDesc(
+			namespace+"actions_runners_online",
+			"Number of online Actions runners by label",
+			[]string{"label"}, nil,
+		),
+	}
+}
+
+// Describe returns all possible prometheus.Desc
+func (c ActionsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Runs
+	ch <- c.Jobs
+	ch <- c.RunnersOnline
+}
+
+// Collect returns the metrics with values
+func (c ActionsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx := db.DefaultContext
+
+	runs, err := actions_model.CountRunsByStatus(ctx)
+	if err != nil {
+		log.Error("Unable to count actions runs: %v", err)
+	}
+	for status, count := range runs {
+		ch <- prometheus.MustNewConstMetric(
+			c.Runs,
+			prometheus.GaugeValue,
+			float64(count),
+			status.String(),
+		)
+	}
+
+	jobs, err := actions_model.CountRunJobsByStatus(ctx)
+	if err != nil {
+		log.Error("Unable to count actions jobs: %v", err)
+	}
+	for status, count := range jobs {
+		ch <- prometheus.MustNewConstMetric(
+			c.Jobs,
+			prometheus.GaugeValue,
+			float64(count),
+			status.String(),
+		)
+	}
+
+	runners, err := db.Find[actions_model.ActionRunner](ctx, actions_model.FindRunnerOptions{
+		IsOnline: optional.Some(true),
+	})
+	if err != nil {
+		log.Error("Unable to find online actions runners: %v", err)
+	}
+	runnersByLabel := make(map[string]int)
+	for _, runner := range runners {
+		for _, label := range runner.AgentLabels {
+			runnersByLabel[label]++
+		}
+	}
+	for label, count := range runnersByLabel {
+		ch <- prometheus.MustNewConstMetric(
+			c.RunnersOnline,
+			prometheus.GaugeValue,
+			float64(count),
+			label,
+		)
+	}
+}
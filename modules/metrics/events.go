@@ -0,0 +1,76 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The metrics below are observed when the events happen instead of being collected from the database,
+// they are only exported after being registered by EventCollectors.
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    namespace + "http_request_duration_seconds",
+			Help:    "Duration of the HTTP requests by route group",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"group", "method", "code"},
+	)
+	webhookDeliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: namespace + "webhook_deliveries_total",
+			Help: "Number of webhook deliveries by webhook type and result",
+		},
+		[]string{"type", "result"},
+	)
+	webhookDeliveryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    namespace + "webhook_delivery_duration_seconds",
+			Help:    "Duration of the webhook deliveries by webhook type",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"type"},
+	)
+	actionsJobWaitDuration = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    namespace + "actions_job_wait_duration_seconds",
+			Help:    "Duration from the creation of the Actions jobs until they were picked by a runner",
+			Buckets: []float64{1, 5, 15, 30, 60, 300, 900, 1800, 3600, 3 * 3600, 12 * 3600},
+		},
+	)
+)
+
+// EventCollectors returns the collectors of the metrics observed when the events happen
+func EventCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		httpRequestDuration,
+		webhookDeliveries,
+		webhookDeliveryDuration,
+		actionsJobWaitDuration,
+	}
+}
+
+// ObserveHTTPRequest records the duration of a HTTP request
+func ObserveHTTPRequest(group, method string, code int, duration time.Duration) {
+	httpRequestDuration.WithLabelValues(group, method, strconv.Itoa(code)).Observe(duration.Seconds())
+}
+
+// ObserveWebhookDelivery records the result and the duration of a webhook delivery
+func ObserveWebhookDelivery(hookType string, succeed bool, duration time.Duration) {
+	result := "failure"
+	if succeed {
+		result = "success"
+	}
+	webhookDeliveries.WithLabelValues(hookType, result).Inc()
+	webhookDeliveryDuration.WithLabelValues(hookType).Observe(duration.Seconds())
+}
+
+// ObserveActionsJobWait records the duration from the creation of an Actions job until it was picked by a runner
+func ObserveActionsJobWait(duration time.Duration) {
+	actionsJobWaitDuration.Observe(duration.Seconds())
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"code.gitea.io/gitea/modules/queue"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueueCollector implements the prometheus.Collector interface and
+// exposes the state of the managed queues for prometheus
+type QueueCollector struct {
+	Items          *prometheus.Desc
+	Workers        *prometheus.Desc
+	ActiveWorkers  *prometheus.Desc
+	MaxWorkers     *prometheus.Desc
+	ProcessedItems *prometheus.Desc
+}
+
+// NewQueueCollector returns a new QueueCollector with all prometheus.Desc initialized
+func NewQueueCollector() QueueCollector {
+	return QueueCollector{
+		Items: prometheus.NewDesc(
+			namespace+"queue_items",
+			"Number of items waiting in the queue",
+			[]string{"queue"}, nil,
+		),
+		Workers: prometheus.NewDesc(
+			namespace+"queue_workers",
+			"Number of workers of the queue",
+			[]string{"queue"}, nil,
+		),
+		ActiveWorkers: prometheus.NewDesc(
+			namespace+"queue_active_workers",
+			"Number of workers handling items of the queue",
+			[]string{"queue"}, nil,
+		),
+		MaxWorkers: prometheus.NewDesc(
+			namespace+"queue_max_workers",
+			"Maximum number of workers of the queue",
+			[]string{"queue"}, nil,
+		),
+		ProcessedItems: prometheus.NewDesc(
+			namespace+"queue_processed_items_total",
+			"Number of items handled by the workers of the queue",
+			[]string{"queue"}, nil,
+		),
+	}
+}
+
+// Describe returns all possible prometheus.Desc
+func (c QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.Items
+	ch <- c.Workers
+	ch <- c.ActiveWorkers
+	ch <- c.MaxWorkers
+	ch <- c.ProcessedItems
+}
+
+// Collect returns the metrics with values
+func (c QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, q := range queue.GetManager().ManagedQueues() {
+		name := q.GetName()
+		ch <- prometheus.MustNewConstMetric(
+			c.Items,
+			prometheus.GaugeValue,
+			float64(q.GetQueueItemNumber()),
+			name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.Workers,
+			prometheus.GaugeValue,
+			float64(q.GetWorkerNumber()),
+			name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.ActiveWorkers,
+			prometheus.GaugeValue,
+			float64(q.GetWorkerActiveNumber()),
+			name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.MaxWorkers,
+			prometheus.GaugeValue,
+			float64(q.GetWorkerMaxNumber()),
+			name,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.ProcessedItems,
+			prometheus.CounterValue,
+			float64(q.GetProcessedItemNumber()),
+			name,
+		)
+	}
+}
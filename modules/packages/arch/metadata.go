@@ -0,0 +1,258 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package arch
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/validation"
+	"code.gitea.io/gitea/modules/zstd"
+
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	PropertyRepository   = "arch.repository"
+	PropertyArchitecture = "arch.architecture"
+	PropertyMetadata     = "arch.metadata"
+	PropertySignature    = "arch.signature"
+
+	SettingKeyPrivate = "arch.key.private"
+	SettingKeyPublic  = "arch.key.public"
+
+	RepositoryPackage = "_arch"
+	RepositoryVersion = "_repository"
+
+	AnyArch = "any"
+)
+
+var (
+	ErrMissingPKGINFOFile     = util.NewInvalidArgumentErrorf(".PKGINFO file is missing")
+	ErrUnsupportedCompression = util.NewInvalidArgumentErrorf("unsupported compression algorithm")
+	ErrInvalidName            = util.NewInvalidArgumentErrorf("package name is invalid")
+	ErrInvalidVersion         = util.NewInvalidArgumentErrorf("package version is invalid")
+	ErrInvalidArchitecture    = util.NewInvalidArgumentErrorf("package architecture is invalid")
+
+	// https://man.archlinux.org/man/PKGBUILD.5#OPTIONS_AND_DIRECTIVES
+	namePattern = regexp.MustCompile(`\A[a-zA-Z0-9@_+][a-zA-Z0-9@._+-]*\z`)
+	// (epoch:)pkgver-pkgrel
+	versionPattern      = regexp.MustCompile(`\A(?:[0-9]+:)?[a-zA-Z0-9._+~]+-[0-9]+(?:\.[0-9]+)?\z`)
+	architecturePattern = regexp.MustCompile(`\A[a-zA-Z0-9_]+\z`)
+
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// Package represents an Arch package
+type Package struct {
+	Name            string
+	Version         string
+	Extension       string
+	VersionMetadata VersionMetadata
+	FileMetadata    FileMetadata
+}
+
+// VersionMetadata of an Arch package
+type VersionMetadata struct {
+	Base        string   `json:"base,omitempty"`
+	Description string   `json:"description,omitempty"`
+	ProjectURL  string   `json:"project_url,omitempty"`
+	Licenses    []string `json:"licenses,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+}
+
+// FileMetadata of an Arch package file. The values may differ between the builds of the architectures.
+type FileMetadata struct {
+	Architecture  string   `json:"architecture"`
+	Packager      string   `json:"packager,omitempty"`
+	BuildDate     int64    `json:"build_date,omitempty"`
+	InstalledSize int64    `json:"installed_size,omitempty"`
+	Replaces      []string `json:"replaces,omitempty"`
+	Conflicts     []string `json:"conflicts,omitempty"`
+	Provides      []string `json:"provides,omitempty"`
+	Depends       []string `json:"depends,omitempty"`
+	OptDepends    []string `json:"opt_depends,omitempty"`
+	MakeDepends   []string `json:"make_depends,omitempty"`
+	CheckDepends  []string `json:"check_depends,omitempty"`
+	Backup        []string `json:"backup,omitempty"`
+}
+
+// Filename returns the name pacman expects for the package file
+func (p *Package) Filename() string {
+	return p.Name + "-" + p.Version + "-" + p.FileMetadata.Architecture + p.Extension
+}
+
+// ParsePackage parses the Arch package file (.pkg.tar.zst, .pkg.tar.xz or .pkg.tar.gz)
+// https://wiki.archlinux.org/title/Arch_package_guidelines
+func ParsePackage(r io.Reader) (*Package, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(len(xzMagic))
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	var inner io.Reader
+	var extension string
+	switch {
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		inner = zr
+		extension = ".pkg.tar.zst"
+	case bytes.HasPrefix(magic, xzMagic):
+		xzr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+
+		inner = xzr
+		extension = ".pkg.tar.xz"
+	case bytes.HasPrefix(magic, gzipMagic):
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gzr.Close()
+
+		inner = gzr
+		extension = ".pkg.tar.gz"
+	default:
+		return nil, ErrUnsupportedCompression
+	}
+
+	tr := tar.NewReader(inner)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if strings.TrimPrefix(hd.Name, "./") == ".PKGINFO" {
+			p, err := ParsePackageInfo(tr)
+			if err != nil {
+				return nil, err
+			}
+			p.Extension = extension
+			return p, nil
+		}
+	}
+
+	return nil, ErrMissingPKGINFOFile
+}
+
+// ParsePackageInfo parses a .PKGINFO file to retrieve the metadata of an Arch package
+func ParsePackageInfo(r io.Reader) (*Package, error) {
+	p := &Package{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "pkgname":
+			p.Name = value
+		case "pkgbase":
+			p.VersionMetadata.Base = value
+		case "pkgver":
+			p.Version = value
+		case "pkgdesc":
+			p.VersionMetadata.Description = value
+		case "url":
+			p.VersionMetadata.ProjectURL = value
+		case "license":
+			p.VersionMetadata.Licenses = appendNonEmpty(p.VersionMetadata.Licenses, value)
+		case "group":
+			p.VersionMetadata.Groups = appendNonEmpty(p.VersionMetadata.Groups, value)
+		case "arch":
+			p.FileMetadata.Architecture = value
+		case "packager":
+			p.FileMetadata.Packager = value
+		case "builddate":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err == nil {
+				p.FileMetadata.BuildDate = n
+			}
+		case "size":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err == nil {
+				p.FileMetadata.InstalledSize = n
+			}
+		case "replaces":
+			p.FileMetadata.Replaces = appendNonEmpty(p.FileMetadata.Replaces, value)
+		case "conflict":
+			p.FileMetadata.Conflicts = appendNonEmpty(p.FileMetadata.Conflicts, value)
+		case "provides":
+			p.FileMetadata.Provides = appendNonEmpty(p.FileMetadata.Provides, value)
+		case "depend":
+			p.FileMetadata.Depends = appendNonEmpty(p.FileMetadata.Depends, value)
+		case "optdepend":
+			p.FileMetadata.OptDepends = appendNonEmpty(p.FileMetadata.OptDepends, value)
+		case "makedepend":
+			p.FileMetadata.MakeDepends = appendNonEmpty(p.FileMetadata.MakeDepends, value)
+		case "checkdepend":
+			p.FileMetadata.CheckDepends = appendNonEmpty(p.FileMetadata.CheckDepends, value)
+		case "backup":
+			p.FileMetadata.Backup = appendNonEmpty(p.FileMetadata.Backup, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !namePattern.MatchString(p.Name) {
+		return nil, ErrInvalidName
+	}
+	if !versionPattern.MatchString(p.Version) {
+		return nil, ErrInvalidVersion
+	}
+	if !architecturePattern.MatchString(p.FileMetadata.Architecture) {
+		return nil, ErrInvalidArchitecture
+	}
+
+	if !validation.IsValidURL(p.VersionMetadata.ProjectURL) {
+		p.VersionMetadata.ProjectURL = ""
+	}
+
+	return p, nil
+}
+
+func appendNonEmpty(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+	return append(values, value)
+}
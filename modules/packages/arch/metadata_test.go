@@ -0,0 +1,154 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package arch
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"code.gitea.io/gitea/modules/zstd"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	packageName        = "gitea"
+	packageVersion     = "1:1.0.1-2"
+	packageDescription = "Package Description"
+	packageProjectURL  = "https://gitea.io"
+)
+
+func createPKGINFOContent(name, version, architecture string) []byte {
+	return []byte(`# Generated by makepkg
+pkgname = ` + name + `
+pkgbase = gitea-base
+pkgver = ` + version + `
+pkgdesc = ` + packageDescription + `
+url = ` + packageProjectURL + `
+builddate = 1678834800
+packager = Gitea <pack@ag.er>
+size = 123456
+arch = ` + architecture + `
+license = MIT
+license = Apache-2.0
+group = tools
+provides = common
+conflict = gitea-git
+depend = glibc
+depend = git
+optdepend = openssh: ssh support
+makedepend = go
+backup = etc/gitea/app.ini`)
+}
+
+func createPackage(compression string, files map[string][]byte) io.Reader {
+	var buf bytes.Buffer
+
+	var w io.WriteCloser
+	switch compression {
+	case "zst":
+		w, _ = zstd.NewWriter(&buf)
+	case "xz":
+		w, _ = xz.NewWriter(&buf)
+	case "gz":
+		w = gzip.NewWriter(&buf)
+	default:
+		w = nopWriteCloser{&buf}
+	}
+
+	tw := tar.NewWriter(w)
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		}
+		_ = tw.WriteHeader(hdr)
+		_, _ = tw.Write(content)
+	}
+	tw.Close()
+	w.Close()
+
+	return &buf
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestParsePackage(t *testing.T) {
+	t.Run("MissingPKGINFOFile", func(t *testing.T) {
+		p, err := ParsePackage(createPackage("zst", map[string][]byte{"dummy.txt": {}}))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrMissingPKGINFOFile)
+	})
+
+	t.Run("UnsupportedCompression", func(t *testing.T) {
+		p, err := ParsePackage(createPackage("", map[string][]byte{".PKGINFO": createPKGINFOContent(packageName, packageVersion, "x86_64")}))
+		assert.Nil(t, p)
+		assert.ErrorIs(t, err, ErrUnsupportedCompression)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		for _, name := range []string{"", "-gitea", ".gitea", "gi tea"} {
+			p, err := ParsePackage(createPackage("zst", map[string][]byte{".PKGINFO": createPKGINFOContent(name, packageVersion, "x86_64")}))
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidName)
+		}
+	})
+
+	t.Run("InvalidVersion", func(t *testing.T) {
+		for _, version := range []string{"", "1.0", "1.0-a", "1.0 -1"} {
+			p, err := ParsePackage(createPackage("zst", map[string][]byte{".PKGINFO": createPKGINFOContent(packageName, version, "x86_64")}))
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidVersion)
+		}
+	})
+
+	t.Run("InvalidArchitecture", func(t *testing.T) {
+		for _, architecture := range []string{"", "x86-64", "../any"} {
+			p, err := ParsePackage(createPackage("zst", map[string][]byte{".PKGINFO": createPKGINFOContent(packageName, packageVersion, architecture)}))
+			assert.Nil(t, p)
+			assert.ErrorIs(t, err, ErrInvalidArchitecture)
+		}
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		for compression, extension := range map[string]string{"zst": ".pkg.tar.zst", "xz": ".pkg.tar.xz", "gz": ".pkg.tar.gz"} {
+			p, err := ParsePackage(createPackage(compression, map[string][]byte{
+				"usr/bin/gitea": {},
+				".PKGINFO":      createPKGINFOContent(packageName, packageVersion, "x86_64"),
+			}))
+			assert.NoError(t, err)
+			assert.NotNil(t, p)
+
+			assert.Equal(t, packageName, p.Name)
+			assert.Equal(t, packageVersion, p.Version)
+			assert.Equal(t, extension, p.Extension)
+			assert.Equal(t, "gitea-1:1.0.1-2-x86_64"+extension, p.Filename())
+			assert.Equal(t, "gitea-base", p.VersionMetadata.Base)
+			assert.Equal(t, packageDescription, p.VersionMetadata.Description)
+			assert.Equal(t, packageProjectURL, p.VersionMetadata.ProjectURL)
+			assert.ElementsMatch(t, []string{"MIT", "Apache-2.0"}, p.VersionMetadata.Licenses)
+			assert.ElementsMatch(t, []string{"tools"}, p.VersionMetadata.Groups)
+			assert.Equal(t, "x86_64", p.FileMetadata.Architecture)
+			assert.Equal(t, "Gitea <pack@ag.er>", p.FileMetadata.Packager)
+			assert.EqualValues(t, 1678834800, p.FileMetadata.BuildDate)
+			assert.EqualValues(t, 123456, p.FileMetadata.InstalledSize)
+			assert.ElementsMatch(t, []string{"common"}, p.FileMetadata.Provides)
+			assert.ElementsMatch(t, []string{"gitea-git"}, p.FileMetadata.Conflicts)
+			assert.ElementsMatch(t, []string{"glibc", "git"}, p.FileMetadata.Depends)
+			assert.ElementsMatch(t, []string{"openssh: ssh support"}, p.FileMetadata.OptDepends)
+			assert.ElementsMatch(t, []string{"go"}, p.FileMetadata.MakeDepends)
+			assert.Empty(t, p.FileMetadata.CheckDepends)
+			assert.ElementsMatch(t, []string{"etc/gitea/app.ini"}, p.FileMetadata.Backup)
+		}
+	})
+}
@@ -0,0 +1,368 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attestation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var (
+	// ErrInvalidAttestation indicates an attestation which can't be parsed
+	ErrInvalidAttestation = util.NewInvalidArgumentErrorf("attestation is invalid")
+	// ErrSubjectMismatch indicates an attestation which is not about the file
+	ErrSubjectMismatch = util.NewInvalidArgumentErrorf("attestation subject doesn't match the file")
+	// ErrInvalidSignature indicates an attestation whose signature doesn't match the embedded certificate
+	ErrInvalidSignature = util.NewInvalidArgumentErrorf("attestation signature is invalid")
+)
+
+const (
+	// PayloadTypeInToto is the DSSE payload type of in-toto statements
+	PayloadTypeInToto = "application/vnd.in-toto+json"
+
+	statementTypePrefix      = "https://in-toto.io/Statement/"
+	sigstoreBundleTypePrefix = "application/vnd.dev.sigstore.bundle"
+)
+
+// Format is the format an attestation was uploaded in
+type Format string
+
+const (
+	// FormatStatement is a plain in-toto statement https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+	FormatStatement Format = "in-toto"
+	// FormatDSSE is a DSSE envelope containing an in-toto statement https://github.com/secure-systems-lab/dsse/blob/master/envelope.md
+	FormatDSSE Format = "dsse"
+	// FormatSigstoreBundle is a Sigstore bundle as used by npm https://docs.sigstore.dev/about/bundle/
+	FormatSigstoreBundle Format = "sigstore-bundle"
+	// FormatPEP740 is a PyPI attestation object https://peps.python.org/pep-0740/
+	FormatPEP740 Format = "pep740"
+)
+
+// Status is the verification status of an attestation
+type Status string
+
+const (
+	// StatusSignatureValidUntrusted means the subject matches the file and the signature matches the embedded certificate,
+	// but the certificate is not checked against a trust root, so anyone could have created it
+	StatusSignatureValidUntrusted Status = "signature_valid_untrusted"
+	// StatusUnverified means the subject matches the file but there is no signature or no certificate to check it
+	StatusUnverified Status = "unverified"
+	// StatusInvalid means the subject doesn't match the file or the signature is invalid
+	StatusInvalid Status = "invalid"
+)
+
+// Subject is a software artifact an in-toto statement is about
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Statement is an in-toto statement
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     any       `json:"predicate,omitempty"`
+}
+
+// HasSubjectDigest checks if the statement is about an artifact with the SHA256 digest
+func (s *Statement) HasSubjectDigest(hashSHA256 string) bool {
+	for _, subject := range s.Subject {
+		if digest, ok := subject.Digest["sha256"]; ok && strings.EqualFold(digest, hashSHA256) {
+			return true
+		}
+	}
+	return false
+}
+
+// Attestation is a parsed attestation
+type Attestation struct {
+	Format      Format
+	Statement   *Statement
+	PayloadType string
+	// Payload is the serialized statement the signature was created for
+	Payload []byte
+	// Signature is nil if the attestation is not signed
+	Signature []byte
+	// Certificate is the signing certificate, nil if the attestation doesn't contain one
+	Certificate *x509.Certificate
+}
+
+type dsseSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+type dsseEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []dsseSignature `json:"signatures"`
+}
+
+type sigstoreRawBytes struct {
+	RawBytes string `json:"rawBytes"`
+}
+
+type sigstoreBundle struct {
+	MediaType            string `json:"mediaType"`
+	VerificationMaterial struct {
+		Certificate          *sigstoreRawBytes `json:"certificate"`
+		X509CertificateChain *struct {
+			Certificates []sigstoreRawBytes `json:"certificates"`
+		} `json:"x509CertificateChain"`
+	} `json:"verificationMaterial"`
+	DSSEEnvelope *dsseEnvelope `json:"dsseEnvelope"`
+}
+
+type pep740Attestation struct {
+	Version              int `json:"version"`
+	VerificationMaterial struct {
+		Certificate string `json:"certificate"`
+	} `json:"verification_material"`
+	Envelope struct {
+		Statement string `json:"statement"`
+		Signature string `json:"signature"`
+	} `json:"envelope"`
+}
+
+// Parse parses an in-toto statement, a DSSE envelope, a Sigstore bundle or a PEP 740 attestation
+func Parse(data []byte) (*Attestation, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, ErrInvalidAttestation
+	}
+
+	_, hasType := fields["_type"]
+	_, hasMediaType := fields["mediaType"]
+	_, hasEnvelope := fields["envelope"]
+	_, hasPayloadType := fields["payloadType"]
+
+	var a *Attestation
+	var err error
+	switch {
+	case hasType:
+		a = &Attestation{Format: FormatStatement, PayloadType: PayloadTypeInToto, Payload: data}
+	case hasMediaType:
+		a, err = parseSigstoreBundle(data)
+	case hasEnvelope:
+		a, err = parsePEP740(data)
+	case hasPayloadType:
+		var env dsseEnvelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			return nil, ErrInvalidAttestation
+		}
+		a, err = parseDSSEEnvelope(&env)
+		if a != nil {
+			a.Format = FormatDSSE
+		}
+	default:
+		return nil, ErrInvalidAttestation
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if a.PayloadType != PayloadTypeInToto {
+		return nil, util.NewInvalidArgumentErrorf("unsupported attestation payload type %q", a.PayloadType)
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(a.Payload, &statement); err != nil {
+		return nil, ErrInvalidAttestation
+	}
+	if !strings.HasPrefix(statement.Type, statementTypePrefix) || len(statement.Subject) == 0 {
+		return nil, ErrInvalidAttestation
+	}
+	a.Statement = &statement
+
+	return a, nil
+}
+
+func parseDSSEEnvelope(env *dsseEnvelope) (*Attestation, error) {
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, ErrInvalidAttestation
+	}
+
+	a := &Attestation{
+		PayloadType: env.PayloadType,
+		Payload:     payload,
+	}
+	if len(env.Signatures) > 0 {
+		if a.Signature, err = base64.StdEncoding.DecodeString(env.Signatures[0].Sig); err != nil {
+			return nil, ErrInvalidAttestation
+		}
+	}
+	return a, nil
+}
+
+func parseSigstoreBundle(data []byte) (*Attestation, error) {
+	var bundle sigstoreBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, ErrInvalidAttestation
+	}
+	if !strings.HasPrefix(bundle.MediaType, sigstoreBundleTypePrefix) {
+		return nil, ErrInvalidAttestation
+	}
+	// bundles with a message signature don't contain a statement
+	if bundle.DSSEEnvelope == nil {
+		return nil, util.NewInvalidArgumentErrorf("sigstore bundle doesn't contain a DSSE envelope")
+	}
+
+	a, err := parseDSSEEnvelope(bundle.DSSEEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	a.Format = FormatSigstoreBundle
+
+	// v0.3 bundles contain the leaf certificate only, older versions the whole chain
+	var rawCertificate string
+	if bundle.VerificationMaterial.Certificate != nil {
+		rawCertificate = bundle.VerificationMaterial.Certificate.RawBytes
+	} else if chain := bundle.VerificationMaterial.X509CertificateChain; chain != nil && len(chain.Certificates) > 0 {
+		rawCertificate = chain.Certificates[0].RawBytes
+	}
+	if rawCertificate != "" {
+		if a.Certificate, err = parseCertificate(rawCertificate); err != nil {
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+func parsePEP740(data []byte) (*Attestation, error) {
+	var attestation pep740Attestation
+	if err := json.Unmarshal(data, &attestation); err != nil {
+		return nil, ErrInvalidAttestation
+	}
+	if attestation.Version != 1 {
+		return nil, util.NewInvalidArgumentErrorf("unsupported attestation version %d", attestation.Version)
+	}
+
+	statement, err := base64.StdEncoding.DecodeString(attestation.Envelope.Statement)
+	if err != nil {
+		return nil, ErrInvalidAttestation
+	}
+	signature, err := base64.StdEncoding.DecodeString(attestation.Envelope.Signature)
+	if err != nil {
+		return nil, ErrInvalidAttestation
+	}
+	certificate, err := parseCertificate(attestation.VerificationMaterial.Certificate)
+	if err != nil {
+		return nil, err
+	}
+
+	// the signature of PEP 740 attestations is a DSSE signature of the statement
+	return &Attestation{
+		Format:      FormatPEP740,
+		PayloadType: PayloadTypeInToto,
+		Payload:     statement,
+		Signature:   signature,
+		Certificate: certificate,
+	}, nil
+}
+
+func parseCertificate(s string) (*x509.Certificate, error) {
+	der, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidAttestation
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid attestation certificate: %v", err)
+	}
+	return certificate, nil
+}
+
+// Verify checks that the attestation is about the file with the SHA256 digest and that the signature matches the embedded certificate.
+// The certificate itself is not checked against the Sigstore trust root and transparency log, so a valid signature
+// doesn't prove who signed the attestation.
+func (a *Attestation) Verify(hashSHA256 string) (Status, error) {
+	if !a.Statement.HasSubjectDigest(hashSHA256) {
+		return StatusInvalid, ErrSubjectMismatch
+	}
+	if a.Signature == nil || a.Certificate == nil {
+		return StatusUnverified, nil
+	}
+	if !verifySignature(a.Certificate.PublicKey, preAuthEncoding(a.PayloadType, a.Payload), a.Signature) {
+		return StatusInvalid, ErrInvalidSignature
+	}
+	return StatusSignatureValidUntrusted, nil
+}
+
+// preAuthEncoding returns the message which is signed in a DSSE envelope
+func preAuthEncoding(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+func verifySignature(publicKey crypto.PublicKey, message, signature []byte) bool {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		var digest []byte
+		switch key.Curve.Params().BitSize {
+		case 384:
+			sum := sha512.Sum384(message)
+			digest = sum[:]
+		case 521:
+			sum := sha512.Sum512(message)
+			digest = sum[:]
+		default:
+			sum := sha256.Sum256(message)
+			digest = sum[:]
+		}
+		return ecdsa.VerifyASN1(key, digest, signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, signature)
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], signature) == nil
+	}
+	return false
+}
+
+var (
+	// https://github.com/sigstore/fulcio/blob/main/docs/oid-info.md
+	oidIssuerV1 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Signer returns the identity (e.g. the URL of a CI workflow) and the OIDC issuer claimed by the signing certificate.
+// The certificate is not trusted, so the values must not be presented as the proven signer.
+func (a *Attestation) Signer() (identity, issuer string) {
+	if a.Certificate == nil {
+		return "", ""
+	}
+
+	if len(a.Certificate.URIs) > 0 {
+		identity = a.Certificate.URIs[0].String()
+	} else if len(a.Certificate.EmailAddresses) > 0 {
+		identity = a.Certificate.EmailAddresses[0]
+	}
+
+	for _, ext := range a.Certificate.Extensions {
+		if ext.Id.Equal(oidIssuerV2) {
+			var s string
+			if _, err := asn1.Unmarshal(ext.Value, &s); err == nil {
+				issuer = s
+				break
+			}
+		} else if ext.Id.Equal(oidIssuerV1) && issuer == "" {
+			issuer = string(ext.Value)
+		}
+	}
+	return identity, issuer
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAndVerify(t *testing.T) {
+	content := []byte("package content")
+	sum := sha256.Sum256(content)
+	hashSHA256 := hex.EncodeToString(sum[:])
+
+	identity := "https://github.com/gitea/test/.github/workflows/release.yml@refs/heads/main"
+	issuer := "https://token.actions.githubusercontent.com"
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	issuerValue, err := asn1.Marshal(issuer)
+	assert.NoError(t, err)
+	identityURL, _ := url.Parse(identity)
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(10 * time.Minute),
+		URIs:         []*url.URL{identityURL},
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidIssuerV2, Value: issuerValue},
+		},
+	}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certificate := base64.StdEncoding.EncodeToString(der)
+
+	statement := []byte(fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"test-1.0.0.tgz","digest":{"sha256":"%s"}}],"predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`, hashSHA256))
+	otherStatement := []byte(`{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"test-1.0.0.tgz","digest":{"sha256":"0000"}}],"predicateType":"https://slsa.dev/provenance/v1"}`)
+
+	sign := func(payload []byte) string {
+		digest := sha256.Sum256(preAuthEncoding(PayloadTypeInToto, payload))
+		sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+		assert.NoError(t, err)
+		return base64.StdEncoding.EncodeToString(sig)
+	}
+
+	sigstoreBundle := func(payload []byte, sig string) []byte {
+		b, _ := json.Marshal(map[string]any{
+			"mediaType": "application/vnd.dev.sigstore.bundle.v0.3+json",
+			"verificationMaterial": map[string]any{
+				"certificate": map[string]string{"rawBytes": certificate},
+			},
+			"dsseEnvelope": map[string]any{
+				"payloadType": PayloadTypeInToto,
+				"payload":     base64.StdEncoding.EncodeToString(payload),
+				"signatures":  []map[string]string{{"sig": sig}},
+			},
+		})
+		return b
+	}
+
+	pep740 := func(payload []byte, sig string) []byte {
+		b, _ := json.Marshal(map[string]any{
+			"version": 1,
+			"verification_material": map[string]any{
+				"certificate":          certificate,
+				"transparency_entries": []any{},
+			},
+			"envelope": map[string]string{
+				"statement": base64.StdEncoding.EncodeToString(payload),
+				"signature": sig,
+			},
+		})
+		return b
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, data := range []string{
+			``,
+			`[]`,
+			`{}`,
+			`{"_type":"https://example.com/Statement"}`,
+			`{"_type":"https://in-toto.io/Statement/v1","subject":[]}`,
+			`{"payloadType":"text/plain","payload":"dGVzdA==","signatures":[]}`,
+			`{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json","messageSignature":{}}`,
+		} {
+			a, err := Parse([]byte(data))
+			assert.Nil(t, a, data)
+			assert.Error(t, err, data)
+		}
+	})
+
+	t.Run("Statement", func(t *testing.T) {
+		a, err := Parse(statement)
+		assert.NoError(t, err)
+		assert.Equal(t, FormatStatement, a.Format)
+		assert.Equal(t, "https://slsa.dev/provenance/v1", a.Statement.PredicateType)
+		assert.Nil(t, a.Signature)
+
+		status, err := a.Verify(hashSHA256)
+		assert.NoError(t, err)
+		assert.Equal(t, StatusUnverified, status)
+
+		status, err = a.Verify("0000")
+		assert.ErrorIs(t, err, ErrSubjectMismatch)
+		assert.Equal(t, StatusInvalid, status)
+	})
+
+	t.Run("DSSE", func(t *testing.T) {
+		b, _ := json.Marshal(map[string]any{
+			"payloadType": PayloadTypeInToto,
+			"payload":     base64.StdEncoding.EncodeToString(statement),
+			"signatures":  []map[string]string{{"keyid": "", "sig": sign(statement)}},
+		})
+
+		a, err := Parse(b)
+		assert.NoError(t, err)
+		assert.Equal(t, FormatDSSE, a.Format)
+		assert.NotNil(t, a.Signature)
+		assert.Nil(t, a.Certificate)
+
+		// the signature can't be checked without a certificate
+		status, err := a.Verify(hashSHA256)
+		assert.NoError(t, err)
+		assert.Equal(t, StatusUnverified, status)
+	})
+
+	t.Run("SigstoreBundle", func(t *testing.T) {
+		a, err := Parse(sigstoreBundle(statement, sign(statement)))
+		assert.NoError(t, err)
+		assert.Equal(t, FormatSigstoreBundle, a.Format)
+
+		status, err := a.Verify(hashSHA256)
+		assert.NoError(t, err)
+		assert.Equal(t, StatusSignatureValidUntrusted, status)
+
+		signerIdentity, signerIssuer := a.Signer()
+		assert.Equal(t, identity, signerIdentity)
+		assert.Equal(t, issuer, signerIssuer)
+
+		// the signature of another statement
+		a, err = Parse(sigstoreBundle(statement, sign(otherStatement)))
+		assert.NoError(t, err)
+		status, err = a.Verify(hashSHA256)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+		assert.Equal(t, StatusInvalid, status)
+	})
+
+	t.Run("PEP740", func(t *testing.T) {
+		a, err := Parse(pep740(statement, sign(statement)))
+		assert.NoError(t, err)
+		assert.Equal(t, FormatPEP740, a.Format)
+
+		status, err := a.Verify(hashSHA256)
+		assert.NoError(t, err)
+		assert.Equal(t, StatusSignatureValidUntrusted, status)
+
+		a, err = Parse(pep740(otherStatement, sign(otherStatement)))
+		assert.NoError(t, err)
+		status, err = a.Verify(hashSHA256)
+		assert.ErrorIs(t, err, ErrSubjectMismatch)
+		assert.Equal(t, StatusInvalid, status)
+	})
+}
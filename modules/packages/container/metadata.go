@@ -21,6 +21,7 @@ const (
 	PropertyMediaType         = "container.mediatype"
 	PropertyManifestTagged    = "container.manifest.tagged"
 	PropertyManifestReference = "container.manifest.reference"
+	PropertyManifestSubject   = "container.manifest.subject"
 
 	DefaultPlatform = "linux/amd64"
 
@@ -63,6 +64,8 @@ type Metadata struct {
 	Labels           map[string]string `json:"labels,omitempty"`
 	ImageLayers      []string          `json:"layer_creation,omitempty"`
 	Manifests        []*Manifest       `json:"manifests,omitempty"`
+	ArtifactType     string            `json:"artifact_type,omitempty"`
+	Annotations      map[string]string `json:"annotations,omitempty"`
 }
 
 type Manifest struct {
@@ -76,6 +79,10 @@ func ParseImageConfig(mt string, r io.Reader) (*Metadata, error) {
 	if strings.EqualFold(mt, helm.ConfigMediaType) {
 		return parseHelmConfig(r)
 	}
+	if strings.EqualFold(mt, oci.MediaTypeEmptyJSON) {
+		// artifacts without a config use the empty descriptor
+		return &Metadata{Type: TypeOCI}, nil
+	}
 
 	// fallback to OCI Image Config
 	return parseOCIImageConfig(r)
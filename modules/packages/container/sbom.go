@@ -0,0 +1,221 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	MediaTypeSPDX      = "application/spdx+json"
+	MediaTypeCycloneDX = "application/vnd.cyclonedx+json"
+	MediaTypeInToto    = "application/vnd.in-toto+json"
+
+	PredicateTypeSPDX      = "https://spdx.dev/Document"
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+
+	AnnotationInTotoPredicateType = "in-toto.io/predicate-type"
+
+	// Annotations used by BuildKit to link attestation manifests to the image manifest they describe
+	AnnotationDockerReferenceType   = "vnd.docker.reference.type"
+	AnnotationDockerReferenceDigest = "vnd.docker.reference.digest"
+	DockerReferenceTypeAttestation  = "attestation-manifest"
+
+	// MaxSBOMSize is the maximum size of a SBOM blob which gets parsed
+	MaxSBOMSize = 32 * 1024 * 1024
+)
+
+var ErrInvalidSBOM = errors.New("SBOM is invalid")
+
+// Component represents a software component listed in a SBOM
+type Component struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Type    string `json:"type,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+func isSBOMMediaType(mt string) bool {
+	if parsed, _, err := mime.ParseMediaType(mt); err == nil {
+		mt = parsed
+	}
+	return strings.EqualFold(mt, MediaTypeSPDX) || strings.EqualFold(mt, MediaTypeCycloneDX)
+}
+
+func isSBOMPredicateType(pt string) bool {
+	// the predicate type may contain a version suffix like https://spdx.dev/Document/v2.3
+	return strings.HasPrefix(pt, PredicateTypeSPDX) || strings.HasPrefix(pt, PredicateTypeCycloneDX)
+}
+
+// IsSBOMArtifactType checks if the artifact type of a manifest describes a SBOM
+func IsSBOMArtifactType(artifactType string) bool {
+	return isSBOMMediaType(artifactType)
+}
+
+// IsSBOMLayer checks if the layer contains a SBOM.
+// SBOM layers are detected by their media type or by the in-toto predicate type annotation.
+func IsSBOMLayer(layer oci.Descriptor) bool {
+	if isSBOMMediaType(layer.MediaType) {
+		return true
+	}
+	return isSBOMPredicateType(layer.Annotations[AnnotationInTotoPredicateType])
+}
+
+type sbomDocument struct {
+	// in-toto statement
+	PredicateType string        `json:"predicateType"`
+	Predicate     *sbomDocument `json:"predicate"`
+	// SPDX
+	SPDXVersion string         `json:"spdxVersion"`
+	Packages    []*spdxPackage `json:"packages"`
+	// CycloneDX
+	BOMFormat  string                `json:"bomFormat"`
+	Components []*cycloneDXComponent `json:"components"`
+}
+
+type spdxPackage struct {
+	Name             string `json:"name"`
+	VersionInfo      string `json:"versionInfo"`
+	LicenseConcluded string `json:"licenseConcluded"`
+	LicenseDeclared  string `json:"licenseDeclared"`
+	Purpose          string `json:"primaryPackagePurpose"`
+	ExternalRefs     []struct {
+		ReferenceType    string `json:"referenceType"`
+		ReferenceLocator string `json:"referenceLocator"`
+	} `json:"externalRefs"`
+}
+
+type cycloneDXComponent struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	PURL     string `json:"purl"`
+	Licenses []struct {
+		License struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"license"`
+		Expression string `json:"expression"`
+	} `json:"licenses"`
+	Components []*cycloneDXComponent `json:"components"`
+}
+
+// ParseSBOM parses a SPDX or CycloneDX JSON document and returns the listed components.
+// Documents wrapped in an in-toto statement are supported too.
+func ParseSBOM(r io.Reader) ([]*Component, error) {
+	var doc sbomDocument
+	if err := json.NewDecoder(io.LimitReader(r, MaxSBOMSize)).Decode(&doc); err != nil {
+		return nil, errors.Join(ErrInvalidSBOM, err)
+	}
+
+	if doc.PredicateType != "" {
+		if !isSBOMPredicateType(doc.PredicateType) || doc.Predicate == nil {
+			return nil, ErrInvalidSBOM
+		}
+		doc = *doc.Predicate
+	}
+
+	var components []*Component
+	switch {
+	case doc.SPDXVersion != "":
+		components = parseSPDXPackages(doc.Packages)
+	case strings.EqualFold(doc.BOMFormat, "CycloneDX"):
+		components = parseCycloneDXComponents(nil, doc.Components)
+	default:
+		return nil, ErrInvalidSBOM
+	}
+
+	return deduplicateComponents(components), nil
+}
+
+func parseSPDXPackages(packages []*spdxPackage) []*Component {
+	components := make([]*Component, 0, len(packages))
+	for _, p := range packages {
+		if p.Name == "" {
+			continue
+		}
+
+		c := &Component{
+			Name:    p.Name,
+			Version: p.VersionInfo,
+			Type:    strings.ToLower(p.Purpose),
+			License: spdxLicense(p.LicenseConcluded),
+		}
+		if c.License == "" {
+			c.License = spdxLicense(p.LicenseDeclared)
+		}
+		for _, ref := range p.ExternalRefs {
+			if ref.ReferenceType == "purl" {
+				c.PURL = ref.ReferenceLocator
+				break
+			}
+		}
+		components = append(components, c)
+	}
+	return components
+}
+
+func spdxLicense(license string) string {
+	if license == "NOASSERTION" || license == "NONE" {
+		return ""
+	}
+	return license
+}
+
+func parseCycloneDXComponents(components []*Component, cdxComponents []*cycloneDXComponent) []*Component {
+	for _, cc := range cdxComponents {
+		if cc.Name != "" {
+			licenses := make([]string, 0, len(cc.Licenses))
+			for _, l := range cc.Licenses {
+				switch {
+				case l.Expression != "":
+					licenses = append(licenses, l.Expression)
+				case l.License.ID != "":
+					licenses = append(licenses, l.License.ID)
+				case l.License.Name != "":
+					licenses = append(licenses, l.License.Name)
+				}
+			}
+
+			components = append(components, &Component{
+				Name:    cc.Name,
+				Version: cc.Version,
+				Type:    cc.Type,
+				PURL:    cc.PURL,
+				License: strings.Join(licenses, " AND "),
+			})
+		}
+
+		components = parseCycloneDXComponents(components, cc.Components)
+	}
+	return components
+}
+
+func deduplicateComponents(components []*Component) []*Component {
+	type key struct {
+		Name    string
+		Version string
+		PURL    string
+	}
+
+	seen := make(map[key]bool, len(components))
+	result := make([]*Component, 0, len(components))
+	for _, c := range components {
+		k := key{c.Name, c.Version, c.PURL}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		result = append(result, c)
+	}
+	return result
+}
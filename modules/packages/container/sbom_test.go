@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"strings"
+	"testing"
+
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	sbomSPDX = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {"name": "alpine-baselayout", "versionInfo": "3.4.3-r1", "licenseConcluded": "GPL-2.0-only", "externalRefs": [{"referenceCategory": "PACKAGE-MANAGER", "referenceType": "purl", "referenceLocator": "pkg:apk/alpine/alpine-baselayout@3.4.3-r1"}]},
+    {"name": "busybox", "versionInfo": "1.36.1-r5", "licenseConcluded": "NOASSERTION", "licenseDeclared": "GPL-2.0-only", "primaryPackagePurpose": "LIBRARY"},
+    {"name": "busybox", "versionInfo": "1.36.1-r5", "primaryPackagePurpose": "LIBRARY"},
+    {"versionInfo": "1.0"}
+  ]
+}`
+	sbomCycloneDX = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.5",
+  "components": [
+    {"type": "library", "name": "openssl", "version": "3.1.4", "purl": "pkg:apk/alpine/openssl@3.1.4", "licenses": [{"license": {"id": "Apache-2.0"}}],
+     "components": [{"type": "library", "name": "libcrypto3", "version": "3.1.4", "licenses": [{"expression": "Apache-2.0 OR MIT"}]}]}
+  ]
+}`
+)
+
+func TestParseSBOM(t *testing.T) {
+	t.Run("SPDX", func(t *testing.T) {
+		components, err := ParseSBOM(strings.NewReader(sbomSPDX))
+		assert.NoError(t, err)
+		assert.Equal(t, []*Component{
+			{Name: "alpine-baselayout", Version: "3.4.3-r1", License: "GPL-2.0-only", PURL: "pkg:apk/alpine/alpine-baselayout@3.4.3-r1"},
+			{Name: "busybox", Version: "1.36.1-r5", Type: "library", License: "GPL-2.0-only"},
+		}, components)
+	})
+
+	t.Run("CycloneDX", func(t *testing.T) {
+		components, err := ParseSBOM(strings.NewReader(sbomCycloneDX))
+		assert.NoError(t, err)
+		assert.Equal(t, []*Component{
+			{Name: "openssl", Version: "3.1.4", Type: "library", License: "Apache-2.0", PURL: "pkg:apk/alpine/openssl@3.1.4"},
+			{Name: "libcrypto3", Version: "3.1.4", Type: "library", License: "Apache-2.0 OR MIT"},
+		}, components)
+	})
+
+	t.Run("InToto", func(t *testing.T) {
+		statement := `{"_type": "https://in-toto.io/Statement/v0.1", "predicateType": "https://spdx.dev/Document", "predicate": ` + sbomSPDX + `}`
+
+		components, err := ParseSBOM(strings.NewReader(statement))
+		assert.NoError(t, err)
+		assert.Len(t, components, 2)
+
+		statement = `{"_type": "https://in-toto.io/Statement/v0.1", "predicateType": "https://slsa.dev/provenance/v0.2", "predicate": {}}`
+
+		_, err = ParseSBOM(strings.NewReader(statement))
+		assert.ErrorIs(t, err, ErrInvalidSBOM)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParseSBOM(strings.NewReader(`{"name": "not a sbom"}`))
+		assert.ErrorIs(t, err, ErrInvalidSBOM)
+
+		_, err = ParseSBOM(strings.NewReader(`{`))
+		assert.ErrorIs(t, err, ErrInvalidSBOM)
+	})
+}
+
+func TestIsSBOMLayer(t *testing.T) {
+	assert.True(t, IsSBOMLayer(oci.Descriptor{MediaType: MediaTypeSPDX}))
+	assert.True(t, IsSBOMLayer(oci.Descriptor{MediaType: MediaTypeCycloneDX + "; version=1.5"}))
+	assert.True(t, IsSBOMLayer(oci.Descriptor{
+		MediaType:   MediaTypeInToto,
+		Annotations: map[string]string{AnnotationInTotoPredicateType: "https://spdx.dev/Document"},
+	}))
+	assert.False(t, IsSBOMLayer(oci.Descriptor{
+		MediaType:   MediaTypeInToto,
+		Annotations: map[string]string{AnnotationInTotoPredicateType: "https://slsa.dev/provenance/v0.2"},
+	}))
+	assert.False(t, IsSBOMLayer(oci.Descriptor{MediaType: oci.MediaTypeImageLayerGzip}))
+}
@@ -44,6 +44,8 @@ type Package struct {
 	Metadata Metadata
 	Filename string
 	Data     []byte
+	// Provenance is the Sigstore bundle sent by "npm publish --provenance", empty if there is none
+	Provenance string
 }
 
 // PackageMetadata https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#package
@@ -96,6 +98,30 @@ type PackageDistribution struct {
 	FileCount    int    `json:"fileCount,omitempty"`
 	UnpackedSize int    `json:"unpackedSize,omitempty"`
 	NpmSignature string `json:"npm-signature,omitempty"`
+	// Attestations is only present if the package has been published with provenance
+	Attestations *PackageDistributionAttestations `json:"attestations,omitempty"`
+}
+
+// PackageDistributionAttestations https://github.com/npm/registry/blob/main/docs/responses/package-metadata.md
+type PackageDistributionAttestations struct {
+	URL        string                        `json:"url"`
+	Provenance *PackageAttestationProvenance `json:"provenance,omitempty"`
+}
+
+// PackageAttestationProvenance describes the provenance attestation of a package version
+type PackageAttestationProvenance struct {
+	PredicateType string `json:"predicateType"`
+}
+
+// PackageAttestations is the response of the attestations endpoint which is used by "npm audit signatures"
+type PackageAttestations struct {
+	Attestations []*PackageAttestation `json:"attestations"`
+}
+
+// PackageAttestation is a Sigstore bundle of a package version
+type PackageAttestation struct {
+	PredicateType string `json:"predicateType"`
+	Bundle        any    `json:"bundle"`
 }
 
 type PackageSearch struct {
@@ -165,6 +191,27 @@ type Repository struct {
 	URL  string `json:"url"`
 }
 
+// UnmarshalJSON is needed because Repository objects can be strings or objects
+func (r *Repository) UnmarshalJSON(data []byte) error {
+	switch data[0] {
+	case '"':
+		if err := json.Unmarshal(data, &r.URL); err != nil {
+			return err
+		}
+	case '{':
+		var tmp struct {
+			Type string `json:"type"`
+			URL  string `json:"url"`
+		}
+		if err := json.Unmarshal(data, &tmp); err != nil {
+			return err
+		}
+		r.Type = tmp.Type
+		r.URL = tmp.URL
+	}
+	return nil
+}
+
 // PackageAttachment https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#package
 type PackageAttachment struct {
 	ContentType string `json:"content_type"`
@@ -194,53 +241,28 @@ func ParsePackage(r io.Reader) (*Package, error) {
 			return nil, ErrInvalidPackageVersion
 		}
 
-		scope := ""
-		name := meta.Name
-		nameParts := strings.SplitN(meta.Name, "/", 2)
-		if len(nameParts) == 2 {
-			scope = nameParts[0]
-			name = nameParts[1]
-		}
-
-		if !validation.IsValidURL(meta.Homepage) {
-			meta.Homepage = ""
-		}
-
 		p := &Package{
 			Name:     meta.Name,
 			Version:  v.String(),
 			DistTags: make([]string, 0, 1),
-			Metadata: Metadata{
-				Scope:                   scope,
-				Name:                    name,
-				Description:             meta.Description,
-				Author:                  meta.Author.Name,
-				License:                 meta.License,
-				ProjectURL:              meta.Homepage,
-				Keywords:                meta.Keywords,
-				Dependencies:            meta.Dependencies,
-				BundleDependencies:      meta.BundleDependencies,
-				DevelopmentDependencies: meta.DevDependencies,
-				PeerDependencies:        meta.PeerDependencies,
-				OptionalDependencies:    meta.OptionalDependencies,
-				Bin:                     meta.Bin,
-				Readme:                  meta.Readme,
-				Repository:              meta.Repository,
-			},
+			Metadata: CreateMetadata(meta),
 		}
 
 		for tag := range upload.DistTags {
 			p.DistTags = append(p.DistTags, tag)
 		}
 
-		p.Filename = strings.ToLower(fmt.Sprintf("%s-%s.tgz", name, p.Version))
+		p.Filename = strings.ToLower(fmt.Sprintf("%s-%s.tgz", p.Metadata.Name, p.Version))
 
-		attachment := func() *PackageAttachment {
-			for _, a := range upload.Attachments {
-				return a
+		var attachment *PackageAttachment
+		for name, a := range upload.Attachments {
+			// the provenance bundle is sent as additional attachment
+			if strings.HasSuffix(name, ".sigstore") {
+				p.Provenance = a.Data
+			} else if attachment == nil {
+				attachment = a
 			}
-			return nil
-		}()
+		}
 		if attachment == nil || len(attachment.Data) == 0 {
 			return nil, ErrInvalidAttachment
 		}
@@ -251,25 +273,10 @@ func ParsePackage(r io.Reader) (*Package, error) {
 		}
 		p.Data = data
 
-		integrity := strings.SplitN(meta.Dist.Integrity, "-", 2)
-		if len(integrity) != 2 {
-			return nil, ErrInvalidIntegrity
-		}
-		integrityHash, err := base64.StdEncoding.DecodeString(integrity[1])
-		if err != nil {
-			return nil, ErrInvalidIntegrity
-		}
-		var hash []byte
-		switch integrity[0] {
-		case "sha1":
-			tmp := sha1.Sum(data)
-			hash = tmp[:]
-		case "sha512":
-			tmp := sha512.Sum512(data)
-			hash = tmp[:]
-		}
-		if !bytes.Equal(integrityHash, hash) {
-			return nil, ErrInvalidIntegrity
+		hashSHA1 := sha1.Sum(data)
+		hashSHA512 := sha512.Sum512(data)
+		if err := ValidateIntegrity(meta.Dist.Integrity, hashSHA1[:], hashSHA512[:]); err != nil {
+			return nil, err
 		}
 
 		return p, nil
@@ -278,6 +285,63 @@ func ParsePackage(r io.Reader) (*Package, error) {
 	return nil, ErrInvalidPackage
 }
 
+// CreateMetadata creates the package metadata from the description of a single version
+func CreateMetadata(meta *PackageMetadataVersion) Metadata {
+	scope := ""
+	name := meta.Name
+	nameParts := strings.SplitN(meta.Name, "/", 2)
+	if len(nameParts) == 2 {
+		scope = nameParts[0]
+		name = nameParts[1]
+	}
+
+	homepage := meta.Homepage
+	if !validation.IsValidURL(homepage) {
+		homepage = ""
+	}
+
+	return Metadata{
+		Scope:                   scope,
+		Name:                    name,
+		Description:             meta.Description,
+		Author:                  meta.Author.Name,
+		License:                 meta.License,
+		ProjectURL:              homepage,
+		Keywords:                meta.Keywords,
+		Dependencies:            meta.Dependencies,
+		BundleDependencies:      meta.BundleDependencies,
+		DevelopmentDependencies: meta.DevDependencies,
+		PeerDependencies:        meta.PeerDependencies,
+		OptionalDependencies:    meta.OptionalDependencies,
+		Bin:                     meta.Bin,
+		Readme:                  meta.Readme,
+		Repository:              meta.Repository,
+	}
+}
+
+// ValidateIntegrity checks the sha1 or sha512 integrity string against the hashes of the package data
+func ValidateIntegrity(integrity string, hashSHA1, hashSHA512 []byte) error {
+	parts := strings.SplitN(integrity, "-", 2)
+	if len(parts) != 2 {
+		return ErrInvalidIntegrity
+	}
+	integrityHash, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalidIntegrity
+	}
+	var hash []byte
+	switch parts[0] {
+	case "sha1":
+		hash = hashSHA1
+	case "sha512":
+		hash = hashSHA512
+	}
+	if !bytes.Equal(integrityHash, hash) {
+		return ErrInvalidIntegrity
+	}
+	return nil
+}
+
 func validateName(name string) bool {
 	if strings.TrimSpace(name) != name {
 		return false
@@ -298,4 +298,41 @@ func TestParsePackage(t *testing.T) {
 		assert.Equal(t, repository.Type, p.Metadata.Repository.Type)
 		assert.Equal(t, repository.URL, p.Metadata.Repository.URL)
 	})
+
+	t.Run("ValidWithProvenance", func(t *testing.T) {
+		filename := fmt.Sprintf("%s-%s.tgz", packageFullName, packageVersion)
+		provenance := `{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json"}`
+		b, _ := json.Marshal(packageUpload{
+			PackageMetadata: PackageMetadata{
+				ID:   packageFullName,
+				Name: packageFullName,
+				Versions: map[string]*PackageMetadataVersion{
+					packageVersion: {
+						Name:    packageFullName,
+						Version: packageVersion,
+						Dist: PackageDistribution{
+							Integrity: integrity,
+						},
+					},
+				},
+			},
+			Attachments: map[string]*PackageAttachment{
+				fmt.Sprintf("%s-%s.sigstore", packageFullName, packageVersion): {
+					ContentType: "application/vnd.dev.sigstore.bundle.v0.3+json",
+					Data:        provenance,
+				},
+				filename: {
+					Data: data,
+				},
+			},
+		})
+
+		p, err := ParsePackage(bytes.NewReader(b))
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+
+		b, _ = base64.StdEncoding.DecodeString(data)
+		assert.Equal(t, b, p.Data)
+		assert.Equal(t, provenance, p.Provenance)
+	})
 }
@@ -0,0 +1,59 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pypi
+
+import (
+	"strings"
+)
+
+// Provenance https://peps.python.org/pep-0740/#provenance-objects
+type Provenance struct {
+	Version            int                  `json:"version"`
+	AttestationBundles []*AttestationBundle `json:"attestation_bundles"`
+}
+
+// AttestationBundle contains the attestations of a single publisher
+type AttestationBundle struct {
+	Publisher    map[string]string `json:"publisher"`
+	Attestations []any             `json:"attestations"`
+}
+
+// NewPublisher describes the Trusted Publisher which signed an attestation by the identity and OIDC issuer of the signing certificate
+// https://docs.pypi.org/attestations/publish/v1/
+func NewPublisher(identity, issuer string) map[string]string {
+	switch issuer {
+	case "https://token.actions.githubusercontent.com":
+		// https://github.com/{owner}/{repo}/.github/workflows/{workflow}@{ref}
+		if path, ok := strings.CutPrefix(identity, "https://github.com/"); ok {
+			path, _, _ = strings.Cut(path, "@")
+			if repository, workflow, ok := strings.Cut(path, "/.github/workflows/"); ok {
+				return map[string]string{
+					"kind":       "GitHub",
+					"repository": repository,
+					"workflow":   workflow,
+				}
+			}
+		}
+	case "https://gitlab.com":
+		// https://gitlab.com/{namespace}/{project}//{path of the ci config}@{ref}
+		if path, ok := strings.CutPrefix(identity, "https://gitlab.com/"); ok {
+			path, _, _ = strings.Cut(path, "@")
+			if repository, workflow, ok := strings.Cut(path, "//"); ok {
+				return map[string]string{
+					"kind":              "GitLab",
+					"repository":        repository,
+					"workflow_filepath": workflow,
+				}
+			}
+		}
+	case "https://accounts.google.com":
+		return map[string]string{
+			"kind":  "Google",
+			"email": identity,
+		}
+	}
+	return map[string]string{
+		"kind": issuer,
+	}
+}
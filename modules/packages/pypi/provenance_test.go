@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pypi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPublisher(t *testing.T) {
+	cases := []struct {
+		Identity string
+		Issuer   string
+		Expected map[string]string
+	}{
+		{
+			Identity: "https://github.com/gitea/test/.github/workflows/release.yml@refs/tags/v1.0.0",
+			Issuer:   "https://token.actions.githubusercontent.com",
+			Expected: map[string]string{"kind": "GitHub", "repository": "gitea/test", "workflow": "release.yml"},
+		},
+		{
+			Identity: "https://gitlab.com/gitea/sub/test//.gitlab-ci.yml@refs/heads/main",
+			Issuer:   "https://gitlab.com",
+			Expected: map[string]string{"kind": "GitLab", "repository": "gitea/sub/test", "workflow_filepath": ".gitlab-ci.yml"},
+		},
+		{
+			Identity: "release@example.com",
+			Issuer:   "https://accounts.google.com",
+			Expected: map[string]string{"kind": "Google", "email": "release@example.com"},
+		},
+		{
+			Identity: "https://github.com/gitea/test",
+			Issuer:   "https://token.actions.githubusercontent.com",
+			Expected: map[string]string{"kind": "https://token.actions.githubusercontent.com"},
+		},
+		{
+			Identity: "https://example.com/ci",
+			Issuer:   "https://example.com",
+			Expected: map[string]string{"kind": "https://example.com"},
+		},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.Expected, NewPublisher(c.Identity, c.Issuer), c.Identity)
+	}
+}
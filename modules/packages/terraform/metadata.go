@@ -0,0 +1,408 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	PropertyOS           = "terraform.os"
+	PropertyArchitecture = "terraform.architecture"
+
+	SettingKeyPrivate = "terraform.key.private"
+	SettingKeyPublic  = "terraform.key.public"
+
+	KindModule   = "module"
+	KindProvider = "provider"
+
+	ShasumsFilename          = "SHA256SUMS"
+	ShasumsSignatureFilename = ShasumsFilename + ".sig"
+
+	// DefaultProtocol is the plugin protocol version used by providers built with the current SDKs
+	DefaultProtocol = "5.0"
+)
+
+var (
+	ErrInvalidName            = util.NewInvalidArgumentErrorf("name is invalid")
+	ErrInvalidVersion         = util.NewInvalidArgumentErrorf("version is invalid")
+	ErrInvalidPlatform        = util.NewInvalidArgumentErrorf("platform is invalid")
+	ErrInvalidProtocol        = util.NewInvalidArgumentErrorf("protocol is invalid")
+	ErrMissingProviderBinary  = util.NewInvalidArgumentErrorf("provider binary is missing")
+	ErrMissingModuleFiles     = util.NewInvalidArgumentErrorf("module has no configuration files")
+	ErrUnsupportedArchiveType = util.NewInvalidArgumentErrorf("archive type is not supported")
+
+	// https://developer.hashicorp.com/terraform/internals/module-registry-protocol#module-addresses
+	moduleNamePattern = regexp.MustCompile(`\A[0-9A-Za-z](?:[0-9A-Za-z_-]{0,62}[0-9A-Za-z])?\z`)
+	// https://developer.hashicorp.com/terraform/internals/provider-registry-protocol#provider-addresses
+	providerTypePattern = regexp.MustCompile(`\A[a-z0-9](?:[a-z0-9-]{0,62}[a-z0-9])?\z`)
+	platformPattern     = regexp.MustCompile(`\A[a-z0-9]+\z`)
+	protocolPattern     = regexp.MustCompile(`\A[0-9]+\.[0-9]+\z`)
+
+	blockPattern             = regexp.MustCompile(`\A(variable|output)\s+"([^"]+)"\s*\{`)
+	terraformBlockPattern    = regexp.MustCompile(`\Aterraform\s*\{`)
+	requiredProvidersPattern = regexp.MustCompile(`\Arequired_providers\s*\{`)
+	providerEntryPattern     = regexp.MustCompile(`\A([0-9A-Za-z_-]+)\s*=\s*\{`)
+	attributePattern         = regexp.MustCompile(`\A([0-9A-Za-z_-]+)\s*=\s*(.*)\z`)
+)
+
+// Metadata represents the metadata of a Terraform module or provider version
+type Metadata struct {
+	Kind              string             `json:"kind"`
+	Description       string             `json:"description,omitempty"`
+	Readme            string             `json:"readme,omitempty"`
+	Variables         []Variable         `json:"variables,omitempty"`
+	Outputs           []Output           `json:"outputs,omitempty"`
+	RequiredProviders []RequiredProvider `json:"required_providers,omitempty"`
+	Protocols         []string           `json:"protocols,omitempty"`
+}
+
+// Variable is an input variable of a module
+type Variable struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+}
+
+// Output is an output value of a module
+type Output struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// RequiredProvider is a provider requirement of a module
+type RequiredProvider struct {
+	Name    string `json:"name"`
+	Source  string `json:"source,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// IsValidModuleName checks if the name is a valid module name or target system
+func IsValidModuleName(name string) bool {
+	return moduleNamePattern.MatchString(name)
+}
+
+// IsValidProviderType checks if the name is a valid provider type
+func IsValidProviderType(name string) bool {
+	return providerTypePattern.MatchString(name)
+}
+
+// IsValidPlatform checks if os and architecture are valid platform parts
+func IsValidPlatform(os, arch string) bool {
+	return platformPattern.MatchString(os) && platformPattern.MatchString(arch)
+}
+
+// IsValidVersion checks if the version is a semantic version as required by the registry protocols
+func IsValidVersion(v string) bool {
+	if strings.HasPrefix(v, "v") {
+		return false
+	}
+	_, err := version.NewSemver(v)
+	return err == nil
+}
+
+// ModulePackageName returns the package name used to store the module
+func ModulePackageName(name, system string) string {
+	return name + "/" + system
+}
+
+// ModuleFilename returns the name of the module archive
+func ModuleFilename(name, system, version string) string {
+	return fmt.Sprintf("%s-%s-%s.tar.gz", name, system, version)
+}
+
+// ProviderFilename returns the name of the provider archive for the platform
+func ProviderFilename(providerType, version, os, arch string) string {
+	return fmt.Sprintf("terraform-provider-%s_%s_%s_%s.zip", providerType, version, os, arch)
+}
+
+// ParseProtocols parses a comma separated list of plugin protocol versions
+func ParseProtocols(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return []string{DefaultProtocol}, nil
+	}
+
+	var protocols []string
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if !protocolPattern.MatchString(p) {
+			return nil, ErrInvalidProtocol
+		}
+		protocols = append(protocols, p)
+	}
+	return protocols, nil
+}
+
+// ParseModuleArchive parses a .tar.gz module archive and extracts the metadata of the root module
+// https://developer.hashicorp.com/terraform/language/modules/develop/structure
+func ParseModuleArchive(r io.Reader) (*Metadata, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		if err == gzip.ErrHeader || err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrUnsupportedArchiveType
+		}
+		return nil, err
+	}
+	defer gzr.Close()
+
+	m := &Metadata{
+		Kind: KindModule,
+	}
+
+	hasConfiguration := false
+
+	tr := tar.NewReader(gzr)
+	for {
+		hd, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hd.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := strings.TrimPrefix(path.Clean(hd.Name), "./")
+		if strings.Contains(name, "/") {
+			// only files of the root module are relevant
+			continue
+		}
+
+		if strings.EqualFold(name, "README.md") {
+			buf, err := io.ReadAll(tr)
+			if err != nil {
+				return nil, err
+			}
+			m.Readme = string(buf)
+			m.Description = extractDescription(m.Readme)
+		} else if strings.HasSuffix(name, ".tf") {
+			if err := ParseConfiguration(tr, m); err != nil {
+				return nil, err
+			}
+			hasConfiguration = true
+		}
+	}
+
+	if !hasConfiguration {
+		return nil, ErrMissingModuleFiles
+	}
+
+	return m, nil
+}
+
+// extractDescription uses the first paragraph line of the readme as description
+func extractDescription(readme string) string {
+	for _, line := range strings.Split(readme, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
+// ParseConfiguration extracts the variables, outputs and required providers from a .tf file.
+// This is no complete HCL parser and only looks at the top level blocks relevant for the registry.
+func ParseConfiguration(r io.Reader, m *Metadata) error {
+	const (
+		blockNone = iota
+		blockVariable
+		blockOutput
+		blockTerraform
+		blockRequiredProviders
+		blockProvider
+	)
+
+	var variable *Variable
+	var output *Output
+	var provider *RequiredProvider
+
+	block := blockNone
+	depth := 0
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := stripComment(strings.TrimSpace(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case depth == 0:
+			if match := blockPattern.FindStringSubmatch(line); match != nil {
+				if match[1] == "variable" {
+					variable = &Variable{Name: match[2], Required: true}
+					block = blockVariable
+				} else {
+					output = &Output{Name: match[2]}
+					block = blockOutput
+				}
+			} else if terraformBlockPattern.MatchString(line) {
+				block = blockTerraform
+			}
+		case depth == 1 && block == blockTerraform:
+			if requiredProvidersPattern.MatchString(line) {
+				block = blockRequiredProviders
+			}
+		case depth == 2 && block == blockRequiredProviders:
+			if match := providerEntryPattern.FindStringSubmatch(line); match != nil {
+				provider = &RequiredProvider{Name: match[1]}
+				block = blockProvider
+
+				// inline syntax: name = { source = "...", version = "..." }
+				if start, end := strings.Index(line, "{"), strings.LastIndex(line, "}"); end > start {
+					for _, attr := range strings.Split(line[start+1:end], ",") {
+						if match := attributePattern.FindStringSubmatch(strings.TrimSpace(attr)); match != nil {
+							setProviderAttribute(provider, match[1], match[2])
+						}
+					}
+				}
+			} else if match := attributePattern.FindStringSubmatch(line); match != nil {
+				// legacy syntax: name = "version constraint"
+				m.RequiredProviders = append(m.RequiredProviders, RequiredProvider{Name: match[1], Version: unquote(match[2])})
+			}
+		case depth == 3 && block == blockProvider:
+			if match := attributePattern.FindStringSubmatch(line); match != nil {
+				setProviderAttribute(provider, match[1], match[2])
+			}
+		case depth == 1 && (block == blockVariable || block == blockOutput):
+			if match := attributePattern.FindStringSubmatch(line); match != nil {
+				switch match[1] {
+				case "description":
+					if block == blockVariable {
+						variable.Description = unquote(match[2])
+					} else {
+						output.Description = unquote(match[2])
+					}
+				case "type":
+					if block == blockVariable {
+						variable.Type = match[2]
+						if strings.Count(variable.Type, "(") != strings.Count(variable.Type, ")") {
+							// multi-line type constraints are reduced to the outer type
+							variable.Type, _, _ = strings.Cut(variable.Type, "(")
+						}
+					}
+				case "default":
+					if block == blockVariable {
+						variable.Required = false
+					}
+				}
+			}
+		}
+
+		depth += countBraces(line)
+		if depth < 0 {
+			depth = 0
+		}
+
+		switch {
+		case depth == 0 && block == blockVariable:
+			m.Variables = append(m.Variables, *variable)
+			block = blockNone
+		case depth == 0 && block == blockOutput:
+			m.Outputs = append(m.Outputs, *output)
+			block = blockNone
+		case depth == 0:
+			block = blockNone
+		case depth == 1 && block == blockRequiredProviders:
+			block = blockTerraform
+		case depth == 2 && block == blockProvider:
+			m.RequiredProviders = append(m.RequiredProviders, *provider)
+			block = blockRequiredProviders
+		}
+	}
+	return scanner.Err()
+}
+
+func setProviderAttribute(provider *RequiredProvider, name, value string) {
+	switch name {
+	case "source":
+		provider.Source = unquote(value)
+	case "version":
+		provider.Version = unquote(value)
+	}
+}
+
+// stripComment removes a trailing # or // comment which is not part of a string
+func stripComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && inString:
+			i++
+		case c == '"':
+			inString = !inString
+		case c == '#' && !inString:
+			return strings.TrimSpace(line[:i])
+		case c == '/' && !inString && i+1 < len(line) && line[i+1] == '/':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// countBraces returns the change of the nesting depth caused by the line
+func countBraces(line string) int {
+	n := 0
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && inString:
+			i++
+		case c == '"':
+			inString = !inString
+		case c == '{' && !inString:
+			n++
+		case c == '}' && !inString:
+			n--
+		}
+	}
+	return n
+}
+
+func unquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strings.ReplaceAll(s[1:len(s)-1], `\"`, `"`)
+	}
+	return s
+}
+
+// ParseProviderArchive checks if the .zip archive contains the provider binary
+// https://developer.hashicorp.com/terraform/registry/providers/publishing#manually-preparing-a-release
+func ParseProviderArchive(r io.ReaderAt, size int64, providerType string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		if err == zip.ErrFormat {
+			return ErrUnsupportedArchiveType
+		}
+		return err
+	}
+
+	binaryName := "terraform-provider-" + providerType
+	for _, f := range zr.File {
+		name := strings.TrimSuffix(f.Name, ".exe")
+		if name == binaryName || strings.HasPrefix(name, binaryName+"_") {
+			return nil
+		}
+	}
+	return ErrMissingProviderBinary
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mainTf = `terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws" # the official provider
+      version = "~> 5.0"
+    }
+    random = { source = "hashicorp/random", version = ">= 3.0" }
+  }
+}
+
+variable "name" {
+  description = "Name of the {resource}"
+  type        = string
+}
+
+variable "tags" {
+  type = map(object({
+    value = string
+  }))
+  default = {}
+}
+
+// not part of the interface
+resource "aws_vpc" "this" {
+  tags = {
+    Name = var.name
+  }
+}
+
+output "id" {
+  description = "ID of the VPC"
+  value       = aws_vpc.this.id
+}
+`
+
+func createModuleArchive(files map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for name, content := range files {
+		_ = tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o600,
+			Size: int64(len(content)),
+		})
+		_, _ = tw.Write([]byte(content))
+	}
+	tw.Close()
+	zw.Close()
+	return &buf
+}
+
+func TestParseModuleArchive(t *testing.T) {
+	t.Run("UnsupportedArchiveType", func(t *testing.T) {
+		m, err := ParseModuleArchive(strings.NewReader("dummy"))
+		assert.Nil(t, m)
+		assert.ErrorIs(t, err, ErrUnsupportedArchiveType)
+	})
+
+	t.Run("MissingModuleFiles", func(t *testing.T) {
+		m, err := ParseModuleArchive(createModuleArchive(map[string]string{
+			"README.md":              "readme",
+			"modules/nested/main.tf": mainTf,
+		}))
+		assert.Nil(t, m)
+		assert.ErrorIs(t, err, ErrMissingModuleFiles)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		m, err := ParseModuleArchive(createModuleArchive(map[string]string{
+			"./README.md": "# VPC\n\n[![badge](https://example.com)](https://example.com)\nCreates a VPC.\n",
+			"main.tf":     mainTf,
+		}))
+		assert.NoError(t, err)
+		assert.NotNil(t, m)
+
+		assert.Equal(t, KindModule, m.Kind)
+		assert.Equal(t, "Creates a VPC.", m.Description)
+		assert.Contains(t, m.Readme, "# VPC")
+		assert.Equal(t, []Variable{
+			{Name: "name", Type: "string", Description: "Name of the {resource}", Required: true},
+			{Name: "tags", Type: "map", Required: false},
+		}, m.Variables)
+		assert.Equal(t, []Output{{Name: "id", Description: "ID of the VPC"}}, m.Outputs)
+		assert.Equal(t, []RequiredProvider{
+			{Name: "aws", Source: "hashicorp/aws", Version: "~> 5.0"},
+			{Name: "random", Source: "hashicorp/random", Version: ">= 3.0"},
+		}, m.RequiredProviders)
+	})
+}
+
+func TestParseProviderArchive(t *testing.T) {
+	createArchive := func(name string) *bytes.Reader {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, _ := zw.Create(name)
+		_, _ = w.Write([]byte("binary"))
+		zw.Close()
+		return bytes.NewReader(buf.Bytes())
+	}
+
+	r := bytes.NewReader([]byte("dummy"))
+	assert.ErrorIs(t, ParseProviderArchive(r, r.Size(), "example"), ErrUnsupportedArchiveType)
+
+	r = createArchive("terraform-provider-other_v1.0.0")
+	assert.ErrorIs(t, ParseProviderArchive(r, r.Size(), "example"), ErrMissingProviderBinary)
+
+	for _, name := range []string{"terraform-provider-example", "terraform-provider-example_v1.0.0", "terraform-provider-example_v1.0.0.exe"} {
+		r = createArchive(name)
+		assert.NoError(t, ParseProviderArchive(r, r.Size(), "example"))
+	}
+}
+
+func TestValidation(t *testing.T) {
+	assert.True(t, IsValidModuleName("vpc"))
+	assert.True(t, IsValidModuleName("my_module-1"))
+	assert.False(t, IsValidModuleName("-vpc"))
+	assert.False(t, IsValidModuleName("vpc/aws"))
+
+	assert.True(t, IsValidProviderType("example"))
+	assert.False(t, IsValidProviderType("Example"))
+
+	assert.True(t, IsValidPlatform("linux", "amd64"))
+	assert.False(t, IsValidPlatform("linux", "../amd64"))
+
+	assert.True(t, IsValidVersion("1.0.0"))
+	assert.True(t, IsValidVersion("1.0.0-beta.1"))
+	assert.False(t, IsValidVersion("v1.0.0"))
+	assert.False(t, IsValidVersion("latest"))
+
+	protocols, err := ParseProtocols("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultProtocol}, protocols)
+	protocols, err = ParseProtocols("5.0, 6.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"5.0", "6.0"}, protocols)
+	_, err = ParseProtocols("5")
+	assert.ErrorIs(t, err, ErrInvalidProtocol)
+}
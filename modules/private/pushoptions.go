@@ -18,6 +18,9 @@ const (
 	GitPushOptionRepoPrivate  = "repo.private"
 	GitPushOptionRepoTemplate = "repo.template"
 	GitPushOptionForcePush    = "force-push"
+
+	// GitPushOptionSecretScanningBypassReason allows to push secrets found by the secret scanning, the value is the reason
+	GitPushOptionSecretScanningBypassReason = "secret-scanning.bypass-reason"
 )
 
 // Bool checks for a key in the map and parses as a boolean
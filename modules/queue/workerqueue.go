@@ -40,6 +40,8 @@ type WorkerPoolQueue[T any] struct {
 	workerMaxNum    int
 	workerActiveNum int
 	workerNumMu     sync.Mutex
+
+	processedNum atomic.Int64 // the number of items handled by the workers since the queue started
 }
 
 type flushType chan struct{}
@@ -91,6 +93,10 @@ func (q *WorkerPoolQueue[T]) GetQueueItemNumber() int {
 	return cnt
 }
 
+func (q *WorkerPoolQueue[T]) GetProcessedItemNumber() int64 {
+	return q.processedNum.Load()
+}
+
 func (q *WorkerPoolQueue[T]) FlushWithContext(ctx context.Context, timeout time.Duration) (err error) {
 	if q.isBaseQueueDummy() {
 		return nil
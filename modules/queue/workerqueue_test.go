@@ -60,6 +60,7 @@ func TestWorkerPoolQueueUnhandled(t *testing.T) {
 		}
 		assert.NoError(t, q.FlushWithContext(context.Background(), 0))
 		stop()
+		assert.EqualValues(t, queueSetting.Length, q.GetProcessedItemNumber(), "test %s: processed items", t.Name())
 
 		ok := true
 		for i := 0; i < queueSetting.Length; i++ {
@@ -54,25 +54,42 @@ func AesDecrypt(key, text []byte) ([]byte, error) {
 
 // EncryptSecret encrypts a string with given key into a hex string
 func EncryptSecret(key, str string) (string, error) {
-	keyHash := sha256.Sum256([]byte(key))
-	plaintext := []byte(str)
-	ciphertext, err := AesEncrypt(keyHash[:], plaintext)
+	ciphertext, err := EncryptBytes(key, []byte(str))
 	if err != nil {
-		return "", fmt.Errorf("failed to encrypt by secret: %w", err)
+		return "", err
 	}
 	return hex.EncodeToString(ciphertext), nil
 }
 
 // DecryptSecret decrypts a previously encrypted hex string
 func DecryptSecret(key, cipherHex string) (string, error) {
-	keyHash := sha256.Sum256([]byte(key))
 	ciphertext, err := hex.DecodeString(cipherHex)
 	if err != nil {
 		return "", fmt.Errorf("failed to decrypt by secret, invalid hex string: %w", err)
 	}
-	plaintext, err := AesDecrypt(keyHash[:], ciphertext)
+	plaintext, err := DecryptBytes(key, ciphertext)
 	if err != nil {
-		return "", fmt.Errorf("failed to decrypt by secret, the key (maybe SECRET_KEY?) might be incorrect: %w", err)
+		return "", err
 	}
 	return string(plaintext), nil
 }
+
+// EncryptBytes encrypts binary data with given key
+func EncryptBytes(key string, data []byte) ([]byte, error) {
+	keyHash := sha256.Sum256([]byte(key))
+	ciphertext, err := AesEncrypt(keyHash[:], data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt by secret: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// DecryptBytes decrypts binary data previously encrypted by EncryptBytes
+func DecryptBytes(key string, ciphertext []byte) ([]byte, error) {
+	keyHash := sha256.Sum256([]byte(key))
+	plaintext, err := AesDecrypt(keyHash[:], ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt by secret, the key (maybe SECRET_KEY?) might be incorrect: %w", err)
+	}
+	return plaintext, nil
+}
@@ -29,3 +29,18 @@ func TestEncryptDecrypt(t *testing.T) {
 	_, err = DecryptSecret("a", "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
 	assert.ErrorContains(t, err, "the key (maybe SECRET_KEY?) might be incorrect: AesDecrypt invalid decrypted base64 string")
 }
+
+func TestEncryptDecryptBytes(t *testing.T) {
+	data := []byte{0x00, 0xff, 'g', 'i', 't', 'e', 'a'}
+
+	ciphertext, err := EncryptBytes("foo", data)
+	assert.NoError(t, err)
+	assert.NotEqual(t, data, ciphertext)
+
+	plaintext, err := DecryptBytes("foo", ciphertext)
+	assert.NoError(t, err)
+	assert.Equal(t, data, plaintext)
+
+	_, err = DecryptBytes("bar", ciphertext)
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package secretscan
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Pattern matches a kind of secret
+type Pattern struct {
+	ID     string
+	Name   string
+	Regexp *regexp.Regexp
+}
+
+// builtinPatterns are the patterns of well known secrets, the first capturing group of an expression is the secret
+var builtinPatterns = []*Pattern{
+	{ID: "aws_access_key_id", Name: "AWS access key ID", Regexp: regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{ID: "aws_secret_access_key", Name: "AWS secret access key", Regexp: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+=]|$)`)},
+	{ID: "github_token", Name: "GitHub token", Regexp: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,255})\b`)},
+	{ID: "github_fine_grained_token", Name: "GitHub fine-grained personal access token", Regexp: regexp.MustCompile(`\b(github_pat_[A-Za-z0-9_]{82})\b`)},
+	{ID: "gitlab_token", Name: "GitLab personal access token", Regexp: regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20})`)},
+	{ID: "slack_token", Name: "Slack token", Regexp: regexp.MustCompile(`\b(xox[abposr]-[A-Za-z0-9-]{10,})`)},
+	{ID: "slack_webhook_url", Name: "Slack webhook URL", Regexp: regexp.MustCompile(`(https://hooks\.slack\.com/services/T[A-Za-z0-9_]+/B[A-Za-z0-9_]+/[A-Za-z0-9_]+)`)},
+	{ID: "google_api_key", Name: "Google API key", Regexp: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`)},
+	{ID: "stripe_secret_key", Name: "Stripe secret key", Regexp: regexp.MustCompile(`\b((?:sk|rk)_live_[0-9A-Za-z]{24,99})\b`)},
+	{ID: "npm_token", Name: "npm access token", Regexp: regexp.MustCompile(`\b(npm_[A-Za-z0-9]{36})\b`)},
+	{ID: "pypi_token", Name: "PyPI API token", Regexp: regexp.MustCompile(`\b(pypi-AgEIcHlwaS5vcmc[A-Za-z0-9_-]{50,})`)},
+	{ID: "private_key", Name: "Private key", Regexp: regexp.MustCompile(`-----BEGIN (?:RSA |DSA |EC |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+}
+
+// Finding is a secret found in a content
+type Finding struct {
+	Pattern *Pattern
+	Secret  string
+	Line    int // 1-based line of the secret in the content
+
+	offset int
+}
+
+// Redacted returns the secret with all but its first characters masked, so that it can be shown to users
+func (f *Finding) Redacted() string {
+	return Redact(f.Secret)
+}
+
+// Redact masks all but the first characters of the secret
+func Redact(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return secret[:4] + strings.Repeat("*", min(len(secret)-4, 16))
+}
+
+// Scanner finds secrets in contents
+type Scanner struct {
+	patterns []*Pattern
+}
+
+// NewScanner creates a scanner which uses the built-in patterns without the disabled ones and the custom patterns.
+// A custom pattern with the ID of a built-in pattern replaces it.
+func NewScanner(customPatterns map[string]string, disabledPatterns []string) (*Scanner, error) {
+	patterns := make([]*Pattern, 0, len(builtinPatterns)+len(customPatterns))
+	for _, p := range builtinPatterns {
+		if _, ok := customPatterns[p.ID]; ok || slices.Contains(disabledPatterns, p.ID) {
+			continue
+		}
+		patterns = append(patterns, p)
+	}
+
+	ids := make([]string, 0, len(customPatterns))
+	for id := range customPatterns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		re, err := regexp.Compile(customPatterns[id])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", id, err)
+		}
+		patterns = append(patterns, &Pattern{ID: id, Name: id, Regexp: re})
+	}
+	return &Scanner{patterns: patterns}, nil
+}
+
+var defaultScanner = sync.OnceValues(func() (*Scanner, error) {
+	return NewScanner(setting.SecretScanning.CustomPatterns, setting.SecretScanning.DisabledPatterns)
+})
+
+// DefaultScanner returns the scanner which uses the patterns configured in the [secret_scanning] settings
+func DefaultScanner() (*Scanner, error) {
+	return defaultScanner()
+}
+
+// binaryCheckSize is the size of the beginning of a content which is checked for NUL bytes, the same as git does
+const binaryCheckSize = 8000
+
+// Scan returns the secrets found in the content, binary contents are not scanned
+func (s *Scanner) Scan(content []byte) []*Finding {
+	if bytes.IndexByte(content[:min(len(content), binaryCheckSize)], 0) >= 0 {
+		return nil
+	}
+
+	var findings []*Finding
+	for _, p := range s.patterns {
+		for _, m := range p.Regexp.FindAllSubmatchIndex(content, -1) {
+			start, end := m[0], m[1]
+			if len(m) >= 4 && m[2] >= 0 {
+				start, end = m[2], m[3]
+			}
+			findings = append(findings, &Finding{
+				Pattern: p,
+				Secret:  string(content[start:end]),
+				Line:    bytes.Count(content[:start], []byte{'\n'}) + 1,
+				offset:  start,
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].offset < findings[j].offset
+	})
+	return findings
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+// Audit settings
+var Audit = struct {
+	Enabled       bool
+	RetentionDays int64
+}{
+	Enabled:       true,
+	RetentionDays: 365,
+}
+
+func loadAuditFrom(rootCfg ConfigProvider) {
+	sec := rootCfg.Section("audit")
+	Audit.Enabled = sec.Key("ENABLED").MustBool(true)
+	// the audit events are kept forever if the retention is not positive
+	Audit.RetentionDays = sec.Key("RETENTION_DAYS").MustInt64(365)
+}
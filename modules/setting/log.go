@@ -127,6 +127,11 @@ func loadLogModeByName(rootCfg ConfigProvider, loggerName, modeName string) (wri
 		writerName += ".access"
 		defaultFlags = "none"
 		defaultFilaName = "access.log"
+	} else if loggerName == "audit" {
+		// "audit" logger writes the audit events as JSON lines, so it doesn't have output flags either
+		writerName += ".audit"
+		defaultFlags = "none"
+		defaultFilaName = "audit.log"
 	}
 
 	writerMode.Level = log.LevelFromString(ConfigInheritedKeyString(sec, "LEVEL", Log.Level.String()))
@@ -211,6 +216,7 @@ func initManagedLoggers(manager *log.LoggerManager, cfg ConfigProvider) {
 	initLoggerByName(manager, cfg, "access")
 	initLoggerByName(manager, cfg, "router")
 	initLoggerByName(manager, cfg, "xorm")
+	initLoggerByName(manager, cfg, "audit")
 }
 
 func initLoggerByName(manager *log.LoggerManager, rootCfg ConfigProvider, loggerName string) {
@@ -264,6 +270,10 @@ func IsAccessLogEnabled() bool {
 	return log.IsLoggerEnabled("access")
 }
 
+func IsAuditLogEnabled() bool {
+	return log.IsLoggerEnabled("audit")
+}
+
 func IsRouteLogEnabled() bool {
 	return log.IsLoggerEnabled("router")
 }
@@ -0,0 +1,23 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+// Quota settings
+var Quota = struct {
+	Enabled       bool
+	DefaultGroups []string
+	// DefaultTotal is the total size limit for owners without any quota group, -1 means no limit
+	DefaultTotal int64
+}{
+	Enabled:       false,
+	DefaultGroups: []string{},
+	DefaultTotal:  -1,
+}
+
+func loadQuotaFrom(rootCfg ConfigProvider) {
+	sec := rootCfg.Section("quota")
+	Quota.Enabled = sec.Key("ENABLED").MustBool(false)
+	Quota.DefaultGroups = sec.Key("DEFAULT_GROUPS").Strings(",")
+	Quota.DefaultTotal = mustBytes(rootCfg.Section("quota.default"), "TOTAL")
+}
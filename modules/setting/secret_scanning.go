@@ -0,0 +1,39 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// SecretScanning settings
+var SecretScanning = struct {
+	Enabled bool
+	// MaxBlobSize is the size of the largest blob which is scanned, larger blobs are skipped
+	MaxBlobSize int64
+	// CustomPatterns maps the IDs of the patterns added by the admin to their regular expressions
+	CustomPatterns   map[string]string
+	DisabledPatterns []string
+}{
+	Enabled:        false,
+	MaxBlobSize:    1024 * 1024,
+	CustomPatterns: map[string]string{},
+}
+
+func loadSecretScanningFrom(rootCfg ConfigProvider) error {
+	sec := rootCfg.Section("secret_scanning")
+	SecretScanning.Enabled = sec.Key("ENABLED").MustBool(false)
+	SecretScanning.MaxBlobSize = sec.Key("MAX_BLOB_SIZE").MustInt64(1024 * 1024)
+	SecretScanning.DisabledPatterns = sec.Key("DISABLED_PATTERNS").Strings(",")
+
+	SecretScanning.CustomPatterns = map[string]string{}
+	for _, key := range rootCfg.Section("secret_scanning.patterns").Keys() {
+		if _, err := regexp.Compile(key.String()); err != nil {
+			return fmt.Errorf("invalid secret scanning pattern %q: %w", key.Name(), err)
+		}
+		SecretScanning.CustomPatterns[key.Name()] = key.String()
+	}
+	return nil
+}
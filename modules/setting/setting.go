@@ -138,8 +138,16 @@ func loadCommonSettingsFrom(cfg ConfigProvider) error {
 	if err := loadActionsFrom(cfg); err != nil {
 		return err
 	}
+	if err := loadTerraformFrom(cfg); err != nil {
+		return err
+	}
 	loadUIFrom(cfg)
 	loadAdminFrom(cfg)
+	loadAuditFrom(cfg)
+	loadQuotaFrom(cfg)
+	if err := loadSecretScanningFrom(cfg); err != nil {
+		return err
+	}
 	loadAPIFrom(cfg)
 	loadMetricsFrom(cfg)
 	loadCamoFrom(cfg)
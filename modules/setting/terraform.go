@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+// Terraform settings
+var Terraform = struct {
+	Enabled      bool
+	Storage      *Storage
+	MaxStateSize int64
+}{
+	Enabled: true,
+}
+
+func loadTerraformFrom(rootCfg ConfigProvider) (err error) {
+	sec := rootCfg.Section("terraform")
+	Terraform.Enabled = sec.Key("ENABLED").MustBool(true)
+
+	// default to 64 MiB, -1 means no limit
+	Terraform.MaxStateSize = 64 << 20
+	if sec.HasKey("MAX_STATE_SIZE") {
+		Terraform.MaxStateSize = mustBytes(sec, "MAX_STATE_SIZE")
+	}
+
+	Terraform.Storage, err = getStorage(rootCfg, "terraform_state", "", nil)
+	return err
+}
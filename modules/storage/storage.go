@@ -129,6 +129,11 @@ var (
 	Actions ObjectStorage = uninitializedStorage
 	// Actions Artifacts represents actions artifacts storage
 	ActionsArtifacts ObjectStorage = uninitializedStorage
+	// Actions Cache represents the storage of the caches saved by `actions/cache`
+	ActionsCache ObjectStorage = uninitializedStorage
+
+	// TerraformState represents the storage of the states of the Terraform http backend
+	TerraformState ObjectStorage = uninitializedStorage
 )
 
 // Init init the stoarge
@@ -141,6 +146,7 @@ func Init() error {
 		initRepoArchives,
 		initPackages,
 		initActions,
+		initTerraformState,
 	} {
 		if err := f(); err != nil {
 			return err
@@ -214,6 +220,7 @@ func initActions() (err error) {
 	if !setting.Actions.Enabled {
 		Actions = discardStorage("Actions isn't enabled")
 		ActionsArtifacts = discardStorage("ActionsArtifacts isn't enabled")
+		ActionsCache = discardStorage("ActionsCache isn't enabled")
 		return nil
 	}
 	log.Info("Initialising Actions storage with type: %s", setting.Actions.LogStorage.Type)
@@ -221,6 +228,20 @@ func initActions() (err error) {
 		return err
 	}
 	log.Info("Initialising ActionsArtifacts storage with type: %s", setting.Actions.ArtifactStorage.Type)
-	ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, setting.Actions.ArtifactStorage)
+	if ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, setting.Actions.ArtifactStorage); err != nil {
+		return err
+	}
+	log.Info("Initialising ActionsCache storage with type: %s", setting.Actions.CacheStorage.Type)
+	ActionsCache, err = NewStorage(setting.Actions.CacheStorage.Type, setting.Actions.CacheStorage)
+	return err
+}
+
+func initTerraformState() (err error) {
+	if !setting.Terraform.Enabled {
+		TerraformState = discardStorage("Terraform isn't enabled")
+		return nil
+	}
+	log.Info("Initialising TerraformState storage with type: %s", setting.Terraform.Storage.Type)
+	TerraformState, err = NewStorage(setting.Terraform.Storage.Type, setting.Terraform.Storage)
 	return err
 }
@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// AuditEvent represents a security relevant action recorded by the audit log
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// the kind of the action, e.g. "team.member_add"
+	Action string `json:"action"`
+	// the user who performed the action, 0 if it was performed by the system
+	ActorID   int64  `json:"actor_id"`
+	ActorName string `json:"actor"`
+	// the user or organization the event belongs to, 0 for instance level events
+	OwnerID int64 `json:"owner_id"`
+	RepoID  int64 `json:"repo_id"`
+	// the type of the object the event is about, e.g. "team" or "repository"
+	TargetType  string `json:"target_type"`
+	TargetID    int64  `json:"target_id"`
+	TargetName  string `json:"target_name"`
+	Description string `json:"description"`
+	IPAddress   string `json:"ip_address"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -114,6 +114,9 @@ var (
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
 	_ Payloader = &PackagePayload{}
+	_ Payloader = &WorkflowRunPayload{}
+	_ Payloader = &WorkflowJobPayload{}
+	_ Payloader = &MergeGroupPayload{}
 )
 
 // _________                        __
@@ -496,6 +499,61 @@ func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
 
+// HookWorkflowRunAction an action that happens to a workflow run
+type HookWorkflowRunAction string
+
+const (
+	// HookWorkflowRunRequested requested
+	HookWorkflowRunRequested HookWorkflowRunAction = "requested"
+	// HookWorkflowRunInProgress in_progress
+	HookWorkflowRunInProgress HookWorkflowRunAction = "in_progress"
+	// HookWorkflowRunCompleted completed
+	HookWorkflowRunCompleted HookWorkflowRunAction = "completed"
+)
+
+// WorkflowRunPayload represents a payload information of a workflow run event
+type WorkflowRunPayload struct {
+	Action       HookWorkflowRunAction `json:"action"`
+	Workflow     *ActionWorkflow       `json:"workflow"`
+	WorkflowRun  *ActionWorkflowRun    `json:"workflow_run"`
+	Organization *User                 `json:"organization"`
+	Repository   *Repository           `json:"repository"`
+	Sender       *User                 `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowRunPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookWorkflowJobAction an action that happens to a workflow job
+type HookWorkflowJobAction string
+
+const (
+	// HookWorkflowJobQueued queued
+	HookWorkflowJobQueued HookWorkflowJobAction = "queued"
+	// HookWorkflowJobWaiting waiting
+	HookWorkflowJobWaiting HookWorkflowJobAction = "waiting"
+	// HookWorkflowJobInProgress in_progress
+	HookWorkflowJobInProgress HookWorkflowJobAction = "in_progress"
+	// HookWorkflowJobCompleted completed
+	HookWorkflowJobCompleted HookWorkflowJobAction = "completed"
+)
+
+// WorkflowJobPayload represents a payload information of a workflow job event
+type WorkflowJobPayload struct {
+	Action       HookWorkflowJobAction `json:"action"`
+	WorkflowJob  *ActionWorkflowJob    `json:"workflow_job"`
+	Organization *User                 `json:"organization"`
+	Repository   *Repository           `json:"repository"`
+	Sender       *User                 `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *WorkflowJobPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
 // WorkflowDispatchPayload represents a workflow dispatch payload
 type WorkflowDispatchPayload struct {
 	Workflow   string         `json:"workflow"`
@@ -509,3 +567,33 @@ type WorkflowDispatchPayload struct {
 func (p *WorkflowDispatchPayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
+
+// HookMergeGroupAction an action that happens to a merge group
+type HookMergeGroupAction string
+
+const (
+	// HookMergeGroupChecksRequested checks_requested
+	HookMergeGroupChecksRequested HookMergeGroupAction = "checks_requested"
+)
+
+// MergeGroup represents a temporary merge group commit of the merge queue
+type MergeGroup struct {
+	HeadSHA    string         `json:"head_sha"`
+	HeadRef    string         `json:"head_ref"`
+	BaseSHA    string         `json:"base_sha"`
+	BaseRef    string         `json:"base_ref"`
+	HeadCommit *PayloadCommit `json:"head_commit"`
+}
+
+// MergeGroupPayload represents a payload information of a merge group event
+type MergeGroupPayload struct {
+	Action     HookMergeGroupAction `json:"action"`
+	MergeGroup *MergeGroup          `json:"merge_group"`
+	Repository *Repository          `json:"repository"`
+	Sender     *User                `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *MergeGroupPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
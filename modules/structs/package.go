@@ -31,3 +31,91 @@ type PackageFile struct {
 	HashSHA256 string `json:"sha256"`
 	HashSHA512 string `json:"sha512"`
 }
+
+// PackageAttestation represents an attestation of a package file, e.g. the provenance of the file
+type PackageAttestation struct {
+	ID       int64  `json:"id"`
+	FileID   int64  `json:"file_id"`
+	FileName string `json:"file_name"`
+	// the format the attestation was uploaded in
+	// enum: in-toto,dsse,sigstore-bundle,pep740
+	Format        string `json:"format"`
+	PredicateType string `json:"predicate_type"`
+	// signature_valid_untrusted means that the attestation is about the file and is signed by the embedded certificate,
+	// the certificate itself is not checked against the Sigstore trust root
+	// enum: signature_valid_untrusted,unverified,invalid
+	Status string `json:"status"`
+	// the identity claimed by the signing certificate, it is not verified
+	SignerIdentity string `json:"signer_identity,omitempty"`
+	// the OIDC issuer claimed by the signing certificate, it is not verified
+	SignerIssuer string `json:"signer_issuer,omitempty"`
+	// the attestation as it was uploaded
+	Content string `json:"content"`
+}
+
+// PackageComponent represents a software component listed in a SBOM attached to a container image
+type PackageComponent struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Type    string `json:"type"`
+	PURL    string `json:"purl"`
+	License string `json:"license"`
+	// digest of the image manifest described by the SBOM
+	Digest string `json:"digest"`
+}
+
+// PackageComponentMatch represents a container image which contains a searched component
+type PackageComponentMatch struct {
+	Image string `json:"image"`
+	// versions (tags and digests) of the image, empty if the described manifest was not pushed yet
+	Versions  []string          `json:"versions"`
+	Component *PackageComponent `json:"component"`
+}
+
+// PackageCleanupRule represents a rule which describes when package versions of a type get removed
+type PackageCleanupRule struct {
+	ID      int64  `json:"id"`
+	Enabled bool   `json:"enabled"`
+	Type    string `json:"type"`
+	// number of the most recent versions of a package which are always kept
+	KeepCount int `json:"keep_count"`
+	// versions matching this pattern are kept
+	KeepPattern string `json:"keep_pattern"`
+	// only versions older than this number of days are removed
+	RemoveDays int `json:"remove_days"`
+	// only versions matching this pattern are removed
+	RemovePattern string `json:"remove_pattern"`
+	// match the patterns against "name/version" instead of "version"
+	MatchFullName bool `json:"match_full_name"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreatePackageCleanupRuleOption options for creating a package cleanup rule
+type CreatePackageCleanupRuleOption struct {
+	Enabled bool `json:"enabled"`
+	// required: true
+	// enum: alpine,arch,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,terraform,vagrant
+	Type string `json:"type" binding:"Required;In(alpine,arch,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,terraform,vagrant)"`
+	// enum: 0,1,5,10,25,50,100
+	KeepCount   int    `json:"keep_count" binding:"In(0,1,5,10,25,50,100)"`
+	KeepPattern string `json:"keep_pattern" binding:"RegexPattern"`
+	// enum: 0,7,14,30,60,90,180
+	RemoveDays    int    `json:"remove_days" binding:"In(0,7,14,30,60,90,180)"`
+	RemovePattern string `json:"remove_pattern" binding:"RegexPattern"`
+	MatchFullName bool   `json:"match_full_name"`
+}
+
+// EditPackageCleanupRuleOption options for editing a package cleanup rule
+type EditPackageCleanupRuleOption struct {
+	Enabled *bool `json:"enabled"`
+	// enum: 0,1,5,10,25,50,100
+	KeepCount   *int    `json:"keep_count" binding:"In(0,1,5,10,25,50,100)"`
+	KeepPattern *string `json:"keep_pattern" binding:"RegexPattern"`
+	// enum: 0,7,14,30,60,90,180
+	RemoveDays    *int    `json:"remove_days" binding:"In(0,7,14,30,60,90,180)"`
+	RemovePattern *string `json:"remove_pattern" binding:"RegexPattern"`
+	MatchFullName *bool   `json:"match_full_name"`
+}
@@ -117,3 +117,25 @@ type ChangedFile struct {
 	ContentsURL      string `json:"contents_url,omitempty"`
 	RawURL           string `json:"raw_url,omitempty"`
 }
+
+// PullRequestCodeOwners represents the approval state of the code owners of the files changed by a pull request
+type PullRequestCodeOwners struct {
+	// whether the branch protection of the base branch requires the approval of the code owners
+	Required bool                         `json:"required"`
+	Approved bool                         `json:"approved"`
+	Paths    []*PullRequestCodeOwnersPath `json:"paths"`
+}
+
+// PullRequestCodeOwnersPath represents the approval state of the code owners of a changed file
+type PullRequestCodeOwnersPath struct {
+	Path     string                  `json:"path"`
+	Approved bool                    `json:"approved"`
+	Owners   []*PullRequestCodeOwner `json:"owners"`
+}
+
+// PullRequestCodeOwner represents a user or team owning a changed file
+type PullRequestCodeOwner struct {
+	User     *User `json:"user,omitempty"`
+	Team     *Team `json:"team,omitempty"`
+	Approved bool  `json:"approved"`
+}
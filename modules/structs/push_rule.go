@@ -0,0 +1,40 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// PushRule represents the rules the commits pushed to a repository have to satisfy
+type PushRule struct {
+	// regular expression the commit messages have to match
+	CommitMessagePattern string `json:"commit_message_pattern"`
+	// the author email of the commits has to be an activated email of an active user, no-reply addresses are not accepted
+	RequireVerifiedAuthorEmail bool `json:"require_verified_author_email"`
+	// the committer email of the commits has to be an activated email of an active user, no-reply addresses are not accepted
+	RequireVerifiedCommitterEmail bool `json:"require_verified_committer_email"`
+	// maximum size of the added or modified files in bytes, 0 for no limit
+	MaxFileSize int64 `json:"max_file_size"`
+	// semicolon separated glob patterns of the paths which must not be added or modified, e.g. `**.exe;secrets/**`
+	ForbiddenFilePatterns string `json:"forbidden_file_patterns"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// UpdatePushRuleOption options for creating or replacing a push rule
+type UpdatePushRuleOption struct {
+	// regular expression the commit messages have to match
+	CommitMessagePattern string `json:"commit_message_pattern"`
+	// the author email of the commits has to be an activated email of an active user, no-reply addresses are not accepted
+	RequireVerifiedAuthorEmail bool `json:"require_verified_author_email"`
+	// the committer email of the commits has to be an activated email of an active user, no-reply addresses are not accepted
+	RequireVerifiedCommitterEmail bool `json:"require_verified_committer_email"`
+	// maximum size of the added or modified files in bytes, 0 for no limit
+	MaxFileSize int64 `json:"max_file_size"`
+	// semicolon separated glob patterns of the paths which must not be added or modified, e.g. `**.exe;secrets/**`
+	ForbiddenFilePatterns string `json:"forbidden_file_patterns"`
+}
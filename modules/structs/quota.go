@@ -0,0 +1,69 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// QuotaInfo represents the storage usage and the quota groups of a user or organization
+// swagger:model
+type QuotaInfo struct {
+	Used   *QuotaUsed    `json:"used"`
+	Groups []*QuotaGroup `json:"groups"`
+}
+
+// QuotaUsed represents the storage used by a user or organization in bytes
+// swagger:model
+type QuotaUsed struct {
+	Git                int64 `json:"git"`
+	LFS                int64 `json:"lfs"`
+	IssueAttachments   int64 `json:"issue_attachments"`
+	ReleaseAttachments int64 `json:"release_attachments"`
+	Artifacts          int64 `json:"artifacts"`
+	Packages           int64 `json:"packages"`
+	Total              int64 `json:"total"`
+}
+
+// QuotaGroup represents a quota group
+// swagger:model
+type QuotaGroup struct {
+	Name  string           `json:"name"`
+	Rules []*QuotaRuleInfo `json:"rules"`
+}
+
+// QuotaRuleInfo represents a quota rule
+// swagger:model
+type QuotaRuleInfo struct {
+	Name string `json:"name"`
+	// the maximum size in bytes, -1 means no limit
+	Limit int64 `json:"limit"`
+	// the storage subjects limited by the rule
+	Subjects []string `json:"subjects"`
+}
+
+// CreateQuotaGroupOption options for creating a quota group
+// swagger:model
+type CreateQuotaGroupOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+}
+
+// CreateQuotaRuleOption options for creating a quota rule
+// swagger:model
+type CreateQuotaRuleOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// the maximum size in bytes, -1 means no limit
+	// required: true
+	Limit int64 `json:"limit"`
+	// the storage subjects limited by the rule
+	// required: true
+	Subjects []string `json:"subjects" binding:"Required"`
+}
+
+// EditQuotaRuleOption options for editing a quota rule
+// swagger:model
+type EditQuotaRuleOption struct {
+	// the maximum size in bytes, -1 means no limit
+	Limit *int64 `json:"limit"`
+	// the storage subjects limited by the rule
+	Subjects *[]string `json:"subjects"`
+}
@@ -32,3 +32,150 @@ type ActionTaskResponse struct {
 	Entries    []*ActionTask `json:"workflow_runs"`
 	TotalCount int64         `json:"total_count"`
 }
+
+// ActionWorkflowRun represents a run of a workflow
+type ActionWorkflowRun struct {
+	ID           int64  `json:"id"`
+	URL          string `json:"url"`
+	HTMLURL      string `json:"html_url"`
+	Name         string `json:"name"`
+	DisplayTitle string `json:"display_title"`
+	// the path of the workflow file
+	Path       string `json:"path"`
+	WorkflowID string `json:"workflow_id"`
+	Event      string `json:"event"`
+	RunNumber  int64  `json:"run_number"`
+	HeadBranch string `json:"head_branch"`
+	HeadSHA    string `json:"head_sha"`
+	// the status of the run, one of queued, waiting, in_progress and completed
+	Status string `json:"status"`
+	// the conclusion of a completed run, one of success, failure, cancelled and skipped
+	Conclusion   string      `json:"conclusion,omitempty"`
+	Actor        *User       `json:"actor"`
+	TriggerActor *User       `json:"trigger_actor"`
+	Repository   *Repository `json:"repository"`
+	JobsURL      string      `json:"jobs_url"`
+	LogsURL      string      `json:"logs_url"`
+	ArtifactsURL string      `json:"artifacts_url"`
+	CancelURL    string      `json:"cancel_url"`
+	RerunURL     string      `json:"rerun_url"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	RunStartedAt time.Time `json:"run_started_at"`
+	// swagger:strfmt date-time
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ActionWorkflowRunsResponse returns ActionWorkflowRuns
+type ActionWorkflowRunsResponse struct {
+	Entries    []*ActionWorkflowRun `json:"workflow_runs"`
+	TotalCount int64                `json:"total_count"`
+}
+
+// ActionWorkflowStep represents a step of a job
+type ActionWorkflowStep struct {
+	Name       string `json:"name"`
+	Number     int64  `json:"number"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	// swagger:strfmt date-time
+	StartedAt time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ActionWorkflowJob represents a job of a workflow run
+type ActionWorkflowJob struct {
+	ID         int64  `json:"id"`
+	URL        string `json:"url"`
+	HTMLURL    string `json:"html_url"`
+	RunID      int64  `json:"run_id"`
+	RunURL     string `json:"run_url"`
+	Name       string `json:"name"`
+	HeadBranch string `json:"head_branch"`
+	HeadSHA    string `json:"head_sha"`
+	RunAttempt int64  `json:"run_attempt"`
+	// the status of the job, one of queued, waiting, in_progress and completed
+	Status string `json:"status"`
+	// the conclusion of a completed job, one of success, failure, cancelled and skipped
+	Conclusion string                `json:"conclusion,omitempty"`
+	Labels     []string              `json:"labels"`
+	RunnerID   int64                 `json:"runner_id,omitempty"`
+	RunnerName string                `json:"runner_name,omitempty"`
+	Steps      []*ActionWorkflowStep `json:"steps"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	StartedAt time.Time `json:"started_at"`
+	// swagger:strfmt date-time
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ActionWorkflowJobsResponse returns ActionWorkflowJobs
+type ActionWorkflowJobsResponse struct {
+	Entries    []*ActionWorkflowJob `json:"jobs"`
+	TotalCount int64                `json:"total_count"`
+}
+
+// ActionArtifact represents an artifact uploaded by a workflow run
+type ActionArtifact struct {
+	ID                 int64                      `json:"id"`
+	Name               string                     `json:"name"`
+	SizeInBytes        int64                      `json:"size_in_bytes"`
+	URL                string                     `json:"url"`
+	ArchiveDownloadURL string                     `json:"archive_download_url"`
+	Expired            bool                       `json:"expired"`
+	WorkflowRun        *ActionWorkflowArtifactRun `json:"workflow_run"`
+	// swagger:strfmt date-time
+	CreatedAt time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	UpdatedAt time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ActionWorkflowArtifactRun represents the workflow run that uploaded an artifact
+type ActionWorkflowArtifactRun struct {
+	ID           int64  `json:"id"`
+	RepositoryID int64  `json:"repository_id"`
+	HeadSHA      string `json:"head_sha"`
+}
+
+// ActionArtifactsResponse returns ActionArtifacts
+type ActionArtifactsResponse struct {
+	Entries    []*ActionArtifact `json:"artifacts"`
+	TotalCount int64             `json:"total_count"`
+}
+
+// ActionWorkflow represents a workflow file of a repository
+type ActionWorkflow struct {
+	// the file name of the workflow, used as the workflow id
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// the state of the workflow, one of active and disabled_manually
+	State    string `json:"state"`
+	URL      string `json:"url"`
+	HTMLURL  string `json:"html_url"`
+	BadgeURL string `json:"badge_url"`
+}
+
+// ActionWorkflowsResponse returns ActionWorkflows
+type ActionWorkflowsResponse struct {
+	Workflows  []*ActionWorkflow `json:"workflows"`
+	TotalCount int64             `json:"total_count"`
+}
+
+// CreateActionWorkflowDispatch represents the payload for triggering a workflow_dispatch event
+// swagger:model
+type CreateActionWorkflowDispatch struct {
+	// the git reference for the workflow, a branch or tag name
+	// required: true
+	// example: refs/heads/main
+	Ref string `json:"ref" binding:"Required"`
+	// input keys and values configured in the workflow file, values are validated against the declared input types
+	Inputs map[string]any `json:"inputs"`
+}
@@ -46,7 +46,10 @@ type BranchProtection struct {
 	ApprovalsWhitelistTeams       []string `json:"approvals_whitelist_teams"`
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	RequireLinearHistory          bool     `json:"require_linear_history"`
+	EnableMergeQueue              bool     `json:"enable_merge_queue"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
 	IgnoreStaleApprovals          bool     `json:"ignore_stale_approvals"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
@@ -84,7 +87,10 @@ type CreateBranchProtectionOption struct {
 	ApprovalsWhitelistTeams       []string `json:"approvals_whitelist_teams"`
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	RequireLinearHistory          bool     `json:"require_linear_history"`
+	EnableMergeQueue              bool     `json:"enable_merge_queue"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
 	IgnoreStaleApprovals          bool     `json:"ignore_stale_approvals"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
@@ -115,7 +121,10 @@ type EditBranchProtectionOption struct {
 	ApprovalsWhitelistTeams       []string `json:"approvals_whitelist_teams"`
 	BlockOnRejectedReviews        *bool    `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests *bool    `json:"block_on_official_review_requests"`
+	RequireCodeOwnerApproval      *bool    `json:"require_code_owner_approval"`
 	BlockOnOutdatedBranch         *bool    `json:"block_on_outdated_branch"`
+	RequireLinearHistory          *bool    `json:"require_linear_history"`
+	EnableMergeQueue              *bool    `json:"enable_merge_queue"`
 	DismissStaleApprovals         *bool    `json:"dismiss_stale_approvals"`
 	IgnoreStaleApprovals          *bool    `json:"ignore_stale_approvals"`
 	RequireSignedCommits          *bool    `json:"require_signed_commits"`
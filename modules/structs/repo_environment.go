@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// Environment represents a deployment environment of a repository
+type Environment struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// the glob patterns of the branches which can deploy to the environment, or of the full names of other refs like refs/tags/v*, any ref can deploy if it's empty
+	DeploymentBranches []string `json:"deployment_branches"`
+	// the users who must approve the deployments to the environment, one of them is enough
+	Reviewers []*User `json:"reviewers"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateOrUpdateEnvironmentOption options when creating or updating an environment
+type CreateOrUpdateEnvironmentOption struct {
+	// the glob patterns of the branches which can deploy to the environment, or of the full names of other refs like refs/tags/v*
+	DeploymentBranches []string `json:"deployment_branches"`
+	// the names of the users who must approve the deployments to the environment
+	Reviewers []string `json:"reviewers"`
+}
+
+// Deployment represents a deployment of a workflow job to an environment
+type Deployment struct {
+	ID          int64        `json:"id"`
+	Environment *Environment `json:"environment"`
+	RunID       int64        `json:"run_id"`
+	JobID       int64        `json:"job_id"`
+	JobName     string       `json:"job_name"`
+	RunAttempt  int64        `json:"run_attempt"`
+	Ref         string       `json:"ref"`
+	SHA         string       `json:"sha"`
+	// the review status of the deployment, one of not_required, pending, approved and rejected
+	ReviewStatus  string `json:"review_status"`
+	ReviewComment string `json:"review_comment,omitempty"`
+	Creator       *User  `json:"creator"`
+	Reviewer      *User  `json:"reviewer,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// ReviewPendingDeploymentsOption options when reviewing the pending deployments of a workflow run
+type ReviewPendingDeploymentsOption struct {
+	// the ids of the environments to approve or reject
+	// required: true
+	EnvironmentIDs []int64 `json:"environment_ids" binding:"Required"`
+	// the review state, either approved or rejected
+	// required: true
+	// enum: approved,rejected
+	State   string `json:"state" binding:"Required;In(approved,rejected)"`
+	Comment string `json:"comment"`
+}
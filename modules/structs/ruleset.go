@@ -0,0 +1,112 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// Ruleset represents a set of rules protecting the branches or tags of the repositories of an organization or of the instance
+type Ruleset struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// enum: branch,tag
+	Target string `json:"target"`
+	// enum: disabled,active,evaluate
+	Enforcement string `json:"enforcement"`
+	// glob patterns of the repository names, patterns containing a slash match the full name
+	RepoNamePatterns []string `json:"repo_name_patterns"`
+	// the repositories have to have one of the topics
+	RepoTopics []string `json:"repo_topics"`
+	// glob patterns of the branch or tag names
+	RefPatterns []string `json:"ref_patterns"`
+	BypassUsers []string `json:"bypass_users"`
+	BypassTeams []string `json:"bypass_teams"`
+
+	RestrictCreation              bool     `json:"restrict_creation"`
+	RestrictUpdate                bool     `json:"restrict_update"`
+	RestrictDeletion              bool     `json:"restrict_deletion"`
+	BlockForcePush                bool     `json:"block_force_push"`
+	RequireSignedCommits          bool     `json:"require_signed_commits"`
+	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
+	RequiredApprovals             int64    `json:"required_approvals"`
+	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
+	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
+	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
+	EnableStatusCheck             bool     `json:"enable_status_check"`
+	StatusCheckContexts           []string `json:"status_check_contexts"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateRulesetOption options for creating a ruleset
+type CreateRulesetOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// required: true
+	// enum: branch,tag
+	Target string `json:"target" binding:"Required;In(branch,tag)"`
+	// enum: disabled,active,evaluate
+	Enforcement      string   `json:"enforcement"`
+	RepoNamePatterns []string `json:"repo_name_patterns"`
+	RepoTopics       []string `json:"repo_topics"`
+	RefPatterns      []string `json:"ref_patterns"`
+	BypassUsers      []string `json:"bypass_users"`
+	BypassTeams      []string `json:"bypass_teams"`
+
+	RestrictCreation              bool     `json:"restrict_creation"`
+	RestrictUpdate                bool     `json:"restrict_update"`
+	RestrictDeletion              bool     `json:"restrict_deletion"`
+	BlockForcePush                bool     `json:"block_force_push"`
+	RequireSignedCommits          bool     `json:"require_signed_commits"`
+	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
+	RequiredApprovals             int64    `json:"required_approvals"`
+	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
+	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
+	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
+	EnableStatusCheck             bool     `json:"enable_status_check"`
+	StatusCheckContexts           []string `json:"status_check_contexts"`
+}
+
+// EditRulesetOption options for editing a ruleset
+type EditRulesetOption struct {
+	Name *string `json:"name" binding:"MaxSize(255)"`
+	// enum: disabled,active,evaluate
+	Enforcement      *string  `json:"enforcement"`
+	RepoNamePatterns []string `json:"repo_name_patterns"`
+	RepoTopics       []string `json:"repo_topics"`
+	RefPatterns      []string `json:"ref_patterns"`
+	BypassUsers      []string `json:"bypass_users"`
+	BypassTeams      []string `json:"bypass_teams"`
+
+	RestrictCreation              *bool    `json:"restrict_creation"`
+	RestrictUpdate                *bool    `json:"restrict_update"`
+	RestrictDeletion              *bool    `json:"restrict_deletion"`
+	BlockForcePush                *bool    `json:"block_force_push"`
+	RequireSignedCommits          *bool    `json:"require_signed_commits"`
+	ProtectedFilePatterns         *string  `json:"protected_file_patterns"`
+	RequiredApprovals             *int64   `json:"required_approvals"`
+	BlockOnRejectedReviews        *bool    `json:"block_on_rejected_reviews"`
+	BlockOnOfficialReviewRequests *bool    `json:"block_on_official_review_requests"`
+	BlockOnOutdatedBranch         *bool    `json:"block_on_outdated_branch"`
+	RequireCodeOwnerApproval      *bool    `json:"require_code_owner_approval"`
+	EnableStatusCheck             *bool    `json:"enable_status_check"`
+	StatusCheckContexts           []string `json:"status_check_contexts"`
+}
+
+// RulesetViolation represents a recorded violation of a ruleset in evaluate mode
+type RulesetViolation struct {
+	ID int64 `json:"id"`
+	// full name of the repository
+	Repository string `json:"repository"`
+	Ref        string `json:"ref"`
+	Doer       *User  `json:"doer"`
+	Reason     string `json:"reason"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// SecretScanningAlert represents a secret found in the files of a repository
+type SecretScanningAlert struct {
+	ID int64 `json:"id"`
+	// ID of the pattern which matched the secret
+	PatternID   string `json:"pattern_id"`
+	PatternName string `json:"pattern_name"`
+	// the secret with all but its first characters masked
+	RedactedSecret string `json:"redacted_secret"`
+	// the commit and the file the secret has first been found in
+	CommitID string `json:"commit_id"`
+	Path     string `json:"path"`
+	Line     int    `json:"line"`
+	// enum: open,resolved
+	State string `json:"state"`
+	// enum: revoked,false_positive,used_in_tests,wont_fix
+	Resolution        string `json:"resolution,omitempty"`
+	ResolutionComment string `json:"resolution_comment,omitempty"`
+	ResolvedBy        *User  `json:"resolved_by"`
+	// swagger:strfmt date-time
+	ResolvedAt *time.Time `json:"resolved_at"`
+	// true if the secret has been pushed by bypassing the push protection
+	PushProtectionBypassed   bool   `json:"push_protection_bypassed"`
+	PushProtectionBypassedBy *User  `json:"push_protection_bypassed_by"`
+	BypassReason             string `json:"bypass_reason,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditSecretScanningAlertOption options for resolving or reopening a secret scanning alert
+type EditSecretScanningAlertOption struct {
+	// required: true
+	// enum: open,resolved
+	State string `json:"state" binding:"Required"`
+	// the reason why the alert is resolved, required if the state is resolved
+	// enum: revoked,false_positive,used_in_tests,wont_fix
+	Resolution        string `json:"resolution"`
+	ResolutionComment string `json:"resolution_comment"`
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// TerraformState represents a state of the Terraform http backend of a repository
+type TerraformState struct {
+	Name string `json:"name"`
+	// the serial of the latest version, Terraform increases it with every change of the state
+	Serial  int64  `json:"serial"`
+	Lineage string `json:"lineage"`
+	Size    int64  `json:"size"`
+	// the lock of the state, null if the state is not locked
+	Lock *TerraformStateLock `json:"lock"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// TerraformStateLock represents the lock of a Terraform state
+type TerraformStateLock struct {
+	ID string `json:"id"`
+	// the Terraform operation which holds the lock, e.g. "OperationTypeApply"
+	Operation string `json:"operation"`
+	// the user and host reported by Terraform
+	Who    string `json:"who"`
+	Locker *User  `json:"locker"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// TerraformStateVersion represents a version of a Terraform state
+type TerraformStateVersion struct {
+	Serial  int64  `json:"serial"`
+	Lineage string `json:"lineage"`
+	Size    int64  `json:"size"`
+	Creator *User  `json:"creator"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -63,3 +63,35 @@ func NewAlreadyExistErrorf(message string, args ...any) error {
 func NewNotExistErrorf(message string, args ...any) error {
 	return NewSilentWrapErrorf(ErrNotExist, message, args...)
 }
+
+// LocaleWrap is a wrapper for an error which also carries a translation key and its arguments,
+// so that callers rendering a web page can show a localized message for it
+type LocaleWrap struct {
+	err    error
+	TrKey  string
+	TrArgs []any
+}
+
+// Error returns the message of the wrapped error
+func (w *LocaleWrap) Error() string {
+	return w.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (w *LocaleWrap) Unwrap() error {
+	return w.err
+}
+
+// ErrorWrapLocale wraps an err with a translation key and arguments
+func ErrorWrapLocale(err error, trKey string, trArgs ...any) error {
+	return &LocaleWrap{err: err, TrKey: trKey, TrArgs: trArgs}
+}
+
+// ErrorAsLocale returns the LocaleWrap in the chain of err, or nil if there is none
+func ErrorAsLocale(err error) *LocaleWrap {
+	var e *LocaleWrap
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
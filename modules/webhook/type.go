@@ -32,6 +32,9 @@ const (
 	HookEventRelease                   HookEventType = "release"
 	HookEventPackage                   HookEventType = "package"
 	HookEventSchedule                  HookEventType = "schedule"
+	HookEventWorkflowRun               HookEventType = "workflow_run"
+	HookEventWorkflowJob               HookEventType = "workflow_job"
+	HookEventMergeGroup                HookEventType = "merge_group"
 )
 
 // Event returns the HookEventType as an event string
@@ -64,6 +67,12 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventWorkflowRun:
+		return "workflow_run"
+	case HookEventWorkflowJob:
+		return "workflow_job"
+	case HookEventMergeGroup:
+		return "merge_group"
 	}
 	return ""
 }
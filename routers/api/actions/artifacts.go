@@ -243,6 +243,9 @@ func (ar artifactRoutes) uploadArtifact(ctx *ArtifactContext) {
 
 	// get upload file size
 	fileRealTotalSize, contentLength := getUploadFileSize(ctx)
+	if !validateQuota(ctx, task, contentLength) {
+		return
+	}
 
 	// get artifact retention days
 	expiredDays := setting.Actions.ArtifactRetentionDays
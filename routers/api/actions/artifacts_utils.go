@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/actions"
+	quota_model "code.gitea.io/gitea/models/quota"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/util"
 )
@@ -54,6 +55,22 @@ func validateRunIDV4(ctx *ArtifactContext, rawRunID string) (*actions.ActionTask
 	return task, runID, true
 }
 
+// validateQuota checks if the owner of the task's repository may store the additional artifact size
+func validateQuota(ctx *ArtifactContext, task *actions.ActionTask, size int64) bool {
+	ok, err := quota_model.EvaluateForUser(ctx, task.OwnerID, quota_model.LimitSubjectSizeAssetsArtifacts, max(size, 0))
+	if err != nil {
+		log.Error("Error evaluating quota: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error evaluating quota")
+		return false
+	}
+	if !ok {
+		log.Error("Error quota exceeded for owner %d", task.OwnerID)
+		ctx.Error(http.StatusRequestEntityTooLarge, "Quota exceeded")
+		return false
+	}
+	return true
+}
+
 func validateArtifactHash(ctx *ArtifactContext, artifactName string) bool {
 	paramHash := ctx.PathParam("artifact_hash")
 	// use artifact name to create upload url
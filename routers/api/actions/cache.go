@@ -0,0 +1,323 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Cache API Simple Description
+//
+// Runners use the cache server of Gitea by setting `cache.external_server` to `{ROOT_URL}api/actions_cache/`,
+// then `actions/cache` sends the requests with the header `Authorization: Bearer {ACTIONS_RUNTIME_TOKEN}`.
+// Caches are isolated by the ref of the run, see `cacheScopes` in `services/actions/cache.go`.
+//
+// 1. Find a cache to restore
+// GET: /_apis/artifactcache/cache?keys=primary-key,restore-key&version=hash-of-paths
+// Response (204 if no cache matches):
+// {
+//     "result": "hit",
+//     "archiveLocation": "http://localhost:3000/api/actions_cache/_apis/artifactcache/download?sig=...&expires=...&taskID=75&cacheID=1",
+//     "cacheKey": "primary-key"
+// }
+//
+// 2. Save a cache
+// 2.1. Reserve the cache
+// POST: /_apis/artifactcache/caches
+// Request:
+// {
+//     "key": "primary-key",
+//     "version": "hash-of-paths",
+//     "cacheSize": 1024
+// }
+// Response (409 if the cache already exists in the scope):
+// {
+//     "cacheId": 1
+// }
+// 2.2. Upload the content in chunks, they could be uploaded in parallel
+// PATCH: /_apis/artifactcache/caches/{cache_id}
+// Header: Content-Range: bytes 0-1023/*
+// 2.3. Commit the cache
+// POST: /_apis/artifactcache/caches/{cache_id}
+// Request:
+// {
+//     "size": 1024
+// }
+//
+// 3. Download the cache (unauthenticated request, the url is signed)
+// GET: /_apis/artifactcache/download?sig=...&expires=...&taskID=75&cacheID=1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+)
+
+const (
+	cacheRouteBase       = "/_apis/artifactcache"
+	cacheURLExpiresAfter = 60 * time.Minute
+	cacheURLTimeFormat   = "2006-01-02 15:04:05.999999999 -0700 MST"
+)
+
+type cacheRoutes struct {
+	prefix string
+}
+
+func CacheRoutes(prefix string) *web.Router {
+	m := web.NewRouter()
+
+	r := cacheRoutes{
+		prefix: prefix,
+	}
+
+	m.Group(cacheRouteBase, func() {
+		m.Get("/cache", r.findCache)
+		m.Post("/caches", r.reserveCache)
+		m.Combo("/caches/{cache_id}").Patch(r.uploadCache).Post(r.commitCache)
+	}, ArtifactContexter())
+	m.Group(cacheRouteBase, func() {
+		m.Get("/download", r.downloadCache)
+	}, ArtifactV4Contexter())
+
+	return m
+}
+
+func buildCacheSignature(endp, expires string, taskID, cacheID int64) []byte {
+	mac := hmac.New(sha256.New, setting.GetGeneralTokenSigningSecret())
+	mac.Write([]byte("actions_cache"))
+	mac.Write([]byte(endp))
+	mac.Write([]byte(expires))
+	mac.Write([]byte(fmt.Sprint(taskID)))
+	mac.Write([]byte(fmt.Sprint(cacheID)))
+	return mac.Sum(nil)
+}
+
+// buildCacheURL returns the signed url of the endpoint for the cache, so the runner can access it without the token
+func buildCacheURL(ctx *ArtifactContext, prefix, endp string, taskID, cacheID int64) string {
+	expires := time.Now().Add(cacheURLExpiresAfter).Format(cacheURLTimeFormat)
+	return strings.TrimSuffix(httplib.GuessCurrentAppURL(ctx), "/") + strings.TrimSuffix(prefix, "/") +
+		"/" + endp + "?sig=" + base64.URLEncoding.EncodeToString(buildCacheSignature(endp, expires, taskID, cacheID)) +
+		"&expires=" + url.QueryEscape(expires) + "&taskID=" + fmt.Sprint(taskID) + "&cacheID=" + fmt.Sprint(cacheID)
+}
+
+// verifyCacheSignature checks the signed url built by buildCacheURL and returns the running task and the cache
+func verifyCacheSignature(ctx *ArtifactContext, endp string) (*actions.ActionTask, *actions.ActionCache, bool) {
+	query := ctx.Req.URL.Query()
+	sig, _ := base64.URLEncoding.DecodeString(query.Get("sig"))
+	expires := query.Get("expires")
+	taskID, _ := strconv.ParseInt(query.Get("taskID"), 10, 64)
+	cacheID, _ := strconv.ParseInt(query.Get("cacheID"), 10, 64)
+
+	if !hmac.Equal(sig, buildCacheSignature(endp, expires, taskID, cacheID)) {
+		log.Error("Error unauthorized")
+		ctx.Error(http.StatusUnauthorized, "Error unauthorized")
+		return nil, nil, false
+	}
+	t, err := time.Parse(cacheURLTimeFormat, expires)
+	if err != nil || t.Before(time.Now()) {
+		log.Error("Error link expired")
+		ctx.Error(http.StatusUnauthorized, "Error link expired")
+		return nil, nil, false
+	}
+	task, err := actions.GetTaskByID(ctx, taskID)
+	if err != nil {
+		log.Error("Error runner api getting task by ID: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error runner api getting task by ID")
+		return nil, nil, false
+	}
+	if task.Status != actions.StatusRunning {
+		log.Error("Error runner api getting task: task is not running")
+		ctx.Error(http.StatusInternalServerError, "Error runner api getting task: task is not running")
+		return nil, nil, false
+	}
+	c, err := actions.GetCacheByID(ctx, cacheID)
+	if err == nil && c.RepoID != task.RepoID {
+		err = util.ErrNotExist
+	}
+	if err != nil {
+		handleCacheError(ctx, err)
+		return nil, nil, false
+	}
+	return task, c, true
+}
+
+// handleCacheError responds the error returned by the cache service with the matching status
+func handleCacheError(ctx *ArtifactContext, err error) {
+	switch {
+	case errors.Is(err, util.ErrNotExist):
+		ctx.Error(http.StatusNotFound, err.Error())
+	case errors.Is(err, util.ErrAlreadyExist):
+		ctx.Error(http.StatusConflict, err.Error())
+	case errors.Is(err, util.ErrPermissionDenied):
+		ctx.Error(http.StatusForbidden, err.Error())
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.Error(http.StatusBadRequest, err.Error())
+	default:
+		log.Error("Error handling actions cache: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error handling actions cache")
+	}
+}
+
+// serveCache sends the content of a complete cache, or redirects to the storage if it's served directly
+func serveCache(ctx *ArtifactContext, c *actions.ActionCache) {
+	if !c.Complete {
+		ctx.Error(http.StatusNotFound, "Error cache is not committed")
+		return
+	}
+	if setting.Actions.CacheStorage.ServeDirect() {
+		u, err := storage.ActionsCache.URL(c.StoragePath(), fmt.Sprintf("cache-%d", c.ID))
+		if err != nil && !errors.Is(err, storage.ErrURLNotSupported) {
+			log.Error("Error getting serve direct url: %v", err)
+		}
+		if u != nil {
+			ctx.Redirect(u.String())
+			return
+		}
+	}
+
+	fd, err := storage.ActionsCache.Open(c.StoragePath())
+	if err != nil {
+		log.Error("Error opening cache %d: %v", c.ID, err)
+		ctx.Error(http.StatusInternalServerError, "Error opening cache")
+		return
+	}
+	defer fd.Close()
+
+	ctx.ServeContent(fd, &context.ServeHeaderOptions{
+		Filename:      fmt.Sprintf("cache-%d", c.ID),
+		ContentLength: &c.Size,
+		LastModified:  c.CreatedUnix.AsLocalTime(),
+	})
+}
+
+type findCacheResponse struct {
+	Result          string `json:"result"`
+	ArchiveLocation string `json:"archiveLocation"`
+	CacheKey        string `json:"cacheKey"`
+}
+
+// findCache returns the cache matching the keys in the order of the scopes
+func (r cacheRoutes) findCache(ctx *ArtifactContext) {
+	keys := strings.Split(ctx.Req.URL.Query().Get("keys"), ",")
+	version := ctx.Req.URL.Query().Get("version")
+
+	c, err := actions_service.FindCache(ctx, ctx.ActionTask, keys, version)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Status(http.StatusNoContent)
+		return
+	} else if err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, findCacheResponse{
+		Result:          "hit",
+		ArchiveLocation: buildCacheURL(ctx, r.prefix+cacheRouteBase, "download", ctx.ActionTask.ID, c.ID),
+		CacheKey:        c.CacheKey,
+	})
+}
+
+type reserveCacheRequest struct {
+	Key       string `json:"key"`
+	Version   string `json:"version"`
+	CacheSize int64  `json:"cacheSize"`
+}
+
+type reserveCacheResponse struct {
+	CacheID int64 `json:"cacheId"`
+}
+
+// reserveCache creates the cache to upload in the scope of the run
+func (r cacheRoutes) reserveCache(ctx *ArtifactContext) {
+	var req reserveCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+
+	c, err := actions_service.ReserveCache(ctx, ctx.ActionTask, req.Key, req.Version, req.CacheSize)
+	if err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, reserveCacheResponse{CacheID: c.ID})
+}
+
+func (r cacheRoutes) getUploadingCache(ctx *ArtifactContext) (*actions.ActionCache, bool) {
+	c, err := actions_service.GetUploadingCacheByID(ctx, ctx.ActionTask, ctx.PathParamInt64("cache_id"))
+	if err != nil {
+		handleCacheError(ctx, err)
+		return nil, false
+	}
+	return c, true
+}
+
+// uploadCache saves a chunk of the cache at the offset in the header Content-Range
+func (r cacheRoutes) uploadCache(ctx *ArtifactContext) {
+	c, ok := r.getUploadingCache(ctx)
+	if !ok {
+		return
+	}
+
+	var start, end int64
+	if _, err := fmt.Sscanf(ctx.Req.Header.Get("Content-Range"), "bytes %d-%d/*", &start, &end); err != nil || start < 0 || end < start {
+		log.Error("Error parse content range: %s", ctx.Req.Header.Get("Content-Range"))
+		ctx.Error(http.StatusBadRequest, "Error parse content range")
+		return
+	}
+
+	if err := actions_service.UploadCacheChunk(ctx, c, start, ctx.Req.Body, end-start+1); err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+type commitCacheRequest struct {
+	Size int64 `json:"size"`
+}
+
+// commitCache merges the uploaded chunks and makes the cache available to restore
+func (r cacheRoutes) commitCache(ctx *ArtifactContext) {
+	c, ok := r.getUploadingCache(ctx)
+	if !ok {
+		return
+	}
+
+	var req commitCacheRequest
+	if err := json.NewDecoder(ctx.Req.Body).Decode(&req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error decode request body")
+		return
+	}
+
+	if err := actions_service.CommitCache(ctx, c, req.Size); err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func (r cacheRoutes) downloadCache(ctx *ArtifactContext) {
+	_, c, ok := verifyCacheSignature(ctx, "download")
+	if !ok {
+		return
+	}
+	serveCache(ctx, c)
+}
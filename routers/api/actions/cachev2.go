@@ -0,0 +1,205 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Cache V2 API Simple Description
+//
+// `actions/cache` uses this twirp-based service when `ACTIONS_CACHE_SERVICE_V2` is set in the environment of the job,
+// the content is uploaded and downloaded in the same way as the artifacts V4 API.
+//
+// 1. Save a cache
+// 1.1. CreateCacheEntry
+// Post: /twirp/github.actions.results.api.v1.CacheService/CreateCacheEntry
+// Request:
+// {
+//     "key": "primary-key",
+//     "version": "hash-of-paths"
+// }
+// Response ("ok" is false if the cache already exists in the scope):
+// {
+//     "ok": true,
+//     "signedUploadUrl": "http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/UploadCache?sig=...&expires=...&taskID=75&cacheID=1"
+// }
+// 1.2. Upload the content to Blobstorage (unauthenticated request)
+// PUT: http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/UploadCache?sig=...&expires=...&taskID=75&cacheID=1
+// Large content is uploaded in blocks with "comp=block&blockid=..." and committed by a BlockList xml payload with "comp=blocklist".
+// 1.3. FinalizeCacheEntryUpload
+// Post: /twirp/github.actions.results.api.v1.CacheService/FinalizeCacheEntryUpload
+// Request:
+// {
+//     "key": "primary-key",
+//     "sizeBytes": "1024",
+//     "version": "hash-of-paths"
+// }
+// Response:
+// {
+//     "ok": true,
+//     "entryId": "1"
+// }
+//
+// 2. Restore a cache
+// 2.1. GetCacheEntryDownloadURL
+// Post: /twirp/github.actions.results.api.v1.CacheService/GetCacheEntryDownloadURL
+// Request:
+// {
+//     "key": "primary-key",
+//     "restoreKeys": ["restore-key"],
+//     "version": "hash-of-paths"
+// }
+// Response ("ok" is false if no cache matches):
+// {
+//     "ok": true,
+//     "signedDownloadUrl": "http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/DownloadCache?sig=...&expires=...&taskID=75&cacheID=1",
+//     "matchedKey": "primary-key"
+// }
+// 2.2. Download the content from Blobstorage (unauthenticated request)
+// GET: http://localhost:3000/twirp/github.actions.results.api.v1.CacheService/DownloadCache?sig=...&expires=...&taskID=75&cacheID=1
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+)
+
+const CacheV2RouteBase = "/twirp/github.actions.results.api.v1.CacheService"
+
+type cacheV2Routes struct {
+	prefix string
+}
+
+func CacheV2Routes(prefix string) *web.Router {
+	m := web.NewRouter()
+
+	r := cacheV2Routes{
+		prefix: prefix,
+	}
+
+	m.Group("", func() {
+		m.Post("CreateCacheEntry", r.createCacheEntry)
+		m.Post("FinalizeCacheEntryUpload", r.finalizeCacheEntryUpload)
+		m.Post("GetCacheEntryDownloadURL", r.getCacheEntryDownloadURL)
+	}, ArtifactContexter())
+	m.Group("", func() {
+		m.Put("UploadCache", r.uploadCache)
+		m.Get("DownloadCache", r.downloadCache)
+	}, ArtifactV4Contexter())
+
+	return m
+}
+
+func (r cacheV2Routes) createCacheEntry(ctx *ArtifactContext) {
+	var req CreateCacheEntryRequest
+	if ok := parseProtbufBody(ctx, &req); !ok {
+		return
+	}
+
+	c, err := actions_service.ReserveCache(ctx, ctx.ActionTask, req.Key, req.Version, 0)
+	if errors.Is(err, util.ErrAlreadyExist) || errors.Is(err, util.ErrInvalidArgument) {
+		sendProtbufBody(ctx, &CreateCacheEntryResponse{Ok: false, Message: err.Error()})
+		return
+	} else if err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+
+	sendProtbufBody(ctx, &CreateCacheEntryResponse{
+		Ok:              true,
+		SignedUploadUrl: buildCacheURL(ctx, r.prefix, "UploadCache", ctx.ActionTask.ID, c.ID),
+	})
+}
+
+func (r cacheV2Routes) uploadCache(ctx *ArtifactContext) {
+	task, c, ok := verifyCacheSignature(ctx, "UploadCache")
+	if !ok {
+		return
+	}
+	if err := actions_service.CheckUploadingCache(ctx, task, c); err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+
+	var err error
+	switch ctx.Req.URL.Query().Get("comp") {
+	case "block":
+		err = actions_service.UploadCacheBlock(ctx, c, ctx.Req.URL.Query().Get("blockid"), ctx.Req.Body, ctx.Req.ContentLength)
+	case "blocklist":
+		blockList := &BlockList{}
+		if err := xml.NewDecoder(ctx.Req.Body).Decode(blockList); err != nil {
+			log.Error("Error decode block list: %v", err)
+			ctx.Error(http.StatusBadRequest, "Error decode block list")
+			return
+		}
+		err = actions_service.CommitCacheBlocks(c, blockList.Latest)
+	case "":
+		err = actions_service.UploadCacheBlob(ctx, c, ctx.Req.Body, ctx.Req.ContentLength)
+	default:
+		ctx.Error(http.StatusBadRequest, "Error unsupported comp")
+		return
+	}
+	if err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusCreated)
+}
+
+func (r cacheV2Routes) finalizeCacheEntryUpload(ctx *ArtifactContext) {
+	var req FinalizeCacheEntryUploadRequest
+	if ok := parseProtbufBody(ctx, &req); !ok {
+		return
+	}
+
+	c, err := actions_service.GetUploadingCache(ctx, ctx.ActionTask, req.Key, req.Version)
+	if err == nil {
+		err = actions_service.CommitCache(ctx, c, req.SizeBytes)
+	}
+	if errors.Is(err, util.ErrNotExist) || errors.Is(err, util.ErrInvalidArgument) {
+		sendProtbufBody(ctx, &FinalizeCacheEntryUploadResponse{Ok: false, Message: err.Error()})
+		return
+	} else if err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+
+	sendProtbufBody(ctx, &FinalizeCacheEntryUploadResponse{
+		Ok:      true,
+		EntryId: c.ID,
+	})
+}
+
+func (r cacheV2Routes) getCacheEntryDownloadURL(ctx *ArtifactContext) {
+	var req GetCacheEntryDownloadURLRequest
+	if ok := parseProtbufBody(ctx, &req); !ok {
+		return
+	}
+
+	keys := append([]string{req.Key}, req.RestoreKeys...)
+	c, err := actions_service.FindCache(ctx, ctx.ActionTask, keys, req.Version)
+	if errors.Is(err, util.ErrNotExist) {
+		sendProtbufBody(ctx, &GetCacheEntryDownloadURLResponse{Ok: false})
+		return
+	} else if err != nil {
+		handleCacheError(ctx, err)
+		return
+	}
+
+	sendProtbufBody(ctx, &GetCacheEntryDownloadURLResponse{
+		Ok:                true,
+		SignedDownloadUrl: buildCacheURL(ctx, r.prefix, "DownloadCache", ctx.ActionTask.ID, c.ID),
+		MatchedKey:        c.CacheKey,
+	})
+}
+
+func (r cacheV2Routes) downloadCache(ctx *ArtifactContext) {
+	_, c, ok := verifyCacheSignature(ctx, "DownloadCache")
+	if !ok {
+		return
+	}
+	serveCache(ctx, c)
+}
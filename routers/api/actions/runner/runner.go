@@ -175,6 +175,17 @@ func (s *Service) UpdateTask(
 	ctx context.Context,
 	req *connect.Request[runnerv1.UpdateTaskRequest],
 ) (*connect.Response[runnerv1.UpdateTaskResponse], error) {
+	// The runner resends the final state until it has been acknowledged,
+	// only the first one finishes the job and may complete its run.
+	isFinished := false
+	if req.Msg.State.Result != runnerv1.Result_RESULT_UNSPECIFIED {
+		storedTask, err := actions_model.GetTaskByID(ctx, req.Msg.State.Id)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "get task: %v", err)
+		}
+		isFinished = !storedTask.Status.IsDone()
+	}
+
 	task, err := actions_model.UpdateTaskByState(ctx, req.Msg.State)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "update task: %v", err)
@@ -218,6 +229,9 @@ func (s *Service) UpdateTask(
 	}
 
 	if req.Msg.State.Result != runnerv1.Result_RESULT_UNSPECIFIED {
+		if isFinished {
+			actions_service.NotifyWorkflowJobsStatusUpdate(ctx, task.Job)
+		}
 		if err := actions_service.EmitJobsIfReady(task.Job.RunID); err != nil {
 			log.Error("Emit ready jobs of run %d: %v", task.Job.RunID, err)
 		}
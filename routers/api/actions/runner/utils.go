@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/services/actions"
 
@@ -30,23 +31,41 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 	if !ok {
 		return nil, false, nil
 	}
+	metrics.ObserveActionsJobWait(t.Started.AsTime().Sub(t.Job.Created.AsTime()))
 
 	secrets, err := secret_model.GetSecretsOfTask(ctx, t)
 	if err != nil {
 		return nil, false, fmt.Errorf("GetSecretsOfTask: %w", err)
 	}
 
-	vars, err := actions_model.GetVariablesOfRun(ctx, t.Job.Run)
+	vars, err := actions_model.GetVariablesOfRunJob(ctx, t.Job)
 	if err != nil {
-		return nil, false, fmt.Errorf("GetVariablesOfRun: %w", err)
+		return nil, false, fmt.Errorf("GetVariablesOfRunJob: %w", err)
+	}
+
+	var call *actions.WorkflowCall
+	if t.Job.ParentJobID != 0 {
+		// the job is expanded from a reusable workflow, it gets the inputs and secrets passed by the calling job
+		if call, err = actions.EvaluateWorkflowCall(ctx, t.Job, secrets, vars); err != nil {
+			log.Error("Cannot evaluate workflow call for task %v: %v", t.ID, err)
+			// Go on with the automatically generated tokens only, like findTaskNeeds does with missing needs.
+			call = &actions.WorkflowCall{
+				Secrets: map[string]string{
+					"GITHUB_TOKEN": t.Token,
+					"GITEA_TOKEN":  t.Token,
+				},
+			}
+		}
+		secrets = call.Secrets
 	}
 
 	actions.CreateCommitStatus(ctx, t.Job)
+	actions.NotifyWorkflowJobsStatusUpdate(ctx, t.Job)
 
 	task := &runnerv1.Task{
 		Id:              t.ID,
 		WorkflowPayload: t.Job.WorkflowPayload,
-		Context:         generateTaskContext(t),
+		Context:         generateTaskContext(t, call),
 		Secrets:         secrets,
 		Vars:            vars,
 	}
@@ -64,7 +83,9 @@ func pickTask(ctx context.Context, runner *actions_model.ActionRunner) (*runnerv
 	return task, true, nil
 }
 
-func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
+// generateTaskContext generates the github context of the task,
+// call is the workflow call of the reusable workflow which the job is expanded from, or nil for the top-level jobs
+func generateTaskContext(t *actions_model.ActionTask, call *actions.WorkflowCall) *structpb.Struct {
 	event := map[string]any{}
 	_ = json.Unmarshal([]byte(t.Job.Run.EventPayload), &event)
 
@@ -76,6 +97,12 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 		eventName = t.Job.Run.Event.Event()
 	}
 
+	// the runner reads the inputs of a reusable workflow from the event
+	if call != nil {
+		eventName = "workflow_call"
+		event["inputs"] = call.Inputs
+	}
+
 	baseRef := ""
 	headRef := ""
 	ref := t.Job.Run.Ref
@@ -162,22 +189,21 @@ func findTaskNeeds(ctx context.Context, task *actions_model.ActionTask) (map[str
 		return nil, fmt.Errorf("FindRunJobs: %w", err)
 	}
 
+	callerIDs := actions_model.ActionJobList(jobs).GetCallerIDs()
 	ret := make(map[string]*runnerv1.TaskNeed, len(needs))
 	for _, job := range jobs {
-		if !needs.Contains(job.JobID) {
+		// the needs are the jobs expanded from the same reusable workflow, or the top-level jobs
+		if job.ParentJobID != task.Job.ParentJobID || !needs.Contains(job.JobID) {
 			continue
 		}
-		if job.TaskID == 0 || !job.Status.IsDone() {
+		if (job.TaskID == 0 && !callerIDs.Contains(job.ID)) || !job.Status.IsDone() {
 			// it shouldn't happen, or the job has been rerun
 			continue
 		}
-		outputs := make(map[string]string)
-		got, err := actions_model.FindTaskOutputByTaskID(ctx, job.TaskID)
+		// the outputs of a job calling a reusable workflow are evaluated with the jobs expanded from it
+		outputs, err := actions.GetJobOutputs(ctx, job, jobs)
 		if err != nil {
-			return nil, fmt.Errorf("FindTaskOutputByTaskID: %w", err)
-		}
-		for _, v := range got {
-			outputs[v.OutputKey] = v.OutputValue
+			return nil, fmt.Errorf("GetJobOutputs: %w", err)
 		}
 		ret[job.JobID] = &runnerv1.TaskNeed{
 			Outputs: outputs,
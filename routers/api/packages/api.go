@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/packages/alpine"
+	"code.gitea.io/gitea/routers/api/packages/arch"
 	"code.gitea.io/gitea/routers/api/packages/cargo"
 	"code.gitea.io/gitea/routers/api/packages/chef"
 	"code.gitea.io/gitea/routers/api/packages/composer"
@@ -33,6 +34,7 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/rpm"
 	"code.gitea.io/gitea/routers/api/packages/rubygems"
 	"code.gitea.io/gitea/routers/api/packages/swift"
+	"code.gitea.io/gitea/routers/api/packages/terraform"
 	"code.gitea.io/gitea/routers/api/packages/vagrant"
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/context"
@@ -121,6 +123,18 @@ func CommonRoutes() *web.Router {
 		&chef.Auth{},
 	})
 
+	// Terraform resolves the registry endpoints by service discovery, so the owner is part of the module and provider address
+	r.Group("/-/terraform", func() {
+		r.Group("/modules/v1/{username}/{name}/{system}", func() {
+			r.Get("/versions", terraform.ListModuleVersions)
+			r.Get("/{version}/download", terraform.DownloadModule)
+		})
+		r.Group("/providers/v1/{username}/{provider}", func() {
+			r.Get("/versions", terraform.ListProviderVersions)
+			r.Get("/{version}/download/{os}/{arch}", terraform.GetProviderPackage)
+		})
+	}, context.UserAssignmentWeb(), context.PackageAssignment(), reqPackageAccess(perm.AccessModeRead))
+
 	r.Group("/{username}", func() {
 		r.Group("/alpine", func() {
 			r.Get("/key", alpine.GetRepositoryKey)
@@ -135,6 +149,14 @@ func CommonRoutes() *web.Router {
 				})
 			})
 		}, reqPackageAccess(perm.AccessModeRead))
+		r.Group("/arch", func() {
+			r.Methods("HEAD,GET", "/repository.key", arch.GetRepositoryKey)
+			r.Group("/{repository}", func() {
+				r.Put("", reqPackageAccess(perm.AccessModeWrite), arch.UploadPackageFile)
+				r.Get("/{architecture}/{filename}", arch.GetRepositoryFile)
+				r.Delete("/{name}/{version}/{architecture}", reqPackageAccess(perm.AccessModeWrite), arch.DeletePackageFile)
+			})
+		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/cargo", func() {
 			r.Group("/api/v1/crates", func() {
 				r.Get("", cargo.SearchPackages)
@@ -505,6 +527,8 @@ func CommonRoutes() *web.Router {
 			r.Group("/-/v1/search", func() {
 				r.Get("", npm.PackageSearch)
 			})
+			r.Get("/-/npm/v1/attestations/@{scope}/{id}", npm.PackageAttestations)
+			r.Get("/-/npm/v1/attestations/{id}", npm.PackageAttestations)
 		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/pub", func() {
 			r.Group("/api/packages", func() {
@@ -523,6 +547,7 @@ func CommonRoutes() *web.Router {
 		r.Group("/pypi", func() {
 			r.Post("/", reqPackageAccess(perm.AccessModeWrite), pypi.UploadPackageFile)
 			r.Get("/files/{id}/{version}/{filename}", pypi.DownloadPackageFile)
+			r.Get("/integrity/{id}/{version}/{filename}/provenance", pypi.PackageFileProvenance)
 			r.Get("/simple/{id}", pypi.PackageMetadata)
 		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/rpm", func() {
@@ -644,6 +669,22 @@ func CommonRoutes() *web.Router {
 			})
 			r.Get("/identifiers", swift.CheckAcceptMediaType(swift.AcceptJSON), swift.LookupPackageIdentifiers)
 		}, reqPackageAccess(perm.AccessModeRead))
+		r.Group("/terraform", func() {
+			r.Group("/modules/{name}/{system}/{version}", func() {
+				r.Put("", reqPackageAccess(perm.AccessModeWrite), terraform.UploadModule)
+				r.Delete("", reqPackageAccess(perm.AccessModeWrite), terraform.DeleteModule)
+				r.Get("/{filename}", terraform.DownloadModuleFile)
+			})
+			r.Group("/providers/{provider}/{version}", func() {
+				r.Get("/SHA256SUMS", terraform.GetProviderShasums)
+				r.Get("/SHA256SUMS.sig", terraform.GetProviderShasumsSignature)
+				r.Group("/{os}/{arch}", func() {
+					r.Put("", reqPackageAccess(perm.AccessModeWrite), terraform.UploadProvider)
+					r.Delete("", reqPackageAccess(perm.AccessModeWrite), terraform.DeleteProviderFile)
+					r.Get("/{filename}", terraform.DownloadProviderFile)
+				})
+			})
+		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/vagrant", func() {
 			r.Group("/authenticate", func() {
 				r.Get("", vagrant.CheckAuthenticate)
@@ -704,12 +745,14 @@ func ContainerRoutes() *web.Router {
 				r.Delete("", reqPackageAccess(perm.AccessModeWrite), container.DeleteManifest)
 			})
 			r.Get("/tags/list", container.GetTagList)
+			r.Get("/referrers/{digest}", container.GetReferrers)
 		}, container.VerifyImageName)
 
 		var (
 			blobsUploadsPattern = regexp.MustCompile(`\A(.+)/blobs/uploads/([a-zA-Z0-9-_.=]+)\z`)
 			blobsPattern        = regexp.MustCompile(`\A(.+)/blobs/([^/]+)\z`)
 			manifestsPattern    = regexp.MustCompile(`\A(.+)/manifests/([^/]+)\z`)
+			referrersPattern    = regexp.MustCompile(`\A(.+)/referrers/([^/]+)\z`)
 		)
 
 		// Manual mapping of routes because {image} can contain slashes which chi does not support
@@ -797,6 +840,19 @@ func ContainerRoutes() *web.Router {
 				}
 				return
 			}
+			m = referrersPattern.FindStringSubmatch(path)
+			if len(m) == 3 && isGet {
+				ctx.SetPathParam("image", m[1])
+				container.VerifyImageName(ctx)
+				if ctx.Written() {
+					return
+				}
+
+				ctx.SetPathParam("digest", m[2])
+
+				container.GetReferrers(ctx)
+				return
+			}
 			m = manifestsPattern.FindStringSubmatch(path)
 			if len(m) == 3 && (isHead || isGet || isPut || isDelete) {
 				ctx.SetPathParam("image", m[1])
@@ -32,6 +32,8 @@ import (
 	container_service "code.gitea.io/gitea/services/packages/container"
 
 	digest "github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
 // maximum size of a container manifest
@@ -599,6 +601,11 @@ func UploadManifest(ctx *context.Context) {
 		return
 	}
 
+	// https://github.com/opencontainers/distribution-spec/blob/main/spec.md#pushing-manifests-with-subject
+	if mci.Subject != "" {
+		ctx.Resp.Header().Set("OCI-Subject", mci.Subject)
+	}
+
 	setResponseHeaders(ctx.Resp, &containerHeaders{
 		Location:      fmt.Sprintf("/v2/%s/%s/manifests/%s", ctx.Package.Owner.LowerName, mci.Image, reference),
 		ContentDigest: digest,
@@ -690,6 +697,16 @@ func DeleteManifest(ctx *context.Context) {
 		return
 	}
 
+	manifestDigest := opts.Digest
+	if manifestDigest == "" {
+		pfd, err := container_model.GetContainerBlob(ctx, opts)
+		if err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		manifestDigest = pfd.Properties.GetByName(container_module.PropertyDigest)
+	}
+
 	for _, pv := range pvs {
 		if err := packages_service.RemovePackageVersion(ctx, ctx.Doer, pv); err != nil {
 			apiError(ctx, http.StatusInternalServerError, err)
@@ -697,6 +714,11 @@ func DeleteManifest(ctx *context.Context) {
 		}
 	}
 
+	if err := container_service.RemoveReferrers(ctx, ctx.Package.Owner.ID, opts.Image, manifestDigest); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
 	setResponseHeaders(ctx.Resp, &containerHeaders{
 		Status: http.StatusAccepted,
 	})
@@ -778,6 +800,39 @@ func GetTagList(ctx *context.Context) {
 	})
 }
 
+// https://github.com/opencontainers/distribution-spec/blob/main/spec.md#listing-referrers
+func GetReferrers(ctx *context.Context) {
+	subject := ctx.PathParam("digest")
+	if digest.Digest(subject).Validate() != nil {
+		apiErrorDefined(ctx, errDigestInvalid)
+		return
+	}
+
+	artifactType := ctx.FormTrim("artifactType")
+
+	descriptors, err := container_service.GetReferrers(ctx, ctx.Package.Owner.ID, ctx.PathParam("image"), subject, artifactType)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	if artifactType != "" {
+		ctx.Resp.Header().Set("OCI-Filters-Applied", "artifactType")
+	}
+
+	setResponseHeaders(ctx.Resp, &containerHeaders{
+		ContentType: oci.MediaTypeImageIndex,
+		Status:      http.StatusOK,
+	})
+	if err := json.NewEncoder(ctx.Resp).Encode(&oci.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: oci.MediaTypeImageIndex,
+		Manifests: descriptors,
+	}); err != nil {
+		log.Error("JSON encode: %v", err)
+	}
+}
+
 // FIXME: Workaround to be removed in v1.20
 // https://github.com/go-gitea/gitea/issues/19586
 func workaroundGetContainerBlob(ctx *context.Context, opts *container_model.BlobSearchOptions) (*packages_model.PackageFileDescriptor, error) {
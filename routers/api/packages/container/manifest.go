@@ -47,6 +47,7 @@ type manifestCreationInfo struct {
 	Image      string
 	Reference  string
 	IsTagged   bool
+	Subject    string
 	Properties map[string]string
 }
 
@@ -71,6 +72,14 @@ func processManifest(ctx context.Context, mci *manifestCreationInfo, buf *packag
 		}
 	}
 
+	// https://github.com/opencontainers/image-spec/blob/main/manifest.md#image-manifest-property-descriptions
+	if index.Subject != nil {
+		if index.Subject.Digest.Validate() != nil {
+			return "", errManifestInvalid.WithMessage("Subject digest is invalid")
+		}
+		mci.Subject = string(index.Subject.Digest)
+	}
+
 	if isImageManifestMediaType(mci.MediaType) {
 		return processImageManifest(ctx, mci, buf)
 	} else if isImageIndexMediaType(mci.MediaType) {
@@ -118,6 +127,13 @@ func processImageManifest(ctx context.Context, mci *manifestCreationInfo, buf *p
 			return err
 		}
 
+		// the artifact type of an image manifest defaults to the media type of the config
+		metadata.ArtifactType = manifest.ArtifactType
+		if metadata.ArtifactType == "" {
+			metadata.ArtifactType = manifest.Config.MediaType
+		}
+		metadata.Annotations = manifest.Annotations
+
 		blobReferences := make([]*blobReference, 0, 1+len(manifest.Layers))
 
 		blobReferences = append(blobReferences, &blobReference{
@@ -176,6 +192,11 @@ func processImageManifest(ctx context.Context, mci *manifestCreationInfo, buf *p
 			return err
 		}
 
+		if err := createComponents(ctx, mci, pv, &manifest, blobReferences[1:], digest); err != nil {
+			removeBlob = created
+			return err
+		}
+
 		if err := committer.Commit(); err != nil {
 			removeBlob = created
 			return err
@@ -216,8 +237,10 @@ func processImageManifestIndex(ctx context.Context, mci *manifestCreationInfo, b
 		defer committer.Close()
 
 		metadata := &container_module.Metadata{
-			Type:      container_module.TypeOCI,
-			Manifests: make([]*container_module.Manifest, 0, len(index.Manifests)),
+			Type:         container_module.TypeOCI,
+			Manifests:    make([]*container_module.Manifest, 0, len(index.Manifests)),
+			ArtifactType: index.ArtifactType,
+			Annotations:  index.Annotations,
 		}
 
 		for _, manifest := range index.Manifests {
@@ -265,6 +288,20 @@ func processImageManifestIndex(ctx context.Context, mci *manifestCreationInfo, b
 			return err
 		}
 
+		// BuildKit stores the SBOM in an attestation manifest which is linked to the image manifest by annotations
+		for _, manifest := range index.Manifests {
+			if manifest.Annotations[container_module.AnnotationDockerReferenceType] != container_module.DockerReferenceTypeAttestation {
+				continue
+			}
+			reference := manifest.Annotations[container_module.AnnotationDockerReferenceDigest]
+			if reference == "" {
+				continue
+			}
+			if err := container_model.UpdateComponentsSubject(ctx, pv.PackageID, string(manifest.Digest), reference); err != nil {
+				return err
+			}
+		}
+
 		pb, created, digest, err := createManifestBlob(ctx, mci, pv, buf)
 		removeBlob := false
 		defer func() {
@@ -386,6 +423,12 @@ func createPackageAndVersion(ctx context.Context, mci *manifestCreationInfo, met
 			return nil, err
 		}
 	}
+	if mci.Subject != "" {
+		if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestSubject, mci.Subject); err != nil {
+			log.Error("Error setting package version property: %v", err)
+			return nil, err
+		}
+	}
 
 	return pv, nil
 }
@@ -446,6 +489,56 @@ func createFileFromBlobReference(ctx context.Context, pv, uploadVersion *package
 	return nil
 }
 
+// createComponents stores the components listed in the SBOM layers of the manifest.
+// The components describe the subject of the manifest or the manifest itself if it has no subject.
+func createComponents(ctx context.Context, mci *manifestCreationInfo, pv *packages_model.PackageVersion, manifest *oci.Manifest, layers []*blobReference, manifestDigest string) error {
+	subject := mci.Subject
+	if subject == "" {
+		subject = manifestDigest
+	}
+
+	isSBOMArtifact := container_module.IsSBOMArtifactType(manifest.ArtifactType)
+
+	var components []*container_model.Component
+	for i, layer := range manifest.Layers {
+		if !isSBOMArtifact && !container_module.IsSBOMLayer(layer) {
+			continue
+		}
+		if layer.Size > container_module.MaxSBOMSize {
+			log.Warn("Skipping SBOM layer %s of %s: too large", layer.Digest, mci.Image)
+			continue
+		}
+
+		r, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(layers[i].File.Blob.HashSHA256))
+		if err != nil {
+			return err
+		}
+		parsed, err := container_module.ParseSBOM(r)
+		r.Close()
+		if err != nil {
+			// an invalid SBOM must not prevent pushing the image
+			log.Warn("Skipping SBOM layer %s of %s: %v", layer.Digest, mci.Image, err)
+			continue
+		}
+
+		for _, c := range parsed {
+			components = append(components, &container_model.Component{
+				OwnerID:   mci.Owner.ID,
+				PackageID: pv.PackageID,
+				VersionID: pv.ID,
+				Subject:   subject,
+				Name:      c.Name,
+				Version:   c.Version,
+				Type:      c.Type,
+				PURL:      c.PURL,
+				License:   c.License,
+			})
+		}
+	}
+
+	return container_model.InsertComponents(ctx, components)
+}
+
 func createManifestBlob(ctx context.Context, mci *manifestCreationInfo, pv *packages_model.PackageVersion, buf *packages_module.HashedBuffer) (*packages_model.PackageBlob, bool, string, error) {
 	pb, exists, err := packages_model.GetOrInsertBlob(ctx, packages_service.NewPackageBlob(buf))
 	if err != nil {
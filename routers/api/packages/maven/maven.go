@@ -18,6 +18,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/globallock"
@@ -25,9 +26,12 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	maven_module "code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+	packages_virtual_service "code.gitea.io/gitea/services/packages/virtual"
 )
 
 const (
@@ -86,7 +90,24 @@ func serveMavenMetadata(ctx *context.Context, params parameters) {
 	// /com/foo/project/maven-metadata.xml[.md5/.sha1/.sha256/.sha512]
 
 	packageName := params.GroupID + "-" + params.ArtifactID
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, packageName)
+
+	pu, err := packages_proxy_service.GetUpstreamForPackage(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pu != nil {
+		puc, err := packages_proxy_service.GetMetadata(ctx, pu, upstreamPath(ctx), contentTypeXML)
+		if err == nil {
+			serveMetadata(ctx, params, puc.Content, puc.FetchedUnix.AsTime())
+			return
+		}
+		if !errors.Is(err, util.ErrNotExist) {
+			log.Warn("Unable to fetch maven metadata of %s from upstream: %v", packageName, err)
+		}
+	}
+
+	pvs, err := packages_virtual_service.GetVersionsByPackageName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypeMaven, packageName)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -115,9 +136,12 @@ func serveMavenMetadata(ctx *context.Context, params parameters) {
 	xmlMetadataWithHeader := append([]byte(xml.Header), xmlMetadata...)
 
 	latest := pds[len(pds)-1]
+	serveMetadata(ctx, params, xmlMetadataWithHeader, latest.Version.CreatedUnix.AsTime())
+}
+
+func serveMetadata(ctx *context.Context, params parameters, xmlMetadataWithHeader []byte, lastModified time.Time) {
 	// http.TimeFormat required a UTC time, refer to https://pkg.go.dev/net/http#TimeFormat
-	lastModifed := latest.Version.CreatedUnix.AsTime().UTC().Format(http.TimeFormat)
-	ctx.Resp.Header().Set("Last-Modified", lastModifed)
+	ctx.Resp.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
 
 	ext := strings.ToLower(filepath.Ext(params.Filename))
 	if isChecksumExtension(ext) {
@@ -149,16 +173,6 @@ func serveMavenMetadata(ctx *context.Context, params parameters) {
 func servePackageFile(ctx *context.Context, params parameters, serveContent bool) {
 	packageName := params.GroupID + "-" + params.ArtifactID
 
-	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, packageName, params.Version)
-	if err != nil {
-		if err == packages_model.ErrPackageNotExist {
-			apiError(ctx, http.StatusNotFound, err)
-		} else {
-			apiError(ctx, http.StatusInternalServerError, err)
-		}
-		return
-	}
-
 	filename := params.Filename
 
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -166,10 +180,15 @@ func servePackageFile(ctx *context.Context, params parameters, serveContent bool
 		filename = filename[:len(filename)-len(ext)]
 	}
 
-	pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, filename, packages_model.EmptyFileKey)
+	pf, err := getPackageFile(ctx, packageName, params.Version, filename)
+	if errors.Is(err, util.ErrNotExist) {
+		pf, err = fetchPackageFileFromUpstream(ctx, params, packageName, filename)
+	}
 	if err != nil {
-		if err == packages_model.ErrPackageFileNotExist {
+		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
+		} else if errors.Is(err, packages_proxy_service.ErrUpstreamResponse) {
+			apiError(ctx, http.StatusBadGateway, err)
 		} else {
 			apiError(ctx, http.StatusInternalServerError, err)
 		}
@@ -226,6 +245,10 @@ func servePackageFile(ctx *context.Context, params parameters, serveContent bool
 	helper.ServePackageFile(ctx, s, u, pf, opts)
 }
 
+func getPackageFile(ctx *context.Context, packageName, version, filename string) (*packages_model.PackageFile, error) {
+	return packages_virtual_service.GetFileForVersionByName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypeMaven, packageName, version, filename, packages_model.EmptyFileKey)
+}
+
 func mavenPkgNameKey(packageName string) string {
 	return "pkg_maven_" + packageName
 }
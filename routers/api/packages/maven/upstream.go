@@ -0,0 +1,113 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maven
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/globallock"
+	"code.gitea.io/gitea/modules/json"
+	maven_module "code.gitea.io/gitea/modules/packages/maven"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+)
+
+// upstreamPath returns the path of the requested file in the upstream repository without a checksum extension
+func upstreamPath(ctx *context.Context) string {
+	p := ctx.PathParam("*")
+	if ext := filepath.Ext(p); isChecksumExtension(strings.ToLower(ext)) {
+		p = p[:len(p)-len(ext)]
+	}
+	return p
+}
+
+// fetchPackageFileFromUpstream downloads a file which is not available locally from the upstream of the owner and stores it
+func fetchPackageFileFromUpstream(ctx *context.Context, params parameters, packageName, filename string) (*packages_model.PackageFile, error) {
+	// snapshot metadata changes with every deployment and can't be cached as package file
+	if params.IsMeta {
+		return nil, packages_model.ErrPackageFileNotExist
+	}
+
+	pu, err := packages_proxy_service.GetUpstreamForPackage(ctx, ctx.Package.Owner.ID, packages_model.TypeMaven, packageName)
+	if err != nil {
+		return nil, err
+	}
+	if pu == nil {
+		return nil, packages_model.ErrPackageFileNotExist
+	}
+
+	releaser, err := globallock.Lock(ctx, mavenPkgNameKey(packageName))
+	if err != nil {
+		return nil, err
+	}
+	defer releaser()
+
+	// the file may have been fetched while waiting for the lock
+	if pf, err := getPackageFile(ctx, packageName, params.Version, filename); !errors.Is(err, util.ErrNotExist) {
+		return pf, err
+	}
+
+	buf, err := packages_proxy_service.FetchFile(ctx, pu, upstreamPath(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	pvci := &packages_service.PackageCreationInfo{
+		PackageInfo: packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeMaven,
+			Name:        packageName,
+			Version:     params.Version,
+		},
+		SemverCompatible: false,
+	}
+	pfci := &packages_service.PackageFileCreationInfo{
+		PackageFileInfo: packages_service.PackageFileInfo{
+			Filename: filename,
+		},
+		Data: buf,
+	}
+
+	if strings.ToLower(filepath.Ext(filename)) == extensionPom {
+		pfci.IsLead = true
+
+		pvci.Metadata, err = maven_module.ParsePackageMetaData(buf)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", packages_proxy_service.ErrUpstreamResponse, err)
+		}
+
+		if _, err := buf.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	pv, pf, err := packages_proxy_service.StoreFile(ctx, pu, ctx.Doer, pvci, pfci)
+	if err != nil {
+		return nil, err
+	}
+
+	// the version may have been created by another file before the pom was fetched
+	if pvci.Metadata != nil {
+		raw, err := json.Marshal(pvci.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		if pv.MetadataJSON != string(raw) {
+			pv.MetadataJSON = string(raw)
+			if err := packages_model.UpdateVersion(ctx, pv); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return pf, nil
+}
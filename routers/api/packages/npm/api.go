@@ -9,27 +9,32 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strings"
 
 	packages_model "code.gitea.io/gitea/models/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
 	"code.gitea.io/gitea/modules/setting"
+	packages_service "code.gitea.io/gitea/services/packages"
 )
 
 func createPackageMetadataResponse(registryURL string, pds []*packages_model.PackageDescriptor) *npm_module.PackageMetadata {
+	// the descriptors are ordered by registry priority, so the first tag wins if several owners of a virtual registry use it
+	distTags := make(map[string]string)
+	for _, pd := range pds {
+		for _, pvp := range pd.VersionProperties {
+			if _, has := distTags[pvp.Value]; pvp.Name == npm_module.TagProperty && !has {
+				distTags[pvp.Value] = pd.Version.Version
+			}
+		}
+	}
+
 	sort.Slice(pds, func(i, j int) bool {
 		return pds[i].SemVer.LessThan(pds[j].SemVer)
 	})
 
 	versions := make(map[string]*npm_module.PackageMetadataVersion)
-	distTags := make(map[string]string)
 	for _, pd := range pds {
 		versions[pd.SemVer.String()] = createPackageMetadataVersion(registryURL, pd)
-
-		for _, pvp := range pd.VersionProperties {
-			if pvp.Name == npm_module.TagProperty {
-				distTags[pvp.Value] = pd.Version.Version
-			}
-		}
 	}
 
 	latest := pds[len(pds)-1]
@@ -55,6 +60,21 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 
 	metadata := pd.Metadata.(*npm_module.Metadata)
 
+	var attestations *npm_module.PackageDistributionAttestations
+	if fas := packages_service.GetFileAttestations(pd.Files); len(fas) > 0 {
+		attestations = &npm_module.PackageDistributionAttestations{
+			URL: fmt.Sprintf("%s/-/npm/v1/attestations/%s@%s", registryURL, pd.Package.Name, pd.Version.Version),
+		}
+		for _, fa := range fas {
+			if strings.HasPrefix(fa.Attestation.Statement.PredicateType, "https://slsa.dev/provenance/") {
+				attestations.Provenance = &npm_module.PackageAttestationProvenance{
+					PredicateType: fa.Attestation.Statement.PredicateType,
+				}
+				break
+			}
+		}
+	}
+
 	return &npm_module.PackageMetadataVersion{
 		ID:                   fmt.Sprintf("%s@%s", pd.Package.Name, pd.Version.Version),
 		Name:                 pd.Package.Name,
@@ -71,9 +91,10 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 		Readme:               metadata.Readme,
 		Bin:                  metadata.Bin,
 		Dist: npm_module.PackageDistribution{
-			Shasum:    pd.Files[0].Blob.HashSHA1,
-			Integrity: "sha512-" + base64.StdEncoding.EncodeToString(hashBytes),
-			Tarball:   fmt.Sprintf("%s/%s/-/%s/%s", registryURL, url.QueryEscape(pd.Package.Name), url.PathEscape(pd.Version.Version), url.PathEscape(pd.Files[0].File.LowerName)),
+			Shasum:       pd.Files[0].Blob.HashSHA1,
+			Integrity:    "sha512-" + base64.StdEncoding.EncodeToString(hashBytes),
+			Tarball:      fmt.Sprintf("%s/%s/-/%s/%s", registryURL, url.QueryEscape(pd.Package.Name), url.PathEscape(pd.Version.Version), url.PathEscape(pd.Files[0].File.LowerName)),
+			Attestations: attestations,
 		},
 	}
 }
@@ -17,6 +17,8 @@ import (
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
@@ -25,6 +27,8 @@ import (
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+	packages_virtual_service "code.gitea.io/gitea/services/packages/virtual"
 
 	"github.com/hashicorp/go-version"
 )
@@ -54,8 +58,29 @@ func packageNameFromParams(ctx *context.Context) string {
 // PackageMetadata returns the metadata for a single package
 func PackageMetadata(ctx *context.Context) {
 	packageName := packageNameFromParams(ctx)
+	registryURL := setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/npm"
 
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
+	pu, err := packages_proxy_service.GetUpstreamForPackage(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pu != nil {
+		content, err := getUpstreamPackageDocument(ctx, pu, packageName)
+		if err == nil {
+			var resp map[string]any
+			resp, err = createUpstreamPackageMetadataResponse(registryURL, packageName, content)
+			if err == nil {
+				ctx.JSON(http.StatusOK, resp)
+				return
+			}
+		}
+		if !errors.Is(err, util.ErrNotExist) {
+			log.Warn("Unable to fetch npm metadata of %s from upstream: %v", packageName, err)
+		}
+	}
+
+	pvs, err := packages_virtual_service.GetVersionsByPackageName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypeNpm, packageName)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -72,7 +97,7 @@ func PackageMetadata(ctx *context.Context) {
 	}
 
 	resp := createPackageMetadataResponse(
-		setting.AppURL+"api/packages/"+ctx.Package.Owner.Name+"/npm",
+		registryURL,
 		pds,
 	)
 
@@ -85,23 +110,25 @@ func DownloadPackageFile(ctx *context.Context) {
 	packageVersion := ctx.PathParam("version")
 	filename := ctx.PathParam("filename")
 
-	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
-		ctx,
-		&packages_service.PackageInfo{
-			Owner:       ctx.Package.Owner,
-			PackageType: packages_model.TypeNpm,
-			Name:        packageName,
-			Version:     packageVersion,
-		},
-		&packages_service.PackageFileInfo{
-			Filename: filename,
-		},
-	)
+	pf, err := packages_virtual_service.GetFileForVersionByName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypeNpm, packageName, packageVersion, filename, packages_model.EmptyFileKey)
+	if err == packages_model.ErrPackageFileNotExist {
+		pf, err = fetchPackageFileFromUpstream(ctx, packageName, packageVersion, filename)
+	}
 	if err != nil {
-		if err == packages_model.ErrPackageNotExist || err == packages_model.ErrPackageFileNotExist {
+		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, packages_proxy_service.ErrUpstreamResponse) {
+			apiError(ctx, http.StatusBadGateway, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	s, u, _, err := packages_service.GetPackageFileStream(ctx, pf)
+	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -190,6 +217,11 @@ func UploadPackage(ctx *context.Context) {
 	}
 	defer buf.Close()
 
+	var attestations []string
+	if npmPackage.Provenance != "" {
+		attestations = []string{npmPackage.Provenance}
+	}
+
 	pv, _, err := packages_service.CreatePackageAndAddFile(
 		ctx,
 		&packages_service.PackageCreationInfo{
@@ -207,9 +239,10 @@ func UploadPackage(ctx *context.Context) {
 			PackageFileInfo: packages_service.PackageFileInfo{
 				Filename: npmPackage.Filename,
 			},
-			Creator: ctx.Doer,
-			Data:    buf,
-			IsLead:  true,
+			Creator:      ctx.Doer,
+			Data:         buf,
+			IsLead:       true,
+			Attestations: attestations,
 		},
 	)
 	if err != nil {
@@ -219,7 +252,11 @@ func UploadPackage(ctx *context.Context) {
 		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
 			apiError(ctx, http.StatusForbidden, err)
 		default:
-			apiError(ctx, http.StatusInternalServerError, err)
+			if errors.Is(err, util.ErrInvalidArgument) {
+				apiError(ctx, http.StatusBadRequest, err)
+			} else {
+				apiError(ctx, http.StatusInternalServerError, err)
+			}
 		}
 		return
 	}
@@ -245,6 +282,69 @@ func UploadPackage(ctx *context.Context) {
 	ctx.Status(http.StatusCreated)
 }
 
+// PackageAttestations returns the provenance of a package version which is checked by "npm audit signatures"
+func PackageAttestations(ctx *context.Context) {
+	// the id has the form name@version
+	id := ctx.PathParam("id")
+	pos := strings.LastIndex(id, "@")
+	if pos <= 0 {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+	packageName := id[:pos]
+	if scope := ctx.PathParam("scope"); scope != "" {
+		packageName = fmt.Sprintf("@%s/%s", scope, packageName)
+	}
+	packageVersion := strings.ToLower(id[pos+1:])
+
+	pvs, err := packages_virtual_service.GetVersionsByPackageName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypeNpm, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	var pv *packages_model.PackageVersion
+	for _, v := range pvs {
+		if v.LowerVersion == packageVersion {
+			pv = v
+			break
+		}
+	}
+	if pv == nil {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	fas := packages_service.GetFileAttestations(pd.Files)
+	if len(fas) == 0 {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	resp := &npm_module.PackageAttestations{
+		Attestations: make([]*npm_module.PackageAttestation, 0, len(fas)),
+	}
+	for _, fa := range fas {
+		var bundle any
+		if err := json.Unmarshal([]byte(fa.Property.Value), &bundle); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+		resp.Attestations = append(resp.Attestations, &npm_module.PackageAttestation{
+			PredicateType: fa.Attestation.Statement.PredicateType,
+			Bundle:        bundle,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, resp)
+}
+
 // DeletePreview does nothing
 // The client tells the server what package version it knows about after deleting a version.
 func DeletePreview(ctx *context.Context) {
@@ -0,0 +1,163 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+
+	"github.com/hashicorp/go-version"
+)
+
+const upstreamAccept = "application/json"
+
+// upstreamPackageDocument contains the parts of the upstream package document needed to fetch a version
+type upstreamPackageDocument struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Versions map[string]any    `json:"versions"`
+}
+
+func getUpstreamPackageDocument(ctx *context.Context, pu *packages_model.PackageUpstream, packageName string) ([]byte, error) {
+	puc, err := packages_proxy_service.GetMetadata(ctx, pu, url.PathEscape(packageName), upstreamAccept)
+	if err != nil {
+		return nil, err
+	}
+	return puc.Content, nil
+}
+
+// createUpstreamPackageMetadataResponse rewrites the tarball urls of the upstream package document to point to this registry
+func createUpstreamPackageMetadataResponse(registryURL, packageName string, content []byte) (map[string]any, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+
+	versions, _ := doc["versions"].(map[string]any)
+	for v, raw := range versions {
+		meta, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		dist, ok := meta["dist"].(map[string]any)
+		if !ok {
+			continue
+		}
+		tarball, _ := dist["tarball"].(string)
+		dist["tarball"] = fmt.Sprintf("%s/%s/-/%s/%s", registryURL, url.QueryEscape(packageName), url.PathEscape(v), url.PathEscape(tarballFilename(tarball)))
+	}
+	return doc, nil
+}
+
+func tarballFilename(tarball string) string {
+	u, err := url.Parse(tarball)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(path.Base(u.Path))
+}
+
+// fetchPackageFileFromUpstream downloads a tarball which is not available locally from the upstream of the owner and stores it
+func fetchPackageFileFromUpstream(ctx *context.Context, packageName, packageVersion, filename string) (*packages_model.PackageFile, error) {
+	pu, err := packages_proxy_service.GetUpstreamForPackage(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, packageName)
+	if err != nil {
+		return nil, err
+	}
+	if pu == nil {
+		return nil, packages_model.ErrPackageFileNotExist
+	}
+
+	content, err := getUpstreamPackageDocument(ctx, pu, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc upstreamPackageDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	versionDoc, ok := doc.Versions[packageVersion]
+	if !ok {
+		return nil, packages_model.ErrPackageNotExist
+	}
+	// decode only the requested version because other versions may use unsupported field types
+	raw, err := json.Marshal(versionDoc)
+	if err != nil {
+		return nil, err
+	}
+	var meta npm_module.PackageMetadataVersion
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, err
+	}
+	if tarballFilename(meta.Dist.Tarball) != strings.ToLower(filename) {
+		return nil, packages_model.ErrPackageFileNotExist
+	}
+
+	v, err := version.NewSemver(meta.Version)
+	if err != nil {
+		return nil, npm_module.ErrInvalidPackageVersion
+	}
+
+	buf, err := packages_proxy_service.FetchFile(ctx, pu, meta.Dist.Tarball)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	_, hashSHA1, _, hashSHA512 := buf.Sums()
+	if meta.Dist.Integrity != "" {
+		err = npm_module.ValidateIntegrity(meta.Dist.Integrity, hashSHA1, hashSHA512)
+	} else if meta.Dist.Shasum != hex.EncodeToString(hashSHA1) {
+		err = npm_module.ErrInvalidIntegrity
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", packages_proxy_service.ErrUpstreamResponse, err)
+	}
+
+	pv, pf, err := packages_proxy_service.StoreFile(
+		ctx,
+		pu,
+		ctx.Doer,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeNpm,
+				Name:        packageName,
+				Version:     v.String(),
+			},
+			SemverCompatible: true,
+			Metadata:         npm_module.CreateMetadata(&meta),
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: strings.ToLower(filename),
+			},
+			Data:   buf,
+			IsLead: true,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for tag, tagVersion := range doc.DistTags {
+		if tagVersion != packageVersion {
+			continue
+		}
+		if err := setPackageTag(ctx, tag, pv, false); err != nil && err != errInvalidTagName {
+			return nil, err
+		}
+	}
+
+	return pf, nil
+}
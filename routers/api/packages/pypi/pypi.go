@@ -5,6 +5,7 @@ package pypi
 
 import (
 	"encoding/hex"
+	"errors"
 	"io"
 	"net/http"
 	"regexp"
@@ -12,13 +13,19 @@ import (
 	"strings"
 
 	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
+	attestation_module "code.gitea.io/gitea/modules/packages/attestation"
 	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	"code.gitea.io/gitea/services/context"
 	packages_service "code.gitea.io/gitea/services/packages"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+	packages_virtual_service "code.gitea.io/gitea/services/packages/virtual"
 )
 
 // https://peps.python.org/pep-0426/#name
@@ -46,8 +53,29 @@ func apiError(ctx *context.Context, status int, obj any) {
 // PackageMetadata returns the metadata for a single package
 func PackageMetadata(ctx *context.Context) {
 	packageName := normalizer.Replace(ctx.PathParam("id"))
+	registryURL := setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/pypi"
 
-	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypePyPI, packageName)
+	pu, err := packages_proxy_service.GetUpstreamForPackage(ctx, ctx.Package.Owner.ID, packages_model.TypePyPI, packageName)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if pu != nil {
+		project, err := getUpstreamProject(ctx, pu, packageName)
+		if err == nil {
+			ctx.Data["RegistryURL"] = registryURL
+			ctx.Data["PackageName"] = project.Name
+			ctx.Data["PackageLowerName"] = strings.ToLower(packageName)
+			ctx.Data["Files"] = project.Files
+			ctx.HTML(http.StatusOK, "api/packages/pypi/simple_upstream")
+			return
+		}
+		if !errors.Is(err, util.ErrNotExist) {
+			log.Warn("Unable to fetch PyPI metadata of %s from upstream: %v", packageName, err)
+		}
+	}
+
+	pvs, err := packages_virtual_service.GetVersionsByPackageName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypePyPI, packageName)
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -68,7 +96,7 @@ func PackageMetadata(ctx *context.Context) {
 		return strings.Compare(pds[i].Version.Version, pds[j].Version.Version) < 0
 	})
 
-	ctx.Data["RegistryURL"] = setting.AppURL + "api/packages/" + ctx.Package.Owner.Name + "/pypi"
+	ctx.Data["RegistryURL"] = registryURL
 	ctx.Data["PackageDescriptor"] = pds[0]
 	ctx.Data["PackageDescriptors"] = pds
 	ctx.HTML(http.StatusOK, "api/packages/pypi/simple")
@@ -80,23 +108,25 @@ func DownloadPackageFile(ctx *context.Context) {
 	packageVersion := ctx.PathParam("version")
 	filename := ctx.PathParam("filename")
 
-	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
-		ctx,
-		&packages_service.PackageInfo{
-			Owner:       ctx.Package.Owner,
-			PackageType: packages_model.TypePyPI,
-			Name:        packageName,
-			Version:     packageVersion,
-		},
-		&packages_service.PackageFileInfo{
-			Filename: filename,
-		},
-	)
+	pf, err := packages_virtual_service.GetFileForVersionByName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypePyPI, packageName, packageVersion, filename, packages_model.EmptyFileKey)
+	if err == packages_model.ErrPackageFileNotExist {
+		pf, err = fetchPackageFileFromUpstream(ctx, packageName, packageVersion, filename)
+	}
 	if err != nil {
-		if err == packages_model.ErrPackageNotExist || err == packages_model.ErrPackageFileNotExist {
+		if errors.Is(err, util.ErrNotExist) {
 			apiError(ctx, http.StatusNotFound, err)
 			return
 		}
+		if errors.Is(err, packages_proxy_service.ErrUpstreamResponse) {
+			apiError(ctx, http.StatusBadGateway, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	s, u, _, err := packages_service.GetPackageFileStream(ctx, pf)
+	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
 	}
@@ -104,6 +134,57 @@ func DownloadPackageFile(ctx *context.Context) {
 	helper.ServePackageFile(ctx, s, u, pf)
 }
 
+// PackageFileProvenance serves the PEP 740 provenance of a package file
+func PackageFileProvenance(ctx *context.Context) {
+	packageName := normalizer.Replace(ctx.PathParam("id"))
+	packageVersion := ctx.PathParam("version")
+	filename := ctx.PathParam("filename")
+
+	pf, err := packages_virtual_service.GetFileForVersionByName(ctx, ctx.Package.Owner, ctx.Doer, packages_model.TypePyPI, packageName, packageVersion, filename, packages_model.EmptyFileKey)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+			return
+		}
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	pfd, err := packages_model.GetPackageFileDescriptor(ctx, pf)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	provenance := &pypi_module.Provenance{
+		Version:            1,
+		AttestationBundles: make([]*pypi_module.AttestationBundle, 0, 1),
+	}
+	for _, fa := range packages_service.GetFileAttestations([]*packages_model.PackageFileDescriptor{pfd}) {
+		// other formats can't be part of the provenance
+		if fa.Attestation.Format != attestation_module.FormatPEP740 {
+			continue
+		}
+
+		var obj any
+		if err := json.Unmarshal([]byte(fa.Property.Value), &obj); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+
+		provenance.AttestationBundles = append(provenance.AttestationBundles, &pypi_module.AttestationBundle{
+			Publisher:    pypi_module.NewPublisher(fa.SignerIdentity(), fa.SignerIssuer()),
+			Attestations: []any{obj},
+		})
+	}
+	if len(provenance.AttestationBundles) == 0 {
+		apiError(ctx, http.StatusNotFound, nil)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, provenance)
+}
+
 // UploadPackageFile adds a file to the package. If the package does not exist, it gets created.
 func UploadPackageFile(ctx *context.Context) {
 	file, fileHeader, err := ctx.Req.FormFile("content")
@@ -139,6 +220,24 @@ func UploadPackageFile(ctx *context.Context) {
 		return
 	}
 
+	// PEP 740 attestations are sent as JSON array
+	var attestations []string
+	if value := ctx.Req.FormValue("attestations"); value != "" {
+		var objects []any
+		if err := json.Unmarshal([]byte(value), &objects); err != nil {
+			apiError(ctx, http.StatusBadRequest, "invalid attestations")
+			return
+		}
+		for _, obj := range objects {
+			data, err := json.Marshal(obj)
+			if err != nil {
+				apiError(ctx, http.StatusInternalServerError, err)
+				return
+			}
+			attestations = append(attestations, string(data))
+		}
+	}
+
 	projectURL := ctx.Req.FormValue("home_page")
 	if !validation.IsValidURL(projectURL) {
 		projectURL = ""
@@ -169,9 +268,10 @@ func UploadPackageFile(ctx *context.Context) {
 			PackageFileInfo: packages_service.PackageFileInfo{
 				Filename: fileHeader.Filename,
 			},
-			Creator: ctx.Doer,
-			Data:    buf,
-			IsLead:  true,
+			Creator:      ctx.Doer,
+			Data:         buf,
+			IsLead:       true,
+			Attestations: attestations,
 		},
 	)
 	if err != nil {
@@ -181,7 +281,11 @@ func UploadPackageFile(ctx *context.Context) {
 		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
 			apiError(ctx, http.StatusForbidden, err)
 		default:
-			apiError(ctx, http.StatusInternalServerError, err)
+			if errors.Is(err, util.ErrInvalidArgument) {
+				apiError(ctx, http.StatusBadRequest, err)
+			} else {
+				apiError(ctx, http.StatusInternalServerError, err)
+			}
 		}
 		return
 	}
@@ -0,0 +1,144 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pypi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/json"
+	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+)
+
+// https://peps.python.org/pep-0691/
+const upstreamAccept = "application/vnd.pypi.simple.v1+json"
+
+type upstreamProject struct {
+	Name  string          `json:"name"`
+	Files []*upstreamFile `json:"files"`
+}
+
+type upstreamFile struct {
+	Filename       string            `json:"filename"`
+	URL            string            `json:"url"`
+	Hashes         map[string]string `json:"hashes"`
+	RequiresPython string            `json:"requires-python"`
+	Version        string            `json:"-"`
+}
+
+func getUpstreamProject(ctx *context.Context, pu *packages_model.PackageUpstream, packageName string) (*upstreamProject, error) {
+	puc, err := packages_proxy_service.GetMetadata(ctx, pu, strings.ToLower(packageName)+"/", upstreamAccept)
+	if err != nil {
+		return nil, err
+	}
+
+	var project upstreamProject
+	if err := json.Unmarshal(puc.Content, &project); err != nil {
+		return nil, err
+	}
+
+	files := make([]*upstreamFile, 0, len(project.Files))
+	for _, f := range project.Files {
+		f.Version = versionFromFilename(f.Filename)
+		if !isValidNameAndVersion(packageName, f.Version) {
+			continue
+		}
+		files = append(files, f)
+	}
+	project.Files = files
+
+	return &project, nil
+}
+
+// versionFromFilename extracts the version from the name of a wheel, egg or source distribution
+func versionFromFilename(filename string) string {
+	for _, ext := range []string{".whl", ".egg"} {
+		if strings.HasSuffix(filename, ext) {
+			parts := strings.Split(filename, "-")
+			if len(parts) < 3 {
+				return ""
+			}
+			return parts[1]
+		}
+	}
+
+	for _, ext := range []string{".tar.gz", ".tar.bz2", ".tgz", ".zip"} {
+		if strings.HasSuffix(filename, ext) {
+			base := strings.TrimSuffix(filename, ext)
+			if idx := strings.LastIndex(base, "-"); idx != -1 {
+				return base[idx+1:]
+			}
+		}
+	}
+	return ""
+}
+
+// fetchPackageFileFromUpstream downloads a file which is not available locally from the upstream of the owner and stores it
+func fetchPackageFileFromUpstream(ctx *context.Context, packageName, packageVersion, filename string) (*packages_model.PackageFile, error) {
+	pu, err := packages_proxy_service.GetUpstreamForPackage(ctx, ctx.Package.Owner.ID, packages_model.TypePyPI, packageName)
+	if err != nil {
+		return nil, err
+	}
+	if pu == nil {
+		return nil, packages_model.ErrPackageFileNotExist
+	}
+
+	project, err := getUpstreamProject(ctx, pu, packageName)
+	if err != nil {
+		return nil, err
+	}
+
+	var file *upstreamFile
+	for _, f := range project.Files {
+		if f.Filename == filename && f.Version == packageVersion {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil, packages_model.ErrPackageFileNotExist
+	}
+
+	buf, err := packages_proxy_service.FetchFile(ctx, pu, file.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer buf.Close()
+
+	_, _, hashSHA256, _ := buf.Sums()
+	if expected, ok := file.Hashes["sha256"]; ok && !strings.EqualFold(expected, hex.EncodeToString(hashSHA256)) {
+		return nil, fmt.Errorf("%w: hash mismatch", packages_proxy_service.ErrUpstreamResponse)
+	}
+
+	_, pf, err := packages_proxy_service.StoreFile(
+		ctx,
+		pu,
+		ctx.Doer,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypePyPI,
+				Name:        packageName,
+				Version:     packageVersion,
+			},
+			SemverCompatible: false,
+			Metadata: &pypi_module.Metadata{
+				RequiresPython: file.RequiresPython,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: filename,
+			},
+			Data:   buf,
+			IsLead: true,
+		},
+	)
+	return pf, err
+}
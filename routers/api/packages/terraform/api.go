@@ -0,0 +1,96 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	packages_model "code.gitea.io/gitea/models/packages"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+)
+
+// https://developer.hashicorp.com/terraform/internals/module-registry-protocol#list-available-versions-for-a-specific-module
+type ModuleVersionsResponse struct {
+	Modules []*ModuleVersions `json:"modules"`
+}
+
+type ModuleVersions struct {
+	Versions []*ModuleVersion `json:"versions"`
+}
+
+type ModuleVersion struct {
+	Version string `json:"version"`
+}
+
+func createModuleVersionsResponse(pvs []*packages_model.PackageVersion) *ModuleVersionsResponse {
+	versions := make([]*ModuleVersion, 0, len(pvs))
+	for _, pv := range pvs {
+		versions = append(versions, &ModuleVersion{Version: pv.Version})
+	}
+
+	return &ModuleVersionsResponse{
+		Modules: []*ModuleVersions{
+			{Versions: versions},
+		},
+	}
+}
+
+// https://developer.hashicorp.com/terraform/internals/provider-registry-protocol#list-available-versions
+type ProviderVersionsResponse struct {
+	Versions []*ProviderVersion `json:"versions"`
+}
+
+type ProviderVersion struct {
+	Version   string              `json:"version"`
+	Protocols []string            `json:"protocols"`
+	Platforms []*ProviderPlatform `json:"platforms"`
+}
+
+type ProviderPlatform struct {
+	OS   string `json:"os"`
+	Arch string `json:"arch"`
+}
+
+func createProviderVersionsResponse(pds []*packages_model.PackageDescriptor) *ProviderVersionsResponse {
+	versions := make([]*ProviderVersion, 0, len(pds))
+	for _, pd := range pds {
+		platforms := make([]*ProviderPlatform, 0, len(pd.Files))
+		for _, pfd := range pd.Files {
+			platforms = append(platforms, &ProviderPlatform{
+				OS:   pfd.Properties.GetByName(terraform_module.PropertyOS),
+				Arch: pfd.Properties.GetByName(terraform_module.PropertyArchitecture),
+			})
+		}
+
+		versions = append(versions, &ProviderVersion{
+			Version:   pd.Version.Version,
+			Protocols: pd.Metadata.(*terraform_module.Metadata).Protocols,
+			Platforms: platforms,
+		})
+	}
+
+	return &ProviderVersionsResponse{
+		Versions: versions,
+	}
+}
+
+// https://developer.hashicorp.com/terraform/internals/provider-registry-protocol#find-a-provider-package
+type ProviderPackageResponse struct {
+	Protocols           []string     `json:"protocols"`
+	OS                  string       `json:"os"`
+	Arch                string       `json:"arch"`
+	Filename            string       `json:"filename"`
+	DownloadURL         string       `json:"download_url"`
+	ShasumsURL          string       `json:"shasums_url"`
+	ShasumsSignatureURL string       `json:"shasums_signature_url"`
+	Shasum              string       `json:"shasum"`
+	SigningKeys         *SigningKeys `json:"signing_keys"`
+}
+
+type SigningKeys struct {
+	GPGPublicKeys []*GPGPublicKey `json:"gpg_public_keys"`
+}
+
+type GPGPublicKey struct {
+	KeyID      string `json:"key_id"`
+	ASCIIArmor string `json:"ascii_armor"`
+}
@@ -0,0 +1,493 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/packages/helper"
+	"code.gitea.io/gitea/services/context"
+	packages_service "code.gitea.io/gitea/services/packages"
+	terraform_service "code.gitea.io/gitea/services/packages/terraform"
+)
+
+func apiError(ctx *context.Context, status int, obj any) {
+	helper.LogAndProcessError(ctx, status, obj, func(message string) {
+		ctx.JSON(status, map[string][]string{
+			"errors": {message},
+		})
+	})
+}
+
+func ownerURL(ctx *context.Context) string {
+	return setting.AppURL + "api/packages/" + url.PathEscape(ctx.Package.Owner.Name) + "/terraform"
+}
+
+// ListModuleVersions lists all available versions of a module
+func ListModuleVersions(ctx *context.Context) {
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, terraform_module.ModulePackageName(ctx.PathParam("name"), ctx.PathParam("system")))
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pvs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageNotExist)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createModuleVersionsResponse(pvs))
+}
+
+// DownloadModule points Terraform to the archive of the module version
+func DownloadModule(ctx *context.Context) {
+	name, system, version := ctx.PathParam("name"), ctx.PathParam("system"), ctx.PathParam("version")
+
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, terraform_module.ModulePackageName(name, system), version)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Resp.Header().Set("X-Terraform-Get", fmt.Sprintf("%s/modules/%s/%s/%s/%s", ownerURL(ctx), url.PathEscape(name), url.PathEscape(system), url.PathEscape(pv.Version), url.PathEscape(terraform_module.ModuleFilename(name, system, pv.Version))))
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadModule creates a new module version from a .tar.gz archive
+func UploadModule(ctx *context.Context) {
+	name, system, version := ctx.PathParam("name"), ctx.PathParam("system"), ctx.PathParam("version")
+
+	if !terraform_module.IsValidModuleName(name) || !terraform_module.IsValidModuleName(system) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidName)
+		return
+	}
+	if !terraform_module.IsValidVersion(version) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidVersion)
+		return
+	}
+
+	upload, needToClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if needToClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	metadata, err := terraform_module.ParseModuleArchive(buf)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			apiError(ctx, http.StatusBadRequest, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, _, err = packages_service.CreatePackageAndAddFile(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeTerraform,
+				Name:        terraform_module.ModulePackageName(name, system),
+				Version:     version,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata:         metadata,
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: terraform_module.ModuleFilename(name, system, version),
+			},
+			Creator: ctx.Doer,
+			Data:    buf,
+			IsLead:  true,
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageVersion:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// DownloadModuleFile serves the archive of the module version
+func DownloadModuleFile(ctx *context.Context) {
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+		ctx,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        terraform_module.ModulePackageName(ctx.PathParam("name"), ctx.PathParam("system")),
+			Version:     ctx.PathParam("version"),
+		},
+		&packages_service.PackageFileInfo{
+			Filename: ctx.PathParam("filename"),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// DeleteModule deletes the module version
+func DeleteModule(ctx *context.Context) {
+	err := packages_service.RemovePackageVersionByNameAndVersion(
+		ctx,
+		ctx.Doer,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        terraform_module.ModulePackageName(ctx.PathParam("name"), ctx.PathParam("system")),
+			Version:     ctx.PathParam("version"),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListProviderVersions lists all available versions of a provider with their platforms
+func ListProviderVersions(ctx *context.Context) {
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, ctx.PathParam("provider"))
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pvs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageNotExist)
+		return
+	}
+
+	pds, err := packages_model.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, createProviderVersionsResponse(pds))
+}
+
+// GetProviderPackage serves the download information of the provider archive for a platform
+func GetProviderPackage(ctx *context.Context) {
+	provider, os, arch := ctx.PathParam("provider"), ctx.PathParam("os"), ctx.PathParam("arch")
+
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, provider, ctx.PathParam("version"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := terraform_module.ProviderFilename(provider, pv.Version, os, arch)
+
+	var pfd *packages_model.PackageFileDescriptor
+	for _, f := range pd.Files {
+		if f.File.Name == filename {
+			pfd = f
+			break
+		}
+	}
+	if pfd == nil {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+
+	keyID, publicKey, err := terraform_service.GetSigningKey(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	versionURL := fmt.Sprintf("%s/providers/%s/%s", ownerURL(ctx), url.PathEscape(provider), url.PathEscape(pv.Version))
+
+	ctx.JSON(http.StatusOK, &ProviderPackageResponse{
+		Protocols:           pd.Metadata.(*terraform_module.Metadata).Protocols,
+		OS:                  os,
+		Arch:                arch,
+		Filename:            filename,
+		DownloadURL:         fmt.Sprintf("%s/%s/%s/%s", versionURL, url.PathEscape(os), url.PathEscape(arch), url.PathEscape(filename)),
+		ShasumsURL:          versionURL + "/" + terraform_module.ShasumsFilename,
+		ShasumsSignatureURL: versionURL + "/" + terraform_module.ShasumsSignatureFilename,
+		Shasum:              pfd.Blob.HashSHA256,
+		SigningKeys: &SigningKeys{
+			GPGPublicKeys: []*GPGPublicKey{
+				{
+					KeyID:      keyID,
+					ASCIIArmor: publicKey,
+				},
+			},
+		},
+	})
+}
+
+// UploadProvider adds the provider archive for a platform to the provider version
+func UploadProvider(ctx *context.Context) {
+	provider, version, os, arch := ctx.PathParam("provider"), ctx.PathParam("version"), ctx.PathParam("os"), ctx.PathParam("arch")
+
+	if !terraform_module.IsValidProviderType(provider) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidName)
+		return
+	}
+	if !terraform_module.IsValidVersion(version) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidVersion)
+		return
+	}
+	if !terraform_module.IsValidPlatform(os, arch) {
+		apiError(ctx, http.StatusBadRequest, terraform_module.ErrInvalidPlatform)
+		return
+	}
+
+	protocols, err := terraform_module.ParseProtocols(ctx.FormString("protocols"))
+	if err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	upload, needToClose, err := ctx.UploadStream()
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if needToClose {
+		defer upload.Close()
+	}
+
+	buf, err := packages_module.CreateHashedBufferFromReader(upload)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	defer buf.Close()
+
+	if err := terraform_module.ParseProviderArchive(buf, buf.Size(), provider); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			apiError(ctx, http.StatusBadRequest, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if _, err := buf.Seek(0, io.SeekStart); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	_, _, err = packages_service.CreatePackageOrAddFileToExisting(
+		ctx,
+		&packages_service.PackageCreationInfo{
+			PackageInfo: packages_service.PackageInfo{
+				Owner:       ctx.Package.Owner,
+				PackageType: packages_model.TypeTerraform,
+				Name:        provider,
+				Version:     version,
+			},
+			SemverCompatible: true,
+			Creator:          ctx.Doer,
+			Metadata: &terraform_module.Metadata{
+				Kind:      terraform_module.KindProvider,
+				Protocols: protocols,
+			},
+		},
+		&packages_service.PackageFileCreationInfo{
+			PackageFileInfo: packages_service.PackageFileInfo{
+				Filename: terraform_module.ProviderFilename(provider, version, os, arch),
+			},
+			Creator: ctx.Doer,
+			Data:    buf,
+			Properties: map[string]string{
+				terraform_module.PropertyOS:           os,
+				terraform_module.PropertyArchitecture: arch,
+			},
+		},
+	)
+	if err != nil {
+		switch err {
+		case packages_model.ErrDuplicatePackageFile:
+			apiError(ctx, http.StatusConflict, err)
+		case packages_service.ErrQuotaTotalCount, packages_service.ErrQuotaTypeSize, packages_service.ErrQuotaTotalSize:
+			apiError(ctx, http.StatusForbidden, err)
+		default:
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// DownloadProviderFile serves the provider archive for a platform
+func DownloadProviderFile(ctx *context.Context) {
+	provider, version := ctx.PathParam("provider"), ctx.PathParam("version")
+
+	if ctx.PathParam("filename") != terraform_module.ProviderFilename(provider, version, ctx.PathParam("os"), ctx.PathParam("arch")) {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageFileNotExist)
+		return
+	}
+
+	s, u, pf, err := packages_service.GetFileStreamByPackageNameAndVersion(
+		ctx,
+		&packages_service.PackageInfo{
+			Owner:       ctx.Package.Owner,
+			PackageType: packages_model.TypeTerraform,
+			Name:        provider,
+			Version:     version,
+		},
+		&packages_service.PackageFileInfo{
+			Filename: ctx.PathParam("filename"),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	helper.ServePackageFile(ctx, s, u, pf)
+}
+
+// DeleteProviderFile deletes the provider archive for a platform and the version if it was the last archive
+func DeleteProviderFile(ctx *context.Context) {
+	provider, version := ctx.PathParam("provider"), ctx.PathParam("version")
+
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, provider, version)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, terraform_module.ProviderFilename(provider, pv.Version, ctx.PathParam("os"), ctx.PathParam("arch")), packages_model.EmptyFileKey)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	if err := packages_service.RemovePackageFileAndVersionIfUnreferenced(ctx, ctx.Doer, pf); err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetProviderShasums serves the SHA256SUMS file of all archives of the provider version
+func GetProviderShasums(ctx *context.Context) {
+	shasums, ok := getProviderShasums(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.ServeContent(bytes.NewReader(shasums), &context.ServeHeaderOptions{
+		ContentType: "text/plain; charset=utf-8",
+		Filename:    terraform_module.ShasumsFilename,
+	})
+}
+
+// GetProviderShasumsSignature serves the detached signature of the SHA256SUMS file
+func GetProviderShasumsSignature(ctx *context.Context) {
+	shasums, ok := getProviderShasums(ctx)
+	if !ok {
+		return
+	}
+
+	signature, err := terraform_service.SignShasums(ctx, ctx.Package.Owner.ID, shasums)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.ServeContent(bytes.NewReader(signature), &context.ServeHeaderOptions{
+		Filename: terraform_module.ShasumsSignatureFilename,
+	})
+}
+
+func getProviderShasums(ctx *context.Context) ([]byte, bool) {
+	pv, err := packages_model.GetVersionByNameAndVersion(ctx, ctx.Package.Owner.ID, packages_model.TypeTerraform, ctx.PathParam("provider"), ctx.PathParam("version"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			apiError(ctx, http.StatusNotFound, err)
+		} else {
+			apiError(ctx, http.StatusInternalServerError, err)
+		}
+		return nil, false
+	}
+
+	shasums, err := terraform_service.BuildShasums(ctx, pv)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	return shasums, true
+}
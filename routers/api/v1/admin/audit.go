@@ -0,0 +1,54 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListAuditEvents lists the audit events of the instance
+func ListAuditEvents(ctx *context.APIContext) {
+	// swagger:operation GET /admin/audit/events admin adminListAuditEvents
+	// ---
+	// summary: List the audit events of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: action
+	//   in: query
+	//   description: only show events of this action
+	//   type: string
+	// - name: actor
+	//   in: query
+	//   description: only show events performed by this user
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only show events recorded at or after this time
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: only show events recorded before this time
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuditEventList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.ListAuditEvents(ctx, 0)
+}
@@ -0,0 +1,610 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	quota_model "code.gitea.io/gitea/models/quota"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListQuotaGroups lists all quota groups
+func ListQuotaGroups(ctx *context.APIContext) {
+	// swagger:operation GET /admin/quota/groups admin adminListQuotaGroups
+	// ---
+	// summary: List the available quota groups
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QuotaGroupList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	groups, err := quota_model.ListGroups(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListGroups", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToQuotaGroupList(groups))
+}
+
+// CreateQuotaGroup creates a quota group
+func CreateQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/quota/groups admin adminCreateQuotaGroup
+	// ---
+	// summary: Create a new quota group
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateQuotaGroupOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/QuotaGroup"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateQuotaGroupOption)
+
+	group := &quota_model.Group{Name: form.Name}
+	if err := quota_model.CreateGroup(ctx, group); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateGroup", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToQuotaGroup(group))
+}
+
+// GetQuotaGroup gets a quota group
+func GetQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation GET /admin/quota/groups/{quotagroup} admin adminGetQuotaGroup
+	// ---
+	// summary: Get a quota group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to get
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QuotaGroup"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToQuotaGroup(group))
+}
+
+// DeleteQuotaGroup deletes a quota group
+func DeleteQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/quota/groups/{quotagroup} admin adminDeleteQuotaGroup
+	// ---
+	// summary: Delete a quota group
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := quota_model.DeleteGroup(ctx, group.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteGroup", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// AddRuleToQuotaGroup adds a rule to a quota group
+func AddRuleToQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/quota/groups/{quotagroup}/rules/{quotarule} admin adminAddRuleToQuotaGroup
+	// ---
+	// summary: Add a rule to a quota group
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to add the rule to
+	//   type: string
+	//   required: true
+	// - name: quotarule
+	//   in: path
+	//   description: quota rule to add
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	rule := getQuotaRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := quota_model.AddRuleToGroup(ctx, group.ID, rule.ID); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddRuleToGroup", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveRuleFromQuotaGroup removes a rule from a quota group
+func RemoveRuleFromQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/quota/groups/{quotagroup}/rules/{quotarule} admin adminRemoveRuleFromQuotaGroup
+	// ---
+	// summary: Remove a rule from a quota group
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to remove the rule from
+	//   type: string
+	//   required: true
+	// - name: quotarule
+	//   in: path
+	//   description: quota rule to remove
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	rule := getQuotaRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := quota_model.RemoveRuleFromGroup(ctx, group.ID, rule.ID); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveRuleFromGroup", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListUsersInQuotaGroup lists the users and organizations assigned to a quota group
+func ListUsersInQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation GET /admin/quota/groups/{quotagroup}/users admin adminListUsersInQuotaGroup
+	// ---
+	// summary: List the users and organizations assigned to a quota group
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to list the users of
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	userIDs, err := quota_model.GetUserIDsInGroup(ctx, group.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserIDsInGroup", err)
+		return
+	}
+	users, err := user_model.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsersByIDs", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToUsers(ctx, ctx.Doer, users))
+}
+
+// AddUserToQuotaGroup assigns a quota group to a user or organization
+func AddUserToQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/quota/groups/{quotagroup}/users/{username} admin adminAddUserToQuotaGroup
+	// ---
+	// summary: Assign a quota group to a user or organization
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to assign
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: user or organization to assign the quota group to
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := quota_model.AddUserToGroup(ctx, group.ID, ctx.ContextUser.ID); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddUserToGroup", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveUserFromQuotaGroup removes a quota group from a user or organization
+func RemoveUserFromQuotaGroup(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/quota/groups/{quotagroup}/users/{username} admin adminRemoveUserFromQuotaGroup
+	// ---
+	// summary: Remove a quota group from a user or organization
+	// parameters:
+	// - name: quotagroup
+	//   in: path
+	//   description: quota group to remove
+	//   type: string
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: user or organization to remove the quota group from
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	group := getQuotaGroupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := quota_model.RemoveUserFromGroup(ctx, group.ID, ctx.ContextUser.ID); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveUserFromGroup", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListQuotaRules lists all quota rules
+func ListQuotaRules(ctx *context.APIContext) {
+	// swagger:operation GET /admin/quota/rules admin adminListQuotaRules
+	// ---
+	// summary: List the available quota rules
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QuotaRuleInfoList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	rules, err := quota_model.ListRules(ctx)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListRules", err)
+		return
+	}
+
+	apiRules := make([]*api.QuotaRuleInfo, 0, len(rules))
+	for _, r := range rules {
+		apiRules = append(apiRules, convert.ToQuotaRuleInfo(r))
+	}
+
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// CreateQuotaRule creates a quota rule
+func CreateQuotaRule(ctx *context.APIContext) {
+	// swagger:operation POST /admin/quota/rules admin adminCreateQuotaRule
+	// ---
+	// summary: Create a new quota rule
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateQuotaRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/QuotaRuleInfo"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateQuotaRuleOption)
+
+	subjects := parseLimitSubjects(ctx, form.Subjects)
+	if ctx.Written() {
+		return
+	}
+
+	rule := &quota_model.Rule{
+		Name:     form.Name,
+		Limit:    max(form.Limit, -1),
+		Subjects: subjects,
+	}
+	if err := quota_model.CreateRule(ctx, rule); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateRule", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToQuotaRuleInfo(rule))
+}
+
+// GetQuotaRule gets a quota rule
+func GetQuotaRule(ctx *context.APIContext) {
+	// swagger:operation GET /admin/quota/rules/{quotarule} admin adminGetQuotaRule
+	// ---
+	// summary: Get a quota rule
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: quotarule
+	//   in: path
+	//   description: quota rule to get
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QuotaRuleInfo"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule := getQuotaRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToQuotaRuleInfo(rule))
+}
+
+// EditQuotaRule edits a quota rule
+func EditQuotaRule(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/quota/rules/{quotarule} admin adminEditQuotaRule
+	// ---
+	// summary: Change an existing quota rule
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: quotarule
+	//   in: path
+	//   description: quota rule to change
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditQuotaRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QuotaRuleInfo"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	rule := getQuotaRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditQuotaRuleOption)
+
+	if form.Limit != nil {
+		rule.Limit = max(*form.Limit, -1)
+	}
+	if form.Subjects != nil {
+		subjects := parseLimitSubjects(ctx, *form.Subjects)
+		if ctx.Written() {
+			return
+		}
+		rule.Subjects = subjects
+	}
+
+	if err := quota_model.UpdateRule(ctx, rule); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToQuotaRuleInfo(rule))
+}
+
+// DeleteQuotaRule deletes a quota rule
+func DeleteQuotaRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/quota/rules/{quotarule} admin adminDeleteQuotaRule
+	// ---
+	// summary: Delete a quota rule
+	// parameters:
+	// - name: quotarule
+	//   in: path
+	//   description: quota rule to delete
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule := getQuotaRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := quota_model.DeleteRule(ctx, rule.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRule", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetUserQuota gets the storage usage and the quota groups of a user or organization
+func GetUserQuota(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/{username}/quota admin adminGetUserQuota
+	// ---
+	// summary: Get the storage usage and the quota groups of a user or organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/QuotaInfo"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetQuota(ctx, ctx.ContextUser.ID)
+}
+
+func getQuotaGroupByParams(ctx *context.APIContext) *quota_model.Group {
+	group, err := quota_model.GetGroupByName(ctx, ctx.PathParam("quotagroup"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetGroupByName", err)
+		}
+		return nil
+	}
+	return group
+}
+
+func getQuotaRuleByParams(ctx *context.APIContext) *quota_model.Rule {
+	rule, err := quota_model.GetRuleByName(ctx, ctx.PathParam("quotarule"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRuleByName", err)
+		}
+		return nil
+	}
+	return rule
+}
+
+func parseLimitSubjects(ctx *context.APIContext, values []string) []quota_model.LimitSubject {
+	subjects := make([]quota_model.LimitSubject, 0, len(values))
+	for _, v := range values {
+		subject, err := quota_model.ParseLimitSubject(v)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return nil
+		}
+		subjects = append(subjects, subject)
+	}
+	return subjects
+}
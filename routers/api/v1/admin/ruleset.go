@@ -0,0 +1,173 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListRulesets lists the rulesets of the instance
+func ListRulesets(ctx *context.APIContext) {
+	// swagger:operation GET /admin/rulesets admin adminListRulesets
+	// ---
+	// summary: List the rulesets of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RulesetList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	shared.ListRulesets(ctx, 0)
+}
+
+// CreateRuleset creates a ruleset for the instance
+func CreateRuleset(ctx *context.APIContext) {
+	// swagger:operation POST /admin/rulesets admin adminCreateRuleset
+	// ---
+	// summary: Create a ruleset for the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRulesetOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Ruleset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.CreateRuleset(ctx, 0)
+}
+
+// GetRuleset returns a ruleset of the instance
+func GetRuleset(ctx *context.APIContext) {
+	// swagger:operation GET /admin/rulesets/{id} admin adminGetRuleset
+	// ---
+	// summary: Get a ruleset of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Ruleset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRuleset(ctx, 0)
+}
+
+// EditRuleset edits a ruleset of the instance
+func EditRuleset(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/rulesets/{id} admin adminEditRuleset
+	// ---
+	// summary: Edit a ruleset of the instance
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRulesetOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Ruleset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRuleset(ctx, 0)
+}
+
+// DeleteRuleset deletes a ruleset of the instance
+func DeleteRuleset(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/rulesets/{id} admin adminDeleteRuleset
+	// ---
+	// summary: Delete a ruleset of the instance
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRuleset(ctx, 0)
+}
+
+// ListRulesetViolations lists the violations recorded for a ruleset of the instance
+func ListRulesetViolations(ctx *context.APIContext) {
+	// swagger:operation GET /admin/rulesets/{id}/violations admin adminListRulesetViolations
+	// ---
+	// summary: List the violations recorded for a ruleset of the instance in evaluate mode
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RulesetViolationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRulesetViolations(ctx, 0)
+}
@@ -71,6 +71,7 @@ import (
 	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	audit_model "code.gitea.io/gitea/models/audit"
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
@@ -94,6 +95,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/user"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/services/actions"
+	audit_service "code.gitea.io/gitea/services/audit"
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
@@ -101,9 +103,16 @@ import (
 	_ "code.gitea.io/gitea/routers/api/v1/swagger" // for swagger generation
 
 	"gitea.com/go-chi/binding"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 )
 
+func init() {
+	// the Terraform http backend locks and unlocks states with these methods
+	chi.RegisterMethod("LOCK")
+	chi.RegisterMethod("UNLOCK")
+}
+
 func sudo() func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
 		sudo := ctx.FormString("sudo")
@@ -123,6 +132,8 @@ func sudo() func(ctx *context.APIContext) {
 					return
 				}
 				log.Trace("Sudo from (%s) to: %s", ctx.Doer.Name, user.Name)
+				audit_service.Record(ctx, ctx.Doer, audit_model.ActionUserImpersonate, 0, 0, audit_service.UserTarget(user),
+					"%s %s", ctx.Req.Method, ctx.Req.URL.Path)
 				ctx.Doer = user
 			} else {
 				ctx.JSON(http.StatusForbidden, map[string]string{
@@ -308,7 +319,8 @@ func tokenRequiresScopes(requiredScopeCategories ...auth_model.AccessTokenScopeC
 
 		// use the http method to determine the access level
 		requiredScopeLevel := auth_model.Read
-		if ctx.Req.Method == "POST" || ctx.Req.Method == "PUT" || ctx.Req.Method == "PATCH" || ctx.Req.Method == "DELETE" {
+		if ctx.Req.Method == "POST" || ctx.Req.Method == "PUT" || ctx.Req.Method == "PATCH" || ctx.Req.Method == "DELETE" ||
+			ctx.Req.Method == "LOCK" || ctx.Req.Method == "UNLOCK" {
 			requiredScopeLevel = auth_model.Write
 		}
 
@@ -714,6 +726,13 @@ func mustEnableAttachments(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableTerraform(ctx *context.APIContext) {
+	if !setting.Terraform.Enabled {
+		ctx.NotFound()
+		return
+	}
+}
+
 // bind binding an obj to a func(ctx *context.APIContext)
 func bind[T any](_ T) any {
 	return func(ctx *context.APIContext) {
@@ -1000,6 +1019,7 @@ func Routes() *web.Router {
 				m.Get("", user.GetUserSettings)
 				m.Patch("", bind(api.UserSettingsOptions{}), user.UpdateUserSettings)
 			}, reqToken())
+			m.Get("/quota", user.GetQuota)
 			m.Combo("/emails").
 				Get(user.ListEmails).
 				Post(bind(api.CreateEmailOption{}), user.AddEmail).
@@ -1212,9 +1232,86 @@ func Routes() *web.Router {
 							Delete(repo.DeleteTagProtection)
 					})
 				}, reqToken(), reqAdmin())
+				m.Combo("/push_rule", reqToken(), reqAdmin()).Get(repo.GetPushRule).
+					Put(bind(api.UpdatePushRuleOption{}), repo.UpdatePushRule).
+					Delete(repo.DeletePushRule)
+				m.Group("/secret-scanning/alerts", func() {
+					m.Get("", repo.ListSecretScanningAlerts)
+					m.Combo("/{id}").Get(repo.GetSecretScanningAlert).
+						Patch(bind(api.EditSecretScanningAlertOption{}), repo.EditSecretScanningAlert)
+				}, reqToken(), reqAdmin())
 				m.Group("/actions", func() {
 					m.Get("/tasks", repo.ListActionTasks)
+					m.Group("/runs", func() {
+						m.Get("", repo.ListWorkflowRuns)
+						m.Group("/{run_id}", func() {
+							m.Get("", repo.GetWorkflowRun)
+							m.Get("/jobs", repo.ListWorkflowRunJobs)
+							m.Get("/artifacts", repo.ListWorkflowRunArtifacts)
+							m.Post("/cancel", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.CancelWorkflowRun)
+							m.Post("/rerun", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.RerunWorkflowRun)
+							m.Combo("/pending_deployments").Get(repo.ListPendingDeployments).
+								Post(reqToken(), mustNotBeArchived, bind(api.ReviewPendingDeploymentsOption{}), repo.ReviewPendingDeployments)
+						})
+					})
+					m.Group("/jobs/{job_id}", func() {
+						m.Get("", repo.GetWorkflowJob)
+						m.Get("/logs", repo.DownloadWorkflowJobLogs)
+						m.Post("/rerun", reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.RerunWorkflowJob)
+					})
+					m.Group("/workflows", func() {
+						m.Get("", repo.ListWorkflows)
+						m.Group("/{workflow_id}", func() {
+							m.Get("", repo.GetWorkflow)
+							m.Group("", func() {
+								m.Put("/enable", repo.EnableWorkflow)
+								m.Put("/disable", repo.DisableWorkflow)
+								m.Post("/dispatches", bind(api.CreateActionWorkflowDispatch{}), repo.DispatchWorkflow)
+							}, reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived)
+						})
+					})
+					m.Group("/artifacts", func() {
+						m.Get("", repo.ListArtifacts)
+						m.Group("/{artifact_id}", func() {
+							m.Combo("").Get(repo.GetArtifact).
+								Delete(reqToken(), reqRepoWriter(unit.TypeActions), mustNotBeArchived, repo.DeleteArtifact)
+							m.Get("/zip", repo.DownloadArtifact)
+						})
+					})
 				}, reqRepoReader(unit.TypeActions), context.ReferencesGitRepo(true))
+				m.Group("/environments", func() {
+					m.Get("", repo.ListEnvironments)
+					m.Group("/{environment_name}", func() {
+						m.Combo("").Get(repo.GetEnvironment).
+							Put(reqToken(), reqAdmin(), bind(api.CreateOrUpdateEnvironmentOption{}), repo.CreateOrUpdateEnvironment).
+							Delete(reqToken(), reqAdmin(), repo.DeleteEnvironment)
+						m.Get("/deployments", repo.ListEnvironmentDeployments)
+						m.Group("", func() {
+							m.Get("/secrets", repo.ListEnvironmentSecrets)
+							m.Combo("/secrets/{secretname}").
+								Put(bind(api.CreateOrUpdateSecretOption{}), repo.CreateOrUpdateEnvironmentSecret).
+								Delete(repo.DeleteEnvironmentSecret)
+							m.Get("/variables", repo.ListEnvironmentVariables)
+							m.Combo("/variables/{variablename}").
+								Get(repo.GetEnvironmentVariable).
+								Post(bind(api.CreateVariableOption{}), repo.CreateEnvironmentVariable).
+								Put(bind(api.UpdateVariableOption{}), repo.UpdateEnvironmentVariable).
+								Delete(repo.DeleteEnvironmentVariable)
+						}, reqToken(), reqAdmin())
+					})
+				}, reqRepoReader(unit.TypeActions))
+				m.Group("/terraform/state", func() {
+					m.Get("", repo.ListTerraformStates)
+					m.Group("/{name}", func() {
+						m.Combo("").Get(repo.GetTerraformState).
+							Post(mustNotBeArchived, repo.UpdateTerraformState).
+							Delete(reqAdmin(), repo.DeleteTerraformState)
+						m.Methods("LOCK,POST", "/lock", mustNotBeArchived, repo.LockTerraformState)
+						m.Methods("UNLOCK,DELETE", "/lock", repo.UnlockTerraformState)
+						m.Get("/versions", repo.ListTerraformStateVersions)
+						m.Get("/versions/{serial}", repo.GetTerraformStateVersion)
+					})
+				}, mustEnableTerraform, reqToken(), reqRepoWriter(unit.TypeCode))
 				m.Group("/keys", func() {
 					m.Combo("").Get(repo.ListDeployKeys).
 						Post(bind(api.CreateKeyOption{}), repo.CreateDeployKey)
@@ -1288,6 +1385,7 @@ func Routes() *web.Router {
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Get("/commits", repo.GetPullRequestCommits)
 						m.Get("/files", repo.GetPullRequestFiles)
+						m.Get("/codeowners", repo.GetPullRequestCodeOwners)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest).
 							Delete(reqToken(), mustNotBeArchived, repo.CancelScheduledAutoMerge)
@@ -1497,10 +1595,28 @@ func Routes() *web.Router {
 
 		// NOTE: these are Gitea package management API - see packages.CommonRoutes and packages.DockerContainerRoutes for endpoints that implement package manager APIs
 		m.Group("/packages/{username}", func() {
+			m.Group("/-/cleanup_rules", func() {
+				m.Combo("").Get(packages.ListCleanupRules).
+					Post(bind(api.CreatePackageCleanupRuleOption{}), packages.CreateCleanupRule)
+				m.Group("/{id}", func() {
+					m.Combo("").Get(packages.GetCleanupRule).
+						Patch(bind(api.EditPackageCleanupRuleOption{}), packages.EditCleanupRule).
+						Delete(packages.DeleteCleanupRule)
+					m.Get("/preview", packages.PreviewCleanupRule)
+				})
+			}, reqToken(), reqPackageAccess(perm.AccessModeAdmin))
+			m.Get("/-/components", reqToken(), packages.SearchPackageComponents)
+			m.Group("/{type}/{name}", func() {
+				m.Post("/-/link/{repo_name}", packages.LinkPackage)
+				m.Post("/-/unlink", packages.UnlinkPackage)
+			}, reqToken(), reqPackageAccess(perm.AccessModeWrite))
 			m.Group("/{type}/{name}/{version}", func() {
 				m.Get("", reqToken(), packages.GetPackage)
 				m.Delete("", reqToken(), reqPackageAccess(perm.AccessModeWrite), packages.DeletePackage)
 				m.Get("/files", reqToken(), packages.ListPackageFiles)
+				m.Get("/components", reqToken(), packages.ListPackageComponents)
+				m.Combo("/attestations").Get(reqToken(), packages.ListPackageAttestations).
+					Post(reqToken(), reqPackageAccess(perm.AccessModeWrite), packages.AddPackageAttestation)
 			})
 			m.Get("/", reqToken(), packages.ListPackages)
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryPackage), context.UserAssignmentAPI(), context.PackageAssignmentAPI(), reqPackageAccess(perm.AccessModeRead), checkTokenPublicOnly())
@@ -1517,6 +1633,7 @@ func Routes() *web.Router {
 			m.Combo("").Get(org.Get).
 				Patch(reqToken(), reqOrgOwnership(), bind(api.EditOrgOption{}), org.Edit).
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
+			m.Get("/quota", reqToken(), reqOrgOwnership(), org.GetQuota)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
 			m.Group("/members", func() {
@@ -1558,7 +1675,19 @@ func Routes() *web.Router {
 				m.Post("", bind(api.UpdateUserAvatarOption{}), org.UpdateAvatar)
 				m.Delete("", org.DeleteAvatar)
 			}, reqToken(), reqOrgOwnership())
+			m.Group("/rulesets", func() {
+				m.Combo("").Get(org.ListRulesets).
+					Post(bind(api.CreateRulesetOption{}), org.CreateRuleset)
+				m.Combo("/{id}").Get(org.GetRuleset).
+					Patch(bind(api.EditRulesetOption{}), org.EditRuleset).
+					Delete(org.DeleteRuleset)
+				m.Get("/{id}/violations", org.ListRulesetViolations)
+			}, reqToken(), reqOrgOwnership())
+			m.Combo("/push_rule", reqToken(), reqOrgOwnership()).Get(org.GetPushRule).
+				Put(bind(api.UpdatePushRuleOption{}), org.UpdatePushRule).
+				Delete(org.DeletePushRule)
 			m.Get("/activities/feeds", org.ListOrgActivityFeeds)
+			m.Get("/audit/events", reqToken(), reqOrgOwnership(), org.ListAuditEvents)
 
 			m.Group("/blocks", func() {
 				m.Get("", org.ListBlocks)
@@ -1591,6 +1720,7 @@ func Routes() *web.Router {
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryOrganization), orgAssignment(false, true), reqToken(), reqTeamMembership(), checkTokenPublicOnly())
 
 		m.Group("/admin", func() {
+			m.Get("/audit/events", admin.ListAuditEvents)
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
 				m.Post("/{task}", admin.PostCronTask)
@@ -1613,6 +1743,7 @@ func Routes() *web.Router {
 					m.Get("/badges", admin.ListUserBadges)
 					m.Post("/badges", bind(api.UserBadgeOption{}), admin.AddUserBadges)
 					m.Delete("/badges", bind(api.UserBadgeOption{}), admin.DeleteUserBadges)
+					m.Get("/quota", admin.GetUserQuota)
 				}, context.UserAssignmentAPI())
 			})
 			m.Group("/emails", func() {
@@ -1634,6 +1765,36 @@ func Routes() *web.Router {
 			m.Group("/runners", func() {
 				m.Get("/registration-token", admin.GetRegistrationToken)
 			})
+			m.Group("/rulesets", func() {
+				m.Combo("").Get(admin.ListRulesets).
+					Post(bind(api.CreateRulesetOption{}), admin.CreateRuleset)
+				m.Combo("/{id}").Get(admin.GetRuleset).
+					Patch(bind(api.EditRulesetOption{}), admin.EditRuleset).
+					Delete(admin.DeleteRuleset)
+				m.Get("/{id}/violations", admin.ListRulesetViolations)
+			})
+			m.Group("/quota", func() {
+				m.Group("/groups", func() {
+					m.Combo("").Get(admin.ListQuotaGroups).
+						Post(bind(api.CreateQuotaGroupOption{}), admin.CreateQuotaGroup)
+					m.Group("/{quotagroup}", func() {
+						m.Combo("").Get(admin.GetQuotaGroup).
+							Delete(admin.DeleteQuotaGroup)
+						m.Combo("/rules/{quotarule}").Put(admin.AddRuleToQuotaGroup).
+							Delete(admin.RemoveRuleFromQuotaGroup)
+						m.Get("/users", admin.ListUsersInQuotaGroup)
+						m.Combo("/users/{username}", context.UserAssignmentAPI()).Put(admin.AddUserToQuotaGroup).
+							Delete(admin.RemoveUserFromQuotaGroup)
+					})
+				})
+				m.Group("/rules", func() {
+					m.Combo("").Get(admin.ListQuotaRules).
+						Post(bind(api.CreateQuotaRuleOption{}), admin.CreateQuotaRule)
+					m.Combo("/{quotarule}").Get(admin.GetQuotaRule).
+						Patch(bind(api.EditQuotaRuleOption{}), admin.EditQuotaRule).
+						Delete(admin.DeleteQuotaRule)
+				})
+			})
 		}, tokenRequiresScopes(auth_model.AccessTokenScopeCategoryAdmin), reqToken(), reqSiteAdmin())
 
 		m.Group("/topics", func() {
@@ -0,0 +1,88 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetPushRule returns the push rule of the organization
+func GetPushRule(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/push_rule organization orgGetPushRule
+	// ---
+	// summary: Get the push rule of the organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetPushRule(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// UpdatePushRule creates or replaces the push rule of the organization
+func UpdatePushRule(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/push_rule organization orgUpdatePushRule
+	// ---
+	// summary: Create or replace the push rule of the organization
+	// description: The push rule applies to all repositories of the organization.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdatePushRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdatePushRule(ctx, ctx.Org.Organization.ID, 0)
+}
+
+// DeletePushRule deletes the push rule of the organization
+func DeletePushRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/push_rule organization orgDeletePushRule
+	// ---
+	// summary: Delete the push rule of the organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeletePushRule(ctx, ctx.Org.Organization.ID, 0)
+}
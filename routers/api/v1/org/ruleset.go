@@ -0,0 +1,207 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// ListRulesets lists the rulesets of the organization
+func ListRulesets(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/rulesets organization orgListRulesets
+	// ---
+	// summary: List the rulesets of the organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RulesetList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRulesets(ctx, ctx.Org.Organization.ID)
+}
+
+// CreateRuleset creates a ruleset for the organization
+func CreateRuleset(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/rulesets organization orgCreateRuleset
+	// ---
+	// summary: Create a ruleset for the organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRulesetOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Ruleset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.CreateRuleset(ctx, ctx.Org.Organization.ID)
+}
+
+// GetRuleset returns a ruleset of the organization
+func GetRuleset(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/rulesets/{id} organization orgGetRuleset
+	// ---
+	// summary: Get a ruleset of the organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Ruleset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetRuleset(ctx, ctx.Org.Organization.ID)
+}
+
+// EditRuleset edits a ruleset of the organization
+func EditRuleset(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/rulesets/{id} organization orgEditRuleset
+	// ---
+	// summary: Edit a ruleset of the organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRulesetOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Ruleset"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.EditRuleset(ctx, ctx.Org.Organization.ID)
+}
+
+// DeleteRuleset deletes a ruleset of the organization
+func DeleteRuleset(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/rulesets/{id} organization orgDeleteRuleset
+	// ---
+	// summary: Delete a ruleset of the organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeleteRuleset(ctx, ctx.Org.Organization.ID)
+}
+
+// ListRulesetViolations lists the violations recorded for a ruleset of the organization
+func ListRulesetViolations(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/rulesets/{id}/violations organization orgListRulesetViolations
+	// ---
+	// summary: List the violations recorded for a ruleset of the organization in evaluate mode
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the ruleset
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RulesetViolationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.ListRulesetViolations(ctx, ctx.Org.Organization.ID)
+}
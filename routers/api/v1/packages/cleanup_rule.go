@@ -0,0 +1,321 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/packages"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+)
+
+// ListCleanupRules lists the package cleanup rules of an owner
+func ListCleanupRules(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup_rules package listPackageCleanupRules
+	// ---
+	// summary: List the package cleanup rules of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRuleList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcrs, err := packages.GetCleanupRulesByOwner(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCleanupRulesByOwner", err)
+		return
+	}
+
+	apiRules := make([]*api.PackageCleanupRule, 0, len(pcrs))
+	for _, pcr := range pcrs {
+		apiRules = append(apiRules, convert.ToPackageCleanupRule(pcr))
+	}
+
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// CreateCleanupRule creates a package cleanup rule
+func CreateCleanupRule(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/-/cleanup_rules package createPackageCleanupRule
+	// ---
+	// summary: Create a package cleanup rule
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePackageCleanupRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePackageCleanupRuleOption)
+
+	packageType := packages.Type(form.Type)
+	if has, err := packages.HasOwnerCleanupRuleForPackageType(ctx, ctx.Package.Owner.ID, packageType); err != nil {
+		ctx.Error(http.StatusInternalServerError, "HasOwnerCleanupRuleForPackageType", err)
+		return
+	} else if has {
+		ctx.Error(http.StatusConflict, "HasOwnerCleanupRuleForPackageType", "there is already a cleanup rule for this package type")
+		return
+	}
+
+	pcr, err := packages.InsertCleanupRule(ctx, &packages.PackageCleanupRule{
+		Enabled:       form.Enabled,
+		OwnerID:       ctx.Package.Owner.ID,
+		Type:          packageType,
+		KeepCount:     form.KeepCount,
+		KeepPattern:   form.KeepPattern,
+		RemoveDays:    form.RemoveDays,
+		RemovePattern: form.RemovePattern,
+		MatchFullName: form.MatchFullName,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "InsertCleanupRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToPackageCleanupRule(pcr))
+}
+
+// GetCleanupRule gets a package cleanup rule
+func GetCleanupRule(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup_rules/{id} package getPackageCleanupRule
+	// ---
+	// summary: Get a package cleanup rule
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageCleanupRule(pcr))
+}
+
+// EditCleanupRule edits a package cleanup rule
+func EditCleanupRule(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/-/cleanup_rules/{id} package editPackageCleanupRule
+	// ---
+	// summary: Edit a package cleanup rule
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageCleanupRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageCleanupRule"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	pcr := getCleanupRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.EditPackageCleanupRuleOption)
+
+	if form.Enabled != nil {
+		pcr.Enabled = *form.Enabled
+	}
+	if form.KeepCount != nil {
+		pcr.KeepCount = *form.KeepCount
+	}
+	if form.KeepPattern != nil {
+		pcr.KeepPattern = *form.KeepPattern
+	}
+	if form.RemoveDays != nil {
+		pcr.RemoveDays = *form.RemoveDays
+	}
+	if form.RemovePattern != nil {
+		pcr.RemovePattern = *form.RemovePattern
+	}
+	if form.MatchFullName != nil {
+		pcr.MatchFullName = *form.MatchFullName
+	}
+
+	if err := packages.UpdateCleanupRule(ctx, pcr); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateCleanupRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPackageCleanupRule(pcr))
+}
+
+// DeleteCleanupRule deletes a package cleanup rule
+func DeleteCleanupRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/-/cleanup_rules/{id} package deletePackageCleanupRule
+	// ---
+	// summary: Delete a package cleanup rule
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages.DeleteCleanupRuleByID(ctx, pcr.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteCleanupRuleByID", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// PreviewCleanupRule lists the package versions which would be removed by a cleanup rule
+func PreviewCleanupRule(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/cleanup_rules/{id}/preview package previewPackageCleanupRule
+	// ---
+	// summary: List the package versions which would be removed by a cleanup rule without removing them
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the cleanup rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pcr := getCleanupRuleByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	pds, err := packages_cleanup_service.PreviewCleanupRule(ctx, pcr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "PreviewCleanupRule", err)
+		return
+	}
+
+	apiPackages := make([]*api.Package, 0, len(pds))
+	for _, pd := range pds {
+		apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+			return
+		}
+		apiPackages = append(apiPackages, apiPackage)
+	}
+
+	ctx.JSON(http.StatusOK, apiPackages)
+}
+
+func getCleanupRuleByParams(ctx *context.APIContext) *packages.PackageCleanupRule {
+	pcr, err := packages.GetCleanupRuleByID(ctx, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, packages.ErrPackageCleanupRuleNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCleanupRuleByID", err)
+		}
+		return nil
+	}
+	if pcr.OwnerID != ctx.Package.Owner.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return pcr
+}
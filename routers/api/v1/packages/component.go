@@ -0,0 +1,133 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	container_service "code.gitea.io/gitea/services/packages/container"
+)
+
+// ListPackageComponents gets the components listed in the SBOMs attached to a container image
+func ListPackageComponents(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/components package listPackageComponents
+	// ---
+	// summary: Gets the components listed in the SBOMs attached to a container image
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageComponentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	apiComponents := make([]*api.PackageComponent, 0, 10)
+	if ctx.Package.Descriptor.Package.Type == packages.TypeContainer {
+		components, err := container_service.GetComponents(ctx, ctx.Package.Descriptor)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetComponents", err)
+			return
+		}
+		for _, c := range components {
+			apiComponents = append(apiComponents, convert.ToPackageComponent(c))
+		}
+	}
+
+	ctx.JSON(http.StatusOK, apiComponents)
+}
+
+// SearchPackageComponents searches the container images of an owner which contain a component
+func SearchPackageComponents(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/-/components package searchPackageComponents
+	// ---
+	// summary: Searches the container images of an owner which contain a component listed in their SBOM
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the component, case insensitive
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: query
+	//   description: exact version of the component
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageComponentMatchList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	name := ctx.FormTrim("name")
+	if name == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("name is required"))
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+
+	matches, count, err := container_service.SearchComponents(ctx, &container_model.ComponentSearchOptions{
+		Paginator: &listOptions,
+		OwnerID:   ctx.Package.Owner.ID,
+		Name:      name,
+		Version:   ctx.FormTrim("version"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchComponents", err)
+		return
+	}
+
+	apiMatches := make([]*api.PackageComponentMatch, 0, len(matches))
+	for _, m := range matches {
+		apiMatches = append(apiMatches, convert.ToPackageComponentMatch(m))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiMatches)
+}
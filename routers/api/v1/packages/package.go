@@ -4,17 +4,27 @@
 package packages
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 
 	"code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/optional"
+	attestation_module "code.gitea.io/gitea/modules/packages/attestation"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 	packages_service "code.gitea.io/gitea/services/packages"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 )
 
+// maxAttestationSize is the maximum size of an attestation added through the API
+const maxAttestationSize = 1 << 20
+
 // ListPackages gets all packages of an owner
 func ListPackages(ctx *context.APIContext) {
 	// swagger:operation GET /packages/{owner} package listPackages
@@ -40,7 +50,7 @@ func ListPackages(ctx *context.APIContext) {
 	//   in: query
 	//   description: package type filter
 	//   type: string
-	//   enum: [alpine, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, vagrant]
+	//   enum: [alpine, arch, cargo, chef, composer, conan, conda, container, cran, debian, generic, go, helm, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, terraform, vagrant]
 	// - name: q
 	//   in: query
 	//   description: name filter
@@ -164,7 +174,7 @@ func DeletePackage(ctx *context.APIContext) {
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
-	err := packages_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor.Version)
+	err := packages_cleanup_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "RemovePackageVersion", err)
 		return
@@ -213,3 +223,255 @@ func ListPackageFiles(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, apiPackageFiles)
 }
+
+// ListPackageAttestations gets all attestations of the files of a package
+func ListPackageAttestations(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/attestations package listPackageAttestations
+	// ---
+	// summary: Gets all attestations of the files of a package
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAttestationList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	fas := packages_service.GetFileAttestations(ctx.Package.Descriptor.Files)
+
+	apiAttestations := make([]*api.PackageAttestation, 0, len(fas))
+	for _, fa := range fas {
+		apiAttestations = append(apiAttestations, convert.ToPackageAttestation(fa))
+	}
+
+	ctx.JSON(http.StatusOK, apiAttestations)
+}
+
+// AddPackageAttestation adds an attestation to the files of a package it is about
+func AddPackageAttestation(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/{type}/{name}/{version}/attestations package addPackageAttestation
+	// ---
+	// summary: Add an attestation to the files of a package
+	// description: The attestation can be an in-toto statement, a DSSE envelope, a Sigstore bundle or a PEP 740 attestation.
+	//   It is added to every file of the package whose SHA256 digest is a subject of the attestation.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: the attestation
+	//   schema:
+	//     type: object
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageAttestationList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	data, err := io.ReadAll(io.LimitReader(ctx.Req.Body, maxAttestationSize+1))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadAll", err)
+		return
+	}
+	if len(data) > maxAttestationSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "ReadAll", fmt.Errorf("the attestation exceeds the maximum size of %d bytes", maxAttestationSize))
+		return
+	}
+
+	a, err := attestation_module.Parse(data)
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "Parse", err)
+		return
+	}
+
+	apiAttestations := make([]*api.PackageAttestation, 0, 1)
+	for _, pfd := range ctx.Package.Descriptor.Files {
+		if !a.Statement.HasSubjectDigest(pfd.Blob.HashSHA256) {
+			continue
+		}
+
+		pp, err := packages_service.AddAttestationToPackageFile(ctx, pfd.File, data)
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusBadRequest, "AddAttestationToPackageFile", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "AddAttestationToPackageFile", err)
+			}
+			return
+		}
+		status, _ := a.Verify(pfd.Blob.HashSHA256)
+
+		apiAttestations = append(apiAttestations, convert.ToPackageAttestation(&packages_service.FileAttestation{
+			File:        pfd,
+			Property:    pp,
+			Attestation: a,
+			Status:      status,
+		}))
+	}
+	if len(apiAttestations) == 0 {
+		ctx.Error(http.StatusBadRequest, "HasSubjectDigest", attestation_module.ErrSubjectMismatch)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, apiAttestations)
+}
+
+// LinkPackage sets a repository link for a package
+func LinkPackage(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/{type}/{name}/-/link/{repo_name} package linkPackage
+	// ---
+	// summary: Link a package to a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: repo_name
+	//   in: path
+	//   description: name of the repository to link.
+	//   type: string
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pkg := getPackageByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	repo, err := repo_model.GetRepositoryByName(ctx, ctx.ContextUser.ID, ctx.PathParam("repo_name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByName", err)
+		}
+		return
+	}
+
+	if err := packages_service.LinkToRepository(ctx, pkg, repo, ctx.Doer); err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusBadRequest, "LinkToRepository", err)
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "LinkToRepository", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "LinkToRepository", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusCreated)
+}
+
+// UnlinkPackage removes the repository link of a package
+func UnlinkPackage(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/{type}/{name}/-/unlink package unlinkPackage
+	// ---
+	// summary: Unlink a package from a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pkg := getPackageByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages_service.UnlinkFromRepository(ctx, pkg); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnlinkFromRepository", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getPackageByParams(ctx *context.APIContext) *packages.Package {
+	pkg, err := packages.GetPackageByName(ctx, ctx.Package.Owner.ID, packages.Type(ctx.PathParam("type")), ctx.PathParam("name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageByName", err)
+		}
+		return nil
+	}
+	return pkg
+}
@@ -5,20 +5,28 @@ package repo
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
 	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/routers/api/v1/shared"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/routers/common"
 	actions_service "code.gitea.io/gitea/services/actions"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 	secret_service "code.gitea.io/gitea/services/secrets"
+
+	"github.com/nektos/act/pkg/model"
 )
 
 // ListActionsSecrets list an repo's actions secrets
@@ -121,7 +129,7 @@ func (Action) CreateOrUpdateSecret(ctx *context.APIContext) {
 
 	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
 
-	_, created, err := secret_service.CreateOrUpdateSecret(ctx, 0, repo.ID, ctx.PathParam("secretname"), opt.Data)
+	_, created, err := secret_service.CreateOrUpdateSecret(ctx, ctx.Doer, 0, repo.ID, ctx.PathParam("secretname"), opt.Data)
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "CreateOrUpdateSecret", err)
@@ -175,7 +183,7 @@ func (Action) DeleteSecret(ctx *context.APIContext) {
 
 	repo := ctx.Repo.Repository
 
-	err := secret_service.DeleteSecretByName(ctx, 0, repo.ID, ctx.PathParam("secretname"))
+	err := secret_service.DeleteSecretByName(ctx, ctx.Doer, 0, repo.ID, ctx.PathParam("secretname"))
 	if err != nil {
 		if errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusBadRequest, "DeleteSecret", err)
@@ -581,3 +589,1080 @@ func ListActionTasks(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &res)
 }
+
+// getRunByID gets the run by the "run_id" path parameter, any error will be written to the ctx
+func getRunByID(ctx *context.APIContext) *actions_model.ActionRun {
+	run, err := actions_model.GetRunByID(ctx, ctx.PathParamInt64("run_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunByID", err)
+		}
+		return nil
+	}
+	if run.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	run.Repo = ctx.Repo.Repository
+	return run
+}
+
+// getRunJobByID gets the job by the "job_id" path parameter, any error will be written to the ctx
+func getRunJobByID(ctx *context.APIContext) *actions_model.ActionRunJob {
+	job, err := actions_model.GetRunJobByID(ctx, ctx.PathParamInt64("job_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunJobByID", err)
+		}
+		return nil
+	}
+	if job.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return job
+}
+
+// getArtifactByID gets the uploaded artifact by the "artifact_id" path parameter, any error will be written to the ctx
+func getArtifactByID(ctx *context.APIContext) *actions_model.ActionArtifact {
+	art, err := actions_model.GetArtifactByID(ctx, ctx.PathParamInt64("artifact_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetArtifactByID", err)
+		}
+		return nil
+	}
+	if art.RepoID != ctx.Repo.Repository.ID ||
+		(art.Status != int64(actions_model.ArtifactStatusUploadConfirmed) && art.Status != int64(actions_model.ArtifactStatusExpired)) {
+		ctx.NotFound()
+		return nil
+	}
+	return art
+}
+
+// parseRunStatusFilter converts the "status" query parameter to the statuses of runs
+func parseRunStatusFilter(status string) ([]actions_model.Status, bool) {
+	switch status {
+	case "":
+		return nil, true
+	case "queued":
+		return []actions_model.Status{actions_model.StatusWaiting}, true
+	case "waiting":
+		return []actions_model.Status{actions_model.StatusBlocked}, true
+	case "in_progress":
+		return []actions_model.Status{actions_model.StatusRunning}, true
+	case "completed":
+		return []actions_model.Status{actions_model.StatusSuccess, actions_model.StatusFailure, actions_model.StatusCancelled, actions_model.StatusSkipped}, true
+	case "success":
+		return []actions_model.Status{actions_model.StatusSuccess}, true
+	case "failure":
+		return []actions_model.Status{actions_model.StatusFailure}, true
+	case "cancelled":
+		return []actions_model.Status{actions_model.StatusCancelled}, true
+	case "skipped":
+		return []actions_model.Status{actions_model.StatusSkipped}, true
+	}
+	return nil, false
+}
+
+// ListWorkflowRuns list the workflow runs of a repository
+func ListWorkflowRuns(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs repository ListWorkflowRuns
+	// ---
+	// summary: List a repository's workflow runs
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: event
+	//   in: query
+	//   description: workflow event name
+	//   type: string
+	// - name: branch
+	//   in: query
+	//   description: workflow branch
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: "workflow status (queued, waiting, in_progress, completed, success, failure, cancelled, skipped)"
+	//   type: string
+	// - name: actor
+	//   in: query
+	//   description: triggered by user
+	//   type: string
+	// - name: head_sha
+	//   in: query
+	//   description: triggering sha of the workflow run
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRunsList"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := actions_model.FindRunOptions{
+		ListOptions:  utils.GetListOptions(ctx),
+		RepoID:       ctx.Repo.Repository.ID,
+		TriggerEvent: webhook_module.HookEventType(ctx.FormString("event")),
+		CommitSHA:    ctx.FormString("head_sha"),
+	}
+	if branch := ctx.FormString("branch"); branch != "" {
+		opts.Ref = string(git.RefNameFromBranch(branch))
+	}
+
+	statuses, ok := parseRunStatusFilter(ctx.FormString("status"))
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "Status", fmt.Errorf("invalid status %q", ctx.FormString("status")))
+		return
+	}
+	opts.Status = statuses
+
+	res := &api.ActionWorkflowRunsResponse{Entries: make([]*api.ActionWorkflowRun, 0)}
+
+	if actor := ctx.FormString("actor"); actor != "" {
+		user, err := user_model.GetUserByName(ctx, actor)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.JSON(http.StatusOK, res)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		opts.TriggerUserID = user.ID
+	}
+
+	runs, total, err := db.FindAndCount[actions_model.ActionRun](ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRuns", err)
+		return
+	}
+
+	res.TotalCount = total
+	for _, run := range runs {
+		run.Repo = ctx.Repo.Repository
+		convertedRun, err := convert.ToActionWorkflowRun(ctx, run)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+			return
+		}
+		res.Entries = append(res.Entries, convertedRun)
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetWorkflowRun get a workflow run of a repository
+func GetWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run_id} repository GetWorkflowRun
+	// ---
+	// summary: Get a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	convertedRun, err := convert.ToActionWorkflowRun(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertedRun)
+}
+
+// ListWorkflowRunJobs list the jobs of a workflow run
+func ListWorkflowRunJobs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run_id}/jobs repository ListWorkflowRunJobs
+	// ---
+	// summary: List the jobs of a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowJobsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	jobs, total, err := db.FindAndCount[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RunID:       run.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRunJobs", err)
+		return
+	}
+
+	res := &api.ActionWorkflowJobsResponse{
+		Entries:    make([]*api.ActionWorkflowJob, 0, len(jobs)),
+		TotalCount: total,
+	}
+	for _, job := range jobs {
+		job.Run = run
+		convertedJob, err := convert.ToActionWorkflowJob(ctx, job)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToActionWorkflowJob", err)
+			return
+		}
+		res.Entries = append(res.Entries, convertedJob)
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// ListWorkflowRunArtifacts list the artifacts of a workflow run
+func ListWorkflowRunArtifacts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run_id}/artifacts repository ListWorkflowRunArtifacts
+	// ---
+	// summary: List the artifacts of a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the artifact
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ArtifactsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	listArtifacts(ctx, run.ID)
+}
+
+// ListArtifacts list the artifacts of a repository
+func ListArtifacts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/artifacts repository ListArtifacts
+	// ---
+	// summary: List a repository's artifacts
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: query
+	//   description: name of the artifact
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ArtifactsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listArtifacts(ctx, 0)
+}
+
+func listArtifacts(ctx *context.APIContext, runID int64) {
+	arts, total, err := actions_model.FindUploadedArtifacts(ctx, actions_model.FindArtifactsOptions{
+		ListOptions:  utils.GetListOptions(ctx),
+		RepoID:       ctx.Repo.Repository.ID,
+		RunID:        runID,
+		ArtifactName: ctx.FormString("name"),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUploadedArtifacts", err)
+		return
+	}
+
+	res := &api.ActionArtifactsResponse{
+		Entries:    make([]*api.ActionArtifact, 0, len(arts)),
+		TotalCount: total,
+	}
+	for _, art := range arts {
+		res.Entries = append(res.Entries, convert.ToActionArtifact(ctx.Repo.Repository, art))
+	}
+
+	ctx.SetTotalCountHeader(total)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CancelWorkflowRun cancel a workflow run
+func CancelWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run_id}/cancel repository CancelWorkflowRun
+	// ---
+	// summary: Cancel a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+	if run.Status.IsDone() {
+		ctx.Error(http.StatusConflict, "CancelWorkflowRun", "the run is already done")
+		return
+	}
+
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+
+	if err := actions_service.CancelWorkflowRun(ctx, jobs); err != nil {
+		ctx.Error(http.StatusInternalServerError, "CancelWorkflowRun", err)
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
+
+// RerunWorkflowRun rerun all jobs of a workflow run
+func RerunWorkflowRun(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run_id}/rerun repository RerunWorkflowRun
+	// ---
+	// summary: Rerun all jobs of a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	rerunWorkflowRun(ctx, run, nil)
+}
+
+// RerunWorkflowJob rerun a job and the jobs depending on it
+func RerunWorkflowJob(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/jobs/{job_id}/rerun repository RerunWorkflowJob
+	// ---
+	// summary: Rerun a job of a workflow run and all jobs depending on it
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/conflict"
+
+	job := getRunJobByID(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := job.LoadRun(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRun", err)
+		return
+	}
+	job.Run.Repo = ctx.Repo.Repository
+
+	rerunWorkflowRun(ctx, job.Run, job)
+}
+
+func rerunWorkflowRun(ctx *context.APIContext, run *actions_model.ActionRun, job *actions_model.ActionRunJob) {
+	// can not rerun job when workflow is disabled
+	cfgUnit := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions)
+	if cfgUnit.ActionsConfig().IsWorkflowDisabled(run.WorkflowID) {
+		ctx.Error(http.StatusConflict, "IsWorkflowDisabled", fmt.Sprintf("workflow %s is disabled", run.WorkflowID))
+		return
+	}
+
+	if !run.Status.IsDone() || (job != nil && !job.Status.IsDone()) {
+		ctx.Error(http.StatusConflict, "RerunWorkflowRun", "the run is not done yet")
+		return
+	}
+
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRunJobsByRunID", err)
+		return
+	}
+	if job != nil {
+		// use the job loaded with the others, so GetAllRerunJobs can find it by JobID
+		for _, j := range jobs {
+			if j.ID == job.ID {
+				job = j
+				break
+			}
+		}
+	}
+
+	if err := actions_service.RerunWorkflowRun(ctx, run, jobs, job); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RerunWorkflowRun", err)
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// GetWorkflowJob get a job of a workflow run
+func GetWorkflowJob(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id} repository GetWorkflowJob
+	// ---
+	// summary: Get a job of a workflow run
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowJob"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	job := getRunJobByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	convertedJob, err := convert.ToActionWorkflowJob(ctx, job)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionWorkflowJob", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertedJob)
+}
+
+// DownloadWorkflowJobLogs download the logs of a job
+func DownloadWorkflowJobLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id}/logs repository DownloadWorkflowJobLogs
+	// ---
+	// summary: Download the logs of a job of a workflow run
+	// produces:
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the logs of the job
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	job := getRunJobByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := common.DownloadActionsRunJobLogs(ctx.Base, job); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DownloadActionsRunJobLogs", err)
+		}
+	}
+}
+
+// GetArtifact get an artifact of a repository
+func GetArtifact(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/artifacts/{artifact_id} repository GetArtifact
+	// ---
+	// summary: Get an artifact
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: artifact_id
+	//   in: path
+	//   description: id of the artifact
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Artifact"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	art := getArtifactByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	arts, _, err := actions_model.FindUploadedArtifacts(ctx, actions_model.FindArtifactsOptions{
+		RunID:        art.RunID,
+		ArtifactName: art.ArtifactName,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUploadedArtifacts", err)
+		return
+	}
+	if len(arts) == 0 {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToActionArtifact(ctx.Repo.Repository, arts[0]))
+}
+
+// DeleteArtifact delete an artifact of a repository
+func DeleteArtifact(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/actions/artifacts/{artifact_id} repository DeleteArtifact
+	// ---
+	// summary: Delete an artifact
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: artifact_id
+	//   in: path
+	//   description: id of the artifact
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	art := getArtifactByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_model.SetArtifactNeedDelete(ctx, art.RunID, art.ArtifactName); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetArtifactNeedDelete", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DownloadArtifact download an artifact of a repository
+func DownloadArtifact(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/artifacts/{artifact_id}/zip repository DownloadArtifact
+	// ---
+	// summary: Download an artifact as a zip archive
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: artifact_id
+	//   in: path
+	//   description: id of the artifact
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip archive of the artifact
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "410":
+	//     description: the artifact has expired
+
+	art := getArtifactByID(ctx)
+	if ctx.Written() {
+		return
+	}
+	if art.Status == int64(actions_model.ArtifactStatusExpired) {
+		ctx.Error(http.StatusGone, "DownloadArtifact", "the artifact has expired")
+		return
+	}
+
+	if err := common.DownloadActionsRunArtifacts(ctx.Base, art.RunID, art.ArtifactName); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DownloadActionsRunArtifacts", err)
+		}
+	}
+}
+
+func getWorkflowEntry(ctx *context.APIContext) (string, *git.TreeEntry) {
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return "", nil
+	}
+
+	dir, entry, err := actions_service.GetWorkflowEntry(ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.PathParam("workflow_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetWorkflowEntry", err)
+		}
+		return "", nil
+	}
+	return dir, entry
+}
+
+// ListWorkflows list the workflows of a repository
+func ListWorkflows(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/workflows repository ListWorkflows
+	// ---
+	// summary: List the workflows of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/WorkflowsList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.JSON(http.StatusOK, &api.ActionWorkflowsResponse{Workflows: []*api.ActionWorkflow{}})
+		return
+	}
+
+	dir, entries, err := actions_service.ListDefaultBranchWorkflows(ctx.Repo.Repository, ctx.Repo.GitRepo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListDefaultBranchWorkflows", err)
+		return
+	}
+
+	cfg := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions).ActionsConfig()
+	res := &api.ActionWorkflowsResponse{
+		Workflows:  make([]*api.ActionWorkflow, 0, len(entries)),
+		TotalCount: int64(len(entries)),
+	}
+	for _, entry := range entries {
+		res.Workflows = append(res.Workflows, convert.ToActionWorkflow(ctx.Repo.Repository, dir, entry, cfg.IsWorkflowDisabled(entry.Name())))
+	}
+
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetWorkflow get a workflow of a repository
+func GetWorkflow(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/workflows/{workflow_id} repository GetWorkflow
+	// ---
+	// summary: Get a workflow
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: file name of the workflow
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Workflow"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	dir, entry := getWorkflowEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	cfg := ctx.Repo.Repository.MustGetUnit(ctx, unit.TypeActions).ActionsConfig()
+	ctx.JSON(http.StatusOK, convert.ToActionWorkflow(ctx.Repo.Repository, dir, entry, cfg.IsWorkflowDisabled(entry.Name())))
+}
+
+// EnableWorkflow enable a workflow of a repository
+func EnableWorkflow(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/workflows/{workflow_id}/enable repository EnableWorkflow
+	// ---
+	// summary: Enable a workflow
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: file name of the workflow
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	enableOrDisableWorkflow(ctx, true)
+}
+
+// DisableWorkflow disable a workflow of a repository
+func DisableWorkflow(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/actions/workflows/{workflow_id}/disable repository DisableWorkflow
+	// ---
+	// summary: Disable a workflow
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: file name of the workflow
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	enableOrDisableWorkflow(ctx, false)
+}
+
+func enableOrDisableWorkflow(ctx *context.APIContext, isEnable bool) {
+	_, entry := getWorkflowEntry(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.EnableOrDisableWorkflow(ctx, ctx.Repo.Repository, entry.Name(), isEnable); err != nil {
+		ctx.Error(http.StatusInternalServerError, "EnableOrDisableWorkflow", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DispatchWorkflow create a workflow_dispatch event for a workflow
+func DispatchWorkflow(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/workflows/{workflow_id}/dispatches repository DispatchWorkflow
+	// ---
+	// summary: Create a workflow_dispatch event for a workflow
+	// description: The workflow file is read from the default branch and it must have a workflow_dispatch trigger.
+	//   The inputs are validated against the inputs declared by the trigger.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: workflow_id
+	//   in: path
+	//   description: file name of the workflow
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateActionWorkflowDispatch"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/WorkflowRun"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.CreateActionWorkflowDispatch)
+
+	run, err := actions_service.DispatchActionWorkflow(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.PathParam("workflow_id"), opt.Ref, func(workflowDispatch *model.WorkflowDispatch, inputs map[string]any) error {
+		return actions_service.ValidateWorkflowDispatchInputs(workflowDispatch, opt.Inputs, inputs)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrNotExist):
+			ctx.Error(http.StatusNotFound, "DispatchActionWorkflow", err)
+		case errors.Is(err, util.ErrPermissionDenied):
+			ctx.Error(http.StatusForbidden, "DispatchActionWorkflow", err)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "DispatchActionWorkflow", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "DispatchActionWorkflow", err)
+		}
+		return
+	}
+
+	run.Repo = ctx.Repo.Repository
+	convertedRun, err := convert.ToActionWorkflowRun(ctx, run)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToActionWorkflowRun", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convertedRun)
+}
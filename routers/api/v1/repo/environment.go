@@ -0,0 +1,966 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	secret_model "code.gitea.io/gitea/models/secret"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	secret_service "code.gitea.io/gitea/services/secrets"
+)
+
+// getEnvironmentByName gets the environment by the "environment_name" path parameter, any error will be written to the ctx
+func getEnvironmentByName(ctx *context.APIContext) *actions_model.ActionEnvironment {
+	env, err := actions_model.GetEnvironmentByName(ctx, ctx.Repo.Repository.ID, ctx.PathParam("environment_name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetEnvironmentByName", err)
+		}
+		return nil
+	}
+	return env
+}
+
+// ListEnvironments list the deployment environments of a repository
+func ListEnvironments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments repository repoListEnvironments
+	// ---
+	// summary: List a repository's deployment environments
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/EnvironmentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	envs, count, err := db.FindAndCount[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindEnvironments", err)
+		return
+	}
+
+	apiEnvs := make([]*api.Environment, 0, len(envs))
+	for _, env := range envs {
+		apiEnv, err := convert.ToEnvironment(ctx, env, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToEnvironment", err)
+			return
+		}
+		apiEnvs = append(apiEnvs, apiEnv)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiEnvs)
+}
+
+// GetEnvironment get a deployment environment of a repository
+func GetEnvironment(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name} repository repoGetEnvironment
+	// ---
+	// summary: Get a deployment environment of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Environment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	apiEnv, err := convert.ToEnvironment(ctx, env, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToEnvironment", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiEnv)
+}
+
+// CreateOrUpdateEnvironment create or update a deployment environment of a repository
+func CreateOrUpdateEnvironment(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/environments/{environment_name} repository repoCreateOrUpdateEnvironment
+	// ---
+	// summary: Create or update a deployment environment with its protection rules
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateEnvironmentOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Environment"
+	//   "201":
+	//     "$ref": "#/responses/Environment"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opt := web.GetForm(ctx).(*api.CreateOrUpdateEnvironmentOption)
+
+	reviewerIDs := make([]int64, 0, len(opt.Reviewers))
+	for _, name := range opt.Reviewers {
+		reviewer, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "GetUserByName", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	}
+
+	status := http.StatusOK
+	env, err := actions_model.GetEnvironmentByName(ctx, ctx.Repo.Repository.ID, ctx.PathParam("environment_name"))
+	if errors.Is(err, util.ErrNotExist) {
+		status = http.StatusCreated
+		env, err = actions_service.CreateEnvironment(ctx, ctx.Repo.Repository, ctx.PathParam("environment_name"), opt.DeploymentBranches, reviewerIDs)
+	} else if err == nil {
+		err = actions_service.UpdateEnvironment(ctx, ctx.Repo.Repository, env, opt.DeploymentBranches, reviewerIDs)
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateOrUpdateEnvironment", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateOrUpdateEnvironment", err)
+		}
+		return
+	}
+
+	apiEnv, err := convert.ToEnvironment(ctx, env, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToEnvironment", err)
+		return
+	}
+	ctx.JSON(status, apiEnv)
+}
+
+// DeleteEnvironment delete a deployment environment of a repository
+func DeleteEnvironment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/environments/{environment_name} repository repoDeleteEnvironment
+	// ---
+	// summary: Delete a deployment environment with its secrets, variables and deployment history
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteEnvironment(ctx, env); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteEnvironment", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListEnvironmentDeployments list the deployment history of an environment
+func ListEnvironmentDeployments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/deployments repository repoListEnvironmentDeployments
+	// ---
+	// summary: List the deployments to an environment, the latest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeploymentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	deployments, count, err := db.FindAndCount[actions_model.ActionDeployment](ctx, actions_model.FindDeploymentsOptions{
+		ListOptions:    utils.GetListOptions(ctx),
+		RepoID:         env.RepoID,
+		EnvironmentIDs: []int64{env.ID},
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindDeployments", err)
+		return
+	}
+
+	apiDeployments, err := toDeployments(ctx, env, deployments)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToDeployment", err)
+		return
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiDeployments)
+}
+
+func toDeployments(ctx *context.APIContext, env *actions_model.ActionEnvironment, deployments []*actions_model.ActionDeployment) ([]*api.Deployment, error) {
+	apiDeployments := make([]*api.Deployment, 0, len(deployments))
+	for _, d := range deployments {
+		if env != nil {
+			d.Environment = env
+		}
+		apiDeployment, err := convert.ToDeployment(ctx, d, ctx.Doer)
+		if err != nil {
+			return nil, err
+		}
+		apiDeployments = append(apiDeployments, apiDeployment)
+	}
+	return apiDeployments, nil
+}
+
+// ListEnvironmentSecrets list the secrets of an environment
+func ListEnvironmentSecrets(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/secrets repository repoListEnvironmentSecrets
+	// ---
+	// summary: List the secrets of an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	secrets, count, err := db.FindAndCount[secret_model.Secret](ctx, &secret_model.FindSecretsOptions{
+		ListOptions:   utils.GetListOptions(ctx),
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+	})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiSecrets := make([]*api.Secret, len(secrets))
+	for k, v := range secrets {
+		apiSecrets[k] = &api.Secret{
+			Name:    v.Name,
+			Created: v.CreatedUnix.AsTime(),
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiSecrets)
+}
+
+// CreateOrUpdateEnvironmentSecret create or update a secret of an environment
+func CreateOrUpdateEnvironmentSecret(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/environments/{environment_name}/secrets/{secretname} repository updateEnvironmentSecret
+	// ---
+	// summary: Create or Update a secret value in an environment
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateOrUpdateSecretOption"
+	// responses:
+	//   "201":
+	//     description: response when creating a secret
+	//   "204":
+	//     description: response when updating a secret
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+	opt := web.GetForm(ctx).(*api.CreateOrUpdateSecretOption)
+
+	_, created, err := secret_service.CreateOrUpdateEnvironmentSecret(ctx, ctx.Doer, env.RepoID, env.ID, ctx.PathParam("secretname"), opt.Data)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, "CreateOrUpdateEnvironmentSecret", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateOrUpdateEnvironmentSecret", err)
+		}
+		return
+	}
+
+	if created {
+		ctx.Status(http.StatusCreated)
+	} else {
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// DeleteEnvironmentSecret delete a secret of an environment
+func DeleteEnvironmentSecret(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/environments/{environment_name}/secrets/{secretname} repository deleteEnvironmentSecret
+	// ---
+	// summary: Delete a secret in an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: secretname
+	//   in: path
+	//   description: name of the secret
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: delete one secret of the environment
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := secret_service.DeleteEnvironmentSecretByName(ctx, ctx.Doer, env.RepoID, env.ID, ctx.PathParam("secretname")); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, "DeleteEnvironmentSecret", err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, "DeleteEnvironmentSecret", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteEnvironmentSecret", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListEnvironmentVariables list the variables of an environment
+func ListEnvironmentVariables(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/variables repository getEnvironmentVariablesList
+	// ---
+	// summary: Get the variables of an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VariableList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	vars, count, err := db.FindAndCount[actions_model.ActionVariable](ctx, &actions_model.FindVariablesOpts{
+		ListOptions:   utils.GetListOptions(ctx),
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindVariables", err)
+		return
+	}
+
+	variables := make([]*api.ActionVariable, len(vars))
+	for i, v := range vars {
+		variables[i] = &api.ActionVariable{
+			RepoID: v.RepoID,
+			Name:   v.Name,
+			Data:   v.Data,
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, variables)
+}
+
+// getEnvironmentVariable gets the variable of the environment by the "variablename" path parameter, any error will be written to the ctx
+func getEnvironmentVariable(ctx *context.APIContext, env *actions_model.ActionEnvironment) *actions_model.ActionVariable {
+	v, err := actions_service.GetVariable(ctx, actions_model.FindVariablesOpts{
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+		Name:          ctx.PathParam("variablename"),
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, "GetVariable", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetVariable", err)
+		}
+		return nil
+	}
+	return v
+}
+
+// GetEnvironmentVariable get a variable of an environment
+func GetEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository getEnvironmentVariable
+	// ---
+	// summary: Get a variable of an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionVariable"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+	v := getEnvironmentVariable(ctx, env)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ActionVariable{
+		RepoID: v.RepoID,
+		Name:   v.Name,
+		Data:   v.Data,
+	})
+}
+
+// CreateEnvironmentVariable create a variable of an environment
+func CreateEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository createEnvironmentVariable
+	// ---
+	// summary: Create a variable of an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateVariableOption"
+	// responses:
+	//   "204":
+	//     description: response when creating a variable of an environment
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: variable name already exists.
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+	opt := web.GetForm(ctx).(*api.CreateVariableOption)
+	variableName := ctx.PathParam("variablename")
+
+	v, err := actions_service.GetVariable(ctx, actions_model.FindVariablesOpts{
+		RepoID:        env.RepoID,
+		EnvironmentID: env.ID,
+		Name:          variableName,
+	})
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusInternalServerError, "GetVariable", err)
+		return
+	}
+	if v != nil && v.ID > 0 {
+		ctx.Error(http.StatusConflict, "VariableNameAlreadyExists", util.NewAlreadyExistErrorf("variable name %s already exists", variableName))
+		return
+	}
+
+	if _, err := actions_service.CreateEnvironmentVariable(ctx, env.RepoID, env.ID, variableName, opt.Value); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, "CreateEnvironmentVariable", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateEnvironmentVariable", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UpdateEnvironmentVariable update a variable of an environment
+func UpdateEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository updateEnvironmentVariable
+	// ---
+	// summary: Update a variable of an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdateVariableOption"
+	// responses:
+	//   "204":
+	//     description: response when updating a variable of an environment
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+	v := getEnvironmentVariable(ctx, env)
+	if ctx.Written() {
+		return
+	}
+	opt := web.GetForm(ctx).(*api.UpdateVariableOption)
+
+	if opt.Name == "" {
+		opt.Name = ctx.PathParam("variablename")
+	}
+	if _, err := actions_service.UpdateVariable(ctx, v.ID, opt.Name, opt.Value); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, "UpdateVariable", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateVariable", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteEnvironmentVariable delete a variable of an environment
+func DeleteEnvironmentVariable(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/environments/{environment_name}/variables/{variablename} repository deleteEnvironmentVariable
+	// ---
+	// summary: Delete a variable of an environment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: environment_name
+	//   in: path
+	//   description: name of the environment
+	//   type: string
+	//   required: true
+	// - name: variablename
+	//   in: path
+	//   description: name of the variable
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     description: response when deleting a variable of an environment
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	env := getEnvironmentByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteEnvironmentVariableByName(ctx, env.RepoID, env.ID, ctx.PathParam("variablename")); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, "DeleteEnvironmentVariableByName", err)
+		} else if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, "DeleteEnvironmentVariableByName", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteEnvironmentVariableByName", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListPendingDeployments list the deployments of a workflow run waiting for reviews
+func ListPendingDeployments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/runs/{run_id}/pending_deployments repository listWorkflowRunPendingDeployments
+	// ---
+	// summary: List the deployments of a workflow run which are waiting for the reviews of the required reviewers
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeploymentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	deployments, err := db.Find[actions_model.ActionDeployment](ctx, actions_model.FindDeploymentsOptions{
+		RepoID:       run.RepoID,
+		RunID:        run.ID,
+		ReviewStatus: actions_model.DeploymentReviewPending,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindDeployments", err)
+		return
+	}
+
+	apiDeployments, err := toDeployments(ctx, nil, deployments)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToDeployment", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiDeployments)
+}
+
+// ReviewPendingDeployments approve or reject the pending deployments of a workflow run
+func ReviewPendingDeployments(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/actions/runs/{run_id}/pending_deployments repository reviewWorkflowRunPendingDeployments
+	// ---
+	// summary: Approve or reject the pending deployments of a workflow run, the doer must be a required reviewer of the environments
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: run_id
+	//   in: path
+	//   description: id of the run
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ReviewPendingDeploymentsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeploymentList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	run := getRunByID(ctx)
+	if ctx.Written() {
+		return
+	}
+	opt := web.GetForm(ctx).(*api.ReviewPendingDeploymentsOption)
+
+	deployments, err := actions_service.ReviewDeployments(ctx, ctx.Doer, run, opt.EnvironmentIDs, opt.State == "approved", opt.Comment)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusNotFound, "ReviewDeployments", err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "ReviewDeployments", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "ReviewDeployments", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ReviewDeployments", err)
+		}
+		return
+	}
+
+	apiDeployments, err := toDeployments(ctx, nil, deployments)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToDeployment", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiDeployments)
+}
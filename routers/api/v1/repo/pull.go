@@ -37,6 +37,7 @@ import (
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/gitdiff"
 	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/mergequeue"
 	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
@@ -941,6 +942,17 @@ func MergePullRequest(ctx *context.APIContext) {
 		mergeCheckType = pull_service.MergeCheckTypeManually
 	}
 
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFirstMatchProtectedBranchRule", err)
+		return
+	}
+	// pull requests are merged through the merge queue unless an admin forces the merge
+	useMergeQueue := pb != nil && pb.EnableMergeQueue && !manuallyMerged && !form.ForceMerge
+	if useMergeQueue {
+		mergeCheckType = pull_service.MergeCheckTypeQueue
+	}
+
 	// start with merging by checking
 	if err := pull_service.CheckPullMergeable(ctx, ctx.Doer, &ctx.Repo.Permission, pr, mergeCheckType, form.ForceMerge); err != nil {
 		if errors.Is(err, pull_service.ErrIsClosed) {
@@ -999,6 +1011,22 @@ func MergePullRequest(ctx *context.APIContext) {
 		message += "\n\n" + form.MergeMessageField
 	}
 
+	if useMergeQueue {
+		if err := mergequeue.AddToMergeQueue(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message); err != nil {
+			if pull_model.IsErrAlreadyInMergeQueue(err) {
+				ctx.Error(http.StatusConflict, "AddToMergeQueue", err)
+			} else if models.IsErrInvalidMergeStyle(err) {
+				ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s can not be used by the merge queue", repo_model.MergeStyle(form.Do)))
+			} else {
+				ctx.Error(http.StatusInternalServerError, "AddToMergeQueue", err)
+			}
+			return
+		}
+		// the pull request is merged by the merge queue
+		ctx.Status(http.StatusCreated)
+		return
+	}
+
 	if form.MergeWhenChecksSucceed {
 		scheduled, err := automerge.ScheduleAutoMerge(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message)
 		if err != nil {
@@ -1667,3 +1695,85 @@ func GetPullRequestFiles(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &apiFiles)
 }
+
+// GetPullRequestCodeOwners gets the approval state of the code owners of the files changed by a pull request
+func GetPullRequestCodeOwners(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/codeowners repository repoGetPullRequestCodeOwners
+	// ---
+	// summary: Get the approval state of the code owners of the files changed by a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestCodeOwners"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFirstMatchProtectedBranchRule", err)
+		return
+	}
+
+	approvals, err := pull_service.GetCodeOwnersApprovals(ctx, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCodeOwnersApprovals", err)
+		return
+	}
+
+	apiCodeOwners := &api.PullRequestCodeOwners{
+		Required: pb != nil && pb.RequireCodeOwnerApproval,
+		Approved: approvals.IsApproved(),
+		Paths:    make([]*api.PullRequestCodeOwnersPath, 0, len(approvals)),
+	}
+	for _, p := range approvals {
+		apiPath := &api.PullRequestCodeOwnersPath{
+			Path:     p.Path,
+			Approved: p.IsApproved(),
+			Owners:   make([]*api.PullRequestCodeOwner, 0, len(p.Owners)),
+		}
+		for _, o := range p.Owners {
+			apiOwner := &api.PullRequestCodeOwner{Approved: o.Approved}
+			if o.User != nil {
+				apiOwner.User = convert.ToUser(ctx, o.User, ctx.Doer)
+			} else {
+				apiOwner.Team, err = convert.ToTeam(ctx, o.Team)
+				if err != nil {
+					ctx.Error(http.StatusInternalServerError, "ToTeam", err)
+					return
+				}
+			}
+			apiPath.Owners = append(apiPath.Owners, apiOwner)
+		}
+		apiCodeOwners.Paths = append(apiCodeOwners.Paths, apiPath)
+	}
+
+	ctx.JSON(http.StatusOK, apiCodeOwners)
+}
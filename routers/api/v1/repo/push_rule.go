@@ -0,0 +1,97 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"code.gitea.io/gitea/routers/api/v1/shared"
+	"code.gitea.io/gitea/services/context"
+)
+
+// GetPushRule returns the push rule of the repository
+func GetPushRule(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/push_rule repository repoGetPushRule
+	// ---
+	// summary: Get the push rule of the repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRule"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.GetPushRule(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// UpdatePushRule creates or replaces the push rule of the repository
+func UpdatePushRule(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/push_rule repository repoUpdatePushRule
+	// ---
+	// summary: Create or replace the push rule of the repository
+	// description: The push rule is checked in addition to the push rule of the organization owning the repository.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UpdatePushRuleOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRule"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	shared.UpdatePushRule(ctx, 0, ctx.Repo.Repository.ID)
+}
+
+// DeletePushRule deletes the push rule of the repository
+func DeletePushRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/push_rule repository repoDeletePushRule
+	// ---
+	// summary: Delete the push rule of the repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	shared.DeletePushRule(ctx, 0, ctx.Repo.Repository.ID)
+}
@@ -0,0 +1,25 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	quota_model "code.gitea.io/gitea/models/quota"
+	"code.gitea.io/gitea/services/context"
+)
+
+// checkQuota checks if the repository owner may store the additional size for the subject and responds with an error if not
+func checkQuota(ctx *context.APIContext, subject quota_model.LimitSubject, size int64) bool {
+	ok, err := quota_model.EvaluateForUser(ctx, ctx.Repo.Repository.OwnerID, subject, size)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "EvaluateForUser", err)
+		return false
+	}
+	if !ok {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", "quota exceeded")
+		return false
+	}
+	return true
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	quota_model "code.gitea.io/gitea/models/quota"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -191,6 +192,8 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
 
 	// Check if attachments are enabled
 	if !setting.Attachment.Enabled {
@@ -233,6 +236,10 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 		return
 	}
 
+	if !checkQuota(ctx, quota_model.LimitSubjectSizeAssetsAttachmentsReleases, max(size, 0)) {
+		return
+	}
+
 	// Create a new attachment and save the file
 	attach, err := attachment.UploadAttachment(ctx, content, setting.Repository.Release.AllowedTypes, size, &repo_model.Attachment{
 		Name:       filename,
@@ -0,0 +1,217 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	secretscanning_service "code.gitea.io/gitea/services/secretscanning"
+)
+
+// getSecretScanningAlert gets the alert by the "id" path parameter, any error will be written to the ctx
+func getSecretScanningAlert(ctx *context.APIContext) *git_model.SecretScanningAlert {
+	alert, err := git_model.GetSecretScanningAlertByID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSecretScanningAlertByID", err)
+		}
+		return nil
+	}
+	return alert
+}
+
+// ListSecretScanningAlerts list the secret scanning alerts of a repository
+func ListSecretScanningAlerts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/secret-scanning/alerts repository repoListSecretScanningAlerts
+	// ---
+	// summary: List a repository's secret scanning alerts
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: state
+	//   in: query
+	//   description: only list the alerts with this state
+	//   type: string
+	//   enum: [open, resolved]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretScanningAlertList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	state := git_model.SecretScanningAlertState(ctx.FormString("state"))
+	if state != "" && !state.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid state")
+		return
+	}
+
+	alerts, count, err := db.FindAndCount[git_model.SecretScanningAlert](ctx, git_model.FindSecretScanningAlertsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		State:       state,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindSecretScanningAlerts", err)
+		return
+	}
+
+	apiAlerts := make([]*api.SecretScanningAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		apiAlert, err := convert.ToSecretScanningAlert(ctx, alert, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToSecretScanningAlert", err)
+			return
+		}
+		apiAlerts = append(apiAlerts, apiAlert)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiAlerts)
+}
+
+// GetSecretScanningAlert get a secret scanning alert of a repository
+func GetSecretScanningAlert(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/secret-scanning/alerts/{id} repository repoGetSecretScanningAlert
+	// ---
+	// summary: Get a secret scanning alert of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the alert
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretScanningAlert"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	alert := getSecretScanningAlert(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	apiAlert, err := convert.ToSecretScanningAlert(ctx, alert, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToSecretScanningAlert", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiAlert)
+}
+
+// EditSecretScanningAlert resolves or reopens a secret scanning alert of a repository
+func EditSecretScanningAlert(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/secret-scanning/alerts/{id} repository repoEditSecretScanningAlert
+	// ---
+	// summary: Resolve or reopen a secret scanning alert of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the alert
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditSecretScanningAlertOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SecretScanningAlert"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditSecretScanningAlertOption)
+
+	alert := getSecretScanningAlert(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	var err error
+	switch git_model.SecretScanningAlertState(form.State) {
+	case git_model.SecretScanningAlertStateResolved:
+		err = secretscanning_service.ResolveAlert(ctx, ctx.Doer, ctx.Repo.Repository, alert, git_model.SecretScanningResolution(form.Resolution), form.ResolutionComment)
+	case git_model.SecretScanningAlertStateOpen:
+		err = secretscanning_service.ReopenAlert(ctx, ctx.Doer, ctx.Repo.Repository, alert)
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid state")
+		return
+	}
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "EditSecretScanningAlert", err)
+		}
+		return
+	}
+
+	apiAlert, err := convert.ToSecretScanningAlert(ctx, alert, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToSecretScanningAlert", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiAlert)
+}
@@ -0,0 +1,583 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models/db"
+	terraform_model "code.gitea.io/gitea/models/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+	terraform_service "code.gitea.io/gitea/services/terraform"
+)
+
+// getTerraformStateByName gets the state by the "name" path parameter, any error will be written to the ctx
+func getTerraformStateByName(ctx *context.APIContext) *terraform_model.TerraformState {
+	s, err := terraform_model.GetStateByName(ctx, ctx.Repo.Repository.ID, ctx.PathParam("name"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetStateByName", err)
+		}
+		return nil
+	}
+	return s
+}
+
+// readTerraformBody reads the request body up to the maximum state size, any error will be written to the ctx
+func readTerraformBody(ctx *context.APIContext) []byte {
+	if ctx.Req.Body == nil {
+		return nil
+	}
+	r := io.Reader(ctx.Req.Body)
+	if setting.Terraform.MaxStateSize >= 0 {
+		r = io.LimitReader(r, setting.Terraform.MaxStateSize+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReadBody", err)
+		return nil
+	}
+	if setting.Terraform.MaxStateSize >= 0 && int64(len(data)) > setting.Terraform.MaxStateSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "ReadBody", fmt.Errorf("the state exceeds the maximum size of %d bytes", setting.Terraform.MaxStateSize))
+		return nil
+	}
+	return data
+}
+
+// writeTerraformLockInfo responds with the lock info of the current lock which Terraform shows to the user
+func writeTerraformLockInfo(ctx *context.APIContext, status int, s *terraform_model.TerraformState) {
+	ctx.Resp.Header().Set("Content-Type", "application/json")
+	ctx.Resp.WriteHeader(status)
+	_, _ = ctx.Resp.Write([]byte(s.LockInfo))
+}
+
+// writeTerraformContent responds with the raw content of a state version
+func writeTerraformContent(ctx *context.APIContext, v *terraform_model.TerraformStateVersion) {
+	content, err := terraform_service.GetStateVersionContent(ctx, v)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStateVersionContent", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "application/json")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	_, _ = ctx.Resp.Write(content)
+}
+
+// ListTerraformStates lists the Terraform states of a repository
+func ListTerraformStates(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/terraform/state repository repoListTerraformStates
+	// ---
+	// summary: List the Terraform states of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TerraformStateList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	states, count, err := db.FindAndCount[terraform_model.TerraformState](ctx, terraform_model.FindStatesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindStates", err)
+		return
+	}
+
+	apiStates := make([]*api.TerraformState, 0, len(states))
+	for _, s := range states {
+		apiState, err := convert.ToTerraformState(ctx, s, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToTerraformState", err)
+			return
+		}
+		apiStates = append(apiStates, apiState)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiStates)
+}
+
+// GetTerraformState serves the latest version of a state to the http backend of Terraform
+func GetTerraformState(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/terraform/state/{name} repository repoGetTerraformState
+	// ---
+	// summary: Get the content of the latest version of a Terraform state
+	// description: This is the address of the Terraform http backend.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the content of the state
+	//   "204":
+	//     description: the state has not been written yet
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s, err := terraform_model.GetStateByName(ctx, ctx.Repo.Repository.ID, ctx.PathParam("name"))
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusInternalServerError, "GetStateByName", err)
+		return
+	}
+	// Terraform starts with an empty state if there is no content
+	if s == nil || s.LatestVersionID == 0 {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	v, err := terraform_model.GetStateVersionByID(ctx, s.LatestVersionID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStateVersionByID", err)
+		return
+	}
+	writeTerraformContent(ctx, v)
+}
+
+// UpdateTerraformState stores a new version of a state sent by the http backend of Terraform
+func UpdateTerraformState(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/terraform/state/{name} repository repoUpdateTerraformState
+	// ---
+	// summary: Store a new version of a Terraform state
+	// description: The state is created if it doesn't exist. If the state is locked, the id of the lock must be passed.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// - name: ID
+	//   in: query
+	//   description: id of the lock held by Terraform
+	//   type: string
+	// - name: body
+	//   in: body
+	//   description: the state written by Terraform
+	//   schema:
+	//     type: object
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "423":
+	//     description: the state is locked by another lock
+
+	name := ctx.PathParam("name")
+	if !terraform_service.IsValidStateName(name) {
+		ctx.Error(http.StatusBadRequest, "IsValidStateName", fmt.Errorf("invalid state name %q", name))
+		return
+	}
+
+	content := readTerraformBody(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if contentMD5 := ctx.Req.Header.Get("Content-MD5"); contentMD5 != "" {
+		sum := md5.Sum(content)
+		if expected, err := base64.StdEncoding.DecodeString(contentMD5); err != nil || !bytes.Equal(expected, sum[:]) {
+			ctx.Error(http.StatusBadRequest, "Content-MD5", errors.New("the checksum of the state doesn't match"))
+			return
+		}
+	}
+
+	if _, err := terraform_service.UpdateState(ctx, ctx.Doer, ctx.Repo.Repository.ID, name, ctx.FormString("ID"), content); err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusBadRequest, "UpdateState", err)
+		case errors.Is(err, terraform_model.ErrStateLocked):
+			if s, getErr := terraform_model.GetStateByName(ctx, ctx.Repo.Repository.ID, name); getErr == nil {
+				writeTerraformLockInfo(ctx, http.StatusLocked, s)
+			} else {
+				ctx.Error(http.StatusLocked, "UpdateState", err)
+			}
+		default:
+			ctx.Error(http.StatusInternalServerError, "UpdateState", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// DeleteTerraformState deletes a state with all its versions
+func DeleteTerraformState(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/terraform/state/{name} repository repoDeleteTerraformState
+	// ---
+	// summary: Delete a Terraform state with all its versions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// - name: ID
+	//   in: query
+	//   description: id of the lock if the state is locked
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "423":
+	//     description: the state is locked by another lock
+
+	s := getTerraformStateByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := terraform_service.DeleteState(ctx, s, ctx.FormString("ID")); err != nil {
+		if errors.Is(err, terraform_model.ErrStateLocked) {
+			writeTerraformLockInfo(ctx, http.StatusLocked, s)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteState", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// LockTerraformState locks a state for the http backend of Terraform, it also accepts the LOCK method
+func LockTerraformState(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/terraform/state/{name}/lock repository repoLockTerraformState
+	// ---
+	// summary: Lock a Terraform state
+	// description: This is the lock address of the Terraform http backend, the LOCK method is accepted as well.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: the lock info of Terraform
+	//   schema:
+	//     type: object
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "423":
+	//     description: the state is locked by another lock, the response contains the lock info of the current lock
+
+	name := ctx.PathParam("name")
+	if !terraform_service.IsValidStateName(name) {
+		ctx.Error(http.StatusBadRequest, "IsValidStateName", fmt.Errorf("invalid state name %q", name))
+		return
+	}
+
+	lockInfo := readTerraformBody(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	s, err := terraform_service.LockState(ctx, ctx.Doer, ctx.Repo.Repository.ID, name, lockInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusBadRequest, "LockState", err)
+		case errors.Is(err, terraform_model.ErrStateLocked) && s != nil:
+			writeTerraformLockInfo(ctx, http.StatusLocked, s)
+		default:
+			ctx.Error(http.StatusInternalServerError, "LockState", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// UnlockTerraformState unlocks a state for the http backend of Terraform, it also accepts the UNLOCK method
+func UnlockTerraformState(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/terraform/state/{name}/lock repository repoUnlockTerraformState
+	// ---
+	// summary: Unlock a Terraform state
+	// description: This is the unlock address of the Terraform http backend, the UNLOCK method is accepted as well.
+	//   The state is unlocked regardless of the current lock if the body is empty.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   description: the lock info of Terraform
+	//   schema:
+	//     type: object
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: the state is locked by another lock, the response contains the lock info of the current lock
+
+	s := getTerraformStateByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	lockInfo := readTerraformBody(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	s, err := terraform_service.UnlockState(ctx, s, lockInfo)
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusBadRequest, "UnlockState", err)
+		case errors.Is(err, terraform_model.ErrStateLocked) && s != nil:
+			writeTerraformLockInfo(ctx, http.StatusConflict, s)
+		default:
+			ctx.Error(http.StatusInternalServerError, "UnlockState", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// ListTerraformStateVersions lists the versions of a state, the newest first
+func ListTerraformStateVersions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/terraform/state/{name}/versions repository repoListTerraformStateVersions
+	// ---
+	// summary: List the versions of a Terraform state
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TerraformStateVersionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getTerraformStateByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	versions, count, err := db.FindAndCount[terraform_model.TerraformStateVersion](ctx, terraform_model.FindStateVersionsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		StateID:     s.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindStateVersions", err)
+		return
+	}
+
+	apiVersions := make([]*api.TerraformStateVersion, 0, len(versions))
+	for _, v := range versions {
+		apiVersion, err := convert.ToTerraformStateVersion(ctx, v, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToTerraformStateVersion", err)
+			return
+		}
+		apiVersions = append(apiVersions, apiVersion)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiVersions)
+}
+
+// GetTerraformStateVersion serves the content of a version of a state
+func GetTerraformStateVersion(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/terraform/state/{name}/versions/{serial} repository repoGetTerraformStateVersion
+	// ---
+	// summary: Get the content of a version of a Terraform state
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the state
+	//   type: string
+	//   required: true
+	// - name: serial
+	//   in: path
+	//   description: serial of the version
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the content of the version
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getTerraformStateByName(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	serial, err := strconv.ParseInt(ctx.PathParam("serial"), 10, 64)
+	if err != nil {
+		ctx.NotFound(err)
+		return
+	}
+
+	v, err := terraform_model.GetStateVersionBySerial(ctx, s.ID, serial)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetStateVersionBySerial", err)
+		}
+		return
+	}
+	writeTerraformContent(ctx, v)
+}
@@ -0,0 +1,43 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"net/http"
+	"strings"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListAuditEvents responds with the audit events of the given owner, or all events if ownerID is 0
+func ListAuditEvents(ctx *context.APIContext, ownerID int64) {
+	before, since, err := context.GetQueryBeforeSince(ctx.Base)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	events, count, err := db.FindAndCount[audit_model.Event](ctx, &audit_model.FindEventsOptions{
+		ListOptions: listOptions,
+		OwnerID:     ownerID,
+		DoerName:    strings.TrimSpace(ctx.FormString("actor")),
+		Action:      audit_model.Action(ctx.FormString("action")),
+		Since:       timeutil.TimeStamp(since),
+		Before:      timeutil.TimeStamp(before),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindAuditEvents", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, convert.ToAuditEvents(events))
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	git_model "code.gitea.io/gitea/models/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	audit_service "code.gitea.io/gitea/services/audit"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetPushRule responds with the push rule of the organization (repoID 0) or of the repository (ownerID 0)
+func GetPushRule(ctx *context.APIContext, ownerID, repoID int64) {
+	rule, err := git_model.GetPushRule(ctx, ownerID, repoID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPushRule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPushRule(rule))
+}
+
+// UpdatePushRule creates or replaces the push rule of the organization (repoID 0) or of the repository (ownerID 0)
+func UpdatePushRule(ctx *context.APIContext, ownerID, repoID int64) {
+	form := web.GetForm(ctx).(*api.UpdatePushRuleOption)
+
+	rule, err := git_model.GetPushRule(ctx, ownerID, repoID)
+	if err != nil {
+		if !errors.Is(err, util.ErrNotExist) {
+			ctx.Error(http.StatusInternalServerError, "GetPushRule", err)
+			return
+		}
+		rule = &git_model.PushRule{OwnerID: ownerID, RepoID: repoID}
+	}
+
+	rule.CommitMessagePattern = form.CommitMessagePattern
+	rule.RequireVerifiedAuthorEmail = form.RequireVerifiedAuthorEmail
+	rule.RequireVerifiedCommitterEmail = form.RequireVerifiedCommitterEmail
+	rule.MaxFileSize = form.MaxFileSize
+	rule.ForbiddenFilePatterns = form.ForbiddenFilePatterns
+	if err := rule.Validate(); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "Validate", err)
+		return
+	}
+
+	if err := git_model.SavePushRule(ctx, rule); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SavePushRule", err)
+		return
+	}
+
+	recordPushRuleEvent(ctx, audit_model.ActionPushRuleUpdate, rule)
+
+	ctx.JSON(http.StatusOK, convert.ToPushRule(rule))
+}
+
+// DeletePushRule deletes the push rule of the organization (repoID 0) or of the repository (ownerID 0)
+func DeletePushRule(ctx *context.APIContext, ownerID, repoID int64) {
+	rule, err := git_model.GetPushRule(ctx, ownerID, repoID)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPushRule", err)
+		}
+		return
+	}
+
+	if err := git_model.DeletePushRule(ctx, ownerID, repoID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeletePushRule", err)
+		return
+	}
+
+	recordPushRuleEvent(ctx, audit_model.ActionPushRuleDelete, rule)
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func recordPushRuleEvent(ctx *context.APIContext, action audit_model.Action, rule *git_model.PushRule) {
+	if rule.IsOrgRule() {
+		audit_service.Record(ctx, ctx.Doer, action, rule.OwnerID, 0, audit_service.PushRuleTarget(rule, ctx.Org.Organization.Name), "")
+		return
+	}
+	audit_service.RecordRepo(ctx, ctx.Doer, action, ctx.Repo.Repository, audit_service.PushRuleTarget(rule, ctx.Repo.Repository.FullName()), "")
+}
@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"net/http"
+
+	quota_model "code.gitea.io/gitea/models/quota"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetQuota responds with the storage usage and the quota groups of the owner
+func GetQuota(ctx *context.APIContext, ownerID int64) {
+	used, err := quota_model.GetUsedForUser(ctx, ownerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsedForUser", err)
+		return
+	}
+
+	groups, err := quota_model.GetGroupsForUser(ctx, ownerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetGroupsForUser", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToQuotaInfo(used, groups))
+}
@@ -0,0 +1,330 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package shared
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	audit_service "code.gitea.io/gitea/services/audit"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListRulesets responds with the rulesets of the owner, or the instance level rulesets if ownerID is 0
+func ListRulesets(ctx *context.APIContext, ownerID int64) {
+	listOptions := utils.GetListOptions(ctx)
+	rulesets, count, err := db.FindAndCount[git_model.Ruleset](ctx, &git_model.FindRulesetsOptions{
+		ListOptions: listOptions,
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRulesets", err)
+		return
+	}
+
+	apiRulesets := make([]*api.Ruleset, 0, len(rulesets))
+	for _, rs := range rulesets {
+		apiRuleset, err := convert.ToRuleset(ctx, rs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToRuleset", err)
+			return
+		}
+		apiRulesets = append(apiRulesets, apiRuleset)
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiRulesets)
+}
+
+// GetRuleset responds with the ruleset of the owner
+func GetRuleset(ctx *context.APIContext, ownerID int64) {
+	rs := getRulesetByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+	respondRuleset(ctx, http.StatusOK, rs)
+}
+
+// CreateRuleset creates a ruleset for the owner
+func CreateRuleset(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.CreateRulesetOption)
+
+	enforcement := git_model.RulesetEnforcementActive
+	if form.Enforcement != "" {
+		var err error
+		if enforcement, err = git_model.ParseRulesetEnforcement(form.Enforcement); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ParseRulesetEnforcement", err)
+			return
+		}
+	}
+
+	rs := &git_model.Ruleset{
+		OwnerID:                       ownerID,
+		Name:                          form.Name,
+		Target:                        git_model.RulesetTarget(form.Target),
+		Enforcement:                   enforcement,
+		RepoNamePatterns:              form.RepoNamePatterns,
+		RepoTopics:                    form.RepoTopics,
+		RefPatterns:                   form.RefPatterns,
+		RestrictCreation:              form.RestrictCreation,
+		RestrictUpdate:                form.RestrictUpdate,
+		RestrictDeletion:              form.RestrictDeletion,
+		BlockForcePush:                form.BlockForcePush,
+		RequireSignedCommits:          form.RequireSignedCommits,
+		ProtectedFilePatterns:         form.ProtectedFilePatterns,
+		RequiredApprovals:             form.RequiredApprovals,
+		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
+		BlockOnOfficialReviewRequests: form.BlockOnOfficialReviewRequests,
+		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      form.RequireCodeOwnerApproval,
+		EnableStatusCheck:             form.EnableStatusCheck,
+		StatusCheckContexts:           form.StatusCheckContexts,
+	}
+	if !setRulesetBypassLists(ctx, rs, form.BypassUsers, form.BypassTeams) || !validateRuleset(ctx, rs) {
+		return
+	}
+
+	if err := git_model.InsertRuleset(ctx, rs); err != nil {
+		ctx.Error(http.StatusInternalServerError, "InsertRuleset", err)
+		return
+	}
+
+	audit_service.Record(ctx, ctx.Doer, audit_model.ActionRulesetCreate, ownerID, 0, audit_service.RulesetTarget(rs), "")
+
+	respondRuleset(ctx, http.StatusCreated, rs)
+}
+
+// EditRuleset edits the ruleset of the owner
+func EditRuleset(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.EditRulesetOption)
+
+	rs := getRulesetByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil {
+		rs.Name = *form.Name
+	}
+	if form.Enforcement != nil {
+		enforcement, err := git_model.ParseRulesetEnforcement(*form.Enforcement)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ParseRulesetEnforcement", err)
+			return
+		}
+		rs.Enforcement = enforcement
+	}
+	if form.RepoNamePatterns != nil {
+		rs.RepoNamePatterns = form.RepoNamePatterns
+	}
+	if form.RepoTopics != nil {
+		rs.RepoTopics = form.RepoTopics
+	}
+	if form.RefPatterns != nil {
+		rs.RefPatterns = form.RefPatterns
+	}
+	if form.RestrictCreation != nil {
+		rs.RestrictCreation = *form.RestrictCreation
+	}
+	if form.RestrictUpdate != nil {
+		rs.RestrictUpdate = *form.RestrictUpdate
+	}
+	if form.RestrictDeletion != nil {
+		rs.RestrictDeletion = *form.RestrictDeletion
+	}
+	if form.BlockForcePush != nil {
+		rs.BlockForcePush = *form.BlockForcePush
+	}
+	if form.RequireSignedCommits != nil {
+		rs.RequireSignedCommits = *form.RequireSignedCommits
+	}
+	if form.ProtectedFilePatterns != nil {
+		rs.ProtectedFilePatterns = *form.ProtectedFilePatterns
+	}
+	if form.RequiredApprovals != nil {
+		rs.RequiredApprovals = *form.RequiredApprovals
+	}
+	if form.BlockOnRejectedReviews != nil {
+		rs.BlockOnRejectedReviews = *form.BlockOnRejectedReviews
+	}
+	if form.BlockOnOfficialReviewRequests != nil {
+		rs.BlockOnOfficialReviewRequests = *form.BlockOnOfficialReviewRequests
+	}
+	if form.BlockOnOutdatedBranch != nil {
+		rs.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
+	}
+	if form.RequireCodeOwnerApproval != nil {
+		rs.RequireCodeOwnerApproval = *form.RequireCodeOwnerApproval
+	}
+	if form.EnableStatusCheck != nil {
+		rs.EnableStatusCheck = *form.EnableStatusCheck
+	}
+	if form.StatusCheckContexts != nil {
+		rs.StatusCheckContexts = form.StatusCheckContexts
+	}
+
+	if form.BypassUsers != nil || form.BypassTeams != nil {
+		bypassUsers, bypassTeams := form.BypassUsers, form.BypassTeams
+		var err error
+		if bypassUsers == nil {
+			if bypassUsers, err = user_model.GetUserNamesByIDs(ctx, rs.BypassUserIDs); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserNamesByIDs", err)
+				return
+			}
+		}
+		if bypassTeams == nil {
+			if bypassTeams, err = organization.GetTeamNamesByID(ctx, rs.BypassTeamIDs); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetTeamNamesByID", err)
+				return
+			}
+		}
+		if !setRulesetBypassLists(ctx, rs, bypassUsers, bypassTeams) {
+			return
+		}
+	}
+
+	if !validateRuleset(ctx, rs) {
+		return
+	}
+
+	if err := git_model.UpdateRuleset(ctx, rs); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRuleset", err)
+		return
+	}
+
+	audit_service.Record(ctx, ctx.Doer, audit_model.ActionRulesetUpdate, ownerID, 0, audit_service.RulesetTarget(rs), "")
+
+	respondRuleset(ctx, http.StatusOK, rs)
+}
+
+// DeleteRuleset deletes the ruleset of the owner
+func DeleteRuleset(ctx *context.APIContext, ownerID int64) {
+	rs := getRulesetByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+
+	if err := git_model.DeleteRuleset(ctx, rs.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRuleset", err)
+		return
+	}
+
+	audit_service.Record(ctx, ctx.Doer, audit_model.ActionRulesetDelete, ownerID, 0, audit_service.RulesetTarget(rs), "")
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRulesetViolations responds with the violations recorded for the ruleset of the owner
+func ListRulesetViolations(ctx *context.APIContext, ownerID int64) {
+	rs := getRulesetByParams(ctx, ownerID)
+	if ctx.Written() {
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	violations, count, err := db.FindAndCount[git_model.RulesetViolation](ctx, &git_model.FindRulesetViolationsOptions{
+		ListOptions: listOptions,
+		RulesetID:   rs.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRulesetViolations", err)
+		return
+	}
+
+	apiViolations, err := convert.ToRulesetViolations(ctx, violations, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToRulesetViolations", err)
+		return
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiViolations)
+}
+
+func getRulesetByParams(ctx *context.APIContext, ownerID int64) *git_model.Ruleset {
+	rs, err := git_model.GetRulesetByID(ctx, ownerID, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRulesetByID", err)
+		}
+		return nil
+	}
+	return rs
+}
+
+// setRulesetBypassLists resolves the names of the users and teams which are allowed to bypass the ruleset
+func setRulesetBypassLists(ctx *context.APIContext, rs *git_model.Ruleset, userNames, teamNames []string) bool {
+	userIDs, err := user_model.GetUserIDsByNames(ctx, userNames, false)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetUserIDsByNames", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
+		}
+		return false
+	}
+
+	var teamIDs []int64
+	if len(teamNames) > 0 {
+		if rs.OwnerID == 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "BypassTeams", errors.New("instance level rulesets cannot be bypassed by teams"))
+			return false
+		}
+		teamIDs, err = organization.GetTeamIDsByNames(ctx, rs.OwnerID, teamNames, false)
+		if err != nil {
+			if organization.IsErrTeamNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "GetTeamIDsByNames", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
+			}
+			return false
+		}
+	}
+
+	rs.BypassUserIDs = userIDs
+	rs.BypassTeamIDs = teamIDs
+	return true
+}
+
+func validateRuleset(ctx *context.APIContext, rs *git_model.Ruleset) bool {
+	if strings.TrimSpace(rs.Name) == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "Name", errors.New("name must not be empty"))
+		return false
+	}
+	if rs.RequiredApprovals < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "RequiredApprovals", errors.New("required_approvals must not be negative"))
+		return false
+	}
+	for _, patterns := range [][]string{rs.RepoNamePatterns, rs.RefPatterns, rs.StatusCheckContexts} {
+		if err := git_model.ValidateRulesetPatterns(patterns); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "ValidateRulesetPatterns", err)
+			return false
+		}
+	}
+	return true
+}
+
+func respondRuleset(ctx *context.APIContext, status int, rs *git_model.Ruleset) {
+	apiRuleset, err := convert.ToRuleset(ctx, rs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToRuleset", err)
+		return
+	}
+	ctx.JSON(status, apiRuleset)
+}
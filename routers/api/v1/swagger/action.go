@@ -32,3 +32,24 @@ type swaggerResponseVariableList struct {
 	// in:body
 	Body []api.ActionVariable `json:"body"`
 }
+
+// Environment
+// swagger:response Environment
+type swaggerResponseEnvironment struct {
+	// in:body
+	Body api.Environment `json:"body"`
+}
+
+// EnvironmentList
+// swagger:response EnvironmentList
+type swaggerResponseEnvironmentList struct {
+	// in:body
+	Body []api.Environment `json:"body"`
+}
+
+// DeploymentList
+// swagger:response DeploymentList
+type swaggerResponseDeploymentList struct {
+	// in:body
+	Body []api.Deployment `json:"body"`
+}
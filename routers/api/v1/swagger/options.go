@@ -205,4 +205,40 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UpdateVariableOption api.UpdateVariableOption
+
+	// in:body
+	CreateActionWorkflowDispatch api.CreateActionWorkflowDispatch
+
+	// in:body
+	CreateOrUpdateEnvironmentOption api.CreateOrUpdateEnvironmentOption
+
+	// in:body
+	ReviewPendingDeploymentsOption api.ReviewPendingDeploymentsOption
+
+	// in:body
+	CreatePackageCleanupRuleOption api.CreatePackageCleanupRuleOption
+
+	// in:body
+	EditPackageCleanupRuleOption api.EditPackageCleanupRuleOption
+
+	// in:body
+	CreateQuotaGroupOption api.CreateQuotaGroupOption
+
+	// in:body
+	CreateQuotaRuleOption api.CreateQuotaRuleOption
+
+	// in:body
+	EditQuotaRuleOption api.EditQuotaRuleOption
+
+	// in:body
+	CreateRulesetOption api.CreateRulesetOption
+
+	// in:body
+	EditRulesetOption api.EditRulesetOption
+
+	// in:body
+	UpdatePushRuleOption api.UpdatePushRuleOption
+
+	// in:body
+	EditSecretScanningAlertOption api.EditSecretScanningAlertOption
 }
@@ -27,3 +27,38 @@ type swaggerResponsePackageFileList struct {
 	// in:body
 	Body []api.PackageFile `json:"body"`
 }
+
+// PackageAttestationList
+// swagger:response PackageAttestationList
+type swaggerResponsePackageAttestationList struct {
+	// in:body
+	Body []api.PackageAttestation `json:"body"`
+}
+
+// PackageComponentList
+// swagger:response PackageComponentList
+type swaggerResponsePackageComponentList struct {
+	// in:body
+	Body []api.PackageComponent `json:"body"`
+}
+
+// PackageComponentMatchList
+// swagger:response PackageComponentMatchList
+type swaggerResponsePackageComponentMatchList struct {
+	// in:body
+	Body []api.PackageComponentMatch `json:"body"`
+}
+
+// PackageCleanupRule
+// swagger:response PackageCleanupRule
+type swaggerResponsePackageCleanupRule struct {
+	// in:body
+	Body api.PackageCleanupRule `json:"body"`
+}
+
+// PackageCleanupRuleList
+// swagger:response PackageCleanupRuleList
+type swaggerResponsePackageCleanupRuleList struct {
+	// in:body
+	Body []api.PackageCleanupRule `json:"body"`
+}
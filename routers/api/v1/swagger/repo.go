@@ -267,6 +267,13 @@ type swaggerCommitList struct {
 	Body []api.Commit `json:"body"`
 }
 
+// PullRequestCodeOwners
+// swagger:response PullRequestCodeOwners
+type swaggerPullRequestCodeOwners struct {
+	// in: body
+	Body api.PullRequestCodeOwners `json:"body"`
+}
+
 // ChangedFileList
 // swagger:response ChangedFileList
 type swaggerChangedFileList struct {
@@ -443,6 +450,62 @@ type swaggerRepoTasksList struct {
 	Body api.ActionTaskResponse `json:"body"`
 }
 
+// WorkflowRun
+// swagger:response WorkflowRun
+type swaggerRepoWorkflowRun struct {
+	// in:body
+	Body api.ActionWorkflowRun `json:"body"`
+}
+
+// WorkflowRunsList
+// swagger:response WorkflowRunsList
+type swaggerRepoWorkflowRunsList struct {
+	// in:body
+	Body api.ActionWorkflowRunsResponse `json:"body"`
+}
+
+// WorkflowJob
+// swagger:response WorkflowJob
+type swaggerRepoWorkflowJob struct {
+	// in:body
+	Body api.ActionWorkflowJob `json:"body"`
+}
+
+// WorkflowJobsList
+// swagger:response WorkflowJobsList
+type swaggerRepoWorkflowJobsList struct {
+	// in:body
+	Body api.ActionWorkflowJobsResponse `json:"body"`
+}
+
+// Artifact
+// swagger:response Artifact
+type swaggerRepoArtifact struct {
+	// in:body
+	Body api.ActionArtifact `json:"body"`
+}
+
+// ArtifactsList
+// swagger:response ArtifactsList
+type swaggerRepoArtifactsList struct {
+	// in:body
+	Body api.ActionArtifactsResponse `json:"body"`
+}
+
+// Workflow
+// swagger:response Workflow
+type swaggerRepoWorkflow struct {
+	// in:body
+	Body api.ActionWorkflow `json:"body"`
+}
+
+// WorkflowsList
+// swagger:response WorkflowsList
+type swaggerRepoWorkflowsList struct {
+	// in:body
+	Body api.ActionWorkflowsResponse `json:"body"`
+}
+
 // swagger:response Compare
 type swaggerCompare struct {
 	// in:body
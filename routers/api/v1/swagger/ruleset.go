@@ -0,0 +1,29 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Ruleset
+// swagger:response Ruleset
+type swaggerResponseRuleset struct {
+	// in:body
+	Body api.Ruleset `json:"body"`
+}
+
+// RulesetList
+// swagger:response RulesetList
+type swaggerResponseRulesetList struct {
+	// in:body
+	Body []api.Ruleset `json:"body"`
+}
+
+// RulesetViolationList
+// swagger:response RulesetViolationList
+type swaggerResponseRulesetViolationList struct {
+	// in:body
+	Body []api.RulesetViolation `json:"body"`
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// SecretScanningAlert
+// swagger:response SecretScanningAlert
+type swaggerResponseSecretScanningAlert struct {
+	// in:body
+	Body api.SecretScanningAlert `json:"body"`
+}
+
+// SecretScanningAlertList
+// swagger:response SecretScanningAlertList
+type swaggerResponseSecretScanningAlertList struct {
+	// in:body
+	Body []api.SecretScanningAlert `json:"body"`
+}
@@ -0,0 +1,20 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import api "code.gitea.io/gitea/modules/structs"
+
+// TerraformStateList
+// swagger:response TerraformStateList
+type swaggerResponseTerraformStateList struct {
+	// in:body
+	Body []api.TerraformState `json:"body"`
+}
+
+// TerraformStateVersionList
+// swagger:response TerraformStateVersionList
+type swaggerResponseTerraformStateVersionList struct {
+	// in:body
+	Body []api.TerraformStateVersion `json:"body"`
+}
@@ -11,11 +11,13 @@ import (
 	"strconv"
 	"strings"
 
+	audit_model "code.gitea.io/gitea/models/audit"
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	audit_service "code.gitea.io/gitea/services/audit"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/convert"
 )
@@ -128,6 +130,8 @@ func CreateAccessToken(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "NewAccessToken", err)
 		return
 	}
+	audit_service.Record(ctx, ctx.Doer, audit_model.ActionAccessTokenCreate, ctx.ContextUser.ID, 0,
+		audit_service.Target{Type: audit_model.TargetTypeAccessToken, ID: t.ID, Name: t.Name}, "scope: %s", t.Scope)
 	ctx.JSON(http.StatusCreated, &api.AccessToken{
 		Name:           t.Name,
 		Token:          t.Token,
@@ -202,6 +206,8 @@ func DeleteAccessToken(ctx *context.APIContext) {
 		}
 		return
 	}
+	audit_service.Record(ctx, ctx.Doer, audit_model.ActionAccessTokenDelete, ctx.ContextUser.ID, 0,
+		audit_service.Target{Type: audit_model.TargetTypeAccessToken, ID: tokenID}, "")
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/metrics"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+)
+
+// requestGroup returns the group of the route which handles the request, the groups are coarse
+// to keep the cardinality of the metrics low
+func requestGroup(req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.Path, setting.AppSubURL)
+	switch {
+	case strings.HasPrefix(path, "/api/v1/"), strings.HasPrefix(path, "/swagger."):
+		return "api"
+	case strings.HasPrefix(path, "/api/internal/"):
+		return "internal"
+	case strings.HasPrefix(path, "/api/packages/"), strings.HasPrefix(path, "/v2/"), path == "/v2":
+		return "packages"
+	case strings.HasPrefix(path, "/api/actions"), strings.HasPrefix(path, "/twirp/"):
+		return "actions"
+	case strings.HasPrefix(path, "/assets/"), strings.HasPrefix(path, "/avatars/"), strings.HasPrefix(path, "/repo-avatars/"):
+		return "assets"
+	case path == "/metrics", strings.HasPrefix(path, "/api/healthz"):
+		return "monitoring"
+	case strings.Contains(path, "/info/refs"), strings.HasSuffix(path, "/git-upload-pack"), strings.HasSuffix(path, "/git-receive-pack"),
+		strings.Contains(path, "/info/lfs/"):
+		return "git"
+	}
+	return "web"
+}
+
+// requestMethod returns the method of the request, the non-standard methods are reported as "OTHER"
+func requestMethod(req *http.Request) string {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return req.Method
+	}
+	return "OTHER"
+}
+
+// RequestMetrics returns a middleware which records the duration of the requests by route group
+func RequestMetrics() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			wrapped := context.WrapResponseWriter(resp)
+			defer func() {
+				status := wrapped.WrittenStatus()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				metrics.ObserveHTTPRequest(requestGroup(req), requestMethod(req), status, time.Since(start))
+			}()
+			next.ServeHTTP(wrapped, req)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestGroup(t *testing.T) {
+	cases := map[string]string{
+		"/":                                            "web",
+		"/user2/repo1/issues/1":                        "web",
+		"/api/v1/repos/user2/repo1":                    "api",
+		"/api/internal/hook/post-receive":              "internal",
+		"/api/packages/user2/npm/pkg":                  "packages",
+		"/v2/user2/image/manifests/latest":             "packages",
+		"/api/actions/runner.v1.RunnerService":         "actions",
+		"/api/actions_cache/_apis/artifactcache/cache": "actions",
+		"/twirp/github.actions.results.api.v1.ArtifactService/CreateArtifact": "actions",
+		"/assets/js/index.js":                     "assets",
+		"/metrics":                                "monitoring",
+		"/user2/repo1.git/info/refs":              "git",
+		"/user2/repo1.git/git-upload-pack":        "git",
+		"/user2/repo1.git/info/lfs/objects/batch": "git",
+	}
+	for path, group := range cases {
+		assert.Equal(t, group, requestGroup(httptest.NewRequest("GET", path, nil)), path)
+	}
+}
+
+func TestRequestMethod(t *testing.T) {
+	assert.Equal(t, "GET", requestMethod(httptest.NewRequest("GET", "/", nil)))
+	assert.Equal(t, "OTHER", requestMethod(httptest.NewRequest("FOO", "/", nil)))
+}
@@ -60,6 +60,10 @@ func ProtocolMiddlewares() (handlers []any) {
 		})
 	})
 
+	if setting.Metrics.Enabled {
+		handlers = append(handlers, RequestMetrics())
+	}
+
 	if setting.ReverseProxyLimit > 0 {
 		opt := proxy.NewForwardedHeadersOptions().
 			WithForwardLimit(setting.ReverseProxyLimit).
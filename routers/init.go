@@ -45,9 +45,11 @@ import (
 	"code.gitea.io/gitea/services/mailer"
 	mailer_incoming "code.gitea.io/gitea/services/mailer/incoming"
 	markup_service "code.gitea.io/gitea/services/markup"
+	"code.gitea.io/gitea/services/mergequeue"
 	repo_migrations "code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	"code.gitea.io/gitea/services/oauth2_provider"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
 	pull_service "code.gitea.io/gitea/services/pull"
 	release_service "code.gitea.io/gitea/services/release"
 	repo_service "code.gitea.io/gitea/services/repository"
@@ -159,8 +161,10 @@ func InitWebInstalled(ctx context.Context) {
 	mustInit(webhook.Init)
 	mustInit(pull_service.Init)
 	mustInit(automerge.Init)
+	mustInit(mergequeue.Init)
 	mustInit(task.Init)
 	mustInit(repo_migrations.Init)
+	mustInit(packages_proxy_service.Init)
 	eventsource.GetManager().Init()
 	mustInitCtx(ctx, mailer_incoming.Init)
 
@@ -210,6 +214,12 @@ func NormalRoutes() *web.Router {
 		r.Mount(prefix, actions_router.ArtifactsRoutes(prefix))
 		prefix = actions_router.ArtifactV4RouteBase
 		r.Mount(prefix, actions_router.ArtifactsV4Routes(prefix))
+
+		// Runners use it as the external cache server by setting `cache.external_server` to `{ROOT_URL}api/actions_cache/`
+		prefix = "/api/actions_cache"
+		r.Mount(prefix, actions_router.CacheRoutes(prefix))
+		prefix = actions_router.CacheV2RouteBase
+		r.Mount(prefix, actions_router.CacheV2Routes(prefix))
 	}
 
 	r.NotFound(func(w http.ResponseWriter, req *http.Request) {
@@ -171,6 +171,14 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
+	// handle merge queue merging, the pushed merge group merges all the pull requests queued before the pull request
+	if opts.PushTrigger == repo_module.PushTriggerMergeQueue {
+		handleMergeGroupMerging(ctx, opts, ownerName, repoName, updates)
+		if ctx.Written() {
+			return
+		}
+	}
+
 	isPrivate := opts.GitPushOptions.Bool(private.GitPushOptionRepoPrivate)
 	isTemplate := opts.GitPushOptions.Bool(private.GitPushOptionRepoTemplate)
 	// Handle Push Options
@@ -378,3 +386,70 @@ func handlePullRequestMerging(ctx *gitea_context.PrivateContext, opts *private.H
 		ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{Err: "Failed to update PR to merged"})
 	}
 }
+
+func handleMergeGroupMerging(ctx *gitea_context.PrivateContext, opts *private.HookOptions, ownerName, repoName string, updates []*repo_module.PushUpdateOptions) {
+	if len(updates) == 0 {
+		ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+			Err: fmt.Sprintf("Pushing a merge group (pr:%d) no commits pushed ", opts.PullRequestID),
+		})
+		return
+	}
+
+	pr, err := issues_model.GetPullRequestByID(ctx, opts.PullRequestID)
+	if err != nil {
+		log.Error("GetPullRequestByID[%d]: %v", opts.PullRequestID, err)
+		ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{Err: "GetPullRequestByID failed"})
+		return
+	}
+
+	pusher, err := loadContextCacheUser(ctx, opts.UserID)
+	if err != nil {
+		log.Error("Failed to Update: %s/%s Error: %v", ownerName, repoName, err)
+		ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{Err: "Load pusher user failed"})
+		return
+	}
+
+	err = db.WithTx(ctx, func(ctx context.Context) error {
+		exist, lastEntry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+		if err != nil {
+			return fmt.Errorf("GetMergeQueueEntryByPullID[%d]: %v", pr.ID, err)
+		} else if !exist {
+			return fmt.Errorf("pull request %d is not in the merge queue", pr.ID)
+		}
+		entries, err := pull_model.GetMergeQueueEntries(ctx, lastEntry.RepoID, lastEntry.BaseBranch)
+		if err != nil {
+			return fmt.Errorf("GetMergeQueueEntries: %v", err)
+		}
+
+		// every pull request of the merge group is merged with its own merge group commit
+		for _, entry := range entries {
+			if entry.ID > lastEntry.ID {
+				break
+			}
+			groupPR, err := issues_model.GetPullRequestByID(ctx, entry.PullID)
+			if err != nil {
+				return fmt.Errorf("GetPullRequestByID[%d]: %v", entry.PullID, err)
+			}
+			groupPR.MergedCommitID = entry.GroupCommitID
+			groupPR.MergedUnix = timeutil.TimeStampNow()
+			groupPR.Merger = pusher
+			groupPR.MergerID = pusher.ID
+
+			if err := pull_model.RemoveFromMergeQueue(ctx, groupPR.ID); err != nil {
+				return fmt.Errorf("RemoveFromMergeQueue[%d]: %v", groupPR.ID, err)
+			}
+			// Removing an auto merge pull and ignore if not exist
+			if err := pull_model.DeleteScheduledAutoMerge(ctx, groupPR.ID); err != nil && !db.IsErrNotExist(err) {
+				return fmt.Errorf("DeleteScheduledAutoMerge[%d]: %v", groupPR.ID, err)
+			}
+			if _, err := groupPR.SetMerged(ctx); err != nil {
+				return fmt.Errorf("SetMerged failed: %s/%s Error: %v", ownerName, repoName, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Error("Failed to update merge group PRs to merged: %v", err)
+		ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{Err: "Failed to update merge group PRs to merged"})
+	}
+}
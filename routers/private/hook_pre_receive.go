@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
@@ -14,14 +15,18 @@ import (
 	issues_model "code.gitea.io/gitea/models/issues"
 	perm_model "code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
+	quota_model "code.gitea.io/gitea/models/quota"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/web"
 	gitea_context "code.gitea.io/gitea/services/context"
 	pull_service "code.gitea.io/gitea/services/pull"
+	secretscanning_service "code.gitea.io/gitea/services/secretscanning"
 )
 
 type preReceiveContext struct {
@@ -42,6 +47,15 @@ type preReceiveContext struct {
 	protectedTags    []*git_model.ProtectedTag
 	gotProtectedTags bool
 
+	rulesets map[git_model.RulesetTarget][]*git_model.Ruleset
+
+	pushRules      []*git_model.PushRule
+	gotPushRules   bool
+	verifiedEmails map[string]bool
+
+	scannedBlobs  container.Set[string]
+	bypassedLeaks []*secretscanning_service.Leak
+
 	env []string
 
 	opts *private.HookOptions
@@ -101,6 +115,40 @@ func (ctx *preReceiveContext) AssertCreatePullRequest() bool {
 	return true
 }
 
+// assertQuota rejects pushes which add data if the repository owner exceeds the git quota.
+// Pushes which only delete refs are always allowed so that owners can free space.
+func (ctx *preReceiveContext) assertQuota() bool {
+	emptyObjectID := ctx.Repo.GetObjectFormat().EmptyObjectID().String()
+	addsData := false
+	for _, newCommitID := range ctx.opts.NewCommitIDs {
+		if newCommitID != emptyObjectID {
+			addsData = true
+			break
+		}
+	}
+	if !addsData {
+		return true
+	}
+
+	repo := ctx.Repo.Repository
+	ok, err := quota_model.EvaluateForUser(ctx, repo.OwnerID, quota_model.LimitSubjectSizeGitAll, 0)
+	if err != nil {
+		log.Error("Unable to evaluate the quota of %-v: %v", repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to evaluate quota: %v", err),
+		})
+		return false
+	}
+	if !ok {
+		log.Warn("Forbidden: the owner of %-v exceeds the quota", repo)
+		ctx.JSON(http.StatusRequestEntityTooLarge, private.Response{
+			UserMsg: "The repository owner has exceeded the storage quota.",
+		})
+		return false
+	}
+	return true
+}
+
 // HookPreReceive checks whether a individual commit is acceptable
 func HookPreReceive(ctx *gitea_context.PrivateContext) {
 	opts := web.GetForm(ctx).(*private.HookOptions)
@@ -111,6 +159,10 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		opts:           opts,
 	}
 
+	if !ourCtx.assertQuota() {
+		return
+	}
+
 	// Iterate across the provided old commit IDs
 	for i := range opts.OldCommitIDs {
 		oldCommitID := opts.OldCommitIDs[i]
@@ -130,6 +182,26 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		if ctx.Written() {
 			return
 		}
+
+		preReceiveRulesets(ourCtx, oldCommitID, newCommitID, refFullName)
+		if ctx.Written() {
+			return
+		}
+
+		preReceivePushRules(ourCtx, newCommitID, refFullName)
+		if ctx.Written() {
+			return
+		}
+
+		preReceiveSecretScanning(ourCtx, newCommitID, refFullName)
+		if ctx.Written() {
+			return
+		}
+	}
+
+	recordBypassedSecrets(ourCtx)
+	if ctx.Written() {
+		return
 	}
 
 	ctx.PlainText(http.StatusOK, "ok")
@@ -227,9 +299,34 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, r
 		}
 	}
 
+	// 4. Enforce require linear history
+	if protectBranch.RequireLinearHistory {
+		cmd := git.NewCommand(ctx, "rev-list", "--min-parents=2", "--max-count=1").AddDynamicArguments(newCommitID)
+		if oldCommitID == objectFormat.EmptyObjectID().String() {
+			// a new branch may start from the existing history, only the pushed commits are checked
+			cmd.AddArguments("--not", "--all")
+		} else {
+			cmd.AddDynamicArguments("^" + oldCommitID)
+		}
+		output, _, err := cmd.RunStdString(&git.RunOpts{Dir: repo.RepoPath(), Env: ctx.env})
+		if err != nil {
+			log.Error("Unable to detect merge commits between: %s and %s in %-v Error: %v", oldCommitID, newCommitID, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Fail to detect merge commits: %v", err),
+			})
+			return
+		} else if mergeCommitID := strings.TrimSpace(output); mergeCommitID != "" {
+			log.Warn("Forbidden: Branch: %s in %-v requires a linear history, merge commit %s is not allowed", branchName, repo, mergeCommitID)
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("branch %s requires a linear history, merge commit %s is not allowed", branchName, mergeCommitID),
+			})
+			return
+		}
+	}
+
 	// Now there are several tests which can be overridden:
 	//
-	// 4. Check protected file patterns - this is overridable from the UI
+	// 5. Check protected file patterns - this is overridable from the UI
 	changedProtectedfiles := false
 	protectedFilePath := ""
 
@@ -250,7 +347,34 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, r
 		}
 	}
 
-	// 5. Check if the doer is allowed to push (and force-push if the incoming push is a force-push)
+	// The merge queue must push the merge group of the pull request, no matter who is allowed to push or merge,
+	// because the pull requests of the merge group are marked as merged with their merge group commits
+	if ctx.opts.PushTrigger == repo_module.PushTriggerMergeQueue {
+		pr, err := issues_model.GetPullRequestByID(ctx, ctx.opts.PullRequestID)
+		if err != nil {
+			log.Error("Unable to get PullRequest %d Error: %v", ctx.opts.PullRequestID, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Unable to get PullRequest %d Error: %v", ctx.opts.PullRequestID, err),
+			})
+			return
+		}
+		if err := pull_service.CheckMergeGroupCommit(ctx, pr, newCommitID); err != nil {
+			if models.IsErrDisallowedToMerge(err) {
+				log.Warn("Forbidden: Commit %s pushed to protected branch %s in %-v is not the merge group of pr #%d: %s", newCommitID, branchName, repo, pr.Index, err.Error())
+				ctx.JSON(http.StatusForbidden, private.Response{
+					UserMsg: fmt.Sprintf("Not allowed to push to protected branch %s: %s", branchName, err.Error()),
+				})
+				return
+			}
+			log.Error("Unable to check the merge group of pr #%d in %-v: %v", pr.Index, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Unable to check the merge group of pull request %d. Error: %v", ctx.opts.PullRequestID, err),
+			})
+			return
+		}
+	}
+
+	// 6. Check if the doer is allowed to push (and force-push if the incoming push is a force-push)
 	var canPush bool
 	if ctx.opts.DeployKeyID != 0 {
 		// This flag is only ever true if protectBranch.CanForcePush is true
@@ -275,11 +399,11 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, r
 		}
 	}
 
-	// 6. If we're not allowed to push directly
+	// 7. If we're not allowed to push directly
 	if !canPush {
 		// Is this is a merge from the UI/API?
 		if ctx.opts.PullRequestID == 0 {
-			// 6a. If we're not merging from the UI/API then there are two ways we got here:
+			// 7a. If we're not merging from the UI/API then there are two ways we got here:
 			//
 			// We are changing a protected file and we're not allowed to do that
 			if changedProtectedfiles {
@@ -321,7 +445,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, r
 			})
 			return
 		}
-		// 6b. Merge (from UI or API)
+		// 7b. Merge (from UI or API)
 
 		// Get the PR, user and permissions for the user in the repository
 		pr, err := issues_model.GetPullRequestByID(ctx, ctx.opts.PullRequestID)
@@ -372,8 +496,13 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID string, r
 			return
 		}
 
-		// Check all status checks and reviews are ok
-		if err := pull_service.CheckPullBranchProtections(ctx, pr, true); err != nil {
+		// Check all status checks and reviews are ok, the status checks of a merge group are required for the merge group commit
+		if ctx.opts.PushTrigger == repo_module.PushTriggerMergeQueue {
+			err = pull_service.CheckMergeGroupBranchProtections(ctx, pr, newCommitID)
+		} else {
+			err = pull_service.CheckPullBranchProtections(ctx, pr, true)
+		}
+		if err != nil {
 			if models.IsErrDisallowedToMerge(err) {
 				log.Warn("Forbidden: User %d is not allowed push to protected branch %s in %-v and pr #%d is not ready to be merged: %s", ctx.opts.UserID, branchName, repo, pr.Index, err.Error())
 				ctx.JSON(http.StatusForbidden, private.Response{
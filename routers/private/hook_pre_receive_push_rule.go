@@ -0,0 +1,188 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	git_model "code.gitea.io/gitea/models/git"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+)
+
+// preReceivePushRules checks the commits added by the push against the push rules of the repository and its owner.
+// The push is rejected with a message naming the first commit which violates a rule.
+func preReceivePushRules(ctx *preReceiveContext, newCommitID string, refFullName git.RefName) {
+	// the commits of merged pull requests have been checked when they were pushed, and the wiki is not a code repository
+	if ctx.opts.PullRequestID > 0 || ctx.opts.IsWiki {
+		return
+	}
+	if !refFullName.IsBranch() && !refFullName.IsTag() && !refFullName.IsFor() {
+		return
+	}
+	if newCommitID == ctx.Repo.GetObjectFormat().EmptyObjectID().String() {
+		return
+	}
+
+	repo := ctx.Repo.Repository
+
+	if !ctx.gotPushRules {
+		var err error
+		ctx.pushRules, err = git_model.GetRepoPushRules(ctx, repo)
+		if err != nil {
+			log.Error("Unable to get push rules for %-v Error: %v", repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		ctx.gotPushRules = true
+	}
+	if len(ctx.pushRules) == 0 {
+		return
+	}
+
+	reader, err := git.NewPushReader(ctx, ctx.Repo.GitRepo, ctx.env)
+	if err != nil {
+		log.Error("Unable to read the pushed objects of %-v: %v", repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to read the pushed objects: %v", err),
+		})
+		return
+	}
+	defer reader.Close()
+
+	commitIDs, err := reader.CommitIDs(ctx, newCommitID)
+	if err != nil {
+		log.Error("Unable to get the pushed commits of %s in %-v: %v", refFullName, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to get the pushed commits of %s: %v", refFullName, err),
+		})
+		return
+	}
+
+	for _, commitID := range commitIDs {
+		for _, rule := range ctx.pushRules {
+			reason, err := pushRuleCommitViolation(ctx, reader, rule, commitID)
+			if err != nil {
+				log.Error("Unable to check push rule %d for commit %s in %-v: %v", rule.ID, commitID, repo, err)
+				ctx.JSON(http.StatusInternalServerError, private.Response{
+					Err: fmt.Sprintf("Unable to check push rules for commit %s: %v", commitID, err),
+				})
+				return
+			}
+			if reason == "" {
+				continue
+			}
+
+			scope := "repository"
+			if rule.IsOrgRule() {
+				scope = "organization"
+			}
+			log.Warn("Forbidden: commit %s pushed to %s in %-v by user %d violates the %s push rule: %s", commitID, refFullName, repo, ctx.opts.UserID, scope, reason)
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("commit %s violates the %s push rule: %s", commitID, scope, reason),
+			})
+			return
+		}
+	}
+}
+
+// pushRuleCommitViolation returns the reason why the commit violates the push rule or an empty string if the commit satisfies it
+func pushRuleCommitViolation(ctx *preReceiveContext, reader *git.PushReader, rule *git_model.PushRule, commitID string) (string, error) {
+	if re := rule.CommitMessageRegexp(); re != nil || rule.RequireVerifiedAuthorEmail || rule.RequireVerifiedCommitterEmail {
+		commit, err := reader.Commit(commitID)
+		if err != nil {
+			return "", err
+		}
+
+		if re != nil && !re.MatchString(commit.CommitMessage) {
+			return fmt.Sprintf("the commit message does not match the pattern %q", rule.CommitMessagePattern), nil
+		}
+		if rule.RequireVerifiedAuthorEmail {
+			if verified, err := ctx.isVerifiedEmail(commit.Author.Email); err != nil {
+				return "", err
+			} else if !verified {
+				return fmt.Sprintf("the author email %s is not a verified email of a user", commit.Author.Email), nil
+			}
+		}
+		if rule.RequireVerifiedCommitterEmail {
+			if verified, err := ctx.isVerifiedEmail(commit.Committer.Email); err != nil {
+				return "", err
+			} else if !verified {
+				return fmt.Sprintf("the committer email %s is not a verified email of a user", commit.Committer.Email), nil
+			}
+		}
+	}
+
+	globs := rule.GetForbiddenFilePatterns()
+	if rule.MaxFileSize <= 0 && len(globs) == 0 {
+		return "", nil
+	}
+
+	files, err := reader.ChangedFiles(ctx, commitID)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if rule.MaxFileSize > 0 && file.Size > rule.MaxFileSize {
+			return fmt.Sprintf("the file %s (%s) exceeds the maximum file size of %s", file.Path, base.FileSize(file.Size), base.FileSize(rule.MaxFileSize)), nil
+		}
+		lpath := strings.ToLower(file.Path)
+		for _, g := range globs {
+			if g.Match(lpath) {
+				return fmt.Sprintf("the file %s is forbidden", file.Path), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// isVerifiedEmail returns true if the email is an activated email address of an active user who is not prohibited from logging in,
+// the no-reply addresses of the users don't count. The results are cached for the push.
+func (ctx *preReceiveContext) isVerifiedEmail(email string) (bool, error) {
+	email = strings.ToLower(email)
+	if verified, ok := ctx.verifiedEmails[email]; ok {
+		return verified, nil
+	}
+
+	verified, err := isActivatedEmailOfActiveUser(ctx, email)
+	if err != nil {
+		return false, err
+	}
+
+	if ctx.verifiedEmails == nil {
+		ctx.verifiedEmails = make(map[string]bool)
+	}
+	ctx.verifiedEmails[email] = verified
+	return verified, nil
+}
+
+func isActivatedEmailOfActiveUser(ctx context.Context, email string) (bool, error) {
+	emailAddress, err := user_model.GetEmailAddressByEmail(ctx, email)
+	if err != nil {
+		if user_model.IsErrEmailAddressNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !emailAddress.IsActivated {
+		return false, nil
+	}
+
+	u, err := user_model.GetUserByID(ctx, emailAddress.UID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return u.IsActive && !u.ProhibitLogin, nil
+}
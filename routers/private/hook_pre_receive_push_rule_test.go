@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsActivatedEmailOfActiveUser(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	for email, expected := range map[string]bool{
+		"user2@example.com":         true,
+		"USER2@example.com":         true,
+		"user2-2@example.com":       false, // not activated
+		"user2@noreply.example.org": false, // no-reply address
+		"org3@example.com":          false, // inactive user
+		"user37@example.com":        false, // prohibited user
+		"unknown@example.com":       false,
+	} {
+		verified, err := isActivatedEmailOfActiveUser(db.DefaultContext, email)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, verified, email)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// rulesetRefUpdate holds the information about a ref update needed to evaluate the rules of rulesets
+type rulesetRefUpdate struct {
+	refFullName  git.RefName
+	oldCommitID  string
+	newCommitID  string
+	isCreation   bool
+	isDeletion   bool
+	isForcePush  bool
+	checkedForce bool
+
+	pullViolations []*git_model.RulesetViolation // nil until the pull request rules are evaluated
+}
+
+// preReceiveRulesets evaluates the rulesets of the repository owner and the instance which apply to the branch or tag.
+// The push is rejected if an actively enforced ruleset is violated, otherwise the violations of rulesets in evaluate mode are recorded.
+func preReceiveRulesets(ctx *preReceiveContext, oldCommitID, newCommitID string, refFullName git.RefName) {
+	var target git_model.RulesetTarget
+	switch {
+	case refFullName.IsBranch():
+		target = git_model.RulesetTargetBranch
+	case refFullName.IsTag():
+		target = git_model.RulesetTargetTag
+	default:
+		return
+	}
+
+	repo := ctx.Repo.Repository
+
+	if ctx.rulesets == nil {
+		ctx.rulesets = make(map[git_model.RulesetTarget][]*git_model.Ruleset, 2)
+	}
+	rulesets, has := ctx.rulesets[target]
+	if !has {
+		var err error
+		rulesets, err = git_model.GetRepoRulesets(ctx, repo, target)
+		if err != nil {
+			log.Error("Unable to get rulesets for %-v Error: %v", repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		ctx.rulesets[target] = rulesets
+	}
+	if len(rulesets) == 0 {
+		return
+	}
+
+	if !ctx.loadPusherAndPermission() {
+		return
+	}
+
+	emptyObjectID := ctx.Repo.GetObjectFormat().EmptyObjectID().String()
+	update := &rulesetRefUpdate{
+		refFullName: refFullName,
+		oldCommitID: oldCommitID,
+		newCommitID: newCommitID,
+		isCreation:  oldCommitID == emptyObjectID,
+		isDeletion:  newCommitID == emptyObjectID,
+	}
+
+	violations := make([]*git_model.RulesetViolation, 0, len(rulesets))
+	for _, rs := range rulesets {
+		if !rs.MatchRef(refFullName) {
+			continue
+		}
+
+		// deploy keys act as the owner of the repository which must not bypass the rulesets
+		if ctx.opts.DeployKeyID == 0 {
+			canBypass, err := rs.CanBypass(ctx, ctx.user.ID)
+			if err != nil {
+				log.Error("Unable to check if %-v can bypass ruleset %d: %v", ctx.user, rs.ID, err)
+				ctx.JSON(http.StatusInternalServerError, private.Response{
+					Err: err.Error(),
+				})
+				return
+			}
+			if canBypass {
+				continue
+			}
+		}
+
+		violation, err := checkRulesetRefUpdate(ctx, rs, update)
+		if err != nil {
+			log.Error("Unable to evaluate ruleset %d for %s in %-v: %v", rs.ID, refFullName, repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Unable to evaluate ruleset %q: %v", rs.Name, err),
+			})
+			return
+		}
+		if violation == nil {
+			continue
+		}
+
+		if rs.IsActive() {
+			log.Warn("Forbidden: %s in %-v by user %d", violation.Error(), repo, ctx.opts.UserID)
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: violation.Error(),
+			})
+			return
+		}
+		violations = append(violations, violation)
+	}
+
+	for _, violation := range violations {
+		log.Info("Allowed by evaluate mode: %s in %-v by user %d", violation.Error(), repo, ctx.opts.UserID)
+		if err := git_model.InsertRulesetViolation(ctx, violation); err != nil {
+			log.Error("Unable to record violation of ruleset %d: %v", violation.RulesetID, err)
+		}
+	}
+}
+
+// checkRulesetRefUpdate returns the violation of the ruleset by the ref update or nil if the update satisfies all rules
+func checkRulesetRefUpdate(ctx *preReceiveContext, rs *git_model.Ruleset, update *rulesetRefUpdate) (*git_model.RulesetViolation, error) {
+	reason, err := rulesetRefUpdateViolation(ctx, rs, update)
+	if err != nil || reason == "" {
+		return nil, err
+	}
+	return &git_model.RulesetViolation{
+		RulesetID: rs.ID,
+		Ruleset:   rs,
+		RepoID:    ctx.Repo.Repository.ID,
+		RefName:   update.refFullName,
+		DoerID:    ctx.opts.UserID,
+		Reason:    reason,
+	}, nil
+}
+
+func rulesetRefUpdateViolation(ctx *preReceiveContext, rs *git_model.Ruleset, update *rulesetRefUpdate) (string, error) {
+	if update.isDeletion {
+		if rs.RestrictDeletion {
+			return "deletion is restricted", nil
+		}
+		return "", nil
+	}
+
+	if update.isCreation && rs.RestrictCreation {
+		return "creation is restricted", nil
+	}
+
+	if rs.Target == git_model.RulesetTargetTag {
+		if !update.isCreation && rs.RestrictUpdate {
+			return "updating is restricted", nil
+		}
+		return "", nil
+	}
+
+	isPullRequestMerge := ctx.opts.PullRequestID > 0
+
+	if !update.isCreation && rs.BlockForcePush {
+		isForcePush, err := update.detectForcePush(ctx)
+		if err != nil {
+			return "", err
+		}
+		if isForcePush {
+			return "force push is blocked", nil
+		}
+	}
+
+	if !update.isCreation && rs.RestrictUpdate && !isPullRequestMerge {
+		return "changes must be made through a pull request", nil
+	}
+
+	if rs.RequireSignedCommits {
+		if err := verifyCommits(update.oldCommitID, update.newCommitID, ctx.Repo.GitRepo, ctx.env); err != nil {
+			if !isErrUnverifiedCommit(err) {
+				return "", err
+			}
+			return fmt.Sprintf("commit %s is not signed by a verified key", err.(*errUnverifiedCommit).sha), nil
+		}
+	}
+
+	if globs := rs.GetProtectedFilePatterns(); len(globs) > 0 && !update.isCreation {
+		_, err := pull_service.CheckFileProtection(ctx.Repo.GitRepo, update.refFullName.BranchName(), update.oldCommitID, update.newCommitID, globs, 1, ctx.env)
+		if err != nil {
+			if !models.IsErrFilePathProtected(err) {
+				return "", err
+			}
+			return fmt.Sprintf("changing file %s is restricted", err.(models.ErrFilePathProtected).Path), nil
+		}
+	}
+
+	if isPullRequestMerge {
+		if update.pullViolations == nil {
+			pr, err := issues_model.GetPullRequestByID(ctx, ctx.opts.PullRequestID)
+			if err != nil {
+				return "", err
+			}
+			if update.pullViolations, err = pull_service.EvaluatePullRulesets(ctx, pr, ctx.user); err != nil {
+				return "", err
+			}
+		}
+		for _, v := range update.pullViolations {
+			if v.RulesetID == rs.ID {
+				return v.Reason, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// detectForcePush returns true if the old commit is not an ancestor of the new commit
+func (update *rulesetRefUpdate) detectForcePush(ctx *preReceiveContext) (bool, error) {
+	if !update.checkedForce {
+		output, _, err := git.NewCommand(ctx, "rev-list", "--max-count=1").AddDynamicArguments(update.oldCommitID, "^"+update.newCommitID).RunStdString(&git.RunOpts{Dir: ctx.Repo.Repository.RepoPath(), Env: ctx.env})
+		if err != nil {
+			return false, err
+		}
+		update.isForcePush = len(output) > 0
+		update.checkedForce = true
+	}
+	return update.isForcePush, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+	secretscanning_service "code.gitea.io/gitea/services/secretscanning"
+)
+
+// maxReportedSecrets is the number of secrets listed in the message of a rejected push
+const maxReportedSecrets = 10
+
+// preReceiveSecretScanning scans the files added by the push for secrets.
+// The push is rejected if secrets are found unless the pusher bypasses the push protection with a reason,
+// the bypassed secrets are recorded as alerts when all refs have been checked.
+func preReceiveSecretScanning(ctx *preReceiveContext, newCommitID string, refFullName git.RefName) {
+	if !setting.SecretScanning.Enabled {
+		return
+	}
+	// the commits of merged pull requests have been scanned when they were pushed, and the wiki is not a code repository
+	if ctx.opts.PullRequestID > 0 || ctx.opts.IsWiki {
+		return
+	}
+	if !refFullName.IsBranch() && !refFullName.IsTag() && !refFullName.IsFor() {
+		return
+	}
+	if newCommitID == ctx.Repo.GetObjectFormat().EmptyObjectID().String() {
+		return
+	}
+
+	repo := ctx.Repo.Repository
+
+	reader, err := git.NewPushReader(ctx, ctx.Repo.GitRepo, ctx.env)
+	if err != nil {
+		log.Error("Unable to read the pushed objects of %-v: %v", repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to read the pushed objects: %v", err),
+		})
+		return
+	}
+	defer reader.Close()
+
+	commitIDs, err := reader.CommitIDs(ctx, newCommitID)
+	if err != nil {
+		log.Error("Unable to get the pushed commits of %s in %-v: %v", refFullName, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to get the pushed commits of %s: %v", refFullName, err),
+		})
+		return
+	}
+
+	if ctx.scannedBlobs == nil {
+		ctx.scannedBlobs = make(container.Set[string])
+	}
+	leaks, err := secretscanning_service.ScanCommits(ctx, reader, commitIDs, ctx.scannedBlobs)
+	if err != nil {
+		log.Error("Unable to scan the pushed commits of %s in %-v for secrets: %v", refFullName, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to scan the pushed commits of %s for secrets: %v", refFullName, err),
+		})
+		return
+	}
+	if len(leaks) == 0 {
+		return
+	}
+
+	if strings.TrimSpace(ctx.opts.GitPushOptions[private.GitPushOptionSecretScanningBypassReason]) != "" {
+		ctx.bypassedLeaks = append(ctx.bypassedLeaks, leaks...)
+		return
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "push protection: %d secrets found in %s\n", len(leaks), refFullName.ShortName())
+	for i, leak := range leaks {
+		if i == maxReportedSecrets {
+			fmt.Fprintf(&msg, "  ... and %d more\n", len(leaks)-maxReportedSecrets)
+			break
+		}
+		fmt.Fprintf(&msg, "  %s\n", leak)
+	}
+	fmt.Fprintf(&msg, "Remove the secrets from the commits, or bypass the push protection with: git push -o %s=\"<reason>\"", private.GitPushOptionSecretScanningBypassReason)
+
+	log.Warn("Forbidden: %d secrets found in the commits pushed to %s in %-v by user %d", len(leaks), refFullName, repo, ctx.opts.UserID)
+	ctx.JSON(http.StatusForbidden, private.Response{
+		UserMsg: msg.String(),
+	})
+}
+
+// recordBypassedSecrets records the secrets pushed by bypassing the push protection as alerts of the repository
+func recordBypassedSecrets(ctx *preReceiveContext) {
+	if len(ctx.bypassedLeaks) == 0 {
+		return
+	}
+	if !ctx.loadPusherAndPermission() {
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	reason := strings.TrimSpace(ctx.opts.GitPushOptions[private.GitPushOptionSecretScanningBypassReason])
+	log.Warn("User %d bypassed the push protection for %d secrets pushed to %-v: %s", ctx.opts.UserID, len(ctx.bypassedLeaks), repo, reason)
+	if err := secretscanning_service.CreateAlerts(ctx, repo, ctx.bypassedLeaks, ctx.user, reason); err != nil {
+		log.Error("Unable to record the secret scanning alerts of %-v: %v", repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to record the secret scanning alerts: %v", err),
+		})
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/base"
+	shared_audit "code.gitea.io/gitea/routers/web/shared/audit"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplAudit base.TplName = "admin/audit"
+
+// AuditEvents shows the audit events of the instance
+func AuditEvents(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("audit.events")
+	ctx.Data["PageIsAdminAudit"] = true
+
+	shared_audit.Events(ctx, 0)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplAudit)
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/base"
+	shared_audit "code.gitea.io/gitea/routers/web/shared/audit"
+	"code.gitea.io/gitea/services/context"
+)
+
+const tplSettingsAudit base.TplName = "org/settings/audit"
+
+// AuditEvents shows the audit events of the organization
+func AuditEvents(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("audit.events")
+	ctx.Data["PageIsOrgSettings"] = true
+	ctx.Data["PageIsSettingsAudit"] = true
+
+	shared_audit.Events(ctx, ctx.Org.Organization.ID)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.HTML(http.StatusOK, tplSettingsAudit)
+}
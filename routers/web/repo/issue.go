@@ -1939,11 +1939,31 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["IsBlockedByOutdatedBranch"] = issues_model.MergeBlockedByOutdatedBranch(pb, pull)
 			ctx.Data["GrantedApprovals"] = issues_model.GetGrantedApprovalsCount(ctx, pb, pull)
 			ctx.Data["RequireSigned"] = pb.RequireSignedCommits
+			ctx.Data["RequireLinearHistory"] = pb.RequireLinearHistory
 			ctx.Data["ChangedProtectedFiles"] = pull.ChangedProtectedFiles
 			ctx.Data["IsBlockedByChangedProtectedFiles"] = len(pull.ChangedProtectedFiles) != 0
 			ctx.Data["ChangedProtectedFilesNum"] = len(pull.ChangedProtectedFiles)
 			ctx.Data["RequireApprovalsWhitelist"] = pb.EnableApprovalsWhitelist
+			if pb.RequireCodeOwnerApproval {
+				codeOwnersApprovals, err := pull_service.GetCodeOwnersApprovals(ctx, pull)
+				if err != nil {
+					ctx.ServerError("GetCodeOwnersApprovals", err)
+					return
+				}
+				ctx.Data["CodeOwnersApprovals"] = codeOwnersApprovals
+				ctx.Data["IsBlockedByCodeOwners"] = !codeOwnersApprovals.IsApproved()
+			}
+		}
+
+		rulesetViolations, err := pull_service.EvaluatePullRulesets(ctx, pull, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("EvaluatePullRulesets", err)
+			return
 		}
+		ctx.Data["RulesetViolations"] = rulesetViolations
+		ctx.Data["IsBlockedByRulesets"] = slices.ContainsFunc(rulesetViolations, func(v *git_model.RulesetViolation) bool {
+			return v.Ruleset.IsActive()
+		})
 		ctx.Data["WillSign"] = false
 		if ctx.Doer != nil {
 			sign, key, _, err := asymkey_service.SignMerge(ctx, pull, ctx.Doer, pull.BaseRepo.RepoPath(), pull.BaseBranch, pull.GetGitRefName())
@@ -1999,6 +2019,23 @@ func ViewIssue(ctx *context.Context) {
 			ctx.ServerError("GetScheduledMergeByPullID", err)
 			return
 		}
+
+		// Check if the pr is waiting in the merge queue
+		if pb != nil && pb.EnableMergeQueue {
+			queueEntries, err := pull_model.GetMergeQueueEntries(ctx, pull.BaseRepoID, pull.BaseBranch)
+			if err != nil {
+				ctx.ServerError("GetMergeQueueEntries", err)
+				return
+			}
+			for i, entry := range queueEntries {
+				if entry.PullID == pull.ID {
+					ctx.Data["MergeQueueEntry"] = entry
+					ctx.Data["MergeQueuePosition"] = i + 1
+					ctx.Data["MergeQueueLength"] = len(queueEntries)
+					break
+				}
+			}
+		}
 	}
 
 	// Get Dependencies
@@ -41,6 +41,7 @@ import (
 	"code.gitea.io/gitea/services/context/upload"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/gitdiff"
+	"code.gitea.io/gitea/services/mergequeue"
 	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
@@ -1029,6 +1030,17 @@ func MergePullRequest(ctx *context.Context) {
 		mergeCheckType = pull_service.MergeCheckTypeManually
 	}
 
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		ctx.ServerError("GetFirstMatchProtectedBranchRule", err)
+		return
+	}
+	// pull requests are merged through the merge queue unless an admin forces the merge
+	useMergeQueue := pb != nil && pb.EnableMergeQueue && !manuallyMerged && !form.ForceMerge
+	if useMergeQueue {
+		mergeCheckType = pull_service.MergeCheckTypeQueue
+	}
+
 	// start with merging by checking
 	if err := pull_service.CheckPullMergeable(ctx, ctx.Doer, &ctx.Repo.Permission, pr, mergeCheckType, form.ForceMerge); err != nil {
 		switch {
@@ -1093,6 +1105,23 @@ func MergePullRequest(ctx *context.Context) {
 		message += "\n\n" + form.MergeMessageField
 	}
 
+	if useMergeQueue {
+		if err := mergequeue.AddToMergeQueue(ctx, ctx.Doer, pr, repo_model.MergeStyle(form.Do), message); err != nil {
+			switch {
+			case pull_model.IsErrAlreadyInMergeQueue(err):
+				ctx.JSONError(ctx.Tr("repo.pulls.merge_queue_already_queued"))
+			case models.IsErrInvalidMergeStyle(err):
+				ctx.JSONError(ctx.Tr("repo.pulls.invalid_merge_option"))
+			default:
+				ctx.ServerError("AddToMergeQueue", err)
+			}
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue_added"))
+		ctx.JSONRedirect(issue.Link())
+		return
+	}
+
 	if form.MergeWhenChecksSucceed {
 		// delete all scheduled auto merges
 		_ = pull_model.DeleteScheduledAutoMerge(ctx, pr.ID)
@@ -1232,6 +1261,35 @@ func CancelAutoMergePullRequest(ctx *context.Context) {
 	ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index))
 }
 
+// RemoveFromMergeQueuePullRequest removes the pull request from the merge queue of its base branch,
+// only the users who are allowed to merge it can remove it
+func RemoveFromMergeQueuePullRequest(ctx *context.Context) {
+	issue, ok := getPullInfo(ctx)
+	if !ok {
+		return
+	}
+
+	if allowed, err := pull_service.IsUserAllowedToMerge(ctx, issue.PullRequest, ctx.Repo.Permission, ctx.Doer); err != nil {
+		ctx.ServerError("IsUserAllowedToMerge", err)
+		return
+	} else if !allowed {
+		ctx.Error(http.StatusForbidden)
+		return
+	}
+
+	if err := mergequeue.RemoveFromMergeQueue(ctx, ctx.Doer, issue.PullRequest, ""); err != nil {
+		if db.IsErrNotExist(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.merge_queue_not_queued"))
+			ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index))
+			return
+		}
+		ctx.ServerError("RemoveFromMergeQueue", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue_removed"))
+	ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index))
+}
+
 func stopTimerIfAvailable(ctx *context.Context, user *user_model.User, issue *issues_model.Issue) error {
 	if issues_model.StopwatchExists(ctx, user.ID, issue.ID) {
 		if err := issues_model.CreateOrStopIssueStopwatch(ctx, user, issue); err != nil {
@@ -0,0 +1,313 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	secret_model "code.gitea.io/gitea/models/secret"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+	secret_service "code.gitea.io/gitea/services/secrets"
+)
+
+const tplRepoEnvironments base.TplName = "repo/settings/actions"
+
+// Environments renders the deployment environments of the repository
+func Environments(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("actions.environments")
+	ctx.Data["PageType"] = "environments"
+	ctx.Data["PageIsSharedSettingsEnvironments"] = true
+
+	envs, err := db.Find[actions_model.ActionEnvironment](ctx, actions_model.FindEnvironmentsOptions{RepoID: ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.ServerError("FindEnvironments", err)
+		return
+	}
+	ctx.Data["Environments"] = envs
+
+	ctx.HTML(http.StatusOK, tplRepoEnvironments)
+}
+
+// EnvironmentCreate creates a deployment environment without protection rules
+func EnvironmentCreate(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.EditEnvironmentForm)
+
+	env, err := actions_service.CreateEnvironment(ctx, ctx.Repo.Repository, form.Name, nil, nil)
+	if err != nil {
+		log.Error("CreateEnvironment: %v", err)
+		ctx.JSONError(ctx.Tr("actions.environments.creation.failed"))
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.environments.creation.success", env.Name))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
+
+func environmentLink(ctx *context.Context, env *actions_model.ActionEnvironment) string {
+	return ctx.Repo.RepoLink + "/settings/actions/environments/" + url.PathEscape(env.Name)
+}
+
+func getEnvironment(ctx *context.Context) *actions_model.ActionEnvironment {
+	env, err := actions_model.GetEnvironmentByName(ctx, ctx.Repo.Repository.ID, ctx.PathParam(":environment"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound("GetEnvironmentByName", err)
+		} else {
+			ctx.ServerError("GetEnvironmentByName", err)
+		}
+		return nil
+	}
+	return env
+}
+
+// EnvironmentEdit renders the protection rules, secrets, variables and deployment history of an environment
+func EnvironmentEdit(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.Data["Title"] = ctx.Tr("actions.environments.edit")
+	ctx.Data["PageType"] = "environment"
+	ctx.Data["PageIsSharedSettingsEnvironments"] = true
+	ctx.Data["Environment"] = env
+
+	reviewers, err := user_model.GetUsersByIDs(ctx, env.ReviewerIDs)
+	if err != nil {
+		ctx.ServerError("GetUsersByIDs", err)
+		return
+	}
+	reviewerNames := make([]string, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		reviewerNames = append(reviewerNames, reviewer.Name)
+	}
+	ctx.Data["ReviewerNames"] = strings.Join(reviewerNames, ",")
+
+	secrets, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{RepoID: env.RepoID, EnvironmentID: env.ID})
+	if err != nil {
+		ctx.ServerError("FindSecrets", err)
+		return
+	}
+	ctx.Data["Secrets"] = secrets
+
+	variables, err := db.Find[actions_model.ActionVariable](ctx, actions_model.FindVariablesOpts{RepoID: env.RepoID, EnvironmentID: env.ID})
+	if err != nil {
+		ctx.ServerError("FindVariables", err)
+		return
+	}
+	ctx.Data["Variables"] = variables
+
+	deployments, err := db.Find[actions_model.ActionDeployment](ctx, actions_model.FindDeploymentsOptions{
+		ListOptions:    db.ListOptions{PageSize: 50},
+		RepoID:         env.RepoID,
+		EnvironmentIDs: []int64{env.ID},
+	})
+	if err != nil {
+		ctx.ServerError("FindDeployments", err)
+		return
+	}
+	for _, d := range deployments {
+		d.Environment = env
+		if err := d.LoadAttributes(ctx); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+		if err := d.Job.LoadRun(ctx); err != nil {
+			ctx.ServerError("LoadRun", err)
+			return
+		}
+	}
+	ctx.Data["Deployments"] = deployments
+
+	ctx.HTML(http.StatusOK, tplRepoEnvironments)
+}
+
+// EnvironmentEditPost updates the protection rules of an environment
+func EnvironmentEditPost(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*forms.EditEnvironmentForm)
+	link := environmentLink(ctx, env)
+
+	var branches []string
+	for _, line := range strings.Split(form.DeploymentBranches, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			branches = append(branches, line)
+		}
+	}
+	var reviewerIDs []int64
+	for _, name := range strings.Split(form.Reviewers, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		reviewer, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Flash.Error(ctx.Tr("form.user_not_exist"))
+				ctx.Redirect(link)
+			} else {
+				ctx.ServerError("GetUserByName", err)
+			}
+			return
+		}
+		reviewerIDs = append(reviewerIDs, reviewer.ID)
+	}
+
+	if err := actions_service.UpdateEnvironment(ctx, ctx.Repo.Repository, env, branches, reviewerIDs); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Flash.Error(ctx.Tr("actions.environments.update.failed", err.Error()))
+			ctx.Redirect(link)
+		} else {
+			ctx.ServerError("UpdateEnvironment", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.environments.update.success"))
+	ctx.Redirect(link)
+}
+
+// EnvironmentDelete deletes an environment with its secrets, variables and deployment history
+func EnvironmentDelete(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteEnvironment(ctx, env); err != nil {
+		log.Error("DeleteEnvironment(%d): %v", env.ID, err)
+		ctx.JSONError(ctx.Tr("actions.environments.deletion.failed"))
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.environments.deletion.success"))
+	ctx.JSONRedirect(ctx.Repo.RepoLink + "/settings/actions/environments")
+}
+
+// EnvironmentSecretsPost creates or updates a secret of an environment
+func EnvironmentSecretsPost(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*forms.AddSecretForm)
+
+	s, _, err := secret_service.CreateOrUpdateEnvironmentSecret(ctx, ctx.Doer, env.RepoID, env.ID, form.Name, util.ReserveLineBreakForTextarea(form.Data))
+	if err != nil {
+		log.Error("CreateOrUpdateEnvironmentSecret failed: %v", err)
+		ctx.JSONError(ctx.Tr("secrets.creation.failed"))
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("secrets.creation.success", s.Name))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
+
+// EnvironmentSecretsDelete deletes a secret of an environment
+func EnvironmentSecretsDelete(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	id := ctx.FormInt64("id")
+
+	if err := secret_service.DeleteEnvironmentSecretByID(ctx, ctx.Doer, env.RepoID, env.ID, id); err != nil {
+		log.Error("DeleteEnvironmentSecretByID(%d) failed: %v", id, err)
+		ctx.JSONError(ctx.Tr("secrets.deletion.failed"))
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("secrets.deletion.success"))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
+
+// EnvironmentVariableCreate creates a variable of an environment
+func EnvironmentVariableCreate(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*forms.EditVariableForm)
+
+	v, err := actions_service.CreateEnvironmentVariable(ctx, env.RepoID, env.ID, form.Name, form.Data)
+	if err != nil {
+		log.Error("CreateEnvironmentVariable: %v", err)
+		ctx.JSONError(ctx.Tr("actions.variables.creation.failed"))
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("actions.variables.creation.success", v.Name))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
+
+// getEnvironmentVariable returns the variable of the environment by the id in the path
+func getEnvironmentVariable(ctx *context.Context, env *actions_model.ActionEnvironment) *actions_model.ActionVariable {
+	id := ctx.PathParamInt64(":variable_id")
+	variables, err := db.Find[actions_model.ActionVariable](ctx, actions_model.FindVariablesOpts{RepoID: env.RepoID, EnvironmentID: env.ID})
+	if err != nil {
+		ctx.ServerError("FindVariables", err)
+		return nil
+	}
+	for _, v := range variables {
+		if v.ID == id {
+			return v
+		}
+	}
+	ctx.JSONError(ctx.Tr("actions.variables.id_not_exist", id))
+	return nil
+}
+
+// EnvironmentVariableUpdate updates a variable of an environment
+func EnvironmentVariableUpdate(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	v := getEnvironmentVariable(ctx, env)
+	if ctx.Written() {
+		return
+	}
+	form := web.GetForm(ctx).(*forms.EditVariableForm)
+
+	if ok, err := actions_service.UpdateVariable(ctx, v.ID, form.Name, form.Data); err != nil || !ok {
+		log.Error("UpdateVariable: %v", err)
+		ctx.JSONError(ctx.Tr("actions.variables.update.failed"))
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("actions.variables.update.success"))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
+
+// EnvironmentVariableDelete deletes a variable of an environment
+func EnvironmentVariableDelete(ctx *context.Context) {
+	env := getEnvironment(ctx)
+	if ctx.Written() {
+		return
+	}
+	v := getEnvironmentVariable(ctx, env)
+	if ctx.Written() {
+		return
+	}
+
+	if err := actions_service.DeleteVariableByID(ctx, v.ID); err != nil {
+		log.Error("Delete variable [%d] failed: %v", v.ID, err)
+		ctx.JSONError(ctx.Tr("actions.variables.deletion.failed"))
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("actions.variables.deletion.success"))
+	ctx.JSONRedirect(environmentLink(ctx, env))
+}
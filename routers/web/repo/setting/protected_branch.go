@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	audit_model "code.gitea.io/gitea/models/audit"
 	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
@@ -17,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/web/repo"
+	audit_service "code.gitea.io/gitea/services/audit"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 	pull_service "code.gitea.io/gitea/services/pull"
@@ -256,6 +258,14 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 	protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 	protectBranch.UnprotectedFilePatterns = f.UnprotectedFilePatterns
 	protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+	protectBranch.RequireLinearHistory = f.RequireLinearHistory
+	protectBranch.EnableMergeQueue = f.EnableMergeQueue
+	protectBranch.RequireCodeOwnerApproval = f.RequireCodeOwnerApproval
+
+	auditAction := audit_model.ActionProtectedBranchUpdate
+	if protectBranch.ID == 0 {
+		auditAction = audit_model.ActionProtectedBranchCreate
+	}
 
 	err = git_model.UpdateProtectBranch(ctx, ctx.Repo.Repository, protectBranch, git_model.WhitelistOptions{
 		UserIDs:          whitelistUsers,
@@ -271,6 +281,7 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 		ctx.ServerError("UpdateProtectBranch", err)
 		return
 	}
+	audit_service.RecordRepo(ctx, ctx.Doer, auditAction, ctx.Repo.Repository, audit_service.ProtectedBranchTarget(protectBranch), "")
 
 	// FIXME: since we only need to recheck files protected rules, we could improve this
 	matchedBranches, err := git_model.FindAllMatchedBranches(ctx, ctx.Repo.Repository.ID, protectBranch.RuleName)
@@ -316,6 +327,7 @@ func DeleteProtectedBranchRulePost(ctx *context.Context) {
 		ctx.JSONRedirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
 		return
 	}
+	audit_service.RecordRepo(ctx, ctx.Doer, audit_model.ActionProtectedBranchDelete, ctx.Repo.Repository, audit_service.ProtectedBranchTarget(rule), "")
 
 	ctx.Flash.Success(ctx.Tr("repo.settings.remove_protected_branch_success", rule.RuleName))
 	ctx.JSONRedirect(fmt.Sprintf("%s/settings/branches", ctx.Repo.RepoLink))
@@ -0,0 +1,138 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/context"
+	"code.gitea.io/gitea/services/forms"
+	secretscanning_service "code.gitea.io/gitea/services/secretscanning"
+)
+
+const tplSecretScanning base.TplName = "repo/settings/secret_scanning"
+
+// SecretScanningAlerts renders the secret scanning alerts of the repository
+func SecretScanningAlerts(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.secret_scanning")
+	ctx.Data["PageIsSettingsSecretScanning"] = true
+
+	state := git_model.SecretScanningAlertState(ctx.FormString("state"))
+	if !state.IsValid() {
+		state = git_model.SecretScanningAlertStateOpen
+	}
+	ctx.Data["State"] = state
+
+	openCount, err := db.Count[git_model.SecretScanningAlert](ctx, git_model.FindSecretScanningAlertsOptions{
+		RepoID: ctx.Repo.Repository.ID,
+		State:  git_model.SecretScanningAlertStateOpen,
+	})
+	if err != nil {
+		ctx.ServerError("CountSecretScanningAlerts", err)
+		return
+	}
+	resolvedCount, err := db.Count[git_model.SecretScanningAlert](ctx, git_model.FindSecretScanningAlertsOptions{
+		RepoID: ctx.Repo.Repository.ID,
+		State:  git_model.SecretScanningAlertStateResolved,
+	})
+	if err != nil {
+		ctx.ServerError("CountSecretScanningAlerts", err)
+		return
+	}
+	ctx.Data["OpenCount"] = openCount
+	ctx.Data["ResolvedCount"] = resolvedCount
+
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+	alerts, count, err := db.FindAndCount[git_model.SecretScanningAlert](ctx, git_model.FindSecretScanningAlertsOptions{
+		ListOptions: db.ListOptions{Page: page, PageSize: setting.UI.ExplorePagingNum},
+		RepoID:      ctx.Repo.Repository.ID,
+		State:       state,
+	})
+	if err != nil {
+		ctx.ServerError("FindSecretScanningAlerts", err)
+		return
+	}
+	for _, alert := range alerts {
+		if err := alert.LoadAttributes(ctx); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+	}
+	ctx.Data["Alerts"] = alerts
+	ctx.Data["Resolutions"] = git_model.SecretScanningResolutions()
+
+	pager := context.NewPagination(int(count), setting.UI.ExplorePagingNum, page, 5)
+	pager.AddParamString("state", string(state))
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplSecretScanning)
+}
+
+// getSecretScanningAlert gets the alert by the "id" path parameter, any error will be written to the ctx
+func getSecretScanningAlert(ctx *context.Context) *git_model.SecretScanningAlert {
+	alert, err := git_model.GetSecretScanningAlertByID(ctx, ctx.Repo.Repository.ID, ctx.PathParamInt64("id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound("GetSecretScanningAlertByID", err)
+		} else {
+			ctx.ServerError("GetSecretScanningAlertByID", err)
+		}
+		return nil
+	}
+	return alert
+}
+
+// ResolveSecretScanningAlertPost resolves a secret scanning alert
+func ResolveSecretScanningAlertPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ResolveSecretScanningAlertForm)
+	redirect := ctx.Repo.RepoLink + "/settings/secret_scanning"
+
+	alert := getSecretScanningAlert(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.JSONError(ctx.GetErrMsg())
+		return
+	}
+
+	if err := secretscanning_service.ResolveAlert(ctx, ctx.Doer, ctx.Repo.Repository, alert, git_model.SecretScanningResolution(form.Resolution), form.ResolutionComment); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.JSONError(ctx.Tr("repo.settings.secret_scanning.invalid_resolution"))
+		} else {
+			ctx.ServerError("ResolveAlert", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.secret_scanning.resolve_success"))
+	ctx.JSONRedirect(redirect)
+}
+
+// ReopenSecretScanningAlertPost reopens a resolved secret scanning alert
+func ReopenSecretScanningAlertPost(ctx *context.Context) {
+	alert := getSecretScanningAlert(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := secretscanning_service.ReopenAlert(ctx, ctx.Doer, ctx.Repo.Repository, alert); err != nil {
+		ctx.ServerError("ReopenAlert", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.secret_scanning.reopen_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/secret_scanning?state=resolved")
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	audit_model "code.gitea.io/gitea/models/audit"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+)
+
+// Events prepares the list of the audit events which belong to the owner, all the events are listed if ownerID is 0
+func Events(ctx *context.Context, ownerID int64) {
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	actor := ctx.FormTrim("actor")
+	opts := audit_model.FindEventsOptions{
+		ListOptions: db.ListOptions{
+			Page:     page,
+			PageSize: setting.UI.Admin.NoticePagingNum,
+		},
+		OwnerID:  ownerID,
+		DoerName: actor,
+		Action:   audit_model.Action(ctx.FormTrim("action")),
+	}
+
+	events, total, err := db.FindAndCount[audit_model.Event](ctx, opts)
+	if err != nil {
+		ctx.ServerError("FindAndCount", err)
+		return
+	}
+
+	ctx.Data["AuditEvents"] = events
+	ctx.Data["AuditActions"] = audit_model.AllActions()
+	ctx.Data["AuditEnabled"] = setting.Audit.Enabled
+	ctx.Data["FilterAction"] = opts.Action
+	ctx.Data["FilterActor"] = actor
+
+	pager := context.NewPagination(int(total), opts.PageSize, page, 5)
+	pager.AddParamString("action", string(opts.Action))
+	pager.AddParamString("actor", actor)
+	ctx.Data["Page"] = pager
+}
@@ -6,19 +6,19 @@ package packages
 import (
 	"fmt"
 	"net/http"
-	"time"
+	"strings"
+	"unicode"
 
-	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
-	container_service "code.gitea.io/gitea/services/packages/container"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
 )
 
 func SetPackagesContext(ctx *context.Context, owner *user_model.User) {
@@ -29,6 +29,22 @@ func SetPackagesContext(ctx *context.Context, owner *user_model.User) {
 	}
 
 	ctx.Data["CleanupRules"] = pcrs
+
+	pus, err := packages_model.GetUpstreamsByOwner(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("GetUpstreamsByOwner", err)
+		return
+	}
+
+	ctx.Data["Upstreams"] = pus
+
+	pvrs, err := packages_model.GetVirtualRegistriesByOwner(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("GetVirtualRegistriesByOwner", err)
+		return
+	}
+
+	ctx.Data["VirtualRegistries"] = pvrs
 }
 
 func SetRuleAddContext(ctx *context.Context) {
@@ -139,89 +155,172 @@ func SetRulePreviewContext(ctx *context.Context, owner *user_model.User) {
 		return
 	}
 
-	if err := pcr.CompiledPattern(); err != nil {
-		ctx.ServerError("CompiledPattern", err)
+	versionsToRemove, err := packages_cleanup_service.PreviewCleanupRule(ctx, pcr)
+	if err != nil {
+		ctx.ServerError("PreviewCleanupRule", err)
 		return
 	}
 
-	olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
+	ctx.Data["CleanupRule"] = pcr
+	ctx.Data["VersionsToRemove"] = versionsToRemove
+}
+
+func getCleanupRuleByContext(ctx *context.Context, owner *user_model.User) *packages_model.PackageCleanupRule {
+	id := ctx.FormInt64("id")
+	if id == 0 {
+		id = ctx.PathParamInt64("id")
+	}
 
-	packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+	pcr, err := packages_model.GetCleanupRuleByID(ctx, id)
 	if err != nil {
-		ctx.ServerError("GetPackagesByType", err)
+		if err == packages_model.ErrPackageCleanupRuleNotExist {
+			ctx.NotFound("", err)
+		} else {
+			ctx.ServerError("GetCleanupRuleByID", err)
+		}
+		return nil
+	}
+
+	if pcr != nil && pcr.OwnerID == owner.ID {
+		return pcr
+	}
+
+	ctx.NotFound("", fmt.Errorf("PackageCleanupRule[%v] not associated to owner %v", id, owner))
+
+	return nil
+}
+
+func SetUpstreamAddContext(ctx *context.Context) {
+	setUpstreamEditContext(ctx, nil)
+}
+
+func SetUpstreamEditContext(ctx *context.Context, owner *user_model.User) {
+	pu := getUpstreamByContext(ctx, owner)
+	if pu == nil {
 		return
 	}
 
-	versionsToRemove := make([]*packages_model.PackageDescriptor, 0, 10)
+	setUpstreamEditContext(ctx, pu)
+}
+
+func setUpstreamEditContext(ctx *context.Context, pu *packages_model.PackageUpstream) {
+	ctx.Data["IsEditUpstream"] = pu != nil
 
-	for _, p := range packages {
-		pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
-			PackageID:  p.ID,
-			IsInternal: optional.Some(false),
-			Sort:       packages_model.SortCreatedDesc,
-			Paginator:  db.NewAbsoluteListOptions(pcr.KeepCount, 200),
-		})
-		if err != nil {
-			ctx.ServerError("SearchVersions", err)
+	if pu == nil {
+		pu = &packages_model.PackageUpstream{
+			Enabled:     true,
+			MetadataTTL: 1800,
+		}
+	}
+	ctx.Data["Upstream"] = pu
+	ctx.Data["AvailableTypes"] = packages_model.UpstreamTypeList
+}
+
+func PerformUpstreamAddPost(ctx *context.Context, owner *user_model.User, redirectURL string, template base.TplName) {
+	performUpstreamEditPost(ctx, owner, nil, redirectURL, template)
+}
+
+func PerformUpstreamEditPost(ctx *context.Context, owner *user_model.User, redirectURL string, template base.TplName) {
+	pu := getUpstreamByContext(ctx, owner)
+	if pu == nil {
+		return
+	}
+
+	form := web.GetForm(ctx).(*forms.PackageUpstreamForm)
+
+	if form.Action == "remove" {
+		if err := packages_model.DeleteUpstreamByID(ctx, pu.ID); err != nil {
+			ctx.ServerError("DeleteUpstreamByID", err)
 			return
 		}
-		for _, pv := range pvs {
-			if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
-				ctx.ServerError("ShouldBeSkipped", err)
-				return
-			} else if skip {
-				continue
-			}
 
-			toMatch := pv.LowerVersion
-			if pcr.MatchFullName {
-				toMatch = p.LowerName + "/" + pv.LowerVersion
-			}
+		ctx.Flash.Success(ctx.Tr("packages.owner.settings.upstreams.success.delete"))
+		ctx.Redirect(redirectURL)
+	} else {
+		performUpstreamEditPost(ctx, owner, pu, redirectURL, template)
+	}
+}
 
-			if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
-				continue
-			}
-			if pv.CreatedUnix.AsLocalTime().After(olderThan) {
-				continue
-			}
-			if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
-				continue
-			}
+func performUpstreamEditPost(ctx *context.Context, owner *user_model.User, pu *packages_model.PackageUpstream, redirectURL string, template base.TplName) {
+	isEditUpstream := pu != nil
+
+	if pu == nil {
+		pu = &packages_model.PackageUpstream{}
+	}
+
+	form := web.GetForm(ctx).(*forms.PackageUpstreamForm)
+
+	urlChanged := pu.URL != form.URL
+
+	pu.Enabled = form.Enabled
+	pu.OwnerID = owner.ID
+	pu.URL = form.URL
+	pu.MetadataTTL = form.MetadataTTL
+
+	ctx.Data["IsEditUpstream"] = isEditUpstream
+	ctx.Data["Upstream"] = pu
+	ctx.Data["AvailableTypes"] = packages_model.UpstreamTypeList
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, template)
+		return
+	}
 
-			pd, err := packages_model.GetPackageDescriptor(ctx, pv)
-			if err != nil {
-				ctx.ServerError("GetPackageDescriptor", err)
+	if isEditUpstream {
+		if err := packages_model.UpdateUpstream(ctx, pu); err != nil {
+			ctx.ServerError("UpdateUpstream", err)
+			return
+		}
+		if urlChanged {
+			if err := packages_model.DeleteUpstreamCacheByUpstreamID(ctx, pu.ID); err != nil {
+				ctx.ServerError("DeleteUpstreamCacheByUpstreamID", err)
 				return
 			}
-			versionsToRemove = append(versionsToRemove, pd)
+		}
+	} else {
+		pu.Type = packages_model.Type(form.Type)
+
+		if has, err := packages_model.HasOwnerUpstreamForPackageType(ctx, owner.ID, pu.Type); err != nil {
+			ctx.ServerError("HasOwnerUpstreamForPackageType", err)
+			return
+		} else if has {
+			ctx.Data["Err_Type"] = true
+			ctx.HTML(http.StatusOK, template)
+			return
+		}
+
+		var err error
+		if pu, err = packages_model.InsertUpstream(ctx, pu); err != nil {
+			ctx.ServerError("InsertUpstream", err)
+			return
 		}
 	}
 
-	ctx.Data["CleanupRule"] = pcr
-	ctx.Data["VersionsToRemove"] = versionsToRemove
+	ctx.Flash.Success(ctx.Tr("packages.owner.settings.upstreams.success.update"))
+	ctx.Redirect(fmt.Sprintf("%s/upstreams/%d", redirectURL, pu.ID))
 }
 
-func getCleanupRuleByContext(ctx *context.Context, owner *user_model.User) *packages_model.PackageCleanupRule {
+func getUpstreamByContext(ctx *context.Context, owner *user_model.User) *packages_model.PackageUpstream {
 	id := ctx.FormInt64("id")
 	if id == 0 {
 		id = ctx.PathParamInt64("id")
 	}
 
-	pcr, err := packages_model.GetCleanupRuleByID(ctx, id)
+	pu, err := packages_model.GetUpstreamByID(ctx, id)
 	if err != nil {
-		if err == packages_model.ErrPackageCleanupRuleNotExist {
+		if err == packages_model.ErrPackageUpstreamNotExist {
 			ctx.NotFound("", err)
 		} else {
-			ctx.ServerError("GetCleanupRuleByID", err)
+			ctx.ServerError("GetUpstreamByID", err)
 		}
 		return nil
 	}
 
-	if pcr != nil && pcr.OwnerID == owner.ID {
-		return pcr
+	if pu != nil && pu.OwnerID == owner.ID {
+		return pu
 	}
 
-	ctx.NotFound("", fmt.Errorf("PackageCleanupRule[%v] not associated to owner %v", id, owner))
+	ctx.NotFound("", fmt.Errorf("PackageUpstream[%v] not associated to owner %v", id, owner))
 
 	return nil
 }
@@ -245,3 +344,187 @@ func RebuildCargoIndex(ctx *context.Context, owner *user_model.User) {
 		ctx.Flash.Success(ctx.Tr("packages.owner.settings.cargo.rebuild.success"))
 	}
 }
+
+func SetVirtualRegistryAddContext(ctx *context.Context) {
+	setVirtualRegistryEditContext(ctx, nil)
+}
+
+func SetVirtualRegistryEditContext(ctx *context.Context, owner *user_model.User) {
+	pvr := getVirtualRegistryByContext(ctx, owner)
+	if pvr == nil {
+		return
+	}
+
+	setVirtualRegistryEditContext(ctx, pvr)
+}
+
+func setVirtualRegistryEditContext(ctx *context.Context, pvr *packages_model.PackageVirtualRegistry) {
+	ctx.Data["IsEditVirtualRegistry"] = pvr != nil
+
+	memberNames := ""
+	if pvr == nil {
+		pvr = &packages_model.PackageVirtualRegistry{
+			Enabled: true,
+		}
+	} else {
+		memberIDs, err := packages_model.GetVirtualRegistryMemberIDs(ctx, pvr.ID)
+		if err != nil {
+			ctx.ServerError("GetVirtualRegistryMemberIDs", err)
+			return
+		}
+		members, err := user_model.GetUsersByIDs(ctx, memberIDs)
+		if err != nil {
+			ctx.ServerError("GetUsersByIDs", err)
+			return
+		}
+		names := make(map[int64]string, len(members))
+		for _, member := range members {
+			names[member.ID] = member.Name
+		}
+		lines := make([]string, 0, len(memberIDs))
+		for _, id := range memberIDs {
+			if name, ok := names[id]; ok {
+				lines = append(lines, name)
+			}
+		}
+		memberNames = strings.Join(lines, "\n")
+	}
+	ctx.Data["VirtualRegistry"] = pvr
+	ctx.Data["MemberNames"] = memberNames
+	ctx.Data["AvailableTypes"] = packages_model.VirtualTypeList
+}
+
+func PerformVirtualRegistryAddPost(ctx *context.Context, owner *user_model.User, redirectURL string, template base.TplName) {
+	performVirtualRegistryEditPost(ctx, owner, nil, redirectURL, template)
+}
+
+func PerformVirtualRegistryEditPost(ctx *context.Context, owner *user_model.User, redirectURL string, template base.TplName) {
+	pvr := getVirtualRegistryByContext(ctx, owner)
+	if pvr == nil {
+		return
+	}
+
+	form := web.GetForm(ctx).(*forms.PackageVirtualRegistryForm)
+
+	if form.Action == "remove" {
+		if err := packages_model.DeleteVirtualRegistryByID(ctx, pvr.ID); err != nil {
+			ctx.ServerError("DeleteVirtualRegistryByID", err)
+			return
+		}
+
+		ctx.Flash.Success(ctx.Tr("packages.owner.settings.virtual_registries.success.delete"))
+		ctx.Redirect(redirectURL)
+	} else {
+		performVirtualRegistryEditPost(ctx, owner, pvr, redirectURL, template)
+	}
+}
+
+func performVirtualRegistryEditPost(ctx *context.Context, owner *user_model.User, pvr *packages_model.PackageVirtualRegistry, redirectURL string, template base.TplName) {
+	isEditVirtualRegistry := pvr != nil
+
+	if pvr == nil {
+		pvr = &packages_model.PackageVirtualRegistry{}
+	}
+
+	form := web.GetForm(ctx).(*forms.PackageVirtualRegistryForm)
+
+	pvr.Enabled = form.Enabled
+	pvr.OwnerID = owner.ID
+
+	ctx.Data["IsEditVirtualRegistry"] = isEditVirtualRegistry
+	ctx.Data["VirtualRegistry"] = pvr
+	ctx.Data["MemberNames"] = form.Members
+	ctx.Data["AvailableTypes"] = packages_model.VirtualTypeList
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, template)
+		return
+	}
+
+	// members are separated by whitespace or commas, their order defines the priority
+	memberIDs := make([]int64, 0, 10)
+	seen := make(container.Set[int64])
+	for _, name := range strings.FieldsFunc(form.Members, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		member, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Data["Err_Members"] = true
+				ctx.RenderWithErr(ctx.Tr("packages.owner.settings.virtual_registries.members.not_exist", name), template, nil)
+				return
+			}
+			ctx.ServerError("GetUserByName", err)
+			return
+		}
+		if member.ID == owner.ID || (!member.IsIndividual() && !member.IsOrganization()) {
+			ctx.Data["Err_Members"] = true
+			ctx.RenderWithErr(ctx.Tr("packages.owner.settings.virtual_registries.members.invalid", name), template, nil)
+			return
+		}
+		if seen.Add(member.ID) {
+			memberIDs = append(memberIDs, member.ID)
+		}
+	}
+
+	if isEditVirtualRegistry {
+		if err := packages_model.UpdateVirtualRegistry(ctx, pvr); err != nil {
+			ctx.ServerError("UpdateVirtualRegistry", err)
+			return
+		}
+	} else {
+		pvr.Type = packages_model.Type(form.Type)
+
+		if !packages_model.IsVirtualType(pvr.Type) {
+			ctx.Data["Err_Type"] = true
+			ctx.RenderWithErr(ctx.Tr("packages.owner.settings.virtual_registries.type.unsupported", pvr.Type.Name()), template, nil)
+			return
+		}
+
+		if has, err := packages_model.HasOwnerVirtualRegistryForPackageType(ctx, owner.ID, pvr.Type); err != nil {
+			ctx.ServerError("HasOwnerVirtualRegistryForPackageType", err)
+			return
+		} else if has {
+			ctx.Data["Err_Type"] = true
+			ctx.HTML(http.StatusOK, template)
+			return
+		}
+
+		var err error
+		if pvr, err = packages_model.InsertVirtualRegistry(ctx, pvr); err != nil {
+			ctx.ServerError("InsertVirtualRegistry", err)
+			return
+		}
+	}
+
+	if err := packages_model.SetVirtualRegistryMembers(ctx, pvr.ID, memberIDs); err != nil {
+		ctx.ServerError("SetVirtualRegistryMembers", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("packages.owner.settings.virtual_registries.success.update"))
+	ctx.Redirect(fmt.Sprintf("%s/virtual_registries/%d", redirectURL, pvr.ID))
+}
+
+func getVirtualRegistryByContext(ctx *context.Context, owner *user_model.User) *packages_model.PackageVirtualRegistry {
+	id := ctx.FormInt64("id")
+	if id == 0 {
+		id = ctx.PathParamInt64("id")
+	}
+
+	pvr, err := packages_model.GetVirtualRegistryByID(ctx, id)
+	if err != nil {
+		if err == packages_model.ErrPackageVirtualRegistryNotExist {
+			ctx.NotFound("", err)
+		} else {
+			ctx.ServerError("GetVirtualRegistryByID", err)
+		}
+		return nil
+	}
+
+	if pvr != nil && pvr.OwnerID == owner.ID {
+		return pvr
+	}
+
+	ctx.NotFound("", fmt.Errorf("PackageVirtualRegistry[%v] not associated to owner %v", id, owner))
+
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	quota_model "code.gitea.io/gitea/models/quota"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/context"
+)
+
+// StorageQuota prepares the storage usage and the quota groups of the owner for display
+func StorageQuota(ctx *context.Context, owner *user_model.User) {
+	used, err := quota_model.GetUsedForUser(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("GetUsedForUser", err)
+		return
+	}
+
+	groups, err := quota_model.GetGroupsForUser(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("GetGroupsForUser", err)
+		return
+	}
+
+	ctx.Data["QuotaUsed"] = used
+	ctx.Data["QuotaUsedTotal"] = used.SizeFor(quota_model.LimitSubjectSizeAll)
+	ctx.Data["QuotaGroups"] = groups
+	ctx.Data["QuotaDefaultTotal"] = setting.Quota.DefaultTotal
+}
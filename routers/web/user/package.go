@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/optional"
 	alpine_module "code.gitea.io/gitea/modules/packages/alpine"
+	arch_module "code.gitea.io/gitea/modules/packages/arch"
 	debian_module "code.gitea.io/gitea/modules/packages/debian"
 	rpm_module "code.gitea.io/gitea/modules/packages/rpm"
 	"code.gitea.io/gitea/modules/setting"
@@ -30,12 +31,16 @@ import (
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 	packages_service "code.gitea.io/gitea/services/packages"
+	arch_service "code.gitea.io/gitea/services/packages/arch"
+	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+	container_service "code.gitea.io/gitea/services/packages/container"
 )
 
 const (
 	tplPackagesList       base.TplName = "user/overview/packages"
 	tplPackagesView       base.TplName = "package/view"
 	tplPackageVersionList base.TplName = "user/overview/package_versions"
+	tplPackageComponents  base.TplName = "user/overview/package_components"
 	tplPackagesSettings   base.TplName = "package/settings"
 )
 
@@ -177,14 +182,24 @@ func ViewPackageVersion(ctx *context.Context) {
 	ctx.Data["Title"] = pd.Package.Name
 	ctx.Data["IsPackagesPage"] = true
 	ctx.Data["PackageDescriptor"] = pd
+	ctx.Data["Attestations"] = packages_service.GetFileAttestations(pd.Files)
 
 	switch pd.Package.Type {
-	case packages_model.TypeContainer:
+	case packages_model.TypeContainer, packages_model.TypeTerraform:
 		registryAppURL, err := url.Parse(httplib.GuessCurrentAppURL(ctx))
 		if err != nil {
 			registryAppURL, _ = url.Parse(setting.AppURL)
 		}
 		ctx.Data["RegistryHost"] = registryAppURL.Host
+
+		if pd.Package.Type == packages_model.TypeContainer {
+			components, err := container_service.GetComponents(ctx, pd)
+			if err != nil {
+				ctx.ServerError("GetComponents", err)
+				return
+			}
+			ctx.Data["Components"] = components
+		}
 	case packages_model.TypeAlpine:
 		branches := make(container.Set[string])
 		repositories := make(container.Set[string])
@@ -206,6 +221,30 @@ func ViewPackageVersion(ctx *context.Context) {
 		ctx.Data["Branches"] = util.Sorted(branches.Values())
 		ctx.Data["Repositories"] = util.Sorted(repositories.Values())
 		ctx.Data["Architectures"] = util.Sorted(architectures.Values())
+	case packages_model.TypeArch:
+		repositories := make(container.Set[string])
+		architectures := make(container.Set[string])
+
+		for _, f := range pd.Files {
+			for _, pp := range f.Properties {
+				switch pp.Name {
+				case arch_module.PropertyRepository:
+					repositories.Add(pp.Value)
+				case arch_module.PropertyArchitecture:
+					architectures.Add(pp.Value)
+				}
+			}
+		}
+
+		ctx.Data["Repositories"] = util.Sorted(repositories.Values())
+		ctx.Data["Architectures"] = util.Sorted(architectures.Values())
+
+		fingerprint, err := arch_service.GetPublicKeyFingerprint(ctx, pd.Owner.ID)
+		if err != nil {
+			ctx.ServerError("GetPublicKeyFingerprint", err)
+			return
+		}
+		ctx.Data["SigningKeyFingerprint"] = fingerprint
 	case packages_model.TypeDebian:
 		distributions := make(container.Set[string])
 		components := make(container.Set[string])
@@ -398,6 +437,63 @@ func ListPackageVersions(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplPackageVersionList)
 }
 
+// ListPackageComponents searches the components listed in the SBOMs of the container images of the owner
+func ListPackageComponents(ctx *context.Context) {
+	shared_user.PrepareContextForProfileBigAvatar(ctx)
+
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	name := ctx.FormTrim("name")
+	version := ctx.FormTrim("version")
+
+	shared_user.RenderUserHeader(ctx)
+
+	ctx.Data["Title"] = ctx.Tr("packages.components.search")
+	ctx.Data["IsPackagesPage"] = true
+	ctx.Data["ComponentName"] = name
+	ctx.Data["ComponentVersion"] = version
+
+	var (
+		matches []*container_service.ComponentMatch
+		total   int64
+	)
+	if name != "" {
+		var err error
+		matches, total, err = container_service.SearchComponents(ctx, &container_model.ComponentSearchOptions{
+			Paginator: &db.ListOptions{
+				PageSize: setting.UI.PackagesPagingNum,
+				Page:     page,
+			},
+			OwnerID: ctx.ContextUser.ID,
+			Name:    name,
+			Version: version,
+		})
+		if err != nil {
+			ctx.ServerError("SearchComponents", err)
+			return
+		}
+	}
+
+	ctx.Data["ComponentMatches"] = matches
+	ctx.Data["Total"] = total
+
+	err := shared_user.LoadHeaderCount(ctx)
+	if err != nil {
+		ctx.ServerError("LoadHeaderCount", err)
+		return
+	}
+
+	pager := context.NewPagination(int(total), setting.UI.PackagesPagingNum, page, 5)
+	pager.AddParamString("name", name)
+	pager.AddParamString("version", version)
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplPackageComponents)
+}
+
 // PackageSettings displays the package settings page
 func PackageSettings(ctx *context.Context) {
 	pd := ctx.Package.Descriptor
@@ -464,7 +560,7 @@ func PackageSettingsPost(ctx *context.Context) {
 		ctx.Redirect(ctx.Link)
 		return
 	case "delete":
-		err := packages_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor.Version)
+		err := packages_cleanup_service.RemovePackageVersion(ctx, ctx.Doer, ctx.Package.Descriptor)
 		if err != nil {
 			log.Error("Error deleting package: %v", err)
 			ctx.Flash.Error(ctx.Tr("packages.settings.delete.error"))
@@ -7,12 +7,14 @@ package setting
 import (
 	"net/http"
 
+	audit_model "code.gitea.io/gitea/models/audit"
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/web"
+	audit_service "code.gitea.io/gitea/services/audit"
 	"code.gitea.io/gitea/services/context"
 	"code.gitea.io/gitea/services/forms"
 )
@@ -72,6 +74,8 @@ func ApplicationsPost(ctx *context.Context) {
 		ctx.ServerError("NewAccessToken", err)
 		return
 	}
+	audit_service.Record(ctx, ctx.Doer, audit_model.ActionAccessTokenCreate, ctx.Doer.ID, 0,
+		audit_service.Target{Type: audit_model.TargetTypeAccessToken, ID: t.ID, Name: t.Name}, "scope: %s", t.Scope)
 
 	ctx.Flash.Success(ctx.Tr("settings.generate_token_success"))
 	ctx.Flash.Info(t.Token)
@@ -81,9 +85,12 @@ func ApplicationsPost(ctx *context.Context) {
 
 // DeleteApplication response for delete user access token
 func DeleteApplication(ctx *context.Context) {
-	if err := auth_model.DeleteAccessTokenByID(ctx, ctx.FormInt64("id"), ctx.Doer.ID); err != nil {
+	tokenID := ctx.FormInt64("id")
+	if err := auth_model.DeleteAccessTokenByID(ctx, tokenID, ctx.Doer.ID); err != nil {
 		ctx.Flash.Error("DeleteAccessTokenByID: " + err.Error())
 	} else {
+		audit_service.Record(ctx, ctx.Doer, audit_model.ActionAccessTokenDelete, ctx.Doer.ID, 0,
+			audit_service.Target{Type: audit_model.TargetTypeAccessToken, ID: tokenID}, "")
 		ctx.Flash.Success(ctx.Tr("settings.delete_token_success"))
 	}
 
@@ -259,7 +259,11 @@ func Routes() *web.Router {
 	}
 
 	if setting.Metrics.Enabled {
-		prometheus.MustRegister(metrics.NewCollector())
+		prometheus.MustRegister(metrics.NewCollector(), metrics.NewQueueCollector())
+		prometheus.MustRegister(metrics.EventCollectors()...)
+		if setting.Actions.Enabled {
+			prometheus.MustRegister(metrics.NewActionsCollector())
+		}
 		routes.Get("/metrics", append(mid, Metrics)...)
 	}
 
@@ -347,6 +351,13 @@ func registerRoutes(m *web.Router) {
 		}
 	}
 
+	secretScanningEnabled := func(ctx *context.Context) {
+		if !setting.SecretScanning.Enabled {
+			ctx.Error(http.StatusNotFound)
+			return
+		}
+	}
+
 	federationEnabled := func(ctx *context.Context) {
 		if !setting.Federation.Enabled {
 			ctx.Error(http.StatusNotFound)
@@ -375,6 +386,13 @@ func registerRoutes(m *web.Router) {
 		}
 	}
 
+	quotaEnabled := func(ctx *context.Context) {
+		if !setting.Quota.Enabled {
+			ctx.Error(http.StatusNotFound)
+			return
+		}
+	}
+
 	feedEnabled := func(ctx *context.Context) {
 		if !setting.Other.EnableFeed {
 			ctx.Error(http.StatusNotFound)
@@ -450,6 +468,23 @@ func registerRoutes(m *web.Router) {
 		})
 	}
 
+	addSettingsEnvironmentsRoutes := func() {
+		m.Group("/environments", func() {
+			m.Get("", repo_setting.Environments)
+			m.Post("/new", web.Bind(forms.EditEnvironmentForm{}), repo_setting.EnvironmentCreate)
+			m.Group("/{environment}", func() {
+				m.Combo("").Get(repo_setting.EnvironmentEdit).
+					Post(web.Bind(forms.EditEnvironmentForm{}), repo_setting.EnvironmentEditPost)
+				m.Post("/delete", repo_setting.EnvironmentDelete)
+				m.Post("/secrets", web.Bind(forms.AddSecretForm{}), repo_setting.EnvironmentSecretsPost)
+				m.Post("/secrets/delete", repo_setting.EnvironmentSecretsDelete)
+				m.Post("/variables/new", web.Bind(forms.EditVariableForm{}), repo_setting.EnvironmentVariableCreate)
+				m.Post("/variables/{variable_id}/edit", web.Bind(forms.EditVariableForm{}), repo_setting.EnvironmentVariableUpdate)
+				m.Post("/variables/{variable_id}/delete", repo_setting.EnvironmentVariableDelete)
+			})
+		})
+	}
+
 	addSettingsRunnersRoutes := func() {
 		m.Group("/runners", func() {
 			m.Get("", repo_setting.Runners)
@@ -475,6 +510,7 @@ func registerRoutes(m *web.Router) {
 			ctx.Redirect(setting.AppSubURL + "/user/settings/account")
 		})
 		m.Get("/passkey-endpoints", passkeyEndpoints)
+		m.Get("/terraform.json", packagesEnabled, terraformServiceDiscovery)
 		m.Methods("GET, HEAD", "/*", public.FileHandlerFunc())
 	}, optionsCorsHandler())
 
@@ -624,6 +660,26 @@ func registerRoutes(m *web.Router) {
 					m.Get("/preview", user_setting.PackagesRulePreview)
 				})
 			})
+			m.Group("/upstreams", func() {
+				m.Group("/add", func() {
+					m.Get("", user_setting.PackagesUpstreamAdd)
+					m.Post("", web.Bind(forms.PackageUpstreamForm{}), user_setting.PackagesUpstreamAddPost)
+				})
+				m.Group("/{id}", func() {
+					m.Get("", user_setting.PackagesUpstreamEdit)
+					m.Post("", web.Bind(forms.PackageUpstreamForm{}), user_setting.PackagesUpstreamEditPost)
+				})
+			})
+			m.Group("/virtual_registries", func() {
+				m.Group("/add", func() {
+					m.Get("", user_setting.PackagesVirtualRegistryAdd)
+					m.Post("", web.Bind(forms.PackageVirtualRegistryForm{}), user_setting.PackagesVirtualRegistryAddPost)
+				})
+				m.Group("/{id}", func() {
+					m.Get("", user_setting.PackagesVirtualRegistryEdit)
+					m.Post("", web.Bind(forms.PackageVirtualRegistryForm{}), user_setting.PackagesVirtualRegistryEditPost)
+				})
+			})
 			m.Group("/cargo", func() {
 				m.Post("/initialize", user_setting.InitializeCargoIndex)
 				m.Post("/rebuild", user_setting.RebuildCargoIndex)
@@ -657,7 +713,9 @@ func registerRoutes(m *web.Router) {
 			m.Get("", user_setting.BlockedUsers)
 			m.Post("", web.Bind(forms.BlockUserForm{}), user_setting.BlockedUsersPost)
 		})
-	}, reqSignIn, ctxDataSet("PageIsUserSettings", true, "EnablePackages", setting.Packages.Enabled))
+
+		m.Get("/storage", quotaEnabled, user_setting.Storage)
+	}, reqSignIn, ctxDataSet("PageIsUserSettings", true, "EnablePackages", setting.Packages.Enabled, "EnableQuota", setting.Quota.Enabled))
 
 	m.Group("/user", func() {
 		m.Get("/activate", auth.Activate)
@@ -767,6 +825,8 @@ func registerRoutes(m *web.Router) {
 			m.Post("/{authid}/delete", admin.DeleteAuthSource)
 		})
 
+		m.Get("/audit", admin.AuditEvents)
+
 		m.Group("/notices", func() {
 			m.Get("", admin.Notices)
 			m.Post("/delete", admin.DeleteNotices)
@@ -943,6 +1003,8 @@ func registerRoutes(m *web.Router) {
 					addSettingsVariablesRoutes()
 				}, actions.MustEnableActions)
 
+				m.Get("/audit", org.AuditEvents)
+
 				m.Methods("GET,POST", "/delete", org.SettingsDelete)
 
 				m.Group("/packages", func() {
@@ -958,6 +1020,26 @@ func registerRoutes(m *web.Router) {
 							m.Get("/preview", org.PackagesRulePreview)
 						})
 					})
+					m.Group("/upstreams", func() {
+						m.Group("/add", func() {
+							m.Get("", org.PackagesUpstreamAdd)
+							m.Post("", web.Bind(forms.PackageUpstreamForm{}), org.PackagesUpstreamAddPost)
+						})
+						m.Group("/{id}", func() {
+							m.Get("", org.PackagesUpstreamEdit)
+							m.Post("", web.Bind(forms.PackageUpstreamForm{}), org.PackagesUpstreamEditPost)
+						})
+					})
+					m.Group("/virtual_registries", func() {
+						m.Group("/add", func() {
+							m.Get("", org.PackagesVirtualRegistryAdd)
+							m.Post("", web.Bind(forms.PackageVirtualRegistryForm{}), org.PackagesVirtualRegistryAddPost)
+						})
+						m.Group("/{id}", func() {
+							m.Get("", org.PackagesVirtualRegistryEdit)
+							m.Post("", web.Bind(forms.PackageVirtualRegistryForm{}), org.PackagesVirtualRegistryEditPost)
+						})
+					})
 					m.Group("/cargo", func() {
 						m.Post("/initialize", org.InitializeCargoIndex)
 						m.Post("/rebuild", org.RebuildCargoIndex)
@@ -968,7 +1050,9 @@ func registerRoutes(m *web.Router) {
 					m.Get("", org.BlockedUsers)
 					m.Post("", web.Bind(forms.BlockUserForm{}), org.BlockedUsersPost)
 				})
-			}, ctxDataSet("EnableOAuth2", setting.OAuth2.Enabled, "EnablePackages", setting.Packages.Enabled, "PageIsOrgSettings", true))
+
+				m.Get("/storage", quotaEnabled, org.SettingsStorage)
+			}, ctxDataSet("EnableOAuth2", setting.OAuth2.Enabled, "EnablePackages", setting.Packages.Enabled, "EnableQuota", setting.Quota.Enabled, "PageIsOrgSettings", true))
 		}, context.OrgAssignment(true, true))
 	}, reqSignIn)
 	// end "/org": most org routes
@@ -986,6 +1070,7 @@ func registerRoutes(m *web.Router) {
 		if setting.Packages.Enabled {
 			m.Group("/packages", func() {
 				m.Get("", user.ListPackages)
+				m.Get("/components", user.ListPackageComponents)
 				m.Group("/{type}/{name}", func() {
 					m.Get("", user.RedirectToLastVersion)
 					m.Get("/versions", user.ListPackageVersions)
@@ -1109,6 +1194,12 @@ func registerRoutes(m *web.Router) {
 			m.Post("/delete", repo_setting.DeleteDeployKey)
 		})
 
+		m.Group("/secret_scanning", func() {
+			m.Get("", repo_setting.SecretScanningAlerts)
+			m.Post("/{id}/resolve", web.Bind(forms.ResolveSecretScanningAlertForm{}), repo_setting.ResolveSecretScanningAlertPost)
+			m.Post("/{id}/reopen", repo_setting.ReopenSecretScanningAlertPost)
+		}, secretScanningEnabled)
+
 		m.Group("/lfs", func() {
 			m.Get("/", repo_setting.LFSFiles)
 			m.Get("/show/{oid}", repo_setting.LFSFileGet)
@@ -1127,6 +1218,7 @@ func registerRoutes(m *web.Router) {
 			addSettingsRunnersRoutes()
 			addSettingsSecretsRoutes()
 			addSettingsVariablesRoutes()
+			addSettingsEnvironmentsRoutes()
 		}, actions.MustEnableActions)
 		// the follow handler must be under "settings", otherwise this incomplete repo can't be accessed
 		m.Group("/migrate", func() {
@@ -1135,7 +1227,7 @@ func registerRoutes(m *web.Router) {
 		})
 	},
 		reqSignIn, context.RepoAssignment, reqRepoAdmin, context.RepoRef(),
-		ctxDataSet("PageIsRepoSettings", true, "LFSStartServer", setting.LFS.StartServer),
+		ctxDataSet("PageIsRepoSettings", true, "LFSStartServer", setting.LFS.StartServer, "SecretScanningEnabled", setting.SecretScanning.Enabled),
 	)
 	// end "/{username}/{reponame}/settings"
 
@@ -1473,6 +1565,7 @@ func registerRoutes(m *web.Router) {
 			})
 			m.Post("/merge", context.RepoMustNotBeArchived(), web.Bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cancel_auto_merge", context.RepoMustNotBeArchived(), repo.CancelAutoMergePullRequest)
+			m.Post("/remove_from_merge_queue", context.RepoMustNotBeArchived(), repo.RemoveFromMergeQueuePullRequest)
 			m.Post("/update", repo.UpdatePullRequest)
 			m.Post("/set_allow_maintainer_edit", web.Bind(forms.UpdateAllowEditsForm{}), repo.SetAllowEdits)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
@@ -0,0 +1,394 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// cacheScopes returns the scopes the run of the task can restore caches from, ordered by priority:
+// the ref of the run, the base branch if the run is triggered by a pull request, and the default branch.
+// Caches are only saved to the first one, so the runs of a branch can't affect the caches of other branches.
+func cacheScopes(ctx context.Context, task *actions_model.ActionTask) ([]string, error) {
+	if err := task.LoadJob(ctx); err != nil {
+		return nil, err
+	}
+	if err := task.Job.LoadRun(ctx); err != nil {
+		return nil, err
+	}
+	run := task.Job.Run
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	scopes := []string{run.Ref}
+	addScope := func(scope string) {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if payload, err := run.GetPullRequestEventPayload(); err == nil && payload.PullRequest != nil && payload.PullRequest.Base != nil {
+		addScope(git.BranchPrefix + payload.PullRequest.Base.Ref)
+	}
+	addScope(git.BranchPrefix + run.Repo.DefaultBranch)
+	return scopes, nil
+}
+
+// FindCache returns the cache to restore for the task, the keys are the primary key followed by the restore keys.
+// In every scope, each key is matched exactly at first and then as a prefix of the most recently created cache.
+func FindCache(ctx context.Context, task *actions_model.ActionTask, keys []string, version string) (*actions_model.ActionCache, error) {
+	scopes, err := cacheScopes(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		for _, key := range keys {
+			if key == "" {
+				continue
+			}
+			c, err := actions_model.GetCache(ctx, task.RepoID, scope, key, version)
+			if err == nil && !c.Complete {
+				err = util.ErrNotExist
+			}
+			if errors.Is(err, util.ErrNotExist) {
+				c, err = actions_model.FindCacheByPrefix(ctx, task.RepoID, scope, key, version)
+			}
+			if errors.Is(err, util.ErrNotExist) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			c.UsedUnix = timeutil.TimeStampNow()
+			if err := actions_model.UpdateCache(ctx, c, "used_unix"); err != nil {
+				return nil, err
+			}
+			return c, nil
+		}
+	}
+	return nil, util.NewNotExistErrorf("no cache matches the keys")
+}
+
+// ReserveCache creates an incomplete cache in the scope of the task to upload, the size is optional.
+// A cache can't be overwritten, so it fails if there is already a cache with the same key and version in the scope.
+func ReserveCache(ctx context.Context, task *actions_model.ActionTask, key, version string, size int64) (*actions_model.ActionCache, error) {
+	if key == "" || len(key) > 512 || strings.Contains(key, ",") {
+		return nil, util.NewInvalidArgumentErrorf("invalid cache key %q", key)
+	}
+	if version == "" || len(version) > 255 {
+		return nil, util.NewInvalidArgumentErrorf("invalid cache version %q", version)
+	}
+	if setting.Actions.CacheMaxSize >= 0 && size > setting.Actions.CacheMaxSize {
+		return nil, util.NewInvalidArgumentErrorf("cache size %d exceeds the limit %d", size, setting.Actions.CacheMaxSize)
+	}
+
+	scopes, err := cacheScopes(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	scope := scopes[0]
+
+	if _, err := actions_model.GetCache(ctx, task.RepoID, scope, key, version); err == nil {
+		return nil, util.NewAlreadyExistErrorf("cache %s already exists in scope %s", key, scope)
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+
+	c := &actions_model.ActionCache{
+		RepoID:       task.RepoID,
+		Scope:        scope,
+		CacheKey:     key,
+		CacheVersion: version,
+		UsedUnix:     timeutil.TimeStampNow(),
+	}
+	return c, db.Insert(ctx, c)
+}
+
+// GetUploadingCache returns the cache with the key and version in the scope of the task which isn't committed yet
+func GetUploadingCache(ctx context.Context, task *actions_model.ActionTask, key, version string) (*actions_model.ActionCache, error) {
+	scopes, err := cacheScopes(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	c, err := actions_model.GetCache(ctx, task.RepoID, scopes[0], key, version)
+	if err != nil {
+		return nil, err
+	}
+	if c.Complete {
+		return nil, util.NewInvalidArgumentErrorf("cache %d has been committed", c.ID)
+	}
+	return c, nil
+}
+
+// GetUploadingCacheByID returns the cache by id which the task can upload to
+func GetUploadingCacheByID(ctx context.Context, task *actions_model.ActionTask, id int64) (*actions_model.ActionCache, error) {
+	c, err := actions_model.GetCacheByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckUploadingCache(ctx, task, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CheckUploadingCache checks the cache isn't committed yet and is reserved in the scope the task saves caches to,
+// so the runs of other refs in the repository, like the runs of fork pull requests, can't upload to it
+func CheckUploadingCache(ctx context.Context, task *actions_model.ActionTask, c *actions_model.ActionCache) error {
+	if c.RepoID != task.RepoID {
+		return util.NewNotExistErrorf("cache with id %d", c.ID)
+	}
+	scopes, err := cacheScopes(ctx, task)
+	if err != nil {
+		return err
+	}
+	if c.Scope != scopes[0] {
+		return util.NewPermissionDeniedErrorf("cache %d isn't in the scope %s of the task", c.ID, scopes[0])
+	}
+	if c.Complete {
+		return util.NewInvalidArgumentErrorf("cache %d has been committed", c.ID)
+	}
+	return nil
+}
+
+// UploadCacheChunk saves the content of the cache at the offset, the chunks are merged by the offsets when the cache is committed
+func UploadCacheChunk(ctx context.Context, c *actions_model.ActionCache, start int64, r io.Reader, size int64) error {
+	p := fmt.Sprintf("%s/%d-%d.chunk", c.ChunksPath(), start, start+size-1)
+	return saveCacheObject(ctx, c, p, r, size)
+}
+
+// UploadCacheBlock saves a block of the cache, the blocks are merged in the order of the block list when the cache is committed
+func UploadCacheBlock(ctx context.Context, c *actions_model.ActionCache, blockID string, r io.Reader, size int64) error {
+	p := fmt.Sprintf("%s/block-%s", c.ChunksPath(), base64.URLEncoding.EncodeToString([]byte(blockID)))
+	return saveCacheObject(ctx, c, p, r, size)
+}
+
+// UploadCacheBlob saves the whole content of the cache
+func UploadCacheBlob(ctx context.Context, c *actions_model.ActionCache, r io.Reader, size int64) error {
+	return saveCacheObject(ctx, c, c.StoragePath(), r, size)
+}
+
+// saveCacheObject saves an uploaded object of the cache. The size is counted to the uploaded size of the cache
+// before saving, so the uploaded content can't exceed the size limit no matter what size is declared when reserving.
+// Objects uploaded again are counted again.
+func saveCacheObject(ctx context.Context, c *actions_model.ActionCache, p string, r io.Reader, size int64) error {
+	if size < 0 {
+		return util.NewInvalidArgumentErrorf("content length of cache %d is required", c.ID)
+	}
+	if ok, err := actions_model.IncreaseCacheUploadedSize(ctx, c.ID, size, setting.Actions.CacheMaxSize); err != nil {
+		return err
+	} else if !ok {
+		return util.NewInvalidArgumentErrorf("cache %d exceeds the size limit %d", c.ID, setting.Actions.CacheMaxSize)
+	}
+
+	if err := saveCacheObjectContent(p, r, size); err != nil {
+		if _, err := actions_model.IncreaseCacheUploadedSize(ctx, c.ID, -size, -1); err != nil {
+			log.Error("Failed to decrease the uploaded size of cache %d: %v", c.ID, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func saveCacheObjectContent(p string, r io.Reader, size int64) error {
+	written, err := storage.ActionsCache.Save(p, r, size)
+	if err != nil {
+		return err
+	}
+	if written != size {
+		if err := storage.ActionsCache.Delete(p); err != nil {
+			log.Error("Failed to delete cache object %s: %v", p, err)
+		}
+		return util.NewInvalidArgumentErrorf("written size %d doesn't match content size %d", written, size)
+	}
+	return nil
+}
+
+type cacheChunk struct {
+	Path  string
+	Start int64
+	End   int64
+}
+
+// listCacheChunks returns the uploaded chunks of the cache ordered by the offsets, they must be contiguous
+func listCacheChunks(c *actions_model.ActionCache) ([]string, error) {
+	dir := c.ChunksPath()
+	var chunks []*cacheChunk
+	if err := storage.ActionsCache.IterateObjects(dir, func(fpath string, obj storage.Object) error {
+		// the path only contains the storage dir and the basename, no matter the subdirectory setting in storage config
+		chunk := &cacheChunk{Path: dir + "/" + filepath.Base(fpath)}
+		if _, err := fmt.Sscanf(filepath.Base(fpath), "%d-%d.chunk", &chunk.Start, &chunk.End); err != nil {
+			return nil
+		}
+		chunks = append(chunks, chunk)
+		return nil
+	}); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].Start < chunks[j].Start
+	})
+
+	paths := make([]string, 0, len(chunks))
+	next := int64(0)
+	for _, chunk := range chunks {
+		if chunk.Start != next {
+			return nil, util.NewInvalidArgumentErrorf("missing content of cache %d at offset %d", c.ID, next)
+		}
+		paths = append(paths, chunk.Path)
+		next = chunk.End + 1
+	}
+	return paths, nil
+}
+
+// mergeCacheObjects concatenates the objects to the storage path of the cache
+func mergeCacheObjects(c *actions_model.ActionCache, paths []string) error {
+	readers := make([]io.Reader, 0, len(paths))
+	defer func() {
+		for _, r := range readers {
+			_ = r.(io.Closer).Close()
+		}
+	}()
+	for _, p := range paths {
+		f, err := storage.ActionsCache.Open(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return util.NewInvalidArgumentErrorf("missing content %s of cache %d", p, c.ID)
+			}
+			return err
+		}
+		readers = append(readers, f)
+	}
+	_, err := storage.ActionsCache.Save(c.StoragePath(), io.MultiReader(readers...), -1)
+	return err
+}
+
+// CommitCacheBlocks merges the uploaded blocks of the cache in the order of the block IDs
+func CommitCacheBlocks(c *actions_model.ActionCache, blockIDs []string) error {
+	paths := make([]string, 0, len(blockIDs))
+	for _, id := range blockIDs {
+		paths = append(paths, fmt.Sprintf("%s/block-%s", c.ChunksPath(), base64.URLEncoding.EncodeToString([]byte(id))))
+	}
+	return mergeCacheObjects(c, paths)
+}
+
+// CommitCache makes the uploaded content of the cache available to restore, the chunks are merged if there are any.
+// The size is checked if it's positive, and the least recently used caches of the repository are evicted
+// if the total size of the caches exceeds the limit.
+func CommitCache(ctx context.Context, c *actions_model.ActionCache, size int64) error {
+	paths, err := listCacheChunks(c)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 0 {
+		if err := mergeCacheObjects(c, paths); err != nil {
+			return err
+		}
+	}
+	removeCacheChunks(c)
+
+	info, err := storage.ActionsCache.Stat(c.StoragePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return util.NewInvalidArgumentErrorf("no content of cache %d is uploaded", c.ID)
+		}
+		return err
+	}
+	if size > 0 && info.Size() != size {
+		return util.NewInvalidArgumentErrorf("uploaded size %d of cache %d doesn't match size %d", info.Size(), c.ID, size)
+	}
+	if setting.Actions.CacheMaxSize >= 0 && info.Size() > setting.Actions.CacheMaxSize {
+		if err := DeleteCache(ctx, c); err != nil {
+			return err
+		}
+		return util.NewInvalidArgumentErrorf("cache size %d exceeds the limit %d", info.Size(), setting.Actions.CacheMaxSize)
+	}
+
+	c.Size = info.Size()
+	c.Complete = true
+	c.UsedUnix = timeutil.TimeStampNow()
+	if err := actions_model.UpdateCache(ctx, c, "size", "complete", "used_unix"); err != nil {
+		return err
+	}
+	return evictCaches(ctx, c.RepoID)
+}
+
+// evictCaches deletes the least recently used caches of the repository until the total size doesn't exceed the limit
+func evictCaches(ctx context.Context, repoID int64) error {
+	if setting.Actions.CacheMaxSize < 0 {
+		return nil
+	}
+	total, err := actions_model.GetCachesTotalSize(ctx, repoID)
+	if err != nil {
+		return err
+	}
+	if total <= setting.Actions.CacheMaxSize {
+		return nil
+	}
+
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCachesOptions{
+		RepoID:   repoID,
+		Complete: optional.Some(true),
+	})
+	if err != nil {
+		return err
+	}
+	for _, c := range caches {
+		if total <= setting.Actions.CacheMaxSize {
+			break
+		}
+		if err := DeleteCache(ctx, c); err != nil {
+			return err
+		}
+		total -= c.Size
+		log.Trace("Evicted cache %d of repo %d", c.ID, repoID)
+	}
+	return nil
+}
+
+// DeleteCache deletes a cache with its content
+func DeleteCache(ctx context.Context, c *actions_model.ActionCache) error {
+	if err := actions_model.DeleteCacheByID(ctx, c.ID); err != nil {
+		return err
+	}
+	RemoveCacheFiles(c)
+	return nil
+}
+
+// RemoveCacheFiles removes the content and the uploading chunks of a cache from the storage
+func RemoveCacheFiles(c *actions_model.ActionCache) {
+	if err := storage.ActionsCache.Delete(c.StoragePath()); err != nil {
+		log.Error("Failed to delete cache %d: %v", c.ID, err)
+	}
+	removeCacheChunks(c)
+}
+
+func removeCacheChunks(c *actions_model.ActionCache) {
+	dir := c.ChunksPath()
+	if err := storage.ActionsCache.IterateObjects(dir, func(fpath string, obj storage.Object) error {
+		return storage.ActionsCache.Delete(dir + "/" + filepath.Base(fpath))
+	}); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error("Failed to delete chunks of cache %d: %v", c.ID, err)
+	}
+}
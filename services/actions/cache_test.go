@@ -0,0 +1,74 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUploadingCache(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	// the run of task 48 is triggered by a push to refs/heads/master of repo 4
+	task := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionTask{ID: 48})
+
+	insert := func(repoID int64, scope string, complete bool) *actions_model.ActionCache {
+		c := &actions_model.ActionCache{RepoID: repoID, Scope: scope, CacheKey: "linux-go", CacheVersion: "v1", Complete: complete}
+		require.NoError(t, db.Insert(db.DefaultContext, c))
+		return c
+	}
+
+	c := insert(4, "refs/heads/master", false)
+	got, err := GetUploadingCacheByID(db.DefaultContext, task, c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, c.ID, got.ID)
+
+	_, err = GetUploadingCacheByID(db.DefaultContext, task, insert(4, "refs/heads/feature", false).ID)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+	_, err = GetUploadingCacheByID(db.DefaultContext, task, insert(4, "refs/pull/1/head", false).ID)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+	_, err = GetUploadingCacheByID(db.DefaultContext, task, insert(1, "refs/heads/master", false).ID)
+	assert.ErrorIs(t, err, util.ErrNotExist)
+	_, err = GetUploadingCacheByID(db.DefaultContext, task, insert(4, "refs/heads/master", true).ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestUploadCacheSizeLimit(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	cacheStorage, err := storage.NewLocalStorage(db.DefaultContext, &setting.Storage{Path: t.TempDir()})
+	require.NoError(t, err)
+	defer test.MockVariableValue(&storage.ActionsCache, cacheStorage)()
+	defer test.MockVariableValue(&setting.Actions.CacheMaxSize, 10)()
+
+	c := &actions_model.ActionCache{RepoID: 4, Scope: "refs/heads/master", CacheKey: "linux-go", CacheVersion: "v1"}
+	require.NoError(t, db.Insert(db.DefaultContext, c))
+
+	require.NoError(t, UploadCacheChunk(db.DefaultContext, c, 0, strings.NewReader("AAAAAA"), 6))
+	// the size declared when reserving doesn't limit the uploaded chunks
+	err = UploadCacheChunk(db.DefaultContext, c, 6, strings.NewReader("BBBBBB"), 6)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	// the content length is required
+	err = UploadCacheBlock(db.DefaultContext, c, "1", strings.NewReader("BB"), -1)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	// a chunk which isn't saved isn't counted
+	err = UploadCacheChunk(db.DefaultContext, c, 6, strings.NewReader("BB"), 4)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	require.NoError(t, UploadCacheChunk(db.DefaultContext, c, 6, strings.NewReader("BBBB"), 4))
+
+	require.NoError(t, CommitCache(db.DefaultContext, c, 10))
+	assert.EqualValues(t, 10, c.Size)
+}
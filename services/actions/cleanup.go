@@ -9,20 +9,27 @@ import (
 	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
 )
 
-// Cleanup removes expired actions logs, data and artifacts
+// Cleanup removes expired actions logs, data, artifacts and caches
 func Cleanup(ctx context.Context) error {
 	// clean up expired artifacts
 	if err := CleanupArtifacts(ctx); err != nil {
 		return fmt.Errorf("cleanup artifacts: %w", err)
 	}
 
+	// clean up unused caches
+	if err := CleanupCaches(ctx); err != nil {
+		return fmt.Errorf("cleanup caches: %w", err)
+	}
+
 	// clean up old logs
 	if err := CleanupLogs(ctx); err != nil {
 		return fmt.Errorf("cleanup logs: %w", err)
@@ -88,6 +95,47 @@ func cleanNeedDeleteArtifacts(taskCtx context.Context) error {
 	return nil
 }
 
+// cacheReservationTimeout is the time to wait for the upload of a reserved cache before it's deleted
+const cacheReservationTimeout = 24 * time.Hour
+
+// deleteCacheBatchSize is the batch size of deleting caches
+const deleteCacheBatchSize = 100
+
+// CleanupCaches removes the caches which haven't been used for the retention period and the abandoned uploads
+func CleanupCaches(ctx context.Context) error {
+	now := time.Now()
+	for _, opts := range []actions_model.FindCachesOptions{
+		{
+			Complete:   optional.Some(true),
+			UsedBefore: timeutil.TimeStamp(now.Add(-time.Duration(setting.Actions.CacheRetentionDays) * 24 * time.Hour).Unix()),
+		},
+		{
+			Complete:     optional.Some(false),
+			CreateBefore: timeutil.TimeStamp(now.Add(-cacheReservationTimeout).Unix()),
+		},
+	} {
+		opts.ListOptions = db.ListOptions{PageSize: deleteCacheBatchSize}
+		count := 0
+		for {
+			caches, err := db.Find[actions_model.ActionCache](ctx, opts)
+			if err != nil {
+				return err
+			}
+			for _, c := range caches {
+				if err := DeleteCache(ctx, c); err != nil {
+					return err
+				}
+			}
+			count += len(caches)
+			if len(caches) < deleteCacheBatchSize {
+				break
+			}
+		}
+		log.Info("Removed %d caches", count)
+	}
+	return nil
+}
+
 const deleteLogBatchSize = 100
 
 // CleanupLogs removes logs which are older than the configured retention time
@@ -68,6 +68,8 @@ func stopTasks(ctx context.Context, opts actions_model.FindTaskOptions) error {
 	}
 
 	CreateCommitStatus(ctx, jobs...)
+	NotifyWorkflowJobsStatusUpdate(ctx, jobs...)
+	emitRunsOfStoppedJobs(jobs...)
 
 	return nil
 }
@@ -96,6 +98,8 @@ func CancelAbandonedJobs(ctx context.Context) error {
 		}
 		CreateCommitStatus(ctx, job)
 	}
+	NotifyWorkflowJobsStatusUpdate(ctx, jobs...)
+	emitRunsOfStoppedJobs(jobs...)
 
 	return nil
 }
@@ -73,6 +73,9 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 	case webhook_module.HookEventRelease:
 		event = string(run.Event)
 		sha = run.CommitSHA
+	case webhook_module.HookEventMergeGroup:
+		event = string(run.Event)
+		sha = run.CommitSHA
 	default:
 		return nil
 	}
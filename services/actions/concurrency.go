@@ -0,0 +1,293 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// rawConcurrency is the `concurrency` setting of a workflow or a job before evaluating expressions,
+// see https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#concurrency
+type rawConcurrency struct {
+	Group            string `yaml:"group"`
+	CancelInProgress string `yaml:"cancel-in-progress"`
+}
+
+// UnmarshalYAML supports both `concurrency: group` and `concurrency: {group: group, cancel-in-progress: true}`
+func (c *rawConcurrency) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		c.Group = node.Value
+		return nil
+	}
+	type plain rawConcurrency
+	return node.Decode((*plain)(c))
+}
+
+// rawWorkflow holds the `concurrency` and `environment` settings of a workflow file, which are dropped by jobparser
+type rawWorkflow struct {
+	Concurrency *rawConcurrency `yaml:"concurrency"`
+	Jobs        map[string]struct {
+		Concurrency *rawConcurrency `yaml:"concurrency"`
+		Environment *rawEnvironment `yaml:"environment"`
+	} `yaml:"jobs"`
+}
+
+func parseRawWorkflow(content []byte) (*rawWorkflow, error) {
+	raw := &rawWorkflow{}
+	if err := yaml.Unmarshal(content, raw); err != nil {
+		return nil, fmt.Errorf("parse workflow: %w", err)
+	}
+	return raw, nil
+}
+
+// evaluateRunJobOptions evaluates the concurrency and the environment of a job of the workflow
+func evaluateRunJobOptions(raw *rawWorkflow, gitCtx *model.GithubContext, vars map[string]string, swf *jobparser.SingleWorkflow) *actions_model.RunJobOptions {
+	opts := &actions_model.RunJobOptions{ParentIndex: -1}
+	id, job := swf.Job()
+	rawJob, ok := raw.Jobs[id]
+	if !ok {
+		return opts
+	}
+	if rawJob.Concurrency != nil {
+		opts.Concurrency = evaluateConcurrency(rawJob.Concurrency, gitCtx, vars, id, decodeJobMatrix(job))
+	}
+	if rawJob.Environment != nil {
+		opts.Environment = evaluateEnvironment(rawJob.Environment, gitCtx, vars, id, decodeJobMatrix(job))
+	}
+	return opts
+}
+
+// evaluateConcurrency evaluates the expressions in the concurrency setting with the github, vars and matrix contexts
+func evaluateConcurrency(raw *rawConcurrency, gitCtx *model.GithubContext, vars map[string]string, jobID string, matrix map[string]any) *actions_model.Concurrency {
+	results := map[string]*jobparser.JobResult{jobID: {}}
+	evaluator := jobparser.NewExpressionEvaluator(jobparser.NewInterpeter(jobID, &model.Job{}, matrix, gitCtx, results, vars))
+
+	cancel, _ := strconv.ParseBool(strings.TrimSpace(evaluator.Interpolate(raw.CancelInProgress)))
+	return &actions_model.Concurrency{
+		Group:            strings.TrimSpace(evaluator.Interpolate(raw.Group)),
+		CancelInProgress: cancel,
+	}
+}
+
+// generateGitContext generates the github context of the run which is available when evaluating concurrency groups
+func generateGitContext(run *actions_model.ActionRun) *model.GithubContext {
+	event := map[string]any{}
+	_ = json.Unmarshal([]byte(run.EventPayload), &event)
+
+	baseRef := ""
+	headRef := ""
+	ref := run.Ref
+	sha := run.CommitSHA
+	if pullPayload, err := run.GetPullRequestEventPayload(); err == nil && pullPayload.PullRequest != nil && pullPayload.PullRequest.Base != nil && pullPayload.PullRequest.Head != nil {
+		baseRef = pullPayload.PullRequest.Base.Ref
+		headRef = pullPayload.PullRequest.Head.Ref
+		if run.TriggerEvent == actions_module.GithubEventPullRequestTarget {
+			ref = git.BranchPrefix + pullPayload.PullRequest.Base.Name
+			sha = pullPayload.PullRequest.Base.Sha
+		}
+	}
+	refName := git.RefName(ref)
+
+	gitCtx := &model.GithubContext{
+		Event:     event,
+		EventName: run.TriggerEvent,
+		Workflow:  run.WorkflowID,
+		Sha:       sha,
+		Ref:       ref,
+		RefName:   refName.ShortName(),
+		RefType:   refName.RefType(),
+		HeadRef:   headRef,
+		BaseRef:   baseRef,
+		ServerURL: setting.AppURL,
+		APIURL:    setting.AppURL + "api/v1",
+	}
+	if run.Repo != nil {
+		gitCtx.Repository = run.Repo.OwnerName + "/" + run.Repo.Name
+		gitCtx.RepositoryOwner = run.Repo.OwnerName
+	}
+	if run.TriggerUser != nil {
+		gitCtx.Actor = run.TriggerUser.Name
+	}
+	return gitCtx
+}
+
+// decodeJobMatrix returns the matrix of a job parsed by jobparser, which has one value for each key at most
+func decodeJobMatrix(job *jobparser.Job) map[string]any {
+	var values map[string][]any
+	if err := job.Strategy.RawMatrix.Decode(&values); err != nil {
+		return nil
+	}
+	matrix := make(map[string]any, len(values))
+	for k, v := range values {
+		if len(v) > 0 {
+			matrix[k] = v[0]
+		}
+	}
+	return matrix
+}
+
+// InsertRun evaluates the workflow-level and job-level concurrency and the environments of the run, expands the jobs calling reusable workflows,
+// then inserts the run and its jobs.
+// content is the workflow file which the jobs are parsed from.
+// If the workflow-level concurrency cancels runs in progress, the other runs in the group are cancelled,
+// otherwise the run is blocked until the group is not in use, and the former pending run of the group is cancelled.
+// The status updates of the cancelled jobs, the new run and its jobs are notified after inserting.
+func InsertRun(ctx context.Context, run *actions_model.ActionRun, content []byte, jobs []*jobparser.SingleWorkflow, vars map[string]string) error {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+
+	raw, err := parseRawWorkflow(content)
+	if err != nil {
+		return err
+	}
+	gitCtx := generateGitContext(run)
+
+	if raw.Concurrency != nil {
+		concurrency := evaluateConcurrency(raw.Concurrency, gitCtx, vars, "", nil)
+		run.ConcurrencyGroup = concurrency.Group
+		run.ConcurrencyCancel = concurrency.CancelInProgress
+	}
+
+	jobOpts := make([]*actions_model.RunJobOptions, len(jobs))
+	for i, swf := range jobs {
+		jobOpts[i] = evaluateRunJobOptions(raw, gitCtx, vars, swf)
+	}
+
+	jobs, jobOpts, err = expandReusableWorkflows(ctx, run, gitCtx, vars, jobs, jobOpts)
+	if err != nil {
+		return err
+	}
+
+	var cancelledJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if run.ConcurrencyGroup != "" {
+			if run.ConcurrencyCancel {
+				cancelled, err := actions_model.CancelConcurrentRuns(ctx, run.RepoID, run.ConcurrencyGroup, []actions_model.Status{actions_model.StatusWaiting, actions_model.StatusRunning, actions_model.StatusBlocked})
+				if err != nil {
+					return err
+				}
+				cancelledJobs = append(cancelledJobs, cancelled...)
+			} else {
+				// only one run of a group can be pending, the former pending one is replaced by this one
+				cancelled, err := actions_model.CancelConcurrentRuns(ctx, run.RepoID, run.ConcurrencyGroup, []actions_model.Status{actions_model.StatusBlocked})
+				if err != nil {
+					return err
+				}
+				cancelledJobs = append(cancelledJobs, cancelled...)
+				active, err := actions_model.HasActiveConcurrentRun(ctx, run.RepoID, run.ConcurrencyGroup)
+				if err != nil {
+					return err
+				}
+				if active {
+					run.Status = actions_model.StatusBlocked
+				}
+			}
+		}
+
+		cancelled, err := actions_model.InsertRun(ctx, run, jobs, jobOpts)
+		if err != nil {
+			return err
+		}
+		cancelledJobs = append(cancelledJobs, cancelled...)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+
+	NotifyWorkflowRunStatusUpdate(ctx, run)
+	runJobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		log.Error("FindRunJobs: %v", err)
+		return nil
+	}
+	NotifyWorkflowJobsStatusUpdate(ctx, runJobs...)
+
+	// the jobs calling reusable workflows and the jobs referencing environments are started by the job emitter
+	if len(actions_model.ActionJobList(runJobs).GetCallerIDs()) > 0 || actions_model.ActionJobList(runJobs).HasEnvironments() {
+		if err := EmitJobsIfReady(run.ID); err != nil {
+			log.Error("EmitJobsIfReady: %v", err)
+		}
+	}
+	return nil
+}
+
+// emitConcurrencyBlockedRuns emits the runs blocked by the concurrency groups which the run and its done jobs no longer use
+func emitConcurrencyBlockedRuns(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) error {
+	runGroup := ""
+	if run.ConcurrencyGroup != "" && run.Status.IsDone() {
+		active, err := actions_model.HasActiveConcurrentRun(ctx, run.RepoID, run.ConcurrencyGroup)
+		if err != nil {
+			return err
+		}
+		if !active {
+			runGroup = run.ConcurrencyGroup
+		}
+	}
+
+	jobGroups := make(container.Set[string])
+	for _, job := range jobs {
+		if job.ConcurrencyGroup == "" || !job.Status.IsDone() || jobGroups.Contains(job.ConcurrencyGroup) {
+			continue
+		}
+		active, err := actions_model.HasActiveConcurrentJob(ctx, run.RepoID, job.ConcurrencyGroup)
+		if err != nil {
+			return err
+		}
+		if !active {
+			jobGroups.Add(job.ConcurrencyGroup)
+		}
+	}
+
+	if runGroup == "" && len(jobGroups) == 0 {
+		return nil
+	}
+
+	runIDs, err := actions_model.GetBlockedConcurrentRunIDs(ctx, run.RepoID, runGroup, jobGroups.Values())
+	if err != nil {
+		return err
+	}
+	emitted := container.SetOf(run.ID)
+	for _, id := range runIDs {
+		if !emitted.Add(id) {
+			continue
+		}
+		if err := EmitJobsIfReady(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitRunsOfStoppedJobs emits the runs of the jobs which are stopped outside of the job emitter,
+// so the runs blocked by the concurrency groups of the jobs and their runs can be started
+func emitRunsOfStoppedJobs(jobs ...*actions_model.ActionRunJob) {
+	runIDs := make(container.Set[int64])
+	for _, job := range jobs {
+		if !runIDs.Add(job.RunID) {
+			continue
+		}
+		if err := EmitJobsIfReady(job.RunID); err != nil {
+			log.Error("EmitJobsIfReady: %v", err)
+		}
+	}
+}
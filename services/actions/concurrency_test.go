@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateConcurrency(t *testing.T) {
+	content := []byte(`
+name: test
+on: push
+concurrency: deploy-${{ github.ref }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    strategy:
+      matrix:
+        os: [linux, windows]
+    concurrency:
+      group: build-${{ matrix.os }}-${{ vars.SUFFIX }}
+      cancel-in-progress: ${{ github.event_name == 'push' }}
+    steps:
+      - run: echo build
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo test
+`)
+	raw, err := parseRawWorkflow(content)
+	require.NoError(t, err)
+	require.NotNil(t, raw.Concurrency)
+	assert.Nil(t, raw.Jobs["test"].Concurrency)
+
+	gitCtx := &model.GithubContext{
+		EventName: "push",
+		Ref:       "refs/heads/main",
+	}
+	vars := map[string]string{"SUFFIX": "v1"}
+
+	assert.Equal(t, &actions_model.Concurrency{Group: "deploy-refs/heads/main"}, evaluateConcurrency(raw.Concurrency, gitCtx, vars, "", nil))
+
+	jobs, err := jobparser.Parse(content)
+	require.NoError(t, err)
+	var groups []string
+	for _, swf := range jobs {
+		id, job := swf.Job()
+		if id != "build" {
+			continue
+		}
+		concurrency := evaluateConcurrency(raw.Jobs[id].Concurrency, gitCtx, vars, id, decodeJobMatrix(job))
+		assert.True(t, concurrency.CancelInProgress)
+		groups = append(groups, concurrency.Group)
+	}
+	assert.ElementsMatch(t, []string{"build-linux-v1", "build-windows-v1"}, groups)
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	secret_model "code.gitea.io/gitea/models/secret"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// rawEnvironment is the `environment` setting of a job before evaluating expressions,
+// see https://docs.github.com/en/actions/writing-workflows/workflow-syntax-for-github-actions#jobsjob_idenvironment
+type rawEnvironment struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+}
+
+// UnmarshalYAML supports both `environment: name` and `environment: {name: name, url: url}`
+func (e *rawEnvironment) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Name = node.Value
+		return nil
+	}
+	type plain rawEnvironment
+	return node.Decode((*plain)(e))
+}
+
+// evaluateEnvironment evaluates the expressions in the environment name with the github, vars and matrix contexts
+func evaluateEnvironment(raw *rawEnvironment, gitCtx *model.GithubContext, vars map[string]string, jobID string, matrix map[string]any) string {
+	results := map[string]*jobparser.JobResult{jobID: {}}
+	evaluator := jobparser.NewExpressionEvaluator(jobparser.NewInterpeter(jobID, &model.Job{}, matrix, gitCtx, results, vars))
+
+	name, _ := util.SplitStringAtByteN(strings.TrimSpace(evaluator.Interpolate(raw.Name)), 255)
+	return name
+}
+
+// resolveDeployment resolves the status of a job referencing an environment which is ready to run.
+// The environment is created if it doesn't exist, and the deployment of the upcoming attempt of the job is created on the first call.
+// The job fails if the ref of the run can't deploy to the environment or the deployment is rejected,
+// it stays blocked until the deployment is approved if the environment has required reviewers.
+func resolveDeployment(ctx context.Context, run *actions_model.ActionRun, job *actions_model.ActionRunJob) (actions_model.Status, error) {
+	env, err := actions_model.GetEnvironmentByName(ctx, job.RepoID, job.Environment)
+	if errors.Is(err, util.ErrNotExist) {
+		env = &actions_model.ActionEnvironment{RepoID: job.RepoID, Name: job.Environment}
+		if err := actions_model.InsertEnvironment(ctx, env); err != nil {
+			return actions_model.StatusUnknown, err
+		}
+	} else if err != nil {
+		return actions_model.StatusUnknown, err
+	}
+
+	deployment, has, err := actions_model.GetDeploymentOfJob(ctx, job)
+	if err != nil {
+		return actions_model.StatusUnknown, err
+	}
+	if !has {
+		if !env.MatchRef(git.RefName(run.Ref)) {
+			log.Trace("ref %s of run %d can't deploy to environment %s", run.Ref, run.ID, env.Name)
+			return actions_model.StatusFailure, nil
+		}
+		deployment = &actions_model.ActionDeployment{
+			RepoID:        job.RepoID,
+			EnvironmentID: env.ID,
+			RunID:         job.RunID,
+			RunJobID:      job.ID,
+			Attempt:       job.Attempt + 1,
+			Ref:           run.Ref,
+			CommitSHA:     run.CommitSHA,
+			CreatorID:     run.TriggerUserID,
+			ReviewStatus:  actions_model.DeploymentReviewNotRequired,
+		}
+		if env.NeedReview() {
+			deployment.ReviewStatus = actions_model.DeploymentReviewPending
+		}
+		if err := db.Insert(ctx, deployment); err != nil {
+			return actions_model.StatusUnknown, err
+		}
+	}
+
+	switch deployment.ReviewStatus {
+	case actions_model.DeploymentReviewPending:
+		return actions_model.StatusBlocked, nil
+	case actions_model.DeploymentReviewRejected:
+		return actions_model.StatusFailure, nil
+	default:
+		return actions_model.StatusWaiting, nil
+	}
+}
+
+// ReviewDeployments approves or rejects the pending deployments of the run to the environments,
+// the doer must be a required reviewer of all the environments.
+func ReviewDeployments(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun, environmentIDs []int64, approve bool, comment string) ([]*actions_model.ActionDeployment, error) {
+	deployments, err := db.Find[actions_model.ActionDeployment](ctx, actions_model.FindDeploymentsOptions{
+		RepoID:         run.RepoID,
+		RunID:          run.ID,
+		EnvironmentIDs: environmentIDs,
+		ReviewStatus:   actions_model.DeploymentReviewPending,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deployments) == 0 {
+		return nil, util.NewNotExistErrorf("no pending deployments of run %d to the environments", run.ID)
+	}
+
+	status := actions_model.DeploymentReviewRejected
+	if approve {
+		status = actions_model.DeploymentReviewApproved
+	}
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		for _, d := range deployments {
+			if err := d.LoadAttributes(ctx); err != nil {
+				return err
+			}
+			if !d.Environment.IsReviewer(doer.ID) {
+				return util.NewPermissionDeniedErrorf("%s is not a required reviewer of environment %s", doer.Name, d.Environment.Name)
+			}
+			d.ReviewStatus = status
+			d.ReviewerID = doer.ID
+			d.ReviewComment = comment
+			if updated, err := actions_model.UpdateDeploymentReview(ctx, d); err != nil {
+				return err
+			} else if !updated {
+				return util.NewInvalidArgumentErrorf("deployment %d is not pending", d.ID)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := EmitJobsIfReady(run.ID); err != nil {
+		log.Error("EmitJobsIfReady: %v", err)
+	}
+	return deployments, nil
+}
+
+// validateEnvironment checks the protection rules of an environment,
+// the required reviewers must be able to read the actions of the repository.
+func validateEnvironment(ctx context.Context, repo *repo_model.Repository, env *actions_model.ActionEnvironment) error {
+	for _, pattern := range env.DeploymentBranches {
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			return util.NewInvalidArgumentErrorf("invalid deployment branch pattern %q: %v", pattern, err)
+		}
+	}
+	if len(env.ReviewerIDs) == 0 {
+		return nil
+	}
+	reviewers, err := user_model.GetUsersByIDs(ctx, env.ReviewerIDs)
+	if err != nil {
+		return err
+	}
+	if len(reviewers) != len(env.ReviewerIDs) {
+		return util.NewInvalidArgumentErrorf("some reviewers don't exist")
+	}
+	for _, reviewer := range reviewers {
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, reviewer)
+		if err != nil {
+			return err
+		}
+		if !perm.CanRead(unit.TypeActions) {
+			return util.NewInvalidArgumentErrorf("reviewer %s can't access the actions of the repository", reviewer.Name)
+		}
+	}
+	return nil
+}
+
+// CreateEnvironment creates an environment of the repository
+func CreateEnvironment(ctx context.Context, repo *repo_model.Repository, name string, deploymentBranches []string, reviewerIDs []int64) (*actions_model.ActionEnvironment, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 255 || strings.ContainsAny(name, "/,") {
+		return nil, util.NewInvalidArgumentErrorf("invalid environment name %q", name)
+	}
+	if _, err := actions_model.GetEnvironmentByName(ctx, repo.ID, name); err == nil {
+		return nil, util.NewAlreadyExistErrorf("environment %s already exists", name)
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+
+	env := &actions_model.ActionEnvironment{
+		RepoID:             repo.ID,
+		Name:               name,
+		DeploymentBranches: deploymentBranches,
+		ReviewerIDs:        reviewerIDs,
+	}
+	if err := validateEnvironment(ctx, repo, env); err != nil {
+		return nil, err
+	}
+	return env, actions_model.InsertEnvironment(ctx, env)
+}
+
+// UpdateEnvironment updates the protection rules of an environment,
+// the pending deployments to the environment aren't affected.
+func UpdateEnvironment(ctx context.Context, repo *repo_model.Repository, env *actions_model.ActionEnvironment, deploymentBranches []string, reviewerIDs []int64) error {
+	env.DeploymentBranches = deploymentBranches
+	env.ReviewerIDs = reviewerIDs
+	if err := validateEnvironment(ctx, repo, env); err != nil {
+		return err
+	}
+	return actions_model.UpdateEnvironment(ctx, env)
+}
+
+// DeleteEnvironment deletes an environment with its secrets, variables and deployment history
+func DeleteEnvironment(ctx context.Context, env *actions_model.ActionEnvironment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("repo_id=? AND environment_id=?", env.RepoID, env.ID).Delete(new(secret_model.Secret)); err != nil {
+			return err
+		}
+		return actions_model.DeleteEnvironment(ctx, env)
+	})
+}
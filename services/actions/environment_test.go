@@ -0,0 +1,94 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateRunJobEnvironment(t *testing.T) {
+	content := []byte(`
+name: test
+on: push
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+  staging:
+    runs-on: ubuntu-latest
+    environment: staging-${{ github.ref_name }}
+    steps:
+      - run: echo deploy
+  production:
+    runs-on: ubuntu-latest
+    environment:
+      name: ${{ vars.PROD_ENV }}
+      url: https://example.com
+    steps:
+      - run: echo deploy
+`)
+	raw, err := parseRawWorkflow(content)
+	require.NoError(t, err)
+
+	gitCtx := &model.GithubContext{
+		EventName: "push",
+		Ref:       "refs/heads/main",
+		RefName:   "main",
+	}
+	vars := map[string]string{"PROD_ENV": "production"}
+
+	jobs, err := jobparser.Parse(content)
+	require.NoError(t, err)
+	environments := map[string]string{}
+	for _, swf := range jobs {
+		id, _ := swf.Job()
+		opts := evaluateRunJobOptions(raw, gitCtx, vars, swf)
+		assert.Equal(t, -1, opts.ParentIndex)
+		environments[id] = opts.Environment
+	}
+	assert.Equal(t, map[string]string{
+		"build":      "",
+		"staging":    "staging-main",
+		"production": "production",
+	}, environments)
+}
+
+func TestResolveDeploymentBranches(t *testing.T) {
+	require.NoError(t, unittest.PrepareTestDatabase())
+
+	env := &actions_model.ActionEnvironment{RepoID: 1, Name: "production", DeploymentBranches: []string{"main"}}
+	require.NoError(t, actions_model.InsertEnvironment(db.DefaultContext, env))
+
+	cases := []struct {
+		ref    string
+		status actions_model.Status
+	}{
+		{ref: "refs/heads/main", status: actions_model.StatusWaiting},
+		{ref: "refs/heads/feature", status: actions_model.StatusFailure},
+		// a tag named like the allowed branch can't deploy
+		{ref: "refs/tags/main", status: actions_model.StatusFailure},
+		{ref: "refs/pull/main", status: actions_model.StatusFailure},
+	}
+	for i, c := range cases {
+		run := &actions_model.ActionRun{ID: int64(100 + i), RepoID: 1, Ref: c.ref}
+		job := &actions_model.ActionRunJob{ID: int64(100 + i), RunID: run.ID, RepoID: 1, Environment: env.Name}
+		status, err := resolveDeployment(db.DefaultContext, run, job)
+		require.NoError(t, err)
+		assert.Equal(t, c.status, status, "ref %s", c.ref)
+
+		_, has, err := actions_model.GetDeploymentOfJob(db.DefaultContext, job)
+		require.NoError(t, err)
+		assert.Equal(t, c.status == actions_model.StatusWaiting, has, "ref %s", c.ref)
+	}
+}
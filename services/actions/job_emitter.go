@@ -7,13 +7,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/timeutil"
 
+	"github.com/nektos/act/pkg/exprparser"
 	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
 	"xorm.io/builder"
 )
 
@@ -45,25 +51,97 @@ func jobEmitterQueueHandler(items ...*jobUpdate) []*jobUpdate {
 }
 
 func checkJobsOfRun(ctx context.Context, runID int64) error {
+	run, err := actions_model.GetRunByID(ctx, runID)
+	if err != nil {
+		return err
+	}
+	// the jobs of a run waiting for approval can't be emitted
+	if run.NeedApproval {
+		return nil
+	}
+
 	jobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: runID})
 	if err != nil {
 		return err
 	}
+	var updatedJobs, cancelledJobs []*actions_model.ActionRunJob
+	var deploymentFailed bool
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		// the run is blocked by its concurrency group until the other runs in the group are done
+		if run.Status == actions_model.StatusBlocked && run.ConcurrencyGroup != "" {
+			if active, err := actions_model.HasActiveConcurrentRun(ctx, run.RepoID, run.ConcurrencyGroup); err != nil {
+				return err
+			} else if active {
+				return nil
+			}
+		}
+
 		idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
 		for _, job := range jobs {
 			idToJobs[job.JobID] = append(idToJobs[job.JobID], job)
 		}
 
-		updates := newJobStatusResolver(jobs).Resolve()
+		resolver := newJobStatusResolver(jobs)
+		callerIDs := actions_model.ActionJobList(jobs).GetCallerIDs()
+		if len(callerIDs) > 0 {
+			if err := run.LoadAttributes(ctx); err != nil {
+				return err
+			}
+			vars, err := actions_model.GetVariablesOfRun(ctx, run)
+			if err != nil {
+				return err
+			}
+			resolver.gitCtx = generateGitContext(run)
+			resolver.vars = vars
+		}
+		updates := resolver.Resolve()
+		usedGroups := make(container.Set[string])
 		for _, job := range jobs {
 			if status, ok := updates[job.ID]; ok {
+				// the job referencing an environment is blocked until its deployment is approved
+				if status == actions_model.StatusWaiting && job.Environment != "" {
+					if status, err = resolveDeployment(ctx, run, job); err != nil {
+						return err
+					}
+					if status == actions_model.StatusBlocked {
+						continue
+					}
+					deploymentFailed = deploymentFailed || status == actions_model.StatusFailure
+				}
+				if status == actions_model.StatusWaiting && job.ConcurrencyGroup != "" {
+					concurrency := &actions_model.Concurrency{Group: job.ConcurrencyGroup, CancelInProgress: job.ConcurrencyCancel}
+					blocked, cancelled, err := actions_model.BlockedByJobConcurrency(ctx, job.RepoID, job.RunID, concurrency, usedGroups)
+					if err != nil {
+						return err
+					}
+					cancelledJobs = append(cancelledJobs, cancelled...)
+					if blocked {
+						continue
+					}
+				}
+				oldStatus := job.Status
 				job.Status = status
-				if n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": actions_model.StatusBlocked}, "status"); err != nil {
+				cols := []string{"status"}
+				if status == actions_model.StatusFailure && job.Environment != "" && !callerIDs.Contains(job.ID) {
+					// the job failed by its deployment protection rules has no task
+					job.Stopped = timeutil.TimeStampNow()
+					cols = append(cols, "stopped")
+				} else if callerIDs.Contains(job.ID) {
+					// the job calling a reusable workflow has no task, so its duration is recorded here
+					if status == actions_model.StatusRunning {
+						job.Started = timeutil.TimeStampNow()
+						cols = append(cols, "started")
+					} else if status.IsDone() && !job.Started.IsZero() {
+						job.Stopped = timeutil.TimeStampNow()
+						cols = append(cols, "stopped")
+					}
+				}
+				if n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": oldStatus}, cols...); err != nil {
 					return err
 				} else if n != 1 {
-					return fmt.Errorf("no affected for updating blocked job %v", job.ID)
+					return fmt.Errorf("no affected for updating %s job %v", oldStatus, job.ID)
 				}
+				updatedJobs = append(updatedJobs, job)
 			}
 		}
 		return nil
@@ -71,36 +149,66 @@ func checkJobsOfRun(ctx context.Context, runID int64) error {
 		return err
 	}
 	CreateCommitStatus(ctx, jobs...)
-	return nil
+	NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+	NotifyWorkflowJobsStatusUpdate(ctx, updatedJobs...)
+
+	// the jobs needing the jobs failed by their deployments are resolved in the next round
+	if deploymentFailed {
+		if err := EmitJobsIfReady(runID); err != nil {
+			log.Error("EmitJobsIfReady: %v", err)
+		}
+	}
+
+	// reload the run since its status may be updated with the jobs
+	if run, err = actions_model.GetRunByID(ctx, runID); err != nil {
+		return err
+	}
+	return emitConcurrencyBlockedRuns(ctx, run, jobs)
 }
 
 type jobStatusResolver struct {
 	statuses map[int64]actions_model.Status
 	needs    map[int64][]int64
+	children map[int64][]int64
 	jobMap   map[int64]*actions_model.ActionRunJob
+
+	// gitCtx and vars are used to evaluate the `if` of the jobs calling reusable workflows, which aren't run by runners
+	gitCtx *model.GithubContext
+	vars   map[string]string
 }
 
 func newJobStatusResolver(jobs actions_model.ActionJobList) *jobStatusResolver {
-	idToJobs := make(map[string][]*actions_model.ActionRunJob, len(jobs))
+	// the needs of a job are the jobs expanded from the same reusable workflow, or the top-level jobs
+	type jobKey struct {
+		ParentJobID int64
+		JobID       string
+	}
+	idToJobs := make(map[jobKey][]*actions_model.ActionRunJob, len(jobs))
 	jobMap := make(map[int64]*actions_model.ActionRunJob)
 	for _, job := range jobs {
-		idToJobs[job.JobID] = append(idToJobs[job.JobID], job)
+		key := jobKey{ParentJobID: job.ParentJobID, JobID: job.JobID}
+		idToJobs[key] = append(idToJobs[key], job)
 		jobMap[job.ID] = job
 	}
 
 	statuses := make(map[int64]actions_model.Status, len(jobs))
 	needs := make(map[int64][]int64, len(jobs))
+	children := make(map[int64][]int64)
 	for _, job := range jobs {
 		statuses[job.ID] = job.Status
 		for _, need := range job.Needs {
-			for _, v := range idToJobs[need] {
+			for _, v := range idToJobs[jobKey{ParentJobID: job.ParentJobID, JobID: need}] {
 				needs[job.ID] = append(needs[job.ID], v.ID)
 			}
 		}
+		if job.ParentJobID != 0 {
+			children[job.ParentJobID] = append(children[job.ParentJobID], job.ID)
+		}
 	}
 	return &jobStatusResolver{
 		statuses: statuses,
 		needs:    needs,
+		children: children,
 		jobMap:   jobMap,
 	}
 }
@@ -123,9 +231,26 @@ func (r *jobStatusResolver) Resolve() map[int64]actions_model.Status {
 func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 	ret := map[int64]actions_model.Status{}
 	for id, status := range r.statuses {
+		if status == actions_model.StatusRunning && len(r.children[id]) > 0 {
+			if childrenStatus, ok := r.resolveChildren(id); ok {
+				ret[id] = childrenStatus
+			}
+			continue
+		}
 		if status != actions_model.StatusBlocked {
 			continue
 		}
+		if parentID := r.jobMap[id].ParentJobID; parentID != 0 {
+			parentStatus := r.statuses[parentID]
+			if parentStatus.IsDone() {
+				// the calling job has been skipped or cancelled
+				ret[id] = actions_model.StatusSkipped
+				continue
+			}
+			if parentStatus != actions_model.StatusRunning {
+				continue
+			}
+		}
 		allDone, allSucceed := true, true
 		for _, need := range r.needs[id] {
 			needStatus := r.statuses[need]
@@ -137,7 +262,14 @@ func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 			}
 		}
 		if allDone {
-			if allSucceed {
+			if len(r.children[id]) > 0 {
+				// the job calling a reusable workflow isn't run by runners, it's running until the jobs expanded from it are done
+				if r.shouldRunCaller(id, allSucceed) {
+					ret[id] = actions_model.StatusRunning
+				} else {
+					ret[id] = actions_model.StatusSkipped
+				}
+			} else if allSucceed {
 				ret[id] = actions_model.StatusWaiting
 			} else {
 				// Check if the job has an "if" condition
@@ -160,3 +292,70 @@ func (r *jobStatusResolver) resolve() map[int64]actions_model.Status {
 	}
 	return ret
 }
+
+// resolveChildren returns the status of a job calling a reusable workflow according to the jobs expanded from it,
+// it returns false if any of them is not done.
+func (r *jobStatusResolver) resolveChildren(id int64) (actions_model.Status, bool) {
+	hasFailure, hasCancelled, allSkipped := false, false, true
+	for _, child := range r.children[id] {
+		status := r.statuses[child]
+		if !status.IsDone() {
+			return actions_model.StatusUnknown, false
+		}
+		switch status {
+		case actions_model.StatusFailure:
+			hasFailure = true
+		case actions_model.StatusCancelled:
+			hasCancelled = true
+		}
+		if status != actions_model.StatusSkipped {
+			allSkipped = false
+		}
+	}
+	switch {
+	case hasFailure:
+		return actions_model.StatusFailure, true
+	case hasCancelled:
+		return actions_model.StatusCancelled, true
+	case allSkipped:
+		return actions_model.StatusSkipped, true
+	default:
+		return actions_model.StatusSuccess, true
+	}
+}
+
+// shouldRunCaller evaluates the `if` of a job calling a reusable workflow with the results of its needs,
+// the job runs if all needs succeed when the `if` is empty.
+func (r *jobStatusResolver) shouldRunCaller(id int64, allSucceed bool) bool {
+	job := r.jobMap[id]
+	wfJobs, err := jobparser.Parse(job.WorkflowPayload)
+	if err != nil || len(wfJobs) != 1 {
+		return allSucceed
+	}
+	_, wfJob := wfJobs[0].Job()
+	expr := strings.TrimSpace(wfJob.If.Value)
+	if expr == "" {
+		return allSucceed
+	}
+	if strings.HasPrefix(expr, "${{") && strings.HasSuffix(expr, "}}") {
+		expr = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(expr, "${{"), "}}"))
+	}
+
+	results := map[string]*jobparser.JobResult{
+		job.JobID: {Needs: job.Needs},
+	}
+	for _, need := range r.needs[id] {
+		results[r.jobMap[need].JobID] = &jobparser.JobResult{Result: r.statuses[need].String()}
+	}
+	gitCtx := r.gitCtx
+	if gitCtx == nil {
+		gitCtx = &model.GithubContext{}
+	}
+	interpreter := jobparser.NewInterpeter(job.JobID, &model.Job{}, decodeJobMatrix(wfJob), gitCtx, results, r.vars)
+	result, err := interpreter.Evaluate(expr, exprparser.DefaultStatusCheckSuccess)
+	if err != nil {
+		log.Error("evaluate `if` of job %d: %v", id, err)
+		return false
+	}
+	return exprparser.IsTruthy(result)
+}
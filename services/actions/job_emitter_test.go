@@ -126,6 +126,74 @@ jobs:
 			},
 			want: map[int64]actions_model.Status{2: actions_model.StatusSkipped},
 		},
+		{
+			name: "caller starts when its needs succeed",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "job1", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "caller", Status: actions_model.StatusBlocked, Needs: []string{"job1"}},
+				{ID: 3, JobID: "job1", ParentJobID: 2, Status: actions_model.StatusBlocked, Needs: []string{}},
+				{ID: 4, JobID: "job2", ParentJobID: 2, Status: actions_model.StatusBlocked, Needs: []string{"job1"}},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusRunning,
+				3: actions_model.StatusWaiting,
+			},
+		},
+		{
+			name: "caller is skipped with the jobs expanded from it",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "job1", Status: actions_model.StatusFailure, Needs: []string{}},
+				{ID: 2, JobID: "caller", Status: actions_model.StatusBlocked, Needs: []string{"job1"}},
+				{ID: 3, JobID: "job1", ParentJobID: 2, Status: actions_model.StatusBlocked, Needs: []string{}},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusSkipped,
+				3: actions_model.StatusSkipped,
+			},
+		},
+		{
+			name: "caller with `if` runs when its needs fail",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "job1", Status: actions_model.StatusFailure, Needs: []string{}},
+				{ID: 2, JobID: "caller", Status: actions_model.StatusBlocked, Needs: []string{"job1"}, WorkflowPayload: []byte(
+					`
+name: test
+on: push
+jobs:
+  caller:
+    needs: job1
+    if: ${{ always() && needs.job1.result == 'failure' }}
+    uses: ./.gitea/workflows/called.yml
+`)},
+				{ID: 3, JobID: "job1", ParentJobID: 2, Status: actions_model.StatusBlocked, Needs: []string{}},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusRunning,
+				3: actions_model.StatusWaiting,
+			},
+		},
+		{
+			name: "caller is done when the jobs expanded from it are done",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "caller", Status: actions_model.StatusRunning, Needs: []string{}},
+				{ID: 2, JobID: "job1", ParentJobID: 1, Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 3, JobID: "job2", ParentJobID: 1, Status: actions_model.StatusFailure, Needs: []string{"job1"}},
+				{ID: 4, JobID: "job3", Status: actions_model.StatusBlocked, Needs: []string{"caller"}},
+			},
+			want: map[int64]actions_model.Status{
+				1: actions_model.StatusFailure,
+				4: actions_model.StatusSkipped,
+			},
+		},
+		{
+			name: "caller is running until the jobs expanded from it are done",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "caller", Status: actions_model.StatusRunning, Needs: []string{}},
+				{ID: 2, JobID: "job1", ParentJobID: 1, Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 3, JobID: "job2", ParentJobID: 1, Status: actions_model.StatusRunning, Needs: []string{"job1"}},
+			},
+			want: map[int64]actions_model.Status{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
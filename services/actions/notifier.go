@@ -6,6 +6,7 @@ package actions
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	perm_model "code.gitea.io/gitea/models/perm"
@@ -13,6 +14,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
@@ -647,11 +649,76 @@ func (n *actionsNotifier) PackageDelete(ctx context.Context, doer *user_model.Us
 	notifyPackage(ctx, doer, pd, api.HookPackageDeleted)
 }
 
+func (n *actionsNotifier) WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	// the runs triggered by workflow_run events don't trigger workflow_run events again, to avoid triggering cyclically
+	if run.Event == webhook_module.HookEventWorkflowRun {
+		return
+	}
+
+	ctx = withMethod(ctx, "WorkflowRunStatusUpdate")
+
+	convertedWorkflow, err := convert.ToRunActionWorkflow(ctx, run)
+	if err != nil {
+		log.Error("ToRunActionWorkflow: %v", err)
+		return
+	}
+	convertedRun, err := convert.ToActionWorkflowRun(ctx, run)
+	if err != nil {
+		log.Error("ToActionWorkflowRun: %v", err)
+		return
+	}
+
+	permission, _ := access_model.GetUserRepoPermission(ctx, repo, sender)
+
+	newNotifyInput(repo, sender, webhook_module.HookEventWorkflowRun).
+		WithPayload(&api.WorkflowRunPayload{
+			Action:      convert.ToWorkflowRunAction(run.Status),
+			Workflow:    convertedWorkflow,
+			WorkflowRun: convertedRun,
+			Repository:  convert.ToRepo(ctx, repo, permission),
+			Sender:      convert.ToUser(ctx, sender, nil),
+		}).
+		Notify(ctx)
+}
+
 func (n *actionsNotifier) AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
 	ctx = withMethod(ctx, "AutoMergePullRequest")
 	n.MergePullRequest(ctx, doer, pr)
 }
 
+func (n *actionsNotifier) MergeGroupChecksRequested(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, baseRef, groupRef git.RefName, baseCommitID, groupCommitID string) {
+	ctx = withMethod(ctx, "MergeGroupChecksRequested")
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		log.Error("OpenRepository: %v", err)
+		return
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetCommit(groupCommitID)
+	if err != nil {
+		log.Error("GetCommit: %v", err)
+		return
+	}
+
+	newNotifyInput(repo, doer, webhook_module.HookEventMergeGroup).
+		WithRef(groupRef.String()).
+		WithPayload(&api.MergeGroupPayload{
+			Action: api.HookMergeGroupChecksRequested,
+			MergeGroup: &api.MergeGroup{
+				HeadSHA:    groupCommitID,
+				HeadRef:    groupRef.String(),
+				BaseSHA:    baseCommitID,
+				BaseRef:    baseRef.String(),
+				HeadCommit: convert.ToPayloadCommit(ctx, repo, commit),
+			},
+			Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm_model.AccessModeNone}),
+			Sender:     convert.ToUser(ctx, doer, nil),
+		}).
+		Notify(ctx)
+}
+
 func (n *actionsNotifier) PullRequestSynchronized(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
 	ctx = withMethod(ctx, "PullRequestSynchronized")
 
@@ -27,6 +27,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/services/convert"
+	notify_service "code.gitea.io/gitea/services/notify"
 
 	"github.com/nektos/act/pkg/jobparser"
 	"github.com/nektos/act/pkg/model"
@@ -117,7 +118,9 @@ func (input *notifyInput) Notify(ctx context.Context) {
 
 func notify(ctx context.Context, input *notifyInput) error {
 	shouldDetectSchedules := input.Event == webhook_module.HookEventPush && input.Ref.BranchName() == input.Repo.DefaultBranch
-	if input.Doer.IsActions() {
+	// workflow_run events can't trigger cyclically, the runs triggered by them don't send workflow_run events again,
+	// so they are also sent for the runs triggered by the actions user, for example scheduled runs
+	if input.Doer.IsActions() && input.Event != webhook_module.HookEventWorkflowRun {
 		// avoiding triggering cyclically, for example:
 		// a comment of an issue will trigger the runner to add a new comment as reply,
 		// and the new comment will trigger the runner again.
@@ -341,18 +344,20 @@ func handleWorkflows(
 		// cancel running jobs if the event is push or pull_request_sync
 		if run.Event == webhook_module.HookEventPush ||
 			run.Event == webhook_module.HookEventPullRequestSync {
-			if err := actions_model.CancelPreviousJobs(
+			cancelledJobs, err := actions_model.CancelPreviousJobs(
 				ctx,
 				run.RepoID,
 				run.Ref,
 				run.WorkflowID,
 				run.Event,
-			); err != nil {
+			)
+			if err != nil {
 				log.Error("CancelPreviousJobs: %v", err)
 			}
+			NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
 		}
 
-		if err := actions_model.InsertRun(ctx, run, jobs); err != nil {
+		if err := InsertRun(ctx, run, dwf.Content, jobs, vars); err != nil {
 			log.Error("InsertRun: %v", err)
 			continue
 		}
@@ -552,3 +557,79 @@ func DetectAndHandleSchedules(ctx context.Context, repo *repo_model.Repository)
 
 	return handleSchedules(ctx, scheduleWorkflows, commit, notifyInput, repo.DefaultBranch)
 }
+
+// NotifyWorkflowRunStatusUpdate notifies the status of the run has been updated
+func NotifyWorkflowRunStatusUpdate(ctx context.Context, run *actions_model.ActionRun) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+	notify_service.WorkflowRunStatusUpdate(ctx, run.Repo, run.TriggerUser, run)
+}
+
+// NotifyWorkflowJobsStatusUpdate notifies the statuses of the jobs have been updated,
+// and notifies the status of their runs if the runs have been started or completed by the jobs
+func NotifyWorkflowJobsStatusUpdate(ctx context.Context, jobs ...*actions_model.ActionRunJob) {
+	var runIDs []int64
+	runJobs := make(map[int64][]*actions_model.ActionRunJob)
+	for _, job := range jobs {
+		if err := job.LoadAttributes(ctx); err != nil {
+			log.Error("LoadAttributes: %v", err)
+			continue
+		}
+		notify_service.WorkflowJobStatusUpdate(ctx, job.Run.Repo, job.Run.TriggerUser, job)
+
+		if _, ok := runJobs[job.RunID]; !ok {
+			runIDs = append(runIDs, job.RunID)
+		}
+		runJobs[job.RunID] = append(runJobs[job.RunID], job)
+	}
+
+	for _, runID := range runIDs {
+		// reload the run since its status may be updated with the jobs
+		run, err := actions_model.GetRunByID(ctx, runID)
+		if err != nil {
+			log.Error("GetRunByID: %v", err)
+			continue
+		}
+		updated, err := isRunStatusUpdatedByJobs(ctx, run, runJobs[runID])
+		if err != nil {
+			log.Error("isRunStatusUpdatedByJobs: %v", err)
+			continue
+		}
+		if updated {
+			NotifyWorkflowRunStatusUpdate(ctx, run)
+		}
+	}
+}
+
+// isRunStatusUpdatedByJobs returns whether the run has been completed by one of the jobs which are done,
+// or started by one of the jobs which is the first job of the run to start
+func isRunStatusUpdatedByJobs(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob) (bool, error) {
+	if run.Status.IsDone() {
+		return slices.ContainsFunc(jobs, func(job *actions_model.ActionRunJob) bool {
+			return job.Status.IsDone()
+		}), nil
+	}
+	if !run.Status.IsRunning() {
+		return false, nil
+	}
+
+	allJobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return false, err
+	}
+	var first *actions_model.ActionRunJob
+	for _, job := range allJobs {
+		// the jobs which haven't been started since the run started are ignored, including the ones not rerun
+		if job.Started.IsZero() || job.Started < run.Started {
+			continue
+		}
+		if first == nil || job.Started < first.Started || job.Started == first.Started && job.ID < first.ID {
+			first = job
+		}
+	}
+	return first != nil && slices.ContainsFunc(jobs, func(job *actions_model.ActionRunJob) bool {
+		return job.ID == first.ID && job.Status.IsRunning()
+	}), nil
+}
@@ -4,12 +4,27 @@
 package actions
 
 import (
+	"context"
+
 	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/container"
+
+	"xorm.io/builder"
 )
 
 // GetAllRerunJobs get all jobs that need to be rerun when job should be rerun
+// A job expanded from a reusable workflow is rerun with the top-level job calling the workflow,
+// and a job calling a reusable workflow is rerun with all jobs expanded from it.
 func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.ActionRunJob) []*actions_model.ActionRunJob {
+	for job.ParentJobID != 0 {
+		parent := findJobByID(allJobs, job.ParentJobID)
+		if parent == nil {
+			break
+		}
+		job = parent
+	}
+
 	rerunJobs := []*actions_model.ActionRunJob{job}
 	rerunJobsIDSet := make(container.Set[string])
 	rerunJobsIDSet.Add(job.JobID)
@@ -17,7 +32,7 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 	for {
 		found := false
 		for _, j := range allJobs {
-			if rerunJobsIDSet.Contains(j.JobID) {
+			if j.ParentJobID != job.ParentJobID || rerunJobsIDSet.Contains(j.JobID) {
 				continue
 			}
 			for _, need := range j.Needs {
@@ -34,5 +49,122 @@ func GetAllRerunJobs(job *actions_model.ActionRunJob, allJobs []*actions_model.A
 		}
 	}
 
+	// add the jobs expanded from the reusable workflows called by the jobs to rerun
+	rerunIDs := make(container.Set[int64])
+	for _, j := range rerunJobs {
+		rerunIDs.Add(j.ID)
+	}
+	for i := 0; i < len(rerunJobs); i++ {
+		if rerunJobs[i].ID == 0 {
+			continue
+		}
+		for _, j := range allJobs {
+			if j.ParentJobID == rerunJobs[i].ID && rerunIDs.Add(j.ID) {
+				rerunJobs = append(rerunJobs, j)
+			}
+		}
+	}
+
 	return rerunJobs
 }
+
+func findJobByID(jobs []*actions_model.ActionRunJob, id int64) *actions_model.ActionRunJob {
+	for _, job := range jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// RerunWorkflowRun reruns jobs of the run.
+// If job is nil, all jobs of the run are rerun, otherwise the job and all jobs depending on it are rerun.
+// The caller should check whether the workflow of the run is disabled.
+func RerunWorkflowRun(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, job *actions_model.ActionRunJob) error {
+	// reset run's start and stop time when it is done
+	if run.Status.IsDone() {
+		run.PreviousDuration = run.Duration()
+		run.Started = 0
+		run.Stopped = 0
+		if err := actions_model.UpdateRun(ctx, run, "started", "stopped", "previous_duration"); err != nil {
+			return err
+		}
+	}
+
+	// the jobs calling reusable workflows, the jobs expanded from them and the jobs referencing environments are started by the job emitter
+	callerIDs := actions_model.ActionJobList(jobs).GetCallerIDs()
+	if job == nil { // rerun all jobs
+		for _, j := range jobs {
+			// if the job has needs, it should be set to "blocked" status to wait for other jobs
+			shouldBlock := len(j.Needs) > 0 || j.ParentJobID != 0 || callerIDs.Contains(j.ID) || j.Environment != ""
+			if err := rerunJob(ctx, j, shouldBlock); err != nil {
+				return err
+			}
+		}
+	} else {
+		rerunJobs := GetAllRerunJobs(job, jobs)
+		for _, j := range rerunJobs {
+			// jobs other than the specified one should be set to "blocked" status
+			shouldBlock := j.JobID != rerunJobs[0].JobID || j.ParentJobID != 0 || callerIDs.Contains(j.ID) || j.Environment != ""
+			if err := rerunJob(ctx, j, shouldBlock); err != nil {
+				return err
+			}
+		}
+	}
+	if len(callerIDs) > 0 || actions_model.ActionJobList(jobs).HasEnvironments() {
+		if err := EmitJobsIfReady(run.ID); err != nil {
+			return err
+		}
+	}
+
+	// reload the run since its status is updated with the jobs
+	run, err := actions_model.GetRunByID(ctx, run.ID)
+	if err != nil {
+		return err
+	}
+	NotifyWorkflowRunStatusUpdate(ctx, run)
+	return nil
+}
+
+func rerunJob(ctx context.Context, job *actions_model.ActionRunJob, shouldBlock bool) error {
+	status := job.Status
+	if !status.IsDone() {
+		return nil
+	}
+
+	job.TaskID = 0
+	job.Status = actions_model.StatusWaiting
+	if shouldBlock {
+		job.Status = actions_model.StatusBlocked
+	}
+	job.Started = 0
+	job.Stopped = 0
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, job)
+	NotifyWorkflowJobsStatusUpdate(ctx, job)
+	return nil
+}
+
+// CancelWorkflowRun cancels all jobs of a run which are not done yet
+func CancelWorkflowRun(ctx context.Context, jobs []*actions_model.ActionRunJob) error {
+	var cancelledJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		cancelledJobs, err = actions_model.CancelJobs(ctx, jobs)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, jobs...)
+	NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+	emitRunsOfStoppedJobs(jobs...)
+	return nil
+}
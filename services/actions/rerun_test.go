@@ -46,3 +46,18 @@ func TestGetAllRerunJobs(t *testing.T) {
 		assert.ElementsMatch(t, tc.rerunJobs, rerunJobs)
 	}
 }
+
+func TestGetAllRerunJobsOfReusableWorkflow(t *testing.T) {
+	job1 := &actions_model.ActionRunJob{ID: 1, JobID: "job1"}
+	caller := &actions_model.ActionRunJob{ID: 2, JobID: "caller", Needs: []string{"job1"}}
+	called1 := &actions_model.ActionRunJob{ID: 3, JobID: "job1", ParentJobID: 2}
+	called2 := &actions_model.ActionRunJob{ID: 4, JobID: "job2", ParentJobID: 2, Needs: []string{"job1"}}
+	job3 := &actions_model.ActionRunJob{ID: 5, JobID: "job3", Needs: []string{"caller"}}
+
+	jobs := []*actions_model.ActionRunJob{job1, caller, called1, called2, job3}
+
+	assert.ElementsMatch(t, jobs, GetAllRerunJobs(job1, jobs))
+	assert.ElementsMatch(t, []*actions_model.ActionRunJob{caller, called1, called2, job3}, GetAllRerunJobs(caller, jobs))
+	assert.ElementsMatch(t, []*actions_model.ActionRunJob{caller, called1, called2, job3}, GetAllRerunJobs(called2, jobs))
+	assert.ElementsMatch(t, []*actions_model.ActionRunJob{job3}, GetAllRerunJobs(job3, jobs))
+}
@@ -0,0 +1,434 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/exprparser"
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// maxReusableWorkflowDepth is the max depth of nested workflows including the top-level one,
+// see https://docs.github.com/en/actions/sharing-automations/reusing-workflows#nesting-reusable-workflows
+const maxReusableWorkflowDepth = 4
+
+// reusableWorkflowSource is the commit of a repository which a workflow is read from,
+// the local reusable workflows called by the workflow are read from the same commit
+type reusableWorkflowSource struct {
+	Repo      *repo_model.Repository
+	CommitSHA string
+}
+
+// parseReusableWorkflowUses parses the `uses` of a job calling a reusable workflow,
+// which is either `./{path}` for a workflow of the same commit or `{owner}/{repo}/{path}@{ref}` for a workflow of a repository on this instance
+func parseReusableWorkflowUses(uses string) (owner, repo, path, ref string, err error) {
+	if strings.HasPrefix(uses, "./") {
+		path = strings.TrimPrefix(uses, "./")
+	} else {
+		var fullPath string
+		var ok bool
+		if fullPath, ref, ok = strings.Cut(uses, "@"); !ok || ref == "" {
+			return "", "", "", "", util.NewInvalidArgumentErrorf("reusable workflow %q has no ref", uses)
+		}
+		parts := strings.SplitN(fullPath, "/", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return "", "", "", "", util.NewInvalidArgumentErrorf("reusable workflow %q is not in the form of owner/repo/path@ref", uses)
+		}
+		owner, repo, path = parts[0], parts[1], parts[2]
+	}
+	if !actions_module.IsWorkflow(path) {
+		return "", "", "", "", util.NewInvalidArgumentErrorf("reusable workflow %q is not a workflow file", uses)
+	}
+	return owner, repo, path, ref, nil
+}
+
+// checkReusableWorkflowRepoAccess checks that the run can call the reusable workflows of another repository.
+// The repository must have Actions enabled. A repository which isn't public must belong to the owner of the run's repository
+// and the code of it must be readable by the user who triggered the run, and the runs triggered by fork pull requests can't call it.
+func checkReusableWorkflowRepoAccess(ctx context.Context, run *actions_model.ActionRun, repo *repo_model.Repository) error {
+	if !repo.UnitEnabled(ctx, unit.TypeActions) {
+		return util.NewPermissionDeniedErrorf("repository %s has Actions disabled", repo.FullName())
+	}
+
+	if err := repo.LoadOwner(ctx); err != nil {
+		return err
+	}
+	if !repo.IsPrivate && repo.Owner.Visibility.IsPublic() {
+		return nil
+	}
+
+	if err := run.LoadAttributes(ctx); err != nil {
+		return err
+	}
+	if repo.OwnerID != run.Repo.OwnerID || run.IsForkPullRequest {
+		return util.NewPermissionDeniedErrorf("repository %s is not accessible", repo.FullName())
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, run.TriggerUser)
+	if err != nil {
+		return err
+	}
+	if !perm.CanRead(unit.TypeCode) {
+		return util.NewPermissionDeniedErrorf("repository %s is not accessible", repo.FullName())
+	}
+	return nil
+}
+
+// readReusableWorkflow reads the content of the reusable workflow called by a workflow read from the source,
+// the workflow of another repository can only be called if checkReusableWorkflowRepoAccess allows it.
+func readReusableWorkflow(ctx context.Context, run *actions_model.ActionRun, source *reusableWorkflowSource, uses string) ([]byte, *reusableWorkflowSource, error) {
+	ownerName, repoName, path, ref, err := parseReusableWorkflowUses(uses)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	repo := source.Repo
+	if ownerName != "" {
+		repo, err = repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+		if err != nil {
+			if repo_model.IsErrRepoNotExist(err) {
+				return nil, nil, util.NewNotExistErrorf("repository of reusable workflow %q doesn't exist", uses)
+			}
+			return nil, nil, err
+		}
+		if repo.ID != run.RepoID {
+			if err := checkReusableWorkflowRepoAccess(ctx, run, repo); err != nil {
+				return nil, nil, fmt.Errorf("reusable workflow %q: %w", uses, err)
+			}
+		}
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gitRepo.Close()
+
+	commitID := source.CommitSHA
+	if ownerName != "" {
+		commitID = ref
+	}
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil, util.NewNotExistErrorf("ref of reusable workflow %q doesn't exist", uses)
+		}
+		return nil, nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(path)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil, util.NewNotExistErrorf("reusable workflow %q doesn't exist", uses)
+		}
+		return nil, nil, err
+	}
+	content, err := actions_module.GetContentFromEntry(entry)
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, &reusableWorkflowSource{Repo: repo, CommitSHA: commit.ID.String()}, nil
+}
+
+// reusableWorkflowExpander expands the jobs calling reusable workflows into the jobs of the workflows
+type reusableWorkflowExpander struct {
+	run    *actions_model.ActionRun
+	gitCtx *model.GithubContext
+	vars   map[string]string
+
+	jobs    []*jobparser.SingleWorkflow
+	jobOpts []*actions_model.RunJobOptions
+}
+
+// expandReusableWorkflows expands the jobs calling reusable workflows recursively,
+// the jobs expanded from a calling job follow it, and their ParentIndex options hold the index of the calling job.
+func expandReusableWorkflows(ctx context.Context, run *actions_model.ActionRun, gitCtx *model.GithubContext, vars map[string]string, jobs []*jobparser.SingleWorkflow, jobOpts []*actions_model.RunJobOptions) ([]*jobparser.SingleWorkflow, []*actions_model.RunJobOptions, error) {
+	e := &reusableWorkflowExpander{
+		run:    run,
+		gitCtx: gitCtx,
+		vars:   vars,
+	}
+	source := &reusableWorkflowSource{Repo: run.Repo, CommitSHA: run.CommitSHA}
+	for i, job := range jobs {
+		if err := e.expand(ctx, source, job, jobOpts[i], 1); err != nil {
+			return nil, nil, err
+		}
+	}
+	return e.jobs, e.jobOpts, nil
+}
+
+func (e *reusableWorkflowExpander) expand(ctx context.Context, source *reusableWorkflowSource, swf *jobparser.SingleWorkflow, opts *actions_model.RunJobOptions, depth int) error {
+	index := len(e.jobs)
+	e.jobs = append(e.jobs, swf)
+	e.jobOpts = append(e.jobOpts, opts)
+
+	_, job := swf.Job()
+	if job == nil || job.Uses == "" {
+		return nil
+	}
+	if depth >= maxReusableWorkflowDepth {
+		return util.NewInvalidArgumentErrorf("reusable workflow %q exceeds the max depth %d of nested workflows", job.Uses, maxReusableWorkflowDepth)
+	}
+
+	content, calledSource, err := readReusableWorkflow(ctx, e.run, source, job.Uses)
+	if err != nil {
+		return err
+	}
+	called, err := jobparser.Parse(content, jobparser.WithVars(e.vars), jobparser.WithGitContext(e.gitCtx))
+	if err != nil {
+		return util.NewInvalidArgumentErrorf("parse reusable workflow %q: %v", job.Uses, err)
+	}
+	if len(called) == 0 {
+		return util.NewInvalidArgumentErrorf("reusable workflow %q has no jobs", job.Uses)
+	}
+	raw, err := parseRawWorkflow(content)
+	if err != nil {
+		return err
+	}
+
+	for _, calledSwf := range called {
+		calledOpts := evaluateRunJobOptions(raw, e.gitCtx, e.vars, calledSwf)
+		calledOpts.ParentIndex = index
+		id, calledJob := calledSwf.Job()
+		calledJob.Name = job.Name + " / " + calledJob.Name
+		if err := calledSwf.SetJob(id, calledJob); err != nil {
+			return fmt.Errorf("SetJob: %w", err)
+		}
+		if err := e.expand(ctx, calledSource, calledSwf, calledOpts, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRunJobPayload returns the job parsed from the workflow payload of a run job
+func parseRunJobPayload(job *actions_model.ActionRunJob) (*jobparser.SingleWorkflow, *jobparser.Job, error) {
+	swfs, err := jobparser.Parse(job.WorkflowPayload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse workflow payload of job %d: %w", job.ID, err)
+	}
+	if len(swfs) != 1 {
+		return nil, nil, fmt.Errorf("workflow payload of job %d has %d jobs", job.ID, len(swfs))
+	}
+	_, wfJob := swfs[0].Job()
+	return swfs[0], wfJob, nil
+}
+
+// WorkflowCall holds the inputs and the secrets which a reusable workflow is called with
+type WorkflowCall struct {
+	Inputs  map[string]any
+	Secrets map[string]string
+}
+
+// EvaluateWorkflowCall evaluates the `with` and `secrets` of the jobs calling the reusable workflow which the job is expanded from.
+// secrets are the secrets of the run's repository, which are passed to the reusable workflows called by the top-level jobs with `secrets: inherit`.
+// The GITHUB_TOKEN and GITEA_TOKEN of the secrets are always passed.
+func EvaluateWorkflowCall(ctx context.Context, job *actions_model.ActionRunJob, secrets, vars map[string]string) (*WorkflowCall, error) {
+	if err := job.LoadRun(ctx); err != nil {
+		return nil, err
+	}
+	if err := job.Run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, job.RunID)
+	if err != nil {
+		return nil, err
+	}
+	jobMap := make(map[int64]*actions_model.ActionRunJob, len(jobs))
+	for _, v := range jobs {
+		jobMap[v.ID] = v
+	}
+
+	call, err := evaluateWorkflowCall(ctx, generateGitContext(job.Run), vars, jobs, jobMap, job, secrets)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"GITHUB_TOKEN", "GITEA_TOKEN"} {
+		if v, ok := secrets[name]; ok {
+			call.Secrets[name] = v
+		}
+	}
+	return call, nil
+}
+
+func evaluateWorkflowCall(ctx context.Context, gitCtx *model.GithubContext, vars map[string]string, jobs actions_model.ActionJobList, jobMap map[int64]*actions_model.ActionRunJob, job *actions_model.ActionRunJob, secrets map[string]string) (*WorkflowCall, error) {
+	if job.ParentJobID == 0 {
+		// the top-level jobs are called with the inputs of the event, such as workflow_dispatch
+		inputs, _ := gitCtx.Event["inputs"].(map[string]any)
+		return &WorkflowCall{Inputs: inputs, Secrets: secrets}, nil
+	}
+
+	caller, ok := jobMap[job.ParentJobID]
+	if !ok {
+		return nil, fmt.Errorf("calling job %d of job %d doesn't exist", job.ParentJobID, job.ID)
+	}
+	callerCall, err := evaluateWorkflowCall(ctx, gitCtx, vars, jobs, jobMap, caller, secrets)
+	if err != nil {
+		return nil, err
+	}
+	_, callerJob, err := parseRunJobPayload(caller)
+	if err != nil {
+		return nil, err
+	}
+	needs, err := getJobNeeds(ctx, caller, jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluator := jobparser.NewExpressionEvaluator(exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Github:  gitCtx,
+		Secrets: callerCall.Secrets,
+		Matrix:  decodeJobMatrix(callerJob),
+		Needs:   needs,
+		Inputs:  callerCall.Inputs,
+		Vars:    vars,
+	}, exprparser.Config{
+		Run: &model.Run{
+			Workflow: &model.Workflow{Jobs: map[string]*model.Job{caller.JobID: {}}},
+			JobID:    caller.JobID,
+		},
+		Context: "job",
+	}))
+
+	call := &WorkflowCall{
+		Inputs:  make(map[string]any),
+		Secrets: make(map[string]string),
+	}
+
+	swf, _, err := parseRunJobPayload(job)
+	if err != nil {
+		return nil, err
+	}
+	config := (&model.Workflow{RawOn: swf.RawOn}).WorkflowCallConfig()
+	for name, input := range config.Inputs {
+		value, ok := callerJob.With[name]
+		if !ok {
+			// the runner uses the default value of the missing input
+			continue
+		}
+		node := &yaml.Node{}
+		if err := node.Encode(value); err != nil {
+			return nil, err
+		}
+		if err := evaluator.EvaluateYamlNode(node); err != nil {
+			return nil, fmt.Errorf("evaluate input %q: %w", name, err)
+		}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		if input.Type == "boolean" {
+			// the runner expects the string "true" for the boolean inputs
+			value = fmt.Sprint(value)
+		}
+		call.Inputs[name] = value
+	}
+
+	switch callerJob.RawSecrets.Kind {
+	case yaml.ScalarNode:
+		if callerJob.RawSecrets.Value == "inherit" {
+			call.Secrets = callerCall.Secrets
+		}
+	case yaml.MappingNode:
+		var rawSecrets map[string]string
+		if err := callerJob.RawSecrets.Decode(&rawSecrets); err != nil {
+			return nil, fmt.Errorf("decode secrets: %w", err)
+		}
+		for name, value := range rawSecrets {
+			call.Secrets[name] = evaluator.Interpolate(value)
+		}
+	}
+	return call, nil
+}
+
+// getJobNeeds returns the results and outputs of the jobs which the job needs
+func getJobNeeds(ctx context.Context, job *actions_model.ActionRunJob, jobs actions_model.ActionJobList) (map[string]exprparser.Needs, error) {
+	needs := make(map[string]exprparser.Needs, len(job.Needs))
+	for _, v := range jobs {
+		if v.ParentJobID != job.ParentJobID || !v.Status.IsDone() || !util.SliceContainsString(job.Needs, v.JobID) {
+			continue
+		}
+		outputs, err := GetJobOutputs(ctx, v, jobs)
+		if err != nil {
+			return nil, err
+		}
+		needs[v.JobID] = exprparser.Needs{
+			Outputs: outputs,
+			Result:  v.Status.String(),
+		}
+	}
+	return needs, nil
+}
+
+// GetJobOutputs returns the outputs of the job,
+// the outputs of a job calling a reusable workflow are evaluated with the outputs of the jobs expanded from it.
+// jobs are all jobs of the run.
+func GetJobOutputs(ctx context.Context, job *actions_model.ActionRunJob, jobs actions_model.ActionJobList) (map[string]string, error) {
+	outputs := make(map[string]string)
+	if job.TaskID != 0 {
+		got, err := actions_model.FindTaskOutputByTaskID(ctx, job.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("FindTaskOutputByTaskID: %w", err)
+		}
+		for _, v := range got {
+			outputs[v.OutputKey] = v.OutputValue
+		}
+		return outputs, nil
+	}
+
+	var config *model.WorkflowCall
+	results := make(map[string]*model.WorkflowCallResult)
+	for _, child := range jobs {
+		if child.ParentJobID != job.ID {
+			continue
+		}
+		if config == nil {
+			swf, _, err := parseRunJobPayload(child)
+			if err != nil {
+				return nil, err
+			}
+			config = (&model.Workflow{RawOn: swf.RawOn}).WorkflowCallConfig()
+		}
+		childOutputs, err := GetJobOutputs(ctx, child, jobs)
+		if err != nil {
+			return nil, err
+		}
+		if results[child.JobID] == nil {
+			results[child.JobID] = &model.WorkflowCallResult{Outputs: make(map[string]string)}
+		}
+		// the outputs of the jobs with a matrix are merged, like GitHub does
+		for k, v := range childOutputs {
+			results[child.JobID].Outputs[k] = v
+		}
+	}
+	if config == nil {
+		return outputs, nil
+	}
+
+	evaluator := jobparser.NewExpressionEvaluator(exprparser.NewInterpeter(&exprparser.EvaluationEnvironment{
+		Github: &model.GithubContext{},
+		Jobs:   &results,
+	}, exprparser.Config{
+		Run: &model.Run{
+			Workflow: &model.Workflow{Jobs: map[string]*model.Job{job.JobID: {}}},
+			JobID:    job.JobID,
+		},
+		Context: "job",
+	}))
+	for name, output := range config.Outputs {
+		outputs[name] = evaluator.Interpolate(output.Value)
+	}
+	return outputs, nil
+}
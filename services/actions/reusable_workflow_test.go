@@ -0,0 +1,94 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReusableWorkflowUses(t *testing.T) {
+	kases := []struct {
+		uses                   string
+		owner, repo, path, ref string
+		valid                  bool
+	}{
+		{uses: "./.gitea/workflows/build.yml", path: ".gitea/workflows/build.yml", valid: true},
+		{uses: "./.github/workflows/build.yaml", path: ".github/workflows/build.yaml", valid: true},
+		{uses: "org/shared/.github/workflows/build.yml@main", owner: "org", repo: "shared", path: ".github/workflows/build.yml", ref: "main", valid: true},
+		{uses: "org/shared/.gitea/workflows/build.yml@v1.0.0", owner: "org", repo: "shared", path: ".gitea/workflows/build.yml", ref: "v1.0.0", valid: true},
+		{uses: "./build.yml"},
+		{uses: "./.gitea/workflows/build.txt"},
+		{uses: "org/shared/.github/workflows/build.yml"},
+		{uses: "org/shared/.github/workflows/build.yml@"},
+		{uses: "org/.github/workflows/build.yml@main"},
+		{uses: "actions/checkout@v4"},
+	}
+	for _, kase := range kases {
+		t.Run(kase.uses, func(t *testing.T) {
+			owner, repo, path, ref, err := parseReusableWorkflowUses(kase.uses)
+			if !kase.valid {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, kase.owner, owner)
+			assert.Equal(t, kase.repo, repo)
+			assert.Equal(t, kase.path, path)
+			assert.Equal(t, kase.ref, ref)
+		})
+	}
+}
+
+func TestCheckReusableWorkflowRepoAccess(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	repo2 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2}) // private repository of user2
+	repo3 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3}) // private repository of org3
+
+	newRun := func(triggerUser *user_model.User, isForkPullRequest bool) *actions_model.ActionRun {
+		return &actions_model.ActionRun{
+			RepoID:            repo1.ID,
+			Repo:              repo1,
+			TriggerUserID:     triggerUser.ID,
+			TriggerUser:       triggerUser,
+			IsForkPullRequest: isForkPullRequest,
+		}
+	}
+
+	// Actions are disabled in repo2
+	err := checkReusableWorkflowRepoAccess(db.DefaultContext, newRun(user2, false), repo2)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+
+	assert.NoError(t, db.Insert(db.DefaultContext, &repo_model.RepoUnit{RepoID: repo2.ID, Type: unit.TypeActions}))
+	repo2 = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo2.ID})
+
+	assert.NoError(t, checkReusableWorkflowRepoAccess(db.DefaultContext, newRun(user2, false), repo2))
+
+	// the runs triggered by fork pull requests can't call the private workflows
+	err = checkReusableWorkflowRepoAccess(db.DefaultContext, newRun(user2, true), repo2)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+
+	// the trigger user can't read the code of repo2
+	err = checkReusableWorkflowRepoAccess(db.DefaultContext, newRun(user5, false), repo2)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+
+	// the private repositories of other owners are not accessible
+	assert.NoError(t, db.Insert(db.DefaultContext, &repo_model.RepoUnit{RepoID: repo3.ID, Type: unit.TypeActions}))
+	repo3 = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo3.ID})
+	err = checkReusableWorkflowRepoAccess(db.DefaultContext, newRun(user2, false), repo3)
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+}
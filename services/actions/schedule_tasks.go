@@ -55,15 +55,17 @@ func startTasks(ctx context.Context) error {
 			// cancel running jobs if the event is push
 			if row.Schedule.Event == webhook_module.HookEventPush {
 				// cancel running jobs of the same workflow
-				if err := actions_model.CancelPreviousJobs(
+				cancelledJobs, err := actions_model.CancelPreviousJobs(
 					ctx,
 					row.RepoID,
 					row.Schedule.Ref,
 					row.Schedule.WorkflowID,
 					webhook_module.HookEventSchedule,
-				); err != nil {
+				)
+				if err != nil {
 					log.Error("CancelPreviousJobs: %v", err)
 				}
+				NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
 			}
 
 			if row.Repo.IsArchived {
@@ -145,7 +147,7 @@ func CreateScheduleTask(ctx context.Context, cron *actions_model.ActionSchedule)
 	}
 
 	// Insert the action run and its associated jobs into the database
-	if err := actions_model.InsertRun(ctx, run, workflows); err != nil {
+	if err := InsertRun(ctx, run, cron.Content, workflows, vars); err != nil {
 		return err
 	}
 
@@ -31,6 +31,19 @@ func CreateVariable(ctx context.Context, ownerID, repoID int64, name, data strin
 	return v, nil
 }
 
+// CreateEnvironmentVariable creates a variable of an environment of the repository
+func CreateEnvironmentVariable(ctx context.Context, repoID, environmentID int64, name, data string) (*actions_model.ActionVariable, error) {
+	if err := secret_service.ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	if err := envNameCIRegexMatch(name); err != nil {
+		return nil, err
+	}
+
+	return actions_model.InsertEnvironmentVariable(ctx, repoID, environmentID, name, util.ReserveLineBreakForTextarea(data))
+}
+
 func UpdateVariable(ctx context.Context, variableID int64, name, data string) (bool, error) {
 	if err := secret_service.ValidateName(name); err != nil {
 		return false, err
@@ -72,6 +85,27 @@ func DeleteVariableByName(ctx context.Context, ownerID, repoID int64, name strin
 	return actions_model.DeleteVariable(ctx, v.ID)
 }
 
+func DeleteEnvironmentVariableByName(ctx context.Context, repoID, environmentID int64, name string) error {
+	if err := secret_service.ValidateName(name); err != nil {
+		return err
+	}
+
+	if err := envNameCIRegexMatch(name); err != nil {
+		return err
+	}
+
+	v, err := GetVariable(ctx, actions_model.FindVariablesOpts{
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		Name:          name,
+	})
+	if err != nil {
+		return err
+	}
+
+	return actions_model.DeleteVariable(ctx, v.ID)
+}
+
 func GetVariable(ctx context.Context, opts actions_model.FindVariablesOpts) (*actions_model.ActionVariable, error) {
 	vars, err := actions_model.FindVariables(ctx, opts)
 	if err != nil {
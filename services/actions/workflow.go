@@ -0,0 +1,264 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/convert"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// GetWorkflowEntry returns the directory of the workflows and the tree entry of the workflow file on the default branch of the repository
+func GetWorkflowEntry(repo *repo_model.Repository, gitRepo *git.Repository, workflowID string) (string, *git.TreeEntry, error) {
+	dir, entries, err := ListDefaultBranchWorkflows(repo, gitRepo)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name() == workflowID {
+			return dir, entry, nil
+		}
+	}
+	return "", nil, util.ErrorWrapLocale(util.NewNotExistErrorf("workflow %q not found", workflowID), "actions.workflow.not_found", workflowID)
+}
+
+// ListDefaultBranchWorkflows returns the directory of the workflows and the workflow files on the default branch of the repository
+func ListDefaultBranchWorkflows(repo *repo_model.Repository, gitRepo *git.Repository) (string, git.Entries, error) {
+	defaultBranchCommit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return "", nil, fmt.Errorf("GetBranchCommit: %w", err)
+	}
+	return actions.ListWorkflows(defaultBranchCommit)
+}
+
+// EnableOrDisableWorkflow enables or disables the workflow in the actions unit config of the repository
+func EnableOrDisableWorkflow(ctx context.Context, repo *repo_model.Repository, workflowID string, isEnable bool) error {
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		return err
+	}
+	cfg := cfgUnit.ActionsConfig()
+
+	if isEnable {
+		cfg.EnableWorkflow(workflowID)
+	} else {
+		cfg.DisableWorkflow(workflowID)
+	}
+
+	return repo_model.UpdateRepoUnit(ctx, cfgUnit)
+}
+
+// DispatchActionWorkflow creates a workflow_dispatch run of the workflow on the given ref.
+// The workflow file is read from the default branch, processInputs is called to fill the inputs of the event payload.
+func DispatchActionWorkflow(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, workflowID, ref string, processInputs func(workflowDispatch *model.WorkflowDispatch, inputs map[string]any) error) (*actions_model.ActionRun, error) {
+	if len(workflowID) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("workflow is required")
+	}
+	if len(ref) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("ref is required")
+	}
+
+	// can not dispatch a run when workflow is disabled
+	cfgUnit, err := repo.GetUnit(ctx, unit.TypeActions)
+	if err != nil {
+		return nil, err
+	}
+	if cfgUnit.ActionsConfig().IsWorkflowDisabled(workflowID) {
+		return nil, util.ErrorWrapLocale(util.NewPermissionDeniedErrorf("workflow %q is disabled", workflowID), "actions.workflow.disabled")
+	}
+
+	// short ref names are resolved as a branch, unless only a tag with the name exists
+	if !strings.HasPrefix(ref, "refs/") {
+		if !gitRepo.IsBranchExist(ref) && gitRepo.IsTagExist(ref) {
+			ref = git.RefNameFromTag(ref).String()
+		} else {
+			ref = git.RefNameFromBranch(ref).String()
+		}
+	}
+
+	// get target commit of run from specified ref
+	refName := git.RefName(ref)
+	var runTargetCommit *git.Commit
+	if refName.IsTag() {
+		runTargetCommit, err = gitRepo.GetTagCommit(refName.TagName())
+	} else if refName.IsBranch() {
+		runTargetCommit, err = gitRepo.GetBranchCommit(refName.BranchName())
+	} else {
+		return nil, util.ErrorWrapLocale(util.NewInvalidArgumentErrorf("invalid ref %q", ref), "form.git_ref_name_error", ref)
+	}
+	if err != nil {
+		return nil, util.ErrorWrapLocale(util.NewNotExistErrorf("ref %q doesn't exist", ref), "form.target_ref_not_exist", ref)
+	}
+
+	// get workflow entry from default branch commit
+	_, entry, err := GetWorkflowEntry(repo, gitRepo, workflowID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := actions.GetContentFromEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+	workflows, err := jobparser.Parse(content)
+	if err != nil {
+		return nil, util.ErrorWrapLocale(util.NewInvalidArgumentErrorf("parse workflow %q: %v", workflowID, err), "actions.runs.invalid_workflow_helper", err.Error())
+	}
+	if len(workflows) == 0 {
+		return nil, util.ErrorWrapLocale(util.NewNotExistErrorf("workflow %q has no jobs", workflowID), "actions.workflow.not_found", workflowID)
+	}
+
+	workflow := &model.Workflow{
+		RawOn: workflows[0].RawOn,
+	}
+	workflowDispatch := workflow.WorkflowDispatchConfig()
+	if workflowDispatch == nil {
+		return nil, util.ErrorWrapLocale(util.NewInvalidArgumentErrorf("workflow %q doesn't have a workflow_dispatch trigger", workflowID), "actions.workflow.has_no_workflow_dispatch", workflowID)
+	}
+	inputs := make(map[string]any)
+	if err := processInputs(workflowDispatch, inputs); err != nil {
+		return nil, err
+	}
+
+	// inputs -> WorkflowDispatchPayload.Inputs -> ActionRun.EventPayload -> runner: ghc.Event
+	// https://docs.github.com/en/actions/learn-github-actions/contexts#github-context
+	// https://docs.github.com/en/webhooks/webhook-events-and-payloads#workflow_dispatch
+	workflowDispatchPayload := &api.WorkflowDispatchPayload{
+		Workflow:   workflowID,
+		Ref:        ref,
+		Repository: convert.ToRepo(ctx, repo, access_model.Permission{AccessMode: perm.AccessModeNone}),
+		Inputs:     inputs,
+		Sender:     convert.ToUserWithAccessMode(ctx, doer, perm.AccessModeNone),
+	}
+	eventPayload, err := workflowDispatchPayload.JSONPayload()
+	if err != nil {
+		return nil, fmt.Errorf("JSONPayload: %w", err)
+	}
+
+	run := &actions_model.ActionRun{
+		Title:             strings.SplitN(runTargetCommit.CommitMessage, "\n", 2)[0],
+		RepoID:            repo.ID,
+		OwnerID:           repo.OwnerID,
+		WorkflowID:        workflowID,
+		TriggerUserID:     doer.ID,
+		Ref:               ref,
+		CommitSHA:         runTargetCommit.ID.String(),
+		IsForkPullRequest: false,
+		Event:             "workflow_dispatch",
+		TriggerEvent:      "workflow_dispatch",
+		EventPayload:      string(eventPayload),
+		Status:            actions_model.StatusWaiting,
+	}
+
+	// cancel running jobs of the same workflow
+	cancelledJobs, err := actions_model.CancelPreviousJobs(
+		ctx,
+		run.RepoID,
+		run.Ref,
+		run.WorkflowID,
+		run.Event,
+	)
+	if err != nil {
+		log.Error("CancelRunningJobs: %v", err)
+	}
+	NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+
+	vars, err := actions_model.GetVariablesOfRun(ctx, run)
+	if err != nil {
+		return nil, fmt.Errorf("GetVariablesOfRun: %w", err)
+	}
+
+	// Insert the action run and its associated jobs into the database
+	if err := InsertRun(ctx, run, content, workflows, vars); err != nil {
+		return nil, fmt.Errorf("InsertRun: %w", err)
+	}
+
+	alljobs, err := db.Find[actions_model.ActionRunJob](ctx, actions_model.FindRunJobOptions{RunID: run.ID})
+	if err != nil {
+		log.Error("FindRunJobs: %v", err)
+	}
+	CreateCommitStatus(ctx, alljobs...)
+
+	return run, nil
+}
+
+// ValidateWorkflowDispatchInputs checks the given inputs against the inputs declared by the workflow_dispatch trigger,
+// and fills processed with the values converted to strings, falling back to the defaults for missing inputs.
+func ValidateWorkflowDispatchInputs(workflowDispatch *model.WorkflowDispatch, given map[string]any, processed map[string]any) error {
+	for name := range given {
+		if _, ok := workflowDispatch.Inputs[name]; !ok {
+			return util.NewInvalidArgumentErrorf("unexpected input %q", name)
+		}
+	}
+
+	for name, config := range workflowDispatch.Inputs {
+		value, ok := given[name]
+		if !ok || value == nil {
+			if config.Required && config.Default == "" {
+				return util.NewInvalidArgumentErrorf("required input %q is not provided", name)
+			}
+			processed[name] = config.Default
+			continue
+		}
+
+		str, err := workflowDispatchInputString(name, config, value)
+		if err != nil {
+			return err
+		}
+		processed[name] = str
+	}
+	return nil
+}
+
+func workflowDispatchInputString(name string, config model.WorkflowDispatchInput, value any) (string, error) {
+	switch config.Type {
+	case "boolean":
+		switch v := value.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return strconv.FormatBool(b), nil
+			}
+		}
+		return "", util.NewInvalidArgumentErrorf("input %q must be a boolean", name)
+	case "number":
+		switch v := value.(type) {
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return v, nil
+			}
+		}
+		return "", util.NewInvalidArgumentErrorf("input %q must be a number", name)
+	}
+
+	v, ok := value.(string)
+	if !ok {
+		return "", util.NewInvalidArgumentErrorf("input %q must be a string", name)
+	}
+	if config.Type == "choice" && !slices.Contains(config.Options, v) {
+		return "", util.NewInvalidArgumentErrorf("input %q must be one of %v", name, config.Options)
+	}
+	return v, nil
+}
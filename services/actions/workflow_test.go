@@ -0,0 +1,61 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateWorkflowDispatchInputs(t *testing.T) {
+	workflowDispatch := &model.WorkflowDispatch{
+		Inputs: map[string]model.WorkflowDispatchInput{
+			"name":    {Required: true},
+			"debug":   {Type: "boolean", Default: "false"},
+			"retries": {Type: "number", Default: "3"},
+			"env":     {Type: "choice", Options: []string{"staging", "production"}, Default: "staging"},
+		},
+	}
+
+	testCases := []struct {
+		given    map[string]any
+		expected map[string]any
+		err      bool
+	}{
+		{
+			given:    map[string]any{"name": "gitea"},
+			expected: map[string]any{"name": "gitea", "debug": "false", "retries": "3", "env": "staging"},
+		},
+		{
+			given:    map[string]any{"name": "gitea", "debug": true, "retries": float64(5), "env": "production"},
+			expected: map[string]any{"name": "gitea", "debug": "true", "retries": "5", "env": "production"},
+		},
+		{
+			given:    map[string]any{"name": "gitea", "debug": "true", "retries": "1.5"},
+			expected: map[string]any{"name": "gitea", "debug": "true", "retries": "1.5", "env": "staging"},
+		},
+		{given: map[string]any{}, err: true},
+		{given: map[string]any{"name": "gitea", "unknown": "value"}, err: true},
+		{given: map[string]any{"name": "gitea", "debug": "yes"}, err: true},
+		{given: map[string]any{"name": "gitea", "retries": "many"}, err: true},
+		{given: map[string]any{"name": "gitea", "env": "testing"}, err: true},
+		{given: map[string]any{"name": 1}, err: true},
+	}
+
+	for _, tc := range testCases {
+		processed := make(map[string]any)
+		err := ValidateWorkflowDispatchInputs(workflowDispatch, tc.given, processed)
+		if tc.err {
+			assert.ErrorIs(t, err, util.ErrInvalidArgument, "given: %v", tc.given)
+			continue
+		}
+		if assert.NoError(t, err) {
+			assert.Equal(t, tc.expected, processed)
+		}
+	}
+}
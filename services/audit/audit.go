@@ -0,0 +1,153 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Target represents the object an audit event is about
+type Target struct {
+	Type audit_model.TargetType
+	ID   int64
+	Name string
+}
+
+// UserTarget returns the target of an event about the user
+func UserTarget(u *user_model.User) Target {
+	return Target{Type: audit_model.TargetTypeUser, ID: u.ID, Name: u.Name}
+}
+
+// RepoTarget returns the target of an event about the repository
+func RepoTarget(repo *repo_model.Repository) Target {
+	return Target{Type: audit_model.TargetTypeRepository, ID: repo.ID, Name: repo.FullName()}
+}
+
+// ProtectedBranchTarget returns the target of an event about the protected branch rule
+func ProtectedBranchTarget(pb *git_model.ProtectedBranch) Target {
+	return Target{Type: audit_model.TargetTypeProtectedBranch, ID: pb.ID, Name: pb.RuleName}
+}
+
+// RulesetTarget returns the target of an event about the ruleset
+func RulesetTarget(rs *git_model.Ruleset) Target {
+	return Target{Type: audit_model.TargetTypeRuleset, ID: rs.ID, Name: rs.Name}
+}
+
+// PushRuleTarget returns the target of an event about the push rule, the name is the name of the organization or repository it belongs to
+func PushRuleTarget(rule *git_model.PushRule, name string) Target {
+	return Target{Type: audit_model.TargetTypePushRule, ID: rule.ID, Name: name}
+}
+
+// SecretScanningAlertTarget returns the target of an event about the secret scanning alert
+func SecretScanningAlertTarget(alert *git_model.SecretScanningAlert) Target {
+	return Target{Type: audit_model.TargetTypeSecretScanning, ID: alert.ID, Name: alert.PatternName}
+}
+
+// remoteAddr returns the ip address of the client of the request the context belongs to
+func remoteAddr(ctx context.Context) string {
+	req, ok := ctx.Value(httplib.RequestContextKey).(*http.Request)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// Record records an audit event which belongs to the owner (0 for instance level events) and the repository (0 if none),
+// the event is stored in the database and written to the "audit" logger.
+// Errors are only logged, the recorded action has already happened when the event is recorded.
+func Record(ctx context.Context, doer *user_model.User, action audit_model.Action, ownerID, repoID int64, target Target, format string, args ...any) {
+	if !setting.Audit.Enabled {
+		return
+	}
+
+	e := &audit_model.Event{
+		Action:      action,
+		OwnerID:     ownerID,
+		RepoID:      repoID,
+		TargetType:  target.Type,
+		TargetID:    target.ID,
+		TargetName:  target.Name,
+		Description: format,
+		IPAddress:   remoteAddr(ctx),
+	}
+	if len(args) > 0 {
+		e.Description = fmt.Sprintf(format, args...)
+	}
+	if doer != nil {
+		e.DoerID = doer.ID
+		e.DoerName = doer.Name
+	}
+
+	// the action has been done, so the event must be recorded even if the request has been cancelled
+	if err := audit_model.InsertEvent(context.WithoutCancel(ctx), e); err != nil {
+		log.Error("Unable to record audit event %s by %s: %v", action, e.DoerName, err)
+	}
+
+	if setting.IsAuditLogEnabled() {
+		writeEvent(e)
+	}
+}
+
+// RecordRepo records an audit event which belongs to the repository and its owner
+func RecordRepo(ctx context.Context, doer *user_model.User, action audit_model.Action, repo *repo_model.Repository, target Target, format string, args ...any) {
+	Record(ctx, doer, action, repo.OwnerID, repo.ID, target, format, args...)
+}
+
+// writeEvent writes the audit event as a JSON line to the "audit" logger
+func writeEvent(e *audit_model.Event) {
+	created := e.CreatedUnix.AsTime()
+	if e.CreatedUnix == 0 {
+		created = time.Now()
+	}
+	content, err := json.Marshal(map[string]any{
+		"id":          e.ID,
+		"action":      e.Action,
+		"actor_id":    e.DoerID,
+		"actor":       e.DoerName,
+		"owner_id":    e.OwnerID,
+		"repo_id":     e.RepoID,
+		"target_type": e.TargetType,
+		"target_id":   e.TargetID,
+		"target_name": e.TargetName,
+		"description": e.Description,
+		"ip_address":  e.IPAddress,
+		"created_at":  created.Format(time.RFC3339),
+	})
+	if err != nil {
+		log.Error("Unable to marshal audit event %s: %v", e.Action, err)
+		return
+	}
+	log.GetLogger("audit").Info("%s", content)
+}
+
+// CleanupEvents deletes the audit events which are older than the retention period
+func CleanupEvents(ctx context.Context) error {
+	if setting.Audit.RetentionDays <= 0 {
+		return nil
+	}
+	olderThan := time.Now().AddDate(0, 0, -int(setting.Audit.RetentionDays))
+	count, err := audit_model.DeleteEventsOlderThan(ctx, olderThan)
+	if err != nil {
+		return fmt.Errorf("DeleteEventsOlderThan: %w", err)
+	}
+	log.Info("Deleted %d audit events older than %s", count, olderThan.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,303 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/nektos/act/pkg/jobparser"
+	"github.com/nektos/act/pkg/model"
+)
+
+// ToActionsStatus converts an actions status to the status and conclusion used by the API
+func ToActionsStatus(status actions_model.Status) (string, string) {
+	switch status {
+	case actions_model.StatusUnknown, actions_model.StatusWaiting:
+		return "queued", ""
+	case actions_model.StatusBlocked:
+		return "waiting", ""
+	case actions_model.StatusRunning:
+		return "in_progress", ""
+	default:
+		return "completed", status.String()
+	}
+}
+
+// ToWorkflowRunAction returns the action of the workflow_run event for the status of the run
+func ToWorkflowRunAction(status actions_model.Status) api.HookWorkflowRunAction {
+	switch s, _ := ToActionsStatus(status); s {
+	case "in_progress":
+		return api.HookWorkflowRunInProgress
+	case "completed":
+		return api.HookWorkflowRunCompleted
+	default:
+		return api.HookWorkflowRunRequested
+	}
+}
+
+// ToActionWorkflowRun convert a actions_model.ActionRun to an api.ActionWorkflowRun
+func ToActionWorkflowRun(ctx context.Context, run *actions_model.ActionRun) (*api.ActionWorkflowRun, error) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	status, conclusion := ToActionsStatus(run.Status)
+	apiURL := fmt.Sprintf("%s/actions/runs/%d", run.Repo.APIURL(), run.ID)
+	actor := ToUserWithAccessMode(ctx, run.TriggerUser, perm.AccessModeNone)
+	return &api.ActionWorkflowRun{
+		ID:           run.ID,
+		URL:          apiURL,
+		HTMLURL:      run.HTMLURL(),
+		Name:         run.WorkflowID,
+		DisplayTitle: run.Title,
+		Path:         ".gitea/workflows/" + run.WorkflowID,
+		WorkflowID:   run.WorkflowID,
+		Event:        string(run.Event),
+		RunNumber:    run.Index,
+		HeadBranch:   run.PrettyRef(),
+		HeadSHA:      run.CommitSHA,
+		Status:       status,
+		Conclusion:   conclusion,
+		Actor:        actor,
+		TriggerActor: actor,
+		Repository:   ToRepo(ctx, run.Repo, access_model.Permission{AccessMode: perm.AccessModeNone}),
+		JobsURL:      apiURL + "/jobs",
+		LogsURL:      apiURL + "/logs",
+		ArtifactsURL: apiURL + "/artifacts",
+		CancelURL:    apiURL + "/cancel",
+		RerunURL:     apiURL + "/rerun",
+		CreatedAt:    run.Created.AsLocalTime(),
+		UpdatedAt:    run.Updated.AsLocalTime(),
+		RunStartedAt: run.Started.AsLocalTime(),
+		CompletedAt:  run.Stopped.AsLocalTime(),
+	}, nil
+}
+
+// ToActionWorkflowJob convert a actions_model.ActionRunJob to an api.ActionWorkflowJob
+func ToActionWorkflowJob(ctx context.Context, job *actions_model.ActionRunJob) (*api.ActionWorkflowJob, error) {
+	if err := job.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	// the index of the job in the run is used by the web UI
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, job.RunID)
+	if err != nil {
+		return nil, err
+	}
+	jobIndex := 0
+	for i, v := range jobs {
+		if v.ID == job.ID {
+			jobIndex = i
+			break
+		}
+	}
+
+	status, conclusion := ToActionsStatus(job.Status)
+	runAPIURL := fmt.Sprintf("%s/actions/runs/%d", job.Run.Repo.APIURL(), job.RunID)
+	apiJob := &api.ActionWorkflowJob{
+		ID:          job.ID,
+		URL:         fmt.Sprintf("%s/actions/jobs/%d", job.Run.Repo.APIURL(), job.ID),
+		HTMLURL:     fmt.Sprintf("%s/jobs/%d", job.Run.HTMLURL(), jobIndex),
+		RunID:       job.RunID,
+		RunURL:      runAPIURL,
+		Name:        job.Name,
+		HeadBranch:  job.Run.PrettyRef(),
+		HeadSHA:     job.CommitSHA,
+		RunAttempt:  job.Attempt,
+		Status:      status,
+		Conclusion:  conclusion,
+		Labels:      job.RunsOn,
+		Steps:       make([]*api.ActionWorkflowStep, 0),
+		CreatedAt:   job.Created.AsLocalTime(),
+		StartedAt:   job.Started.AsLocalTime(),
+		CompletedAt: job.Stopped.AsLocalTime(),
+	}
+
+	if job.TaskID == 0 {
+		return apiJob, nil
+	}
+
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	task.Job = job
+	if err := task.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	apiJob.RunnerID = task.RunnerID
+	if runner, err := actions_model.GetRunnerByID(ctx, task.RunnerID); err == nil {
+		apiJob.RunnerName = runner.Name
+	} else if !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+
+	for i, step := range actions.FullSteps(task) {
+		stepStatus, stepConclusion := ToActionsStatus(step.Status)
+		apiJob.Steps = append(apiJob.Steps, &api.ActionWorkflowStep{
+			Name:        step.Name,
+			Number:      int64(i + 1),
+			Status:      stepStatus,
+			Conclusion:  stepConclusion,
+			StartedAt:   step.Started.AsLocalTime(),
+			CompletedAt: step.Stopped.AsLocalTime(),
+		})
+	}
+
+	return apiJob, nil
+}
+
+// ToActionArtifact convert a actions_model.ActionArtifact to an api.ActionArtifact
+func ToActionArtifact(repo *repo_model.Repository, art *actions_model.ActionArtifact) *api.ActionArtifact {
+	apiURL := fmt.Sprintf("%s/actions/artifacts/%d", repo.APIURL(), art.ID)
+	return &api.ActionArtifact{
+		ID:                 art.ID,
+		Name:               art.ArtifactName,
+		SizeInBytes:        art.FileSize,
+		URL:                apiURL,
+		ArchiveDownloadURL: apiURL + "/zip",
+		Expired:            art.Status == int64(actions_model.ArtifactStatusExpired),
+		WorkflowRun: &api.ActionWorkflowArtifactRun{
+			ID:           art.RunID,
+			RepositoryID: art.RepoID,
+			HeadSHA:      art.CommitSHA,
+		},
+		CreatedAt: art.CreatedUnix.AsLocalTime(),
+		UpdatedAt: art.UpdatedUnix.AsLocalTime(),
+		ExpiresAt: art.ExpiredUnix.AsLocalTime(),
+	}
+}
+
+// ToActionWorkflow convert a workflow file in dir of the default branch to an api.ActionWorkflow
+func ToActionWorkflow(repo *repo_model.Repository, dir string, entry *git.TreeEntry, isDisabled bool) *api.ActionWorkflow {
+	workflowID := entry.Name()
+	name := workflowID
+	if content, err := actions.GetContentFromEntry(entry); err != nil {
+		log.Warn("GetContentFromEntry %s: %v", workflowID, err)
+	} else if workflow, err := model.ReadWorkflow(bytes.NewReader(content)); err == nil && workflow.Name != "" {
+		name = workflow.Name
+	}
+	return toActionWorkflow(repo, dir, workflowID, name, isDisabled)
+}
+
+// ToRunActionWorkflow convert the workflow which a actions_model.ActionRun is created from to an api.ActionWorkflow,
+// the name of the workflow is read from the jobs of the run
+func ToRunActionWorkflow(ctx context.Context, run *actions_model.ActionRun) (*api.ActionWorkflow, error) {
+	if err := run.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	jobs, err := actions_model.GetRunJobsByRunID(ctx, run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := run.WorkflowID
+	if len(jobs) > 0 {
+		if workflows, err := jobparser.Parse(jobs[0].WorkflowPayload); err == nil && len(workflows) > 0 && workflows[0].Name != "" {
+			name = workflows[0].Name
+		}
+	}
+
+	isDisabled := false
+	if cfgUnit, err := run.Repo.GetUnit(ctx, unit.TypeActions); err == nil {
+		isDisabled = cfgUnit.ActionsConfig().IsWorkflowDisabled(run.WorkflowID)
+	}
+	return toActionWorkflow(run.Repo, ".gitea/workflows", run.WorkflowID, name, isDisabled), nil
+}
+
+func toActionWorkflow(repo *repo_model.Repository, dir, workflowID, name string, isDisabled bool) *api.ActionWorkflow {
+	state := "active"
+	if isDisabled {
+		state = "disabled_manually"
+	}
+
+	path := dir + "/" + workflowID
+	return &api.ActionWorkflow{
+		ID:       workflowID,
+		Name:     name,
+		Path:     path,
+		State:    state,
+		URL:      fmt.Sprintf("%s/actions/workflows/%s", repo.APIURL(), util.PathEscapeSegments(workflowID)),
+		HTMLURL:  fmt.Sprintf("%s/src/branch/%s/%s", repo.HTMLURL(), util.PathEscapeSegments(repo.DefaultBranch), util.PathEscapeSegments(path)),
+		BadgeURL: fmt.Sprintf("%s/actions/workflows/%s/badge.svg", repo.HTMLURL(), util.PathEscapeSegments(workflowID)),
+	}
+}
+
+// ToEnvironment converts an actions_model.ActionEnvironment to an api.Environment
+func ToEnvironment(ctx context.Context, env *actions_model.ActionEnvironment, doer *user_model.User) (*api.Environment, error) {
+	reviewers, err := user_model.GetUsersByIDs(ctx, env.ReviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+	apiReviewers := make([]*api.User, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		apiReviewers = append(apiReviewers, ToUser(ctx, reviewer, doer))
+	}
+	branches := env.DeploymentBranches
+	if branches == nil {
+		branches = []string{}
+	}
+	return &api.Environment{
+		ID:                 env.ID,
+		Name:               env.Name,
+		DeploymentBranches: branches,
+		Reviewers:          apiReviewers,
+		Created:            env.CreatedUnix.AsTime(),
+		Updated:            env.UpdatedUnix.AsTime(),
+	}, nil
+}
+
+// ToDeployment converts an actions_model.ActionDeployment to an api.Deployment
+func ToDeployment(ctx context.Context, d *actions_model.ActionDeployment, doer *user_model.User) (*api.Deployment, error) {
+	if err := d.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	env, err := ToEnvironment(ctx, d.Environment, doer)
+	if err != nil {
+		return nil, err
+	}
+	creator, err := user_model.GetPossibleUserByID(ctx, d.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	apiDeployment := &api.Deployment{
+		ID:            d.ID,
+		Environment:   env,
+		RunID:         d.RunID,
+		JobID:         d.RunJobID,
+		JobName:       d.Job.Name,
+		RunAttempt:    d.Attempt,
+		Ref:           d.Ref,
+		SHA:           d.CommitSHA,
+		ReviewStatus:  d.ReviewStatus.String(),
+		ReviewComment: d.ReviewComment,
+		Creator:       ToUser(ctx, creator, doer),
+		Created:       d.CreatedUnix.AsTime(),
+		Updated:       d.UpdatedUnix.AsTime(),
+	}
+	if d.ReviewerID > 0 {
+		reviewer, err := user_model.GetPossibleUserByID(ctx, d.ReviewerID)
+		if err != nil {
+			return nil, err
+		}
+		apiDeployment.Reviewer = ToUser(ctx, reviewer, doer)
+	}
+	return apiDeployment, nil
+}
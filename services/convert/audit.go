@@ -0,0 +1,36 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	audit_model "code.gitea.io/gitea/models/audit"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAuditEvent converts an audit event to API format
+func ToAuditEvent(e *audit_model.Event) *api.AuditEvent {
+	return &api.AuditEvent{
+		ID:          e.ID,
+		Action:      string(e.Action),
+		ActorID:     e.DoerID,
+		ActorName:   e.DoerName,
+		OwnerID:     e.OwnerID,
+		RepoID:      e.RepoID,
+		TargetType:  string(e.TargetType),
+		TargetID:    e.TargetID,
+		TargetName:  e.TargetName,
+		Description: e.Description,
+		IPAddress:   e.IPAddress,
+		Created:     e.CreatedUnix.AsTime(),
+	}
+}
+
+// ToAuditEvents converts a list of audit events to API format
+func ToAuditEvents(events []*audit_model.Event) []*api.AuditEvent {
+	result := make([]*api.AuditEvent, len(events))
+	for i := range events {
+		result[i] = ToAuditEvent(events[i])
+	}
+	return result
+}
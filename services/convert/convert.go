@@ -179,7 +179,10 @@ func ToBranchProtection(ctx context.Context, bp *git_model.ProtectedBranch, repo
 		ApprovalsWhitelistTeams:       approvalsWhitelistTeams,
 		BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
+		RequireCodeOwnerApproval:      bp.RequireCodeOwnerApproval,
 		BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+		RequireLinearHistory:          bp.RequireLinearHistory,
+		EnableMergeQueue:              bp.EnableMergeQueue,
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
 		IgnoreStaleApprovals:          bp.IgnoreStaleApprovals,
 		RequireSignedCommits:          bp.RequireSignedCommits,
@@ -7,9 +7,12 @@ import (
 	"context"
 
 	"code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
 	api "code.gitea.io/gitea/modules/structs"
+	packages_service "code.gitea.io/gitea/services/packages"
+	container_service "code.gitea.io/gitea/services/packages/container"
 )
 
 // ToPackage convert a packages.PackageDescriptor to api.Package
@@ -51,3 +54,60 @@ func ToPackageFile(pfd *packages.PackageFileDescriptor) *api.PackageFile {
 		HashSHA512: pfd.Blob.HashSHA512,
 	}
 }
+
+// ToPackageAttestation converts packages_service.FileAttestation to api.PackageAttestation
+func ToPackageAttestation(fa *packages_service.FileAttestation) *api.PackageAttestation {
+	return &api.PackageAttestation{
+		ID:             fa.Property.ID,
+		FileID:         fa.File.File.ID,
+		FileName:       fa.File.File.Name,
+		Format:         string(fa.Attestation.Format),
+		PredicateType:  fa.Attestation.Statement.PredicateType,
+		Status:         string(fa.Status),
+		SignerIdentity: fa.SignerIdentity(),
+		SignerIssuer:   fa.SignerIssuer(),
+		Content:        fa.Property.Value,
+	}
+}
+
+// ToPackageComponent converts container_model.Component to api.PackageComponent
+func ToPackageComponent(c *container_model.Component) *api.PackageComponent {
+	return &api.PackageComponent{
+		Name:    c.Name,
+		Version: c.Version,
+		Type:    c.Type,
+		PURL:    c.PURL,
+		License: c.License,
+		Digest:  c.Subject,
+	}
+}
+
+// ToPackageComponentMatch converts container_service.ComponentMatch to api.PackageComponentMatch
+func ToPackageComponentMatch(m *container_service.ComponentMatch) *api.PackageComponentMatch {
+	versions := make([]string, 0, len(m.Versions))
+	for _, pv := range m.Versions {
+		versions = append(versions, pv.Version)
+	}
+
+	return &api.PackageComponentMatch{
+		Image:     m.Package.Name,
+		Versions:  versions,
+		Component: ToPackageComponent(m.Component),
+	}
+}
+
+// ToPackageCleanupRule converts packages.PackageCleanupRule to api.PackageCleanupRule
+func ToPackageCleanupRule(pcr *packages.PackageCleanupRule) *api.PackageCleanupRule {
+	return &api.PackageCleanupRule{
+		ID:            pcr.ID,
+		Enabled:       pcr.Enabled,
+		Type:          string(pcr.Type),
+		KeepCount:     pcr.KeepCount,
+		KeepPattern:   pcr.KeepPattern,
+		RemoveDays:    pcr.RemoveDays,
+		RemovePattern: pcr.RemovePattern,
+		MatchFullName: pcr.MatchFullName,
+		Created:       pcr.CreatedUnix.AsTime(),
+		Updated:       pcr.UpdatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	git_model "code.gitea.io/gitea/models/git"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToPushRule converts a push rule to API format
+func ToPushRule(rule *git_model.PushRule) *api.PushRule {
+	return &api.PushRule{
+		CommitMessagePattern:          rule.CommitMessagePattern,
+		RequireVerifiedAuthorEmail:    rule.RequireVerifiedAuthorEmail,
+		RequireVerifiedCommitterEmail: rule.RequireVerifiedCommitterEmail,
+		MaxFileSize:                   rule.MaxFileSize,
+		ForbiddenFilePatterns:         rule.ForbiddenFilePatterns,
+		Created:                       rule.CreatedUnix.AsTime(),
+		Updated:                       rule.UpdatedUnix.AsTime(),
+	}
+}
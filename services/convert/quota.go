@@ -0,0 +1,66 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	quota_model "code.gitea.io/gitea/models/quota"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToQuotaRuleInfo converts a quota rule to API format
+func ToQuotaRuleInfo(rule *quota_model.Rule) *api.QuotaRuleInfo {
+	subjects := make([]string, 0, len(rule.Subjects))
+	for _, s := range rule.Subjects {
+		subjects = append(subjects, string(s))
+	}
+
+	return &api.QuotaRuleInfo{
+		Name:     rule.Name,
+		Limit:    rule.Limit,
+		Subjects: subjects,
+	}
+}
+
+// ToQuotaGroup converts a quota group to API format
+func ToQuotaGroup(group *quota_model.Group) *api.QuotaGroup {
+	rules := make([]*api.QuotaRuleInfo, 0, len(group.Rules))
+	for _, r := range group.Rules {
+		rules = append(rules, ToQuotaRuleInfo(r))
+	}
+
+	return &api.QuotaGroup{
+		Name:  group.Name,
+		Rules: rules,
+	}
+}
+
+// ToQuotaGroupList converts a list of quota groups to API format
+func ToQuotaGroupList(groups quota_model.GroupList) []*api.QuotaGroup {
+	result := make([]*api.QuotaGroup, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, ToQuotaGroup(g))
+	}
+	return result
+}
+
+// ToQuotaUsed converts the used storage to API format
+func ToQuotaUsed(used *quota_model.Used) *api.QuotaUsed {
+	return &api.QuotaUsed{
+		Git:                used.Git,
+		LFS:                used.LFS,
+		IssueAttachments:   used.IssueAttachments,
+		ReleaseAttachments: used.ReleaseAttachments,
+		Artifacts:          used.Artifacts,
+		Packages:           used.Packages,
+		Total:              used.SizeFor(quota_model.LimitSubjectSizeAll),
+	}
+}
+
+// ToQuotaInfo converts the used storage and the quota groups to API format
+func ToQuotaInfo(used *quota_model.Used, groups quota_model.GroupList) *api.QuotaInfo {
+	return &api.QuotaInfo{
+		Used:   ToQuotaUsed(used),
+		Groups: ToQuotaGroupList(groups),
+	}
+}
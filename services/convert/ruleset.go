@@ -0,0 +1,104 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRuleset converts a ruleset to API format
+func ToRuleset(ctx context.Context, rs *git_model.Ruleset) (*api.Ruleset, error) {
+	bypassUsers, err := user_model.GetUserNamesByIDs(ctx, rs.BypassUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	bypassTeams, err := organization.GetTeamNamesByID(ctx, rs.BypassTeamIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.Ruleset{
+		ID:                            rs.ID,
+		Name:                          rs.Name,
+		Target:                        string(rs.Target),
+		Enforcement:                   rs.Enforcement.String(),
+		RepoNamePatterns:              nonNilStrings(rs.RepoNamePatterns),
+		RepoTopics:                    nonNilStrings(rs.RepoTopics),
+		RefPatterns:                   nonNilStrings(rs.RefPatterns),
+		BypassUsers:                   bypassUsers,
+		BypassTeams:                   bypassTeams,
+		RestrictCreation:              rs.RestrictCreation,
+		RestrictUpdate:                rs.RestrictUpdate,
+		RestrictDeletion:              rs.RestrictDeletion,
+		BlockForcePush:                rs.BlockForcePush,
+		RequireSignedCommits:          rs.RequireSignedCommits,
+		ProtectedFilePatterns:         rs.ProtectedFilePatterns,
+		RequiredApprovals:             rs.RequiredApprovals,
+		BlockOnRejectedReviews:        rs.BlockOnRejectedReviews,
+		BlockOnOfficialReviewRequests: rs.BlockOnOfficialReviewRequests,
+		BlockOnOutdatedBranch:         rs.BlockOnOutdatedBranch,
+		RequireCodeOwnerApproval:      rs.RequireCodeOwnerApproval,
+		EnableStatusCheck:             rs.EnableStatusCheck,
+		StatusCheckContexts:           nonNilStrings(rs.StatusCheckContexts),
+		Created:                       rs.CreatedUnix.AsTime(),
+		Updated:                       rs.UpdatedUnix.AsTime(),
+	}, nil
+}
+
+// ToRulesetViolations converts recorded ruleset violations to API format
+func ToRulesetViolations(ctx context.Context, violations []*git_model.RulesetViolation, doer *user_model.User) ([]*api.RulesetViolation, error) {
+	repos := make(map[int64]*repo_model.Repository)
+	users := make(map[int64]*user_model.User)
+
+	apiViolations := make([]*api.RulesetViolation, 0, len(violations))
+	for _, v := range violations {
+		repo, has := repos[v.RepoID]
+		if !has {
+			var err error
+			repo, err = repo_model.GetRepositoryByID(ctx, v.RepoID)
+			if err != nil && !repo_model.IsErrRepoNotExist(err) {
+				return nil, err
+			}
+			repos[v.RepoID] = repo
+		}
+		user, has := users[v.DoerID]
+		if !has {
+			var err error
+			user, err = user_model.GetPossibleUserByID(ctx, v.DoerID)
+			if err != nil {
+				if !user_model.IsErrUserNotExist(err) {
+					return nil, err
+				}
+				user = user_model.NewGhostUser()
+			}
+			users[v.DoerID] = user
+		}
+
+		apiViolation := &api.RulesetViolation{
+			ID:      v.ID,
+			Ref:     v.RefName.String(),
+			Doer:    ToUser(ctx, user, doer),
+			Reason:  v.Reason,
+			Created: v.CreatedUnix.AsTime(),
+		}
+		if repo != nil {
+			apiViolation.Repository = repo.FullName()
+		}
+		apiViolations = append(apiViolations, apiViolation)
+	}
+	return apiViolations, nil
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	git_model "code.gitea.io/gitea/models/git"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToSecretScanningAlert converts a secret scanning alert to API format
+func ToSecretScanningAlert(ctx context.Context, alert *git_model.SecretScanningAlert, doer *user_model.User) (*api.SecretScanningAlert, error) {
+	if err := alert.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+
+	apiAlert := &api.SecretScanningAlert{
+		ID:                     alert.ID,
+		PatternID:              alert.PatternID,
+		PatternName:            alert.PatternName,
+		RedactedSecret:         alert.RedactedSecret,
+		CommitID:               alert.CommitID,
+		Path:                   alert.Path,
+		Line:                   alert.Line,
+		State:                  string(alert.State),
+		Resolution:             string(alert.Resolution),
+		ResolutionComment:      alert.ResolutionComment,
+		PushProtectionBypassed: alert.PushProtectionBypassed,
+		BypassReason:           alert.BypassReason,
+		Created:                alert.CreatedUnix.AsTime(),
+		Updated:                alert.UpdatedUnix.AsTime(),
+	}
+	if alert.Resolver != nil {
+		apiAlert.ResolvedBy = ToUser(ctx, alert.Resolver, doer)
+	}
+	if alert.ResolvedUnix > 0 {
+		resolved := alert.ResolvedUnix.AsTime()
+		apiAlert.ResolvedAt = &resolved
+	}
+	if alert.Bypasser != nil {
+		apiAlert.PushProtectionBypassedBy = ToUser(ctx, alert.Bypasser, doer)
+	}
+	return apiAlert, nil
+}
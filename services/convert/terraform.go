@@ -0,0 +1,64 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	terraform_model "code.gitea.io/gitea/models/terraform"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	terraform_service "code.gitea.io/gitea/services/terraform"
+)
+
+func getTerraformUser(ctx context.Context, id int64) (*user_model.User, error) {
+	u, err := user_model.GetPossibleUserByID(ctx, id)
+	if user_model.IsErrUserNotExist(err) {
+		return user_model.NewGhostUser(), nil
+	}
+	return u, err
+}
+
+// ToTerraformState converts a terraform_model.TerraformState to an api.TerraformState
+func ToTerraformState(ctx context.Context, s *terraform_model.TerraformState, doer *user_model.User) (*api.TerraformState, error) {
+	apiState := &api.TerraformState{
+		Name:    s.Name,
+		Serial:  s.Serial,
+		Lineage: s.Lineage,
+		Size:    s.Size,
+		Created: s.CreatedUnix.AsTime(),
+		Updated: s.UpdatedUnix.AsTime(),
+	}
+	if s.IsLocked() {
+		locker, err := getTerraformUser(ctx, s.LockerID)
+		if err != nil {
+			return nil, err
+		}
+		apiState.Lock = &api.TerraformStateLock{
+			ID:      s.LockID,
+			Locker:  ToUser(ctx, locker, doer),
+			Created: s.LockedUnix.AsTime(),
+		}
+		if info, err := terraform_service.ParseLockInfo([]byte(s.LockInfo)); err == nil {
+			apiState.Lock.Operation = info.Operation
+			apiState.Lock.Who = info.Who
+		}
+	}
+	return apiState, nil
+}
+
+// ToTerraformStateVersion converts a terraform_model.TerraformStateVersion to an api.TerraformStateVersion
+func ToTerraformStateVersion(ctx context.Context, v *terraform_model.TerraformStateVersion, doer *user_model.User) (*api.TerraformStateVersion, error) {
+	creator, err := getTerraformUser(ctx, v.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	return &api.TerraformStateVersion{
+		Serial:  v.Serial,
+		Lineage: v.Lineage,
+		Size:    v.Size,
+		Creator: ToUser(ctx, creator, doer),
+		Created: v.CreatedUnix.AsTime(),
+	}, nil
+}
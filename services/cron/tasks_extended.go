@@ -17,6 +17,7 @@ import (
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+	secretscanning_service "code.gitea.io/gitea/services/secretscanning"
 	user_service "code.gitea.io/gitea/services/user"
 )
 
@@ -223,6 +224,19 @@ func registerRebuildIssueIndexer() {
 	})
 }
 
+func registerSecretScanningBackfill() {
+	if !setting.SecretScanning.Enabled {
+		return
+	}
+	RegisterTaskFatal("secret_scanning_backfill", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return secretscanning_service.BackfillRepositories(ctx)
+	})
+}
+
 func initExtendedTasks() {
 	registerDeleteInactiveUsers()
 	registerDeleteRepositoryArchives()
@@ -238,4 +252,5 @@ func initExtendedTasks() {
 	registerDeleteOldSystemNotices()
 	registerGCLFS()
 	registerRebuildIssueIndexer()
+	registerSecretScanningBackfill()
 }
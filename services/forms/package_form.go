@@ -15,7 +15,7 @@ import (
 type PackageCleanupRuleForm struct {
 	ID            int64
 	Enabled       bool
-	Type          string `binding:"Required;In(alpine,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,vagrant)"`
+	Type          string `binding:"Required;In(alpine,arch,cargo,chef,composer,conan,conda,container,cran,debian,generic,go,helm,maven,npm,nuget,pub,pypi,rpm,rubygems,swift,terraform,vagrant)"`
 	KeepCount     int    `binding:"In(0,1,5,10,25,50,100)"`
 	KeepPattern   string `binding:"RegexPattern"`
 	RemoveDays    int    `binding:"In(0,7,14,30,60,90,180)"`
@@ -28,3 +28,30 @@ func (f *PackageCleanupRuleForm) Validate(req *http.Request, errs binding.Errors
 	ctx := context.GetValidateContext(req)
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
+
+type PackageUpstreamForm struct {
+	ID          int64
+	Enabled     bool
+	Type        string `binding:"Required;In(maven,npm,pypi)"`
+	URL         string `binding:"Required;ValidUrl;MaxSize(2048)"`
+	MetadataTTL int64  `binding:"In(0,300,1800,3600,21600,86400)"`
+	Action      string `binding:"Required;In(save,remove)"`
+}
+
+func (f *PackageUpstreamForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+type PackageVirtualRegistryForm struct {
+	ID      int64
+	Enabled bool
+	Type    string `binding:"Required;In(maven,npm,pypi)"`
+	Members string `binding:"Required;MaxSize(4096)"`
+	Action  string `binding:"Required;In(save,remove)"`
+}
+
+func (f *PackageVirtualRegistryForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
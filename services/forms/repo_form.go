@@ -213,7 +213,10 @@ type ProtectBranchForm struct {
 	ApprovalsWhitelistTeams       string
 	BlockOnRejectedReviews        bool
 	BlockOnOfficialReviewRequests bool
+	RequireCodeOwnerApproval      bool
 	BlockOnOutdatedBranch         bool
+	RequireLinearHistory          bool
+	EnableMergeQueue              bool
 	DismissStaleApprovals         bool
 	IgnoreStaleApprovals          bool
 	RequireSignedCommits          bool
@@ -227,6 +230,18 @@ func (f *ProtectBranchForm) Validate(req *http.Request, errs binding.Errors) bin
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// ResolveSecretScanningAlertForm form for resolving a secret scanning alert
+type ResolveSecretScanningAlertForm struct {
+	Resolution        string `binding:"Required"`
+	ResolutionComment string `binding:"MaxSize(1000)"`
+}
+
+// Validate validates the fields
+func (f *ResolveSecretScanningAlertForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 //  __      __      ___.   .__                   __
 // /  \    /  \ ____\_ |__ |  |__   ____   ____ |  | __
 // \   \/\/   // __ \| __ \|  |  \ /  _ \ /  _ \|  |/ /
@@ -258,6 +273,8 @@ type WebhookForm struct {
 	Wiki                     bool
 	Repository               bool
 	Package                  bool
+	WorkflowRun              bool
+	WorkflowJob              bool
 	Active                   bool
 	BranchFilter             string `binding:"GlobPattern"`
 	AuthorizationHeader      string
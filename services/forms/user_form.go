@@ -345,6 +345,18 @@ func (f *EditVariableForm) Validate(req *http.Request, errs binding.Errors) bind
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// EditEnvironmentForm form for creating or editing a deployment environment
+type EditEnvironmentForm struct {
+	Name               string `binding:"MaxSize(255)"`
+	DeploymentBranches string // one glob pattern per line
+	Reviewers          string // comma-separated user names
+}
+
+func (f *EditEnvironmentForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetValidateContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // NewAccessTokenForm form for creating access token
 type NewAccessTokenForm struct {
 	Name  string `binding:"Required;MaxSize(255)" locale:"settings.token_name"`
@@ -23,6 +23,7 @@ import (
 	git_model "code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
+	quota_model "code.gitea.io/gitea/models/quota"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
@@ -179,6 +180,16 @@ func BatchHandler(ctx *context.Context) {
 		return
 	}
 
+	if isUpload {
+		var uploadSize int64
+		for _, p := range br.Objects {
+			uploadSize += p.Size
+		}
+		if !checkQuota(ctx, repository, uploadSize) {
+			return
+		}
+	}
+
 	contentStore := lfs_module.NewContentStore()
 
 	var responseObjects []*lfs_module.ObjectResponse
@@ -289,6 +300,10 @@ func UploadHandler(ctx *context.Context) {
 		return
 	}
 
+	if !checkQuota(ctx, repository, p.Size) {
+		return
+	}
+
 	contentStore := lfs_module.NewContentStore()
 	exists, err := contentStore.Exists(p)
 	if err != nil {
@@ -489,6 +504,21 @@ func writeStatus(ctx *context.Context, status int) {
 	writeStatusMessage(ctx, status, http.StatusText(status))
 }
 
+// checkQuota checks if the repository owner may store the additional LFS objects and writes an error response if not
+func checkQuota(ctx *context.Context, repository *repo_model.Repository, size int64) bool {
+	ok, err := quota_model.EvaluateForUser(ctx, repository.OwnerID, quota_model.LimitSubjectSizeGitLFS, size)
+	if err != nil {
+		log.Error("Unable to evaluate the quota of %s: %v", repository.FullName(), err)
+		writeStatus(ctx, http.StatusInternalServerError)
+		return false
+	}
+	if !ok {
+		writeStatusMessage(ctx, http.StatusRequestEntityTooLarge, "quota exceeded")
+		return false
+	}
+	return true
+}
+
 func writeStatusMessage(ctx *context.Context, status int, message string) {
 	ctx.Resp.Header().Set("Content-Type", lfs_module.MediaType)
 	ctx.Resp.WriteHeader(status)
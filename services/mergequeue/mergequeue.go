@@ -0,0 +1,365 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mergequeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/globallock"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	notify_service "code.gitea.io/gitea/services/notify"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// The reasons why a pull request is removed from the merge queue, they are stored as locale keys in the comments
+const (
+	ReasonChecksFailed = "repo.pulls.merge_queue_reason_checks_failed"
+	ReasonConflicts    = "repo.pulls.merge_queue_reason_conflicts"
+	ReasonHeadUpdated  = "repo.pulls.merge_queue_reason_head_updated"
+	ReasonClosed       = "repo.pulls.merge_queue_reason_closed"
+	ReasonDisabled     = "repo.pulls.merge_queue_reason_disabled"
+	ReasonNotMergeable = "repo.pulls.merge_queue_reason_not_mergeable"
+	ReasonInvalidStyle = "repo.pulls.merge_queue_reason_invalid_style"
+)
+
+// ErrMergeQueueDisabled represents an error that the merge queue is not enabled for the base branch of a pull request
+var ErrMergeQueueDisabled = errors.New("the merge queue is not enabled for the base branch")
+
+// mergeQueue represents a queue to handle the merge queues of the branches
+var mergeQueue *queue.WorkerPoolQueue[string]
+
+// Init runs the task queue to that handles the merge queues
+func Init() error {
+	notify_service.RegisterNotifier(NewNotifier())
+
+	mergeQueue = queue.CreateUniqueQueue(graceful.GetManager().ShutdownContext(), "pr_merge_queue", handler)
+	if mergeQueue == nil {
+		return fmt.Errorf("unable to create pr_merge_queue queue")
+	}
+	go graceful.GetManager().RunWithCancel(mergeQueue)
+	return nil
+}
+
+// handle passed repository IDs and branches and process their merge queues
+func handler(items ...string) []string {
+	for _, s := range items {
+		var repoID int64
+		var branch string
+		if _, err := fmt.Sscanf(s, "%d_%s", &repoID, &branch); err != nil {
+			log.Error("could not parse data from pr_merge_queue queue (%v): %v", s, err)
+			continue
+		}
+		handleMergeQueue(repoID, branch)
+	}
+	return nil
+}
+
+func addToQueue(repoID int64, branch string) {
+	log.Trace("Adding repo: %d branch: %s to the merge queue processing queue", repoID, branch)
+	if err := mergeQueue.Push(fmt.Sprintf("%d_%s", repoID, branch)); err != nil {
+		log.Error("Error adding repo: %d branch: %s to the merge queue processing queue %v", repoID, branch, err)
+	}
+}
+
+func getMergeQueueLockKey(repoID int64, branch string) string {
+	return fmt.Sprintf("merge_queue_%d_%s", repoID, branch)
+}
+
+// AddToMergeQueue adds the pull request to the end of the merge queue of its base branch
+func AddToMergeQueue(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, style repo_model.MergeStyle, message string) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return err
+	} else if pb == nil || !pb.EnableMergeQueue {
+		return ErrMergeQueueDisabled
+	}
+
+	if style == repo_model.MergeStyleFastForwardOnly || style == repo_model.MergeStyleManuallyMerged || !pb.IsMergeStyleAllowed(style) {
+		return models.ErrInvalidMergeStyle{ID: pr.BaseRepoID, Style: style}
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, pr.BaseRepo)
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return err
+	}
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := pull_model.AddToMergeQueue(ctx, doer, pr.BaseRepoID, pr.BaseBranch, pr.ID, style, message, headCommitID); err != nil {
+			return err
+		}
+		_, err := issues_model.CreateMergeQueueComment(ctx, issues_model.CommentTypePRAddedToMergeQueue, pr, doer, "")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	addToQueue(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// RemoveFromMergeQueue removes the pull request from the merge queue, reason is empty if it was removed by the doer,
+// the merge groups of the pull requests queued after it will be rebuilt
+func RemoveFromMergeQueue(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, reason string) error {
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := pull_model.RemoveFromMergeQueue(ctx, pr.ID); err != nil {
+			return err
+		}
+		_, err := issues_model.CreateMergeQueueComment(ctx, issues_model.CommentTypePRRemovedFromMergeQueue, pr, doer, reason)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if err := pull_service.DeleteMergeGroupRef(ctx, pr); err != nil {
+		log.Error("DeleteMergeGroupRef %-v: %v", pr, err)
+	}
+
+	addToQueue(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// StartMergeQueueCheckBySHA starts to process the merge queues which have a merge group with the given commit
+func StartMergeQueueCheckBySHA(ctx context.Context, sha string, repo *repo_model.Repository) error {
+	entries, err := pull_model.GetMergeQueueEntriesByGroupCommitID(ctx, repo.ID, sha)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		addToQueue(entry.RepoID, entry.BaseBranch)
+	}
+	return nil
+}
+
+func handleMergeQueue(repoID int64, branch string) {
+	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(),
+		fmt.Sprintf("Handle merge queue of repo %d branch %s", repoID, branch))
+	defer finished()
+
+	releaser, err := globallock.Lock(ctx, getMergeQueueLockKey(repoID, branch))
+	if err != nil {
+		log.Error("lock.Lock(): %v", err)
+		return
+	}
+	defer releaser()
+
+	if err := processMergeQueue(ctx, repoID, branch); err != nil {
+		log.Error("processMergeQueue[repo_id: %d, branch: %s]: %v", repoID, branch, err)
+	}
+}
+
+// processMergeQueue merges the merge groups whose required status checks succeeded, removes the pull requests whose
+// merge groups failed and (re)builds the merge groups which are missing or outdated
+func processMergeQueue(ctx context.Context, repoID int64, branch string) error {
+	entries, err := pull_model.GetMergeQueueEntries(ctx, repoID, branch)
+	if err != nil {
+		return err
+	} else if len(entries) == 0 {
+		return nil
+	}
+
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		return err
+	}
+
+	prs := make([]*issues_model.PullRequest, 0, len(entries))
+	valid := make([]*pull_model.MergeQueueEntry, 0, len(entries))
+	for _, entry := range entries {
+		if err := entry.LoadDoer(ctx); err != nil {
+			return err
+		}
+		pr, err := issues_model.GetPullRequestByID(ctx, entry.PullID)
+		if err != nil {
+			return err
+		}
+		pr.BaseRepo = repo
+		if err := pr.LoadIssue(ctx); err != nil {
+			return err
+		}
+		if pr.HasMerged || pr.Issue.IsClosed {
+			if err := pull_model.RemoveFromMergeQueue(ctx, pr.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		prs = append(prs, pr)
+		valid = append(valid, entry)
+	}
+	entries = valid
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, repoID, branch)
+	if err != nil {
+		return err
+	}
+	if pb == nil || !pb.EnableMergeQueue {
+		for i, entry := range entries {
+			if err := RemoveFromMergeQueue(ctx, entry.Doer, prs[i], ReasonDisabled); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	baseCommitID, err := gitRepo.GetBranchCommitID(branch)
+	if err != nil {
+		return err
+	}
+
+	// Find the last merge group which can be merged, or the first one which failed
+	mergeIdx, failedIdx := -1, -1
+	expectedBase := baseCommitID
+	for i, entry := range entries {
+		if entry.GroupCommitID == "" || entry.BaseCommitID != expectedBase {
+			break
+		}
+		expectedBase = entry.GroupCommitID
+
+		state, err := pull_service.GetMergeGroupCommitStatusState(ctx, pb, entry.GroupCommitID)
+		if err != nil {
+			return err
+		}
+		if state.IsSuccess() {
+			mergeIdx = i
+		} else if state.IsFailure() || state.IsError() {
+			failedIdx = i
+			break
+		}
+	}
+
+	changed := false
+	if mergeIdx >= 0 {
+		merged, err := mergeGroup(ctx, entries[:mergeIdx+1], prs[:mergeIdx+1])
+		if err != nil {
+			return err
+		}
+		if merged {
+			// the following merge groups were built on the merged one, so they are still valid
+			entries, prs = entries[mergeIdx+1:], prs[mergeIdx+1:]
+		}
+		// the pull requests which can't be merged any more have been removed, the queue will be processed again
+		changed = true
+	} else if failedIdx >= 0 {
+		if err := RemoveFromMergeQueue(ctx, entries[failedIdx].Doer, prs[failedIdx], ReasonChecksFailed); err != nil {
+			return err
+		}
+		entries = append(entries[:failedIdx:failedIdx], entries[failedIdx+1:]...)
+		prs = append(prs[:failedIdx:failedIdx], prs[failedIdx+1:]...)
+	}
+
+	// (Re)build the merge groups on top of the latest base branch
+	baseCommitID, err = gitRepo.GetBranchCommitID(branch)
+	if err != nil {
+		return err
+	}
+	expectedBase = baseCommitID
+	ontoRefName := git.BranchPrefix + branch
+	for i, entry := range entries {
+		pr := prs[i]
+		if entry.GroupCommitID != "" && entry.BaseCommitID == expectedBase {
+			expectedBase, ontoRefName = entry.GroupCommitID, pull_service.GetMergeGroupRefName(pr)
+			continue
+		}
+
+		groupBaseCommitID, groupCommitID, err := pull_service.CreateMergeGroup(ctx, pr, entry.Doer, entry.MergeStyle, entry.HeadCommitID, entry.Message, ontoRefName)
+		if err != nil {
+			reason := ""
+			switch {
+			case models.IsErrMergeConflicts(err), models.IsErrRebaseConflicts(err), models.IsErrMergeUnrelatedHistories(err):
+				reason = ReasonConflicts
+			case models.IsErrSHADoesNotMatch(err), git_model.IsErrBranchNotExist(err):
+				reason = ReasonHeadUpdated
+			case models.IsErrInvalidMergeStyle(err):
+				reason = ReasonInvalidStyle
+			default:
+				return err
+			}
+			log.Debug("Unable to build the merge group of %-v: %v", pr, err)
+			if err := RemoveFromMergeQueue(ctx, entry.Doer, pr, reason); err != nil {
+				return err
+			}
+			continue
+		}
+
+		entry.BaseCommitID, entry.GroupCommitID = groupBaseCommitID, groupCommitID
+		if err := pull_model.UpdateMergeQueueEntryGroupCommit(ctx, entry); err != nil {
+			return err
+		}
+		groupRefName := pull_service.GetMergeGroupRefName(pr)
+		notify_service.MergeGroupChecksRequested(ctx, entry.Doer, repo, git.RefNameFromBranch(branch), git.RefName(groupRefName), groupBaseCommitID, groupCommitID)
+
+		expectedBase, ontoRefName = groupCommitID, groupRefName
+		changed = true
+	}
+
+	if changed {
+		// the merge groups may not need any status check, so process the queue again
+		addToQueue(repoID, branch)
+	}
+	return nil
+}
+
+// mergeGroup merges the merge group of the last entry, which contains all the entries,
+// the pull requests which don't satisfy the protected branch rule any more are removed from the queue instead
+func mergeGroup(ctx context.Context, entries []*pull_model.MergeQueueEntry, prs []*issues_model.PullRequest) (bool, error) {
+	canMerge := true
+	for i, pr := range prs {
+		if err := pull_service.CheckMergeQueueBranchProtections(ctx, pr); err != nil {
+			if !models.IsErrDisallowedToMerge(err) {
+				return false, err
+			}
+			log.Debug("%-v can't be merged by the merge queue: %v", pr, err)
+			if err := RemoveFromMergeQueue(ctx, entries[i].Doer, pr, ReasonNotMergeable); err != nil {
+				return false, err
+			}
+			canMerge = false
+		}
+	}
+	if !canMerge {
+		return false, nil
+	}
+
+	last, lastPR := entries[len(entries)-1], prs[len(prs)-1]
+	if err := pull_service.MergeMergeGroup(ctx, last.Doer, prs, last.GroupCommitID); err != nil {
+		switch {
+		case git.IsErrPushOutOfDate(err):
+			// the base branch has been updated, the merge groups will be rebuilt
+			log.Debug("Unable to push the merge group of %-v: %v", lastPR, err)
+			return false, nil
+		case git.IsErrPushRejected(err):
+			log.Debug("The merge group of %-v was rejected: %v", lastPR, err)
+			return false, RemoveFromMergeQueue(ctx, last.Doer, lastPR, ReasonNotMergeable)
+		}
+		return false, err
+	}
+	return true, nil
+}
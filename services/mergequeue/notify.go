@@ -0,0 +1,73 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mergequeue
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repository"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+type mergeQueueNotifier struct {
+	notify_service.NullNotifier
+}
+
+var _ notify_service.Notifier = &mergeQueueNotifier{}
+
+// NewNotifier create a new mergeQueueNotifier notifier
+func NewNotifier() notify_service.Notifier {
+	return &mergeQueueNotifier{}
+}
+
+// removePullRequest removes the pull request from the merge queue if it is queued
+func removePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, reason string) {
+	exist, _, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		log.Error("GetMergeQueueEntryByPullID: %v", err)
+		return
+	} else if !exist {
+		return
+	}
+	if err := RemoveFromMergeQueue(ctx, doer, pr, reason); err != nil {
+		log.Error("RemoveFromMergeQueue: %v", err)
+	}
+}
+
+func (n *mergeQueueNotifier) PullRequestSynchronized(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	// the merge group was built with the old head, the pull request has to be queued again
+	removePullRequest(ctx, doer, pr, ReasonHeadUpdated)
+}
+
+func (n *mergeQueueNotifier) IssueChangeStatus(ctx context.Context, doer *user_model.User, commitID string, issue *issues_model.Issue, actionComment *issues_model.Comment, isClosed bool) {
+	if !issue.IsPull || !isClosed {
+		return
+	}
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		log.Error("LoadPullRequest: %v", err)
+		return
+	}
+	removePullRequest(ctx, doer, issue.PullRequest, ReasonClosed)
+}
+
+func (n *mergeQueueNotifier) PushCommits(ctx context.Context, pusher *user_model.User, repo *repo_model.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	if !opts.RefFullName.IsBranch() || opts.IsDelRef() {
+		return
+	}
+	// the merge groups have to be rebuilt if the base branch was updated by others
+	branch := opts.RefFullName.BranchName()
+	entries, err := pull_model.GetMergeQueueEntries(ctx, repo.ID, branch)
+	if err != nil {
+		log.Error("GetMergeQueueEntries: %v", err)
+		return
+	}
+	if len(entries) > 0 {
+		addToQueue(repo.ID, branch)
+	}
+}
@@ -6,6 +6,7 @@ package notify
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -88,6 +89,13 @@ func AutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues
 	}
 }
 
+// MergeGroupChecksRequested notifies the status checks of a new merge group of the merge queue are requested to notifiers
+func MergeGroupChecksRequested(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, baseRef, groupRef git.RefName, baseCommitID, groupCommitID string) {
+	for _, notifier := range notifiers {
+		notifier.MergeGroupChecksRequested(ctx, doer, repo, baseRef, groupRef, baseCommitID, groupCommitID)
+	}
+}
+
 // NewPullRequest notifies new pull request to notifiers
 func NewPullRequest(ctx context.Context, pr *issues_model.PullRequest, mentions []*user_model.User) {
 	if err := pr.LoadIssue(ctx); err != nil {
@@ -367,3 +375,17 @@ func ChangeDefaultBranch(ctx context.Context, repo *repo_model.Repository) {
 		notifier.ChangeDefaultBranch(ctx, repo)
 	}
 }
+
+// WorkflowRunStatusUpdate notifies the status update of a workflow run to notifiers
+func WorkflowRunStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, run *actions_model.ActionRun) {
+	for _, notifier := range notifiers {
+		notifier.WorkflowRunStatusUpdate(ctx, repo, sender, run)
+	}
+}
+
+// WorkflowJobStatusUpdate notifies the status update of a workflow job to notifiers
+func WorkflowJobStatusUpdate(ctx context.Context, repo *repo_model.Repository, sender *user_model.User, job *actions_model.ActionRunJob) {
+	for _, notifier := range notifiers {
+		notifier.WorkflowJobStatusUpdate(ctx, repo, sender, job)
+	}
+}
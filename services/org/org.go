@@ -8,13 +8,17 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/models"
+	audit_model "code.gitea.io/gitea/models/audit"
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
 	org_model "code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
+	quota_model "code.gitea.io/gitea/models/quota"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
+	audit_service "code.gitea.io/gitea/services/audit"
 	repo_service "code.gitea.io/gitea/services/repository"
 )
 
@@ -52,6 +56,18 @@ func DeleteOrganization(ctx context.Context, org *org_model.Organization, purge
 		return fmt.Errorf("DeleteOrganization: %w", err)
 	}
 
+	if err := quota_model.DeleteGroupMappingsForUser(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteGroupMappingsForUser: %w", err)
+	}
+
+	if err := git_model.DeleteRulesetsByOwnerID(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeleteRulesetsByOwnerID: %w", err)
+	}
+
+	if err := git_model.DeletePushRule(ctx, org.ID, 0); err != nil {
+		return fmt.Errorf("DeletePushRule: %w", err)
+	}
+
 	if err := committer.Commit(); err != nil {
 		return err
 	}
@@ -74,3 +90,12 @@ func DeleteOrganization(ctx context.Context, org *org_model.Organization, purge
 
 	return nil
 }
+
+// RemoveOrgUser removes the user from the organization
+func RemoveOrgUser(ctx context.Context, doer *user_model.User, org *org_model.Organization, u *user_model.User) error {
+	if err := models.RemoveOrgUser(ctx, org, u); err != nil {
+		return err
+	}
+	audit_service.Record(ctx, doer, audit_model.ActionOrgMemberRemove, org.ID, 0, audit_service.UserTarget(u), "")
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	audit_model "code.gitea.io/gitea/models/audit"
+	org_model "code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	audit_service "code.gitea.io/gitea/services/audit"
+)
+
+func teamTarget(t *org_model.Team) audit_service.Target {
+	return audit_service.Target{Type: audit_model.TargetTypeTeam, ID: t.ID, Name: t.Name}
+}
+
+// NewTeam creates a team in the organization of the team
+func NewTeam(ctx context.Context, doer *user_model.User, t *org_model.Team) error {
+	if err := models.NewTeam(ctx, t); err != nil {
+		return err
+	}
+	audit_service.Record(ctx, doer, audit_model.ActionTeamCreate, t.OrgID, 0, teamTarget(t), "permission: %s", t.AccessMode.ToString())
+	return nil
+}
+
+// UpdateTeam updates the information of the team
+func UpdateTeam(ctx context.Context, doer *user_model.User, t *org_model.Team, authChanged, includeAllChanged bool) error {
+	if err := models.UpdateTeam(ctx, t, authChanged, includeAllChanged); err != nil {
+		return err
+	}
+	audit_service.Record(ctx, doer, audit_model.ActionTeamUpdate, t.OrgID, 0, teamTarget(t), "permission: %s, includes all repositories: %t", t.AccessMode.ToString(), t.IncludesAllRepositories)
+	return nil
+}
+
+// DeleteTeam deletes the team
+func DeleteTeam(ctx context.Context, doer *user_model.User, t *org_model.Team) error {
+	if err := models.DeleteTeam(ctx, t); err != nil {
+		return err
+	}
+	audit_service.Record(ctx, doer, audit_model.ActionTeamDelete, t.OrgID, 0, teamTarget(t), "")
+	return nil
+}
+
+// AddTeamMember adds the user to the team
+func AddTeamMember(ctx context.Context, doer *user_model.User, t *org_model.Team, u *user_model.User) error {
+	if err := models.AddTeamMember(ctx, t, u); err != nil {
+		return err
+	}
+	audit_service.Record(ctx, doer, audit_model.ActionTeamMemberAdd, t.OrgID, 0, teamTarget(t), "member: %s", u.Name)
+	return nil
+}
+
+// RemoveTeamMember removes the user from the team
+func RemoveTeamMember(ctx context.Context, doer *user_model.User, t *org_model.Team, u *user_model.User) error {
+	if err := models.RemoveTeamMember(ctx, t, u); err != nil {
+		return err
+	}
+	audit_service.Record(ctx, doer, audit_model.ActionTeamMemberRemove, t.OrgID, 0, teamTarget(t), "member: %s", u.Name)
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"testing"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamMemberAuditEvents(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 2})
+	member := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+
+	assert.NoError(t, AddTeamMember(db.DefaultContext, doer, team, member))
+	unittest.AssertExistsAndLoadBean(t, &audit_model.Event{
+		Action:     audit_model.ActionTeamMemberAdd,
+		DoerID:     doer.ID,
+		OwnerID:    team.OrgID,
+		TargetType: audit_model.TargetTypeTeam,
+		TargetID:   team.ID,
+	})
+
+	assert.NoError(t, RemoveTeamMember(db.DefaultContext, doer, team, member))
+	unittest.AssertExistsAndLoadBean(t, &audit_model.Event{
+		Action:     audit_model.ActionTeamMemberRemove,
+		DoerID:     doer.ID,
+		OwnerID:    team.OrgID,
+		TargetType: audit_model.TargetTypeTeam,
+		TargetID:   team.ID,
+	})
+}
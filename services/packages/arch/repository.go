@@ -0,0 +1,413 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package arch
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	arch_model "code.gitea.io/gitea/models/packages/arch"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/json"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	arch_module "code.gitea.io/gitea/modules/packages/arch"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+const (
+	IndexArchiveFilename   = "packages.db"
+	IndexSignatureFilename = IndexArchiveFilename + ".sig"
+)
+
+// GetOrCreateRepositoryVersion gets or creates the internal repository package
+// The Arch registry needs multiple database files which are stored in this package.
+func GetOrCreateRepositoryVersion(ctx context.Context, ownerID int64) (*packages_model.PackageVersion, error) {
+	return packages_service.GetOrCreateInternalPackageVersion(ctx, ownerID, packages_model.TypeArch, arch_module.RepositoryPackage, arch_module.RepositoryVersion)
+}
+
+// GetOrCreateKeyPair gets or creates the PGP keys used to sign packages and repository databases
+func GetOrCreateKeyPair(ctx context.Context, ownerID int64) (string, string, error) {
+	priv, err := user_model.GetSetting(ctx, ownerID, arch_module.SettingKeyPrivate)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return "", "", err
+	}
+
+	pub, err := user_model.GetSetting(ctx, ownerID, arch_module.SettingKeyPublic)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return "", "", err
+	}
+
+	if priv == "" || pub == "" {
+		priv, pub, err = generateKeypair()
+		if err != nil {
+			return "", "", err
+		}
+
+		if err := user_model.SetUserSetting(ctx, ownerID, arch_module.SettingKeyPrivate, priv); err != nil {
+			return "", "", err
+		}
+
+		if err := user_model.SetUserSetting(ctx, ownerID, arch_module.SettingKeyPublic, pub); err != nil {
+			return "", "", err
+		}
+	}
+
+	return priv, pub, nil
+}
+
+func generateKeypair() (string, string, error) {
+	e, err := openpgp.NewEntity("", "Arch Registry", "", nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	var priv strings.Builder
+	var pub strings.Builder
+
+	w, err := armor.Encode(&priv, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if err := e.SerializePrivate(w, nil); err != nil {
+		return "", "", err
+	}
+	w.Close()
+
+	w, err = armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if err := e.Serialize(w); err != nil {
+		return "", "", err
+	}
+	w.Close()
+
+	return priv.String(), pub.String(), nil
+}
+
+// GetPublicKeyFingerprint returns the fingerprint of the signing key which must be trusted by pacman
+func GetPublicKeyFingerprint(ctx context.Context, ownerID int64) (string, error) {
+	_, pub, err := GetOrCreateKeyPair(ctx, ownerID)
+	if err != nil {
+		return "", err
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(pub))
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(fmt.Sprintf("%x", keyring[0].PrimaryKey.Fingerprint)), nil
+}
+
+// CreateSignature creates a binary detached signature of the content with the key of the owner
+func CreateSignature(ctx context.Context, ownerID int64, r io.Reader) ([]byte, error) {
+	priv, _, err := GetOrCreateKeyPair(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(priv))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := openpgp.DetachSign(&buf, keyring[0], r, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// BuildAllRepositoryFiles (re)builds all databases for every available repository and architecture
+func BuildAllRepositoryFiles(ctx context.Context, ownerID int64) error {
+	pv, err := GetOrCreateRepositoryVersion(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	// 1. Delete all existing repository files
+	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, pf := range pfs {
+		if err := packages_service.DeletePackageFile(ctx, pf); err != nil {
+			return err
+		}
+	}
+
+	// 2. (Re)Build repository files for existing packages
+	repositories, err := arch_model.GetRepositories(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	for _, repository := range repositories {
+		architectures, err := arch_model.GetArchitectures(ctx, ownerID, repository)
+		if err != nil {
+			return err
+		}
+		for _, architecture := range architectures {
+			if err := buildPackagesIndex(ctx, ownerID, pv, repository, architecture); err != nil {
+				return fmt.Errorf("failed to build repository files [%s/%s]: %w", repository, architecture, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// BuildSpecificRepositoryFiles builds the database for the repository and architecture
+func BuildSpecificRepositoryFiles(ctx context.Context, ownerID int64, repository, architecture string) error {
+	pv, err := GetOrCreateRepositoryVersion(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	architectures := container.SetOf(architecture)
+	if architecture == arch_module.AnyArch {
+		// Update all other architectures too when updating the any database
+		additionalArchitectures, err := arch_model.GetArchitectures(ctx, ownerID, repository)
+		if err != nil {
+			return err
+		}
+		architectures.AddMultiple(additionalArchitectures...)
+	}
+
+	for architecture := range architectures {
+		if err := buildPackagesIndex(ctx, ownerID, pv, repository, architecture); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func searchPackageFiles(ctx context.Context, ownerID int64, repository, architecture string) ([]*packages_model.PackageFile, error) {
+	pfs, _, err := packages_model.SearchFiles(ctx, &packages_model.PackageFileSearchOptions{
+		OwnerID:     ownerID,
+		PackageType: packages_model.TypeArch,
+		Query:       "%.pkg.tar.%",
+		Properties: map[string]string{
+			arch_module.PropertyRepository:   repository,
+			arch_module.PropertyArchitecture: architecture,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pfs, nil
+}
+
+// https://wiki.archlinux.org/title/Pacman/Tips_and_tricks#Custom_local_repository
+// https://gitlab.archlinux.org/pacman/pacman/-/blob/master/scripts/repo-add.sh.in
+func buildPackagesIndex(ctx context.Context, ownerID int64, repoVersion *packages_model.PackageVersion, repository, architecture string) error {
+	pfs, err := searchPackageFiles(ctx, ownerID, repository, architecture)
+	if err != nil {
+		return err
+	}
+	if architecture != arch_module.AnyArch {
+		// Add all any packages too
+		anyFiles, err := searchPackageFiles(ctx, ownerID, repository, arch_module.AnyArch)
+		if err != nil {
+			return err
+		}
+		pfs = append(pfs, anyFiles...)
+	}
+
+	compositeKey := fmt.Sprintf("%s|%s", repository, architecture)
+
+	// Delete the database if there are no packages
+	if len(pfs) == 0 {
+		for _, filename := range []string{IndexArchiveFilename, IndexSignatureFilename} {
+			pf, err := packages_model.GetFileForVersionByName(ctx, repoVersion.ID, filename, compositeKey)
+			if err != nil && !errors.Is(err, util.ErrNotExist) {
+				return err
+			} else if pf == nil {
+				continue
+			}
+
+			if err := packages_service.DeletePackageFile(ctx, pf); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	indexContent, _ := packages_module.NewHashedBuffer()
+	defer indexContent.Close()
+
+	zw := gzip.NewWriter(indexContent)
+	tw := tar.NewWriter(zw)
+
+	for _, pf := range pfs {
+		pv, err := packages_model.GetVersionByID(ctx, pf.VersionID)
+		if err != nil {
+			return err
+		}
+		p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+		if err != nil {
+			return err
+		}
+		pb, err := packages_model.GetBlobByID(ctx, pf.BlobID)
+		if err != nil {
+			return err
+		}
+
+		var vm *arch_module.VersionMetadata
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), &vm); err != nil {
+			return err
+		}
+
+		fm := &arch_module.FileMetadata{}
+		pps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeFile, pf.ID, arch_module.PropertyMetadata)
+		if err != nil {
+			return err
+		}
+		if len(pps) > 0 {
+			if err := json.Unmarshal([]byte(pps[0].Value), fm); err != nil {
+				return err
+			}
+		}
+
+		signature := ""
+		pps, err = packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeFile, pf.ID, arch_module.PropertySignature)
+		if err != nil {
+			return err
+		}
+		if len(pps) > 0 {
+			signature = pps[0].Value
+		}
+
+		var desc bytes.Buffer
+		writeDescField(&desc, "FILENAME", pf.Name)
+		writeDescField(&desc, "NAME", p.Name)
+		writeDescField(&desc, "BASE", vm.Base)
+		writeDescField(&desc, "VERSION", pv.Version)
+		writeDescField(&desc, "DESC", vm.Description)
+		writeDescField(&desc, "GROUPS", vm.Groups...)
+		writeDescField(&desc, "CSIZE", strconv.FormatInt(pb.Size, 10))
+		writeDescField(&desc, "ISIZE", strconv.FormatInt(fm.InstalledSize, 10))
+		writeDescField(&desc, "MD5SUM", pb.HashMD5)
+		writeDescField(&desc, "SHA256SUM", pb.HashSHA256)
+		writeDescField(&desc, "PGPSIG", signature)
+		writeDescField(&desc, "URL", vm.ProjectURL)
+		writeDescField(&desc, "LICENSE", vm.Licenses...)
+		writeDescField(&desc, "ARCH", fm.Architecture)
+		writeDescField(&desc, "BUILDDATE", strconv.FormatInt(fm.BuildDate, 10))
+		writeDescField(&desc, "PACKAGER", fm.Packager)
+		writeDescField(&desc, "REPLACES", fm.Replaces...)
+		writeDescField(&desc, "CONFLICTS", fm.Conflicts...)
+		writeDescField(&desc, "PROVIDES", fm.Provides...)
+		writeDescField(&desc, "DEPENDS", fm.Depends...)
+		writeDescField(&desc, "OPTDEPENDS", fm.OptDepends...)
+		writeDescField(&desc, "MAKEDEPENDS", fm.MakeDepends...)
+		writeDescField(&desc, "CHECKDEPENDS", fm.CheckDepends...)
+
+		dir := fmt.Sprintf("%s-%s/", p.Name, pv.Version)
+		if err := tw.WriteHeader(&tar.Header{
+			Name:     dir,
+			Mode:     0o755,
+			Typeflag: tar.TypeDir,
+			ModTime:  pf.CreatedUnix.AsLocalTime(),
+		}); err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    dir + "desc",
+			Mode:    0o644,
+			Size:    int64(desc.Len()),
+			ModTime: pf.CreatedUnix.AsLocalTime(),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(desc.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	if _, err := indexContent.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	signature, err := CreateSignature(ctx, ownerID, indexContent)
+	if err != nil {
+		return err
+	}
+
+	if _, err := indexContent.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	signatureContent, err := packages_module.CreateHashedBufferFromReader(bytes.NewReader(signature))
+	if err != nil {
+		return err
+	}
+	defer signatureContent.Close()
+
+	for filename, content := range map[string]*packages_module.HashedBuffer{
+		IndexArchiveFilename:   indexContent,
+		IndexSignatureFilename: signatureContent,
+	} {
+		if _, err := packages_service.AddFileToPackageVersionInternal(
+			ctx,
+			repoVersion,
+			&packages_service.PackageFileCreationInfo{
+				PackageFileInfo: packages_service.PackageFileInfo{
+					Filename:     filename,
+					CompositeKey: compositeKey,
+				},
+				Creator:           user_model.NewGhostUser(),
+				Data:              content,
+				IsLead:            false,
+				OverwriteExisting: true,
+				Properties: map[string]string{
+					arch_module.PropertyRepository:   repository,
+					arch_module.PropertyArchitecture: architecture,
+				},
+			},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeDescField writes a %NAME% section of a desc file. Empty values are skipped.
+func writeDescField(w *bytes.Buffer, name string, values ...string) {
+	values = util.SliceRemoveAll(values, "")
+	if len(values) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%%%s%%\n", name)
+	for _, value := range values {
+		fmt.Fprintln(w, value)
+	}
+	fmt.Fprintln(w)
+}
+
+// EncodeSignature encodes a binary signature as used in the PGPSIG field of the database
+func EncodeSignature(signature []byte) string {
+	return base64.StdEncoding.EncodeToString(signature)
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
+	attestation_module "code.gitea.io/gitea/modules/packages/attestation"
+)
+
+// FileAttestation is an attestation of a package file together with its verification status
+type FileAttestation struct {
+	File        *packages_model.PackageFileDescriptor
+	Property    *packages_model.PackageProperty
+	Attestation *attestation_module.Attestation
+	Status      attestation_module.Status
+}
+
+// SignerIdentity returns the identity claimed by the untrusted signing certificate, empty if unknown
+func (fa *FileAttestation) SignerIdentity() string {
+	identity, _ := fa.Attestation.Signer()
+	return identity
+}
+
+// SignerIssuer returns the OIDC issuer claimed by the untrusted signing certificate, empty if unknown
+func (fa *FileAttestation) SignerIssuer() string {
+	_, issuer := fa.Attestation.Signer()
+	return issuer
+}
+
+// ValidateAttestation checks that the attestation can be parsed, is about the file with the SHA256 digest and has a valid signature
+func ValidateAttestation(data []byte, hashSHA256 string) error {
+	a, err := attestation_module.Parse(data)
+	if err != nil {
+		return err
+	}
+	_, err = a.Verify(hashSHA256)
+	return err
+}
+
+// AddAttestationToPackageFile validates the attestation and stores it as property of the package file
+func AddAttestationToPackageFile(ctx context.Context, pf *packages_model.PackageFile, data []byte) (*packages_model.PackageProperty, error) {
+	pb, err := packages_model.GetBlobByID(ctx, pf.BlobID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateAttestation(data, pb.HashSHA256); err != nil {
+		return nil, err
+	}
+	return packages_model.InsertProperty(ctx, packages_model.PropertyTypeFile, pf.ID, packages_model.PropertyAttestation, string(data))
+}
+
+// GetFileAttestations parses and verifies the attestations of the package files
+func GetFileAttestations(pfds []*packages_model.PackageFileDescriptor) []*FileAttestation {
+	fas := make([]*FileAttestation, 0, 1)
+	for _, pfd := range pfds {
+		for _, pp := range pfd.Properties {
+			if pp.Name != packages_model.PropertyAttestation {
+				continue
+			}
+
+			a, err := attestation_module.Parse([]byte(pp.Value))
+			if err != nil {
+				log.Warn("Unable to parse attestation %d of package file %d: %v", pp.ID, pfd.File.ID, err)
+				continue
+			}
+			status, _ := a.Verify(pfd.Blob.HashSHA256)
+
+			fas = append(fas, &FileAttestation{
+				File:        pfd,
+				Property:    pp,
+				Attestation: a,
+				Status:      status,
+			})
+		}
+	}
+	return fas
+}
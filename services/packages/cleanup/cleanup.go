@@ -16,6 +16,7 @@ import (
 	packages_module "code.gitea.io/gitea/modules/packages"
 	packages_service "code.gitea.io/gitea/services/packages"
 	alpine_service "code.gitea.io/gitea/services/packages/alpine"
+	arch_service "code.gitea.io/gitea/services/packages/arch"
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
 	container_service "code.gitea.io/gitea/services/packages/container"
 	debian_service "code.gitea.io/gitea/services/packages/debian"
@@ -49,8 +50,6 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 			return fmt.Errorf("CleanupRule [%d]: CompilePattern failed: %w", pcr.ID, err)
 		}
 
-		olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
-
 		packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
 		if err != nil {
 			return fmt.Errorf("CleanupRule [%d]: GetPackagesByType failed: %w", pcr.ID, err)
@@ -58,55 +57,21 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 
 		anyVersionDeleted := false
 		for _, p := range packages {
-			pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
-				PackageID:  p.ID,
-				IsInternal: optional.Some(false),
-				Sort:       packages_model.SortCreatedDesc,
-				Paginator:  db.NewAbsoluteListOptions(pcr.KeepCount, 200),
-			})
+			pvs, err := getVersionsToRemove(ctx, pcr, p)
 			if err != nil {
-				return fmt.Errorf("CleanupRule [%d]: SearchVersions failed: %w", pcr.ID, err)
+				return fmt.Errorf("CleanupRule [%d]: %w", pcr.ID, err)
 			}
-			versionDeleted := false
 			for _, pv := range pvs {
-				if pcr.Type == packages_model.TypeContainer {
-					if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
-						return fmt.Errorf("CleanupRule [%d]: container.ShouldBeSkipped failed: %w", pcr.ID, err)
-					} else if skip {
-						log.Debug("Rule[%d]: keep '%s/%s' (container)", pcr.ID, p.Name, pv.Version)
-						continue
-					}
-				}
-
-				toMatch := pv.LowerVersion
-				if pcr.MatchFullName {
-					toMatch = p.LowerName + "/" + pv.LowerVersion
-				}
-
-				if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
-					log.Debug("Rule[%d]: keep '%s/%s' (keep pattern)", pcr.ID, p.Name, pv.Version)
-					continue
-				}
-				if pv.CreatedUnix.AsLocalTime().After(olderThan) {
-					log.Debug("Rule[%d]: keep '%s/%s' (remove days)", pcr.ID, p.Name, pv.Version)
-					continue
-				}
-				if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
-					log.Debug("Rule[%d]: keep '%s/%s' (remove pattern)", pcr.ID, p.Name, pv.Version)
-					continue
-				}
-
 				log.Debug("Rule[%d]: remove '%s/%s'", pcr.ID, p.Name, pv.Version)
 
 				if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
 					return fmt.Errorf("CleanupRule [%d]: DeletePackageVersionAndReferences failed: %w", pcr.ID, err)
 				}
+			}
 
-				versionDeleted = true
+			if len(pvs) > 0 {
 				anyVersionDeleted = true
-			}
 
-			if versionDeleted {
 				if pcr.Type == packages_model.TypeCargo {
 					owner, err := user_model.GetUserByID(ctx, pcr.OwnerID)
 					if err != nil {
@@ -120,18 +85,8 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 		}
 
 		if anyVersionDeleted {
-			if pcr.Type == packages_model.TypeDebian {
-				if err := debian_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: debian.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-				}
-			} else if pcr.Type == packages_model.TypeAlpine {
-				if err := alpine_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: alpine.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-				}
-			} else if pcr.Type == packages_model.TypeRpm {
-				if err := rpm_service.BuildAllRepositoryFiles(ctx, pcr.OwnerID); err != nil {
-					return fmt.Errorf("CleanupRule [%d]: rpm.BuildAllRepositoryFiles failed: %w", pcr.ID, err)
-				}
+			if err := buildAllRepositoryFiles(ctx, pcr.OwnerID, pcr.Type); err != nil {
+				return fmt.Errorf("CleanupRule [%d]: %w", pcr.ID, err)
 			}
 		}
 		return nil
@@ -143,6 +98,116 @@ func ExecuteCleanupRules(outerCtx context.Context) error {
 	return committer.Commit()
 }
 
+// getVersionsToRemove returns the versions of the package which are matched by the cleanup rule.
+// The patterns of the rule must be compiled before.
+func getVersionsToRemove(ctx context.Context, pcr *packages_model.PackageCleanupRule, p *packages_model.Package) ([]*packages_model.PackageVersion, error) {
+	olderThan := time.Now().AddDate(0, 0, -pcr.RemoveDays)
+
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		PackageID:  p.ID,
+		IsInternal: optional.Some(false),
+		Sort:       packages_model.SortCreatedDesc,
+		Paginator:  db.NewAbsoluteListOptions(pcr.KeepCount, 200),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SearchVersions failed: %w", err)
+	}
+
+	toRemove := make([]*packages_model.PackageVersion, 0, len(pvs))
+	for _, pv := range pvs {
+		if pcr.Type == packages_model.TypeContainer {
+			if skip, err := container_service.ShouldBeSkipped(ctx, pcr, p, pv); err != nil {
+				return nil, fmt.Errorf("container.ShouldBeSkipped failed: %w", err)
+			} else if skip {
+				log.Debug("Rule[%d]: keep '%s/%s' (container)", pcr.ID, p.Name, pv.Version)
+				continue
+			}
+		}
+
+		toMatch := pv.LowerVersion
+		if pcr.MatchFullName {
+			toMatch = p.LowerName + "/" + pv.LowerVersion
+		}
+
+		if pcr.KeepPatternMatcher != nil && pcr.KeepPatternMatcher.MatchString(toMatch) {
+			log.Debug("Rule[%d]: keep '%s/%s' (keep pattern)", pcr.ID, p.Name, pv.Version)
+			continue
+		}
+		if pv.CreatedUnix.AsLocalTime().After(olderThan) {
+			log.Debug("Rule[%d]: keep '%s/%s' (remove days)", pcr.ID, p.Name, pv.Version)
+			continue
+		}
+		if pcr.RemovePatternMatcher != nil && !pcr.RemovePatternMatcher.MatchString(toMatch) {
+			log.Debug("Rule[%d]: keep '%s/%s' (remove pattern)", pcr.ID, p.Name, pv.Version)
+			continue
+		}
+
+		toRemove = append(toRemove, pv)
+	}
+	return toRemove, nil
+}
+
+// PreviewCleanupRule returns the package versions which would be removed if the cleanup rule gets executed
+func PreviewCleanupRule(ctx context.Context, pcr *packages_model.PackageCleanupRule) ([]*packages_model.PackageDescriptor, error) {
+	if err := pcr.CompiledPattern(); err != nil {
+		return nil, err
+	}
+
+	packages, err := packages_model.GetPackagesByType(ctx, pcr.OwnerID, pcr.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	pvs := make([]*packages_model.PackageVersion, 0, 10)
+	for _, p := range packages {
+		toRemove, err := getVersionsToRemove(ctx, pcr, p)
+		if err != nil {
+			return nil, err
+		}
+		pvs = append(pvs, toRemove...)
+	}
+
+	return packages_model.GetPackageDescriptors(ctx, pvs)
+}
+
+// RemovePackageVersion deletes the package version and updates the repository index files of the package type
+func RemovePackageVersion(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) error {
+	if err := packages_service.RemovePackageVersion(ctx, doer, pd.Version); err != nil {
+		return err
+	}
+
+	if pd.Package.Type == packages_model.TypeCargo {
+		if err := cargo_service.UpdatePackageIndexIfExists(ctx, doer, pd.Owner, pd.Package.ID); err != nil {
+			return fmt.Errorf("cargo.UpdatePackageIndexIfExists failed: %w", err)
+		}
+	}
+
+	return buildAllRepositoryFiles(ctx, pd.Owner.ID, pd.Package.Type)
+}
+
+// buildAllRepositoryFiles rebuilds the repository index files of the owner for package types which have them
+func buildAllRepositoryFiles(ctx context.Context, ownerID int64, packageType packages_model.Type) error {
+	switch packageType {
+	case packages_model.TypeDebian:
+		if err := debian_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("debian.BuildAllRepositoryFiles failed: %w", err)
+		}
+	case packages_model.TypeAlpine:
+		if err := alpine_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("alpine.BuildAllRepositoryFiles failed: %w", err)
+		}
+	case packages_model.TypeArch:
+		if err := arch_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("arch.BuildAllRepositoryFiles failed: %w", err)
+		}
+	case packages_model.TypeRpm:
+		if err := rpm_service.BuildAllRepositoryFiles(ctx, ownerID); err != nil {
+			return fmt.Errorf("rpm.BuildAllRepositoryFiles failed: %w", err)
+		}
+	}
+	return nil
+}
+
 func CleanupExpiredData(outerCtx context.Context, olderThan time.Duration) error {
 	ctx, committer, err := db.TxContext(outerCtx)
 	if err != nil {
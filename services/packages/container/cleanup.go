@@ -21,6 +21,9 @@ func Cleanup(ctx context.Context, olderThan time.Duration) error {
 	if err := cleanupExpiredBlobUploads(ctx, olderThan); err != nil {
 		return err
 	}
+	if err := cleanupDanglingReferrers(ctx, olderThan); err != nil {
+		return err
+	}
 	return cleanupExpiredUploadedBlobs(ctx, olderThan)
 }
 
@@ -102,6 +105,11 @@ func ShouldBeSkipped(ctx context.Context, pcr *packages_model.PackageCleanupRule
 		if has {
 			return true, nil
 		}
+
+		// Skip it if the version is a referrer (signature, SBOM, ...) of an existing manifest
+		if has, err := hasExistingSubject(ctx, p, pv); err != nil || has {
+			return has, err
+		}
 	}
 
 	return false, nil
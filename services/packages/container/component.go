@@ -0,0 +1,87 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"context"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+)
+
+// ComponentMatch is a component found in the SBOM of a container image
+type ComponentMatch struct {
+	Component *container_model.Component
+	Package   *packages_model.Package
+	Versions  []*packages_model.PackageVersion // versions containing the manifest described by the SBOM
+}
+
+// GetComponents gets the components listed in the SBOMs of the container image.
+// For multi-arch images the components of all platform manifests are returned.
+func GetComponents(ctx context.Context, pd *packages_model.PackageDescriptor) ([]*container_model.Component, error) {
+	subjects := make([]string, 0, 1)
+	for _, pf := range pd.Files {
+		if pf.File.LowerName == container_model.ManifestFilename {
+			subjects = append(subjects, pf.Properties.GetByName(container_module.PropertyDigest))
+		}
+	}
+	if metadata, ok := pd.Metadata.(*container_module.Metadata); ok {
+		for _, manifest := range metadata.Manifests {
+			subjects = append(subjects, manifest.Digest)
+		}
+	}
+
+	return container_model.GetComponentsBySubjects(ctx, pd.Package.ID, subjects)
+}
+
+// SearchComponents searches the components of all container images of an owner
+// and resolves the images containing them.
+func SearchComponents(ctx context.Context, opts *container_model.ComponentSearchOptions) ([]*ComponentMatch, int64, error) {
+	components, total, err := container_model.SearchComponents(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	packages := make(map[int64]*packages_model.Package)
+	matches := make([]*ComponentMatch, 0, len(components))
+	for _, c := range components {
+		p, has := packages[c.PackageID]
+		if !has {
+			p, err = packages_model.GetPackageByID(ctx, c.PackageID)
+			if err != nil {
+				return nil, 0, err
+			}
+			packages[c.PackageID] = p
+		}
+
+		// the subject may not exist if the SBOM was pushed before the image
+		pfds, err := container_model.GetContainerBlobs(ctx, &container_model.BlobSearchOptions{
+			OwnerID:    p.OwnerID,
+			Image:      p.LowerName,
+			Digest:     c.Subject,
+			IsManifest: true,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		versions := make([]*packages_model.PackageVersion, 0, len(pfds))
+		for _, pfd := range pfds {
+			pv, err := packages_model.GetVersionByID(ctx, pfd.File.VersionID)
+			if err != nil {
+				return nil, 0, err
+			}
+			versions = append(versions, pv)
+		}
+
+		matches = append(matches, &ComponentMatch{
+			Component: c,
+			Package:   p,
+			Versions:  versions,
+		})
+	}
+
+	return matches, total, nil
+}
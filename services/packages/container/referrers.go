@@ -0,0 +1,161 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package container
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	"code.gitea.io/gitea/modules/json"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	packages_service "code.gitea.io/gitea/services/packages"
+
+	digest "github.com/opencontainers/go-digest"
+	oci "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// GetReferrers gets the descriptors of the manifests of the image which refer to the subject digest.
+// If artifactType is not empty only manifests of this artifact type are returned.
+func GetReferrers(ctx context.Context, ownerID int64, image, subject, artifactType string) ([]oci.Descriptor, error) {
+	pfds, err := container_model.GetContainerBlobs(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    ownerID,
+		Image:      image,
+		Subject:    subject,
+		IsManifest: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	descriptors := make([]oci.Descriptor, 0, len(pfds))
+	seen := make(map[string]bool, len(pfds))
+	for _, pfd := range pfds {
+		// the same manifest may be stored as multiple versions (tag and digest)
+		manifestDigest := pfd.Properties.GetByName(container_module.PropertyDigest)
+		if seen[manifestDigest] {
+			continue
+		}
+		seen[manifestDigest] = true
+
+		pv, err := packages_model.GetVersionByID(ctx, pfd.File.VersionID)
+		if err != nil {
+			return nil, err
+		}
+
+		var metadata container_module.Metadata
+		if err := json.Unmarshal([]byte(pv.MetadataJSON), &metadata); err != nil {
+			return nil, err
+		}
+
+		if artifactType != "" && metadata.ArtifactType != artifactType {
+			continue
+		}
+
+		descriptors = append(descriptors, oci.Descriptor{
+			MediaType:    pfd.Properties.GetByName(container_module.PropertyMediaType),
+			Digest:       digest.Digest(manifestDigest),
+			Size:         pfd.Blob.Size,
+			ArtifactType: metadata.ArtifactType,
+			Annotations:  metadata.Annotations,
+		})
+	}
+
+	return descriptors, nil
+}
+
+// RemoveReferrers removes the manifests of the image which refer to the subject digest if no manifest with this digest exists anymore.
+// The referrers of the removed manifests are removed too.
+func RemoveReferrers(ctx context.Context, ownerID int64, image, subject string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		return removeReferrers(ctx, ownerID, image, subject)
+	})
+}
+
+func removeReferrers(ctx context.Context, ownerID int64, image, subject string) error {
+	_, err := container_model.GetContainerBlob(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    ownerID,
+		Image:      image,
+		Digest:     subject,
+		IsManifest: true,
+	})
+	if err == nil {
+		// the subject is still referenced by another tag
+		return nil
+	} else if err != container_model.ErrContainerBlobNotExist {
+		return err
+	}
+
+	pfds, err := container_model.GetContainerBlobs(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    ownerID,
+		Image:      image,
+		Subject:    subject,
+		IsManifest: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	removed := make(map[string]bool, len(pfds))
+	for _, pfd := range pfds {
+		pv, err := packages_model.GetVersionByID(ctx, pfd.File.VersionID)
+		if err != nil {
+			return err
+		}
+		if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
+			return err
+		}
+		removed[pfd.Properties.GetByName(container_module.PropertyDigest)] = true
+	}
+
+	for referrer := range removed {
+		if err := removeReferrers(ctx, ownerID, image, referrer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanupDanglingReferrers removes expired manifests which refer to a subject not existing anymore.
+// Referrers may be pushed before their subject, so only referrers older than specified are removed.
+func cleanupDanglingReferrers(ctx context.Context, olderThan time.Duration) error {
+	for {
+		pvs, err := container_model.SearchDanglingReferrers(ctx, olderThan)
+		if err != nil {
+			return err
+		}
+		if len(pvs) == 0 {
+			return nil
+		}
+
+		// removing the referrers may leave their own referrers dangling
+		for _, pv := range pvs {
+			if err := packages_service.DeletePackageVersionAndReferences(ctx, pv); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// hasExistingSubject checks if the manifest version refers to a subject manifest which exists in the package
+func hasExistingSubject(ctx context.Context, p *packages_model.Package, pv *packages_model.PackageVersion) (bool, error) {
+	pps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypeVersion, pv.ID, container_module.PropertyManifestSubject)
+	if err != nil || len(pps) == 0 {
+		return false, err
+	}
+
+	_, err = container_model.GetContainerBlob(ctx, &container_model.BlobSearchOptions{
+		OwnerID:    p.OwnerID,
+		Image:      p.LowerName,
+		Digest:     pps[0].Value,
+		IsManifest: true,
+	})
+	if err == container_model.ErrContainerBlobNotExist {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// LinkToRepository links the package to a repository of the same owner. The doer needs write access to the code of the repository.
+func LinkToRepository(ctx context.Context, pkg *packages_model.Package, repo *repo_model.Repository, doer *user_model.User) error {
+	if pkg.OwnerID != repo.OwnerID {
+		return util.NewInvalidArgumentErrorf("package and repository must have the same owner")
+	}
+	if pkg.RepoID == repo.ID {
+		return nil
+	}
+
+	perms, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return err
+	}
+	if !perms.CanWrite(unit.TypeCode) {
+		return util.NewPermissionDeniedErrorf("no permission to link this package to the repository")
+	}
+
+	if err := packages_model.SetRepositoryLink(ctx, pkg.ID, repo.ID); err != nil {
+		return err
+	}
+	pkg.RepoID = repo.ID
+	return nil
+}
+
+// UnlinkFromRepository removes the link between the package and its repository
+func UnlinkFromRepository(ctx context.Context, pkg *packages_model.Package) error {
+	if pkg.RepoID == 0 {
+		return nil
+	}
+
+	if err := packages_model.SetRepositoryLink(ctx, pkg.ID, 0); err != nil {
+		return err
+	}
+	pkg.RepoID = 0
+	return nil
+}
@@ -14,6 +14,8 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	packages_model "code.gitea.io/gitea/models/packages"
+	container_model "code.gitea.io/gitea/models/packages/container"
+	quota_model "code.gitea.io/gitea/models/quota"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/json"
@@ -62,6 +64,7 @@ type PackageFileCreationInfo struct {
 	Data              packages_module.HashedSizeReader
 	IsLead            bool
 	Properties        map[string]string
+	Attestations      []string // validated against the file content and stored as file properties
 	OverwriteExisting bool
 }
 
@@ -262,6 +265,15 @@ func addFileToPackageVersion(ctx context.Context, pv *packages_model.PackageVers
 func addFileToPackageVersionUnchecked(ctx context.Context, pv *packages_model.PackageVersion, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
 	log.Trace("Adding package file: %v, %s", pv.ID, pfci.Filename)
 
+	if len(pfci.Attestations) > 0 {
+		_, _, hashSHA256, _ := pfci.Data.Sums()
+		for _, a := range pfci.Attestations {
+			if err := ValidateAttestation([]byte(a), hex.EncodeToString(hashSHA256)); err != nil {
+				return nil, nil, false, err
+			}
+		}
+	}
+
 	pb, exists, err := packages_model.GetOrInsertBlob(ctx, NewPackageBlob(pfci.Data))
 	if err != nil {
 		log.Error("Error inserting package blob: %v", err)
@@ -317,6 +329,13 @@ func addFileToPackageVersionUnchecked(ctx context.Context, pv *packages_model.Pa
 		}
 	}
 
+	for _, a := range pfci.Attestations {
+		if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeFile, pf.ID, packages_model.PropertyAttestation, a); err != nil {
+			log.Error("Error setting package file attestation: %v", err)
+			return pf, pb, !exists, err
+		}
+	}
+
 	return pf, pb, !exists, nil
 }
 
@@ -355,6 +374,8 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 	switch packageType {
 	case packages_model.TypeAlpine:
 		typeSpecificSize = setting.Packages.LimitSizeAlpine
+	case packages_model.TypeArch:
+		typeSpecificSize = setting.Packages.LimitSizeArch
 	case packages_model.TypeCargo:
 		typeSpecificSize = setting.Packages.LimitSizeCargo
 	case packages_model.TypeChef:
@@ -393,6 +414,8 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		typeSpecificSize = setting.Packages.LimitSizeRubyGems
 	case packages_model.TypeSwift:
 		typeSpecificSize = setting.Packages.LimitSizeSwift
+	case packages_model.TypeTerraform:
+		typeSpecificSize = setting.Packages.LimitSizeTerraform
 	case packages_model.TypeVagrant:
 		typeSpecificSize = setting.Packages.LimitSizeVagrant
 	}
@@ -413,6 +436,15 @@ func CheckSizeQuotaExceeded(ctx context.Context, doer, owner *user_model.User, p
 		}
 	}
 
+	ok, err := quota_model.EvaluateForUser(ctx, owner.ID, quota_model.LimitSubjectSizeAssetsPackagesAll, uploadSize)
+	if err != nil {
+		log.Error("EvaluateForUser failed: %v", err)
+		return err
+	}
+	if !ok {
+		return ErrQuotaTotalSize
+	}
+
 	return nil
 }
 
@@ -552,6 +584,10 @@ func DeletePackageVersionAndReferences(ctx context.Context, pv *packages_model.P
 		}
 	}
 
+	if err := container_model.DeleteComponentsByVersionID(ctx, pv.ID); err != nil {
+		return err
+	}
+
 	return packages_model.DeleteVersionByID(ctx, pv.ID)
 }
 
@@ -0,0 +1,228 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/hostmatcher"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// PropertyUpstream marks a package whose files were fetched from an upstream
+const PropertyUpstream = "upstream.url"
+
+// maxMetadataSize limits the size of metadata documents fetched from an upstream
+const maxMetadataSize = 64 * 1024 * 1024
+
+var (
+	ErrUpstreamNotFound = util.NewNotExistErrorf("package does not exist in upstream")
+	ErrUpstreamResponse = errors.New("invalid upstream response")
+)
+
+var httpClient *http.Client
+
+// Init creates the http client used to access upstream registries
+func Init() error {
+	allowedHostListValue := setting.Packages.UpstreamAllowedHostList
+	if allowedHostListValue == "" {
+		allowedHostListValue = hostmatcher.MatchBuiltinExternal
+	}
+	allowedHostMatcher := hostmatcher.ParseHostMatchList("packages.UPSTREAM_ALLOWED_HOST_LIST", allowedHostListValue)
+
+	httpClient = &http.Client{
+		Timeout: time.Duration(setting.Packages.UpstreamTimeout) * time.Second,
+		Transport: &http.Transport{
+			Proxy:       proxy.Proxy(),
+			DialContext: hostmatcher.NewDialContext("package upstream", allowedHostMatcher, nil, nil),
+		},
+	}
+	return nil
+}
+
+// GetUpstreamForPackage returns the enabled upstream of the owner which should be used to resolve the package.
+// A package which was published locally or is provided by a member of a virtual registry of the owner
+// always takes precedence over the upstream, so nil is returned in that case.
+func GetUpstreamForPackage(ctx context.Context, ownerID int64, packageType packages_model.Type, name string) (*packages_model.PackageUpstream, error) {
+	pu, err := packages_model.GetUpstreamByOwnerAndType(ctx, ownerID, packageType)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !pu.Enabled {
+		return nil, nil
+	}
+
+	has, err := packages_model.HasVirtualRegistryMemberPackage(ctx, ownerID, packageType, name)
+	if err != nil {
+		return nil, err
+	}
+	if has {
+		return nil, nil
+	}
+
+	p, err := packages_model.GetPackageByName(ctx, ownerID, packageType, name)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			return pu, nil
+		}
+		return nil, err
+	}
+
+	pps, err := packages_model.GetPropertiesByName(ctx, packages_model.PropertyTypePackage, p.ID, PropertyUpstream)
+	if err != nil {
+		return nil, err
+	}
+	if len(pps) == 0 {
+		return nil, nil
+	}
+	return pu, nil
+}
+
+// ResolveURL resolves a path relative to the upstream url. Absolute urls are returned unchanged.
+func ResolveURL(pu *packages_model.PackageUpstream, ref string) string {
+	if u, err := url.Parse(ref); err == nil && u.IsAbs() {
+		return ref
+	}
+	return strings.TrimSuffix(pu.URL, "/") + "/" + strings.TrimPrefix(ref, "/")
+}
+
+func doRequest(ctx context.Context, rawURL, accept string) (*http.Response, error) {
+	if httpClient == nil {
+		if err := Init(); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUpstreamResponse, err)
+	}
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+		resp.Body.Close()
+		return nil, ErrUpstreamNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s returned %d", ErrUpstreamResponse, rawURL, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// GetMetadata returns the metadata document stored at the path of the upstream.
+// The document is cached and refetched after the metadata ttl of the upstream expired.
+// If the upstream is not reachable a stale cached document is returned.
+func GetMetadata(ctx context.Context, pu *packages_model.PackageUpstream, path, accept string) (*packages_model.PackageUpstreamCache, error) {
+	cached, err := packages_model.GetUpstreamCache(ctx, pu.ID, path)
+	if err != nil && !errors.Is(err, util.ErrNotExist) {
+		return nil, err
+	}
+	if cached != nil && cached.IsFresh(pu.MetadataTTLDuration()) {
+		return cached, nil
+	}
+
+	puc, err := fetchMetadata(ctx, pu, path, accept)
+	if err != nil {
+		if cached != nil && !errors.Is(err, ErrUpstreamNotFound) {
+			log.Warn("Serving stale metadata for %s from upstream %d: %v", path, pu.ID, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if err := packages_model.UpsertUpstreamCache(ctx, puc); err != nil {
+		return nil, err
+	}
+	return puc, nil
+}
+
+func fetchMetadata(ctx context.Context, pu *packages_model.PackageUpstream, path, accept string) (*packages_model.PackageUpstreamCache, error) {
+	resp, err := doRequest(ctx, ResolveURL(pu, path), accept)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > maxMetadataSize {
+		return nil, fmt.Errorf("%w: metadata of %s is too large", ErrUpstreamResponse, path)
+	}
+
+	return &packages_model.PackageUpstreamCache{
+		UpstreamID:  pu.ID,
+		Path:        path,
+		ContentType: resp.Header.Get("Content-Type"),
+		Content:     content,
+		FetchedUnix: timeutil.TimeStampNow(),
+	}, nil
+}
+
+// FetchFile downloads a package file from the upstream
+func FetchFile(ctx context.Context, pu *packages_model.PackageUpstream, ref string) (*packages_module.HashedBuffer, error) {
+	resp, err := doRequest(ctx, ResolveURL(pu, ref), "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return packages_module.CreateHashedBufferFromReader(resp.Body)
+}
+
+// StoreFile stores a file fetched from the upstream so that subsequent requests are served locally
+func StoreFile(ctx context.Context, pu *packages_model.PackageUpstream, doer *user_model.User, pvci *packages_service.PackageCreationInfo, pfci *packages_service.PackageFileCreationInfo) (*packages_model.PackageVersion, *packages_model.PackageFile, error) {
+	if doer == nil {
+		doer = user_model.NewGhostUser()
+	}
+	pvci.Creator = doer
+	pfci.Creator = doer
+
+	if pvci.PackageProperties == nil {
+		pvci.PackageProperties = make(map[string]string)
+	}
+	pvci.PackageProperties[PropertyUpstream] = pu.URL
+
+	pv, pf, err := packages_service.CreatePackageOrAddFileToExisting(ctx, pvci, pfci)
+	if err == packages_model.ErrDuplicatePackageFile {
+		// the file was stored by a concurrent request
+		pv, err = packages_model.GetVersionByNameAndVersion(ctx, pvci.Owner.ID, pvci.PackageType, pvci.Name, pvci.Version)
+		if err != nil {
+			return nil, nil, err
+		}
+		pf, err = packages_model.GetFileForVersionByName(ctx, pv.ID, pfci.Filename, pfci.CompositeKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pv, pf, nil
+	}
+	return pv, pf, err
+}
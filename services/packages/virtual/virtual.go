@@ -0,0 +1,114 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package virtual
+
+import (
+	"context"
+	"errors"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/perm"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/util"
+	gitea_context "code.gitea.io/gitea/services/context"
+)
+
+// GetRegistryOwners returns the owners whose packages are served by the registry of the owner, ordered by priority.
+// The owner itself always comes first and is the only entry if the owner has no enabled virtual registry.
+// Members whose packages are not readable by the doer are skipped.
+func GetRegistryOwners(ctx context.Context, owner, doer *user_model.User, packageType packages_model.Type) ([]*user_model.User, error) {
+	owners := []*user_model.User{owner}
+
+	pvr, err := packages_model.GetVirtualRegistryByOwnerAndType(ctx, owner.ID, packageType)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			return owners, nil
+		}
+		return nil, err
+	}
+	if !pvr.Enabled {
+		return owners, nil
+	}
+
+	memberIDs, err := packages_model.GetVirtualRegistryMemberIDs(ctx, pvr.ID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := user_model.GetUsersByIDs(ctx, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+	membersByID := make(map[int64]*user_model.User, len(members))
+	for _, member := range members {
+		membersByID[member.ID] = member
+	}
+
+	for _, memberID := range memberIDs {
+		member, ok := membersByID[memberID]
+		if !ok || member.ID == owner.ID {
+			continue
+		}
+		accessMode, err := gitea_context.DeterminePackageAccessMode(ctx, member, doer)
+		if err != nil {
+			return nil, err
+		}
+		if accessMode < perm.AccessModeRead {
+			continue
+		}
+		owners = append(owners, member)
+	}
+	return owners, nil
+}
+
+// GetVersionsByPackageName returns the versions of the package merged from all owners of the registry.
+// If several owners provide the same version, the version of the owner with the highest priority is used.
+func GetVersionsByPackageName(ctx context.Context, owner, doer *user_model.User, packageType packages_model.Type, name string) ([]*packages_model.PackageVersion, error) {
+	owners, err := GetRegistryOwners(ctx, owner, doer, packageType)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(container.Set[string])
+	merged := make([]*packages_model.PackageVersion, 0, 10)
+	for _, o := range owners {
+		pvs, err := packages_model.GetVersionsByPackageName(ctx, o.ID, packageType, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pv := range pvs {
+			if seen.Add(pv.LowerVersion) {
+				merged = append(merged, pv)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// GetFileForVersionByName returns the file of the first owner of the registry which provides it
+func GetFileForVersionByName(ctx context.Context, owner, doer *user_model.User, packageType packages_model.Type, name, version, filename, key string) (*packages_model.PackageFile, error) {
+	owners, err := GetRegistryOwners(ctx, owner, doer, packageType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range owners {
+		pv, err := packages_model.GetVersionByNameAndVersion(ctx, o.ID, packageType, name, version)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		pf, err := packages_model.GetFileForVersionByName(ctx, pv.ID, filename, key)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		return pf, nil
+	}
+	return nil, packages_model.ErrPackageFileNotExist
+}
@@ -65,6 +65,7 @@ const (
 	MergeCheckTypeGeneral  MergeCheckType = iota // general merge checks for "merge", "rebase", "squash", etc
 	MergeCheckTypeManually                       // Manually Merged button (mark a PR as merged manually)
 	MergeCheckTypeAuto                           // Auto Merge (Scheduled Merge) After Checks Succeed
+	MergeCheckTypeQueue                          // Merge Queue, the status checks are required for the merge group instead of the pull request
 )
 
 // CheckPullMergeable check if the pull mergeable based on all conditions (branch protection, merge options, ...)
@@ -105,7 +106,13 @@ func CheckPullMergeable(stdCtx context.Context, doer *user_model.User, perm *acc
 			return ErrIsChecking
 		}
 
-		if err := CheckPullBranchProtections(ctx, pr, false); err != nil {
+		var err error
+		if mergeCheckType == MergeCheckTypeQueue {
+			err = CheckMergeQueueBranchProtections(ctx, pr)
+		} else {
+			err = CheckPullBranchProtections(ctx, pr, false)
+		}
+		if err != nil {
 			if !models.IsErrDisallowedToMerge(err) {
 				log.Error("Error whilst checking pull branch protection for %-v: %v", pr, err)
 				return err
@@ -134,6 +141,13 @@ func CheckPullMergeable(stdCtx context.Context, doer *user_model.User, perm *acc
 			}
 		}
 
+		// the rulesets of the owner and the instance can't be skipped by repo admins, the auto merge is checked when it is pushed
+		if mergeCheckType != MergeCheckTypeAuto {
+			if err := CheckPullRulesets(ctx, pr, doer); err != nil {
+				return err
+			}
+		}
+
 		if _, err := isSignedIfRequired(ctx, pr, doer); err != nil {
 			return err
 		}
@@ -0,0 +1,227 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	org_model "code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/optional"
+)
+
+// CodeOwnerApproval is the approval state of a code owner of a changed path
+type CodeOwnerApproval struct {
+	User     *user_model.User
+	Team     *org_model.Team
+	Approved bool
+}
+
+// CodeOwnersPathApproval is the approval state of the code owners of a changed path
+type CodeOwnersPathApproval struct {
+	Path   string
+	Owners []*CodeOwnerApproval
+}
+
+// IsApproved returns true if every code owner of the path has approved it
+func (p *CodeOwnersPathApproval) IsApproved() bool {
+	for _, o := range p.Owners {
+		if !o.Approved {
+			return false
+		}
+	}
+	return true
+}
+
+// CodeOwnersApprovals is the approval state of all changed paths which have code owners
+type CodeOwnersApprovals []*CodeOwnersPathApproval
+
+// IsApproved returns true if all changed paths are approved by their code owners
+func (a CodeOwnersApprovals) IsApproved() bool {
+	for _, p := range a {
+		if !p.IsApproved() {
+			return false
+		}
+	}
+	return true
+}
+
+// ApprovedCount returns the number of approved paths
+func (a CodeOwnersApprovals) ApprovedCount() int {
+	count := 0
+	for _, p := range a {
+		if p.IsApproved() {
+			count++
+		}
+	}
+	return count
+}
+
+// codeOwnerApprover is the latest approving review of a user and the paths changed since the reviewed commit
+type codeOwnerApprover struct {
+	Review       *issues_model.Review
+	ChangedPaths container.Set[string] // nil if all paths must be considered changed
+}
+
+func (a *codeOwnerApprover) hasApproved(path string) bool {
+	return a.ChangedPaths != nil && !a.ChangedPaths.Contains(path)
+}
+
+// GetCodeOwnersApprovals evaluates the CODEOWNERS file of the base branch against the files changed by the pull request.
+// Every user and team owning a changed path has to approve it. A team has approved if one of its members has approved.
+// An approval only counts for a path if the path did not change after the reviewed commit.
+// The poster of the pull request can't approve it, so they are never required as an owner.
+func GetCodeOwnersApprovals(ctx context.Context, pr *issues_model.PullRequest) (CodeOwnersApprovals, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := gitrepo.OpenRepository(ctx, pr.BaseRepo)
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(pr.BaseBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := issues_model.GetCodeOwnersFromCommit(ctx, commit)
+	if len(rules) == 0 {
+		return CodeOwnersApprovals{}, nil
+	}
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+
+	// https://github.com/go-gitea/gitea/issues/29763, the files changed between the merge base and the head commit are needed
+	mergeBase, _, err := git.NewCommand(ctx, "merge-base").AddDashesAndList(git.BranchPrefix+pr.BaseBranch, headCommitID).RunStdString(&git.RunOpts{Dir: gitRepo.Path})
+	if err != nil {
+		return nil, err
+	}
+
+	changedFiles, err := gitRepo.GetFilesChangedBetween(strings.TrimSpace(mergeBase), headCommitID)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(changedFiles)
+
+	reviews, err := issues_model.FindLatestReviews(ctx, issues_model.FindReviewOptions{
+		IssueID:   pr.IssueID,
+		Types:     []issues_model.ReviewType{issues_model.ReviewTypeApprove, issues_model.ReviewTypeReject},
+		Dismissed: optional.Some(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	approvers := make([]*codeOwnerApprover, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Type != issues_model.ReviewTypeApprove || review.ReviewerID <= 0 {
+			continue
+		}
+
+		approver := &codeOwnerApprover{Review: review}
+		if review.CommitID == headCommitID {
+			approver.ChangedPaths = container.Set[string]{}
+		} else if review.CommitID != "" {
+			changed, err := gitRepo.GetFilesChangedBetween(review.CommitID, headCommitID)
+			if err != nil {
+				// the reviewed commit may be gone after a force push
+				log.Debug("GetFilesChangedBetween[%s, %s]: %v", review.CommitID, headCommitID, err)
+			} else {
+				approver.ChangedPaths = container.SetOf(changed...)
+			}
+		}
+		approvers = append(approvers, approver)
+	}
+
+	teamMembers := make(map[[2]int64]bool)
+	isTeamMember := func(team *org_model.Team, userID int64) (bool, error) {
+		key := [2]int64{team.ID, userID}
+		if isMember, has := teamMembers[key]; has {
+			return isMember, nil
+		}
+		isMember, err := org_model.IsTeamMember(ctx, team.OrgID, team.ID, userID)
+		if err != nil {
+			return false, err
+		}
+		teamMembers[key] = isMember
+		return isMember, nil
+	}
+
+	approvals := make(CodeOwnersApprovals, 0, len(changedFiles))
+	for _, file := range changedFiles {
+		users := make([]*user_model.User, 0, 2)
+		teams := make([]*org_model.Team, 0, 2)
+		seenUsers := make(container.Set[int64])
+		seenTeams := make(container.Set[int64])
+		for _, rule := range rules {
+			if rule.Rule.MatchString(file) == rule.Negative {
+				continue
+			}
+			for _, u := range rule.Users {
+				if u.ID != pr.Issue.PosterID && seenUsers.Add(u.ID) {
+					users = append(users, u)
+				}
+			}
+			for _, t := range rule.Teams {
+				if seenTeams.Add(t.ID) {
+					teams = append(teams, t)
+				}
+			}
+		}
+		if len(users) == 0 && len(teams) == 0 {
+			continue
+		}
+
+		pathApproval := &CodeOwnersPathApproval{
+			Path:   file,
+			Owners: make([]*CodeOwnerApproval, 0, len(users)+len(teams)),
+		}
+		for _, u := range users {
+			owner := &CodeOwnerApproval{User: u}
+			for _, approver := range approvers {
+				if approver.Review.ReviewerID == u.ID && approver.hasApproved(file) {
+					owner.Approved = true
+					break
+				}
+			}
+			pathApproval.Owners = append(pathApproval.Owners, owner)
+		}
+		for _, t := range teams {
+			owner := &CodeOwnerApproval{Team: t}
+			for _, approver := range approvers {
+				if !approver.hasApproved(file) {
+					continue
+				}
+				isMember, err := isTeamMember(t, approver.Review.ReviewerID)
+				if err != nil {
+					return nil, err
+				}
+				if isMember {
+					owner.Approved = true
+					break
+				}
+			}
+			pathApproval.Owners = append(pathApproval.Owners, owner)
+		}
+		approvals = append(approvals, pathApproval)
+	}
+
+	return approvals, nil
+}
@@ -0,0 +1,270 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	notify_service "code.gitea.io/gitea/services/notify"
+)
+
+// MergeGroupRefPrefix is the prefix of the refs of the temporary merge groups built by the merge queue
+const MergeGroupRefPrefix = "refs/merge-queue/"
+
+// GetMergeGroupRefName returns the ref of the merge group which ends with the pull request
+func GetMergeGroupRefName(pr *issues_model.PullRequest) string {
+	return fmt.Sprintf("%s%s/pr-%d", MergeGroupRefPrefix, pr.BaseBranch, pr.Index)
+}
+
+// CreateMergeGroup merges the pull request onto the given ref of the base repository the way it would be merged
+// into its base branch and stores the result as the merge group ref of the pull request.
+// It returns the commit the merge group was built on and the merge group commit.
+func CreateMergeGroup(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, mergeStyle repo_model.MergeStyle, expectedHeadCommitID, message, ontoRefName string) (baseCommitID, groupCommitID string, err error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return "", "", fmt.Errorf("LoadBaseRepo: %w", err)
+	}
+	if err := pr.LoadHeadRepo(ctx); err != nil {
+		return "", "", fmt.Errorf("LoadHeadRepo: %w", err)
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return "", "", fmt.Errorf("LoadIssue: %w", err)
+	}
+
+	switch mergeStyle {
+	case repo_model.MergeStyleMerge, repo_model.MergeStyleRebase, repo_model.MergeStyleRebaseMerge, repo_model.MergeStyleSquash:
+	default:
+		// fast-forward only merges can't be stacked on other pull requests
+		return "", "", models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return "", "", fmt.Errorf("LoadProtectedBranch: %w", err)
+	}
+	if pb != nil && !pb.IsMergeStyleAllowed(mergeStyle) {
+		// the protected branch rule may have been changed after the pull request was added to the merge queue
+		return "", "", models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+
+	mergeCtx, cancel, err := createTemporaryRepoForMergeOnto(ctx, pr, doer, expectedHeadCommitID, ontoRefName)
+	if err != nil {
+		return "", "", err
+	}
+	defer cancel()
+
+	baseCommitID, err = git.GetFullCommitID(ctx, mergeCtx.tmpBasePath, baseBranch)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get full commit id for %s: %w", ontoRefName, err)
+	}
+
+	if err := doMergeStyle(mergeCtx, mergeStyle, message); err != nil {
+		return "", "", err
+	}
+
+	groupCommitID, err = git.GetFullCommitID(ctx, mergeCtx.tmpBasePath, baseBranch)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get full commit id for the merge group: %w", err)
+	}
+
+	if setting.LFS.StartServer {
+		if err := LFSPush(ctx, mergeCtx.tmpBasePath, groupCommitID, baseCommitID, pr); err != nil {
+			return "", "", err
+		}
+	}
+
+	// The merge group refs are not branches, so the hooks don't need to run on them
+	if err := git.Push(ctx, mergeCtx.tmpBasePath, git.PushOptions{
+		Remote: "origin",
+		Branch: baseBranch + ":" + GetMergeGroupRefName(pr),
+		Force:  true,
+		Env:    repo_module.InternalPushingEnvironment(doer, pr.BaseRepo),
+	}); err != nil {
+		return "", "", fmt.Errorf("push merge group of %-v: %w", pr, err)
+	}
+
+	return baseCommitID, groupCommitID, nil
+}
+
+// DeleteMergeGroupRef deletes the merge group ref of the pull request
+func DeleteMergeGroupRef(ctx context.Context, pr *issues_model.PullRequest) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+	_, _, err := git.NewCommand(ctx, "update-ref", "-d").AddDynamicArguments(GetMergeGroupRefName(pr)).
+		RunStdString(&git.RunOpts{Dir: pr.BaseRepo.RepoPath()})
+	return err
+}
+
+// GetMergeGroupCommitStatusState returns the state of the required status checks of a merge group commit
+func GetMergeGroupCommitStatusState(ctx context.Context, pb *git_model.ProtectedBranch, groupCommitID string) (structs.CommitStatusState, error) {
+	if !pb.EnableStatusCheck {
+		return structs.CommitStatusSuccess, nil
+	}
+
+	commitStatuses, _, err := git_model.GetLatestCommitStatus(ctx, pb.RepoID, groupCommitID, db.ListOptionsAll)
+	if err != nil {
+		return "", fmt.Errorf("GetLatestCommitStatus: %w", err)
+	}
+	if len(commitStatuses) == 0 {
+		return structs.CommitStatusPending, nil
+	}
+	return MergeRequiredContextsCommitStatus(commitStatuses, pb.StatusCheckContexts), nil
+}
+
+// CheckMergeQueueBranchProtections checks whether the PR can be added to the merge queue (reviews),
+// the status checks are required for its merge group instead of the PR itself
+func CheckMergeQueueBranchProtections(ctx context.Context, pr *issues_model.PullRequest) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return fmt.Errorf("LoadBaseRepo: %w", err)
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return fmt.Errorf("LoadProtectedBranch: %v", err)
+	}
+	if pb == nil {
+		return nil
+	}
+	return checkPullReviewProtections(ctx, pb, pr, false)
+}
+
+// checkMergeGroupCommit checks whether the commit is the merge group of the PR in the merge queue of its base branch
+func checkMergeGroupCommit(ctx context.Context, pr *issues_model.PullRequest, groupCommitID string) (*git_model.ProtectedBranch, *pull_model.MergeQueueEntry, error) {
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("LoadProtectedBranch: %v", err)
+	}
+	if pb == nil || !pb.EnableMergeQueue {
+		return nil, nil, models.ErrDisallowedToMerge{
+			Reason: "The merge queue is not enabled for the branch",
+		}
+	}
+
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist || entry.GroupCommitID != groupCommitID {
+		return nil, nil, models.ErrDisallowedToMerge{
+			Reason: "The commit is not the merge group of the pull request",
+		}
+	}
+	return pb, entry, nil
+}
+
+// CheckMergeGroupCommit checks whether the commit is the merge group of the PR, it can't be skipped by admins
+// because the PRs of the merge group are marked as merged with their merge group commits
+func CheckMergeGroupCommit(ctx context.Context, pr *issues_model.PullRequest, groupCommitID string) error {
+	_, _, err := checkMergeGroupCommit(ctx, pr, groupCommitID)
+	return err
+}
+
+// CheckMergeGroupBranchProtections checks whether the merge group which ends with the PR is ready to be merged,
+// the required status checks of the merge group commit and the reviews of all the PRs of the group are checked
+func CheckMergeGroupBranchProtections(ctx context.Context, pr *issues_model.PullRequest, groupCommitID string) error {
+	pb, entry, err := checkMergeGroupCommit(ctx, pr, groupCommitID)
+	if err != nil {
+		return err
+	}
+
+	state, err := GetMergeGroupCommitStatusState(ctx, pb, groupCommitID)
+	if err != nil {
+		return err
+	}
+	if !state.IsSuccess() {
+		return models.ErrDisallowedToMerge{
+			Reason: "Not all required status checks of the merge group successful",
+		}
+	}
+
+	entries, err := pull_model.GetMergeQueueEntries(ctx, entry.RepoID, entry.BaseBranch)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ID > entry.ID {
+			break
+		}
+		groupPR, err := issues_model.GetPullRequestByID(ctx, e.PullID)
+		if err != nil {
+			return err
+		}
+		if err := checkPullReviewProtections(ctx, pb, groupPR, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeMergeGroup fast-forwards the base branch to the merge group commit which ends with the last one of the PRs,
+// prs are all the PRs of the merge group in the order of the merge queue.
+// The PRs are marked as merged by the post-receive hook.
+func MergeMergeGroup(ctx context.Context, doer *user_model.User, prs []*issues_model.PullRequest, groupCommitID string) error {
+	last := prs[len(prs)-1]
+	if err := last.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+	baseRepo := last.BaseRepo
+
+	env := repo_module.FullPushingEnvironment(doer, doer, baseRepo, baseRepo.Name, last.ID)
+	env = append(env, repo_module.EnvPushTrigger+"="+string(repo_module.PushTriggerMergeQueue))
+
+	// Push the merge group commit from the base repository into itself, so that the hooks run as for any other merge
+	if err := git.Push(ctx, baseRepo.RepoPath(), git.PushOptions{
+		Remote: baseRepo.RepoPath(),
+		Branch: groupCommitID + ":" + git.BranchPrefix + last.BaseBranch,
+		Env:    env,
+	}); err != nil {
+		return err
+	}
+
+	defer func() {
+		go AddTestPullRequestTask(doer, baseRepo.ID, last.BaseBranch, false, "", "")
+	}()
+
+	for _, groupPR := range prs {
+		// reload pull request because it has been updated by post receive hook
+		pr, err := issues_model.GetPullRequestByID(ctx, groupPR.ID)
+		if err != nil {
+			return err
+		}
+		if err := pr.LoadIssue(ctx); err != nil {
+			log.Error("LoadIssue %-v: %v", pr, err)
+		}
+		if err := pr.Issue.LoadRepo(ctx); err != nil {
+			log.Error("pr.Issue.LoadRepo %-v: %v", pr, err)
+		}
+		if err := pr.Issue.Repo.LoadOwner(ctx); err != nil {
+			log.Error("LoadOwner for %-v: %v", pr, err)
+		}
+
+		notify_service.MergePullRequest(ctx, doer, pr)
+
+		if err := DeleteMergeGroupRef(ctx, pr); err != nil {
+			log.Error("DeleteMergeGroupRef %-v: %v", pr, err)
+		}
+		if err := handleCloseCrossReferences(ctx, pr, doer); err != nil {
+			log.Error("handleCloseCrossReferences %-v: %v", pr, err)
+		}
+	}
+
+	// Reset cached commit count
+	cache.Remove(baseRepo.GetCommitsCountCacheKey(last.BaseBranch, true))
+
+	return nil
+}
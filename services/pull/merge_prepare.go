@@ -44,6 +44,12 @@ func (ctx *mergeContext) RunOpts() *git.RunOpts {
 }
 
 func createTemporaryRepoForMerge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, expectedHeadCommitID string) (mergeCtx *mergeContext, cancel context.CancelFunc, err error) {
+	return createTemporaryRepoForMergeOnto(ctx, pr, doer, expectedHeadCommitID, "")
+}
+
+// createTemporaryRepoForMergeOnto creates a temporary repo to merge the pull request onto the given ref of the base repository,
+// the base branch of the pull request is used if ontoRefName is empty
+func createTemporaryRepoForMergeOnto(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, expectedHeadCommitID, ontoRefName string) (mergeCtx *mergeContext, cancel context.CancelFunc, err error) {
 	// Clone base repo.
 	prCtx, cancel, err := createTemporaryRepoForPR(ctx, pr)
 	if err != nil {
@@ -72,6 +78,15 @@ func createTemporaryRepoForMerge(ctx context.Context, pr *issues_model.PullReque
 		}
 	}
 
+	if ontoRefName != "" {
+		if err := git.NewCommand(ctx, "fetch", "--no-tags", "--update-head-ok", "origin").AddDynamicArguments("+" + ontoRefName + ":" + git.BranchPrefix + baseBranch).
+			Run(mergeCtx.RunOpts()); err != nil {
+			defer cancel()
+			log.Error("%-v Unable to fetch %s of the base repository in %s: %v\n%s\n%s", pr, ontoRefName, mergeCtx.tmpBasePath, err, mergeCtx.outbuf.String(), mergeCtx.errbuf.String())
+			return nil, nil, fmt.Errorf("unable to fetch %s of the base repository: %w\n%s\n%s", ontoRefName, err, mergeCtx.outbuf.String(), mergeCtx.errbuf.String())
+		}
+	}
+
 	mergeCtx.outbuf.Reset()
 	mergeCtx.errbuf.Reset()
 	if err := prepareTemporaryRepoForMerge(mergeCtx); err != nil {
@@ -0,0 +1,116 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// EvaluatePullRulesets evaluates the pull request rules of the rulesets which apply to the base branch of the pull request.
+// The rulesets the doer is allowed to bypass are skipped, at most one violation is returned per ruleset.
+func EvaluatePullRulesets(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User) ([]*git_model.RulesetViolation, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	rulesets, err := git_model.GetRepoRulesets(ctx, pr.BaseRepo, git_model.RulesetTargetBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	refName := git.RefNameFromBranch(pr.BaseBranch)
+
+	var commitStatuses []*git_model.CommitStatus
+	var codeOwnersApprovals CodeOwnersApprovals
+
+	violations := make([]*git_model.RulesetViolation, 0, len(rulesets))
+	for _, rs := range rulesets {
+		if !rs.MatchRef(refName) {
+			continue
+		}
+		if doer != nil {
+			canBypass, err := rs.CanBypass(ctx, doer.ID)
+			if err != nil {
+				return nil, err
+			}
+			if canBypass {
+				continue
+			}
+		}
+
+		pb := rs.ToProtectedBranch(pr.BaseRepo)
+
+		reason := ""
+		switch {
+		case !issues_model.HasEnoughApprovals(ctx, pb, pr):
+			reason = "Does not have enough approvals"
+		case issues_model.MergeBlockedByRejectedReview(ctx, pb, pr):
+			reason = "There are requested changes"
+		case issues_model.MergeBlockedByOfficialReviewRequests(ctx, pb, pr):
+			reason = "There are official review requests"
+		case issues_model.MergeBlockedByOutdatedBranch(pb, pr):
+			reason = "The head branch is behind the base branch"
+		}
+
+		if reason == "" && rs.EnableStatusCheck {
+			if commitStatuses == nil {
+				if commitStatuses, err = getPullRequestHeadCommitStatuses(ctx, pr); err != nil {
+					return nil, err
+				}
+			}
+			if !MergeRequiredContextsCommitStatus(commitStatuses, rs.StatusCheckContexts).IsSuccess() {
+				reason = "Not all required status checks successful"
+			}
+		}
+
+		if reason == "" && rs.RequireCodeOwnerApproval {
+			if codeOwnersApprovals == nil {
+				if codeOwnersApprovals, err = GetCodeOwnersApprovals(ctx, pr); err != nil {
+					return nil, err
+				}
+			}
+			if !codeOwnersApprovals.IsApproved() {
+				reason = "Not all code owners have approved"
+			}
+		}
+
+		if reason != "" {
+			v := &git_model.RulesetViolation{
+				RulesetID: rs.ID,
+				Ruleset:   rs,
+				RepoID:    pr.BaseRepoID,
+				RefName:   refName,
+				Reason:    reason,
+			}
+			if doer != nil {
+				v.DoerID = doer.ID
+			}
+			violations = append(violations, v)
+		}
+	}
+	return violations, nil
+}
+
+// CheckPullRulesets returns an ErrDisallowedToMerge if the pull request violates an actively enforced ruleset.
+// The violations of rulesets in evaluate mode don't block the merge, they are recorded when the merge is pushed.
+func CheckPullRulesets(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User) error {
+	violations, err := EvaluatePullRulesets(ctx, pr, doer)
+	if err != nil {
+		return err
+	}
+	for _, v := range violations {
+		if v.Ruleset.IsActive() {
+			return models.ErrDisallowedToMerge{
+				Reason: v.Error(),
+			}
+		}
+	}
+	return nil
+}
@@ -63,6 +63,16 @@ func createTag(ctx context.Context, gitRepo *git.Repository, rel *repo_model.Rel
 				}
 			}
 
+			violation, err := git_model.CheckRulesetsRefAction(ctx, rel.Repo, git.RefNameFromTag(rel.TagName), rel.PublisherID, git_model.RulesetRefActionCreate)
+			if err != nil {
+				return false, err
+			}
+			if violation != nil {
+				return false, models.ErrProtectedTagName{
+					TagName: rel.TagName,
+				}
+			}
+
 			commit, err := gitRepo.GetCommit(rel.Target)
 			if err != nil {
 				return false, err
@@ -324,6 +334,16 @@ func DeleteReleaseByID(ctx context.Context, repo *repo_model.Repository, rel *re
 			}
 		}
 
+		violation, err := git_model.CheckRulesetsRefAction(ctx, repo, git.RefNameFromTag(rel.TagName), doer.ID, git_model.RulesetRefActionDelete)
+		if err != nil {
+			return err
+		}
+		if violation != nil {
+			return models.ErrProtectedTagName{
+				TagName: rel.TagName,
+			}
+		}
+
 		if stdout, _, err := git.NewCommand(ctx, "tag", "-d").AddDashesAndList(rel.TagName).
 			SetDescription(fmt.Sprintf("DeleteReleaseByID (git tag -d): %d", rel.ID)).
 			RunStdString(&git.RunOpts{Dir: repo.RepoPath()}); err != nil && !strings.Contains(err.Error(), "not found") {
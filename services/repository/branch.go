@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
+	actions_service "code.gitea.io/gitea/services/actions"
 	notify_service "code.gitea.io/gitea/services/notify"
 	files_service "code.gitea.io/gitea/services/repository/files"
 
@@ -414,6 +415,7 @@ func RenameBranch(ctx context.Context, repo *repo_model.Repository, doer *user_m
 		return "from_not_exist", nil
 	}
 
+	var cancelledJobs []*actions_model.ActionRunJob
 	if err := git_model.RenameBranch(ctx, repo, from, to, func(ctx context.Context, isDefault bool) error {
 		err2 := gitRepo.RenameBranch(from, to)
 		if err2 != nil {
@@ -426,13 +428,15 @@ func RenameBranch(ctx context.Context, repo *repo_model.Repository, doer *user_m
 				log.Error("DeleteCronTaskByRepo: %v", err)
 			}
 			// cancel running cron jobs of this repository and delete old schedules
-			if err := actions_model.CancelPreviousJobs(
+			var err error
+			cancelledJobs, err = actions_model.CancelPreviousJobs(
 				ctx,
 				repo.ID,
 				from,
 				"",
 				webhook_module.HookEventSchedule,
-			); err != nil {
+			)
+			if err != nil {
 				log.Error("CancelPreviousJobs: %v", err)
 			}
 
@@ -446,6 +450,8 @@ func RenameBranch(ctx context.Context, repo *repo_model.Repository, doer *user_m
 	}); err != nil {
 		return "", err
 	}
+	actions_service.NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
+
 	refNameTo := git.RefNameFromBranch(to)
 	refID, err := gitRepo.GetRefCommitID(refNameTo.String())
 	if err != nil {
@@ -482,6 +488,14 @@ func DeleteBranch(ctx context.Context, doer *user_model.User, repo *repo_model.R
 		return git_model.ErrBranchIsProtected
 	}
 
+	violation, err := git_model.CheckRulesetsRefAction(ctx, repo, git.RefNameFromBranch(branchName), doer.ID, git_model.RulesetRefActionDelete)
+	if err != nil {
+		return err
+	}
+	if violation != nil {
+		return git_model.ErrBranchIsProtected
+	}
+
 	rawBranch, err := git_model.GetBranch(ctx, repo.ID, branchName)
 	if err != nil && !git_model.IsErrBranchNotExist(err) {
 		return fmt.Errorf("GetBranch: %vc", err)
@@ -583,6 +597,7 @@ func SetRepoDefaultBranch(ctx context.Context, repo *repo_model.Repository, gitR
 
 	oldDefaultBranchName := repo.DefaultBranch
 	repo.DefaultBranch = newBranchName
+	var cancelledJobs []*actions_model.ActionRunJob
 	if err := db.WithTx(ctx, func(ctx context.Context) error {
 		if err := repo_model.UpdateDefaultBranch(ctx, repo); err != nil {
 			return err
@@ -592,13 +607,15 @@ func SetRepoDefaultBranch(ctx context.Context, repo *repo_model.Repository, gitR
 			log.Error("DeleteCronTaskByRepo: %v", err)
 		}
 		// cancel running cron jobs of this repository and delete old schedules
-		if err := actions_model.CancelPreviousJobs(
+		var err error
+		cancelledJobs, err = actions_model.CancelPreviousJobs(
 			ctx,
 			repo.ID,
 			oldDefaultBranchName,
 			"",
 			webhook_module.HookEventSchedule,
-		); err != nil {
+		)
+		if err != nil {
 			log.Error("CancelPreviousJobs: %v", err)
 		}
 
@@ -611,6 +628,7 @@ func SetRepoDefaultBranch(ctx context.Context, repo *repo_model.Repository, gitR
 	}); err != nil {
 		return err
 	}
+	actions_service.NotifyWorkflowJobsStatusUpdate(ctx, cancelledJobs...)
 
 	if !repo.IsEmpty {
 		if err := AddRepoToLicenseUpdaterQueue(&LicenseUpdaterOptions{
@@ -8,14 +8,41 @@ import (
 	"context"
 
 	"code.gitea.io/gitea/models"
+	audit_model "code.gitea.io/gitea/models/audit"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	audit_service "code.gitea.io/gitea/services/audit"
 )
 
+// AddCollaborator adds the user as a collaborator of the repository
+func AddCollaborator(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, u *user_model.User) error {
+	if err := repo_module.AddCollaborator(ctx, repo, u); err != nil {
+		return err
+	}
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionRepoCollaboratorAdd, repo, audit_service.UserTarget(u), "")
+	return nil
+}
+
+// ChangeCollaborationAccessMode sets the access mode of the collaborator of the repository
+func ChangeCollaborationAccessMode(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User, mode perm.AccessMode) error {
+	// Discard invalid input
+	if mode <= perm.AccessModeNone || mode > perm.AccessModeOwner {
+		return nil
+	}
+
+	if err := repo_model.ChangeCollaborationAccessMode(ctx, repo, collaborator.ID, mode); err != nil {
+		return err
+	}
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionRepoCollaboratorUpdate, repo, audit_service.UserTarget(collaborator), "permission: %s", mode.ToString())
+	return nil
+}
+
 // DeleteCollaboration removes collaboration relation between the user and repository.
-func DeleteCollaboration(ctx context.Context, repo *repo_model.Repository, collaborator *user_model.User) (err error) {
+func DeleteCollaboration(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, collaborator *user_model.User) (err error) {
 	collaboration := &repo_model.Collaboration{
 		RepoID: repo.ID,
 		UserID: collaborator.ID,
@@ -54,5 +81,9 @@ func DeleteCollaboration(ctx context.Context, repo *repo_model.Repository, colla
 		return err
 	}
 
-	return committer.Commit()
+	if err := committer.Commit(); err != nil {
+		return err
+	}
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionRepoCollaboratorRemove, repo, audit_service.UserTarget(collaborator), "")
+	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/services/automerge"
+	"code.gitea.io/gitea/services/mergequeue"
 )
 
 func getCacheKey(repoID int64, brancheName string) string {
@@ -120,6 +121,11 @@ func CreateCommitStatus(ctx context.Context, repo *repo_model.Repository, creato
 		}
 	}
 
+	// the merge queue merges or removes the pull requests depending on the status of their merge groups
+	if err := mergequeue.StartMergeQueueCheckBySHA(ctx, commit.ID.String(), repo); err != nil {
+		return fmt.Errorf("StartMergeQueueCheckBySHA[repo_id: %d, user_id: %d, sha: %s]: %w", repo.ID, creator.ID, sha, err)
+	}
+
 	return nil
 }
 
@@ -21,13 +21,16 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	secret_model "code.gitea.io/gitea/models/secret"
 	system_model "code.gitea.io/gitea/models/system"
+	terraform_model "code.gitea.io/gitea/models/terraform"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/models/webhook"
 	actions_module "code.gitea.io/gitea/modules/actions"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
+	actions_service "code.gitea.io/gitea/services/actions"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	terraform_service "code.gitea.io/gitea/services/terraform"
 
 	"xorm.io/builder"
 )
@@ -66,6 +69,18 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		return fmt.Errorf("list actions artifacts of repo %v: %w", repoID, err)
 	}
 
+	// Query the caches of this repo, they will be needed after they have been deleted to remove cache files in ObjectStorage
+	caches, err := db.Find[actions_model.ActionCache](ctx, actions_model.FindCachesOptions{RepoID: repoID})
+	if err != nil {
+		return fmt.Errorf("list actions caches of repo %v: %w", repoID, err)
+	}
+
+	// Query the terraform state versions of this repo, they will be needed after they have been deleted to remove the files in ObjectStorage
+	stateVersions, err := db.Find[terraform_model.TerraformStateVersion](ctx, terraform_model.FindStateVersionsOptions{RepoID: repoID})
+	if err != nil {
+		return fmt.Errorf("list terraform state versions of repo %v: %w", repoID, err)
+	}
+
 	// In case owner is a organization, we have to change repo specific teams
 	// if ignoreOrgTeams is not true
 	var org *user_model.User
@@ -146,6 +161,10 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		&activities_model.Notification{RepoID: repoID},
 		&git_model.ProtectedBranch{RepoID: repoID},
 		&git_model.ProtectedTag{RepoID: repoID},
+		&git_model.RulesetViolation{RepoID: repoID},
+		&git_model.PushRule{RepoID: repoID},
+		&git_model.SecretScanningAlert{RepoID: repoID},
+		&git_model.SecretScanningBackfill{RepoID: repoID},
 		&repo_model.PushMirror{RepoID: repoID},
 		&repo_model.Release{RepoID: repoID},
 		&repo_model.RepoIndexerStatus{RepoID: repoID},
@@ -165,6 +184,11 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		&actions_model.ActionSchedule{RepoID: repoID},
 		&actions_model.ActionArtifact{RepoID: repoID},
 		&actions_model.ActionRunnerToken{RepoID: repoID},
+		&actions_model.ActionEnvironment{RepoID: repoID},
+		&actions_model.ActionCache{RepoID: repoID},
+		&actions_model.ActionDeployment{RepoID: repoID},
+		&terraform_model.TerraformState{RepoID: repoID},
+		&terraform_model.TerraformStateVersion{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %w", err)
 	}
@@ -345,6 +369,16 @@ func DeleteRepositoryDirectly(ctx context.Context, doer *user_model.User, repoID
 		}
 	}
 
+	// delete actions caches in ObjectStorage after the repo have already been deleted
+	for _, c := range caches {
+		actions_service.RemoveCacheFiles(c)
+	}
+
+	// delete terraform state versions in ObjectStorage after the repo have already been deleted
+	for _, v := range stateVersions {
+		terraform_service.RemoveStateVersionFile(v)
+	}
+
 	return nil
 }
 
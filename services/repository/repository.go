@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	audit_model "code.gitea.io/gitea/models/audit"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -22,6 +23,7 @@ import (
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
+	audit_service "code.gitea.io/gitea/services/audit"
 	notify_service "code.gitea.io/gitea/services/notify"
 	pull_service "code.gitea.io/gitea/services/pull"
 )
@@ -67,6 +69,8 @@ func DeleteRepository(ctx context.Context, doer *user_model.User, repo *repo_mod
 		return err
 	}
 
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionRepoDelete, repo, audit_service.RepoTarget(repo), "")
+
 	return packages_model.UnlinkRepositoryFromAllPackages(ctx, repo.ID)
 }
 
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models"
+	audit_model "code.gitea.io/gitea/models/audit"
 	"code.gitea.io/gitea/models/db"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
@@ -22,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/util"
+	audit_service "code.gitea.io/gitea/services/audit"
 	notify_service "code.gitea.io/gitea/services/notify"
 )
 
@@ -67,6 +69,12 @@ func TransferOwnership(ctx context.Context, doer, newOwner *user_model.User, rep
 
 	notify_service.TransferRepository(ctx, doer, repo, oldOwner.Name)
 
+	// both the previous and the new owner can see the transfer in their audit logs
+	audit_service.Record(ctx, doer, audit_model.ActionRepoTransfer, oldOwner.ID, newRepo.ID, audit_service.RepoTarget(newRepo), "from %s to %s", oldOwner.Name, newOwner.Name)
+	if oldOwner.ID != newOwner.ID {
+		audit_service.Record(ctx, doer, audit_model.ActionRepoTransfer, newOwner.ID, newRepo.ID, audit_service.RepoTarget(newRepo), "from %s to %s", oldOwner.Name, newOwner.Name)
+	}
+
 	return nil
 }
 
@@ -436,6 +444,8 @@ func StartRepositoryTransfer(ctx context.Context, doer, newOwner *user_model.Use
 	// notify users who are able to accept / reject transfer
 	notify_service.RepoPendingTransfer(ctx, doer, newOwner, repo)
 
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionRepoTransferStart, repo, audit_service.RepoTarget(repo), "to %s", newOwner.Name)
+
 	return nil
 }
 
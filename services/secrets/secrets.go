@@ -6,11 +6,16 @@ package secrets
 import (
 	"context"
 
+	audit_model "code.gitea.io/gitea/models/audit"
 	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
 	secret_model "code.gitea.io/gitea/models/secret"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	audit_service "code.gitea.io/gitea/services/audit"
 )
 
-func CreateOrUpdateSecret(ctx context.Context, ownerID, repoID int64, name, data string) (*secret_model.Secret, bool, error) {
+func CreateOrUpdateSecret(ctx context.Context, doer *user_model.User, ownerID, repoID int64, name, data string) (*secret_model.Secret, bool, error) {
 	if err := ValidateName(name); err != nil {
 		return nil, false, err
 	}
@@ -29,17 +34,19 @@ func CreateOrUpdateSecret(ctx context.Context, ownerID, repoID int64, name, data
 		if err != nil {
 			return nil, false, err
 		}
+		recordSecretEvent(ctx, doer, audit_model.ActionSecretCreate, s)
 		return s, true, nil
 	}
 
 	if err := secret_model.UpdateSecret(ctx, s[0].ID, data); err != nil {
 		return nil, false, err
 	}
+	recordSecretEvent(ctx, doer, audit_model.ActionSecretUpdate, s[0])
 
 	return s[0], false, nil
 }
 
-func DeleteSecretByID(ctx context.Context, ownerID, repoID, secretID int64) error {
+func DeleteSecretByID(ctx context.Context, doer *user_model.User, ownerID, repoID, secretID int64) error {
 	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
 		OwnerID:  ownerID,
 		RepoID:   repoID,
@@ -52,10 +59,10 @@ func DeleteSecretByID(ctx context.Context, ownerID, repoID, secretID int64) erro
 		return secret_model.ErrSecretNotFound{}
 	}
 
-	return deleteSecret(ctx, s[0])
+	return deleteSecret(ctx, doer, s[0])
 }
 
-func DeleteSecretByName(ctx context.Context, ownerID, repoID int64, name string) error {
+func DeleteSecretByName(ctx context.Context, doer *user_model.User, ownerID, repoID int64, name string) error {
 	if err := ValidateName(name); err != nil {
 		return err
 	}
@@ -72,12 +79,101 @@ func DeleteSecretByName(ctx context.Context, ownerID, repoID int64, name string)
 		return secret_model.ErrSecretNotFound{}
 	}
 
-	return deleteSecret(ctx, s[0])
+	return deleteSecret(ctx, doer, s[0])
 }
 
-func deleteSecret(ctx context.Context, s *secret_model.Secret) error {
+func deleteSecret(ctx context.Context, doer *user_model.User, s *secret_model.Secret) error {
 	if _, err := db.DeleteByID[secret_model.Secret](ctx, s.ID); err != nil {
 		return err
 	}
+	recordSecretEvent(ctx, doer, audit_model.ActionSecretDelete, s)
 	return nil
 }
+
+// recordSecretEvent records the audit event of the secret for its owner, or the owner of its repository
+func recordSecretEvent(ctx context.Context, doer *user_model.User, action audit_model.Action, s *secret_model.Secret) {
+	target := audit_service.Target{Type: audit_model.TargetTypeSecret, ID: s.ID, Name: s.Name}
+	if s.RepoID == 0 {
+		audit_service.Record(ctx, doer, action, s.OwnerID, 0, target, "")
+		return
+	}
+
+	repo, err := repo_model.GetRepositoryByID(ctx, s.RepoID)
+	if err != nil {
+		log.Error("GetRepositoryByID[%d]: %v", s.RepoID, err)
+		return
+	}
+	if s.EnvironmentID > 0 {
+		audit_service.RecordRepo(ctx, doer, action, repo, target, "environment: %d", s.EnvironmentID)
+		return
+	}
+	audit_service.RecordRepo(ctx, doer, action, repo, target, "")
+}
+
+// CreateOrUpdateEnvironmentSecret creates or updates a secret of an environment of the repository
+func CreateOrUpdateEnvironmentSecret(ctx context.Context, doer *user_model.User, repoID, environmentID int64, name, data string) (*secret_model.Secret, bool, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, false, err
+	}
+
+	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		Name:          name,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(s) == 0 {
+		s, err := secret_model.InsertEncryptedEnvironmentSecret(ctx, repoID, environmentID, name, data)
+		if err != nil {
+			return nil, false, err
+		}
+		recordSecretEvent(ctx, doer, audit_model.ActionSecretCreate, s)
+		return s, true, nil
+	}
+
+	if err := secret_model.UpdateSecret(ctx, s[0].ID, data); err != nil {
+		return nil, false, err
+	}
+	recordSecretEvent(ctx, doer, audit_model.ActionSecretUpdate, s[0])
+
+	return s[0], false, nil
+}
+
+func DeleteEnvironmentSecretByID(ctx context.Context, doer *user_model.User, repoID, environmentID, secretID int64) error {
+	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		SecretID:      secretID,
+	})
+	if err != nil {
+		return err
+	}
+	if len(s) != 1 {
+		return secret_model.ErrSecretNotFound{}
+	}
+
+	return deleteSecret(ctx, doer, s[0])
+}
+
+func DeleteEnvironmentSecretByName(ctx context.Context, doer *user_model.User, repoID, environmentID int64, name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	s, err := db.Find[secret_model.Secret](ctx, secret_model.FindSecretsOptions{
+		RepoID:        repoID,
+		EnvironmentID: environmentID,
+		Name:          name,
+	})
+	if err != nil {
+		return err
+	}
+	if len(s) != 1 {
+		return secret_model.ErrSecretNotFound{}
+	}
+
+	return deleteSecret(ctx, doer, s[0])
+}
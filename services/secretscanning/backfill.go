@@ -0,0 +1,82 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package secretscanning
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+// BackfillRepositories scans the history of the repositories which have not been scanned yet.
+// The pushes to a repository are scanned by the push protection, so the history of every repository is only scanned once.
+func BackfillRepositories(ctx context.Context) error {
+	if !setting.SecretScanning.Enabled {
+		return nil
+	}
+
+	return db.Iterate(ctx, builder.Eq{"is_empty": false}, func(ctx context.Context, repo *repo_model.Repository) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before secret scanning of %s", repo.FullName())
+		default:
+		}
+
+		backfilled, err := git_model.IsSecretScanningBackfilled(ctx, repo.ID)
+		if err != nil {
+			return err
+		}
+		if backfilled {
+			return nil
+		}
+		if err := ScanRepositoryHistory(ctx, repo); err != nil {
+			// a broken repository must not prevent the other repositories from being scanned
+			log.Error("Unable to scan the history of %-v for secrets: %v", repo, err)
+		}
+		return nil
+	})
+}
+
+// ScanRepositoryHistory scans all commits of the repository for secrets and records an alert for every secret found
+func ScanRepositoryHistory(ctx context.Context, repo *repo_model.Repository) error {
+	gitRepo, err := gitrepo.OpenRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	// the oldest commits are scanned first, so that the alerts point to the commits which added the secrets
+	stdout, _, err := git.NewCommand(ctx, "rev-list", "--reverse", "--all").RunStdString(&git.RunOpts{Dir: repo.RepoPath()})
+	if err != nil {
+		return fmt.Errorf("rev-list: %w", err)
+	}
+
+	reader, err := git.NewPushReader(ctx, gitRepo, nil)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	leaks, err := ScanCommits(ctx, reader, strings.Fields(stdout), make(container.Set[string]))
+	if err != nil {
+		return err
+	}
+	if err := CreateAlerts(ctx, repo, leaks, nil, ""); err != nil {
+		return err
+	}
+	log.Trace("Found %d secrets in the history of %-v", len(leaks), repo)
+
+	return git_model.MarkSecretScanningBackfilled(ctx, repo.ID)
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package secretscanning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	audit_model "code.gitea.io/gitea/models/audit"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/secretscan"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	audit_service "code.gitea.io/gitea/services/audit"
+)
+
+// Leak is a secret found in a file added or modified by a commit
+type Leak struct {
+	CommitID string
+	Path     string
+	*secretscan.Finding
+}
+
+// String returns the location and the kind of the secret without revealing it
+func (l *Leak) String() string {
+	return fmt.Sprintf("commit %s, %s:%d: %s %s", l.CommitID, l.Path, l.Line, l.Pattern.Name, l.Redacted())
+}
+
+// ScanCommits scans the files added or modified by the commits for secrets.
+// The blobs in scanned are skipped and the scanned blobs are added to it, so that every blob is scanned only once.
+func ScanCommits(ctx context.Context, reader *git.PushReader, commitIDs []string, scanned container.Set[string]) ([]*Leak, error) {
+	scanner, err := secretscan.DefaultScanner()
+	if err != nil {
+		return nil, err
+	}
+
+	var leaks []*Leak
+	for _, commitID := range commitIDs {
+		files, err := reader.ChangedFiles(ctx, commitID)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			if !scanned.Add(file.BlobID) {
+				continue
+			}
+			if setting.SecretScanning.MaxBlobSize > 0 && file.Size > setting.SecretScanning.MaxBlobSize {
+				continue
+			}
+			content, err := reader.BlobContent(file.BlobID)
+			if err != nil {
+				return nil, err
+			}
+			for _, finding := range scanner.Scan(content) {
+				leaks = append(leaks, &Leak{CommitID: commitID, Path: file.Path, Finding: finding})
+			}
+		}
+	}
+	return leaks, nil
+}
+
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// CreateAlerts records an alert for every leaked secret the repository has no alert for yet.
+// If the secrets have been pushed by bypassing the push protection, the bypasser and the reason are recorded too.
+func CreateAlerts(ctx context.Context, repo *repo_model.Repository, leaks []*Leak, bypasser *user_model.User, bypassReason string) error {
+	for _, leak := range leaks {
+		alert := &git_model.SecretScanningAlert{
+			RepoID:         repo.ID,
+			PatternID:      leak.Pattern.ID,
+			PatternName:    leak.Pattern.Name,
+			SecretHash:     hashSecret(leak.Secret),
+			RedactedSecret: leak.Redacted(),
+			CommitID:       leak.CommitID,
+			Path:           leak.Path,
+			Line:           leak.Line,
+		}
+		if bypasser != nil {
+			alert.PushProtectionBypassed = true
+			alert.BypassReason = bypassReason
+			alert.BypasserID = bypasser.ID
+		}
+		if _, err := git_model.CreateSecretScanningAlertIfNotExist(ctx, alert); err != nil {
+			return err
+		}
+	}
+
+	if bypasser != nil && len(leaks) > 0 {
+		audit_service.RecordRepo(ctx, bypasser, audit_model.ActionSecretScanningBypass, repo, audit_service.RepoTarget(repo),
+			"Pushed %d secrets to %s: %s", len(leaks), repo.FullName(), bypassReason)
+	}
+	return nil
+}
+
+// ResolveAlert closes the alert for the reason and with the comment
+func ResolveAlert(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, alert *git_model.SecretScanningAlert, resolution git_model.SecretScanningResolution, comment string) error {
+	if !resolution.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid resolution %q", resolution)
+	}
+
+	alert.State = git_model.SecretScanningAlertStateResolved
+	alert.Resolution = resolution
+	alert.ResolutionComment = comment
+	alert.ResolverID = doer.ID
+	alert.Resolver = doer
+	alert.ResolvedUnix = timeutil.TimeStampNow()
+	if err := git_model.UpdateSecretScanningAlertState(ctx, alert); err != nil {
+		return err
+	}
+
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionSecretScanningAlertResolve, repo, audit_service.SecretScanningAlertTarget(alert),
+		"Resolved secret scanning alert #%d in %s as %s", alert.ID, repo.FullName(), resolution)
+	return nil
+}
+
+// ReopenAlert opens the resolved alert again
+func ReopenAlert(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, alert *git_model.SecretScanningAlert) error {
+	alert.State = git_model.SecretScanningAlertStateOpen
+	alert.Resolution = ""
+	alert.ResolutionComment = ""
+	alert.ResolverID = 0
+	alert.Resolver = nil
+	alert.ResolvedUnix = 0
+	if err := git_model.UpdateSecretScanningAlertState(ctx, alert); err != nil {
+		return err
+	}
+
+	audit_service.RecordRepo(ctx, doer, audit_model.ActionSecretScanningAlertReopen, repo, audit_service.SecretScanningAlertTarget(alert),
+		"Reopened secret scanning alert #%d in %s", alert.ID, repo.FullName())
+	return nil
+}
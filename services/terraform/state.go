@@ -0,0 +1,220 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	terraform_model "code.gitea.io/gitea/models/terraform"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+)
+
+var stateNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// IsValidStateName checks if the name can be used for a state
+func IsValidStateName(name string) bool {
+	return len(name) <= 255 && stateNamePattern.MatchString(name)
+}
+
+// LockInfo is the information Terraform sends when it locks a state
+// https://github.com/hashicorp/terraform/blob/main/internal/states/statemgr/locker.go
+type LockInfo struct {
+	ID        string    `json:"ID"`
+	Operation string    `json:"Operation"`
+	Info      string    `json:"Info"`
+	Who       string    `json:"Who"`
+	Version   string    `json:"Version"`
+	Created   time.Time `json:"Created"`
+	Path      string    `json:"Path"`
+}
+
+// ParseLockInfo parses the JSON lock info of Terraform
+func ParseLockInfo(data []byte) (*LockInfo, error) {
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid lock info: %v", err)
+	}
+	return &info, nil
+}
+
+// stateMetadata contains the fields of a Terraform state which are stored in the database
+type stateMetadata struct {
+	Serial  int64  `json:"serial"`
+	Lineage string `json:"lineage"`
+}
+
+// UpdateState stores the content as the new version of the state, the state is created if it doesn't exist.
+// If the state is locked, lockID must be the id of the lock.
+func UpdateState(ctx context.Context, doer *user_model.User, repoID int64, name, lockID string, content []byte) (*terraform_model.TerraformState, error) {
+	var metadata stateMetadata
+	if err := json.Unmarshal(content, &metadata); err != nil {
+		return nil, util.NewInvalidArgumentErrorf("invalid state: %v", err)
+	}
+
+	encrypted, err := secret.EncryptBytes(setting.SecretKey, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// the content is always saved as a new file, the file of a replaced version is only deleted after the transaction is committed,
+	// and the new file is deleted if the transaction fails
+	var s *terraform_model.TerraformState
+	var newVersion, replacedVersion *terraform_model.TerraformStateVersion
+	err = db.WithTx(ctx, func(ctx context.Context) error {
+		s, err = terraform_model.GetOrCreateState(ctx, repoID, name)
+		if err != nil {
+			return err
+		}
+		if s.IsLocked() && s.LockID != lockID {
+			return terraform_model.ErrStateLocked
+		}
+
+		// Terraform may write the same serial again, the version is replaced in this case
+		replacedVersion, err = terraform_model.GetStateVersionBySerial(ctx, s.ID, metadata.Serial)
+		if err != nil && !errors.Is(err, util.ErrNotExist) {
+			return err
+		}
+		if replacedVersion != nil {
+			if err := terraform_model.DeleteStateVersionByID(ctx, replacedVersion.ID); err != nil {
+				return err
+			}
+		}
+
+		v := &terraform_model.TerraformStateVersion{
+			RepoID:    repoID,
+			StateID:   s.ID,
+			Serial:    metadata.Serial,
+			Lineage:   metadata.Lineage,
+			Size:      int64(len(content)),
+			CreatorID: doer.ID,
+		}
+		if err := db.Insert(ctx, v); err != nil {
+			return err
+		}
+
+		if _, err := storage.TerraformState.Save(v.StoragePath(), bytes.NewReader(encrypted), int64(len(encrypted))); err != nil {
+			return err
+		}
+		newVersion = v
+
+		s.LatestVersionID = v.ID
+		s.Serial = v.Serial
+		s.Lineage = v.Lineage
+		s.Size = v.Size
+		return terraform_model.UpdateState(ctx, s, "latest_version_id", "serial", "lineage", "size")
+	})
+	if err != nil {
+		if newVersion != nil {
+			RemoveStateVersionFile(newVersion)
+		}
+		return nil, err
+	}
+	if replacedVersion != nil {
+		RemoveStateVersionFile(replacedVersion)
+	}
+	return s, nil
+}
+
+// GetStateVersionContent returns the decrypted content of a version of a state
+func GetStateVersionContent(ctx context.Context, v *terraform_model.TerraformStateVersion) ([]byte, error) {
+	f, err := storage.TerraformState.Open(v.StoragePath())
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	encrypted, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return secret.DecryptBytes(setting.SecretKey, encrypted)
+}
+
+// LockState locks the state with the lock info sent by Terraform, the state is created if it doesn't exist.
+// If the state is already locked by another lock, terraform_model.ErrStateLocked is returned together with the state.
+func LockState(ctx context.Context, doer *user_model.User, repoID int64, name string, lockInfo []byte) (*terraform_model.TerraformState, error) {
+	info, err := ParseLockInfo(lockInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := terraform_model.GetOrCreateState(ctx, repoID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := terraform_model.LockState(ctx, s, info.ID, string(lockInfo), doer.ID); err != nil {
+		if errors.Is(err, terraform_model.ErrStateLocked) {
+			// reload the state to return the current lock
+			if s, getErr := terraform_model.GetStateByName(ctx, repoID, name); getErr == nil {
+				return s, err
+			}
+		}
+		return nil, err
+	}
+	return terraform_model.GetStateByName(ctx, repoID, name)
+}
+
+// UnlockState unlocks the state, the lock is released regardless of its id if lockInfo is empty (force unlock).
+// If the state is locked by another lock, terraform_model.ErrStateLocked is returned together with the state.
+func UnlockState(ctx context.Context, s *terraform_model.TerraformState, lockInfo []byte) (*terraform_model.TerraformState, error) {
+	lockID := ""
+	if len(bytes.TrimSpace(lockInfo)) > 0 {
+		info, err := ParseLockInfo(lockInfo)
+		if err != nil {
+			return nil, err
+		}
+		lockID = info.ID
+	}
+
+	if err := terraform_model.UnlockState(ctx, s, lockID); err != nil {
+		if errors.Is(err, terraform_model.ErrStateLocked) {
+			if s, getErr := terraform_model.GetStateByName(ctx, s.RepoID, s.Name); getErr == nil {
+				return s, err
+			}
+		}
+		return nil, err
+	}
+	return terraform_model.GetStateByName(ctx, s.RepoID, s.Name)
+}
+
+// DeleteState deletes the state with all its versions. If the state is locked, lockID must be the id of the lock.
+func DeleteState(ctx context.Context, s *terraform_model.TerraformState, lockID string) error {
+	if s.IsLocked() && s.LockID != lockID {
+		return terraform_model.ErrStateLocked
+	}
+
+	versions, err := db.Find[terraform_model.TerraformStateVersion](ctx, terraform_model.FindStateVersionsOptions{StateID: s.ID})
+	if err != nil {
+		return err
+	}
+
+	if err := terraform_model.DeleteStateByID(ctx, s.ID); err != nil {
+		return err
+	}
+
+	for _, v := range versions {
+		RemoveStateVersionFile(v)
+	}
+	return nil
+}
+
+// RemoveStateVersionFile deletes the content of a version from the storage
+func RemoveStateVersionFile(v *terraform_model.TerraformStateVersion) {
+	if err := storage.TerraformState.Delete(v.StoragePath()); err != nil {
+		log.Error("Failed to delete terraform state version %d: %v", v.ID, err)
+	}
+}
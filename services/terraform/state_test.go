@@ -0,0 +1,48 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package terraform
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	terraform_model "code.gitea.io/gitea/models/terraform"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateStateReplacesSerial(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	s, err := UpdateState(db.DefaultContext, user2, 1, "replace", "", []byte(`{"serial":1,"lineage":"first"}`))
+	assert.NoError(t, err)
+	first := unittest.AssertExistsAndLoadBean(t, &terraform_model.TerraformStateVersion{ID: s.LatestVersionID})
+
+	// Terraform writes the same serial again, the version is replaced by a new one
+	s, err = UpdateState(db.DefaultContext, user2, 1, "replace", "", []byte(`{"serial":1,"lineage":"second"}`))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.ID, s.LatestVersionID)
+	assert.Equal(t, "second", s.Lineage)
+	unittest.AssertNotExistsBean(t, &terraform_model.TerraformStateVersion{ID: first.ID})
+	_, err = storage.TerraformState.Stat(first.StoragePath())
+	assert.Error(t, err)
+
+	second := unittest.AssertExistsAndLoadBean(t, &terraform_model.TerraformStateVersion{ID: s.LatestVersionID})
+	content, err := GetStateVersionContent(db.DefaultContext, second)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"serial":1,"lineage":"second"}`, string(content))
+
+	// the content of a locked state can't be replaced without the lock
+	_, err = LockState(db.DefaultContext, user2, 1, "replace", []byte(`{"ID":"lock"}`))
+	assert.NoError(t, err)
+	_, err = UpdateState(db.DefaultContext, user2, 1, "replace", "", []byte(`{"serial":1,"lineage":"third"}`))
+	assert.ErrorIs(t, err, terraform_model.ErrStateLocked)
+	unittest.AssertExistsAndLoadBean(t, &terraform_model.TerraformStateVersion{ID: second.ID})
+	_, err = storage.TerraformState.Stat(second.StoragePath())
+	assert.NoError(t, err)
+}
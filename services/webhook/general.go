@@ -307,6 +307,63 @@ func getPackagePayloadInfo(p *api.PackagePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+func getWorkflowRunPayloadInfo(p *api.WorkflowRunPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	runLink := linkFormatter(p.WorkflowRun.HTMLURL, fmt.Sprintf("%s #%d", p.Workflow.Name, p.WorkflowRun.RunNumber))
+
+	switch p.Action {
+	case api.HookWorkflowRunRequested:
+		text = fmt.Sprintf("[%s] Workflow run %s requested", repoLink, runLink)
+		color = yellowColor
+	case api.HookWorkflowRunInProgress:
+		text = fmt.Sprintf("[%s] Workflow run %s started", repoLink, runLink)
+		color = yellowColor
+	case api.HookWorkflowRunCompleted:
+		text = fmt.Sprintf("[%s] Workflow run %s completed: %s", repoLink, runLink, p.WorkflowRun.Conclusion)
+		color = getWorkflowConclusionColor(p.WorkflowRun.Conclusion)
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getWorkflowJobPayloadInfo(p *api.WorkflowJobPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	jobLink := linkFormatter(p.WorkflowJob.HTMLURL, p.WorkflowJob.Name)
+
+	switch p.Action {
+	case api.HookWorkflowJobQueued:
+		text = fmt.Sprintf("[%s] Workflow job %s queued", repoLink, jobLink)
+		color = yellowColor
+	case api.HookWorkflowJobWaiting:
+		text = fmt.Sprintf("[%s] Workflow job %s waiting", repoLink, jobLink)
+		color = yellowColor
+	case api.HookWorkflowJobInProgress:
+		text = fmt.Sprintf("[%s] Workflow job %s started", repoLink, jobLink)
+		color = yellowColor
+	case api.HookWorkflowJobCompleted:
+		text = fmt.Sprintf("[%s] Workflow job %s completed: %s", repoLink, jobLink, p.WorkflowJob.Conclusion)
+		color = getWorkflowConclusionColor(p.WorkflowJob.Conclusion)
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+url.PathEscape(p.Sender.UserName), p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getWorkflowConclusionColor(conclusion string) int {
+	switch conclusion {
+	case "success":
+		return greenColor
+	case "failure":
+		return redColor
+	}
+	return greyColor
+}
+
 // ToHook convert models.Webhook to api.Hook
 // This function is not part of the convert package to prevent an import cycle
 func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
@@ -341,6 +341,55 @@ func packageTestPayload() *api.PackagePayload {
 	}
 }
 
+func workflowRunTestPayload() *api.WorkflowRunPayload {
+	return &api.WorkflowRunPayload{
+		Action: api.HookWorkflowRunCompleted,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		Workflow: &api.ActionWorkflow{
+			Name: "CI",
+			Path: ".gitea/workflows/ci.yml",
+		},
+		WorkflowRun: &api.ActionWorkflowRun{
+			ID:         1,
+			RunNumber:  3,
+			Status:     "completed",
+			Conclusion: "success",
+			HTMLURL:    "http://localhost:3000/test/repo/actions/runs/3",
+		},
+	}
+}
+
+func workflowJobTestPayload() *api.WorkflowJobPayload {
+	return &api.WorkflowJobPayload{
+		Action: api.HookWorkflowJobCompleted,
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		WorkflowJob: &api.ActionWorkflowJob{
+			ID:         1,
+			RunID:      1,
+			Name:       "build",
+			Status:     "completed",
+			Conclusion: "failure",
+			HTMLURL:    "http://localhost:3000/test/repo/actions/runs/3/jobs/0",
+		},
+	}
+}
+
 func TestGetIssuesPayloadInfo(t *testing.T) {
 	p := issueTestPayload()
 
@@ -610,6 +659,106 @@ func TestGetReleasePayloadInfo(t *testing.T) {
 	}
 }
 
+func TestGetWorkflowRunPayloadInfo(t *testing.T) {
+	p := workflowRunTestPayload()
+
+	cases := []struct {
+		action     api.HookWorkflowRunAction
+		conclusion string
+		text       string
+		color      int
+	}{
+		{
+			api.HookWorkflowRunRequested,
+			"",
+			"[test/repo] Workflow run CI #3 requested by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowRunInProgress,
+			"",
+			"[test/repo] Workflow run CI #3 started by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowRunCompleted,
+			"success",
+			"[test/repo] Workflow run CI #3 completed: success by user1",
+			greenColor,
+		},
+		{
+			api.HookWorkflowRunCompleted,
+			"failure",
+			"[test/repo] Workflow run CI #3 completed: failure by user1",
+			redColor,
+		},
+		{
+			api.HookWorkflowRunCompleted,
+			"cancelled",
+			"[test/repo] Workflow run CI #3 completed: cancelled by user1",
+			greyColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		p.WorkflowRun.Conclusion = c.conclusion
+		text, color := getWorkflowRunPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
+func TestGetWorkflowJobPayloadInfo(t *testing.T) {
+	p := workflowJobTestPayload()
+
+	cases := []struct {
+		action     api.HookWorkflowJobAction
+		conclusion string
+		text       string
+		color      int
+	}{
+		{
+			api.HookWorkflowJobQueued,
+			"",
+			"[test/repo] Workflow job build queued by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowJobWaiting,
+			"",
+			"[test/repo] Workflow job build waiting by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowJobInProgress,
+			"",
+			"[test/repo] Workflow job build started by user1",
+			yellowColor,
+		},
+		{
+			api.HookWorkflowJobCompleted,
+			"success",
+			"[test/repo] Workflow job build completed: success by user1",
+			greenColor,
+		},
+		{
+			api.HookWorkflowJobCompleted,
+			"skipped",
+			"[test/repo] Workflow job build completed: skipped by user1",
+			greyColor,
+		},
+	}
+
+	for i, c := range cases {
+		p.Action = c.action
+		p.WorkflowJob.Conclusion = c.conclusion
+		text, color := getWorkflowJobPayloadInfo(p, noneLinkFormatter, true)
+		assert.Equal(t, c.text, text, "case %d", i)
+		assert.Equal(t, c.color, color, "case %d", i)
+	}
+}
+
 func TestGetIssueCommentPayloadInfo(t *testing.T) {
 	p := pullRequestCommentTestPayload()
 
@@ -156,6 +156,23 @@ func TestSlackPayload(t *testing.T) {
 
 		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Release created: <http://localhost:3000/test/repo/releases/tag/v1.0|v1.0> by <https://try.gitea.io/user1|user1>", pl.Text)
 	})
+	t.Run("WorkflowRun", func(t *testing.T) {
+		p := workflowRunTestPayload()
+
+		pl, err := sc.WorkflowRun(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow run <http://localhost:3000/test/repo/actions/runs/3|CI #3> completed: success by <https://try.gitea.io/user1|user1>", pl.Text)
+	})
+
+	t.Run("WorkflowJob", func(t *testing.T) {
+		p := workflowJobTestPayload()
+
+		pl, err := sc.WorkflowJob(p)
+		require.NoError(t, err)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Workflow job <http://localhost:3000/test/repo/actions/runs/3/jobs/0|build> completed: failure by <https://try.gitea.io/user1|user1>", pl.Text)
+	})
 }
 
 func TestSlackJSONPayload(t *testing.T) {
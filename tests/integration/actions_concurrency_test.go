@@ -0,0 +1,106 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsConcurrency(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "actions-concurrency",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "master",
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   unit_model.TypeActions,
+		}}, nil))
+
+		// the workflows share the concurrency group of the branch
+		workflow := func(cancelInProgress bool) string {
+			return fmt.Sprintf(`on: workflow_dispatch
+concurrency:
+  group: deploy-${{ github.ref_name }}
+  cancel-in-progress: %t
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`, cancelInProgress)
+		}
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{Operation: "create", TreePath: ".gitea/workflows/first.yml", ContentReader: strings.NewReader(workflow(false))},
+				{Operation: "create", TreePath: ".gitea/workflows/second.yml", ContentReader: strings.NewReader(workflow(false))},
+				{Operation: "create", TreePath: ".gitea/workflows/third.yml", ContentReader: strings.NewReader(workflow(false))},
+				{Operation: "create", TreePath: ".gitea/workflows/urgent.yml", ContentReader: strings.NewReader(workflow(true))},
+			},
+			Message:   "add workflows",
+			OldBranch: "master",
+			NewBranch: "master",
+		})
+		require.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		dispatch := func(t *testing.T, workflowID string) *actions_model.ActionRun {
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/%s/dispatches", user2.Name, repo.Name, workflowID), &api.CreateActionWorkflowDispatch{
+				Ref: "master",
+			}).AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			var apiRun api.ActionWorkflowRun
+			DecodeJSON(t, resp, &apiRun)
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: apiRun.ID})
+		}
+		// each run has a single job
+		jobStatus := func(run *actions_model.ActionRun) actions_model.Status {
+			return unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: run.ID}).Status
+		}
+
+		first := dispatch(t, "first.yml")
+		assert.Equal(t, "deploy-master", first.ConcurrencyGroup)
+		assert.Equal(t, actions_model.StatusWaiting, first.Status)
+
+		// the group is in use, so the run is pending
+		second := dispatch(t, "second.yml")
+		assert.Equal(t, actions_model.StatusBlocked, second.Status)
+
+		// the former pending run is replaced
+		third := dispatch(t, "third.yml")
+		assert.Equal(t, actions_model.StatusBlocked, third.Status)
+		assert.Equal(t, actions_model.StatusCancelled, jobStatus(second))
+		assert.Equal(t, actions_model.StatusWaiting, jobStatus(first))
+
+		// the runs in progress are cancelled
+		urgent := dispatch(t, "urgent.yml")
+		assert.Equal(t, actions_model.StatusWaiting, urgent.Status)
+		assert.Equal(t, actions_model.StatusCancelled, jobStatus(first))
+		assert.Equal(t, actions_model.StatusCancelled, jobStatus(third))
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	secret_model "code.gitea.io/gitea/models/secret"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsEnvironment(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "actions-environment",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "master",
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   unit_model.TypeActions,
+		}}, nil))
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		envURL := fmt.Sprintf("/api/v1/repos/%s/%s/environments", user2.Name, repo.Name)
+
+		// production requires the review of user4, staging can only be deployed from release branches
+		req := NewRequestWithJSON(t, "PUT", envURL+"/production", &api.CreateOrUpdateEnvironmentOption{
+			Reviewers: []string{user4.Name},
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var production api.Environment
+		DecodeJSON(t, resp, &production)
+		require.Len(t, production.Reviewers, 1)
+		assert.Equal(t, user4.Name, production.Reviewers[0].UserName)
+
+		req = NewRequestWithJSON(t, "PUT", envURL+"/staging", &api.CreateOrUpdateEnvironmentOption{
+			DeploymentBranches: []string{"release/*"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequestWithJSON(t, "PUT", envURL+"/production/secrets/deploy_key", &api.CreateOrUpdateSecretOption{Data: "production-key"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+		req = NewRequestWithJSON(t, "POST", envURL+"/production/variables/target", &api.CreateVariableOption{Value: "prod.example.com"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/deploy.yml",
+					ContentReader: strings.NewReader(`name: Deploy
+on: workflow_dispatch
+jobs:
+  production:
+    runs-on: ubuntu-latest
+    environment:
+      name: production
+      url: https://example.com
+    steps:
+      - run: echo deploy
+  staging:
+    runs-on: ubuntu-latest
+    environment: staging
+    steps:
+      - run: echo deploy
+`),
+				},
+			},
+			Message:   "add workflow",
+			OldBranch: "master",
+			NewBranch: "master",
+		})
+		require.NoError(t, err)
+
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/deploy.yml/dispatches", user2.Name, repo.Name), &api.CreateActionWorkflowDispatch{Ref: "master"}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		var apiRun api.ActionWorkflowRun
+		DecodeJSON(t, resp, &apiRun)
+
+		productionJob := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: apiRun.ID, JobID: "production"})
+		stagingJob := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: apiRun.ID, JobID: "staging"})
+		assert.Equal(t, "production", productionJob.Environment)
+
+		// the master branch can't deploy to staging
+		assert.Eventually(t, func() bool {
+			job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: stagingJob.ID})
+			return job.Status == actions_model.StatusFailure
+		}, 10*time.Second, 100*time.Millisecond)
+
+		// the deployment to production is waiting for the review
+		pendingURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/pending_deployments", user2.Name, repo.Name, apiRun.ID)
+		var pending []*api.Deployment
+		assert.Eventually(t, func() bool {
+			resp := MakeRequest(t, NewRequest(t, "GET", pendingURL).AddTokenAuth(token), http.StatusOK)
+			DecodeJSON(t, resp, &pending)
+			return len(pending) == 1
+		}, 10*time.Second, 100*time.Millisecond)
+		assert.Equal(t, productionJob.ID, pending[0].JobID)
+		assert.Equal(t, "pending", pending[0].ReviewStatus)
+		assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: productionJob.ID}).Status)
+
+		// only the required reviewers can approve the deployment
+		review := &api.ReviewPendingDeploymentsOption{EnvironmentIDs: []int64{production.ID}, State: "approved", Comment: "ship it"}
+		MakeRequest(t, NewRequestWithJSON(t, "POST", pendingURL, review).AddTokenAuth(token), http.StatusForbidden)
+		token4 := getUserToken(t, user4.Name, auth_model.AccessTokenScopeWriteRepository)
+		MakeRequest(t, NewRequestWithJSON(t, "POST", pendingURL, review).AddTokenAuth(token4), http.StatusOK)
+
+		assert.Eventually(t, func() bool {
+			job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: productionJob.ID})
+			return job.Status == actions_model.StatusWaiting
+		}, 10*time.Second, 100*time.Millisecond)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", envURL+"/production/deployments").AddTokenAuth(token), http.StatusOK)
+		var deployments []*api.Deployment
+		DecodeJSON(t, resp, &deployments)
+		require.Len(t, deployments, 1)
+		assert.Equal(t, "approved", deployments[0].ReviewStatus)
+		assert.Equal(t, user4.Name, deployments[0].Reviewer.UserName)
+
+		// the job gets the secrets and variables of the environment
+		productionJob = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: productionJob.ID})
+		require.NoError(t, productionJob.LoadAttributes(db.DefaultContext))
+		secrets, err := secret_model.GetSecretsOfTask(db.DefaultContext, &actions_model.ActionTask{Job: productionJob})
+		require.NoError(t, err)
+		assert.Equal(t, "production-key", secrets["DEPLOY_KEY"])
+		vars, err := actions_model.GetVariablesOfRunJob(db.DefaultContext, productionJob)
+		require.NoError(t, err)
+		assert.Equal(t, "prod.example.com", vars["TARGET"])
+
+		// the settings pages list the environments and the deployment history
+		session := loginUser(t, user2.Name)
+		settingsURL := fmt.Sprintf("/%s/%s/settings/actions/environments", user2.Name, repo.Name)
+		resp = session.MakeRequest(t, NewRequest(t, "GET", settingsURL), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), settingsURL+"/staging")
+		resp = session.MakeRequest(t, NewRequest(t, "GET", settingsURL+"/production"), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "DEPLOY_KEY")
+		assert.Contains(t, resp.Body.String(), "prod.example.com")
+
+		// the secrets of the environment are removed with it
+		MakeRequest(t, NewRequest(t, "DELETE", envURL+"/production").AddTokenAuth(token), http.StatusNoContent)
+		unittest.AssertNotExistsBean(t, &secret_model.Secret{RepoID: repo.ID, Name: "DEPLOY_KEY"})
+		unittest.AssertNotExistsBean(t, &actions_model.ActionDeployment{RepoID: repo.ID})
+	})
+}
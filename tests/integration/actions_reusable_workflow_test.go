@@ -0,0 +1,154 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	actions_service "code.gitea.io/gitea/services/actions"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsReusableWorkflow(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "actions-reusable-workflow",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "master",
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   unit_model.TypeActions,
+		}}, nil))
+
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/build.yml",
+					ContentReader: strings.NewReader(`name: Build
+on:
+  workflow_call:
+    inputs:
+      target:
+        type: string
+      debug:
+        type: boolean
+    outputs:
+      version:
+        value: ${{ jobs.test.outputs.version }}
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build ${{ inputs.target }}
+  test:
+    needs: build
+    runs-on: ubuntu-latest
+    outputs:
+      version: ${{ steps.version.outputs.version }}
+    steps:
+      - id: version
+        run: echo "version=1.0.0" >> "$GITHUB_OUTPUT"
+`),
+				},
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/release.yml",
+					ContentReader: strings.NewReader(`name: Release
+on: workflow_dispatch
+jobs:
+  call:
+    uses: ./.gitea/workflows/build.yml
+    with:
+      target: release-${{ github.ref_name }}
+      debug: true
+    secrets: inherit
+  publish:
+    needs: call
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo publish ${{ needs.call.outputs.version }}
+`),
+				},
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/private.yml",
+					ContentReader: strings.NewReader(`name: Private
+on: workflow_dispatch
+jobs:
+  call:
+    uses: org3/repo3/.gitea/workflows/build.yml@master
+`),
+				},
+			},
+			Message:   "add workflows",
+			OldBranch: "master",
+			NewBranch: "master",
+		})
+		require.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		dispatchURL := func(workflowID string) string {
+			return fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/%s/dispatches", user2.Name, repo.Name, workflowID)
+		}
+
+		// the private repository of another owner is not accessible
+		req := NewRequestWithJSON(t, "POST", dispatchURL("private.yml"), &api.CreateActionWorkflowDispatch{Ref: "master"}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "private.yml"})
+
+		req = NewRequestWithJSON(t, "POST", dispatchURL("release.yml"), &api.CreateActionWorkflowDispatch{Ref: "master"}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var apiRun api.ActionWorkflowRun
+		DecodeJSON(t, resp, &apiRun)
+
+		// the calling job is expanded into the jobs of the reusable workflow
+		caller := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: apiRun.ID, JobID: "call"})
+		build := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: apiRun.ID, ParentJobID: caller.ID, JobID: "build"})
+		test := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: apiRun.ID, ParentJobID: caller.ID, JobID: "test"})
+		publish := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{RunID: apiRun.ID, JobID: "publish"})
+		assert.Equal(t, "call / build", build.Name)
+		assert.Equal(t, []string{"build"}, test.Needs)
+		assert.EqualValues(t, 0, publish.ParentJobID)
+
+		// the calling job is started by the job emitter, then the first job of the reusable workflow is waiting for runners
+		assert.Eventually(t, func() bool {
+			job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: build.ID})
+			return job.Status == actions_model.StatusWaiting
+		}, 10*time.Second, 100*time.Millisecond)
+		caller = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: caller.ID})
+		assert.Equal(t, actions_model.StatusRunning, caller.Status)
+		assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: test.ID}).Status)
+		assert.Equal(t, actions_model.StatusBlocked, unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: publish.ID}).Status)
+
+		// the jobs of the reusable workflow get the inputs and secrets passed by the calling job
+		call, err := actions_service.EvaluateWorkflowCall(db.DefaultContext, build, map[string]string{"GITEA_TOKEN": "token", "DEPLOY_KEY": "key"}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"target": "release-master", "debug": "true"}, call.Inputs)
+		assert.Equal(t, map[string]string{"GITEA_TOKEN": "token", "DEPLOY_KEY": "key"}, call.Secrets)
+	})
+}
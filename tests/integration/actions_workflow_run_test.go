@@ -0,0 +1,132 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionsWorkflowRun(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "actions-workflow-run",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "master",
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   unit_model.TypeActions,
+		}}, nil))
+
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/build.yml",
+					ContentReader: strings.NewReader(`name: Build
+on: workflow_dispatch
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo build
+`),
+				},
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/report.yml",
+					ContentReader: strings.NewReader(`name: Report
+on:
+  workflow_run:
+    workflows: [Build]
+    types: [completed]
+jobs:
+  report:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo report
+`),
+				},
+			},
+			Message:   "add workflows",
+			OldBranch: "master",
+			NewBranch: "master",
+		})
+		require.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/hooks", user2.Name, repo.Name), &api.CreateHookOption{
+			Type: "gitea",
+			Config: api.CreateHookOptionConfig{
+				"content_type": "json",
+				"url":          "http://127.0.0.1:3000/workflow-run-hook",
+			},
+			Events: []string{string(webhook_module.HookEventWorkflowRun), string(webhook_module.HookEventWorkflowJob)},
+			Active: true,
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var apiHook api.Hook
+		DecodeJSON(t, resp, &apiHook)
+
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows/build.yml/dispatches", user2.Name, repo.Name), &api.CreateActionWorkflowDispatch{
+			Ref: "master",
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		var apiRun api.ActionWorkflowRun
+		DecodeJSON(t, resp, &apiRun)
+
+		// the run is requested and its job is queued
+		hookTasks := func(event webhook_module.HookEventType) int {
+			count, err := db.GetEngine(db.DefaultContext).Count(&webhook_model.HookTask{HookID: apiHook.ID, EventType: event})
+			require.NoError(t, err)
+			return int(count)
+		}
+		assert.Equal(t, 1, hookTasks(webhook_module.HookEventWorkflowRun))
+		assert.Equal(t, 1, hookTasks(webhook_module.HookEventWorkflowJob))
+		unittest.AssertNotExistsBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "report.yml"})
+
+		// the run is completed by cancelling it, which triggers the dependent workflow
+		req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%d/cancel", user2.Name, repo.Name, apiRun.ID)).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusAccepted)
+
+		// the run of Build is completed, and the run of Report is requested
+		assert.Equal(t, 3, hookTasks(webhook_module.HookEventWorkflowRun))
+		assert.Equal(t, 3, hookTasks(webhook_module.HookEventWorkflowJob))
+		report := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{RepoID: repo.ID, WorkflowID: "report.yml"})
+		assert.Equal(t, webhook_module.HookEventWorkflowRun, report.Event)
+		payload := &api.WorkflowRunPayload{}
+		require.NoError(t, json.Unmarshal([]byte(report.EventPayload), payload))
+		assert.Equal(t, api.HookWorkflowRunCompleted, payload.Action)
+		assert.Equal(t, "Build", payload.Workflow.Name)
+		assert.Equal(t, apiRun.ID, payload.WorkflowRun.ID)
+		assert.Equal(t, "failure", payload.WorkflowRun.Conclusion)
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/routers/api/actions"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+type actionsCacheResponse struct {
+	Result          string `json:"result"`
+	ArchiveLocation string `json:"archiveLocation"`
+	CacheKey        string `json:"cacheKey"`
+	CacheID         int64  `json:"cacheId"`
+}
+
+func TestActionsCacheV1(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	require.NoError(t, err)
+
+	base := "/api/actions_cache/_apis/artifactcache"
+
+	// nothing to restore
+	req := NewRequest(t, "GET", base+"/cache?keys=linux-go-abc,linux-go-&version=v1").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// reserve the cache
+	req = NewRequestWithJSON(t, "POST", base+"/caches", map[string]any{"key": "linux-go-abc", "version": "v1", "cacheSize": 8}).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var reserved actionsCacheResponse
+	DecodeJSON(t, resp, &reserved)
+	assert.Positive(t, reserved.CacheID)
+
+	// the cache can't be reserved twice in the same scope
+	req = NewRequestWithJSON(t, "POST", base+"/caches", map[string]any{"key": "linux-go-abc", "version": "v1"}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusConflict)
+
+	// upload the chunks out of order
+	cacheURL := fmt.Sprintf("%s/caches/%d", base, reserved.CacheID)
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader("BBBB")).AddTokenAuth(token)
+	req.Header.Set("Content-Range", "bytes 4-7/*")
+	MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestWithBody(t, "PATCH", cacheURL, strings.NewReader("AAAA")).AddTokenAuth(token)
+	req.Header.Set("Content-Range", "bytes 0-3/*")
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// the size must match the uploaded content
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": 9}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusBadRequest)
+	req = NewRequestWithJSON(t, "POST", cacheURL, map[string]any{"size": 8}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// restore by the restore key
+	req = NewRequest(t, "GET", base+"/cache?keys=linux-go-def,linux-go-&version=v1").AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var found actionsCacheResponse
+	DecodeJSON(t, resp, &found)
+	assert.Equal(t, "hit", found.Result)
+	assert.Equal(t, "linux-go-abc", found.CacheKey)
+
+	// the version must match
+	req = NewRequest(t, "GET", base+"/cache?keys=linux-go-abc&version=v2").AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusNoContent)
+
+	// download without the token
+	idx := strings.Index(found.ArchiveLocation, "/api/actions_cache/")
+	req = NewRequest(t, "GET", found.ArchiveLocation[idx:])
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "AAAABBBB", resp.Body.String())
+
+	// the signature is required
+	req = NewRequest(t, "GET", strings.Replace(found.ArchiveLocation[idx:], "cacheID=", "cacheID=1", 1))
+	MakeRequest(t, req, http.StatusUnauthorized)
+}
+
+func TestActionsCacheV2(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	token, err := actions_service.CreateAuthorizationToken(48, 792, 193)
+	require.NoError(t, err)
+
+	// reserve the cache
+	req := NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/CreateCacheEntry", toProtoJSON(&actions.CreateCacheEntryRequest{
+		Key:     "npm-abc",
+		Version: "v1",
+	})).AddTokenAuth(token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var createResp actions.CreateCacheEntryResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &createResp))
+	assert.True(t, createResp.Ok)
+
+	// upload in blocks and commit them by the block list
+	idx := strings.Index(createResp.SignedUploadUrl, "/twirp/")
+	uploadURL := createResp.SignedUploadUrl[idx:]
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=2", strings.NewReader("DDDD"))
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=1", strings.NewReader("CCCC"))
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=blocklist", strings.NewReader("<BlockList><Latest>1</Latest><Latest>2</Latest></BlockList>"))
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/FinalizeCacheEntryUpload", toProtoJSON(&actions.FinalizeCacheEntryUploadRequest{
+		Key:       "npm-abc",
+		Version:   "v1",
+		SizeBytes: 8,
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var finalizeResp actions.FinalizeCacheEntryUploadResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &finalizeResp))
+	assert.True(t, finalizeResp.Ok)
+	assert.Positive(t, finalizeResp.EntryId)
+
+	// the cache can't be overwritten
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/CreateCacheEntry", toProtoJSON(&actions.CreateCacheEntryRequest{
+		Key:     "npm-abc",
+		Version: "v1",
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &createResp))
+	assert.False(t, createResp.Ok)
+
+	// restore the cache
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/GetCacheEntryDownloadURL", toProtoJSON(&actions.GetCacheEntryDownloadURLRequest{
+		Key:         "npm-def",
+		RestoreKeys: []string{"npm-"},
+		Version:     "v1",
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	var downloadResp actions.GetCacheEntryDownloadURLResponse
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &downloadResp))
+	assert.True(t, downloadResp.Ok)
+	assert.Equal(t, "npm-abc", downloadResp.MatchedKey)
+
+	idx = strings.Index(downloadResp.SignedDownloadUrl, "/twirp/")
+	req = NewRequest(t, "GET", downloadResp.SignedDownloadUrl[idx:])
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "CCCCDDDD", resp.Body.String())
+
+	// nothing matches
+	req = NewRequestWithBody(t, "POST", "/twirp/github.actions.results.api.v1.CacheService/GetCacheEntryDownloadURL", toProtoJSON(&actions.GetCacheEntryDownloadURLRequest{
+		Key:     "yarn-abc",
+		Version: "v1",
+	})).AddTokenAuth(token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	require.NoError(t, protojson.Unmarshal(resp.Body.Bytes(), &downloadResp))
+	assert.False(t, downloadResp.Ok)
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIActionsRuns(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 4})
+	require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+		RepoID: repo.ID,
+		Type:   unit_model.TypeActions,
+	}}, nil))
+
+	readToken := getUserToken(t, "user5", auth_model.AccessTokenScopeReadRepository)
+	writeToken := getUserToken(t, "user5", auth_model.AccessTokenScopeWriteRepository)
+	apiURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions", repo.OwnerName, repo.Name)
+
+	t.Run("ListRuns", func(t *testing.T) {
+		req := NewRequest(t, "GET", apiURL+"/runs").AddTokenAuth(readToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var runs api.ActionWorkflowRunsResponse
+		DecodeJSON(t, resp, &runs)
+		assert.EqualValues(t, 2, runs.TotalCount)
+		assert.Len(t, runs.Entries, 2)
+		assert.EqualValues(t, 792, runs.Entries[0].ID)
+		assert.EqualValues(t, 791, runs.Entries[1].ID)
+
+		req = NewRequest(t, "GET", apiURL+"/runs?status=in_progress").AddTokenAuth(readToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &runs)
+		assert.EqualValues(t, 0, runs.TotalCount)
+
+		req = NewRequest(t, "GET", apiURL+"/runs?status=unknown").AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("GetRun", func(t *testing.T) {
+		req := NewRequest(t, "GET", apiURL+"/runs/791").AddTokenAuth(readToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var run api.ActionWorkflowRun
+		DecodeJSON(t, resp, &run)
+		assert.EqualValues(t, 187, run.RunNumber)
+		assert.Equal(t, "artifact.yaml", run.WorkflowID)
+		assert.Equal(t, "master", run.HeadBranch)
+		assert.Equal(t, "completed", run.Status)
+		assert.Equal(t, "success", run.Conclusion)
+
+		// the run belongs to another repository
+		req = NewRequest(t, "GET", "/api/v1/repos/user2/repo1/actions/runs/791").AddTokenAuth(getUserToken(t, "user2", auth_model.AccessTokenScopeReadRepository))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("ListRunJobs", func(t *testing.T) {
+		req := NewRequest(t, "GET", apiURL+"/runs/791/jobs").AddTokenAuth(readToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var jobs api.ActionWorkflowJobsResponse
+		DecodeJSON(t, resp, &jobs)
+		assert.EqualValues(t, 1, jobs.TotalCount)
+		assert.EqualValues(t, 192, jobs.Entries[0].ID)
+		assert.Equal(t, "job_2", jobs.Entries[0].Name)
+		assert.NotEmpty(t, jobs.Entries[0].Steps)
+
+		req = NewRequest(t, "GET", apiURL+"/jobs/192").AddTokenAuth(readToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var job api.ActionWorkflowJob
+		DecodeJSON(t, resp, &job)
+		assert.EqualValues(t, 791, job.RunID)
+		assert.Equal(t, "completed", job.Status)
+	})
+
+	t.Run("RerunAndCancel", func(t *testing.T) {
+		req := NewRequest(t, "POST", apiURL+"/runs/791/rerun").AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "POST", apiURL+"/runs/791/rerun").AddTokenAuth(writeToken)
+		MakeRequest(t, req, http.StatusCreated)
+		job := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+		assert.Equal(t, actions_model.StatusWaiting, job.Status)
+
+		req = NewRequest(t, "POST", apiURL+"/runs/791/cancel").AddTokenAuth(writeToken)
+		MakeRequest(t, req, http.StatusAccepted)
+		job = unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRunJob{ID: 192})
+		assert.Equal(t, actions_model.StatusCancelled, job.Status)
+
+		req = NewRequest(t, "POST", apiURL+"/runs/791/cancel").AddTokenAuth(writeToken)
+		MakeRequest(t, req, http.StatusConflict)
+	})
+
+	t.Run("Artifacts", func(t *testing.T) {
+		// other tests may have uploaded artifacts to the same runs, so use a dedicated name
+		const artifactName = "api-multi-files"
+		defer func() {
+			_, err := db.DeleteByBean(db.DefaultContext, &actions_model.ActionArtifact{ArtifactName: artifactName})
+			assert.NoError(t, err)
+		}()
+
+		for _, path := range []string{"a.txt", "b.txt"} {
+			assert.NoError(t, db.Insert(db.DefaultContext, &actions_model.ActionArtifact{
+				RunID:        791,
+				RepoID:       repo.ID,
+				OwnerID:      repo.OwnerID,
+				ArtifactName: artifactName,
+				ArtifactPath: artifactName + "/" + path,
+				FileSize:     1024,
+				Status:       int64(actions_model.ArtifactStatusUploadConfirmed),
+			}))
+		}
+
+		req := NewRequest(t, "GET", apiURL+"/artifacts?name="+artifactName).AddTokenAuth(readToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var arts api.ActionArtifactsResponse
+		DecodeJSON(t, resp, &arts)
+		assert.EqualValues(t, 1, arts.TotalCount)
+		assert.Equal(t, artifactName, arts.Entries[0].Name)
+		assert.EqualValues(t, 2048, arts.Entries[0].SizeInBytes)
+		artifactURL := fmt.Sprintf("%s/artifacts/%d", apiURL, arts.Entries[0].ID)
+
+		req = NewRequest(t, "GET", apiURL+"/runs/791/artifacts?name="+artifactName).AddTokenAuth(readToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &arts)
+		assert.EqualValues(t, 1, arts.TotalCount)
+
+		req = NewRequest(t, "GET", apiURL+"/runs/792/artifacts?name="+artifactName).AddTokenAuth(readToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &arts)
+		assert.EqualValues(t, 0, arts.TotalCount)
+
+		req = NewRequest(t, "GET", apiURL+"/artifacts/0").AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", artifactURL).AddTokenAuth(readToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var art api.ActionArtifact
+		DecodeJSON(t, resp, &art)
+		assert.EqualValues(t, 791, art.WorkflowRun.ID)
+		assert.EqualValues(t, 2048, art.SizeInBytes)
+
+		req = NewRequest(t, "DELETE", artifactURL).AddTokenAuth(writeToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", artifactURL).AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
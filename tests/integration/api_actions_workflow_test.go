@@ -0,0 +1,149 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIActionsWorkflows(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:          "workflow-dispatch",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "master",
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo_service.UpdateRepositoryUnits(db.DefaultContext, repo, []repo_model.RepoUnit{{
+			RepoID: repo.ID,
+			Type:   unit_model.TypeActions,
+		}}, nil))
+
+		_, err = files_service.ChangeRepoFiles(git.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation: "create",
+					TreePath:  ".gitea/workflows/deploy.yml",
+					ContentReader: strings.NewReader(`name: deploy
+on:
+  workflow_dispatch:
+    inputs:
+      environment:
+        type: choice
+        options: [staging, production]
+        required: true
+      dry-run:
+        type: boolean
+        default: "false"
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo deploy
+`),
+				},
+			},
+			Message:   "add workflow",
+			OldBranch: "master",
+			NewBranch: "master",
+		})
+		require.NoError(t, err)
+
+		token := getUserToken(t, user2.Name, auth_model.AccessTokenScopeWriteRepository)
+		apiURL := fmt.Sprintf("/api/v1/repos/%s/%s/actions/workflows", user2.Name, repo.Name)
+
+		req := NewRequest(t, "GET", apiURL).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var workflows api.ActionWorkflowsResponse
+		DecodeJSON(t, resp, &workflows)
+		if assert.Len(t, workflows.Workflows, 1) {
+			assert.Equal(t, "deploy.yml", workflows.Workflows[0].ID)
+			assert.Equal(t, "deploy", workflows.Workflows[0].Name)
+			assert.Equal(t, ".gitea/workflows/deploy.yml", workflows.Workflows[0].Path)
+			assert.Equal(t, "active", workflows.Workflows[0].State)
+		}
+
+		req = NewRequest(t, "GET", apiURL+"/unknown.yml").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// a disabled workflow can't be dispatched
+		req = NewRequest(t, "PUT", apiURL+"/deploy.yml/disable").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "GET", apiURL+"/deploy.yml").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var workflow api.ActionWorkflow
+		DecodeJSON(t, resp, &workflow)
+		assert.Equal(t, "disabled_manually", workflow.State)
+
+		dispatchURL := apiURL + "/deploy.yml/dispatches"
+		req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateActionWorkflowDispatch{
+			Ref:    "master",
+			Inputs: map[string]any{"environment": "staging"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "PUT", apiURL+"/deploy.yml/enable").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		// invalid inputs and refs
+		for _, opt := range []*api.CreateActionWorkflowDispatch{
+			{Ref: "master"},
+			{Ref: "master", Inputs: map[string]any{"environment": "testing"}},
+			{Ref: "master", Inputs: map[string]any{"environment": "staging", "dry-run": "maybe"}},
+			{Ref: "master", Inputs: map[string]any{"environment": "staging", "unknown": "value"}},
+		} {
+			req = NewRequestWithJSON(t, "POST", dispatchURL, opt).AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusUnprocessableEntity)
+		}
+		req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateActionWorkflowDispatch{
+			Ref:    "unknown-branch",
+			Inputs: map[string]any{"environment": "staging"},
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateActionWorkflowDispatch{
+			Ref:    "master",
+			Inputs: map[string]any{"environment": "production", "dry-run": true},
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusCreated)
+		var apiRun api.ActionWorkflowRun
+		DecodeJSON(t, resp, &apiRun)
+		assert.Equal(t, "workflow_dispatch", apiRun.Event)
+		assert.Equal(t, "deploy.yml", apiRun.WorkflowID)
+
+		run := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionRun{ID: apiRun.ID, RepoID: repo.ID})
+		assert.Equal(t, "refs/heads/master", run.Ref)
+		var payload api.WorkflowDispatchPayload
+		require.NoError(t, json.Unmarshal([]byte(run.EventPayload), &payload))
+		assert.Equal(t, map[string]any{"environment": "production", "dry-run": "true"}, payload.Inputs)
+
+		// reading is not enough to dispatch workflows
+		readToken := getUserToken(t, user2.Name, auth_model.AccessTokenScopeReadRepository)
+		req = NewRequestWithJSON(t, "POST", dispatchURL, &api.CreateActionWorkflowDispatch{Ref: "master"}).AddTokenAuth(readToken)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+}
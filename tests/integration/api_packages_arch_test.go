@@ -0,0 +1,269 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	arch_module "code.gitea.io/gitea/modules/packages/arch"
+	"code.gitea.io/gitea/modules/zstd"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageArch(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	packageName := "gitea-test"
+	packageVersion := "1.0.1-1"
+
+	createPackage := func(name, architecture string) []byte {
+		pkginfo := []byte(`pkgname = ` + name + `
+pkgbase = ` + name + `
+pkgver = ` + packageVersion + `
+pkgdesc = Gitea Test Package
+url = https://gitea.io/
+builddate = 1678834800
+packager = Gitea <pack@ag.er>
+size = 1024
+arch = ` + architecture + `
+license = MIT
+depend = glibc`)
+
+		var buf bytes.Buffer
+		zw, _ := zstd.NewWriter(&buf)
+		tw := tar.NewWriter(zw)
+		for _, file := range []struct {
+			Name    string
+			Content []byte
+		}{
+			{".PKGINFO", pkginfo},
+			{"usr/bin/" + name, []byte(architecture)},
+		} {
+			_ = tw.WriteHeader(&tar.Header{Name: file.Name, Mode: 0o644, Size: int64(len(file.Content))})
+			_, _ = tw.Write(file.Content)
+		}
+		tw.Close()
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	anyPackageName := packageName + "-any"
+
+	content := createPackage(packageName, "x86_64")
+	anyContent := createPackage(anyPackageName, arch_module.AnyArch)
+
+	readDatabase := func(t *testing.T, content []byte) map[string]string {
+		gzr, err := gzip.NewReader(bytes.NewReader(content))
+		if !assert.NoError(t, err) {
+			return nil
+		}
+
+		entries := make(map[string]string)
+		tr := tar.NewReader(gzr)
+		for {
+			hd, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if !assert.NoError(t, err) {
+				return nil
+			}
+
+			if hd.Typeflag == tar.TypeReg {
+				buf, err := io.ReadAll(tr)
+				assert.NoError(t, err)
+				entries[hd.Name] = string(buf)
+			}
+		}
+		return entries
+	}
+
+	rootURL := fmt.Sprintf("/api/packages/%s/arch", user.Name)
+	repository := "gitea"
+
+	var keyring openpgp.EntityList
+
+	checkSignature := func(t *testing.T, content, signature []byte) {
+		_, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(content), bytes.NewReader(signature), nil)
+		assert.NoError(t, err)
+	}
+
+	t.Run("RepositoryKey", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", rootURL+"/repository.key")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		assert.Equal(t, "application/pgp-keys", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Body.String(), "-----BEGIN PGP PUBLIC KEY BLOCK-----")
+
+		var err error
+		keyring, err = openpgp.ReadArmoredKeyRing(resp.Body)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		uploadURL := rootURL + "/" + repository
+
+		req := NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(content))
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader([]byte{})).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+
+		pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeArch)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 1)
+
+		pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+		assert.NoError(t, err)
+		assert.IsType(t, &arch_module.VersionMetadata{}, pd.Metadata)
+		assert.Equal(t, packageName, pd.Package.Name)
+		assert.Equal(t, packageVersion, pd.Version.Version)
+
+		pfs, err := packages.GetFilesByVersionID(db.DefaultContext, pvs[0].ID)
+		assert.NoError(t, err)
+		assert.Len(t, pfs, 1)
+		assert.Equal(t, fmt.Sprintf("%s-%s-x86_64.pkg.tar.zst", packageName, packageVersion), pfs[0].Name)
+		assert.Equal(t, repository+"|x86_64", pfs[0].CompositeKey)
+		assert.True(t, pfs[0].IsLead)
+
+		req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(content)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(anyContent)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusCreated)
+	})
+
+	t.Run("Database", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		for _, filename := range []string{repository + ".db", repository + ".db.tar.gz"} {
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s", rootURL, repository, filename))
+			resp := MakeRequest(t, req, http.StatusOK)
+			content := resp.Body.Bytes()
+
+			entries := readDatabase(t, content)
+			assert.Len(t, entries, 2)
+			assert.Contains(t, entries, fmt.Sprintf("%s-%s/desc", anyPackageName, packageVersion))
+			desc, ok := entries[fmt.Sprintf("%s-%s/desc", packageName, packageVersion)]
+			assert.True(t, ok)
+			assert.Contains(t, desc, "%FILENAME%\n"+fmt.Sprintf("%s-%s-x86_64.pkg.tar.zst", packageName, packageVersion)+"\n")
+			assert.Contains(t, desc, "%NAME%\n"+packageName+"\n")
+			assert.Contains(t, desc, "%VERSION%\n"+packageVersion+"\n")
+			assert.Contains(t, desc, "%ARCH%\nx86_64\n")
+			assert.Contains(t, desc, "%DEPENDS%\nglibc\n")
+			assert.Contains(t, desc, "%PGPSIG%\n")
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s.sig", rootURL, repository, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+			checkSignature(t, content, resp.Body.Bytes())
+		}
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s/%s.db", rootURL, repository, arch_module.AnyArch, repository))
+		resp := MakeRequest(t, req, http.StatusOK)
+		entries := readDatabase(t, resp.Body.Bytes())
+		assert.Len(t, entries, 1)
+		for _, desc := range entries {
+			assert.Contains(t, desc, "%ARCH%\nany\n")
+		}
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/aarch64/%s.db", rootURL, repository, repository))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		filename := fmt.Sprintf("%s-%s-x86_64.pkg.tar.zst", packageName, packageVersion)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s", rootURL, repository, filename))
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, content, resp.Body.Bytes())
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s.sig", rootURL, repository, filename))
+		resp = MakeRequest(t, req, http.StatusOK)
+		checkSignature(t, content, resp.Body.Bytes())
+
+		// packages of the "any" architecture are available for all architectures
+		filename = fmt.Sprintf("%s-%s-any.pkg.tar.zst", anyPackageName, packageVersion)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/aarch64/%s", rootURL, repository, filename))
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, anyContent, resp.Body.Bytes())
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s", rootURL, repository, strings.Replace(filename, "any", "aarch64", 1)))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("View", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/arch/%s/%s", user.Name, packageName, packageVersion))
+		resp := MakeRequest(t, req, http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "pacman-key --lsign-key")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		deleteURL := fmt.Sprintf("%s/%s/%s/%s/x86_64", rootURL, repository, packageName, packageVersion)
+
+		req := NewRequest(t, "DELETE", deleteURL)
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequest(t, "DELETE", deleteURL).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "DELETE", deleteURL).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		// the x86_64 database still contains the "any" package
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s.db", rootURL, repository, repository))
+		resp := MakeRequest(t, req, http.StatusOK)
+		entries := readDatabase(t, resp.Body.Bytes())
+		assert.Len(t, entries, 1)
+		for _, desc := range entries {
+			assert.Contains(t, desc, "%ARCH%\nany\n")
+		}
+
+		req = NewRequest(t, "DELETE", fmt.Sprintf("%s/%s/%s/%s/%s", rootURL, repository, anyPackageName, packageVersion, arch_module.AnyArch)).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeArch)
+		assert.NoError(t, err)
+		assert.Empty(t, pvs)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/x86_64/%s.db", rootURL, repository, repository))
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+}
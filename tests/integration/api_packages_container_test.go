@@ -9,9 +9,11 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
@@ -74,9 +76,28 @@ func TestPackageContainer(t *testing.T) {
 	untaggedManifestDigest := "sha256:4305f5f5572b9a426b88909b036e52ee3cf3d7b9c1b01fac840e90747f56623d"
 	untaggedManifestContent := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageManifest + `","config":{"mediaType":"application/vnd.docker.container.image.v1+json","digest":"sha256:4607e093bec406eaadb6f3a340f63400c9d3a7038680744c406903766b938f0d","size":1069},"layers":[{"mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip","digest":"sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4","size":32}]}`
 
+	emptyConfigDigest := "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"
+	emptyConfigContent := `{}`
+
+	signatureArtifactType := "application/vnd.dev.cosign.artifact.sig.v1+json"
+	signatureManifestContent := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageManifest + `","artifactType":"` + signatureArtifactType + `","config":{"mediaType":"` + oci.MediaTypeEmptyJSON + `","digest":"` + emptyConfigDigest + `","size":2},"layers":[{"mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip","digest":"sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4","size":32}],"subject":{"mediaType":"` + oci.MediaTypeImageManifest + `","digest":"` + untaggedManifestDigest + `","size":1514},"annotations":{"org.opencontainers.image.created":"2024-01-01T00:00:00Z"}}`
+	signatureManifestDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(signatureManifestContent)))
+
 	indexManifestDigest := "sha256:bab112d6efb9e7f221995caaaa880352feb5bd8b1faf52fae8d12c113aa123ec"
 	indexManifestContent := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageIndex + `","manifests":[{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","digest":"` + manifestDigest + `","platform":{"os":"linux","architecture":"arm","variant":"v7"}},{"mediaType":"` + oci.MediaTypeImageManifest + `","digest":"` + untaggedManifestDigest + `","platform":{"os":"linux","architecture":"arm64","variant":"v8"}}]}`
 
+	sbomContent := `{"spdxVersion":"SPDX-2.3","packages":[{"name":"busybox","versionInfo":"1.36.1-r5","licenseConcluded":"GPL-2.0-only","externalRefs":[{"referenceType":"purl","referenceLocator":"pkg:apk/alpine/busybox@1.36.1-r5"}]}]}`
+	sbomDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(sbomContent)))
+	sbomManifestContent := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageManifest + `","artifactType":"` + container_module.MediaTypeSPDX + `","config":{"mediaType":"` + oci.MediaTypeEmptyJSON + `","digest":"` + emptyConfigDigest + `","size":2},"layers":[{"mediaType":"` + container_module.MediaTypeSPDX + `","digest":"` + sbomDigest + `","size":` + fmt.Sprint(len(sbomContent)) + `}],"subject":{"mediaType":"` + oci.MediaTypeImageManifest + `","digest":"` + untaggedManifestDigest + `","size":1514}}`
+	sbomManifestDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(sbomManifestContent)))
+
+	attestationContent := `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://cyclonedx.org/bom","predicate":{"bomFormat":"CycloneDX","components":[{"type":"library","name":"OpenSSL","version":"3.1.4"}]}}`
+	attestationDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(attestationContent)))
+	attestationManifestContent := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageManifest + `","config":{"mediaType":"` + oci.MediaTypeEmptyJSON + `","digest":"` + emptyConfigDigest + `","size":2},"layers":[{"mediaType":"` + container_module.MediaTypeInToto + `","digest":"` + attestationDigest + `","size":` + fmt.Sprint(len(attestationContent)) + `,"annotations":{"in-toto.io/predicate-type":"https://cyclonedx.org/bom"}}]}`
+	attestationManifestDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(attestationManifestContent)))
+	attestationIndexContent := `{"schemaVersion":2,"mediaType":"` + oci.MediaTypeImageIndex + `","manifests":[{"mediaType":"application/vnd.docker.distribution.manifest.v2+json","digest":"` + manifestDigest + `","platform":{"os":"linux","architecture":"amd64"}},{"mediaType":"` + oci.MediaTypeImageManifest + `","digest":"` + attestationManifestDigest + `","platform":{"os":"unknown","architecture":"unknown"},"annotations":{"vnd.docker.reference.type":"attestation-manifest","vnd.docker.reference.digest":"` + manifestDigest + `"}}]}`
+	attestationIndexDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(attestationIndexContent)))
+
 	anonymousToken := ""
 	userToken := ""
 	readToken := ""
@@ -684,6 +705,228 @@ func TestPackageContainer(t *testing.T) {
 				assert.Len(t, apiPackages, 4) // "latest", "main", "multi", "sha256:..."
 			})
 
+			t.Run("Referrers", func(t *testing.T) {
+				defer tests.PrintCurrentTest(t)()
+
+				req := NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", url, emptyConfigDigest), strings.NewReader(emptyConfigContent)).
+					AddTokenAuth(userToken)
+				MakeRequest(t, req, http.StatusCreated)
+
+				req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, signatureManifestDigest), strings.NewReader(signatureManifestContent)).
+					AddTokenAuth(userToken).
+					SetHeader("Content-Type", oci.MediaTypeImageManifest)
+				resp := MakeRequest(t, req, http.StatusCreated)
+
+				assert.Equal(t, signatureManifestDigest, resp.Header().Get("Docker-Content-Digest"))
+				assert.Equal(t, untaggedManifestDigest, resp.Header().Get("OCI-Subject"))
+
+				pv, err := packages_model.GetVersionByNameAndVersion(db.DefaultContext, user.ID, packages_model.TypeContainer, image, signatureManifestDigest)
+				assert.NoError(t, err)
+
+				pd, err := packages_model.GetPackageDescriptor(db.DefaultContext, pv)
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, []string{untaggedManifestDigest}, getAllByName(pd.VersionProperties, container_module.PropertyManifestSubject))
+				metadata := pd.Metadata.(*container_module.Metadata)
+				assert.Equal(t, signatureArtifactType, metadata.ArtifactType)
+
+				req = NewRequest(t, "GET", fmt.Sprintf("%s/referrers/%s", url, "invalid")).
+					AddTokenAuth(userToken)
+				MakeRequest(t, req, http.StatusBadRequest)
+
+				req = NewRequest(t, "GET", fmt.Sprintf("%s/referrers/%s", url, unknownDigest)).
+					AddTokenAuth(userToken)
+				resp = MakeRequest(t, req, http.StatusOK)
+
+				var index oci.Index
+				DecodeJSON(t, resp, &index)
+				assert.Equal(t, oci.MediaTypeImageIndex, resp.Header().Get("Content-Type"))
+				assert.Equal(t, oci.MediaTypeImageIndex, index.MediaType)
+				assert.Empty(t, index.Manifests)
+
+				req = NewRequest(t, "GET", fmt.Sprintf("%s/referrers/%s", url, untaggedManifestDigest)).
+					AddTokenAuth(anonymousToken)
+				resp = MakeRequest(t, req, http.StatusOK)
+
+				index = oci.Index{}
+				DecodeJSON(t, resp, &index)
+				assert.Empty(t, resp.Header().Get("OCI-Filters-Applied"))
+				if assert.Len(t, index.Manifests, 1) {
+					assert.Equal(t, oci.MediaTypeImageManifest, index.Manifests[0].MediaType)
+					assert.Equal(t, signatureManifestDigest, string(index.Manifests[0].Digest))
+					assert.EqualValues(t, len(signatureManifestContent), index.Manifests[0].Size)
+					assert.Equal(t, signatureArtifactType, index.Manifests[0].ArtifactType)
+					assert.Equal(t, "2024-01-01T00:00:00Z", index.Manifests[0].Annotations["org.opencontainers.image.created"])
+				}
+
+				req = NewRequest(t, "GET", fmt.Sprintf("%s/referrers/%s?artifactType=%s", url, untaggedManifestDigest, "application/spdx%2Bjson")).
+					AddTokenAuth(userToken)
+				resp = MakeRequest(t, req, http.StatusOK)
+
+				index = oci.Index{}
+				DecodeJSON(t, resp, &index)
+				assert.Equal(t, "artifactType", resp.Header().Get("OCI-Filters-Applied"))
+				assert.Empty(t, index.Manifests)
+
+				req = NewRequest(t, "GET", fmt.Sprintf("%s/referrers/%s?artifactType=%s", url, untaggedManifestDigest, strings.ReplaceAll(signatureArtifactType, "+", "%2B"))).
+					AddTokenAuth(userToken)
+				resp = MakeRequest(t, req, http.StatusOK)
+
+				index = oci.Index{}
+				DecodeJSON(t, resp, &index)
+				assert.Len(t, index.Manifests, 1)
+
+				// referrers may be pushed before their subject, they are only removed by the cleanup if they are expired
+				danglingManifestContent := strings.ReplaceAll(signatureManifestContent, untaggedManifestDigest, unknownDigest)
+				danglingManifestDigest := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(danglingManifestContent)))
+
+				req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, danglingManifestDigest), strings.NewReader(danglingManifestContent)).
+					AddTokenAuth(userToken).
+					SetHeader("Content-Type", oci.MediaTypeImageManifest)
+				MakeRequest(t, req, http.StatusCreated)
+
+				pvs, err := container_model.SearchDanglingReferrers(db.DefaultContext, time.Hour)
+				assert.NoError(t, err)
+				assert.Empty(t, pvs)
+
+				pvs, err = container_model.SearchDanglingReferrers(db.DefaultContext, -time.Hour)
+				assert.NoError(t, err)
+				if assert.Len(t, pvs, 1) {
+					assert.Equal(t, danglingManifestDigest, pvs[0].Version)
+				}
+
+				req = NewRequest(t, "DELETE", fmt.Sprintf("%s/manifests/%s", url, danglingManifestDigest)).
+					AddTokenAuth(userToken)
+				MakeRequest(t, req, http.StatusAccepted)
+			})
+
+			t.Run("SBOM", func(t *testing.T) {
+				defer tests.PrintCurrentTest(t)()
+
+				apiURL := fmt.Sprintf("/api/v1/packages/%s/container/%s", user.Name, neturl.PathEscape(image))
+
+				listComponents := func(t *testing.T, version string) []*api.PackageComponent {
+					req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/components", apiURL, version)).
+						AddTokenAuth(token)
+					resp := MakeRequest(t, req, http.StatusOK)
+
+					var components []*api.PackageComponent
+					DecodeJSON(t, resp, &components)
+					return components
+				}
+
+				t.Run("Referrer", func(t *testing.T) {
+					defer tests.PrintCurrentTest(t)()
+
+					req := NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", url, sbomDigest), strings.NewReader(sbomContent)).
+						AddTokenAuth(userToken)
+					MakeRequest(t, req, http.StatusCreated)
+
+					req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, sbomManifestDigest), strings.NewReader(sbomManifestContent)).
+						AddTokenAuth(userToken).
+						SetHeader("Content-Type", oci.MediaTypeImageManifest)
+					MakeRequest(t, req, http.StatusCreated)
+
+					components := listComponents(t, untaggedManifestDigest)
+					if assert.Len(t, components, 1) {
+						assert.Equal(t, "busybox", components[0].Name)
+						assert.Equal(t, "1.36.1-r5", components[0].Version)
+						assert.Equal(t, "GPL-2.0-only", components[0].License)
+						assert.Equal(t, "pkg:apk/alpine/busybox@1.36.1-r5", components[0].PURL)
+						assert.Equal(t, untaggedManifestDigest, components[0].Digest)
+					}
+
+					// the index contains the described manifest
+					assert.Len(t, listComponents(t, multiTag), 1)
+					assert.Empty(t, listComponents(t, tags[0]))
+
+					req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/container/%s/%s", user.Name, neturl.PathEscape(image), multiTag))
+					resp := session.MakeRequest(t, req, http.StatusOK)
+					assert.Contains(t, resp.Body.String(), "pkg:apk/alpine/busybox@1.36.1-r5")
+				})
+
+				t.Run("Attestation", func(t *testing.T) {
+					defer tests.PrintCurrentTest(t)()
+
+					req := NewRequestWithBody(t, "POST", fmt.Sprintf("%s/blobs/uploads?digest=%s", url, attestationDigest), strings.NewReader(attestationContent)).
+						AddTokenAuth(userToken)
+					MakeRequest(t, req, http.StatusCreated)
+
+					req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, attestationManifestDigest), strings.NewReader(attestationManifestContent)).
+						AddTokenAuth(userToken).
+						SetHeader("Content-Type", oci.MediaTypeImageManifest)
+					MakeRequest(t, req, http.StatusCreated)
+
+					req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/manifests/%s", url, attestationIndexDigest), strings.NewReader(attestationIndexContent)).
+						AddTokenAuth(userToken).
+						SetHeader("Content-Type", oci.MediaTypeImageIndex)
+					MakeRequest(t, req, http.StatusCreated)
+
+					components := listComponents(t, tags[0])
+					if assert.Len(t, components, 1) {
+						assert.Equal(t, "OpenSSL", components[0].Name)
+						assert.Equal(t, "3.1.4", components[0].Version)
+						assert.Equal(t, "library", components[0].Type)
+						assert.Equal(t, manifestDigest, components[0].Digest)
+					}
+				})
+
+				t.Run("Search", func(t *testing.T) {
+					defer tests.PrintCurrentTest(t)()
+
+					searchURL := fmt.Sprintf("/api/v1/packages/%s/-/components", user.Name)
+
+					req := NewRequest(t, "GET", searchURL).
+						AddTokenAuth(token)
+					MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+					search := func(t *testing.T, name, version string) []*api.PackageComponentMatch {
+						req := NewRequest(t, "GET", fmt.Sprintf("%s?name=%s&version=%s", searchURL, name, version)).
+							AddTokenAuth(token)
+						resp := MakeRequest(t, req, http.StatusOK)
+
+						var matches []*api.PackageComponentMatch
+						DecodeJSON(t, resp, &matches)
+
+						result := make([]*api.PackageComponentMatch, 0, len(matches))
+						for _, m := range matches {
+							if m.Image == image {
+								result = append(result, m)
+							}
+						}
+						return result
+					}
+
+					matches := search(t, "BusyBox", "1.36.1-r5")
+					if assert.Len(t, matches, 1) {
+						assert.Equal(t, []string{untaggedManifestDigest}, matches[0].Versions)
+						assert.Equal(t, "busybox", matches[0].Component.Name)
+					}
+					assert.Empty(t, search(t, "busybox", "1.0.0"))
+
+					matches = search(t, "openssl", "")
+					if assert.Len(t, matches, 1) {
+						assert.ElementsMatch(t, tags, matches[0].Versions)
+					}
+
+					req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/components?name=busybox", user.Name))
+					resp := session.MakeRequest(t, req, http.StatusOK)
+					assert.Contains(t, resp.Body.String(), untaggedManifestDigest)
+				})
+
+				t.Run("Delete", func(t *testing.T) {
+					defer tests.PrintCurrentTest(t)()
+
+					for _, digest := range []string{sbomManifestDigest, attestationIndexDigest, attestationManifestDigest} {
+						req := NewRequest(t, "DELETE", fmt.Sprintf("%s/manifests/%s", url, digest)).
+							AddTokenAuth(userToken)
+						MakeRequest(t, req, http.StatusAccepted)
+					}
+
+					assert.Empty(t, listComponents(t, untaggedManifestDigest))
+					assert.Empty(t, listComponents(t, tags[0]))
+				})
+			})
+
 			t.Run("Delete", func(t *testing.T) {
 				t.Run("Blob", func(t *testing.T) {
 					defer tests.PrintCurrentTest(t)()
@@ -707,6 +950,11 @@ func TestPackageContainer(t *testing.T) {
 					req = NewRequest(t, "HEAD", fmt.Sprintf("%s/manifests/%s", url, untaggedManifestDigest)).
 						AddTokenAuth(userToken)
 					MakeRequest(t, req, http.StatusNotFound)
+
+					// the referrers of the deleted manifest are deleted too
+					req = NewRequest(t, "HEAD", fmt.Sprintf("%s/manifests/%s", url, signatureManifestDigest)).
+						AddTokenAuth(userToken)
+					MakeRequest(t, req, http.StatusNotFound)
 				})
 
 				t.Run("ManifestByTag", func(t *testing.T) {
@@ -4,7 +4,9 @@
 package integration
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,6 +18,7 @@ import (
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/packages/npm"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/tests"
@@ -328,4 +331,67 @@ func TestPackageNpm(t *testing.T) {
 			assert.Len(t, pvs, 0)
 		})
 	})
+
+	t.Run("Provenance", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		version := packageVersion + "-provenance"
+
+		tarball, _ := base64.StdEncoding.DecodeString(data)
+		hash := sha256.Sum256(tarball)
+
+		buildBundle := func(digest string) string {
+			statement := `{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"pkg:npm/` + packageName + `@` + version + `","digest":{"sha256":"` + digest + `"}}],"predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`
+			bundle, _ := json.Marshal(`{"mediaType":"application/vnd.dev.sigstore.bundle.v0.3+json","verificationMaterial":{},"dsseEnvelope":{"payloadType":"application/vnd.in-toto+json","payload":"` + base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[]}}`)
+			return strings.Replace(buildUpload(version), `"_attachments": {`, `"_attachments": {
+			  "`+packageName+`-`+version+`.sigstore": {
+				"data": `+string(bundle)+`
+			  },`, 1)
+		}
+
+		req := NewRequestWithBody(t, "PUT", root, strings.NewReader(buildBundle(strings.Repeat("0", 64)))).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "PUT", root, strings.NewReader(buildBundle(hex.EncodeToString(hash[:])))).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusCreated)
+
+		attestationsURL := fmt.Sprintf("/api/packages/%s/npm/-/npm/v1/attestations/%s@%s", user.Name, packageName, version)
+
+		req = NewRequest(t, "GET", root).
+			AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result npm.PackageMetadata
+		DecodeJSON(t, resp, &result)
+
+		pmv := result.Versions[version]
+		assert.NotNil(t, pmv)
+		assert.NotNil(t, pmv.Dist.Attestations)
+		assert.Equal(t, setting.AppURL+attestationsURL[1:], pmv.Dist.Attestations.URL)
+		assert.NotNil(t, pmv.Dist.Attestations.Provenance)
+		assert.Equal(t, "https://slsa.dev/provenance/v1", pmv.Dist.Attestations.Provenance.PredicateType)
+
+		req = NewRequest(t, "GET", attestationsURL).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		var attestations npm.PackageAttestations
+		DecodeJSON(t, resp, &attestations)
+
+		assert.Len(t, attestations.Attestations, 1)
+		assert.Equal(t, "https://slsa.dev/provenance/v1", attestations.Attestations[0].PredicateType)
+		assert.NotNil(t, attestations.Attestations[0].Bundle)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/-/npm/v1/attestations/%s@%s", user.Name, packageName, "0.0.0")).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/npm/%s/%s", user.Name, url.PathEscape(packageName), version))
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		assert.Equal(t, 1, htmlDoc.doc.Find(".octicon-unverified").Length())
+	})
 }
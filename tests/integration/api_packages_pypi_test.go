@@ -5,19 +5,31 @@ package integration
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"math/big"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/packages/pypi"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
@@ -179,4 +191,86 @@ func TestPackagePyPI(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Provenance", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		filename := "test-provenance.whl"
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		issuer, _ := asn1.Marshal("https://token.actions.githubusercontent.com")
+		identity, _ := url.Parse("https://github.com/gitea/test/.github/workflows/release.yml@refs/tags/v1.0.1")
+		certificate, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(10 * time.Minute),
+			URIs:         []*url.URL{identity},
+			ExtraExtensions: []pkix.Extension{
+				{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}, Value: issuer},
+			},
+		}, &x509.Certificate{SerialNumber: big.NewInt(1)}, &key.PublicKey, key)
+		assert.NoError(t, err)
+
+		buildAttestation := func(digest string) string {
+			statement := `{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"` + filename + `","digest":{"sha256":"` + digest + `"}}],"predicateType":"https://docs.pypi.org/attestations/publish/v1","predicate":null}`
+			pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len("application/vnd.in-toto+json"), "application/vnd.in-toto+json", len(statement), statement)
+			sum := sha256.Sum256([]byte(pae))
+			signature, err := ecdsa.SignASN1(rand.Reader, key, sum[:])
+			assert.NoError(t, err)
+
+			return `[{"version":1,"verification_material":{"certificate":"` + base64.StdEncoding.EncodeToString(certificate) + `","transparency_entries":[]},"envelope":{"statement":"` + base64.StdEncoding.EncodeToString([]byte(statement)) + `","signature":"` + base64.StdEncoding.EncodeToString(signature) + `"}}]`
+		}
+
+		upload := func(t *testing.T, attestations string, expectedStatus int) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, _ := writer.CreateFormFile("content", filename)
+			_, _ = io.Copy(part, strings.NewReader(content))
+
+			writer.WriteField("name", packageName)
+			writer.WriteField("version", packageVersion)
+			writer.WriteField("sha256_digest", hashSHA256)
+			writer.WriteField("attestations", attestations)
+
+			_ = writer.Close()
+
+			req := NewRequestWithBody(t, "POST", root, body).
+				SetHeader("Content-Type", writer.FormDataContentType()).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, expectedStatus)
+		}
+
+		upload(t, "invalid", http.StatusBadRequest)
+		upload(t, buildAttestation(strings.Repeat("0", 64)), http.StatusBadRequest)
+		upload(t, buildAttestation(hashSHA256), http.StatusCreated)
+
+		provenanceURL := fmt.Sprintf("%s/integrity/%s/%s/%s/provenance", root, packageName, packageVersion, filename)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("%s/simple/%s", root, packageName)).
+			AddBasicAuth(user.Name)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		provenance, exists := htmlDoc.doc.Find(fmt.Sprintf(`a:contains("%s")`, filename)).Attr("data-provenance")
+		assert.True(t, exists)
+		assert.Equal(t, setting.AppURL+provenanceURL[1:], provenance)
+		assert.Equal(t, 2, htmlDoc.doc.Find("a:not([data-provenance])").Length())
+
+		req = NewRequest(t, "GET", provenanceURL).
+			AddBasicAuth(user.Name)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		var result pypi.Provenance
+		DecodeJSON(t, resp, &result)
+
+		assert.Equal(t, 1, result.Version)
+		assert.Len(t, result.AttestationBundles, 1)
+		assert.Equal(t, map[string]string{"kind": "GitHub", "repository": "gitea/test", "workflow": "release.yml"}, result.AttestationBundles[0].Publisher)
+		assert.Len(t, result.AttestationBundles[0].Attestations, 1)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("%s/integrity/%s/%s/%s/provenance", root, packageName, packageVersion, "test.whl")).
+			AddBasicAuth(user.Name)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
 }
@@ -0,0 +1,360 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	terraform_module "code.gitea.io/gitea/modules/packages/terraform"
+	"code.gitea.io/gitea/modules/setting"
+	terraform_router "code.gitea.io/gitea/routers/api/packages/terraform"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageTerraform(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	t.Run("ServiceDiscovery", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/.well-known/terraform.json")
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var result map[string]string
+		DecodeJSON(t, resp, &result)
+		assert.Equal(t, setting.AppSubURL+"/api/packages/-/terraform/modules/v1/", result["modules.v1"])
+		assert.Equal(t, setting.AppSubURL+"/api/packages/-/terraform/providers/v1/", result["providers.v1"])
+	})
+
+	rootURL := fmt.Sprintf("/api/packages/%s/terraform", user.Name)
+
+	t.Run("Module", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		moduleName := "vpc"
+		moduleSystem := "aws"
+		moduleVersion := "1.2.0"
+
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(zw)
+		for _, file := range []struct {
+			Name    string
+			Content string
+		}{
+			{"README.md", "# VPC\n\nCreates a VPC.\n"},
+			{"main.tf", `variable "cidr" {
+  description = "CIDR block of the VPC"
+  type        = string
+}
+
+output "id" {
+  value = "vpc"
+}
+`},
+		} {
+			_ = tw.WriteHeader(&tar.Header{Name: file.Name, Mode: 0o644, Size: int64(len(file.Content))})
+			_, _ = tw.Write([]byte(file.Content))
+		}
+		tw.Close()
+		zw.Close()
+		content := buf.Bytes()
+
+		moduleURL := fmt.Sprintf("%s/modules/%s/%s/%s", rootURL, moduleName, moduleSystem, moduleVersion)
+		registryURL := fmt.Sprintf("/api/packages/-/terraform/modules/v1/%s/%s/%s", user.Name, moduleName, moduleSystem)
+		filename := fmt.Sprintf("%s-%s-%s.tar.gz", moduleName, moduleSystem, moduleVersion)
+
+		t.Run("Upload", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithBody(t, "PUT", moduleURL, bytes.NewReader(content))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", moduleURL, bytes.NewReader([]byte("dummy"))).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/modules/%s/%s/v%s", rootURL, moduleName, moduleSystem, moduleVersion), bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", moduleURL, bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			assert.NoError(t, err)
+			assert.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			assert.NoError(t, err)
+			assert.Equal(t, moduleName+"/"+moduleSystem, pd.Package.Name)
+			assert.Equal(t, moduleVersion, pd.Version.Version)
+			assert.IsType(t, &terraform_module.Metadata{}, pd.Metadata)
+			metadata := pd.Metadata.(*terraform_module.Metadata)
+			assert.Equal(t, terraform_module.KindModule, metadata.Kind)
+			assert.Equal(t, "Creates a VPC.", metadata.Description)
+			assert.Len(t, metadata.Variables, 1)
+			assert.Len(t, metadata.Outputs, 1)
+
+			assert.Len(t, pd.Files, 1)
+			assert.Equal(t, filename, pd.Files[0].File.Name)
+			assert.True(t, pd.Files[0].File.IsLead)
+			assert.Equal(t, int64(len(content)), pd.Files[0].Blob.Size)
+
+			req = NewRequestWithBody(t, "PUT", moduleURL, bytes.NewReader(content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusConflict)
+		})
+
+		t.Run("ListVersions", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", registryURL+"/versions")
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result terraform_router.ModuleVersionsResponse
+			DecodeJSON(t, resp, &result)
+			assert.Len(t, result.Modules, 1)
+			assert.Len(t, result.Modules[0].Versions, 1)
+			assert.Equal(t, moduleVersion, result.Modules[0].Versions[0].Version)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/packages/-/terraform/modules/v1/%s/%s/gcp/versions", user.Name, moduleName))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/download", registryURL, moduleVersion))
+			resp := MakeRequest(t, req, http.StatusNoContent)
+
+			downloadURL := resp.Header().Get("X-Terraform-Get")
+			assert.Equal(t, fmt.Sprintf("%s%s/%s", setting.AppURL, strings.TrimPrefix(moduleURL, "/"), filename), downloadURL)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s", moduleURL, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, content, resp.Body.Bytes())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/1.0.0/download", registryURL))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("View", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/terraform/%s/%s", user.Name, url.PathEscape(moduleName+"/"+moduleSystem), moduleVersion))
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), fmt.Sprintf(`module "%s"`, moduleName))
+			assert.Contains(t, resp.Body.String(), "CIDR block of the VPC")
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "DELETE", moduleURL)
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequest(t, "DELETE", moduleURL).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "DELETE", moduleURL).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusNotFound)
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			assert.NoError(t, err)
+			assert.Empty(t, pvs)
+		})
+	})
+
+	t.Run("Provider", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		providerType := "example"
+		providerVersion := "0.1.0"
+
+		createArchive := func(content string) []byte {
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			w, _ := zw.Create(fmt.Sprintf("terraform-provider-%s_v%s", providerType, providerVersion))
+			_, _ = w.Write([]byte(content))
+			zw.Close()
+			return buf.Bytes()
+		}
+
+		platforms := []struct {
+			OS      string
+			Arch    string
+			Content []byte
+		}{
+			{"darwin", "arm64", createArchive("darwin")},
+			{"linux", "amd64", createArchive("linux")},
+		}
+
+		versionURL := fmt.Sprintf("%s/providers/%s/%s", rootURL, providerType, providerVersion)
+		registryURL := fmt.Sprintf("/api/packages/-/terraform/providers/v1/%s/%s", user.Name, providerType)
+
+		t.Run("Upload", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			uploadURL := fmt.Sprintf("%s/%s/%s", versionURL, platforms[0].OS, platforms[0].Arch)
+
+			req := NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(platforms[0].Content))
+			MakeRequest(t, req, http.StatusUnauthorized)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader([]byte("dummy"))).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL+"?protocols=5", bytes.NewReader(platforms[0].Content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			req = NewRequestWithBody(t, "PUT", strings.Replace(uploadURL, providerType, "other", 1), bytes.NewReader(platforms[0].Content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusBadRequest)
+
+			for _, p := range platforms {
+				req = NewRequestWithBody(t, "PUT", fmt.Sprintf("%s/%s/%s?protocols=5.0,6.0", versionURL, p.OS, p.Arch), bytes.NewReader(p.Content)).
+					AddBasicAuth(user.Name)
+				MakeRequest(t, req, http.StatusCreated)
+			}
+
+			pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+			assert.NoError(t, err)
+			assert.Len(t, pvs, 1)
+
+			pd, err := packages.GetPackageDescriptor(db.DefaultContext, pvs[0])
+			assert.NoError(t, err)
+			assert.Equal(t, providerType, pd.Package.Name)
+			assert.Equal(t, providerVersion, pd.Version.Version)
+			metadata := pd.Metadata.(*terraform_module.Metadata)
+			assert.Equal(t, terraform_module.KindProvider, metadata.Kind)
+			assert.Equal(t, []string{"5.0", "6.0"}, metadata.Protocols)
+			assert.Len(t, pd.Files, 2)
+
+			req = NewRequestWithBody(t, "PUT", uploadURL, bytes.NewReader(platforms[0].Content)).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusConflict)
+		})
+
+		t.Run("ListVersions", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", registryURL+"/versions")
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result terraform_router.ProviderVersionsResponse
+			DecodeJSON(t, resp, &result)
+			assert.Len(t, result.Versions, 1)
+			assert.Equal(t, providerVersion, result.Versions[0].Version)
+			assert.Equal(t, []string{"5.0", "6.0"}, result.Versions[0].Protocols)
+			assert.ElementsMatch(t, []*terraform_router.ProviderPlatform{
+				{OS: "darwin", Arch: "arm64"},
+				{OS: "linux", Arch: "amd64"},
+			}, result.Versions[0].Platforms)
+		})
+
+		t.Run("Download", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			var shasums strings.Builder
+			for _, p := range platforms {
+				sum := sha256.Sum256(p.Content)
+				fmt.Fprintf(&shasums, "%s  terraform-provider-%s_%s_%s_%s.zip\n", hex.EncodeToString(sum[:]), providerType, providerVersion, p.OS, p.Arch)
+			}
+
+			p := platforms[1]
+			filename := fmt.Sprintf("terraform-provider-%s_%s_%s_%s.zip", providerType, providerVersion, p.OS, p.Arch)
+
+			req := NewRequest(t, "GET", fmt.Sprintf("%s/%s/download/%s/%s", registryURL, providerVersion, p.OS, p.Arch))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var result terraform_router.ProviderPackageResponse
+			DecodeJSON(t, resp, &result)
+			sum := sha256.Sum256(p.Content)
+			assert.Equal(t, filename, result.Filename)
+			assert.Equal(t, hex.EncodeToString(sum[:]), result.Shasum)
+			assert.Equal(t, []string{"5.0", "6.0"}, result.Protocols)
+			assert.Equal(t, fmt.Sprintf("%s%s/%s/%s/%s", setting.AppURL, strings.TrimPrefix(versionURL, "/"), p.OS, p.Arch, filename), result.DownloadURL)
+			assert.Equal(t, setting.AppURL+strings.TrimPrefix(versionURL, "/")+"/SHA256SUMS", result.ShasumsURL)
+			assert.Equal(t, setting.AppURL+strings.TrimPrefix(versionURL, "/")+"/SHA256SUMS.sig", result.ShasumsSignatureURL)
+			assert.Len(t, result.SigningKeys.GPGPublicKeys, 1)
+
+			keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(result.SigningKeys.GPGPublicKeys[0].ASCIIArmor))
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("%016X", keyring[0].PrimaryKey.KeyId), result.SigningKeys.GPGPublicKeys[0].KeyID)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s/%s", versionURL, p.OS, p.Arch, filename))
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, p.Content, resp.Body.Bytes())
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/%s/%s", versionURL, platforms[0].OS, platforms[0].Arch, filename))
+			MakeRequest(t, req, http.StatusNotFound)
+
+			req = NewRequest(t, "GET", versionURL+"/SHA256SUMS")
+			resp = MakeRequest(t, req, http.StatusOK)
+			assert.Equal(t, shasums.String(), resp.Body.String())
+
+			req = NewRequest(t, "GET", versionURL+"/SHA256SUMS.sig")
+			resp = MakeRequest(t, req, http.StatusOK)
+			_, err = openpgp.CheckDetachedSignature(keyring, strings.NewReader(shasums.String()), resp.Body, nil)
+			assert.NoError(t, err)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("%s/%s/download/windows/amd64", registryURL, providerVersion))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		t.Run("View", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequest(t, "GET", fmt.Sprintf("/%s/-/packages/terraform/%s/%s", user.Name, providerType, providerVersion))
+			resp := MakeRequest(t, req, http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "required_providers")
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			for i, p := range platforms {
+				deleteURL := fmt.Sprintf("%s/%s/%s", versionURL, p.OS, p.Arch)
+
+				req := NewRequest(t, "DELETE", deleteURL)
+				MakeRequest(t, req, http.StatusUnauthorized)
+
+				req = NewRequest(t, "DELETE", deleteURL).
+					AddBasicAuth(user.Name)
+				MakeRequest(t, req, http.StatusNoContent)
+
+				pvs, err := packages.GetVersionsByPackageType(db.DefaultContext, user.ID, packages.TypeTerraform)
+				assert.NoError(t, err)
+				assert.Len(t, pvs, len(platforms)-i-1)
+			}
+
+			req := NewRequest(t, "GET", registryURL+"/versions")
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
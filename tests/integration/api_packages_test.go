@@ -23,6 +23,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	packages_service "code.gitea.io/gitea/services/packages"
 	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+	repo_service "code.gitea.io/gitea/services/repository"
 	"code.gitea.io/gitea/tests"
 
 	"github.com/stretchr/testify/assert"
@@ -125,6 +126,46 @@ func TestPackageAPI(t *testing.T) {
 		})
 	})
 
+	t.Run("LinkPackage", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user, user, repo_service.CreateRepoOptions{
+			Name: "package-link-test",
+		})
+		assert.NoError(t, err)
+
+		linkURL := fmt.Sprintf("/api/v1/packages/%s/generic/%s/-/link/%s", user.Name, packageName, repo.Name)
+		unlinkURL := fmt.Sprintf("/api/v1/packages/%s/generic/%s/-/unlink", user.Name, packageName)
+
+		req := NewRequest(t, "POST", linkURL).
+			AddTokenAuth(tokenReadPackage)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/packages/%s/generic/%s/-/link/%s", user.Name, "dummy", repo.Name)).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "POST", fmt.Sprintf("/api/v1/packages/%s/generic/%s/-/link/%s", user.Name, packageName, "dummy")).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "POST", linkURL).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusCreated)
+
+		p, err := packages_model.GetPackageByName(db.DefaultContext, user.ID, packages_model.TypeGeneric, packageName)
+		assert.NoError(t, err)
+		assert.Equal(t, repo.ID, p.RepoID)
+
+		req = NewRequest(t, "POST", unlinkURL).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		p, err = packages_model.GetPackageByName(db.DefaultContext, user.ID, packages_model.TypeGeneric, packageName)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 0, p.RepoID)
+	})
+
 	t.Run("ListPackageFiles", func(t *testing.T) {
 		defer tests.PrintCurrentTest(t)()
 
@@ -148,6 +189,45 @@ func TestPackageAPI(t *testing.T) {
 		assert.Equal(t, "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e", files[0].HashSHA512)
 	})
 
+	t.Run("PackageAttestations", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		attestationsURL := fmt.Sprintf("/api/v1/packages/%s/generic/%s/%s/attestations", user.Name, packageName, packageVersion)
+		statement := func(digest string) string {
+			return `{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"` + filename + `","digest":{"sha256":"` + digest + `"}}],"predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`
+		}
+
+		req := NewRequestWithBody(t, "POST", attestationsURL, strings.NewReader(statement("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))).
+			AddTokenAuth(tokenReadPackage)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithBody(t, "POST", attestationsURL, strings.NewReader("invalid")).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "POST", attestationsURL, strings.NewReader(statement(strings.Repeat("0", 64)))).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "POST", attestationsURL, strings.NewReader(statement("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"))).
+			AddTokenAuth(tokenDeletePackage)
+		MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequest(t, "GET", attestationsURL).
+			AddTokenAuth(tokenReadPackage)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var attestations []*api.PackageAttestation
+		DecodeJSON(t, resp, &attestations)
+
+		assert.Len(t, attestations, 1)
+		assert.Equal(t, filename, attestations[0].FileName)
+		assert.Equal(t, "in-toto", attestations[0].Format)
+		assert.Equal(t, "https://slsa.dev/provenance/v1", attestations[0].PredicateType)
+		assert.Equal(t, "unverified", attestations[0].Status)
+		assert.Empty(t, attestations[0].SignerIdentity)
+	})
+
 	t.Run("DeletePackage", func(t *testing.T) {
 		defer tests.PrintCurrentTest(t)()
 
@@ -459,6 +539,8 @@ func TestPackageCleanup(t *testing.T) {
 
 	duration, _ := time.ParseDuration("-1h")
 
+	token := getUserToken(t, user.Name, auth_model.AccessTokenScopeWritePackage)
+
 	t.Run("Common", func(t *testing.T) {
 		defer tests.PrintCurrentTest(t)()
 
@@ -621,6 +703,27 @@ func TestPackageCleanup(t *testing.T) {
 				pcr, err := packages_model.InsertCleanupRule(db.DefaultContext, c.Rule)
 				assert.NoError(t, err)
 
+				if pcr.Enabled {
+					req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/%s/-/cleanup_rules/%d/preview", user.Name, pcr.ID)).
+						AddTokenAuth(token)
+					resp := MakeRequest(t, req, http.StatusOK)
+
+					var apiPackages []*api.Package
+					DecodeJSON(t, resp, &apiPackages)
+
+					expected := make([]string, 0, len(c.Versions))
+					for _, v := range c.Versions {
+						if !v.ShouldExist {
+							expected = append(expected, v.Version)
+						}
+					}
+					previewed := make([]string, 0, len(apiPackages))
+					for _, p := range apiPackages {
+						previewed = append(previewed, p.Version)
+					}
+					assert.ElementsMatch(t, expected, previewed)
+				}
+
 				err = packages_cleanup_service.CleanupTask(db.DefaultContext, duration)
 				assert.NoError(t, err)
 
@@ -639,4 +742,96 @@ func TestPackageCleanup(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("CleanupRulesAPI", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		rulesURL := fmt.Sprintf("/api/v1/packages/%s/-/cleanup_rules", user.Name)
+
+		req := NewRequest(t, "GET", rulesURL).
+			AddTokenAuth(getUserToken(t, "user4", auth_model.AccessTokenScopeWritePackage))
+		MakeRequest(t, req, http.StatusForbidden)
+
+		req = NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{
+			Type:      "dummy",
+			KeepCount: 1,
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{
+			Type:        "npm",
+			KeepCount:   3,
+			KeepPattern: "(",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{
+			Enabled:       true,
+			Type:          "npm",
+			KeepCount:     5,
+			RemovePattern: `.+-pre`,
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+
+		var rule *api.PackageCleanupRule
+		DecodeJSON(t, resp, &rule)
+		assert.True(t, rule.Enabled)
+		assert.Equal(t, "npm", rule.Type)
+		assert.Equal(t, 5, rule.KeepCount)
+		assert.Equal(t, ".+-pre", rule.RemovePattern)
+
+		req = NewRequestWithJSON(t, "POST", rulesURL, &api.CreatePackageCleanupRuleOption{
+			Type: "npm",
+		}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequest(t, "GET", rulesURL).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		var rules []*api.PackageCleanupRule
+		DecodeJSON(t, resp, &rules)
+		assert.Len(t, rules, 1)
+		assert.Equal(t, rule.ID, rules[0].ID)
+
+		ruleURL := fmt.Sprintf("%s/%d", rulesURL, rule.ID)
+
+		enabled := false
+		removeDays := 30
+		req = NewRequestWithJSON(t, "PATCH", ruleURL, &api.EditPackageCleanupRuleOption{
+			Enabled:    &enabled,
+			RemoveDays: &removeDays,
+		}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		DecodeJSON(t, resp, &rule)
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, 5, rule.KeepCount)
+		assert.Equal(t, 30, rule.RemoveDays)
+		assert.Equal(t, ".+-pre", rule.RemovePattern)
+
+		req = NewRequest(t, "GET", ruleURL).
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		DecodeJSON(t, resp, &rule)
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, 30, rule.RemoveDays)
+
+		req = NewRequest(t, "GET", ruleURL+"/preview").
+			AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+
+		var apiPackages []*api.Package
+		DecodeJSON(t, resp, &apiPackages)
+		assert.Empty(t, apiPackages)
+
+		req = NewRequest(t, "DELETE", ruleURL).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", ruleURL).
+			AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
 }
@@ -0,0 +1,316 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/test"
+	packages_proxy_service "code.gitea.io/gitea/services/packages/proxy"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// upstreamStandIn serves static files and counts the requests per path
+type upstreamStandIn struct {
+	mu     sync.Mutex
+	files  map[string]string
+	hits   map[string]int
+	broken bool
+}
+
+func (u *upstreamStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.hits[r.URL.Path]++
+	if u.broken {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	content, ok := u.files[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write([]byte(content))
+}
+
+func (u *upstreamStandIn) Hits(path string) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.hits[path]
+}
+
+func (u *upstreamStandIn) SetBroken(broken bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.broken = broken
+}
+
+func TestPackageUpstream(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	defer func() {
+		assert.NoError(t, packages_proxy_service.Init())
+	}()
+	defer test.MockVariableValue(&setting.Packages.UpstreamAllowedHostList, "loopback")()
+	assert.NoError(t, packages_proxy_service.Init())
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+	standIn := &upstreamStandIn{
+		files: make(map[string]string),
+		hits:  make(map[string]int),
+	}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	addUpstream := func(t *testing.T, packageType packages_model.Type, path string) *packages_model.PackageUpstream {
+		session := loginUser(t, user.Name)
+		req := NewRequestWithValues(t, "POST", "/user/settings/packages/upstreams/add", map[string]string{
+			"_csrf":        GetUserCSRFToken(t, session),
+			"enabled":      "on",
+			"type":         string(packageType),
+			"url":          server.URL + path,
+			"metadata_ttl": "3600",
+			"action":       "save",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+
+		pu, err := packages_model.GetUpstreamByOwnerAndType(db.DefaultContext, user.ID, packageType)
+		assert.NoError(t, err)
+		return pu
+	}
+
+	assertProxiedPackage := func(t *testing.T, packageType packages_model.Type, name string, fileCount int) {
+		p, err := packages_model.GetPackageByName(db.DefaultContext, user.ID, packageType, name)
+		assert.NoError(t, err)
+		pps, err := packages_model.GetPropertiesByName(db.DefaultContext, packages_model.PropertyTypePackage, p.ID, packages_proxy_service.PropertyUpstream)
+		assert.NoError(t, err)
+		assert.Len(t, pps, 1)
+
+		pvs, err := packages_model.GetVersionsByPackageName(db.DefaultContext, user.ID, packageType, name)
+		assert.NoError(t, err)
+		assert.Len(t, pvs, 1)
+		pfs, err := packages_model.GetFilesByVersionID(db.DefaultContext, pvs[0].ID)
+		assert.NoError(t, err)
+		assert.Len(t, pfs, fileCount)
+	}
+
+	t.Run("Maven", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		addUpstream(t, packages_model.TypeMaven, "/maven/")
+
+		metadata := `<?xml version="1.0" encoding="UTF-8"?><metadata><groupId>com.example</groupId><artifactId>lib</artifactId><versioning><release>1.0</release><versions><version>1.0</version></versions></versioning></metadata>`
+		pom := `<?xml version="1.0"?><project><modelVersion>4.0.0</modelVersion><groupId>com.example</groupId><artifactId>lib</artifactId><version>1.0</version><description>Proxied Description</description></project>`
+		jar := "jar content"
+		standIn.files["/maven/com/example/lib/maven-metadata.xml"] = metadata
+		standIn.files["/maven/com/example/lib/1.0/lib-1.0.pom"] = pom
+		standIn.files["/maven/com/example/lib/1.0/lib-1.0.jar"] = jar
+
+		root := fmt.Sprintf("/api/packages/%s/maven/com/example/lib", user.Name)
+
+		resp := MakeRequest(t, NewRequest(t, "GET", root+"/maven-metadata.xml"), http.StatusOK)
+		assert.Equal(t, metadata, resp.Body.String())
+		MakeRequest(t, NewRequest(t, "GET", root+"/maven-metadata.xml"), http.StatusOK)
+		assert.Equal(t, 1, standIn.Hits("/maven/com/example/lib/maven-metadata.xml"))
+
+		resp = MakeRequest(t, NewRequest(t, "GET", root+"/1.0/lib-1.0.jar"), http.StatusOK)
+		assert.Equal(t, jar, resp.Body.String())
+		resp = MakeRequest(t, NewRequest(t, "GET", root+"/1.0/lib-1.0.jar"), http.StatusOK)
+		assert.Equal(t, jar, resp.Body.String())
+		assert.Equal(t, 1, standIn.Hits("/maven/com/example/lib/1.0/lib-1.0.jar"))
+
+		resp = MakeRequest(t, NewRequest(t, "GET", root+"/1.0/lib-1.0.pom.sha256"), http.StatusOK)
+		hash := sha256.Sum256([]byte(pom))
+		assert.Equal(t, hex.EncodeToString(hash[:]), resp.Body.String())
+
+		MakeRequest(t, NewRequest(t, "GET", root+"/1.0/lib-1.0-sources.jar"), http.StatusNotFound)
+
+		assertProxiedPackage(t, packages_model.TypeMaven, "com.example-lib", 2)
+
+		pvs, err := packages_model.GetVersionsByPackageName(db.DefaultContext, user.ID, packages_model.TypeMaven, "com.example-lib")
+		assert.NoError(t, err)
+		assert.Contains(t, pvs[0].MetadataJSON, "Proxied Description")
+
+		t.Run("LocalPackagePrecedence", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			localRoot := fmt.Sprintf("/api/packages/%s/maven/com/example/local", user.Name)
+			standIn.files["/maven/com/example/local/maven-metadata.xml"] = metadata
+			standIn.files["/maven/com/example/local/2.0/local-2.0.jar"] = jar
+
+			req := NewRequestWithBody(t, "PUT", localRoot+"/1.0/local-1.0.pom", strings.NewReader(strings.ReplaceAll(pom, "<artifactId>lib</artifactId>", "<artifactId>local</artifactId>"))).
+				AddBasicAuth(user.Name)
+			MakeRequest(t, req, http.StatusCreated)
+
+			resp := MakeRequest(t, NewRequest(t, "GET", localRoot+"/maven-metadata.xml"), http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "<artifactId>local</artifactId>")
+			MakeRequest(t, NewRequest(t, "GET", localRoot+"/2.0/local-2.0.jar"), http.StatusNotFound)
+
+			assert.Zero(t, standIn.Hits("/maven/com/example/local/maven-metadata.xml"))
+			assert.Zero(t, standIn.Hits("/maven/com/example/local/2.0/local-2.0.jar"))
+		})
+	})
+
+	t.Run("Npm", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		pu := addUpstream(t, packages_model.TypeNpm, "/npm")
+
+		tarball := "tarball content"
+		hash := sha512.Sum512([]byte(tarball))
+		document := `{
+			"_id": "proxied-pkg",
+			"name": "proxied-pkg",
+			"dist-tags": {"latest": "1.0.0"},
+			"versions": {
+				"1.0.0": {
+					"name": "proxied-pkg",
+					"version": "1.0.0",
+					"description": "Proxied Description",
+					"repository": "https://example.com/proxied-pkg.git",
+					"dist": {
+						"integrity": "sha512-` + base64.StdEncoding.EncodeToString(hash[:]) + `",
+						"tarball": "` + server.URL + `/npm/proxied-pkg/-/proxied-pkg-1.0.0.tgz"
+					}
+				}
+			}
+		}`
+		standIn.files["/npm/proxied-pkg"] = document
+		standIn.files["/npm/proxied-pkg/-/proxied-pkg-1.0.0.tgz"] = tarball
+
+		root := fmt.Sprintf("/api/packages/%s/npm/proxied-pkg", user.Name)
+		tarballURL := fmt.Sprintf("%sapi/packages/%s/npm/proxied-pkg/-/1.0.0/proxied-pkg-1.0.0.tgz", setting.AppURL, user.Name)
+
+		resp := MakeRequest(t, NewRequest(t, "GET", root), http.StatusOK)
+
+		var result npm.PackageMetadata
+		DecodeJSON(t, resp, &result)
+		assert.Contains(t, result.Versions, "1.0.0")
+		assert.Equal(t, tarballURL, result.Versions["1.0.0"].Dist.Tarball)
+		assert.Equal(t, "https://example.com/proxied-pkg.git", result.Versions["1.0.0"].Repository.URL)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", tarballURL), http.StatusOK)
+		assert.Equal(t, tarball, resp.Body.String())
+		MakeRequest(t, NewRequest(t, "GET", tarballURL), http.StatusOK)
+		assert.Equal(t, 1, standIn.Hits("/npm/proxied-pkg/-/proxied-pkg-1.0.0.tgz"))
+
+		assertProxiedPackage(t, packages_model.TypeNpm, "proxied-pkg", 1)
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/-/package/proxied-pkg/dist-tags", user.Name))
+		resp = MakeRequest(t, req, http.StatusOK)
+		var tags map[string]string
+		DecodeJSON(t, resp, &tags)
+		assert.Equal(t, "1.0.0", tags["latest"])
+
+		t.Run("MetadataTTL", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			hits := standIn.Hits("/npm/proxied-pkg")
+
+			MakeRequest(t, NewRequest(t, "GET", root), http.StatusOK)
+			assert.Equal(t, hits, standIn.Hits("/npm/proxied-pkg"))
+
+			pu.MetadataTTL = 0
+			assert.NoError(t, packages_model.UpdateUpstream(db.DefaultContext, pu))
+
+			MakeRequest(t, NewRequest(t, "GET", root), http.StatusOK)
+			assert.Equal(t, hits+1, standIn.Hits("/npm/proxied-pkg"))
+
+			// stale metadata is served if the upstream fails
+			standIn.SetBroken(true)
+			defer standIn.SetBroken(false)
+
+			resp := MakeRequest(t, NewRequest(t, "GET", root), http.StatusOK)
+			assert.Contains(t, resp.Body.String(), tarballURL)
+			assert.Equal(t, hits+2, standIn.Hits("/npm/proxied-pkg"))
+		})
+
+		t.Run("IntegrityMismatch", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			standIn.files["/npm/broken-pkg"] = strings.ReplaceAll(document, "proxied-pkg", "broken-pkg")
+			standIn.files["/npm/broken-pkg/-/broken-pkg-1.0.0.tgz"] = "modified content"
+
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/npm/broken-pkg/-/1.0.0/broken-pkg-1.0.0.tgz", user.Name))
+			MakeRequest(t, req, http.StatusBadGateway)
+
+			_, err := packages_model.GetPackageByName(db.DefaultContext, user.ID, packages_model.TypeNpm, "broken-pkg")
+			assert.ErrorIs(t, err, packages_model.ErrPackageNotExist)
+		})
+	})
+
+	t.Run("PyPI", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		addUpstream(t, packages_model.TypePyPI, "/pypi/simple")
+
+		wheel := "wheel content"
+		hash := sha256.Sum256([]byte(wheel))
+		wheelHash := hex.EncodeToString(hash[:])
+		wheelName := "proxied_pkg-1.0.0-py3-none-any.whl"
+		standIn.files["/pypi/simple/proxied-pkg/"] = `{
+			"meta": {"api-version": "1.0"},
+			"name": "proxied-pkg",
+			"files": [
+				{"filename": "` + wheelName + `", "url": "` + server.URL + `/pypi/files/` + wheelName + `", "hashes": {"sha256": "` + wheelHash + `"}, "requires-python": ">=3.8"},
+				{"filename": "proxied-pkg-1.1.0.tar.gz", "url": "` + server.URL + `/pypi/files/proxied-pkg-1.1.0.tar.gz", "hashes": {}, "requires-python": null}
+			]
+		}`
+		standIn.files["/pypi/files/"+wheelName] = wheel
+
+		root := fmt.Sprintf("/api/packages/%s/pypi", user.Name)
+		fileURL := fmt.Sprintf("%sapi/packages/%s/pypi/files/proxied-pkg/1.0.0/%s", setting.AppURL, user.Name, wheelName)
+
+		resp := MakeRequest(t, NewRequest(t, "GET", root+"/simple/proxied-pkg"), http.StatusOK)
+		htmlDoc := NewHTMLParser(t, resp.Body)
+		links := htmlDoc.Find("a")
+		assert.Equal(t, 2, links.Length())
+		href, _ := links.First().Attr("href")
+		assert.Equal(t, fileURL+"#sha256="+wheelHash, href)
+		requiresPython, _ := links.First().Attr("data-requires-python")
+		assert.Equal(t, ">=3.8", requiresPython)
+		href, _ = links.Last().Attr("href")
+		assert.Equal(t, fmt.Sprintf("%sapi/packages/%s/pypi/files/proxied-pkg/1.1.0/proxied-pkg-1.1.0.tar.gz", setting.AppURL, user.Name), href)
+
+		resp = MakeRequest(t, NewRequest(t, "GET", fileURL), http.StatusOK)
+		assert.Equal(t, wheel, resp.Body.String())
+		MakeRequest(t, NewRequest(t, "GET", fileURL), http.StatusOK)
+		assert.Equal(t, 1, standIn.Hits("/pypi/files/"+wheelName))
+
+		MakeRequest(t, NewRequest(t, "GET", root+"/files/proxied-pkg/1.1.0/"+wheelName), http.StatusNotFound)
+
+		assertProxiedPackage(t, packages_model.TypePyPI, "proxied-pkg", 1)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		pu, err := packages_model.GetUpstreamByOwnerAndType(db.DefaultContext, user.ID, packages_model.TypePyPI)
+		assert.NoError(t, err)
+		pu.Enabled = false
+		assert.NoError(t, packages_model.UpdateUpstream(db.DefaultContext, pu))
+
+		MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/pypi/simple/other-pkg", user.Name)), http.StatusNotFound)
+		assert.Zero(t, standIn.Hits("/pypi/simple/other-pkg/"))
+	})
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageVirtualRegistry(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	member := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	privateMember := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 31})
+
+	session := loginUser(t, user.Name)
+
+	addVirtualRegistry := func(t *testing.T, packageType packages_model.Type, members string, expectedStatus int) {
+		req := NewRequestWithValues(t, "POST", "/user/settings/packages/virtual_registries/add", map[string]string{
+			"_csrf":   GetUserCSRFToken(t, session),
+			"enabled": "on",
+			"type":    string(packageType),
+			"members": members,
+			"action":  "save",
+		})
+		session.MakeRequest(t, req, expectedStatus)
+	}
+
+	t.Run("Settings", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		addVirtualRegistry(t, packages_model.TypeMaven, "user5\nnon-existing-user", http.StatusOK)
+		addVirtualRegistry(t, packages_model.TypeMaven, user.Name, http.StatusOK)
+
+		has, err := packages_model.HasOwnerVirtualRegistryForPackageType(db.DefaultContext, user.ID, packages_model.TypeMaven)
+		assert.NoError(t, err)
+		assert.False(t, has)
+
+		// the indexes of these types are signed per owner and can't be merged
+		for _, pt := range []packages_model.Type{packages_model.TypeDebian, packages_model.TypeAlpine, packages_model.TypeRpm} {
+			addVirtualRegistry(t, pt, member.Name, http.StatusOK)
+
+			has, err := packages_model.HasOwnerVirtualRegistryForPackageType(db.DefaultContext, user.ID, pt)
+			assert.NoError(t, err)
+			assert.False(t, has)
+		}
+	})
+
+	t.Run("Maven", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		addVirtualRegistry(t, packages_model.TypeMaven, fmt.Sprintf("%s, %s\n%s", member.Name, privateMember.Name, member.Name), http.StatusSeeOther)
+
+		pvr, err := packages_model.GetVirtualRegistryByOwnerAndType(db.DefaultContext, user.ID, packages_model.TypeMaven)
+		assert.NoError(t, err)
+		ids, err := packages_model.GetVirtualRegistryMemberIDs(db.DefaultContext, pvr.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{member.ID, privateMember.ID}, ids)
+
+		pom := `<?xml version="1.0"?><project><modelVersion>4.0.0</modelVersion><groupId>com.example</groupId><artifactId>lib</artifactId><version>%s</version></project>`
+		upload := func(t *testing.T, owner *user_model.User, version string) {
+			root := fmt.Sprintf("/api/packages/%s/maven/com/example/lib/%s/lib-%s", owner.Name, version, version)
+
+			req := NewRequestWithBody(t, "PUT", root+".pom", strings.NewReader(fmt.Sprintf(pom, version))).
+				AddBasicAuth(owner.Name)
+			MakeRequest(t, req, http.StatusCreated)
+			req = NewRequestWithBody(t, "PUT", root+".jar", strings.NewReader(owner.Name)).
+				AddBasicAuth(owner.Name)
+			MakeRequest(t, req, http.StatusCreated)
+		}
+
+		upload(t, user, "1.0")
+		upload(t, member, "1.0")
+		upload(t, member, "2.0")
+		upload(t, privateMember, "3.0")
+
+		root := fmt.Sprintf("/api/packages/%s/maven/com/example/lib", user.Name)
+
+		resp := MakeRequest(t, NewRequest(t, "GET", root+"/maven-metadata.xml"), http.StatusOK)
+		assert.Contains(t, resp.Body.String(), "<version>1.0</version>")
+		assert.Contains(t, resp.Body.String(), "<version>2.0</version>")
+		assert.NotContains(t, resp.Body.String(), "<version>3.0</version>")
+
+		resp = MakeRequest(t, NewRequest(t, "GET", root+"/1.0/lib-1.0.jar"), http.StatusOK)
+		assert.Equal(t, user.Name, resp.Body.String())
+		resp = MakeRequest(t, NewRequest(t, "GET", root+"/2.0/lib-2.0.jar"), http.StatusOK)
+		assert.Equal(t, member.Name, resp.Body.String())
+		MakeRequest(t, NewRequest(t, "GET", root+"/3.0/lib-3.0.jar"), http.StatusNotFound)
+
+		t.Run("PrivateMember", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			resp := MakeRequest(t, NewRequest(t, "GET", root+"/maven-metadata.xml").AddBasicAuth(privateMember.Name), http.StatusOK)
+			assert.Contains(t, resp.Body.String(), "<version>3.0</version>")
+
+			resp = MakeRequest(t, NewRequest(t, "GET", root+"/3.0/lib-3.0.jar").AddBasicAuth(privateMember.Name), http.StatusOK)
+			assert.Equal(t, privateMember.Name, resp.Body.String())
+		})
+
+		t.Run("Disabled", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithValues(t, "POST", fmt.Sprintf("/user/settings/packages/virtual_registries/%d", pvr.ID), map[string]string{
+				"_csrf":   GetUserCSRFToken(t, session),
+				"type":    string(packages_model.TypeMaven),
+				"members": member.Name,
+				"action":  "save",
+			})
+			session.MakeRequest(t, req, http.StatusSeeOther)
+
+			resp := MakeRequest(t, NewRequest(t, "GET", root+"/maven-metadata.xml"), http.StatusOK)
+			assert.NotContains(t, resp.Body.String(), "<version>2.0</version>")
+			MakeRequest(t, NewRequest(t, "GET", root+"/2.0/lib-2.0.jar"), http.StatusNotFound)
+
+			// the member packages are still available at their owner
+			MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("/api/packages/%s/maven/com/example/lib/2.0/lib-2.0.jar", member.Name)), http.StatusOK)
+		})
+	})
+
+	t.Run("Npm", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		addVirtualRegistry(t, packages_model.TypeNpm, member.Name, http.StatusSeeOther)
+
+		packageName := "@scope/test-package"
+		data := "H4sIAAAAAAAA/ytITM5OTE/VL4DQelnF+XkMVAYGBgZmJiYK2MRBwNDcSIHB2NTMwNDQzMwAqA7IMDUxA9LUdgg2UFpcklgEdAql5kD8ogCnhwio5lJQUMpLzE1VslJQcihOzi9I1S9JLS7RhSYIJR2QgrLUouLM/DyQGkM9Az1D3YIiqExKanFyUWZBCVQ2BKhVwQVJDKwosbQkI78IJO/tZ+LsbRykxFXLNdA+HwWjYBSMgpENACgAbtAACAAA"
+		upload := func(t *testing.T, owner *user_model.User, version string) {
+			body := `{
+				"_id": "` + packageName + `",
+				"name": "` + packageName + `",
+				"dist-tags": {"latest": "` + version + `"},
+				"versions": {
+					"` + version + `": {
+						"name": "` + packageName + `",
+						"version": "` + version + `",
+						"dist": {
+							"integrity": "sha512-yA4FJsVhetynGfOC1jFf79BuS+jrHbm0fhh+aHzCQkOaOBXKf9oBnC4a6DnLLnEsHQDRLYd00cwj8sCXpC+wIg==",
+							"shasum": "aaa7eaf852a948b0aa05afeda35b1badca155d90"
+						}
+					}
+				},
+				"_attachments": {
+					"` + packageName + `-` + version + `.tgz": {"data": "` + data + `"}
+				}
+			}`
+			req := NewRequestWithBody(t, "PUT", fmt.Sprintf("/api/packages/%s/npm/%s", owner.Name, url.QueryEscape(packageName)), strings.NewReader(body)).
+				AddBasicAuth(owner.Name)
+			MakeRequest(t, req, http.StatusCreated)
+		}
+
+		upload(t, user, "1.0.0")
+		upload(t, member, "2.0.0")
+
+		registryURL := fmt.Sprintf("/api/packages/%s/npm", user.Name)
+
+		resp := MakeRequest(t, NewRequest(t, "GET", registryURL+"/"+url.QueryEscape(packageName)), http.StatusOK)
+
+		var result npm.PackageMetadata
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Len(t, result.Versions, 2)
+		// the tag of the owner takes precedence over the tag of the member
+		assert.Equal(t, "1.0.0", result.DistTags["latest"])
+		assert.Equal(t, fmt.Sprintf("%s%s/%s/-/2.0.0/test-package-2.0.0.tgz", setting.AppURL, registryURL[1:], url.QueryEscape(packageName)), result.Versions["2.0.0"].Dist.Tarball)
+
+		MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("%s/%s/-/2.0.0/test-package-2.0.0.tgz", registryURL, packageName)), http.StatusOK)
+		MakeRequest(t, NewRequest(t, "GET", fmt.Sprintf("%s/%s/-/3.0.0/test-package-3.0.0.tgz", registryURL, packageName)), http.StatusNotFound)
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/unittest"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPushRule(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteOrganization)
+
+	for _, urlStr := range []string{"/api/v1/repos/user2/repo1/push_rule", "/api/v1/orgs/org3/push_rule"} {
+		req := NewRequest(t, "GET", urlStr).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.UpdatePushRuleOption{CommitMessagePattern: "("}).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.UpdatePushRuleOption{
+			CommitMessagePattern:  `^[A-Z]+-\d+`,
+			MaxFileSize:           1024,
+			ForbiddenFilePatterns: "**.exe",
+		}).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var rule api.PushRule
+		DecodeJSON(t, resp, &rule)
+		assert.Equal(t, `^[A-Z]+-\d+`, rule.CommitMessagePattern)
+		assert.EqualValues(t, 1024, rule.MaxFileSize)
+
+		req = NewRequestWithJSON(t, "PUT", urlStr, &api.UpdatePushRuleOption{RequireVerifiedAuthorEmail: true}).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &rule)
+		assert.Empty(t, rule.CommitMessagePattern)
+		assert.True(t, rule.RequireVerifiedAuthorEmail)
+
+		req = NewRequest(t, "GET", urlStr).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &rule)
+		assert.True(t, rule.RequireVerifiedAuthorEmail)
+
+		req = NewRequest(t, "DELETE", urlStr).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "DELETE", urlStr).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	}
+
+	// only the repository admins can manage the push rule of a repository
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+	req := NewRequestWithJSON(t, "PUT", "/api/v1/repos/user2/repo1/push_rule", &api.UpdatePushRuleOption{}).AddTokenAuth(token)
+	MakeRequest(t, req, http.StatusForbidden)
+	unittest.AssertNotExistsBean(t, &git_model.PushRule{RepoID: 1})
+}
+
+func TestPushRuleGitPush(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		ctx := NewAPITestContext(t, "user2", "repo-push-rule", auth_model.AccessTokenScopeWriteRepository)
+		t.Run("CreateRepo", doAPICreateRepository(ctx, false))
+
+		dstPath := t.TempDir()
+		u.Path = ctx.GitPath()
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		setPushRule := func(t *testing.T, opts *api.UpdatePushRuleOption) {
+			req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/user2/%s/push_rule", ctx.Reponame), opts).AddTokenAuth(ctx.Token)
+			MakeRequest(t, req, http.StatusOK)
+		}
+
+		_, err := generateCommitWithNewData(littleSize, dstPath, "user2@example.com", "User Two", "push-rule-")
+		assert.NoError(t, err)
+
+		setPushRule(t, &api.UpdatePushRuleOption{MaxFileSize: littleSize / 2})
+		t.Run("PushTooLargeFile", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+
+		setPushRule(t, &api.UpdatePushRuleOption{CommitMessagePattern: `^[A-Z]+-\d+`})
+		t.Run("PushInvalidCommitMessage", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+
+		setPushRule(t, &api.UpdatePushRuleOption{ForbiddenFilePatterns: "push-rule-*"})
+		t.Run("PushForbiddenFile", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+
+		setPushRule(t, &api.UpdatePushRuleOption{MaxFileSize: littleSize, RequireVerifiedAuthorEmail: true})
+		t.Run("Push", doGitPushTestRepository(dstPath, "origin", "master"))
+	})
+}
@@ -0,0 +1,262 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	quota_model "code.gitea.io/gitea/models/quota"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIQuota(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	defer test.MockVariableValue(&setting.Quota.Enabled, true)()
+	defer test.MockVariableValue(&setting.LFS.StartServer, true)()
+
+	adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+
+	t.Run("Rules", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		newCreateRequest := func() *RequestWrapper {
+			return NewRequestWithJSON(t, "POST", "/api/v1/admin/quota/rules", &api.CreateQuotaRuleOption{
+				Name:     "assets",
+				Limit:    1,
+				Subjects: []string{"size:assets:all"},
+			}).AddTokenAuth(adminToken)
+		}
+		resp := MakeRequest(t, newCreateRequest(), http.StatusCreated)
+
+		var rule *api.QuotaRuleInfo
+		DecodeJSON(t, resp, &rule)
+		assert.Equal(t, "assets", rule.Name)
+		assert.EqualValues(t, 1, rule.Limit)
+		assert.Equal(t, []string{"size:assets:all"}, rule.Subjects)
+
+		MakeRequest(t, newCreateRequest(), http.StatusConflict)
+
+		req := NewRequestWithJSON(t, "POST", "/api/v1/admin/quota/rules", &api.CreateQuotaRuleOption{
+			Name:     "invalid",
+			Subjects: []string{"size:unknown"},
+		}).AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/admin/quota/rules", &api.CreateQuotaRuleOption{
+			Name:     "lfs",
+			Limit:    0,
+			Subjects: []string{"size:git:lfs"},
+		}).AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusCreated)
+
+		req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/quota/rules/assets", &api.EditQuotaRuleOption{
+			Limit: util.ToPointer[int64](2),
+		}).AddTokenAuth(adminToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &rule)
+		assert.EqualValues(t, 2, rule.Limit)
+		assert.Equal(t, []string{"size:assets:all"}, rule.Subjects)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/quota/rules").AddTokenAuth(adminToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var rules []*api.QuotaRuleInfo
+		DecodeJSON(t, resp, &rules)
+		assert.Len(t, rules, 2)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/quota/rules/unknown").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("Groups", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		newCreateRequest := func() *RequestWrapper {
+			return NewRequestWithJSON(t, "POST", "/api/v1/admin/quota/groups", &api.CreateQuotaGroupOption{
+				Name: "limited",
+			}).AddTokenAuth(adminToken)
+		}
+		MakeRequest(t, newCreateRequest(), http.StatusCreated)
+		MakeRequest(t, newCreateRequest(), http.StatusConflict)
+
+		req := NewRequest(t, "PUT", "/api/v1/admin/quota/groups/limited/rules/assets").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+		MakeRequest(t, req, http.StatusConflict)
+		req = NewRequest(t, "PUT", "/api/v1/admin/quota/groups/limited/rules/lfs").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "PUT", "/api/v1/admin/quota/groups/limited/users/user2").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+		MakeRequest(t, req, http.StatusConflict)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/quota/groups/limited/users").AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var users []*api.User
+		DecodeJSON(t, resp, &users)
+		assert.Len(t, users, 1)
+		assert.Equal(t, "user2", users[0].UserName)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/quota/groups/limited").AddTokenAuth(adminToken)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var group *api.QuotaGroup
+		DecodeJSON(t, resp, &group)
+		assert.Equal(t, "limited", group.Name)
+		assert.Len(t, group.Rules, 2)
+	})
+
+	t.Run("UserQuota", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", "/api/v1/admin/users/user2/quota").AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var info *api.QuotaInfo
+		DecodeJSON(t, resp, &info)
+		assert.Len(t, info.Groups, 1)
+		assert.Equal(t, "limited", info.Groups[0].Name)
+		assert.NotNil(t, info.Used)
+
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeReadUser, auth_model.AccessTokenScopeReadOrganization)
+		req = NewRequest(t, "GET", "/api/v1/user/quota").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &info)
+		assert.Len(t, info.Groups, 1)
+
+		req = NewRequest(t, "GET", "/api/v1/orgs/org3/quota").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &info)
+		assert.Empty(t, info.Groups)
+
+		token = getUserToken(t, "user4", auth_model.AccessTokenScopeReadOrganization)
+		req = NewRequest(t, "GET", "/api/v1/orgs/org3/quota").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("Enforcement", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+		issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{RepoID: repo.ID})
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteIssue, auth_model.AccessTokenScopeWritePackage)
+
+		t.Run("Attachment", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("attachment", "image.png")
+			assert.NoError(t, err)
+			buff := generateImg()
+			_, err = io.Copy(part, &buff)
+			assert.NoError(t, err)
+			assert.NoError(t, writer.Close())
+
+			req := NewRequestWithBody(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/issues/%d/assets", issue.Index), body).
+				AddTokenAuth(token)
+			req.Header.Add("Content-Type", writer.FormDataContentType())
+			MakeRequest(t, req, http.StatusRequestEntityTooLarge)
+		})
+
+		t.Run("Package", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithBody(t, "PUT", "/api/packages/user2/generic/quota-test/1.0.0/file.bin", bytes.NewReader([]byte("content"))).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusForbidden)
+
+			// the rule accepts uploads which fit into the limit
+			req = NewRequestWithBody(t, "PUT", "/api/packages/user2/generic/quota-test/1.0.0/file.bin", bytes.NewReader([]byte("ab"))).
+				AddTokenAuth(token)
+			MakeRequest(t, req, http.StatusCreated)
+		})
+
+		t.Run("LFS", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+
+			req := NewRequestWithJSON(t, "POST", "/user2/repo1.git/info/lfs/objects/batch", &lfs.BatchRequest{
+				Operation: "upload",
+				Objects: []lfs.Pointer{
+					{Oid: "fb8f7d8435968c4f82a726a92395be4d16f2f63116caf36c8ad35c60831ab042", Size: 6},
+				},
+			}).
+				SetHeader("Accept", lfs.AcceptHeader).
+				SetHeader("Content-Type", lfs.MediaType)
+			session.MakeRequest(t, req, http.StatusRequestEntityTooLarge)
+		})
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "DELETE", "/api/v1/admin/quota/groups/limited/users/user2").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "DELETE", "/api/v1/admin/quota/groups/limited/rules/lfs").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequest(t, "DELETE", "/api/v1/admin/quota/rules/assets").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/quota/groups/limited").AddTokenAuth(adminToken)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var group *api.QuotaGroup
+		DecodeJSON(t, resp, &group)
+		assert.Empty(t, group.Rules)
+
+		req = NewRequest(t, "DELETE", "/api/v1/admin/quota/groups/limited").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNoContent)
+		req = NewRequest(t, "GET", "/api/v1/admin/quota/groups/limited").AddTokenAuth(adminToken)
+		MakeRequest(t, req, http.StatusNotFound)
+
+		unittest.AssertNotExistsBean(t, &quota_model.GroupMapping{UserID: 2})
+	})
+}
+
+func TestQuotaGitPush(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		defer test.MockVariableValue(&setting.Quota.Enabled, true)()
+
+		rule := &quota_model.Rule{Name: "git", Limit: 0, Subjects: []quota_model.LimitSubject{quota_model.LimitSubjectSizeGitAll}}
+		assert.NoError(t, quota_model.CreateRule(db.DefaultContext, rule))
+		group := &quota_model.Group{Name: "git"}
+		assert.NoError(t, quota_model.CreateGroup(db.DefaultContext, group))
+		assert.NoError(t, quota_model.AddRuleToGroup(db.DefaultContext, group.ID, rule.ID))
+
+		ctx := NewAPITestContext(t, "user2", "repo-quota", auth_model.AccessTokenScopeWriteRepository, auth_model.AccessTokenScopeWriteUser)
+		t.Run("CreateRepo", doAPICreateRepository(ctx, false))
+
+		dstPath := t.TempDir()
+		u.Path = ctx.GitPath()
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		_, err := generateCommitWithNewData(littleSize, dstPath, "user2@example.com", "User Two", "quota-")
+		assert.NoError(t, err)
+
+		assert.NoError(t, quota_model.AddUserToGroup(db.DefaultContext, group.ID, 2))
+		t.Run("PushOverQuota", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+
+		assert.NoError(t, quota_model.RemoveUserFromGroup(db.DefaultContext, group.ID, 2))
+		t.Run("Push", doGitPushTestRepository(dstPath, "origin", "master"))
+	})
+}
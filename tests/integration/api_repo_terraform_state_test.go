@@ -0,0 +1,211 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	repo_model "code.gitea.io/gitea/models/repo"
+	terraform_model "code.gitea.io/gitea/models/terraform"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoTerraformState(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1, OwnerID: user.ID})
+
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+
+	stateURL := fmt.Sprintf("/api/v1/repos/%s/%s/terraform/state/production", user.Name, repo.Name)
+	lockURL := stateURL + "/lock"
+
+	stateContent := func(serial int64) string {
+		return fmt.Sprintf(`{"version":4,"terraform_version":"1.9.0","serial":%d,"lineage":"3f7a2b1c","outputs":{},"resources":[]}`, serial)
+	}
+	lockInfo := func(id string) string {
+		return fmt.Sprintf(`{"ID":"%s","Operation":"OperationTypeApply","Info":"","Who":"user@host","Version":"1.9.0","Created":"2024-10-01T12:00:00Z","Path":""}`, id)
+	}
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		MakeRequest(t, NewRequest(t, "GET", stateURL), http.StatusUnauthorized)
+
+		// the state may contain secrets, so readers of the repository can't access it
+		readerToken := getUserToken(t, "user4", auth_model.AccessTokenScopeWriteRepository)
+		req := NewRequest(t, "GET", stateURL).AddTokenAuth(readerToken)
+		MakeRequest(t, req, http.StatusForbidden)
+
+		readOnlyToken := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeReadRepository)
+		req = NewRequestWithBody(t, "POST", stateURL, strings.NewReader(stateContent(1))).AddTokenAuth(readOnlyToken)
+		MakeRequest(t, req, http.StatusForbidden)
+		req = NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader(lockInfo("lock-1"))).AddTokenAuth(readOnlyToken)
+		MakeRequest(t, req, http.StatusForbidden)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", stateURL).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+	})
+
+	t.Run("Lock", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader(lockInfo("lock-1"))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		// locking again with the same lock succeeds
+		req = NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader(lockInfo("lock-1"))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader(lockInfo("lock-2"))).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusLocked)
+		assert.JSONEq(t, lockInfo("lock-1"), resp.Body.String())
+
+		req = NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader("invalid")).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusBadRequest)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "POST", stateURL, strings.NewReader(stateContent(1))).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusLocked)
+		assert.JSONEq(t, lockInfo("lock-1"), resp.Body.String())
+
+		req = NewRequestWithBody(t, "POST", stateURL+"?ID=lock-2", strings.NewReader(stateContent(1))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusLocked)
+
+		req = NewRequestWithBody(t, "POST", stateURL+"?ID=lock-1", strings.NewReader("invalid")).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		req = NewRequestWithBody(t, "POST", stateURL+"?ID=lock-1", strings.NewReader(stateContent(1))).
+			AddTokenAuth(token).
+			SetHeader("Content-MD5", base64.StdEncoding.EncodeToString([]byte("0123456789abcdef")))
+		MakeRequest(t, req, http.StatusBadRequest)
+
+		for _, serial := range []int64{1, 2, 2} {
+			sum := md5.Sum([]byte(stateContent(serial)))
+			req = NewRequestWithBody(t, "POST", stateURL+"?ID=lock-1", strings.NewReader(stateContent(serial))).
+				AddTokenAuth(token).
+				SetHeader("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+			MakeRequest(t, req, http.StatusOK)
+		}
+
+		s := unittest.AssertExistsAndLoadBean(t, &terraform_model.TerraformState{RepoID: repo.ID, Name: "production"})
+		assert.EqualValues(t, 2, s.Serial)
+		assert.Equal(t, "3f7a2b1c", s.Lineage)
+		assert.EqualValues(t, len(stateContent(2)), s.Size)
+		unittest.AssertCount(t, &terraform_model.TerraformStateVersion{StateID: s.ID}, 2)
+
+		req = NewRequest(t, "GET", stateURL).AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, stateContent(2), resp.Body.String())
+	})
+
+	t.Run("Unlock", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "UNLOCK", lockURL, strings.NewReader(lockInfo("lock-2"))).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusConflict)
+		assert.JSONEq(t, lockInfo("lock-1"), resp.Body.String())
+
+		req = NewRequestWithBody(t, "UNLOCK", lockURL, strings.NewReader(lockInfo("lock-1"))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequestWithBody(t, "POST", stateURL, strings.NewReader(stateContent(3))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		// an empty body unlocks the state regardless of the current lock
+		req = NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader(lockInfo("lock-3"))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+		req = NewRequest(t, "DELETE", lockURL).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		s := unittest.AssertExistsAndLoadBean(t, &terraform_model.TerraformState{RepoID: repo.ID, Name: "production"})
+		assert.False(t, s.IsLocked())
+	})
+
+	t.Run("List", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/terraform/state", user.Name, repo.Name)).AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var states []*api.TerraformState
+		DecodeJSON(t, resp, &states)
+		assert.Len(t, states, 1)
+		assert.Equal(t, "production", states[0].Name)
+		assert.EqualValues(t, 3, states[0].Serial)
+		assert.Nil(t, states[0].Lock)
+	})
+
+	t.Run("Versions", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequest(t, "GET", stateURL+"/versions").AddTokenAuth(token)
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var versions []*api.TerraformStateVersion
+		DecodeJSON(t, resp, &versions)
+		assert.Len(t, versions, 3)
+		assert.EqualValues(t, 3, versions[0].Serial)
+		assert.EqualValues(t, 1, versions[2].Serial)
+		assert.Equal(t, user.Name, versions[0].Creator.UserName)
+
+		req = NewRequest(t, "GET", stateURL+"/versions/1").AddTokenAuth(token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		assert.Equal(t, stateContent(1), resp.Body.String())
+
+		req = NewRequest(t, "GET", stateURL+"/versions/42").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNotFound)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		defer test.MockVariableValue(&setting.Terraform.MaxStateSize, 10)()
+
+		req := NewRequestWithBody(t, "POST", stateURL, bytes.NewReader([]byte(stateContent(4)))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithBody(t, "LOCK", lockURL, strings.NewReader(lockInfo("lock-4"))).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		req = NewRequest(t, "DELETE", stateURL).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusLocked)
+
+		req = NewRequest(t, "DELETE", stateURL+"?ID=lock-4").AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusOK)
+
+		unittest.AssertNotExistsBean(t, &terraform_model.TerraformState{RepoID: repo.ID, Name: "production"})
+		unittest.AssertNotExistsBean(t, &terraform_model.TerraformStateVersion{RepoID: repo.ID})
+
+		req = NewRequest(t, "GET", stateURL).AddTokenAuth(token)
+		MakeRequest(t, req, http.StatusNoContent)
+	})
+}
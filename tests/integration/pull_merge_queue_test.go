@@ -0,0 +1,185 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitrepo"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/mergequeue"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullMergeQueue(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "merge-queue",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		req := NewRequestWithValues(t, "POST", "/user2/merge-queue/settings/branches/edit", map[string]string{
+			"_csrf":                 GetUserCSRFToken(t, session),
+			"rule_name":             "master",
+			"enable_status_check":   "true",
+			"status_check_contexts": "ci/test",
+			"enable_merge_queue":    "true",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+
+		// every pull request adds its own file, so their merge groups can be stacked
+		prs := make([]*issues_model.PullRequest, 0, 3)
+		for i := 1; i <= 3; i++ {
+			branch := fmt.Sprintf("feature-%d", i)
+			_, err := files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				OldBranch: repo.DefaultBranch,
+				NewBranch: branch,
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     "create",
+						TreePath:      branch + ".txt",
+						ContentReader: strings.NewReader(branch + "\n"),
+					},
+				},
+			})
+			require.NoError(t, err)
+			testPullCreate(t, session, "user2", "merge-queue", false, repo.DefaultBranch, branch, "Add "+branch)
+			prs = append(prs, unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{BaseRepoID: repo.ID, HeadBranch: branch}))
+		}
+
+		// getEntries returns the merge queue entries of the pull requests once all their merge groups are stacked on the base branch
+		getEntries := func(t *testing.T, prs ...*issues_model.PullRequest) []*pull_model.MergeQueueEntry {
+			var entries []*pull_model.MergeQueueEntry
+			assert.Eventually(t, func() bool {
+				baseCommitID, err := git.GetFullCommitID(db.DefaultContext, repo.RepoPath(), repo.DefaultBranch)
+				if err != nil {
+					return false
+				}
+				entries, err = pull_model.GetMergeQueueEntries(db.DefaultContext, repo.ID, repo.DefaultBranch)
+				if err != nil || len(entries) != len(prs) {
+					return false
+				}
+				for i, entry := range entries {
+					if entry.PullID != prs[i].ID || entry.GroupCommitID == "" || entry.BaseCommitID != baseCommitID {
+						return false
+					}
+					baseCommitID = entry.GroupCommitID
+				}
+				return true
+			}, 10*time.Second, 100*time.Millisecond)
+			require.Len(t, entries, len(prs))
+			return entries
+		}
+
+		t.Run("Queue", func(t *testing.T) {
+			for _, pr := range prs {
+				testPullMerge(t, session, "user2", "merge-queue", fmt.Sprint(pr.Index), repo_model.MergeStyleMerge, false)
+			}
+			getEntries(t, prs...)
+			for _, pr := range prs {
+				pr = unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: pr.ID})
+				assert.False(t, pr.HasMerged)
+				unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: pr.IssueID, Type: issues_model.CommentTypePRAddedToMergeQueue})
+			}
+		})
+
+		t.Run("RemoveWithoutPermission", func(t *testing.T) {
+			readerSession := loginUser(t, "user4")
+			req := NewRequestWithValues(t, "POST", fmt.Sprintf("/user2/merge-queue/pulls/%d/remove_from_merge_queue", prs[0].Index), map[string]string{
+				"_csrf": GetUserCSRFToken(t, readerSession),
+			})
+			readerSession.MakeRequest(t, req, http.StatusForbidden)
+			unittest.AssertExistsAndLoadBean(t, &pull_model.MergeQueueEntry{PullID: prs[0].ID})
+		})
+
+		t.Run("PushOtherCommit", func(t *testing.T) {
+			entries := getEntries(t, prs...)
+			// the merge queue can only push the recorded merge group commit, even for the repository admin
+			err := pull_service.MergeMergeGroup(db.DefaultContext, user2, prs[:1], entries[0].HeadCommitID)
+			assert.True(t, git.IsErrPushRejected(err), "unexpected error: %v", err)
+
+			baseCommitID, err := git.GetFullCommitID(db.DefaultContext, repo.RepoPath(), repo.DefaultBranch)
+			require.NoError(t, err)
+			assert.Equal(t, entries[0].BaseCommitID, baseCommitID)
+			unittest.AssertExistsAndLoadBean(t, &pull_model.MergeQueueEntry{PullID: prs[0].ID})
+		})
+
+		t.Run("FailingGroup", func(t *testing.T) {
+			entries := getEntries(t, prs...)
+			err := commitstatus_service.CreateCommitStatus(db.DefaultContext, repo, user2, entries[2].GroupCommitID, &git_model.CommitStatus{
+				State:   api.CommitStatusFailure,
+				Context: "ci/test",
+			})
+			require.NoError(t, err)
+
+			// the pull requests queued before it stay in the queue
+			getEntries(t, prs[:2]...)
+			unittest.AssertNotExistsBean(t, &pull_model.MergeQueueEntry{PullID: prs[2].ID})
+			unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{
+				IssueID: prs[2].IssueID,
+				Type:    issues_model.CommentTypePRRemovedFromMergeQueue,
+				Content: mergequeue.ReasonChecksFailed,
+			})
+			pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: prs[2].ID})
+			assert.False(t, pr.HasMerged)
+		})
+
+		t.Run("MergeGroup", func(t *testing.T) {
+			entries := getEntries(t, prs[:2]...)
+			// the merge group of the second pull request contains the first one, so both are merged by one fast-forward
+			err := commitstatus_service.CreateCommitStatus(db.DefaultContext, repo, user2, entries[1].GroupCommitID, &git_model.CommitStatus{
+				State:   api.CommitStatusSuccess,
+				Context: "ci/test",
+			})
+			require.NoError(t, err)
+
+			assert.Eventually(t, func() bool {
+				pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: prs[1].ID})
+				return pr.HasMerged
+			}, 10*time.Second, 100*time.Millisecond)
+
+			gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, repo)
+			require.NoError(t, err)
+			defer gitRepo.Close()
+			baseCommitID, err := gitRepo.GetBranchCommitID(repo.DefaultBranch)
+			require.NoError(t, err)
+			assert.Equal(t, entries[1].GroupCommitID, baseCommitID)
+
+			for i, entry := range entries {
+				pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: prs[i].ID})
+				assert.True(t, pr.HasMerged)
+				assert.Equal(t, entry.GroupCommitID, pr.MergedCommitID)
+				assert.EqualValues(t, user2.ID, pr.MergerID)
+				unittest.AssertNotExistsBean(t, &pull_model.MergeQueueEntry{PullID: pr.ID})
+
+				assert.False(t, gitRepo.IsReferenceExist(pull_service.GetMergeGroupRefName(pr)))
+			}
+		})
+	})
+}
@@ -36,6 +36,7 @@ import (
 	"code.gitea.io/gitea/modules/test"
 	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/services/automerge"
+	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	commitstatus_service "code.gitea.io/gitea/services/repository/commitstatus"
@@ -976,3 +977,201 @@ func TestPullAutoMergeAfterCommitStatusSucceedAndApprovalForAgitFlow(t *testing.
 		unittest.AssertNotExistsBean(t, &pull_model.AutoMerge{PullID: pr.ID})
 	})
 }
+
+func TestPullMergeCodeOwnerApproval(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, user2, repo_service.CreateRepoOptions{
+			Name:             "test_codeowner_approval",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		assert.NoError(t, err)
+
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			OldBranch: repo.DefaultBranch,
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      "CODEOWNERS",
+					ContentReader: strings.NewReader("README.md @user5\n"),
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			NewBranch: "codeowner-approval",
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "update",
+					TreePath:      "README.md",
+					ContentReader: strings.NewReader("# Code owner approval\n"),
+				},
+			},
+		})
+		assert.NoError(t, err)
+
+		session := loginUser(t, "user2")
+		testPullCreate(t, session, "user2", repo.Name, false, repo.DefaultBranch, "codeowner-approval", "Code owner approval")
+		pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{BaseRepoID: repo.ID, HeadBranch: "codeowner-approval"})
+
+		req := NewRequestWithValues(t, "POST", "/user2/test_codeowner_approval/settings/branches/edit", map[string]string{
+			"_csrf":                       GetUserCSRFToken(t, session),
+			"rule_name":                   "master",
+			"require_code_owner_approval": "on",
+		})
+		session.MakeRequest(t, req, http.StatusSeeOther)
+
+		token := getTokenForLoggedInUser(t, session, auth_model.AccessTokenScopeWriteRepository)
+		prURL := fmt.Sprintf("/api/v1/repos/user2/%s/pulls/%d", repo.Name, pr.Index)
+
+		getCodeOwners := func(t *testing.T) *api.PullRequestCodeOwners {
+			req := NewRequest(t, "GET", prURL+"/codeowners").AddTokenAuth(token)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var codeOwners *api.PullRequestCodeOwners
+			DecodeJSON(t, resp, &codeOwners)
+			return codeOwners
+		}
+
+		getHeadCommitID := func(t *testing.T) string {
+			gitRepo, err := gitrepo.OpenRepository(db.DefaultContext, repo)
+			assert.NoError(t, err)
+			defer gitRepo.Close()
+			commitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+			assert.NoError(t, err)
+			return commitID
+		}
+
+		pushFile := func(t *testing.T, operation, treePath, content string) {
+			resp, err := files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+				OldBranch: "codeowner-approval",
+				Files: []*files_service.ChangeRepoFile{
+					{
+						Operation:     operation,
+						TreePath:      treePath,
+						ContentReader: strings.NewReader(content),
+					},
+				},
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Eventually(t, func() bool {
+				return getHeadCommitID(t) == resp.Commit.SHA
+			}, 10*time.Second, 100*time.Millisecond)
+		}
+
+		approve := func(t *testing.T) {
+			session := loginUser(t, "user5")
+			req := NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls/%d", repo.Name, pr.Index))
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			testSubmitReview(t, session, htmlDoc.GetCSRF(), "user2", repo.Name, strconv.FormatInt(pr.Index, 10), getHeadCommitID(t), "approve", http.StatusOK)
+		}
+
+		merge := func(t *testing.T, expectedStatus int) *httptest.ResponseRecorder {
+			assert.NoError(t, queue.GetManager().FlushAll(context.Background(), 5*time.Second))
+			req := NewRequestWithJSON(t, http.MethodPost, prURL+"/merge", &forms.MergePullRequestForm{
+				Do: string(repo_model.MergeStyleMerge),
+			}).AddTokenAuth(token)
+			return MakeRequest(t, req, expectedStatus)
+		}
+
+		t.Run("NotApproved", func(t *testing.T) {
+			codeOwners := getCodeOwners(t)
+			assert.True(t, codeOwners.Required)
+			assert.False(t, codeOwners.Approved)
+			if assert.Len(t, codeOwners.Paths, 1) {
+				assert.Equal(t, "README.md", codeOwners.Paths[0].Path)
+				assert.False(t, codeOwners.Paths[0].Approved)
+				if assert.Len(t, codeOwners.Paths[0].Owners, 1) {
+					assert.Equal(t, "user5", codeOwners.Paths[0].Owners[0].User.UserName)
+					assert.False(t, codeOwners.Paths[0].Owners[0].Approved)
+				}
+			}
+
+			req := NewRequest(t, "GET", fmt.Sprintf("/user2/%s/pulls/%d", repo.Name, pr.Index))
+			resp := session.MakeRequest(t, req, http.StatusOK)
+			htmlDoc := NewHTMLParser(t, resp.Body)
+			assert.Equal(t, 1, htmlDoc.doc.Find(".code-owners-approvals li").Length())
+
+			resp = merge(t, http.StatusMethodNotAllowed)
+			var apiError api.APIError
+			DecodeJSON(t, resp, &apiError)
+			assert.Contains(t, apiError.Message, "Not all code owners have approved")
+		})
+
+		t.Run("Approved", func(t *testing.T) {
+			approve(t)
+
+			codeOwners := getCodeOwners(t)
+			assert.True(t, codeOwners.Approved)
+			assert.True(t, codeOwners.Paths[0].Owners[0].Approved)
+		})
+
+		t.Run("OwnedPathChanged", func(t *testing.T) {
+			pushFile(t, "update", "README.md", "# Code owner approval changed\n")
+
+			codeOwners := getCodeOwners(t)
+			assert.False(t, codeOwners.Approved)
+
+			approve(t)
+
+			assert.True(t, getCodeOwners(t).Approved)
+		})
+
+		t.Run("UnownedPathChanged", func(t *testing.T) {
+			pushFile(t, "create", "other.txt", "not owned\n")
+
+			assert.True(t, getCodeOwners(t).Approved)
+
+			merge(t, http.StatusOK)
+		})
+	})
+}
+
+func TestPullMergeRequireLinearHistory(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		ctx := NewAPITestContext(t, "user2", "repo-linear-history", auth_model.AccessTokenScopeWriteRepository)
+		t.Run("CreateRepo", doAPICreateRepository(ctx, false))
+
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/%s/branch_protections", ctx.Reponame), &api.CreateBranchProtectionOption{
+			RuleName:             "master",
+			EnablePush:           true,
+			RequireLinearHistory: true,
+		}).AddTokenAuth(ctx.Token)
+		resp := MakeRequest(t, req, http.StatusCreated)
+		var protection api.BranchProtection
+		DecodeJSON(t, resp, &protection)
+		assert.True(t, protection.RequireLinearHistory)
+
+		dstPath := t.TempDir()
+		u.Path = ctx.GitPath()
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		t.Run("CreateFeatureBranch", doGitCreateBranch(dstPath, "feature"))
+		t.Run("AddCommitsToFeature", doGitAddSomeCommits(dstPath, "feature"))
+		t.Run("PushFeature", doGitPushTestRepository(dstPath, "origin", "feature"))
+
+		t.Run("AddCommitsToMaster", doGitAddSomeCommits(dstPath, "master"))
+		t.Run("MergeFeature", doGitMerge(dstPath, "--no-ff", "-m", "Merge feature", "feature"))
+		t.Run("PushMergeCommit", doGitPushTestRepositoryFail(dstPath, "origin", "master"))
+
+		pr, err := doAPICreatePullRequest(ctx, "user2", ctx.Reponame, "master", "feature")(t)
+		assert.NoError(t, err)
+
+		mergeURL := fmt.Sprintf("/api/v1/repos/user2/%s/pulls/%d/merge", ctx.Reponame, pr.Index)
+		for _, style := range []repo_model.MergeStyle{repo_model.MergeStyleMerge, repo_model.MergeStyleRebaseMerge} {
+			req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{Do: string(style)}).AddTokenAuth(ctx.Token)
+			MakeRequest(t, req, http.StatusMethodNotAllowed)
+		}
+
+		req = NewRequestWithJSON(t, "POST", mergeURL, &forms.MergePullRequestForm{Do: string(repo_model.MergeStyleRebase)}).AddTokenAuth(ctx.Token)
+		MakeRequest(t, req, http.StatusOK)
+	})
+}
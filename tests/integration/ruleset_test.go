@@ -0,0 +1,295 @@
+// Copyright 2024 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/forms"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesetGitPush(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		ctx := NewAPITestContext(t, "user2", "ruleset-push", auth_model.AccessTokenScopeWriteRepository)
+		t.Run("CreateRepo", doAPICreateRepository(ctx, false))
+
+		dstPath := t.TempDir()
+		u.Path = ctx.GitPath()
+		u.User = url.UserPassword("user2", userPassword)
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		repo, err := repo_model.GetRepositoryByOwnerAndName(db.DefaultContext, "user2", "ruleset-push")
+		require.NoError(t, err)
+
+		commitCount := 0
+		doCommit := func(t *testing.T) {
+			commitCount++
+			content := fmt.Sprintf("commit %d\n", commitCount)
+			require.NoError(t, os.WriteFile(filepath.Join(dstPath, "ruleset.txt"), []byte(content), 0o644))
+			require.NoError(t, git.AddChanges(dstPath, true))
+			signature := git.Signature{Email: "user2@example.com", Name: "User Two"}
+			require.NoError(t, git.CommitChanges(dstPath, git.CommitChangesOptions{
+				Committer: &signature,
+				Author:    &signature,
+				Message:   content,
+			}))
+		}
+		// rewriteLastCommit replaces the last commit, so pushing it is a force push
+		rewriteLastCommit := func(t *testing.T) {
+			_, _, err := git.NewCommand(git.DefaultContext, "reset", "--hard", "HEAD~1").RunStdString(&git.RunOpts{Dir: dstPath})
+			require.NoError(t, err)
+			doCommit(t)
+		}
+		getMasterCommitID := func(t *testing.T) string {
+			commitID, err := git.GetFullCommitID(db.DefaultContext, repo.RepoPath(), "master")
+			require.NoError(t, err)
+			return commitID
+		}
+
+		adminToken := getUserToken(t, "user1", auth_model.AccessTokenScopeWriteAdmin)
+
+		var ruleset api.Ruleset
+		t.Run("AdminAPI", func(t *testing.T) {
+			option := &api.CreateRulesetOption{
+				Name:             "no-force-push",
+				Target:           "branch",
+				RepoNamePatterns: []string{"user2/ruleset-push"},
+				RefPatterns:      []string{"master"},
+				BlockForcePush:   true,
+			}
+
+			// only site admins are allowed to manage instance level rulesets
+			req := NewRequestWithJSON(t, "POST", "/api/v1/admin/rulesets", option).
+				AddTokenAuth(getUserToken(t, "user2", auth_model.AccessTokenScopeWriteAdmin))
+			MakeRequest(t, req, http.StatusForbidden)
+
+			req = NewRequestWithJSON(t, "POST", "/api/v1/admin/rulesets", option).AddTokenAuth(adminToken)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			DecodeJSON(t, resp, &ruleset)
+			assert.Equal(t, "no-force-push", ruleset.Name)
+			assert.Equal(t, "branch", ruleset.Target)
+			assert.Equal(t, "active", ruleset.Enforcement)
+			assert.Equal(t, []string{"master"}, ruleset.RefPatterns)
+			assert.True(t, ruleset.BlockForcePush)
+
+			req = NewRequest(t, "GET", "/api/v1/admin/rulesets").AddTokenAuth(adminToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			var rulesets []*api.Ruleset
+			DecodeJSON(t, resp, &rulesets)
+			if assert.Len(t, rulesets, 1) {
+				assert.Equal(t, ruleset.ID, rulesets[0].ID)
+			}
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/rulesets/%d", ruleset.ID)).AddTokenAuth(adminToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			var got api.Ruleset
+			DecodeJSON(t, resp, &got)
+			assert.Equal(t, ruleset.Name, got.Name)
+		})
+
+		editRuleset := func(t *testing.T, option *api.EditRulesetOption) {
+			req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/admin/rulesets/%d", ruleset.ID), option).AddTokenAuth(adminToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &ruleset)
+		}
+		getViolations := func(t *testing.T) []*api.RulesetViolation {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/rulesets/%d/violations", ruleset.ID)).AddTokenAuth(adminToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+			var violations []*api.RulesetViolation
+			DecodeJSON(t, resp, &violations)
+			return violations
+		}
+
+		t.Run("Push", func(t *testing.T) {
+			doCommit(t)
+			doGitPushTestRepository(dstPath, "origin", "master")(t)
+		})
+
+		t.Run("ForcePushActive", func(t *testing.T) {
+			commitID := getMasterCommitID(t)
+			rewriteLastCommit(t)
+			doGitPushTestRepositoryFail(dstPath, "-f", "origin", "master")(t)
+			assert.Equal(t, commitID, getMasterCommitID(t))
+			// violations of active rulesets are rejected, not recorded
+			assert.Empty(t, getViolations(t))
+		})
+
+		t.Run("ForcePushEvaluate", func(t *testing.T) {
+			editRuleset(t, &api.EditRulesetOption{Enforcement: util.ToPointer("evaluate")})
+			assert.Equal(t, "evaluate", ruleset.Enforcement)
+
+			doGitPushTestRepository(dstPath, "-f", "origin", "master")(t)
+
+			violations := getViolations(t)
+			if assert.Len(t, violations, 1) {
+				assert.Equal(t, "user2/ruleset-push", violations[0].Repository)
+				assert.Equal(t, "refs/heads/master", violations[0].Ref)
+				assert.Equal(t, "force push is blocked", violations[0].Reason)
+				if assert.NotNil(t, violations[0].Doer) {
+					assert.Equal(t, "user2", violations[0].Doer.UserName)
+				}
+			}
+		})
+
+		t.Run("ForcePushBypass", func(t *testing.T) {
+			editRuleset(t, &api.EditRulesetOption{
+				Enforcement: util.ToPointer("active"),
+				BypassUsers: []string{"user2"},
+			})
+			assert.Equal(t, []string{"user2"}, ruleset.BypassUsers)
+
+			rewriteLastCommit(t)
+			doGitPushTestRepository(dstPath, "-f", "origin", "master")(t)
+			// the rulesets a user is allowed to bypass are not evaluated at all
+			assert.Len(t, getViolations(t), 1)
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			req := NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/admin/rulesets/%d", ruleset.ID)).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusNoContent)
+
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/rulesets/%d", ruleset.ID)).AddTokenAuth(adminToken)
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+	})
+}
+
+func TestRulesetPullMerge(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		org3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+		repo, err := repo_service.CreateRepositoryDirectly(db.DefaultContext, user2, org3, repo_service.CreateRepoOptions{
+			Name:             "ruleset-pull",
+			Readme:           "Default",
+			AutoInit:         true,
+			ObjectFormatName: git.Sha1ObjectFormat.Name(),
+			DefaultBranch:    "master",
+		})
+		require.NoError(t, err)
+
+		_, err = files_service.ChangeRepoFiles(db.DefaultContext, repo, user2, &files_service.ChangeRepoFilesOptions{
+			OldBranch: repo.DefaultBranch,
+			NewBranch: "feature",
+			Files: []*files_service.ChangeRepoFile{
+				{
+					Operation:     "create",
+					TreePath:      "feature.txt",
+					ContentReader: strings.NewReader("feature\n"),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		orgToken := getUserToken(t, "user2", auth_model.AccessTokenScopeWriteOrganization)
+
+		var ruleset api.Ruleset
+		t.Run("OrgAPI", func(t *testing.T) {
+			option := &api.CreateRulesetOption{
+				Name:              "require-approval",
+				Target:            "branch",
+				RepoNamePatterns:  []string{"ruleset-pull"},
+				RefPatterns:       []string{"master"},
+				RequiredApprovals: 1,
+			}
+
+			// only the owners of the organization are allowed to manage its rulesets
+			req := NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/rulesets", option).
+				AddTokenAuth(getUserToken(t, "user4", auth_model.AccessTokenScopeWriteOrganization))
+			MakeRequest(t, req, http.StatusForbidden)
+
+			req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/org3/rulesets", option).AddTokenAuth(orgToken)
+			resp := MakeRequest(t, req, http.StatusCreated)
+			DecodeJSON(t, resp, &ruleset)
+			assert.Equal(t, "require-approval", ruleset.Name)
+			assert.Equal(t, "active", ruleset.Enforcement)
+			assert.EqualValues(t, 1, ruleset.RequiredApprovals)
+
+			req = NewRequest(t, "GET", "/api/v1/orgs/org3/rulesets").AddTokenAuth(orgToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			var rulesets []*api.Ruleset
+			DecodeJSON(t, resp, &rulesets)
+			if assert.Len(t, rulesets, 1) {
+				assert.Equal(t, ruleset.ID, rulesets[0].ID)
+			}
+
+			// the rulesets of the organization are not accessible through the instance level endpoints
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/admin/rulesets/%d", ruleset.ID)).
+				AddTokenAuth(getUserToken(t, "user1", auth_model.AccessTokenScopeReadAdmin))
+			MakeRequest(t, req, http.StatusNotFound)
+		})
+
+		ctx := NewAPITestContext(t, "user2", "ruleset-pull", auth_model.AccessTokenScopeWriteRepository)
+		apiPull, err := doAPICreatePullRequest(ctx, "org3", "ruleset-pull", "master", "feature")(t)
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: apiPull.ID})
+			return pr.Status == issues_model.PullRequestStatusMergeable
+		}, 10*time.Second, 100*time.Millisecond)
+
+		t.Run("MergeActive", func(t *testing.T) {
+			// the repository admin is not allowed to skip the rulesets of the organization
+			req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/org3/ruleset-pull/pulls/%d/merge", apiPull.Index), &forms.MergePullRequestForm{
+				Do: string(repo_model.MergeStyleMerge),
+			}).AddTokenAuth(ctx.Token)
+			resp := MakeRequest(t, req, http.StatusMethodNotAllowed)
+			var apiErr api.APIError
+			DecodeJSON(t, resp, &apiErr)
+			assert.Contains(t, apiErr.Message, `master is protected by ruleset "require-approval": Does not have enough approvals`)
+
+			pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: apiPull.ID})
+			assert.False(t, pr.HasMerged)
+		})
+
+		t.Run("MergeEvaluate", func(t *testing.T) {
+			req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/orgs/org3/rulesets/%d", ruleset.ID), &api.EditRulesetOption{
+				Enforcement: util.ToPointer("evaluate"),
+			}).AddTokenAuth(orgToken)
+			resp := MakeRequest(t, req, http.StatusOK)
+			DecodeJSON(t, resp, &ruleset)
+			assert.Equal(t, "evaluate", ruleset.Enforcement)
+
+			doAPIMergePullRequest(ctx, "org3", "ruleset-pull", apiPull.Index)(t)
+			pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: apiPull.ID})
+			assert.True(t, pr.HasMerged)
+
+			// the violation is recorded when the merge is pushed to the base branch
+			req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/orgs/org3/rulesets/%d/violations", ruleset.ID)).AddTokenAuth(orgToken)
+			resp = MakeRequest(t, req, http.StatusOK)
+			var violations []*api.RulesetViolation
+			DecodeJSON(t, resp, &violations)
+			if assert.Len(t, violations, 1) {
+				assert.Equal(t, "org3/ruleset-pull", violations[0].Repository)
+				assert.Equal(t, "refs/heads/master", violations[0].Ref)
+				assert.Equal(t, "Does not have enough approvals", violations[0].Reason)
+			}
+		})
+
+		t.Run("Delete", func(t *testing.T) {
+			req := NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/orgs/org3/rulesets/%d", ruleset.ID)).AddTokenAuth(orgToken)
+			MakeRequest(t, req, http.StatusNoContent)
+		})
+	})
+}
@@ -371,6 +371,17 @@ func TestUserSettingsPackagesRulesAdd(t *testing.T) {
 	assertNavbar(t, doc)
 }
 
+func TestUserSettingsPackagesUpstreamsAdd(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	req := NewRequest(t, "GET", "/user/settings/packages/upstreams/add")
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	doc := NewHTMLParser(t, resp.Body)
+
+	assertNavbar(t, doc)
+}
+
 func TestUserSettingsOrganization(t *testing.T) {
 	defer tests.PrepareTestEnv(t)()
 